@@ -1,34 +1,168 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"os"
+	"os/signal"
 	"rentease/config"
+	"rentease/internal/app/eventbus"
 	"rentease/internal/app/repositories"
 	"rentease/internal/app/services"
+	"rentease/internal/cli"
+	"rentease/internal/documents"
+	"rentease/internal/mail"
 	"rentease/internal/ui"
+	"syscall"
+	"time"
 )
 
+// emailFlushTimeout bounds how long the app waits on exit for the emails still queued to be sent.
+const emailFlushTimeout = 10 * time.Second
+
 func main() {
 
-	// Initializing user repo and user service
-	userRepo, _ := repositories.NewUserRepo(config.USER_URI, config.DATABASE, config.USER_COLLECTION)
-	userService := services.NewUserService(userRepo)
+	// Loading the configuration from file, environment and flags; a command may follow the flags
+	cfg, args, err := config.LoadCommand(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		fmt.Println("Error loading configuration:", err)
+		os.Exit(1)
+	}
+
+	// Loading the templates of receipts and lease agreements
+	renderer, err := documents.NewRenderer(cfg.Documents.TemplateDir)
+	if err != nil {
+		fmt.Println("Error loading document templates:", err)
+		os.Exit(1)
+	}
 
-	// Initializing property repo and property service
-	propertyRepo, err := repositories.NewPropertyRepo(config.PROPERTIES_URI, config.DATABASE, config.PROPERTIES_COLLECTION)
+	// Initializing the repositories for the selected storage backend
+	storage, err := repositories.OpenStorage(context.Background(), cfg)
 	if err != nil {
 		fmt.Println("Error initializing repository:", err)
-		return
+		os.Exit(1)
+	}
+	defer closeStorage(storage)
+
+	// Starting the queue emailing users about their requests and listings
+	emailNotifier, err := mail.Open(cfg.Email, storage.Users, storage.Properties)
+	if err != nil {
+		fmt.Println("Error loading email templates:", err)
+		closeStorage(storage)
+		os.Exit(1)
 	}
-	propertyService := services.NewPropertyService(propertyRepo)
+	defer closeEmail(emailNotifier)
+
+	// Delivering the events of the services to the notifications, emails and audit log
+	bus := eventbus.New()
+	services.SubscribeNotifications(bus, storage.Notifications)
+	services.SubscribeEmails(bus, emailNotifier)
+	var auditLog *eventbus.AuditLog
+	if cfg.Audit.File != "" {
+		if auditLog, err = eventbus.OpenAuditLog(cfg.Audit.File); err != nil {
+			fmt.Println("Error opening audit log:", err)
+			closeEmail(emailNotifier)
+			closeStorage(storage)
+			os.Exit(1)
+		}
+		defer closeAudit(auditLog)
+		bus.SubscribeAll(auditLog.Record)
+	}
+
+	// Root context for all service calls, cancelled when the app shuts down
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Releasing the storage when the app is interrupted, since the menus block on input
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		<-signals
+		fmt.Println("\nShutting down...")
+		cancel()
+		closeEmail(emailNotifier)
+		closeAudit(auditLog)
+		closeStorage(storage)
+		os.Exit(130)
+	}()
+
+	// Initializing user service
+	userService := services.NewUserService(storage.Users, storage.Properties, storage.RefreshTokens, bus)
+
+	// Initializing property service
+	propertyService := services.NewPropertyService(storage.Properties, bus)
+
+	// Initializing rent request service
+	rentRequestService := services.NewRequestService(storage.RentRequests, storage.Properties, storage.Leases, bus)
+
+	// Initializing lease service
+	leaseService := services.NewLeaseService(storage.Leases, storage.Properties, cfg.Leases.RelistNeedsApproval)
+
+	// Initializing ledger service
+	ledgerService := services.NewLedgerService(storage.Ledger, storage.Leases)
 
-	// Initializing rent request repo and rent request service
-	rentRequestRepo, err := repositories.NewRequestRepo(config.RENT_REQUEST_URI, config.DATABASE, config.RENT_REQUEST_COLLECTION)
-	rentRequestService := services.NewRequestService(rentRequestRepo)
+	// Initializing deposit service
+	depositService := services.NewDepositService(storage.Deposits, storage.Leases)
 
-	appUI := ui.NewUI(userService, propertyService, rentRequestService)
+	// Initializing maintenance service
+	maintenanceService := services.NewMaintenanceService(storage.Maintenance, storage.Leases)
+
+	// Initializing visit service
+	visitService := services.NewVisitService(storage.Visits, storage.Properties)
+
+	// Initializing message service
+	messageService := services.NewMessageService(storage.Messages, storage.Properties, storage.RentRequests)
+
+	// Initializing notification service
+	notificationService := services.NewNotificationService(storage.Notifications)
+
+	// Initializing document service
+	documentService := services.NewDocumentService(storage.Leases, storage.Ledger, storage.Properties, storage.Users, renderer)
+
+	// Running a single command when one is given, e.g. `rentease property list -json`
+	if len(args) > 0 {
+		code := cli.New(ctx, userService, propertyService, rentRequestService, leaseService, ledgerService, depositService, maintenanceService, visitService, messageService, notificationService, documentService, os.Stdout, os.Stderr).Run(args)
+		cancel()
+		closeEmail(emailNotifier)
+		closeAudit(auditLog)
+		closeStorage(storage)
+		os.Exit(code)
+	}
+
+	appUI := ui.NewUI(ctx, userService, propertyService, rentRequestService, leaseService, ledgerService, depositService, maintenanceService, visitService, messageService, notificationService, documentService)
 
 	// Calling the AppDashboard
 	appUI.AppDashboard()
 
 }
+
+// closeStorage releases the storage, reporting any error.
+func closeStorage(storage *repositories.Storage) {
+	if err := storage.Close(); err != nil {
+		fmt.Println("Error closing storage:", err)
+	}
+}
+
+// closeEmail waits a while for the queued emails to be sent, reporting the ones given up.
+func closeEmail(emailNotifier *mail.Notifier) {
+	ctx, cancel := context.WithTimeout(context.Background(), emailFlushTimeout)
+	defer cancel()
+	if err := emailNotifier.Close(ctx); err != nil {
+		fmt.Println("Error sending the remaining emails:", err)
+	}
+}
+
+// closeAudit closes the audit log, if one is kept, reporting any error.
+func closeAudit(auditLog *eventbus.AuditLog) {
+	if auditLog == nil {
+		return
+	}
+	if err := auditLog.Close(); err != nil {
+		fmt.Println("Error closing audit log:", err)
+	}
+}
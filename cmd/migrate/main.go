@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"rentease/config"
+	"rentease/internal/app/repositories"
+)
+
+// migrate copies all data from the configured MongoDB database into the configured
+// embedded database file, so an existing installation can switch to `-storage=bolt`.
+// It accepts the same configuration file, environment variables and flags as the app.
+func main() {
+
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		fmt.Println("Error loading configuration:", err)
+		os.Exit(1)
+	}
+
+	db, err := repositories.OpenBoltDB(cfg.Bolt.Path)
+	if err != nil {
+		fmt.Println("Error opening database file:", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	client, err := repositories.NewMongoClient(context.Background(), cfg.Mongo)
+	if err != nil {
+		fmt.Println("Error connecting to MongoDB:", err)
+		db.Close()
+		os.Exit(1)
+	}
+	defer repositories.DisconnectMongoClient(client, cfg.Mongo.OperationTimeout)
+
+	source := repositories.MongoSource{
+		Database:               cfg.Mongo.Database,
+		UserCollection:         cfg.Mongo.Collections.Users,
+		PropertyCollection:     cfg.Mongo.Collections.Properties,
+		RentRequestCollection:  cfg.Mongo.Collections.RentRequests,
+		LeaseCollection:        cfg.Mongo.Collections.Leases,
+		RentDueCollection:      cfg.Mongo.Collections.RentDues,
+		PaymentCollection:      cfg.Mongo.Collections.Payments,
+		DepositCollection:      cfg.Mongo.Collections.Deposits,
+		MaintenanceCollection:  cfg.Mongo.Collections.Maintenance,
+		VisitCollection:        cfg.Mongo.Collections.Visits,
+		MessageCollection:      cfg.Mongo.Collections.Messages,
+		NotificationCollection: cfg.Mongo.Collections.Notifications,
+	}
+
+	result, err := repositories.MigrateMongoToBolt(context.Background(), client, source, db)
+	if err != nil {
+		fmt.Println("Error migrating data:", err)
+		_ = repositories.DisconnectMongoClient(client, cfg.Mongo.OperationTimeout)
+		db.Close()
+		os.Exit(1)
+	}
+
+	fmt.Printf("Migrated %d users, %d properties, %d rent requests, %d leases, %d rent dues, %d payments, %d deposits, %d maintenance tickets, %d visit slots, %d message threads and %d notifications into %s\n",
+		result.Users, result.Properties, result.RentRequests, result.Leases, result.RentDues, result.Payments, result.Deposits, result.Tickets, result.VisitSlots, result.Threads, result.Notifications, cfg.Bolt.Path)
+}
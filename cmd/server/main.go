@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"rentease/config"
+	"rentease/internal/api"
+	"rentease/internal/app/eventbus"
+	"rentease/internal/app/repositories"
+	"rentease/internal/app/services"
+	"rentease/internal/documents"
+	"rentease/internal/mail"
+	"syscall"
+)
+
+// server runs the REST/JSON API on the configured storage backend.
+// It accepts the same configuration file, environment variables and flags as the app.
+func main() {
+	if code := run(os.Args[1:]); code != 0 {
+		os.Exit(code)
+	}
+}
+
+// run serves the API until it is interrupted and returns the exit code. Everything it opens is closed by
+// its deferred calls, which os.Exit would skip, so it returns instead of exiting.
+func run(args []string) int {
+
+	cfg, err := config.Load(args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		fmt.Println("Error loading configuration:", err)
+		return 1
+	}
+
+	renderer, err := documents.NewRenderer(cfg.Documents.TemplateDir)
+	if err != nil {
+		fmt.Println("Error loading document templates:", err)
+		return 1
+	}
+
+	// Cancelled on SIGINT or SIGTERM to start a graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	storage, err := repositories.OpenStorage(ctx, cfg)
+	if err != nil {
+		fmt.Println("Error initializing repository:", err)
+		return 1
+	}
+	defer func() {
+		if err := storage.Close(); err != nil {
+			log.Println("Error closing storage:", err)
+		}
+	}()
+
+	emailNotifier, err := mail.Open(cfg.Email, storage.Users, storage.Properties)
+	if err != nil {
+		fmt.Println("Error loading email templates:", err)
+		return 1
+	}
+	// Emails still queued are sent, for as long as the API is given to shut down
+	defer func() {
+		emailCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
+		defer cancel()
+		if err := emailNotifier.Close(emailCtx); err != nil {
+			log.Println("Error sending the remaining emails:", err)
+		}
+	}()
+
+	bus := eventbus.New()
+	services.SubscribeNotifications(bus, storage.Notifications)
+	services.SubscribeEmails(bus, emailNotifier)
+	if cfg.Audit.File != "" {
+		auditLog, err := eventbus.OpenAuditLog(cfg.Audit.File)
+		if err != nil {
+			fmt.Println("Error opening audit log:", err)
+			return 1
+		}
+		defer auditLog.Close()
+		bus.SubscribeAll(auditLog.Record)
+	}
+
+	secret := []byte(cfg.Auth.TokenSecret)
+	if len(secret) == 0 {
+		// Tokens signed with a generated secret stop working when the server restarts
+		log.Println("Warning: no auth.token_secret configured, generating a temporary one")
+		if secret, err = services.GenerateTokenSecret(); err != nil {
+			fmt.Println("Error generating token secret:", err)
+			return 1
+		}
+	}
+
+	handler := api.NewServer(
+		services.NewUserService(storage.Users, storage.Properties, storage.RefreshTokens, bus),
+		services.NewPropertyService(storage.Properties, bus),
+		services.NewRequestService(storage.RentRequests, storage.Properties, storage.Leases, bus),
+		services.NewLeaseService(storage.Leases, storage.Properties, cfg.Leases.RelistNeedsApproval),
+		services.NewLedgerService(storage.Ledger, storage.Leases),
+		services.NewDepositService(storage.Deposits, storage.Leases),
+		services.NewMaintenanceService(storage.Maintenance, storage.Leases),
+		services.NewVisitService(storage.Visits, storage.Properties),
+		services.NewMessageService(storage.Messages, storage.Properties, storage.RentRequests),
+		services.NewNotificationService(storage.Notifications),
+		services.NewDocumentService(storage.Leases, storage.Ledger, storage.Properties, storage.Users, renderer),
+		services.NewTokenService(storage.Users, storage.RefreshTokens, secret, cfg.Auth.AccessTokenTTL, cfg.Auth.RefreshTokenTTL),
+	)
+	server := &http.Server{Addr: cfg.HTTP.Addr, Handler: handler}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Println("API listening on", cfg.HTTP.Addr)
+		serveErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		log.Println("Error serving API:", err)
+		return 1
+	case <-ctx.Done():
+	}
+
+	log.Println("Shutting down...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Println("Error shutting down API:", err)
+	}
+	return 0
+}
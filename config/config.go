@@ -1,11 +1,506 @@
 package config
 
-const USER_URI = "mongodb://localhost:27017/users"
-const DATABASE = "RentEase"
-const USER_COLLECTION = "users"
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/mail"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
-const PROPERTIES_URI = "mongodb://localhost:27017/properties"
-const PROPERTIES_COLLECTION = "properties"
+	"gopkg.in/yaml.v3"
+)
 
-const RENT_REQUEST_URI = "mongodb://localhost:27017/rentRequest"
-const RENT_REQUEST_COLLECTION = "rentRequest"
+// Storage backends that can be selected with Config.Storage.
+const (
+	StorageMongo  = "mongo"
+	StorageBolt   = "bolt"
+	StorageMemory = "memory"
+)
+
+// Email senders that can be selected with EmailConfig.Sender.
+const (
+	EmailSMTP   = "smtp"
+	EmailFile   = "file"
+	EmailMemory = "memory"
+)
+
+// Config holds all settings needed to start RentEase.
+type Config struct {
+	// Storage selects the repository implementation: mongo, bolt or memory.
+	Storage   string         `yaml:"storage"`
+	Mongo     MongoConfig    `yaml:"mongo"`
+	Bolt      BoltConfig     `yaml:"bolt"`
+	HTTP      HTTPConfig     `yaml:"http"`
+	Auth      AuthConfig     `yaml:"auth"`
+	Leases    LeaseConfig    `yaml:"leases"`
+	Documents DocumentConfig `yaml:"documents"`
+	Email     EmailConfig    `yaml:"email"`
+	Audit     AuditConfig    `yaml:"audit"`
+}
+
+// MongoConfig describes where the MongoDB storage backend keeps its data
+// and how the shared client connects to it.
+type MongoConfig struct {
+	URI         string           `yaml:"uri"`
+	Database    string           `yaml:"database"`
+	Collections MongoCollections `yaml:"collections"`
+
+	// MaxPoolSize and MinPoolSize bound the number of pooled connections per server.
+	MaxPoolSize uint64 `yaml:"max_pool_size"`
+	MinPoolSize uint64 `yaml:"min_pool_size"`
+	// ConnectTimeout bounds each startup connection attempt.
+	ConnectTimeout time.Duration `yaml:"connect_timeout"`
+	// ConnectRetries is how many more times the startup health check is tried before giving up.
+	ConnectRetries int `yaml:"connect_retries"`
+	// OperationTimeout bounds every database operation that has no earlier deadline.
+	OperationTimeout time.Duration `yaml:"operation_timeout"`
+}
+
+// MongoCollections names the collection used for each kind of document.
+type MongoCollections struct {
+	Users         string `yaml:"users"`
+	Properties    string `yaml:"properties"`
+	RentRequests  string `yaml:"rent_requests"`
+	RefreshTokens string `yaml:"refresh_tokens"`
+	Leases        string `yaml:"leases"`
+	RentDues      string `yaml:"rent_dues"`
+	Payments      string `yaml:"payments"`
+	Deposits      string `yaml:"deposits"`
+	Maintenance   string `yaml:"maintenance"`
+	Visits        string `yaml:"visits"`
+	Messages      string `yaml:"messages"`
+	Notifications string `yaml:"notifications"`
+}
+
+// named lists the collections by their configuration key.
+func (c MongoCollections) named() []struct{ key, name string } {
+	return []struct{ key, name string }{
+		{"users", c.Users},
+		{"properties", c.Properties},
+		{"rent_requests", c.RentRequests},
+		{"refresh_tokens", c.RefreshTokens},
+		{"leases", c.Leases},
+		{"rent_dues", c.RentDues},
+		{"payments", c.Payments},
+		{"deposits", c.Deposits},
+		{"maintenance", c.Maintenance},
+		{"visits", c.Visits},
+		{"messages", c.Messages},
+		{"notifications", c.Notifications},
+	}
+}
+
+// BoltConfig describes where the embedded storage backend keeps its data.
+type BoltConfig struct {
+	Path string `yaml:"path"`
+}
+
+// HTTPConfig describes how the API server listens for requests.
+type HTTPConfig struct {
+	Addr string `yaml:"addr"`
+	// ShutdownTimeout bounds how long in-flight requests may run after a shutdown signal.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+}
+
+// AuthConfig describes the tokens issued to API clients.
+type AuthConfig struct {
+	// TokenSecret signs access tokens. When empty the API server generates one at startup,
+	// so tokens do not survive a restart.
+	TokenSecret string `yaml:"token_secret"`
+	// AccessTokenTTL is how long an access token is accepted.
+	AccessTokenTTL time.Duration `yaml:"access_token_ttl"`
+	// RefreshTokenTTL is how long a login can be kept alive with refresh tokens without using it.
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl"`
+}
+
+// LeaseConfig describes what happens to a property when its lease ends.
+type LeaseConfig struct {
+	// RelistNeedsApproval sends the property back to the moderators for approval before it is shown again.
+	RelistNeedsApproval bool `yaml:"relist_needs_approval"`
+}
+
+// DocumentConfig describes how rent receipts and lease agreements are drawn up.
+type DocumentConfig struct {
+	// TemplateDir holds receipt.tmpl and lease.tmpl replacing the built-in templates.
+	// When empty, or for a template missing from it, the built-in template is used.
+	TemplateDir string `yaml:"template_dir"`
+}
+
+// EmailConfig describes how users are emailed about their rent requests and listings.
+type EmailConfig struct {
+	// Sender selects where emails go: smtp, file or memory. memory keeps them only until exit,
+	// so nothing leaves the machine until a mail server is configured.
+	Sender string `yaml:"sender"`
+	// From is the address the emails are sent from, e.g. "RentEase <no-reply@example.com>".
+	From string     `yaml:"from"`
+	SMTP SMTPConfig `yaml:"smtp"`
+	// File collects the emails, one after the other, when Sender is file.
+	File string `yaml:"file"`
+	// TemplateDir holds templates replacing the built-in ones, named like them, e.g. welcome.tmpl.
+	// When empty, or for a template missing from it, the built-in template is used.
+	TemplateDir string `yaml:"template_dir"`
+
+	// QueueSize is how many emails may wait to be sent before new ones are turned away.
+	QueueSize int `yaml:"queue_size"`
+	// MaxAttempts is how many times an email is tried before it is given up.
+	MaxAttempts int `yaml:"max_attempts"`
+	// RetryDelay is the wait before the second attempt; it doubles for every attempt after that.
+	RetryDelay time.Duration `yaml:"retry_delay"`
+}
+
+// SMTPConfig describes the mail server used when EmailConfig.Sender is smtp.
+type SMTPConfig struct {
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+	// Username and Password log in to the server; without a username no login is attempted.
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	// Timeout bounds sending one email, from connecting to the server to its goodbye.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// AuditConfig describes where the events published by the services are recorded.
+type AuditConfig struct {
+	// File collects every event as a line of JSON. When empty, events are not recorded.
+	File string `yaml:"file"`
+}
+
+// MinTokenSecretLength is the minimum length of a configured token secret.
+const MinTokenSecretLength = 32
+
+// Default returns the configuration used when nothing is overridden.
+func Default() Config {
+	return Config{
+		Storage: StorageMongo,
+		Mongo: MongoConfig{
+			URI:      "mongodb://localhost:27017",
+			Database: "RentEase",
+			Collections: MongoCollections{
+				Users:         "users",
+				Properties:    "properties",
+				RentRequests:  "rentRequest",
+				RefreshTokens: "refreshTokens",
+				Leases:        "leases",
+				RentDues:      "rentDues",
+				Payments:      "payments",
+				Deposits:      "deposits",
+				Maintenance:   "maintenance",
+				Visits:        "visits",
+				Messages:      "messages",
+				Notifications: "notifications",
+			},
+			MaxPoolSize:      100,
+			MinPoolSize:      0,
+			ConnectTimeout:   5 * time.Second,
+			ConnectRetries:   2,
+			OperationTimeout: 10 * time.Second,
+		},
+		Bolt: BoltConfig{
+			Path: "rentease.db",
+		},
+		HTTP: HTTPConfig{
+			Addr:            ":8080",
+			ShutdownTimeout: 10 * time.Second,
+		},
+		Auth: AuthConfig{
+			AccessTokenTTL:  15 * time.Minute,
+			RefreshTokenTTL: 30 * 24 * time.Hour,
+		},
+		Leases: LeaseConfig{
+			RelistNeedsApproval: true,
+		},
+		Email: EmailConfig{
+			Sender:      EmailMemory,
+			From:        "RentEase <no-reply@rentease.local>",
+			SMTP:        SMTPConfig{Port: 587, Timeout: 30 * time.Second},
+			File:        "rentease-mail.txt",
+			QueueSize:   100,
+			MaxAttempts: 5,
+			RetryDelay:  30 * time.Second,
+		},
+	}
+}
+
+// envPrefix is the prefix of every environment variable read by Load.
+const envPrefix = "RENTEASE_"
+
+// setting binds one configuration field to its environment variable and command-line flag.
+type setting struct {
+	env   string
+	flag  string
+	usage string
+	set   func(cfg *Config, value string) error
+}
+
+var settings = []setting{
+	{"STORAGE", "storage", "storage backend to use: mongo, bolt or memory", stringSetting(func(cfg *Config) *string { return &cfg.Storage })},
+	{"MONGO_URI", "mongo-uri", "URI of the MongoDB server", stringSetting(func(cfg *Config) *string { return &cfg.Mongo.URI })},
+	{"MONGO_DATABASE", "mongo-database", "MongoDB database name", stringSetting(func(cfg *Config) *string { return &cfg.Mongo.Database })},
+	{"MONGO_USERS_COLLECTION", "mongo-users-collection", "MongoDB collection holding users", stringSetting(func(cfg *Config) *string { return &cfg.Mongo.Collections.Users })},
+	{"MONGO_PROPERTIES_COLLECTION", "mongo-properties-collection", "MongoDB collection holding properties", stringSetting(func(cfg *Config) *string { return &cfg.Mongo.Collections.Properties })},
+	{"MONGO_RENT_REQUESTS_COLLECTION", "mongo-rent-requests-collection", "MongoDB collection holding rent requests", stringSetting(func(cfg *Config) *string { return &cfg.Mongo.Collections.RentRequests })},
+	{"MONGO_REFRESH_TOKENS_COLLECTION", "mongo-refresh-tokens-collection", "MongoDB collection holding refresh tokens", stringSetting(func(cfg *Config) *string { return &cfg.Mongo.Collections.RefreshTokens })},
+	{"MONGO_LEASES_COLLECTION", "mongo-leases-collection", "MongoDB collection holding leases", stringSetting(func(cfg *Config) *string { return &cfg.Mongo.Collections.Leases })},
+	{"MONGO_RENT_DUES_COLLECTION", "mongo-rent-dues-collection", "MongoDB collection holding rent dues", stringSetting(func(cfg *Config) *string { return &cfg.Mongo.Collections.RentDues })},
+	{"MONGO_PAYMENTS_COLLECTION", "mongo-payments-collection", "MongoDB collection holding rent payments", stringSetting(func(cfg *Config) *string { return &cfg.Mongo.Collections.Payments })},
+	{"MONGO_DEPOSITS_COLLECTION", "mongo-deposits-collection", "MongoDB collection holding security deposits", stringSetting(func(cfg *Config) *string { return &cfg.Mongo.Collections.Deposits })},
+	{"MONGO_MAINTENANCE_COLLECTION", "mongo-maintenance-collection", "MongoDB collection holding maintenance tickets", stringSetting(func(cfg *Config) *string { return &cfg.Mongo.Collections.Maintenance })},
+	{"MONGO_VISITS_COLLECTION", "mongo-visits-collection", "MongoDB collection holding visit slots", stringSetting(func(cfg *Config) *string { return &cfg.Mongo.Collections.Visits })},
+	{"MONGO_MESSAGES_COLLECTION", "mongo-messages-collection", "MongoDB collection holding message threads", stringSetting(func(cfg *Config) *string { return &cfg.Mongo.Collections.Messages })},
+	{"MONGO_NOTIFICATIONS_COLLECTION", "mongo-notifications-collection", "MongoDB collection holding the notifications of users", stringSetting(func(cfg *Config) *string { return &cfg.Mongo.Collections.Notifications })},
+	{"MONGO_MAX_POOL_SIZE", "mongo-max-pool-size", "maximum number of pooled MongoDB connections", uintSetting(func(cfg *Config) *uint64 { return &cfg.Mongo.MaxPoolSize })},
+	{"MONGO_MIN_POOL_SIZE", "mongo-min-pool-size", "minimum number of pooled MongoDB connections", uintSetting(func(cfg *Config) *uint64 { return &cfg.Mongo.MinPoolSize })},
+	{"MONGO_CONNECT_TIMEOUT", "mongo-connect-timeout", "timeout of each MongoDB connection attempt, e.g. 5s", durationSetting(func(cfg *Config) *time.Duration { return &cfg.Mongo.ConnectTimeout })},
+	{"MONGO_CONNECT_RETRIES", "mongo-connect-retries", "extra attempts of the MongoDB startup health check", intSetting(func(cfg *Config) *int { return &cfg.Mongo.ConnectRetries })},
+	{"MONGO_OPERATION_TIMEOUT", "mongo-operation-timeout", "timeout of each MongoDB operation, e.g. 10s", durationSetting(func(cfg *Config) *time.Duration { return &cfg.Mongo.OperationTimeout })},
+	{"DB_PATH", "db-path", "database file used by the bolt storage backend", stringSetting(func(cfg *Config) *string { return &cfg.Bolt.Path })},
+	{"HTTP_ADDR", "http-addr", "address the API server listens on", stringSetting(func(cfg *Config) *string { return &cfg.HTTP.Addr })},
+	{"HTTP_SHUTDOWN_TIMEOUT", "http-shutdown-timeout", "time allowed for in-flight API requests on shutdown, e.g. 10s", durationSetting(func(cfg *Config) *time.Duration { return &cfg.HTTP.ShutdownTimeout })},
+	{"AUTH_TOKEN_SECRET", "auth-token-secret", "secret signing API access tokens, at least 32 characters", stringSetting(func(cfg *Config) *string { return &cfg.Auth.TokenSecret })},
+	{"AUTH_ACCESS_TOKEN_TTL", "auth-access-token-ttl", "lifetime of API access tokens, e.g. 15m", durationSetting(func(cfg *Config) *time.Duration { return &cfg.Auth.AccessTokenTTL })},
+	{"AUTH_REFRESH_TOKEN_TTL", "auth-refresh-token-ttl", "lifetime of API refresh tokens, e.g. 720h", durationSetting(func(cfg *Config) *time.Duration { return &cfg.Auth.RefreshTokenTTL })},
+	{"LEASES_RELIST_NEEDS_APPROVAL", "leases-relist-needs-approval", "whether a property needs approval again when its lease ends", boolSetting(func(cfg *Config) *bool { return &cfg.Leases.RelistNeedsApproval })},
+	{"DOCUMENTS_TEMPLATE_DIR", "documents-template-dir", "directory of templates replacing the built-in receipt and lease documents", stringSetting(func(cfg *Config) *string { return &cfg.Documents.TemplateDir })},
+	{"EMAIL_SENDER", "email-sender", "where emails go: smtp, file or memory", stringSetting(func(cfg *Config) *string { return &cfg.Email.Sender })},
+	{"EMAIL_FROM", "email-from", "address emails are sent from", stringSetting(func(cfg *Config) *string { return &cfg.Email.From })},
+	{"EMAIL_SMTP_HOST", "email-smtp-host", "host name of the SMTP server", stringSetting(func(cfg *Config) *string { return &cfg.Email.SMTP.Host })},
+	{"EMAIL_SMTP_PORT", "email-smtp-port", "port of the SMTP server", intSetting(func(cfg *Config) *int { return &cfg.Email.SMTP.Port })},
+	{"EMAIL_SMTP_USERNAME", "email-smtp-username", "username logging in to the SMTP server", stringSetting(func(cfg *Config) *string { return &cfg.Email.SMTP.Username })},
+	{"EMAIL_SMTP_PASSWORD", "email-smtp-password", "password logging in to the SMTP server", stringSetting(func(cfg *Config) *string { return &cfg.Email.SMTP.Password })},
+	{"EMAIL_SMTP_TIMEOUT", "email-smtp-timeout", "time allowed for sending one email over SMTP, e.g. 30s", durationSetting(func(cfg *Config) *time.Duration { return &cfg.Email.SMTP.Timeout })},
+	{"EMAIL_FILE", "email-file", "file collecting the emails of the file sender", stringSetting(func(cfg *Config) *string { return &cfg.Email.File })},
+	{"EMAIL_TEMPLATE_DIR", "email-template-dir", "directory of templates replacing the built-in emails", stringSetting(func(cfg *Config) *string { return &cfg.Email.TemplateDir })},
+	{"EMAIL_QUEUE_SIZE", "email-queue-size", "number of emails that may wait to be sent", intSetting(func(cfg *Config) *int { return &cfg.Email.QueueSize })},
+	{"EMAIL_MAX_ATTEMPTS", "email-max-attempts", "number of times an email is tried before it is given up", intSetting(func(cfg *Config) *int { return &cfg.Email.MaxAttempts })},
+	{"EMAIL_RETRY_DELAY", "email-retry-delay", "wait before an email is tried again, doubling each time, e.g. 30s", durationSetting(func(cfg *Config) *time.Duration { return &cfg.Email.RetryDelay })},
+	{"AUDIT_FILE", "audit-file", "file recording every event as a line of JSON, none when empty", stringSetting(func(cfg *Config) *string { return &cfg.Audit.File })},
+}
+
+func stringSetting(field func(cfg *Config) *string) func(*Config, string) error {
+	return func(cfg *Config, value string) error {
+		*field(cfg) = value
+		return nil
+	}
+}
+
+func intSetting(field func(cfg *Config) *int) func(*Config, string) error {
+	return func(cfg *Config, value string) error {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a whole number", value)
+		}
+		*field(cfg) = parsed
+		return nil
+	}
+}
+
+func uintSetting(field func(cfg *Config) *uint64) func(*Config, string) error {
+	return func(cfg *Config, value string) error {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a non-negative whole number", value)
+		}
+		*field(cfg) = parsed
+		return nil
+	}
+}
+
+func boolSetting(field func(cfg *Config) *bool) func(*Config, string) error {
+	return func(cfg *Config, value string) error {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%q is not true or false", value)
+		}
+		*field(cfg) = parsed
+		return nil
+	}
+}
+
+func durationSetting(field func(cfg *Config) *time.Duration) func(*Config, string) error {
+	return func(cfg *Config, value string) error {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a duration such as 5s or 1m", value)
+		}
+		*field(cfg) = parsed
+		return nil
+	}
+}
+
+// Load builds the configuration from, in increasing order of precedence:
+// the defaults, a YAML file, RENTEASE_* environment variables and the command-line flags in args.
+// The file is taken from the -config flag or RENTEASE_CONFIG; without either no file is read.
+// The result is validated before it is returned.
+func Load(args []string) (Config, error) {
+	cfg, _, err := LoadCommand(args)
+	return cfg, err
+}
+
+// LoadCommand is Load for programs that take a command after the flags.
+// It also returns the arguments that follow the flags.
+func LoadCommand(args []string) (Config, []string, error) {
+	cfg := Default()
+
+	fs := flag.NewFlagSet("rentease", flag.ContinueOnError)
+	configPath := fs.String("config", os.Getenv(envPrefix+"CONFIG"), "path of a YAML configuration file")
+	flagValues := make(map[string]*string, len(settings))
+	for _, s := range settings {
+		flagValues[s.flag] = fs.String(s.flag, "", fmt.Sprintf("%s (env %s%s)", s.usage, envPrefix, s.env))
+	}
+	if err := fs.Parse(args); err != nil {
+		return Config{}, nil, err
+	}
+
+	if *configPath != "" {
+		if err := loadFile(*configPath, &cfg); err != nil {
+			return Config{}, nil, err
+		}
+	}
+
+	for _, s := range settings {
+		if value, ok := os.LookupEnv(envPrefix + s.env); ok {
+			if err := s.set(&cfg, value); err != nil {
+				return Config{}, nil, fmt.Errorf("invalid %s%s: %w", envPrefix, s.env, err)
+			}
+		}
+	}
+
+	var flagErr error
+	fs.Visit(func(f *flag.Flag) {
+		for _, s := range settings {
+			if s.flag == f.Name && flagErr == nil {
+				if err := s.set(&cfg, *flagValues[f.Name]); err != nil {
+					flagErr = fmt.Errorf("invalid -%s: %w", s.flag, err)
+				}
+			}
+		}
+	})
+	if flagErr != nil {
+		return Config{}, nil, flagErr
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, nil, err
+	}
+	return cfg, fs.Args(), nil
+}
+
+// loadFile overlays the settings found in the YAML file onto cfg. Unknown keys are rejected.
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Validate checks that the configuration can be used to start the selected storage backend.
+// All problems are reported together.
+func (cfg Config) Validate() error {
+	var problems []string
+
+	switch cfg.Storage {
+	case StorageMongo:
+		if !strings.HasPrefix(cfg.Mongo.URI, "mongodb://") && !strings.HasPrefix(cfg.Mongo.URI, "mongodb+srv://") {
+			problems = append(problems, fmt.Sprintf("mongo.uri %q must start with mongodb:// or mongodb+srv://", cfg.Mongo.URI))
+		}
+		if strings.TrimSpace(cfg.Mongo.Database) == "" {
+			problems = append(problems, "mongo.database must not be empty")
+		}
+		usedBy := make(map[string]string)
+		for _, c := range cfg.Mongo.Collections.named() {
+			if strings.TrimSpace(c.name) == "" {
+				problems = append(problems, fmt.Sprintf("mongo.collections.%s must not be empty", c.key))
+			} else if other, ok := usedBy[c.name]; ok {
+				problems = append(problems, fmt.Sprintf("mongo.collections.%s and %s must be different", other, c.key))
+			} else {
+				usedBy[c.name] = c.key
+			}
+		}
+		if cfg.Mongo.MaxPoolSize == 0 {
+			problems = append(problems, "mongo.max_pool_size must be greater than 0")
+		} else if cfg.Mongo.MinPoolSize > cfg.Mongo.MaxPoolSize {
+			problems = append(problems, "mongo.min_pool_size must not exceed mongo.max_pool_size")
+		}
+		if cfg.Mongo.ConnectTimeout <= 0 {
+			problems = append(problems, "mongo.connect_timeout must be positive")
+		}
+		if cfg.Mongo.ConnectRetries < 0 {
+			problems = append(problems, "mongo.connect_retries must not be negative")
+		}
+		if cfg.Mongo.OperationTimeout <= 0 {
+			problems = append(problems, "mongo.operation_timeout must be positive")
+		}
+	case StorageBolt:
+		if strings.TrimSpace(cfg.Bolt.Path) == "" {
+			problems = append(problems, "bolt.path must not be empty")
+		}
+	case StorageMemory:
+	default:
+		problems = append(problems, fmt.Sprintf("storage %q must be one of mongo, bolt or memory", cfg.Storage))
+	}
+
+	if strings.TrimSpace(cfg.HTTP.Addr) == "" {
+		problems = append(problems, "http.addr must not be empty")
+	}
+	if cfg.HTTP.ShutdownTimeout <= 0 {
+		problems = append(problems, "http.shutdown_timeout must be positive")
+	}
+	if cfg.Auth.TokenSecret != "" && len(cfg.Auth.TokenSecret) < MinTokenSecretLength {
+		problems = append(problems, fmt.Sprintf("auth.token_secret must be at least %d characters", MinTokenSecretLength))
+	}
+	if cfg.Auth.AccessTokenTTL <= 0 {
+		problems = append(problems, "auth.access_token_ttl must be positive")
+	}
+	if cfg.Auth.RefreshTokenTTL <= 0 {
+		problems = append(problems, "auth.refresh_token_ttl must be positive")
+	}
+
+	switch cfg.Email.Sender {
+	case EmailSMTP:
+		if strings.TrimSpace(cfg.Email.SMTP.Host) == "" {
+			problems = append(problems, "email.smtp.host must not be empty")
+		}
+		if cfg.Email.SMTP.Port < 1 || cfg.Email.SMTP.Port > 65535 {
+			problems = append(problems, "email.smtp.port must be between 1 and 65535")
+		}
+		if cfg.Email.SMTP.Timeout <= 0 {
+			problems = append(problems, "email.smtp.timeout must be positive")
+		}
+	case EmailFile:
+		if strings.TrimSpace(cfg.Email.File) == "" {
+			problems = append(problems, "email.file must not be empty")
+		}
+	case EmailMemory:
+	default:
+		problems = append(problems, fmt.Sprintf("email.sender %q must be one of smtp, file or memory", cfg.Email.Sender))
+	}
+	if _, err := mail.ParseAddress(cfg.Email.From); err != nil {
+		problems = append(problems, fmt.Sprintf("email.from %q must be an email address", cfg.Email.From))
+	}
+	if cfg.Email.QueueSize <= 0 {
+		problems = append(problems, "email.queue_size must be greater than 0")
+	}
+	if cfg.Email.MaxAttempts <= 0 {
+		problems = append(problems, "email.max_attempts must be greater than 0")
+	}
+	if cfg.Email.RetryDelay <= 0 {
+		problems = append(problems, "email.retry_delay must be positive")
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
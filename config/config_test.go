@@ -0,0 +1,215 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "rentease.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	t.Setenv("RENTEASE_CONFIG", "")
+
+	cfg, err := Load(nil)
+	require.NoError(t, err)
+	assert.Equal(t, Default(), cfg)
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := writeConfigFile(t, `
+storage: bolt
+mongo:
+  uri: mongodb://file-host:27017
+  database: FromFile
+bolt:
+  path: file.db
+`)
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		args     []string
+		expected func(cfg *Config)
+	}{
+		{
+			name: "File overrides defaults",
+			args: []string{"-config", path},
+			expected: func(cfg *Config) {
+				cfg.Storage = StorageBolt
+				cfg.Mongo.URI = "mongodb://file-host:27017"
+				cfg.Mongo.Database = "FromFile"
+				cfg.Bolt.Path = "file.db"
+			},
+		},
+		{
+			name: "Environment overrides file",
+			env:  map[string]string{"RENTEASE_CONFIG": path, "RENTEASE_MONGO_URI": "mongodb://env-host:27017", "RENTEASE_DB_PATH": "env.db", "RENTEASE_LEASES_RELIST_NEEDS_APPROVAL": "false", "RENTEASE_AUDIT_FILE": "audit.log"},
+			expected: func(cfg *Config) {
+				cfg.Leases.RelistNeedsApproval = false
+				cfg.Audit.File = "audit.log"
+				cfg.Storage = StorageBolt
+				cfg.Mongo.URI = "mongodb://env-host:27017"
+				cfg.Mongo.Database = "FromFile"
+				cfg.Bolt.Path = "env.db"
+			},
+		},
+		{
+			name: "Flags override environment",
+			env:  map[string]string{"RENTEASE_CONFIG": path, "RENTEASE_STORAGE": "memory", "RENTEASE_EMAIL_SENDER": "smtp", "RENTEASE_EMAIL_SMTP_HOST": "mail.env"},
+			args: []string{"-storage", "mongo", "-mongo-users-collection", "people", "-mongo-operation-timeout", "2m", "-mongo-max-pool-size", "20", "-email-smtp-host", "mail.flag", "-email-smtp-port", "2525"},
+			expected: func(cfg *Config) {
+				cfg.Email.Sender = EmailSMTP
+				cfg.Email.SMTP.Host = "mail.flag"
+				cfg.Email.SMTP.Port = 2525
+				cfg.Storage = StorageMongo
+				cfg.Mongo.OperationTimeout = 2 * time.Minute
+				cfg.Mongo.MaxPoolSize = 20
+				cfg.Mongo.URI = "mongodb://file-host:27017"
+				cfg.Mongo.Database = "FromFile"
+				cfg.Mongo.Collections.Users = "people"
+				cfg.Bolt.Path = "file.db"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("RENTEASE_CONFIG", "")
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := Load(tt.args)
+			require.NoError(t, err)
+
+			expected := Default()
+			tt.expected(&expected)
+			assert.Equal(t, expected, cfg)
+		})
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		args     []string
+		contains []string
+	}{
+		{
+			name:     "Unknown key in file",
+			file:     "storage: mongo\nmongo:\n  url: mongodb://localhost\n",
+			contains: []string{"field url not found"},
+		},
+		{
+			name:     "Missing file",
+			args:     []string{"-config", filepath.Join(os.TempDir(), "does-not-exist.yaml")},
+			contains: []string{"failed to read config file"},
+		},
+		{
+			name:     "Empty HTTP address",
+			args:     []string{"-http-addr", " ", "-http-shutdown-timeout", "0s"},
+			contains: []string{"http.addr must not be empty", "http.shutdown_timeout must be positive"},
+		},
+		{
+			name:     "Weak token secret and empty token lifetimes",
+			args:     []string{"-auth-token-secret", "short", "-auth-access-token-ttl", "0s", "-auth-refresh-token-ttl", "-1h"},
+			contains: []string{"auth.token_secret must be at least 32 characters", "auth.access_token_ttl must be positive", "auth.refresh_token_ttl must be positive"},
+		},
+		{
+			name:     "Unknown storage backend",
+			args:     []string{"-storage", "postgres"},
+			contains: []string{`storage "postgres" must be one of mongo, bolt or memory`},
+		},
+		{
+			name:     "All mongo problems reported together",
+			args:     []string{"-mongo-uri", "localhost:27017", "-mongo-database", "", "-mongo-properties-collection", "users"},
+			contains: []string{"mongo.uri", "mongo.database must not be empty", "mongo.collections.users and properties must be different"},
+		},
+		{
+			name:     "Malformed boolean flag",
+			args:     []string{"-leases-relist-needs-approval", "sometimes"},
+			contains: []string{"invalid -leases-relist-needs-approval", "not true or false"},
+		},
+		{
+			name:     "Malformed duration flag",
+			args:     []string{"-mongo-operation-timeout", "soon"},
+			contains: []string{"invalid -mongo-operation-timeout"},
+		},
+		{
+			name:     "Inconsistent pool and timeouts",
+			args:     []string{"-mongo-max-pool-size", "5", "-mongo-min-pool-size", "10", "-mongo-connect-timeout", "0s", "-mongo-connect-retries", "-1"},
+			contains: []string{"min_pool_size must not exceed", "connect_timeout must be positive", "connect_retries must not be negative"},
+		},
+		{
+			name:     "SMTP sender without a server",
+			args:     []string{"-email-sender", "smtp", "-email-smtp-port", "0", "-email-smtp-timeout", "0s", "-email-from", "nobody"},
+			contains: []string{"email.smtp.host must not be empty", "email.smtp.port must be between 1 and 65535", "email.smtp.timeout must be positive", `email.from "nobody" must be an email address`},
+		},
+		{
+			name:     "Unknown email sender and empty queue",
+			args:     []string{"-email-sender", "carrier-pigeon", "-email-queue-size", "0", "-email-max-attempts", "0", "-email-retry-delay", "0s"},
+			contains: []string{`email.sender "carrier-pigeon" must be one of smtp, file or memory`, "email.queue_size", "email.max_attempts", "email.retry_delay must be positive"},
+		},
+		{
+			name:     "Empty bolt path",
+			args:     []string{"-storage", "bolt", "-db-path", " "},
+			contains: []string{"bolt.path must not be empty"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("RENTEASE_CONFIG", "")
+			args := tt.args
+			if tt.file != "" {
+				args = append([]string{"-config", writeConfigFile(t, tt.file)}, args...)
+			}
+
+			_, err := Load(args)
+			require.Error(t, err)
+			for _, part := range tt.contains {
+				assert.Contains(t, err.Error(), part)
+			}
+		})
+	}
+}
+
+func TestLoad_ExampleFile(t *testing.T) {
+	t.Setenv("RENTEASE_CONFIG", "")
+
+	cfg, err := Load([]string{"-config", "rentease.example.yaml"})
+	require.NoError(t, err)
+	assert.Equal(t, Default(), cfg)
+}
+
+func TestLoad_InvalidEnvironmentValue(t *testing.T) {
+	t.Setenv("RENTEASE_CONFIG", "")
+	t.Setenv("RENTEASE_MONGO_MAX_POOL_SIZE", "-1")
+
+	_, err := Load(nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid RENTEASE_MONGO_MAX_POOL_SIZE")
+}
+
+func TestLoadCommand_ReturnsArgumentsAfterFlags(t *testing.T) {
+	t.Setenv("RENTEASE_CONFIG", "")
+
+	cfg, args, err := LoadCommand([]string{"-storage", "memory", "property", "list", "-json"})
+	require.NoError(t, err)
+	assert.Equal(t, StorageMemory, cfg.Storage)
+	assert.Equal(t, []string{"property", "list", "-json"}, args)
+
+	_, args, err = LoadCommand(nil)
+	require.NoError(t, err)
+	assert.Empty(t, args)
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"net/http"
+
+	"rentease/internal/domain/entities"
+)
+
+type roleRequest struct {
+	Role string `json:"role"`
+}
+
+type rejectRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (s *Server) handleListUsers(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	users, err := s.userService.GetAllUsers(r.Context(), session)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if users == nil {
+		users = []entities.User{}
+	}
+	writeJSON(w, http.StatusOK, users)
+}
+
+// handleDeleteUser deletes a user. Their listed properties and logins are removed first. Admins cannot
+// be deleted.
+func (s *Server) handleDeleteUser(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	username := r.PathValue("username")
+	if err := s.userService.DeleteUser(r.Context(), session, username); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleSetRole changes the role of a user. It takes effect on their next request.
+func (s *Server) handleSetRole(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	var req roleRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	username := r.PathValue("username")
+	if err := s.userService.SetRole(r.Context(), session, username, req.Role); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	user, err := s.userService.FindByUsername(r.Context(), username)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (s *Server) handlePendingProperties(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	properties, err := s.propertyService.GetPendingProperties(r.Context(), session)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if properties == nil {
+		properties = []entities.Property{}
+	}
+	writeJSON(w, http.StatusOK, properties)
+}
+
+func (s *Server) handleApproveProperty(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	if _, ok := s.findProperty(w, r, id); !ok {
+		return
+	}
+
+	if err := s.propertyService.ApproveProperty(r.Context(), session, id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	property, ok := s.findProperty(w, r, id)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, property)
+}
+
+// handleRejectProperty turns down a property waiting for approval, removing the listing, and gives it as it was.
+func (s *Server) handleRejectProperty(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	var req rejectRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	property, ok := s.findProperty(w, r, id)
+	if !ok {
+		return
+	}
+
+	if err := s.propertyService.RejectProperty(r.Context(), session, id, req.Reason); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, property)
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"rentease/internal/domain/entities"
+)
+
+// sessionHandler is an endpoint that needs the session of the calling user.
+type sessionHandler func(w http.ResponseWriter, r *http.Request, session *entities.Session)
+
+// bearerToken returns the token of the "Authorization: Bearer <token>" header.
+func bearerToken(r *http.Request) string {
+	const prefix = "Bearer "
+	header := r.Header.Get("Authorization")
+	if len(header) < len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return ""
+	}
+	return strings.TrimSpace(header[len(prefix):])
+}
+
+// authenticated resolves the bearer access token to the session of its user before calling next.
+func (s *Server) authenticated(next sessionHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := bearerToken(r)
+		if token == "" {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, codeUnauthorized, "missing bearer token")
+			return
+		}
+		session, err := s.tokenService.Authenticate(r.Context(), token)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			writeServiceError(w, err)
+			return
+		}
+		next(w, r, session)
+	}
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"rentease/internal/domain/entities"
+)
+
+type depositDeductionsRequest struct {
+	Deductions []entities.DepositDeduction `json:"deductions"`
+}
+
+type depositDisputeRequest struct {
+	Reason string `json:"reason"`
+}
+
+// handleDeposit shows the security deposit of a lease the logged in user is a party to.
+func (s *Server) handleDeposit(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	deposit, err := s.depositService.Deposit(r.Context(), session, id)
+	writeDeposit(w, http.StatusOK, deposit, err)
+}
+
+// handleRecordDeposit lets the landlord record receiving the security deposit of a lease.
+func (s *Server) handleRecordDeposit(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	var req paymentRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	receipt := entities.Payment{Amount: req.Amount, Mode: req.Mode, Reference: req.Reference}
+	if req.PaidOn != "" {
+		paidOn, err := time.Parse(paymentDateLayout, req.PaidOn)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, codeBadRequest, fmt.Sprintf("paid_on must be a date such as %s", paymentDateLayout))
+			return
+		}
+		receipt.PaidOn = paidOn
+	}
+
+	deposit, err := s.depositService.RecordDeposit(r.Context(), session, id, receipt)
+	writeDeposit(w, http.StatusCreated, deposit, err)
+}
+
+// handleProposeDeductions lets the landlord itemise what they keep back of the deposit at move-out.
+func (s *Server) handleProposeDeductions(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	var req depositDeductionsRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	deposit, err := s.depositService.ProposeDeductions(r.Context(), session, id, req.Deductions)
+	writeDeposit(w, http.StatusOK, deposit, err)
+}
+
+// handleAcknowledgeDeductions lets the tenant accept the deposit deductions.
+func (s *Server) handleAcknowledgeDeductions(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	deposit, err := s.depositService.AcknowledgeDeductions(r.Context(), session, id)
+	writeDeposit(w, http.StatusOK, deposit, err)
+}
+
+// handleDisputeDeductions lets the tenant dispute the deposit deductions.
+func (s *Server) handleDisputeDeductions(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	var req depositDisputeRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	deposit, err := s.depositService.DisputeDeductions(r.Context(), session, id, req.Reason)
+	writeDeposit(w, http.StatusOK, deposit, err)
+}
+
+func writeDeposit(w http.ResponseWriter, status int, deposit entities.Deposit, err error) {
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, status, deposit)
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"rentease/internal/domain/entities"
+)
+
+// handleLeaseAgreement sends a printable copy of a lease agreement the logged in user is a party to.
+func (s *Server) handleLeaseAgreement(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	document, err := s.documentService.LeaseAgreement(r.Context(), session, id)
+	writeDocument(w, document, err)
+}
+
+// handleRentReceipt sends the receipt of a rent payment for a lease the logged in user is a party to.
+func (s *Server) handleRentReceipt(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	paymentID, ok := pathObjectID(w, r, "paymentID")
+	if !ok {
+		return
+	}
+	document, err := s.documentService.RentReceipt(r.Context(), session, id, paymentID)
+	writeDocument(w, document, err)
+}
+
+// writeDocument sends the document as a download under its file name.
+func writeDocument(w http.ResponseWriter, document entities.Document, err error) {
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", document.ContentType)
+	w.Header().Set("Content-Disposition", "attachment; filename="+strconv.Quote(document.FileName))
+	w.Header().Set("Content-Length", strconv.Itoa(len(document.Content)))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(document.Content)
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"net/http"
+
+	"rentease/internal/domain/entities"
+)
+
+// handleTenantLeases lists the leases of the logged in tenant.
+func (s *Server) handleTenantLeases(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	leases, err := s.leaseService.GetLeasesForTenant(r.Context(), session)
+	writeLeases(w, leases, err)
+}
+
+// handleLandlordLeases lists the leases of the properties of the logged in landlord.
+func (s *Server) handleLandlordLeases(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	leases, err := s.leaseService.GetLeasesForLandlord(r.Context(), session)
+	writeLeases(w, leases, err)
+}
+
+// handleGetLease shows a lease the logged in user is a party to.
+func (s *Server) handleGetLease(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	lease, err := s.leaseService.GetLease(r.Context(), session, id)
+	writeLease(w, lease, err)
+}
+
+// handleGiveNotice lets the tenant or landlord end an active lease early.
+func (s *Server) handleGiveNotice(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	lease, err := s.leaseService.GiveNotice(r.Context(), session, id)
+	writeLease(w, lease, err)
+}
+
+type renewalOfferRequest struct {
+	MonthlyRent float64 `json:"monthly_rent"`
+	Months      int     `json:"months"`
+}
+
+// handleOfferRenewal lets the landlord offer to extend an active lease.
+func (s *Server) handleOfferRenewal(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	var req renewalOfferRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	lease, err := s.leaseService.OfferRenewal(r.Context(), session, id, req.MonthlyRent, req.Months)
+	writeLease(w, lease, err)
+}
+
+// handleSetRentRules lets the landlord set the rent escalation and late fee rules of a lease.
+func (s *Server) handleSetRentRules(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	var rules entities.RentRules
+	if !decodeJSON(w, r, &rules) {
+		return
+	}
+	lease, err := s.leaseService.SetRentRules(r.Context(), session, id, rules)
+	writeLease(w, lease, err)
+}
+
+// handleAcceptRenewal lets the tenant accept the renewal offer of their lease.
+func (s *Server) handleAcceptRenewal(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	s.answerRenewal(w, r, session, true)
+}
+
+// handleDeclineRenewal lets the tenant decline the renewal offer of their lease.
+func (s *Server) handleDeclineRenewal(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	s.answerRenewal(w, r, session, false)
+}
+
+func (s *Server) answerRenewal(w http.ResponseWriter, r *http.Request, session *entities.Session, accept bool) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	lease, err := s.leaseService.AnswerRenewal(r.Context(), session, id, accept)
+	writeLease(w, lease, err)
+}
+
+func writeLease(w http.ResponseWriter, lease entities.Lease, err error) {
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, lease)
+}
+
+func writeLeases(w http.ResponseWriter, leases []entities.Lease, err error) {
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if leases == nil {
+		leases = []entities.Lease{}
+	}
+	writeJSON(w, http.StatusOK, leases)
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"rentease/internal/app/services"
+	"rentease/internal/domain/entities"
+)
+
+// paymentDateLayout is the format of the date a payment was made on.
+const paymentDateLayout = "2006-01-02"
+
+type paymentRequest struct {
+	Amount    float64              `json:"amount"`
+	PaidOn    string               `json:"paid_on"` // Today when empty
+	Mode      entities.PaymentMode `json:"mode"`
+	Reference string               `json:"reference"`
+}
+
+// handleStatement shows the rent statement of a lease the logged in user is a party to.
+func (s *Server) handleStatement(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	statement, err := s.ledgerService.Statement(r.Context(), session, id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, statement)
+}
+
+// handleRecordPayment lets the landlord record rent received under a lease.
+func (s *Server) handleRecordPayment(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	var req paymentRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	payment := entities.Payment{Amount: req.Amount, Mode: req.Mode, Reference: req.Reference}
+	if req.PaidOn != "" {
+		paidOn, err := time.Parse(paymentDateLayout, req.PaidOn)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, codeBadRequest, fmt.Sprintf("paid_on must be a date such as %s", paymentDateLayout))
+			return
+		}
+		payment.PaidOn = paidOn
+	}
+
+	payment, err := s.ledgerService.RecordPayment(r.Context(), session, id, payment)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, payment)
+}
+
+// handleSchedule shows the rent due in the months ahead under the rules of a lease the logged in user is a party to.
+func (s *Server) handleSchedule(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	s.writeSchedule(w, r, session, nil)
+}
+
+// handleScheduleDryRun shows the rent due in the months ahead under the rules in the request, as if they were
+// set on the lease.
+func (s *Server) handleScheduleDryRun(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	var rules entities.RentRules
+	if !decodeJSON(w, r, &rules) {
+		return
+	}
+	s.writeSchedule(w, r, session, &rules)
+}
+
+// writeSchedule writes the schedule of the lease in the path for as many months as the months query
+// parameter asks for, by default services.DefaultScheduleMonths.
+func (s *Server) writeSchedule(w http.ResponseWriter, r *http.Request, session *entities.Session, rules *entities.RentRules) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	months := services.DefaultScheduleMonths
+	if value := r.URL.Query().Get("months"); value != "" {
+		var err error
+		if months, err = strconv.Atoi(value); err != nil {
+			writeError(w, http.StatusBadRequest, codeBadRequest, "months must be a number")
+			return
+		}
+	}
+	schedule, err := s.ledgerService.Schedule(r.Context(), session, id, rules, months)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, schedule)
+}
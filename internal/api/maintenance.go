@@ -0,0 +1,105 @@
+package api
+
+import (
+	"net/http"
+
+	"rentease/internal/domain/entities"
+)
+
+type reportIssueRequest struct {
+	Category    entities.MaintenanceCategory `json:"category"`
+	Priority    entities.MaintenancePriority `json:"priority"`
+	Description string                       `json:"description"`
+}
+
+type ticketStatusRequest struct {
+	Status entities.TicketStatus `json:"status"`
+	Note   string                `json:"note"`
+}
+
+type ticketCommentRequest struct {
+	Text string `json:"text"`
+}
+
+// handleReportIssue lets the tenant of a lease report an issue with the property.
+func (s *Server) handleReportIssue(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	var req reportIssueRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	ticket, err := s.maintenanceService.ReportIssue(r.Context(), session, id, req.Category, req.Priority, req.Description)
+	writeTicket(w, http.StatusCreated, ticket, err)
+}
+
+// handleTenantTickets lists the maintenance tickets the logged in tenant reported.
+func (s *Server) handleTenantTickets(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	tickets, err := s.maintenanceService.TicketsForTenant(r.Context(), session)
+	writeTickets(w, tickets, err)
+}
+
+// handleMaintenanceQueue lists the tickets the logged in landlord has to work on, overdue ones first.
+func (s *Server) handleMaintenanceQueue(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	tickets, err := s.maintenanceService.MaintenanceQueue(r.Context(), session)
+	writeTickets(w, tickets, err)
+}
+
+// handleGetTicket shows a maintenance ticket the logged in user is a party to.
+func (s *Server) handleGetTicket(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	ticket, err := s.maintenanceService.Ticket(r.Context(), session, id)
+	writeTicket(w, http.StatusOK, ticket, err)
+}
+
+// handleUpdateTicketStatus moves a maintenance ticket on, optionally with a note.
+func (s *Server) handleUpdateTicketStatus(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	var req ticketStatusRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	ticket, err := s.maintenanceService.UpdateTicketStatus(r.Context(), session, id, req.Status, req.Note)
+	writeTicket(w, http.StatusOK, ticket, err)
+}
+
+// handleAddTicketComment adds a comment to a maintenance ticket.
+func (s *Server) handleAddTicketComment(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	var req ticketCommentRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	ticket, err := s.maintenanceService.AddComment(r.Context(), session, id, req.Text)
+	writeTicket(w, http.StatusCreated, ticket, err)
+}
+
+func writeTicket(w http.ResponseWriter, status int, ticket entities.MaintenanceTicket, err error) {
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, status, ticket)
+}
+
+func writeTickets(w http.ResponseWriter, tickets []entities.MaintenanceTicket, err error) {
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if tickets == nil {
+		tickets = []entities.MaintenanceTicket{}
+	}
+	writeJSON(w, http.StatusOK, tickets)
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/domain/entities"
+)
+
+type messageRequest struct {
+	Text string `json:"text"`
+}
+
+// handleContactLandlord sends a message to the landlord of a property, starting a thread about it if needed.
+func (s *Server) handleContactLandlord(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	s.postMessage(w, r, func(id primitive.ObjectID, text string) (entities.Thread, error) {
+		return s.messageService.ContactLandlord(r.Context(), session, id, text)
+	})
+}
+
+// handleMessageAboutRequest sends a message to the other party of a rent request.
+func (s *Server) handleMessageAboutRequest(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	s.postMessage(w, r, func(id primitive.ObjectID, text string) (entities.Thread, error) {
+		return s.messageService.MessageAboutRequest(r.Context(), session, id, text)
+	})
+}
+
+// handleSendMessage sends a message in a thread of the logged in user.
+func (s *Server) handleSendMessage(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	s.postMessage(w, r, func(id primitive.ObjectID, text string) (entities.Thread, error) {
+		return s.messageService.SendMessage(r.Context(), session, id, text)
+	})
+}
+
+// handleThreads lists the threads of the logged in user, latest message first.
+func (s *Server) handleThreads(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	threads, err := s.messageService.Threads(r.Context(), session)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if threads == nil {
+		threads = []entities.Thread{}
+	}
+	writeJSON(w, http.StatusOK, threads)
+}
+
+// handleUnreadMessages counts the messages the logged in user has not read yet.
+func (s *Server) handleUnreadMessages(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	unread, err := s.messageService.UnreadMessages(r.Context(), session)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, unread)
+}
+
+// handleReadThread shows a thread of the logged in user and marks it read for them.
+func (s *Server) handleReadThread(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	thread, err := s.messageService.ReadThread(r.Context(), session, id)
+	writeThread(w, http.StatusOK, thread, err)
+}
+
+// postMessage decodes the message and sends it with send to the ID in the path.
+func (s *Server) postMessage(w http.ResponseWriter, r *http.Request, send func(id primitive.ObjectID, text string) (entities.Thread, error)) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	var req messageRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	thread, err := send(id, req.Text)
+	writeThread(w, http.StatusCreated, thread, err)
+}
+
+func writeThread(w http.ResponseWriter, status int, thread entities.Thread, err error) {
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, status, thread)
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/domain/entities"
+)
+
+type unreadNotificationsResponse struct {
+	Unread int `json:"unread"`
+}
+
+// handleNotifications lists the notifications of the logged in user, newest first,
+// or with ?unread=true only those not read yet.
+func (s *Server) handleNotifications(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	s.writeNotifications(w, r, session, r.URL.Query().Get("unread") == "true")
+}
+
+// handleUnreadNotifications counts the notifications the logged in user has not read yet.
+func (s *Server) handleUnreadNotifications(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	unread, err := s.notificationService.UnreadNotifications(r.Context(), session)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, unreadNotificationsResponse{Unread: unread})
+}
+
+// handleMarkNotificationRead marks one notification of the logged in user read.
+func (s *Server) handleMarkNotificationRead(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	s.changeNotification(w, r, func(id primitive.ObjectID) error {
+		return s.notificationService.MarkNotificationRead(r.Context(), session, id)
+	})
+}
+
+// handleDeleteNotification removes one notification of the logged in user.
+func (s *Server) handleDeleteNotification(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	s.changeNotification(w, r, func(id primitive.ObjectID) error {
+		return s.notificationService.DeleteNotification(r.Context(), session, id)
+	})
+}
+
+// handleMarkAllNotificationsRead marks every notification of the logged in user read and lists them.
+func (s *Server) handleMarkAllNotificationsRead(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	if _, err := s.notificationService.MarkAllNotificationsRead(r.Context(), session); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	s.writeNotifications(w, r, session, false)
+}
+
+// handleClearNotifications removes the notifications the logged in user has read and lists the rest.
+func (s *Server) handleClearNotifications(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	if _, err := s.notificationService.ClearReadNotifications(r.Context(), session); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	s.writeNotifications(w, r, session, false)
+}
+
+// changeNotification runs change on the notification in the path and answers 204 when it succeeds.
+func (s *Server) changeNotification(w http.ResponseWriter, r *http.Request, change func(id primitive.ObjectID) error) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	if err := change(id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) writeNotifications(w http.ResponseWriter, r *http.Request, session *entities.Session, unreadOnly bool) {
+	notifications, err := s.notificationService.Notifications(r.Context(), session)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	listed := []entities.Notification{}
+	for _, notification := range notifications {
+		if !unreadOnly || !notification.Read {
+			listed = append(listed, notification)
+		}
+	}
+	writeJSON(w, http.StatusOK, listed)
+}
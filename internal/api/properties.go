@@ -0,0 +1,245 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/domain/entities"
+)
+
+// propertyRequest is the body used to list or update a property.
+// Details are decoded into the typed struct selected by PropertyType.
+type propertyRequest struct {
+	PropertyType int              `json:"property_type"`
+	Title        string           `json:"title"`
+	Address      entities.Address `json:"address"`
+	RentAmount   float64          `json:"rent_amount"`
+	Deposit      float64          `json:"deposit"` // The default of the leases when 0
+	Details      json.RawMessage  `json:"details"`
+}
+
+// toProperty validates the request and converts it into a property with typed details.
+func (req propertyRequest) toProperty() (entities.Property, error) {
+	if strings.TrimSpace(req.Title) == "" {
+		return entities.Property{}, fmt.Errorf("title must not be empty")
+	}
+	if req.RentAmount <= 0 {
+		return entities.Property{}, fmt.Errorf("rent_amount must be positive")
+	}
+	if req.Deposit < 0 {
+		return entities.Property{}, fmt.Errorf("deposit must not be negative")
+	}
+	details, err := decodeDetails(req.PropertyType, req.Details)
+	if err != nil {
+		return entities.Property{}, err
+	}
+	return entities.Property{
+		PropertyType: req.PropertyType,
+		Title:        req.Title,
+		Address:      req.Address,
+		RentAmount:   req.RentAmount,
+		Deposit:      req.Deposit,
+		Details:      details,
+	}, nil
+}
+
+// decodeDetails decodes the JSON details of a property of the given type.
+func decodeDetails(propertyType int, raw json.RawMessage) (interface{}, error) {
+	var details interface{}
+	switch propertyType {
+	case 1: // Commercial
+		details = &entities.CommercialDetails{}
+	case 2: // House
+		details = &entities.HouseDetails{}
+	case 3: // Flat
+		details = &entities.FlatDetails{}
+	default:
+		return nil, fmt.Errorf("property_type must be 1 (commercial), 2 (house) or 3 (flat)")
+	}
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("details must not be empty")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(details); err != nil {
+		return nil, fmt.Errorf("invalid details: %v", err)
+	}
+
+	// Store the details by value, as the repositories do
+	switch d := details.(type) {
+	case *entities.CommercialDetails:
+		return *d, nil
+	case *entities.HouseDetails:
+		return *d, nil
+	default:
+		return *d.(*entities.FlatDetails), nil
+	}
+}
+
+// findProperty loads the property, writing a not found response if it does not exist.
+func (s *Server) findProperty(w http.ResponseWriter, r *http.Request, id primitive.ObjectID) (entities.Property, bool) {
+	property, err := s.propertyService.FindByID(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, err)
+		return entities.Property{}, false
+	}
+	if property.ID.IsZero() {
+		writeError(w, http.StatusNotFound, codeNotFound, "property not found")
+		return entities.Property{}, false
+	}
+	return property, true
+}
+
+// findPathProperty loads the property named by the id path value.
+func (s *Server) findPathProperty(w http.ResponseWriter, r *http.Request) (entities.Property, bool) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return entities.Property{}, false
+	}
+	return s.findProperty(w, r, id)
+}
+
+// handleListProperties lists the approved properties that are still available.
+func (s *Server) handleListProperties(w http.ResponseWriter, r *http.Request) {
+	properties, err := s.propertyService.GetAllListedProperties(r.Context(), "")
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	available := []entities.Property{}
+	for _, property := range properties {
+		if property.IsApprovedByAdmin {
+			available = append(available, property)
+		}
+	}
+	writeJSON(w, http.StatusOK, available)
+}
+
+func (s *Server) handleSearchProperties(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	propertyType, err := strconv.Atoi(query.Get("type"))
+	if err != nil || propertyType < 1 || propertyType > 3 {
+		writeError(w, http.StatusBadRequest, codeBadRequest, "type must be 1 (commercial), 2 (house) or 3 (flat)")
+		return
+	}
+	var pincode int
+	if value := query.Get("pincode"); value != "" {
+		if pincode, err = strconv.Atoi(value); err != nil {
+			writeError(w, http.StatusBadRequest, codeBadRequest, "pincode must be a number")
+			return
+		}
+	}
+
+	properties, err := s.propertyService.SearchProperties(r.Context(), query.Get("area"), query.Get("city"), query.Get("state"), pincode, propertyType)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if properties == nil {
+		properties = []entities.Property{}
+	}
+	writeJSON(w, http.StatusOK, properties)
+}
+
+func (s *Server) handleMyProperties(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	properties, err := s.propertyService.GetAllListedProperties(r.Context(), session.Username())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if properties == nil {
+		properties = []entities.Property{}
+	}
+	writeJSON(w, http.StatusOK, properties)
+}
+
+func (s *Server) handleCreateProperty(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	var req propertyRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	property, err := req.toProperty()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
+		return
+	}
+
+	// New properties wait for admin approval
+	property.ID = primitive.NewObjectID()
+	property.LandlordUsername = session.Username()
+	if err := s.propertyService.ListProperty(r.Context(), session, property); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, property)
+}
+
+func (s *Server) handleGetProperty(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	property, ok := s.findProperty(w, r, id)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, property)
+}
+
+// handleUpdateProperty lets the landlord change their property. The service checks ownership.
+func (s *Server) handleUpdateProperty(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	stored, ok := s.findPathProperty(w, r)
+	if !ok {
+		return
+	}
+	var req propertyRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if req.PropertyType != stored.PropertyType {
+		writeError(w, http.StatusBadRequest, codeBadRequest, "property_type cannot be changed")
+		return
+	}
+	update, err := req.toProperty()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
+		return
+	}
+
+	stored.Title = update.Title
+	stored.Address = update.Address
+	stored.RentAmount = update.RentAmount
+	stored.Deposit = update.Deposit
+	stored.Details = update.Details
+	if err := s.propertyService.UpdateListedProperty(r.Context(), session, stored); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	updated, ok := s.findProperty(w, r, stored.ID)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// handleDeleteProperty deletes a property of the landlord, or any property for moderators and admins.
+func (s *Server) handleDeleteProperty(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	if err := s.propertyService.DeleteListedProperty(r.Context(), session, id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
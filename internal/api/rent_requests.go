@@ -0,0 +1,129 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/domain/entities"
+)
+
+type rentRequestRequest struct {
+	PropertyID primitive.ObjectID `json:"property_id"`
+}
+
+type rentRequestStatusRequest struct {
+	Status string `json:"status"`
+}
+
+func (s *Server) handleCreateRentRequest(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	var req rentRequestRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if err := s.requestService.CreateRentRequest(r.Context(), session, req.PropertyID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) handleSentRentRequests(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	requests, err := s.requestService.GetRentRequestsInfoForTenant(r.Context(), session)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if requests == nil {
+		requests = []entities.Request{}
+	}
+	writeJSON(w, http.StatusOK, requests)
+}
+
+func (s *Server) handleReceivedRentRequests(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	requests, err := s.requestService.GetRentRequestsInfoForLandlord(r.Context(), session)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if requests == nil {
+		requests = []entities.Request{}
+	}
+	writeJSON(w, http.StatusOK, requests)
+}
+
+// findRequest finds the request among those returned by list, writing a not found response if it is not there.
+func findRequest(w http.ResponseWriter, requests []entities.Request, err error, id primitive.ObjectID) (entities.Request, bool) {
+	if err != nil {
+		writeServiceError(w, err)
+		return entities.Request{}, false
+	}
+	for _, request := range requests {
+		if request.ID == id {
+			return request, true
+		}
+	}
+	writeError(w, http.StatusNotFound, codeNotFound, "rent request not found")
+	return entities.Request{}, false
+}
+
+// findReceivedRequest finds a request for one of the properties of the landlord.
+func (s *Server) findReceivedRequest(w http.ResponseWriter, r *http.Request, session *entities.Session, id primitive.ObjectID) (entities.Request, bool) {
+	requests, err := s.requestService.GetRentRequestsInfoForLandlord(r.Context(), session)
+	return findRequest(w, requests, err, id)
+}
+
+// findSentRequest finds a request sent by the tenant.
+func (s *Server) findSentRequest(w http.ResponseWriter, r *http.Request, session *entities.Session, id primitive.ObjectID) (entities.Request, bool) {
+	requests, err := s.requestService.GetRentRequestsInfoForTenant(r.Context(), session)
+	return findRequest(w, requests, err, id)
+}
+
+// handleUpdateRentRequestStatus lets the landlord answer a request for one of their properties.
+func (s *Server) handleUpdateRentRequestStatus(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	var req rentRequestStatusRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	status := entities.RequestStatus(req.Status)
+	if !status.IsValid() {
+		writeError(w, http.StatusBadRequest, codeBadRequest, fmt.Sprintf("unknown status %q", req.Status))
+		return
+	}
+
+	request, ok := s.findReceivedRequest(w, r, session, id)
+	if !ok {
+		return
+	}
+	if err := s.requestService.UpdateRequestStatus(r.Context(), session, request.ID, status); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	if request, ok = s.findReceivedRequest(w, r, session, id); !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, request)
+}
+
+// handleWithdrawRentRequest lets the tenant take back a pending request.
+func (s *Server) handleWithdrawRentRequest(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	if err := s.requestService.WithdrawRequest(r.Context(), session, id); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	request, ok := s.findSentRequest(w, r, session, id)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, request)
+}
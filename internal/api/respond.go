@@ -0,0 +1,126 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/app/services"
+)
+
+// Error codes returned in the body of every failed request.
+const (
+	codeBadRequest   = "bad_request"
+	codeUnauthorized = "unauthorized"
+	codeForbidden    = "forbidden"
+	codeNotFound     = "not_found"
+	codeConflict     = "conflict"
+	codeInternal     = "internal_error"
+)
+
+// maxBodyBytes limits the size of JSON request bodies.
+const maxBodyBytes = 1 << 20
+
+// errorResponse is the body of every failed request.
+type errorResponse struct {
+	Error errorDetail `json:"error"`
+}
+
+type errorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// writeJSON writes v as the JSON body of the response.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Println("failed to encode response:", err)
+	}
+}
+
+// writeError writes an error body with the given status and code.
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, errorResponse{Error: errorDetail{Code: code, Message: message}})
+}
+
+// writeServiceError maps an error returned by a service onto a status and error body.
+// Unexpected errors are logged and reported without their details.
+func writeServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidCredentials),
+		errors.Is(err, services.ErrNotLoggedIn),
+		errors.Is(err, services.ErrSessionExpired),
+		errors.Is(err, services.ErrInvalidToken),
+		errors.Is(err, services.ErrTokenExpired),
+		errors.Is(err, services.ErrTokenRevoked):
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, err.Error())
+	case errors.Is(err, services.ErrForbidden):
+		writeError(w, http.StatusForbidden, codeForbidden, err.Error())
+	case errors.Is(err, services.ErrUserNotFound),
+		errors.Is(err, services.ErrPropertyNotFound),
+		errors.Is(err, services.ErrRequestNotFound),
+		errors.Is(err, services.ErrLeaseNotFound),
+		errors.Is(err, services.ErrPaymentNotFound),
+		errors.Is(err, services.ErrTicketNotFound),
+		errors.Is(err, services.ErrSlotNotFound),
+		errors.Is(err, services.ErrThreadNotFound),
+		errors.Is(err, services.ErrNotificationNotFound):
+		writeError(w, http.StatusNotFound, codeNotFound, err.Error())
+	case errors.Is(err, services.ErrInvalidRole),
+		errors.Is(err, services.ErrOwnProperty),
+		errors.Is(err, services.ErrInvalidLeaseTerms),
+		errors.Is(err, services.ErrInvalidRentRules),
+		errors.Is(err, services.ErrInvalidPayment),
+		errors.Is(err, services.ErrInvalidDeposit),
+		errors.Is(err, services.ErrInvalidTicket),
+		errors.Is(err, services.ErrInvalidVisit),
+		errors.Is(err, services.ErrInvalidMessage):
+		writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
+	case errors.Is(err, services.ErrUsernameTaken),
+		errors.Is(err, services.ErrAlreadyInWishlist),
+		errors.Is(err, services.ErrInvalidTransition),
+		errors.Is(err, services.ErrPropertyRented),
+		errors.Is(err, services.ErrPropertyApproved),
+		errors.Is(err, services.ErrRequestNotAllowed),
+		errors.Is(err, services.ErrVisitNotAllowed),
+		errors.Is(err, services.ErrNoRenewalOffer):
+		writeError(w, http.StatusConflict, codeConflict, err.Error())
+	default:
+		log.Println("api:", err)
+		writeError(w, http.StatusInternalServerError, codeInternal, "internal server error")
+	}
+}
+
+// decodeJSON reads the JSON request body into v, rejecting unknown fields.
+// On failure the error response has already been written.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		if errors.Is(err, io.EOF) {
+			writeError(w, http.StatusBadRequest, codeBadRequest, "request body is empty")
+		} else {
+			writeError(w, http.StatusBadRequest, codeBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		}
+		return false
+	}
+	return true
+}
+
+// pathObjectID parses the named path value as an ObjectID.
+// On failure the error response has already been written.
+func pathObjectID(w http.ResponseWriter, r *http.Request, name string) (primitive.ObjectID, bool) {
+	id, err := primitive.ObjectIDFromHex(r.PathValue(name))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeBadRequest, fmt.Sprintf("%s must be a 24 character hex id", name))
+		return primitive.NilObjectID, false
+	}
+	return id, true
+}
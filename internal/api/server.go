@@ -0,0 +1,167 @@
+// Package api exposes the RentEase services as a REST/JSON HTTP API.
+package api
+
+import (
+	_ "embed"
+	"net/http"
+
+	"rentease/internal/domain/interfaces"
+)
+
+//go:embed openapi.yaml
+var openAPIDocument []byte
+
+// Server serves the HTTP API on top of the user, property, rent request, lease, ledger, deposit, maintenance,
+// visit, message, notification and document services. Clients authenticate with the access tokens issued by the token service.
+type Server struct {
+	userService         interfaces.UserService
+	propertyService     interfaces.PropertyService
+	requestService      interfaces.RentRequestService
+	leaseService        interfaces.LeaseService
+	ledgerService       interfaces.LedgerService
+	depositService      interfaces.DepositService
+	maintenanceService  interfaces.MaintenanceService
+	visitService        interfaces.VisitService
+	messageService      interfaces.MessageService
+	notificationService interfaces.NotificationService
+	documentService     interfaces.DocumentService
+	tokenService        interfaces.TokenService
+
+	mux *http.ServeMux
+}
+
+// NewServer initializes the API with the provided services.
+func NewServer(userService interfaces.UserService, propertyService interfaces.PropertyService, requestService interfaces.RentRequestService, leaseService interfaces.LeaseService, ledgerService interfaces.LedgerService, depositService interfaces.DepositService, maintenanceService interfaces.MaintenanceService, visitService interfaces.VisitService, messageService interfaces.MessageService, notificationService interfaces.NotificationService, documentService interfaces.DocumentService, tokenService interfaces.TokenService) *Server {
+	s := &Server{
+		userService:         userService,
+		propertyService:     propertyService,
+		requestService:      requestService,
+		leaseService:        leaseService,
+		ledgerService:       ledgerService,
+		depositService:      depositService,
+		maintenanceService:  maintenanceService,
+		visitService:        visitService,
+		messageService:      messageService,
+		notificationService: notificationService,
+		documentService:     documentService,
+		tokenService:        tokenService,
+		mux:                 http.NewServeMux(),
+	}
+	s.routes()
+	return s
+}
+
+// ServeHTTP makes the Server an http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// routes registers every endpoint. Keep openapi.yaml in sync with this list.
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /api/v1/openapi.yaml", s.handleOpenAPI)
+
+	// Accounts
+	s.mux.HandleFunc("POST /api/v1/signup", s.handleSignUp)
+	s.mux.HandleFunc("POST /api/v1/login", s.handleLogin)
+	s.mux.HandleFunc("POST /api/v1/token/refresh", s.handleRefreshToken)
+	s.mux.HandleFunc("POST /api/v1/logout", s.authenticated(s.handleLogout))
+	s.mux.HandleFunc("POST /api/v1/logout/all", s.authenticated(s.handleLogoutAll))
+	s.mux.HandleFunc("GET /api/v1/me", s.authenticated(s.handleMe))
+
+	// Wishlist
+	s.mux.HandleFunc("GET /api/v1/wishlist", s.authenticated(s.handleGetWishlist))
+	s.mux.HandleFunc("POST /api/v1/wishlist", s.authenticated(s.handleAddToWishlist))
+
+	// Properties
+	s.mux.HandleFunc("GET /api/v1/properties", s.handleListProperties)
+	s.mux.HandleFunc("GET /api/v1/properties/search", s.handleSearchProperties)
+	s.mux.HandleFunc("GET /api/v1/properties/mine", s.authenticated(s.handleMyProperties))
+	s.mux.HandleFunc("POST /api/v1/properties", s.authenticated(s.handleCreateProperty))
+	s.mux.HandleFunc("GET /api/v1/properties/{id}", s.handleGetProperty)
+	s.mux.HandleFunc("PUT /api/v1/properties/{id}", s.authenticated(s.handleUpdateProperty))
+	s.mux.HandleFunc("DELETE /api/v1/properties/{id}", s.authenticated(s.handleDeleteProperty))
+
+	// Rent requests
+	s.mux.HandleFunc("POST /api/v1/rent-requests", s.authenticated(s.handleCreateRentRequest))
+	s.mux.HandleFunc("GET /api/v1/rent-requests/sent", s.authenticated(s.handleSentRentRequests))
+	s.mux.HandleFunc("GET /api/v1/rent-requests/received", s.authenticated(s.handleReceivedRentRequests))
+	s.mux.HandleFunc("PUT /api/v1/rent-requests/{id}/status", s.authenticated(s.handleUpdateRentRequestStatus))
+	s.mux.HandleFunc("POST /api/v1/rent-requests/{id}/withdraw", s.authenticated(s.handleWithdrawRentRequest))
+
+	// Leases
+	s.mux.HandleFunc("GET /api/v1/leases/as-tenant", s.authenticated(s.handleTenantLeases))
+	s.mux.HandleFunc("GET /api/v1/leases/as-landlord", s.authenticated(s.handleLandlordLeases))
+	s.mux.HandleFunc("GET /api/v1/leases/{id}", s.authenticated(s.handleGetLease))
+	s.mux.HandleFunc("POST /api/v1/leases/{id}/notice", s.authenticated(s.handleGiveNotice))
+	s.mux.HandleFunc("POST /api/v1/leases/{id}/renewal", s.authenticated(s.handleOfferRenewal))
+	s.mux.HandleFunc("POST /api/v1/leases/{id}/renewal/accept", s.authenticated(s.handleAcceptRenewal))
+	s.mux.HandleFunc("POST /api/v1/leases/{id}/renewal/decline", s.authenticated(s.handleDeclineRenewal))
+	s.mux.HandleFunc("PUT /api/v1/leases/{id}/rules", s.authenticated(s.handleSetRentRules))
+
+	// Rent ledger
+	s.mux.HandleFunc("GET /api/v1/leases/{id}/statement", s.authenticated(s.handleStatement))
+	s.mux.HandleFunc("POST /api/v1/leases/{id}/payments", s.authenticated(s.handleRecordPayment))
+	s.mux.HandleFunc("GET /api/v1/leases/{id}/schedule", s.authenticated(s.handleSchedule))
+	s.mux.HandleFunc("POST /api/v1/leases/{id}/schedule/dry-run", s.authenticated(s.handleScheduleDryRun))
+
+	// Security deposits
+	s.mux.HandleFunc("GET /api/v1/leases/{id}/deposit", s.authenticated(s.handleDeposit))
+	s.mux.HandleFunc("POST /api/v1/leases/{id}/deposit", s.authenticated(s.handleRecordDeposit))
+	s.mux.HandleFunc("PUT /api/v1/leases/{id}/deposit/deductions", s.authenticated(s.handleProposeDeductions))
+	s.mux.HandleFunc("POST /api/v1/leases/{id}/deposit/acknowledge", s.authenticated(s.handleAcknowledgeDeductions))
+	s.mux.HandleFunc("POST /api/v1/leases/{id}/deposit/dispute", s.authenticated(s.handleDisputeDeductions))
+
+	// Maintenance tickets
+	s.mux.HandleFunc("POST /api/v1/leases/{id}/maintenance", s.authenticated(s.handleReportIssue))
+	s.mux.HandleFunc("GET /api/v1/maintenance/as-tenant", s.authenticated(s.handleTenantTickets))
+	s.mux.HandleFunc("GET /api/v1/maintenance/queue", s.authenticated(s.handleMaintenanceQueue))
+	s.mux.HandleFunc("GET /api/v1/maintenance/{id}", s.authenticated(s.handleGetTicket))
+	s.mux.HandleFunc("PUT /api/v1/maintenance/{id}/status", s.authenticated(s.handleUpdateTicketStatus))
+	s.mux.HandleFunc("POST /api/v1/maintenance/{id}/comments", s.authenticated(s.handleAddTicketComment))
+
+	// Property visits
+	s.mux.HandleFunc("POST /api/v1/properties/{id}/visits", s.authenticated(s.handlePublishSlots))
+	s.mux.HandleFunc("GET /api/v1/properties/{id}/visits", s.authenticated(s.handlePropertySlots))
+	s.mux.HandleFunc("GET /api/v1/visits/as-tenant", s.authenticated(s.handleTenantVisits))
+	s.mux.HandleFunc("GET /api/v1/visits/as-landlord", s.authenticated(s.handleLandlordVisits))
+	s.mux.HandleFunc("POST /api/v1/visits/{id}/book", s.authenticated(s.handleBookVisit))
+	s.mux.HandleFunc("POST /api/v1/visits/{id}/reschedule", s.authenticated(s.handleRescheduleVisit))
+	s.mux.HandleFunc("POST /api/v1/visits/{id}/cancel", s.authenticated(s.handleCancelVisit))
+
+	// Messages between tenants and landlords
+	s.mux.HandleFunc("POST /api/v1/properties/{id}/messages", s.authenticated(s.handleContactLandlord))
+	s.mux.HandleFunc("POST /api/v1/rent-requests/{id}/messages", s.authenticated(s.handleMessageAboutRequest))
+	s.mux.HandleFunc("GET /api/v1/threads", s.authenticated(s.handleThreads))
+	s.mux.HandleFunc("GET /api/v1/threads/unread", s.authenticated(s.handleUnreadMessages))
+	s.mux.HandleFunc("GET /api/v1/threads/{id}", s.authenticated(s.handleReadThread))
+	s.mux.HandleFunc("POST /api/v1/threads/{id}/messages", s.authenticated(s.handleSendMessage))
+
+	// Notification inbox of the logged in user
+	s.mux.HandleFunc("GET /api/v1/notifications", s.authenticated(s.handleNotifications))
+	s.mux.HandleFunc("DELETE /api/v1/notifications", s.authenticated(s.handleClearNotifications))
+	s.mux.HandleFunc("GET /api/v1/notifications/unread", s.authenticated(s.handleUnreadNotifications))
+	s.mux.HandleFunc("POST /api/v1/notifications/read", s.authenticated(s.handleMarkAllNotificationsRead))
+	s.mux.HandleFunc("POST /api/v1/notifications/{id}/read", s.authenticated(s.handleMarkNotificationRead))
+	s.mux.HandleFunc("DELETE /api/v1/notifications/{id}", s.authenticated(s.handleDeleteNotification))
+
+	// Documents
+	s.mux.HandleFunc("GET /api/v1/leases/{id}/agreement", s.authenticated(s.handleLeaseAgreement))
+	s.mux.HandleFunc("GET /api/v1/leases/{id}/payments/{paymentID}/receipt", s.authenticated(s.handleRentReceipt))
+
+	// Admin and moderation. The services check the permissions of the caller.
+	s.mux.HandleFunc("GET /api/v1/admin/users", s.authenticated(s.handleListUsers))
+	s.mux.HandleFunc("DELETE /api/v1/admin/users/{username}", s.authenticated(s.handleDeleteUser))
+	s.mux.HandleFunc("PUT /api/v1/admin/users/{username}/role", s.authenticated(s.handleSetRole))
+	s.mux.HandleFunc("GET /api/v1/admin/properties/pending", s.authenticated(s.handlePendingProperties))
+	s.mux.HandleFunc("POST /api/v1/admin/properties/{id}/approve", s.authenticated(s.handleApproveProperty))
+	s.mux.HandleFunc("POST /api/v1/admin/properties/{id}/reject", s.authenticated(s.handleRejectProperty))
+
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, codeNotFound, "no such endpoint")
+	})
+}
+
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	_, _ = w.Write(openAPIDocument)
+}
@@ -0,0 +1,215 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/domain/entities"
+	"rentease/pkg/utils"
+	"rentease/pkg/validation"
+)
+
+type signUpRequest struct {
+	Username    string `json:"username"`
+	Password    string `json:"password"`
+	Name        string `json:"name"`
+	Age         int    `json:"age"`
+	Email       string `json:"email"`
+	PhoneNumber string `json:"phone_number"`
+	Address     string `json:"address"`
+}
+
+// validate applies the same rules as the signup form of the terminal UI.
+func (req signUpRequest) validate() string {
+	switch {
+	case req.Username == "" || !validation.IsInputSpaceFree(req.Username):
+		return "username must be a single word"
+	case !validation.IsInputSpaceFree(req.Password) || !utils.IsValidPassword(req.Password):
+		return "password must be at least 9 characters and include lowercase, uppercase, numbers and special characters"
+	case strings.TrimSpace(req.Name) == "":
+		return "name must not be empty"
+	case req.Age < 18 || req.Age > 125:
+		return "age must be between 18 and 125"
+	case !validation.IsValidMobileNumber(req.PhoneNumber):
+		return "phone_number must be a 10-digit number starting with 6, 7, 8, or 9"
+	case !validation.IsValidEmail(req.Email):
+		return "email is not valid"
+	}
+	return ""
+}
+
+type loginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// tokenResponse is returned by login and token refresh. User is only set at login.
+type tokenResponse struct {
+	AccessToken      string         `json:"access_token"`
+	TokenType        string         `json:"token_type"`
+	ExpiresAt        time.Time      `json:"expires_at"`
+	RefreshToken     string         `json:"refresh_token"`
+	RefreshExpiresAt time.Time      `json:"refresh_expires_at"`
+	User             *entities.User `json:"user,omitempty"`
+}
+
+func newTokenResponse(tokens entities.TokenPair, user *entities.User) tokenResponse {
+	return tokenResponse{
+		AccessToken:      tokens.AccessToken,
+		TokenType:        "Bearer",
+		ExpiresAt:        tokens.AccessExpiresAt,
+		RefreshToken:     tokens.RefreshToken,
+		RefreshExpiresAt: tokens.RefreshExpiresAt,
+		User:             user,
+	}
+}
+
+type wishlistRequest struct {
+	PropertyID primitive.ObjectID `json:"property_id"`
+}
+
+func (s *Server) handleSignUp(w http.ResponseWriter, r *http.Request) {
+	var req signUpRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if problem := req.validate(); problem != "" {
+		writeError(w, http.StatusBadRequest, codeBadRequest, problem)
+		return
+	}
+
+	hashedPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	user := entities.User{
+		Username:     req.Username,
+		PasswordHash: hashedPassword,
+		Name:         req.Name,
+		Age:          req.Age,
+		Email:        req.Email,
+		PhoneNumber:  req.PhoneNumber,
+		Address:      req.Address,
+		Role:         "User",
+		Wishlist:     []primitive.ObjectID{},
+	}
+	if err := s.userService.SignUp(r.Context(), user); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, user)
+}
+
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	var req loginRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	session, err := s.userService.Login(r.Context(), req.Username, req.Password)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	tokens, err := s.tokenService.Issue(r.Context(), session)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newTokenResponse(tokens, &session.User))
+}
+
+func (s *Server) handleRefreshToken(w http.ResponseWriter, r *http.Request) {
+	var req refreshRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if req.RefreshToken == "" {
+		writeError(w, http.StatusBadRequest, codeBadRequest, "refresh_token must not be empty")
+		return
+	}
+
+	tokens, err := s.tokenService.Refresh(r.Context(), req.RefreshToken)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newTokenResponse(tokens, nil))
+}
+
+// handleLogout revokes the tokens of the calling login.
+func (s *Server) handleLogout(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	if err := s.tokenService.Revoke(r.Context(), session); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleLogoutAll revokes the tokens of every login of the calling user.
+func (s *Server) handleLogoutAll(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	if err := s.tokenService.RevokeAll(r.Context(), session.Username()); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleMe(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	user, err := s.userService.FindByUsername(r.Context(), session.Username())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if user.Username == "" {
+		writeError(w, http.StatusNotFound, codeNotFound, "user not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (s *Server) handleGetWishlist(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	user, err := s.userService.FindByUsername(r.Context(), session.Username())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	properties := []entities.Property{}
+	for _, propertyID := range user.Wishlist {
+		property, err := s.propertyService.FindByID(r.Context(), propertyID)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		// Properties deleted since they were added are left out
+		if !property.ID.IsZero() {
+			properties = append(properties, property)
+		}
+	}
+	writeJSON(w, http.StatusOK, properties)
+}
+
+func (s *Server) handleAddToWishlist(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	var req wishlistRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if _, ok := s.findProperty(w, r, req.PropertyID); !ok {
+		return
+	}
+
+	if err := s.userService.AddToWishlist(r.Context(), session, req.PropertyID); err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/domain/entities"
+)
+
+type publishSlotsRequest struct {
+	Starts        []time.Time `json:"starts"`
+	LengthMinutes int         `json:"length_minutes"` // 0 for the default length
+}
+
+type rescheduleVisitRequest struct {
+	ToSlotID primitive.ObjectID `json:"to_slot_id"`
+}
+
+// handlePublishSlots lets the landlord of a property offer times for visiting it.
+func (s *Server) handlePublishSlots(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	var req publishSlotsRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	slots, err := s.visitService.PublishSlots(r.Context(), session, id, req.Starts, time.Duration(req.LengthMinutes)*time.Minute)
+	writeSlots(w, http.StatusCreated, slots, err)
+}
+
+// handlePropertySlots lists the slots of a property: all of them for its landlord, the bookable ones for others.
+func (s *Server) handlePropertySlots(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	slots, err := s.visitService.VisitSlots(r.Context(), session, id)
+	writeSlots(w, http.StatusOK, slots, err)
+}
+
+// handleTenantVisits lists the upcoming visits the logged in tenant booked.
+func (s *Server) handleTenantVisits(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	slots, err := s.visitService.UpcomingVisitsForTenant(r.Context(), session)
+	writeSlots(w, http.StatusOK, slots, err)
+}
+
+// handleLandlordVisits lists the upcoming visits to the logged in landlord's properties.
+func (s *Server) handleLandlordVisits(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	slots, err := s.visitService.UpcomingVisitsForLandlord(r.Context(), session)
+	writeSlots(w, http.StatusOK, slots, err)
+}
+
+// handleBookVisit books an open slot for the logged in tenant.
+func (s *Server) handleBookVisit(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	slot, err := s.visitService.BookVisit(r.Context(), session, id)
+	writeSlot(w, slot, err)
+}
+
+// handleRescheduleVisit moves a booked visit to another slot of the same property.
+func (s *Server) handleRescheduleVisit(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	var req rescheduleVisitRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	slot, err := s.visitService.RescheduleVisit(r.Context(), session, id, req.ToSlotID)
+	writeSlot(w, slot, err)
+}
+
+// handleCancelVisit calls off a visit, or withdraws a slot when the landlord cancels it.
+func (s *Server) handleCancelVisit(w http.ResponseWriter, r *http.Request, session *entities.Session) {
+	id, ok := pathObjectID(w, r, "id")
+	if !ok {
+		return
+	}
+	slot, err := s.visitService.CancelVisit(r.Context(), session, id)
+	writeSlot(w, slot, err)
+}
+
+func writeSlot(w http.ResponseWriter, slot entities.VisitSlot, err error) {
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, slot)
+}
+
+func writeSlots(w http.ResponseWriter, status int, slots []entities.VisitSlot, err error) {
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if slots == nil {
+		slots = []entities.VisitSlot{}
+	}
+	writeJSON(w, status, slots)
+}
@@ -0,0 +1,120 @@
+// Package eventbus delivers the events published by the services to the subscribers of their kind.
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"rentease/internal/domain/events"
+)
+
+// Handler handles an event it subscribed to.
+type Handler func(ctx context.Context, event events.Event) error
+
+// Bus delivers every published event to its subscribers, one after the other in the order they
+// subscribed, before Publish returns. A failing subscriber does not keep the event from the others.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+	all      []Handler
+}
+
+func New() *Bus {
+	return &Bus{handlers: make(map[string][]Handler)}
+}
+
+// Subscribe has handle called with every event of type E.
+func Subscribe[E events.Event](b *Bus, handle func(ctx context.Context, event E) error) {
+	var zero E
+	name := zero.EventName()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[name] = append(b.handlers[name], func(ctx context.Context, event events.Event) error {
+		typed, ok := event.(E)
+		if !ok {
+			return fmt.Errorf("event %s is a %T, not a %T", name, event, zero)
+		}
+		return handle(ctx, typed)
+	})
+}
+
+// SubscribeAll has handle called with every event, after the subscribers of its kind.
+func (b *Bus) SubscribeAll(handle Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.all = append(b.all, handle)
+}
+
+// Publish hands the event to its subscribers and returns their errors joined.
+func (b *Bus) Publish(ctx context.Context, event events.Event) error {
+	b.mu.RLock()
+	handlers := append(append([]Handler(nil), b.handlers[event.EventName()]...), b.all...)
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, handle := range handlers {
+		if err := handle(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", event.EventName(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// AuditLog writes every event it is given as a line of JSON, with the time it was handled.
+type AuditLog struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewAuditLog(w io.Writer) *AuditLog {
+	return &AuditLog{w: w}
+}
+
+// OpenAuditLog opens the audit log kept in the file at path, appending to what it already holds.
+func OpenAuditLog(path string) (*AuditLog, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log %s: %w", path, err)
+	}
+	return NewAuditLog(file), nil
+}
+
+// Close closes the file the log is written to, if it was opened by OpenAuditLog.
+func (a *AuditLog) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if closer, ok := a.w.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// auditEntry is one line of the audit log.
+type auditEntry struct {
+	At    time.Time    `json:"at"`
+	Event string       `json:"event"`
+	Data  events.Event `json:"data"`
+}
+
+// Record writes the event to the log. It is a Handler, to be subscribed to every event. The change the
+// event tells about has already been made, so failing to write it is logged rather than returned.
+func (a *AuditLog) Record(ctx context.Context, event events.Event) error {
+	line, err := json.Marshal(auditEntry{At: time.Now().UTC(), Event: event.EventName(), Data: event})
+	if err == nil {
+		a.mu.Lock()
+		_, err = a.w.Write(append(line, '\n'))
+		a.mu.Unlock()
+	}
+	if err != nil {
+		log.Printf("failed to audit %s: %v", event.EventName(), err)
+	}
+	return nil
+}
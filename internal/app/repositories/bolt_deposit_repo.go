@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+// BoltDepositRepo is a DepositRepo stored in an embedded BoltDB file.
+// Deposits are keyed by the ID of their lease, so a lease has at most one deposit.
+type BoltDepositRepo struct {
+	db *bbolt.DB
+}
+
+// NewBoltDepositRepo initializes a DepositRepo on a database opened with OpenBoltDB.
+func NewBoltDepositRepo(db *bbolt.DB) interfaces.DepositRepo {
+	return &BoltDepositRepo{db: db}
+}
+
+// CreateDeposit saves the deposit, assigning a new ID when it has none, unless its lease already has one.
+func (repo *BoltDepositRepo) CreateDeposit(ctx context.Context, deposit entities.Deposit) (bool, error) {
+	if deposit.ID.IsZero() {
+		deposit.ID = primitive.NewObjectID()
+	}
+	created := false
+	err := boltUpdate(ctx, repo.db, func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(boltDepositsBucket))
+		if bucket.Get(deposit.LeaseID[:]) != nil {
+			return nil
+		}
+		created = true
+		return putBoltDeposit(bucket, deposit)
+	})
+	if err != nil {
+		return false, err
+	}
+	return created, nil
+}
+
+// FindDepositByLease returns the deposit of the lease, or nil if none was recorded.
+func (repo *BoltDepositRepo) FindDepositByLease(ctx context.Context, leaseID primitive.ObjectID) (*entities.Deposit, error) {
+	var deposit *entities.Deposit
+	err := boltView(ctx, repo.db, func(tx *bbolt.Tx) error {
+		data := tx.Bucket([]byte(boltDepositsBucket)).Get(leaseID[:])
+		if data == nil {
+			return nil
+		}
+		var found entities.Deposit
+		if err := bson.Unmarshal(data, &found); err != nil {
+			return fmt.Errorf("failed to decode deposit of lease %s: %w", leaseID.Hex(), err)
+		}
+		deposit = &found
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deposit, nil
+}
+
+// UpdateDeposit replaces the deposit if the stored one is still in from.
+func (repo *BoltDepositRepo) UpdateDeposit(ctx context.Context, deposit entities.Deposit, from entities.DepositStatus) (bool, error) {
+	updated := false
+	err := boltUpdate(ctx, repo.db, func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(boltDepositsBucket))
+		data := bucket.Get(deposit.LeaseID[:])
+		if data == nil {
+			return nil
+		}
+
+		var stored entities.Deposit
+		if err := bson.Unmarshal(data, &stored); err != nil {
+			return fmt.Errorf("failed to decode deposit of lease %s: %w", deposit.LeaseID.Hex(), err)
+		}
+		if stored.ID != deposit.ID || stored.Status != from {
+			return nil
+		}
+		updated = true
+		return putBoltDeposit(bucket, deposit)
+	})
+	if err != nil {
+		return false, err
+	}
+	return updated, nil
+}
+
+// putBoltDeposit writes the deposit under its lease, replacing any existing entry.
+func putBoltDeposit(bucket *bbolt.Bucket, deposit entities.Deposit) error {
+	data, err := bson.Marshal(deposit)
+	if err != nil {
+		return fmt.Errorf("failed to encode deposit %s: %w", deposit.ID.Hex(), err)
+	}
+	return bucket.Put(deposit.LeaseID[:], data)
+}
@@ -0,0 +1,167 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+// BoltLeaseRepo is a LeaseRepo stored in an embedded BoltDB file.
+// Leases are BSON encoded and keyed by their ObjectID.
+type BoltLeaseRepo struct {
+	db *bbolt.DB
+}
+
+// NewBoltLeaseRepo initializes a LeaseRepo on a database opened with OpenBoltDB.
+func NewBoltLeaseRepo(db *bbolt.DB) interfaces.LeaseRepo {
+	return &BoltLeaseRepo{db: db}
+}
+
+// SaveLease saves the lease, assigning a new ID when it has none.
+func (repo *BoltLeaseRepo) SaveLease(ctx context.Context, lease entities.Lease) error {
+	if lease.ID.IsZero() {
+		lease.ID = primitive.NewObjectID()
+	}
+	return boltUpdate(ctx, repo.db, func(tx *bbolt.Tx) error {
+		return putBoltLease(tx.Bucket([]byte(boltLeasesBucket)), lease)
+	})
+}
+
+// FindLeaseByID returns the lease, or nil if there is none with the ID.
+func (repo *BoltLeaseRepo) FindLeaseByID(ctx context.Context, id primitive.ObjectID) (*entities.Lease, error) {
+	var lease *entities.Lease
+	err := boltView(ctx, repo.db, func(tx *bbolt.Tx) error {
+		data := tx.Bucket([]byte(boltLeasesBucket)).Get(id[:])
+		if data == nil {
+			return nil
+		}
+		lease = &entities.Lease{}
+		if err := bson.Unmarshal(data, lease); err != nil {
+			return fmt.Errorf("failed to decode lease %s: %w", id.Hex(), err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lease, nil
+}
+
+// FindLeaseByRequestID returns the lease drawn up for the request, or nil if there is none.
+func (repo *BoltLeaseRepo) FindLeaseByRequestID(ctx context.Context, requestID primitive.ObjectID) (*entities.Lease, error) {
+	leases, err := repo.filter(ctx, func(lease entities.Lease) bool {
+		return lease.RequestID == requestID
+	})
+	if err != nil || len(leases) == 0 {
+		return nil, err
+	}
+	return &leases[0], nil
+}
+
+func (repo *BoltLeaseRepo) FindLeasesByTenant(ctx context.Context, tenantName string) ([]entities.Lease, error) {
+	return repo.filter(ctx, func(lease entities.Lease) bool {
+		return lease.TenantName == tenantName
+	})
+}
+
+func (repo *BoltLeaseRepo) FindLeasesByLandlord(ctx context.Context, landlordName string) ([]entities.Lease, error) {
+	return repo.filter(ctx, func(lease entities.Lease) bool {
+		return lease.LandlordName == landlordName
+	})
+}
+
+func (repo *BoltLeaseRepo) FindLeasesByStatus(ctx context.Context, status entities.LeaseStatus) ([]entities.Lease, error) {
+	return repo.filter(ctx, func(lease entities.Lease) bool {
+		return lease.Status == status
+	})
+}
+
+// UpdateLeaseStatus changes the status if the stored lease is still in from.
+// Bolt runs one update transaction at a time, so the check and the write cannot interleave with another change.
+func (repo *BoltLeaseRepo) UpdateLeaseStatus(ctx context.Context, id primitive.ObjectID, from, to entities.LeaseStatus) (bool, error) {
+	updated := false
+	err := boltUpdate(ctx, repo.db, func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(boltLeasesBucket))
+		data := bucket.Get(id[:])
+		if data == nil {
+			return nil
+		}
+
+		var stored entities.Lease
+		if err := bson.Unmarshal(data, &stored); err != nil {
+			return fmt.Errorf("failed to decode lease %s: %w", id.Hex(), err)
+		}
+		if stored.Status != from {
+			return nil
+		}
+		stored.Status = to
+		updated = true
+		return putBoltLease(bucket, stored)
+	})
+	if err != nil {
+		return false, err
+	}
+	return updated, nil
+}
+
+// UpdateLease replaces the lease if the stored one is still in from, in the same way as UpdateLeaseStatus.
+func (repo *BoltLeaseRepo) UpdateLease(ctx context.Context, lease entities.Lease, from entities.LeaseStatus) (bool, error) {
+	updated := false
+	err := boltUpdate(ctx, repo.db, func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(boltLeasesBucket))
+		data := bucket.Get(lease.ID[:])
+		if data == nil {
+			return nil
+		}
+
+		var stored entities.Lease
+		if err := bson.Unmarshal(data, &stored); err != nil {
+			return fmt.Errorf("failed to decode lease %s: %w", lease.ID.Hex(), err)
+		}
+		if stored.Status != from {
+			return nil
+		}
+		updated = true
+		return putBoltLease(bucket, lease)
+	})
+	if err != nil {
+		return false, err
+	}
+	return updated, nil
+}
+
+// filter returns all leases matching the predicate, ordered by ID (and so by creation).
+func (repo *BoltLeaseRepo) filter(ctx context.Context, match func(entities.Lease) bool) ([]entities.Lease, error) {
+	var leases []entities.Lease
+	err := boltView(ctx, repo.db, func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(boltLeasesBucket)).ForEach(func(key, data []byte) error {
+			var lease entities.Lease
+			if err := bson.Unmarshal(data, &lease); err != nil {
+				return fmt.Errorf("failed to decode lease: %w", err)
+			}
+			if match(lease) {
+				leases = append(leases, lease)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return leases, nil
+}
+
+// putBoltLease writes the lease under its ID, replacing any existing entry.
+func putBoltLease(bucket *bbolt.Bucket, lease entities.Lease) error {
+	data, err := bson.Marshal(lease)
+	if err != nil {
+		return fmt.Errorf("failed to encode lease %s: %w", lease.ID.Hex(), err)
+	}
+	return bucket.Put(lease.ID[:], data)
+}
@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+// BoltLedgerRepo is a LedgerRepo stored in an embedded BoltDB file.
+// Dues are keyed by the ID of their lease followed by the period, so a lease has at most one due per period
+// and its dues are stored next to each other. Payments are keyed by their ObjectID.
+type BoltLedgerRepo struct {
+	db *bbolt.DB
+}
+
+// NewBoltLedgerRepo initializes a LedgerRepo on a database opened with OpenBoltDB.
+func NewBoltLedgerRepo(db *bbolt.DB) interfaces.LedgerRepo {
+	return &BoltLedgerRepo{db: db}
+}
+
+// CreateDue saves the due, assigning a new ID when it has none, unless the lease already has one for the period.
+func (repo *BoltLedgerRepo) CreateDue(ctx context.Context, due entities.RentDue) (bool, error) {
+	if due.ID.IsZero() {
+		due.ID = primitive.NewObjectID()
+	}
+	created := false
+	err := boltUpdate(ctx, repo.db, func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(boltRentDuesBucket))
+		if bucket.Get(boltDueKey(due.LeaseID, due.Period)) != nil {
+			return nil
+		}
+		created = true
+		return putBoltDue(bucket, due)
+	})
+	if err != nil {
+		return false, err
+	}
+	return created, nil
+}
+
+// FindDuesByLease returns the dues of the lease ordered by period.
+func (repo *BoltLedgerRepo) FindDuesByLease(ctx context.Context, leaseID primitive.ObjectID) ([]entities.RentDue, error) {
+	var dues []entities.RentDue
+	err := boltView(ctx, repo.db, func(tx *bbolt.Tx) error {
+		cursor := tx.Bucket([]byte(boltRentDuesBucket)).Cursor()
+		prefix := leaseID[:]
+		for key, data := cursor.Seek(prefix); key != nil && len(key) > len(prefix) && string(key[:len(prefix)]) == string(prefix); key, data = cursor.Next() {
+			var due entities.RentDue
+			if err := bson.Unmarshal(data, &due); err != nil {
+				return fmt.Errorf("failed to decode rent due: %w", err)
+			}
+			dues = append(dues, due)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dues, nil
+}
+
+// SavePayment stores the payment, assigning a new ID when it has none.
+func (repo *BoltLedgerRepo) SavePayment(ctx context.Context, payment entities.Payment) error {
+	if payment.ID.IsZero() {
+		payment.ID = primitive.NewObjectID()
+	}
+	return boltUpdate(ctx, repo.db, func(tx *bbolt.Tx) error {
+		return putBoltPayment(tx.Bucket([]byte(boltPaymentsBucket)), payment)
+	})
+}
+
+// FindPaymentsByLease returns the payments of the lease ordered by the day they were made.
+func (repo *BoltLedgerRepo) FindPaymentsByLease(ctx context.Context, leaseID primitive.ObjectID) ([]entities.Payment, error) {
+	var payments []entities.Payment
+	err := boltView(ctx, repo.db, func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(boltPaymentsBucket)).ForEach(func(key, data []byte) error {
+			var payment entities.Payment
+			if err := bson.Unmarshal(data, &payment); err != nil {
+				return fmt.Errorf("failed to decode payment: %w", err)
+			}
+			if payment.LeaseID == leaseID {
+				payments = append(payments, payment)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(payments, func(i, j int) bool { return payments[i].PaidOn.Before(payments[j].PaidOn) })
+	return payments, nil
+}
+
+// boltDueKey is the key of the due of the lease for the period. The big-endian period keeps the dues
+// of a lease in order.
+func boltDueKey(leaseID primitive.ObjectID, period int) []byte {
+	key := make([]byte, len(leaseID)+4)
+	copy(key, leaseID[:])
+	binary.BigEndian.PutUint32(key[len(leaseID):], uint32(period))
+	return key
+}
+
+// putBoltDue writes the due under its lease and period, replacing any existing entry.
+func putBoltDue(bucket *bbolt.Bucket, due entities.RentDue) error {
+	data, err := bson.Marshal(due)
+	if err != nil {
+		return fmt.Errorf("failed to encode rent due %s: %w", due.ID.Hex(), err)
+	}
+	return bucket.Put(boltDueKey(due.LeaseID, due.Period), data)
+}
+
+// putBoltPayment writes the payment under its ID, replacing any existing entry.
+func putBoltPayment(bucket *bbolt.Bucket, payment entities.Payment) error {
+	data, err := bson.Marshal(payment)
+	if err != nil {
+		return fmt.Errorf("failed to encode payment %s: %w", payment.ID.Hex(), err)
+	}
+	return bucket.Put(payment.ID[:], data)
+}
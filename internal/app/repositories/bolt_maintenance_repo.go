@@ -0,0 +1,148 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+// BoltMaintenanceRepo is a MaintenanceRepo stored in an embedded BoltDB file.
+// Tickets are BSON encoded and keyed by their ObjectID.
+type BoltMaintenanceRepo struct {
+	db *bbolt.DB
+}
+
+// NewBoltMaintenanceRepo initializes a MaintenanceRepo on a database opened with OpenBoltDB.
+func NewBoltMaintenanceRepo(db *bbolt.DB) interfaces.MaintenanceRepo {
+	return &BoltMaintenanceRepo{db: db}
+}
+
+// SaveTicket saves the ticket, assigning a new ID when it has none.
+func (repo *BoltMaintenanceRepo) SaveTicket(ctx context.Context, ticket entities.MaintenanceTicket) error {
+	if ticket.ID.IsZero() {
+		ticket.ID = primitive.NewObjectID()
+	}
+	return boltUpdate(ctx, repo.db, func(tx *bbolt.Tx) error {
+		return putBoltTicket(tx.Bucket([]byte(boltMaintenanceBucket)), ticket)
+	})
+}
+
+// FindTicketByID returns the ticket, or nil if there is none with the ID.
+func (repo *BoltMaintenanceRepo) FindTicketByID(ctx context.Context, id primitive.ObjectID) (*entities.MaintenanceTicket, error) {
+	var ticket *entities.MaintenanceTicket
+	err := boltView(ctx, repo.db, func(tx *bbolt.Tx) error {
+		data := tx.Bucket([]byte(boltMaintenanceBucket)).Get(id[:])
+		if data == nil {
+			return nil
+		}
+		ticket = &entities.MaintenanceTicket{}
+		if err := bson.Unmarshal(data, ticket); err != nil {
+			return fmt.Errorf("failed to decode maintenance ticket %s: %w", id.Hex(), err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+func (repo *BoltMaintenanceRepo) FindTicketsByTenant(ctx context.Context, tenantName string) ([]entities.MaintenanceTicket, error) {
+	return repo.filter(ctx, func(ticket entities.MaintenanceTicket) bool {
+		return ticket.TenantName == tenantName
+	})
+}
+
+func (repo *BoltMaintenanceRepo) FindTicketsByLandlord(ctx context.Context, landlordName string) ([]entities.MaintenanceTicket, error) {
+	return repo.filter(ctx, func(ticket entities.MaintenanceTicket) bool {
+		return ticket.LandlordName == landlordName
+	})
+}
+
+// UpdateTicketStatus changes the status if the stored ticket is still in from.
+// Bolt runs one update transaction at a time, so the check and the write cannot interleave with another change.
+func (repo *BoltMaintenanceRepo) UpdateTicketStatus(ctx context.Context, id primitive.ObjectID, from, to entities.TicketStatus, at time.Time, note *entities.TicketComment) (bool, error) {
+	return repo.update(ctx, id, func(ticket *entities.MaintenanceTicket) bool {
+		if ticket.Status != from {
+			return false
+		}
+		changeTicketStatus(ticket, to, at, note)
+		return true
+	})
+}
+
+// AddTicketComment appends the comment unless the ticket is closed.
+func (repo *BoltMaintenanceRepo) AddTicketComment(ctx context.Context, id primitive.ObjectID, comment entities.TicketComment) (bool, error) {
+	return repo.update(ctx, id, func(ticket *entities.MaintenanceTicket) bool {
+		if ticket.Status == entities.TicketClosed {
+			return false
+		}
+		ticket.Comments = append(ticket.Comments, comment)
+		ticket.UpdatedAt = comment.PostedAt
+		return true
+	})
+}
+
+// update reads the ticket, lets change modify it, and writes it back if change reports it did.
+func (repo *BoltMaintenanceRepo) update(ctx context.Context, id primitive.ObjectID, change func(*entities.MaintenanceTicket) bool) (bool, error) {
+	updated := false
+	err := boltUpdate(ctx, repo.db, func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(boltMaintenanceBucket))
+		data := bucket.Get(id[:])
+		if data == nil {
+			return nil
+		}
+
+		var stored entities.MaintenanceTicket
+		if err := bson.Unmarshal(data, &stored); err != nil {
+			return fmt.Errorf("failed to decode maintenance ticket %s: %w", id.Hex(), err)
+		}
+		if !change(&stored) {
+			return nil
+		}
+		updated = true
+		return putBoltTicket(bucket, stored)
+	})
+	if err != nil {
+		return false, err
+	}
+	return updated, nil
+}
+
+// filter returns all tickets matching the predicate, ordered by when they were reported.
+func (repo *BoltMaintenanceRepo) filter(ctx context.Context, match func(entities.MaintenanceTicket) bool) ([]entities.MaintenanceTicket, error) {
+	var tickets []entities.MaintenanceTicket
+	err := boltView(ctx, repo.db, func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(boltMaintenanceBucket)).ForEach(func(key, data []byte) error {
+			var ticket entities.MaintenanceTicket
+			if err := bson.Unmarshal(data, &ticket); err != nil {
+				return fmt.Errorf("failed to decode maintenance ticket: %w", err)
+			}
+			if match(ticket) {
+				tickets = append(tickets, ticket)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortTickets(tickets)
+	return tickets, nil
+}
+
+// putBoltTicket writes the ticket under its ID, replacing any existing entry.
+func putBoltTicket(bucket *bbolt.Bucket, ticket entities.MaintenanceTicket) error {
+	data, err := bson.Marshal(ticket)
+	if err != nil {
+		return fmt.Errorf("failed to encode maintenance ticket %s: %w", ticket.ID.Hex(), err)
+	}
+	return bucket.Put(ticket.ID[:], data)
+}
@@ -0,0 +1,166 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+// BoltMessageRepo is a MessageRepo stored in an embedded BoltDB file.
+// Threads are BSON encoded, messages included, and keyed by their ObjectID.
+type BoltMessageRepo struct {
+	db *bbolt.DB
+}
+
+// NewBoltMessageRepo initializes a MessageRepo on a database opened with OpenBoltDB.
+func NewBoltMessageRepo(db *bbolt.DB) interfaces.MessageRepo {
+	return &BoltMessageRepo{db: db}
+}
+
+// CreateThread saves the thread, assigning a new ID when it has none, unless the tenant already has a
+// thread about the property. The check and the write run in one update transaction.
+func (repo *BoltMessageRepo) CreateThread(ctx context.Context, thread entities.Thread) (bool, error) {
+	if thread.ID.IsZero() {
+		thread.ID = primitive.NewObjectID()
+	}
+	created := false
+	err := boltUpdate(ctx, repo.db, func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(boltMessagesBucket))
+		exists := false
+		err := forEachBoltThread(bucket, func(stored entities.Thread) {
+			if stored.PropertyID == thread.PropertyID && stored.TenantName == thread.TenantName {
+				exists = true
+			}
+		})
+		if err != nil || exists {
+			return err
+		}
+		created = true
+		return putBoltThread(bucket, thread)
+	})
+	if err != nil {
+		return false, err
+	}
+	return created, nil
+}
+
+// FindThreadByID returns the thread, or nil if there is none with the ID.
+func (repo *BoltMessageRepo) FindThreadByID(ctx context.Context, id primitive.ObjectID) (*entities.Thread, error) {
+	var thread *entities.Thread
+	err := boltView(ctx, repo.db, func(tx *bbolt.Tx) error {
+		data := tx.Bucket([]byte(boltMessagesBucket)).Get(id[:])
+		if data == nil {
+			return nil
+		}
+		thread = &entities.Thread{}
+		if err := bson.Unmarshal(data, thread); err != nil {
+			return fmt.Errorf("failed to decode message thread %s: %w", id.Hex(), err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return thread, nil
+}
+
+// FindThread returns the tenant's thread about the property, or nil if they have none.
+func (repo *BoltMessageRepo) FindThread(ctx context.Context, propertyID primitive.ObjectID, tenantName string) (*entities.Thread, error) {
+	threads, err := repo.filter(ctx, func(thread entities.Thread) bool {
+		return thread.PropertyID == propertyID && thread.TenantName == tenantName
+	})
+	if err != nil || len(threads) == 0 {
+		return nil, err
+	}
+	return &threads[0], nil
+}
+
+func (repo *BoltMessageRepo) FindThreadsByUser(ctx context.Context, username string) ([]entities.Thread, error) {
+	return repo.filter(ctx, func(thread entities.Thread) bool {
+		return thread.HasParty(username)
+	})
+}
+
+// AddMessage appends the message if its sender is a party to the thread.
+func (repo *BoltMessageRepo) AddMessage(ctx context.Context, threadID primitive.ObjectID, message entities.Message) (bool, error) {
+	return repo.update(ctx, threadID, func(thread *entities.Thread) bool {
+		return addMessage(thread, message)
+	})
+}
+
+// MarkThreadRead clears the unread count of the user if they are a party to the thread.
+func (repo *BoltMessageRepo) MarkThreadRead(ctx context.Context, threadID primitive.ObjectID, username string) (bool, error) {
+	return repo.update(ctx, threadID, func(thread *entities.Thread) bool {
+		return markThreadRead(thread, username)
+	})
+}
+
+// update reads the thread, lets change modify it, and writes it back if change reports it did.
+func (repo *BoltMessageRepo) update(ctx context.Context, id primitive.ObjectID, change func(*entities.Thread) bool) (bool, error) {
+	updated := false
+	err := boltUpdate(ctx, repo.db, func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(boltMessagesBucket))
+		data := bucket.Get(id[:])
+		if data == nil {
+			return nil
+		}
+
+		var stored entities.Thread
+		if err := bson.Unmarshal(data, &stored); err != nil {
+			return fmt.Errorf("failed to decode message thread %s: %w", id.Hex(), err)
+		}
+		if !change(&stored) {
+			return nil
+		}
+		updated = true
+		return putBoltThread(bucket, stored)
+	})
+	if err != nil {
+		return false, err
+	}
+	return updated, nil
+}
+
+// filter returns all threads matching the predicate, latest message first.
+func (repo *BoltMessageRepo) filter(ctx context.Context, match func(entities.Thread) bool) ([]entities.Thread, error) {
+	var threads []entities.Thread
+	err := boltView(ctx, repo.db, func(tx *bbolt.Tx) error {
+		return forEachBoltThread(tx.Bucket([]byte(boltMessagesBucket)), func(thread entities.Thread) {
+			if match(thread) {
+				threads = append(threads, thread)
+			}
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortThreads(threads)
+	return threads, nil
+}
+
+// forEachBoltThread decodes every thread in the bucket and passes it to fn.
+func forEachBoltThread(bucket *bbolt.Bucket, fn func(entities.Thread)) error {
+	return bucket.ForEach(func(key, data []byte) error {
+		var thread entities.Thread
+		if err := bson.Unmarshal(data, &thread); err != nil {
+			return fmt.Errorf("failed to decode message thread: %w", err)
+		}
+		fn(thread)
+		return nil
+	})
+}
+
+// putBoltThread writes the thread under its ID, replacing any existing entry.
+func putBoltThread(bucket *bbolt.Bucket, thread entities.Thread) error {
+	data, err := bson.Marshal(thread)
+	if err != nil {
+		return fmt.Errorf("failed to encode message thread %s: %w", thread.ID.Hex(), err)
+	}
+	return bucket.Put(thread.ID[:], data)
+}
@@ -0,0 +1,209 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"rentease/internal/domain/entities"
+)
+
+// MongoSource identifies the MongoDB database and collections to migrate from.
+type MongoSource struct {
+	Database               string
+	UserCollection         string
+	PropertyCollection     string
+	RentRequestCollection  string
+	LeaseCollection        string
+	RentDueCollection      string
+	PaymentCollection      string
+	DepositCollection      string
+	MaintenanceCollection  string
+	VisitCollection        string
+	MessageCollection      string
+	NotificationCollection string
+}
+
+// MigrationResult reports how many documents of each kind were copied.
+type MigrationResult struct {
+	Users         int
+	Properties    int
+	RentRequests  int
+	Leases        int
+	RentDues      int
+	Payments      int
+	Deposits      int
+	Tickets       int
+	VisitSlots    int
+	Threads       int
+	Notifications int
+}
+
+// MigrateMongoToBolt copies all users, properties, rent requests, leases, rent dues, payments, deposits, maintenance tickets, visit slots, message threads and notifications from MongoDB into the BoltDB file.
+// Everything is written in a single transaction, so a failed migration leaves the file untouched.
+// Existing entries with the same key are overwritten, which makes it safe to run the migration again.
+func MigrateMongoToBolt(ctx context.Context, client *mongo.Client, source MongoSource, db *bbolt.DB) (MigrationResult, error) {
+	var result MigrationResult
+	database := client.Database(source.Database)
+
+	err := db.Update(func(tx *bbolt.Tx) error {
+		var err error
+		users := tx.Bucket([]byte(boltUsersBucket))
+		result.Users, err = migrateCollection(ctx, database.Collection(source.UserCollection), func(raw bson.Raw) error {
+			var user entities.User
+			if err := bson.Unmarshal(raw, &user); err != nil {
+				return fmt.Errorf("failed to decode user: %w", err)
+			}
+			return putBoltUser(users, user)
+		})
+		if err != nil {
+			return err
+		}
+
+		properties := tx.Bucket([]byte(boltPropertiesBucket))
+		result.Properties, err = migrateCollection(ctx, database.Collection(source.PropertyCollection), func(raw bson.Raw) error {
+			property, err := decodeProperty(raw)
+			if err != nil {
+				return err
+			}
+			return putBoltProperty(properties, property)
+		})
+		if err != nil {
+			return err
+		}
+
+		requests := tx.Bucket([]byte(boltRentRequestsBucket))
+		result.RentRequests, err = migrateCollection(ctx, database.Collection(source.RentRequestCollection), func(raw bson.Raw) error {
+			var request entities.Request
+			if err := bson.Unmarshal(raw, &request); err != nil {
+				return fmt.Errorf("failed to decode rent request: %w", err)
+			}
+			return putBoltRequest(requests, request)
+		})
+		if err != nil {
+			return err
+		}
+
+		leases := tx.Bucket([]byte(boltLeasesBucket))
+		result.Leases, err = migrateCollection(ctx, database.Collection(source.LeaseCollection), func(raw bson.Raw) error {
+			var lease entities.Lease
+			if err := bson.Unmarshal(raw, &lease); err != nil {
+				return fmt.Errorf("failed to decode lease: %w", err)
+			}
+			return putBoltLease(leases, lease)
+		})
+		if err != nil {
+			return err
+		}
+
+		dues := tx.Bucket([]byte(boltRentDuesBucket))
+		result.RentDues, err = migrateCollection(ctx, database.Collection(source.RentDueCollection), func(raw bson.Raw) error {
+			var due entities.RentDue
+			if err := bson.Unmarshal(raw, &due); err != nil {
+				return fmt.Errorf("failed to decode rent due: %w", err)
+			}
+			return putBoltDue(dues, due)
+		})
+		if err != nil {
+			return err
+		}
+
+		payments := tx.Bucket([]byte(boltPaymentsBucket))
+		result.Payments, err = migrateCollection(ctx, database.Collection(source.PaymentCollection), func(raw bson.Raw) error {
+			var payment entities.Payment
+			if err := bson.Unmarshal(raw, &payment); err != nil {
+				return fmt.Errorf("failed to decode payment: %w", err)
+			}
+			return putBoltPayment(payments, payment)
+		})
+		if err != nil {
+			return err
+		}
+
+		deposits := tx.Bucket([]byte(boltDepositsBucket))
+		result.Deposits, err = migrateCollection(ctx, database.Collection(source.DepositCollection), func(raw bson.Raw) error {
+			var deposit entities.Deposit
+			if err := bson.Unmarshal(raw, &deposit); err != nil {
+				return fmt.Errorf("failed to decode deposit: %w", err)
+			}
+			return putBoltDeposit(deposits, deposit)
+		})
+		if err != nil {
+			return err
+		}
+
+		tickets := tx.Bucket([]byte(boltMaintenanceBucket))
+		result.Tickets, err = migrateCollection(ctx, database.Collection(source.MaintenanceCollection), func(raw bson.Raw) error {
+			var ticket entities.MaintenanceTicket
+			if err := bson.Unmarshal(raw, &ticket); err != nil {
+				return fmt.Errorf("failed to decode maintenance ticket: %w", err)
+			}
+			return putBoltTicket(tickets, ticket)
+		})
+		if err != nil {
+			return err
+		}
+
+		slots := tx.Bucket([]byte(boltVisitBucket))
+		result.VisitSlots, err = migrateCollection(ctx, database.Collection(source.VisitCollection), func(raw bson.Raw) error {
+			var slot entities.VisitSlot
+			if err := bson.Unmarshal(raw, &slot); err != nil {
+				return fmt.Errorf("failed to decode visit slot: %w", err)
+			}
+			return putBoltSlot(slots, slot)
+		})
+		if err != nil {
+			return err
+		}
+
+		threads := tx.Bucket([]byte(boltMessagesBucket))
+		result.Threads, err = migrateCollection(ctx, database.Collection(source.MessageCollection), func(raw bson.Raw) error {
+			var thread entities.Thread
+			if err := bson.Unmarshal(raw, &thread); err != nil {
+				return fmt.Errorf("failed to decode message thread: %w", err)
+			}
+			return putBoltThread(threads, thread)
+		})
+		if err != nil {
+			return err
+		}
+
+		notifications := tx.Bucket([]byte(boltNotificationsBucket))
+		result.Notifications, err = migrateCollection(ctx, database.Collection(source.NotificationCollection), func(raw bson.Raw) error {
+			var notification entities.Notification
+			if err := bson.Unmarshal(raw, &notification); err != nil {
+				return fmt.Errorf("failed to decode notification: %w", err)
+			}
+			return putBoltNotification(notifications, notification)
+		})
+		return err
+	})
+	if err != nil {
+		return MigrationResult{}, err
+	}
+	return result, nil
+}
+
+// migrateCollection calls store for every document of the collection and returns how many were stored.
+func migrateCollection(ctx context.Context, collection *mongo.Collection, store func(bson.Raw) error) (int, error) {
+	cursor, err := collection.Find(ctx, bson.D{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to read collection %s: %w", collection.Name(), err)
+	}
+	defer cursor.Close(ctx)
+
+	count := 0
+	for cursor.Next(ctx) {
+		if err := store(cursor.Current); err != nil {
+			return count, fmt.Errorf("failed to migrate %s: %w", collection.Name(), err)
+		}
+		count++
+	}
+	if err := cursor.Err(); err != nil {
+		return count, fmt.Errorf("cursor error: %w", err)
+	}
+	return count, nil
+}
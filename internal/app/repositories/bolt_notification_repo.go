@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+// BoltNotificationRepo is a NotificationRepo stored in an embedded BoltDB file.
+// Notifications are BSON encoded and keyed by their ObjectID.
+type BoltNotificationRepo struct {
+	db *bbolt.DB
+}
+
+// NewBoltNotificationRepo initializes a NotificationRepo on a database opened with OpenBoltDB.
+func NewBoltNotificationRepo(db *bbolt.DB) interfaces.NotificationRepo {
+	return &BoltNotificationRepo{db: db}
+}
+
+// SaveNotification writes the notification, assigning a new ID when it has none.
+func (repo *BoltNotificationRepo) SaveNotification(ctx context.Context, notification entities.Notification) error {
+	if notification.ID.IsZero() {
+		notification.ID = primitive.NewObjectID()
+	}
+	return boltUpdate(ctx, repo.db, func(tx *bbolt.Tx) error {
+		return putBoltNotification(tx.Bucket([]byte(boltNotificationsBucket)), notification)
+	})
+}
+
+// FindNotificationsByUser returns the notifications of the user, newest first.
+func (repo *BoltNotificationRepo) FindNotificationsByUser(ctx context.Context, username string) ([]entities.Notification, error) {
+	var notifications []entities.Notification
+	err := boltView(ctx, repo.db, func(tx *bbolt.Tx) error {
+		return forEachBoltNotification(tx.Bucket([]byte(boltNotificationsBucket)), func(notification entities.Notification) error {
+			if notification.Username == username {
+				notifications = append(notifications, notification)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortNotifications(notifications)
+	return notifications, nil
+}
+
+// MarkNotificationRead marks the notification read if it belongs to the user.
+func (repo *BoltNotificationRepo) MarkNotificationRead(ctx context.Context, id primitive.ObjectID, username string) (bool, error) {
+	marked, err := repo.update(ctx, func(notification *entities.Notification) bool {
+		if notification.ID != id || notification.Username != username {
+			return false
+		}
+		notification.Read = true
+		return true
+	}, false)
+	return marked == 1, err
+}
+
+// MarkAllNotificationsRead marks the unread notifications of the user read.
+func (repo *BoltNotificationRepo) MarkAllNotificationsRead(ctx context.Context, username string) (int, error) {
+	return repo.update(ctx, func(notification *entities.Notification) bool {
+		if notification.Username != username || notification.Read {
+			return false
+		}
+		notification.Read = true
+		return true
+	}, false)
+}
+
+// DeleteNotification removes the notification if it belongs to the user.
+func (repo *BoltNotificationRepo) DeleteNotification(ctx context.Context, id primitive.ObjectID, username string) (bool, error) {
+	deleted, err := repo.update(ctx, func(notification *entities.Notification) bool {
+		return notification.ID == id && notification.Username == username
+	}, true)
+	return deleted == 1, err
+}
+
+// DeleteReadNotifications removes the notifications the user has read.
+func (repo *BoltNotificationRepo) DeleteReadNotifications(ctx context.Context, username string) (int, error) {
+	return repo.update(ctx, func(notification *entities.Notification) bool {
+		return notification.Username == username && notification.Read
+	}, true)
+}
+
+// update passes every notification to change, in one update transaction, and writes back those it
+// reports changed, or deletes them when remove is set. It returns how many it changed.
+func (repo *BoltNotificationRepo) update(ctx context.Context, change func(*entities.Notification) bool, remove bool) (int, error) {
+	changed := 0
+	err := boltUpdate(ctx, repo.db, func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(boltNotificationsBucket))
+		var matched []entities.Notification
+		err := forEachBoltNotification(bucket, func(notification entities.Notification) error {
+			if change(&notification) {
+				matched = append(matched, notification)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		// The bucket is not modified while iterating over it
+		for _, notification := range matched {
+			if remove {
+				err = bucket.Delete(notification.ID[:])
+			} else {
+				err = putBoltNotification(bucket, notification)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		changed = len(matched)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return changed, nil
+}
+
+// forEachBoltNotification decodes every notification in the bucket and passes it to fn.
+func forEachBoltNotification(bucket *bbolt.Bucket, fn func(entities.Notification) error) error {
+	return bucket.ForEach(func(key, data []byte) error {
+		var notification entities.Notification
+		if err := bson.Unmarshal(data, &notification); err != nil {
+			return fmt.Errorf("failed to decode notification: %w", err)
+		}
+		return fn(notification)
+	})
+}
+
+// putBoltNotification writes the notification under its ID, replacing any existing entry.
+func putBoltNotification(bucket *bbolt.Bucket, notification entities.Notification) error {
+	data, err := bson.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification %s: %w", notification.ID.Hex(), err)
+	}
+	return bucket.Put(notification.ID[:], data)
+}
@@ -0,0 +1,204 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+// BoltPropertyRepo is a PropertyRepo stored in an embedded BoltDB file.
+// Properties are BSON encoded and keyed by their ObjectID, so the typed
+// Details are decoded exactly as they are for MongoDB.
+type BoltPropertyRepo struct {
+	db *bbolt.DB
+}
+
+// NewBoltPropertyRepo initializes a PropertyRepo on a database opened with OpenBoltDB.
+func NewBoltPropertyRepo(db *bbolt.DB) interfaces.PropertyRepo {
+	return &BoltPropertyRepo{db: db}
+}
+
+// SaveProperty saves a new property. Property IDs must be unique.
+func (r *BoltPropertyRepo) SaveProperty(ctx context.Context, property entities.Property) error {
+	return boltUpdate(ctx, r.db, func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(boltPropertiesBucket))
+		if bucket.Get(property.ID[:]) != nil {
+			return fmt.Errorf("property with ID %s already exists", property.ID.Hex())
+		}
+		return putBoltProperty(bucket, property)
+	})
+}
+
+// GetAllListedProperties retrieves the properties of the given landlord.
+// If `landlordUsername` is empty, it returns all properties that are not rented.
+func (r *BoltPropertyRepo) GetAllListedProperties(ctx context.Context, landlordUsername string) ([]entities.Property, error) {
+	return r.filter(ctx, func(property entities.Property) bool {
+		if landlordUsername != "" {
+			return property.LandlordUsername == landlordUsername
+		}
+		return !property.IsRented
+	})
+}
+
+// UpdateListedProperty updates the editable fields of an existing property.
+func (r *BoltPropertyRepo) UpdateListedProperty(ctx context.Context, property entities.Property) error {
+	return boltUpdate(ctx, r.db, func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(boltPropertiesBucket))
+		data := bucket.Get(property.ID[:])
+		if data == nil {
+			return nil
+		}
+
+		stored, err := decodeProperty(data)
+		if err != nil {
+			return err
+		}
+		stored.Title = property.Title
+		stored.Address = property.Address
+		stored.RentAmount = property.RentAmount
+		stored.Deposit = property.Deposit
+		stored.IsApprovedByAdmin = property.IsApprovedByAdmin
+		stored.IsRented = property.IsRented
+		stored.Details = property.Details
+		return putBoltProperty(bucket, stored)
+	})
+}
+
+// DeleteListedProperty deletes the property with the given ID. Deleting an unknown property is not an error.
+func (r *BoltPropertyRepo) DeleteListedProperty(ctx context.Context, propertyID primitive.ObjectID) error {
+	return boltUpdate(ctx, r.db, func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(boltPropertiesBucket)).Delete(propertyID[:])
+	})
+}
+
+// FindByID retrieves a property by its ID, or nil if there is none.
+func (r *BoltPropertyRepo) FindByID(ctx context.Context, id primitive.ObjectID) (*entities.Property, error) {
+	var property *entities.Property
+	err := boltView(ctx, r.db, func(tx *bbolt.Tx) error {
+		data := tx.Bucket([]byte(boltPropertiesBucket)).Get(id[:])
+		if data == nil {
+			return nil // No property found
+		}
+
+		found, err := decodeProperty(data)
+		if err != nil {
+			return err
+		}
+		property = &found
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return property, nil
+}
+
+// UpdateApprovalStatus sets the approval flag of a property.
+func (r *BoltPropertyRepo) UpdateApprovalStatus(ctx context.Context, propertyID primitive.ObjectID, approved bool, adminUsername string) error {
+	return boltUpdate(ctx, r.db, func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(boltPropertiesBucket))
+		data := bucket.Get(propertyID[:])
+		if data == nil {
+			return nil
+		}
+
+		property, err := decodeProperty(data)
+		if err != nil {
+			return err
+		}
+		property.IsApprovedByAdmin = approved
+		return putBoltProperty(bucket, property)
+	})
+}
+
+// SetRented changes whether the property is rented if it is not in that state already.
+// The check and the write happen in one update transaction, which Bolt runs one at a time.
+func (r *BoltPropertyRepo) SetRented(ctx context.Context, propertyID primitive.ObjectID, rented bool) (bool, error) {
+	updated := false
+	err := boltUpdate(ctx, r.db, func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(boltPropertiesBucket))
+		data := bucket.Get(propertyID[:])
+		if data == nil {
+			return nil
+		}
+
+		property, err := decodeProperty(data)
+		if err != nil {
+			return err
+		}
+		if property.IsRented == rented {
+			return nil
+		}
+		property.IsRented = rented
+		updated = true
+		return putBoltProperty(bucket, property)
+	})
+	if err != nil {
+		return false, err
+	}
+	return updated, nil
+}
+
+// FindPendingProperties returns all properties not yet approved by an admin.
+func (r *BoltPropertyRepo) FindPendingProperties(ctx context.Context) ([]entities.Property, error) {
+	return r.filter(ctx, func(property entities.Property) bool {
+		return !property.IsApprovedByAdmin
+	})
+}
+
+// DeleteAllListedPropertiesOfaUser deletes all the listed properties of the given landlord.
+func (r *BoltPropertyRepo) DeleteAllListedPropertiesOfaUser(ctx context.Context, username string) error {
+	return boltUpdate(ctx, r.db, func(tx *bbolt.Tx) error {
+		cursor := tx.Bucket([]byte(boltPropertiesBucket)).Cursor()
+		key, data := cursor.First()
+		for key != nil {
+			landlord, _ := bson.Raw(data).Lookup("landlord_username").StringValueOK()
+			if landlord != username {
+				key, data = cursor.Next()
+				continue
+			}
+			if err := cursor.Delete(); err != nil {
+				return fmt.Errorf("failed to delete properties for user %s: %w", username, err)
+			}
+			// Deleting moves the cursor onto the following entry
+			key, data = cursor.Seek(key)
+		}
+		return nil
+	})
+}
+
+// filter returns all properties matching the predicate, ordered by ID.
+func (r *BoltPropertyRepo) filter(ctx context.Context, match func(entities.Property) bool) ([]entities.Property, error) {
+	var properties []entities.Property
+	err := boltView(ctx, r.db, func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(boltPropertiesBucket)).ForEach(func(key, data []byte) error {
+			property, err := decodeProperty(data)
+			if err != nil {
+				return err
+			}
+			if match(property) {
+				properties = append(properties, property)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return properties, nil
+}
+
+// putBoltProperty writes the property under its ID, replacing any existing entry.
+func putBoltProperty(bucket *bbolt.Bucket, property entities.Property) error {
+	data, err := bson.Marshal(property)
+	if err != nil {
+		return fmt.Errorf("failed to encode property %s: %w", property.ID.Hex(), err)
+	}
+	return bucket.Put(property.ID[:], data)
+}
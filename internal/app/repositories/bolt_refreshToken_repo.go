@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+// BoltRefreshTokenRepo is a RefreshTokenRepo stored in an embedded BoltDB file.
+// Tokens are BSON encoded and keyed by their ID.
+type BoltRefreshTokenRepo struct {
+	db *bbolt.DB
+}
+
+// NewBoltRefreshTokenRepo initializes a RefreshTokenRepo on a database opened with OpenBoltDB.
+func NewBoltRefreshTokenRepo(db *bbolt.DB) interfaces.RefreshTokenRepo {
+	return &BoltRefreshTokenRepo{db: db}
+}
+
+// SaveRefreshToken stores the token, replacing any token with the same ID.
+func (repo *BoltRefreshTokenRepo) SaveRefreshToken(ctx context.Context, token entities.RefreshToken) error {
+	return boltUpdate(ctx, repo.db, func(tx *bbolt.Tx) error {
+		return putBoltRefreshToken(tx.Bucket([]byte(boltRefreshTokensBucket)), token)
+	})
+}
+
+// RotateRefreshToken replaces the token if it still has the previous hash and is not revoked.
+// Bolt runs one update transaction at a time, so the check and the write cannot interleave with another refresh.
+func (repo *BoltRefreshTokenRepo) RotateRefreshToken(ctx context.Context, token entities.RefreshToken, previousHash string) (bool, error) {
+	rotated := false
+	err := boltUpdate(ctx, repo.db, func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(boltRefreshTokensBucket))
+		data := bucket.Get([]byte(token.ID))
+		if data == nil {
+			return nil
+		}
+		var stored entities.RefreshToken
+		if err := bson.Unmarshal(data, &stored); err != nil {
+			return fmt.Errorf("failed to decode refresh token %s: %w", token.ID, err)
+		}
+		if stored.Revoked || stored.TokenHash != previousHash {
+			return nil
+		}
+		rotated = true
+		return putBoltRefreshToken(bucket, token)
+	})
+	return rotated, err
+}
+
+func (repo *BoltRefreshTokenRepo) FindRefreshToken(ctx context.Context, id string) (*entities.RefreshToken, error) {
+	var token *entities.RefreshToken
+	err := boltView(ctx, repo.db, func(tx *bbolt.Tx) error {
+		data := tx.Bucket([]byte(boltRefreshTokensBucket)).Get([]byte(id))
+		if data == nil {
+			return nil // No token found
+		}
+
+		var found entities.RefreshToken
+		if err := bson.Unmarshal(data, &found); err != nil {
+			return fmt.Errorf("failed to decode refresh token %s: %w", id, err)
+		}
+		token = &found
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// RevokeRefreshToken revokes the token with the given ID. Revoking an unknown token is not an error.
+func (repo *BoltRefreshTokenRepo) RevokeRefreshToken(ctx context.Context, id string) error {
+	return repo.revoke(ctx, func(token entities.RefreshToken) bool {
+		return token.ID == id
+	})
+}
+
+// RevokeUserRefreshTokens revokes every token of the user.
+func (repo *BoltRefreshTokenRepo) RevokeUserRefreshTokens(ctx context.Context, username string) error {
+	return repo.revoke(ctx, func(token entities.RefreshToken) bool {
+		return token.Username == username
+	})
+}
+
+// revoke marks every token accepted by match as revoked in a single transaction.
+func (repo *BoltRefreshTokenRepo) revoke(ctx context.Context, match func(entities.RefreshToken) bool) error {
+	return boltUpdate(ctx, repo.db, func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(boltRefreshTokensBucket))
+
+		var revoked []entities.RefreshToken
+		err := bucket.ForEach(func(key, data []byte) error {
+			var token entities.RefreshToken
+			if err := bson.Unmarshal(data, &token); err != nil {
+				return fmt.Errorf("failed to decode refresh token %s: %w", key, err)
+			}
+			if match(token) && !token.Revoked {
+				token.Revoked = true
+				revoked = append(revoked, token)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// Buckets must not be modified while iterating over them
+		for _, token := range revoked {
+			if err := putBoltRefreshToken(bucket, token); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// putBoltRefreshToken writes the token under its ID, replacing any existing entry.
+func putBoltRefreshToken(bucket *bbolt.Bucket, token entities.RefreshToken) error {
+	data, err := bson.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("failed to encode refresh token %s: %w", token.ID, err)
+	}
+	return bucket.Put([]byte(token.ID), data)
+}
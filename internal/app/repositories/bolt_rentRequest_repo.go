@@ -0,0 +1,175 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+// BoltRequestRepo is a RequestRepo stored in an embedded BoltDB file.
+// Requests are BSON encoded and keyed by their ObjectID.
+type BoltRequestRepo struct {
+	db *bbolt.DB
+}
+
+// NewBoltRequestRepo initializes a RequestRepo on a database opened with OpenBoltDB.
+func NewBoltRequestRepo(db *bbolt.DB) interfaces.RequestRepo {
+	return &BoltRequestRepo{db: db}
+}
+
+// SaveRequest saves the request, assigning a new ID when it has none.
+func (repo *BoltRequestRepo) SaveRequest(ctx context.Context, request entities.Request) error {
+	if request.ID.IsZero() {
+		request.ID = primitive.NewObjectID()
+	}
+	return boltUpdate(ctx, repo.db, func(tx *bbolt.Tx) error {
+		return putBoltRequest(tx.Bucket([]byte(boltRentRequestsBucket)), request)
+	})
+}
+
+// CreateRequest saves the request unless the tenant already has an open request for the property.
+// The check and the write happen in one update transaction, which Bolt runs one at a time.
+func (repo *BoltRequestRepo) CreateRequest(ctx context.Context, request entities.Request) (bool, error) {
+	if request.ID.IsZero() {
+		request.ID = primitive.NewObjectID()
+	}
+	created := false
+	err := boltUpdate(ctx, repo.db, func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(boltRentRequestsBucket))
+		err := bucket.ForEach(func(key, data []byte) error {
+			var stored entities.Request
+			if err := bson.Unmarshal(data, &stored); err != nil {
+				return fmt.Errorf("failed to decode request: %w", err)
+			}
+			if stored.TenantName == request.TenantName && stored.PropertyID == request.PropertyID && !stored.RequestStatus.IsFinal() {
+				return errOpenRequestExists
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		created = true
+		return putBoltRequest(bucket, request)
+	})
+	if errors.Is(err, errOpenRequestExists) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return created, nil
+}
+
+// errOpenRequestExists stops the scan of CreateRequest at the first open request of the tenant for the property.
+var errOpenRequestExists = errors.New("open request exists")
+
+func (repo *BoltRequestRepo) FindByTenantUsername(ctx context.Context, tenantUsername string) ([]entities.Request, error) {
+	return repo.filter(ctx, func(request entities.Request) bool {
+		return request.TenantName == tenantUsername
+	})
+}
+
+func (repo *BoltRequestRepo) FindByLandlordName(ctx context.Context, landlordName string) ([]entities.Request, error) {
+	return repo.filter(ctx, func(request entities.Request) bool {
+		return request.LandlordName == landlordName
+	})
+}
+
+func (repo *BoltRequestRepo) FindByStatus(ctx context.Context, status entities.RequestStatus) ([]entities.Request, error) {
+	return repo.filter(ctx, func(request entities.Request) bool {
+		return request.RequestStatus == status
+	})
+}
+
+func (repo *BoltRequestRepo) FindByPropertyID(ctx context.Context, propertyID primitive.ObjectID) ([]entities.Request, error) {
+	return repo.filter(ctx, func(request entities.Request) bool {
+		return request.PropertyID == propertyID
+	})
+}
+
+// FindRequestByID returns the request, or nil if there is none with the ID.
+func (repo *BoltRequestRepo) FindRequestByID(ctx context.Context, id primitive.ObjectID) (*entities.Request, error) {
+	var request *entities.Request
+	err := boltView(ctx, repo.db, func(tx *bbolt.Tx) error {
+		data := tx.Bucket([]byte(boltRentRequestsBucket)).Get(id[:])
+		if data == nil {
+			return nil
+		}
+		request = &entities.Request{}
+		if err := bson.Unmarshal(data, request); err != nil {
+			return fmt.Errorf("failed to decode request %s: %w", id.Hex(), err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// UpdateRequestStatus applies the change if the stored request is still in change.From.
+// Bolt runs one update transaction at a time, so the check and the write cannot interleave with another change.
+func (repo *BoltRequestRepo) UpdateRequestStatus(ctx context.Context, id primitive.ObjectID, change entities.StatusChange) (bool, error) {
+	updated := false
+	err := boltUpdate(ctx, repo.db, func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(boltRentRequestsBucket))
+		data := bucket.Get(id[:])
+		if data == nil {
+			return nil
+		}
+
+		var stored entities.Request
+		if err := bson.Unmarshal(data, &stored); err != nil {
+			return fmt.Errorf("failed to decode request %s: %w", id.Hex(), err)
+		}
+		if stored.RequestStatus != change.From {
+			return nil
+		}
+		stored.RequestStatus = change.To
+		stored.History = append(stored.History, change)
+		updated = true
+		return putBoltRequest(bucket, stored)
+	})
+	if err != nil {
+		return false, err
+	}
+	return updated, nil
+}
+
+// filter returns all requests matching the predicate, ordered by ID (and so by creation).
+func (repo *BoltRequestRepo) filter(ctx context.Context, match func(entities.Request) bool) ([]entities.Request, error) {
+	var requests []entities.Request
+	err := boltView(ctx, repo.db, func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(boltRentRequestsBucket)).ForEach(func(key, data []byte) error {
+			var request entities.Request
+			if err := bson.Unmarshal(data, &request); err != nil {
+				return fmt.Errorf("failed to decode request: %w", err)
+			}
+			if match(request) {
+				requests = append(requests, request)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// putBoltRequest writes the request under its ID, replacing any existing entry.
+func putBoltRequest(bucket *bbolt.Bucket, request entities.Request) error {
+	data, err := bson.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to encode request %s: %w", request.ID.Hex(), err)
+	}
+	return bucket.Put(request.ID[:], data)
+}
@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+// Bucket names used by the BoltDB storage backend.
+const (
+	boltMetaBucket          = "meta"
+	boltUsersBucket         = "users"
+	boltPropertiesBucket    = "properties"
+	boltRentRequestsBucket  = "rentRequests"
+	boltRefreshTokensBucket = "refreshTokens"
+	boltLeasesBucket        = "leases"
+	boltRentDuesBucket      = "rentDues"
+	boltPaymentsBucket      = "payments"
+	boltDepositsBucket      = "deposits"
+	boltMaintenanceBucket   = "maintenance"
+	boltVisitBucket         = "visits"
+	boltMessagesBucket      = "messages"
+	boltNotificationsBucket = "notifications"
+)
+
+// boltSchemaVersion is the version of the bucket layout written by this build.
+const boltSchemaVersion = 1
+
+var boltSchemaVersionKey = []byte("schema_version")
+
+// OpenBoltDB opens (or creates) the BoltDB file at path and makes sure all buckets exist.
+// The returned database must be closed by the caller.
+func OpenBoltDB(path string) (*bbolt.DB, error) {
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database file %s: %w", path, err)
+	}
+
+	if err := createBoltSchema(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	log.Println("Opened database file", path)
+	return db, nil
+}
+
+// createBoltSchema creates the buckets on first start and checks the schema version afterwards.
+func createBoltSchema(db *bbolt.DB) error {
+	return db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range []string{boltMetaBucket, boltUsersBucket, boltPropertiesBucket, boltRentRequestsBucket, boltRefreshTokensBucket, boltLeasesBucket, boltRentDuesBucket, boltPaymentsBucket, boltDepositsBucket, boltMaintenanceBucket, boltVisitBucket, boltMessagesBucket, boltNotificationsBucket} {
+			if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+				return fmt.Errorf("failed to create bucket %s: %w", name, err)
+			}
+		}
+
+		meta := tx.Bucket([]byte(boltMetaBucket))
+		stored := meta.Get(boltSchemaVersionKey)
+		if stored == nil {
+			version := make([]byte, 8)
+			binary.BigEndian.PutUint64(version, boltSchemaVersion)
+			return meta.Put(boltSchemaVersionKey, version)
+		}
+
+		if version := binary.BigEndian.Uint64(stored); version > boltSchemaVersion {
+			return fmt.Errorf("database schema version %d is newer than supported version %d", version, boltSchemaVersion)
+		}
+		return nil
+	})
+}
+
+// boltView runs fn in a read-only transaction unless ctx is already done.
+func boltView(ctx context.Context, db *bbolt.DB, fn func(tx *bbolt.Tx) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return db.View(fn)
+}
+
+// boltUpdate runs fn in a read-write transaction unless ctx is already done.
+func boltUpdate(ctx context.Context, db *bbolt.DB, fn func(tx *bbolt.Tx) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return db.Update(fn)
+}
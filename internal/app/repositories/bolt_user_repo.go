@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+	"rentease/pkg/utils"
+)
+
+// BoltUserRepo is a UserRepo stored in an embedded BoltDB file.
+// Users are BSON encoded and keyed by username.
+type BoltUserRepo struct {
+	db *bbolt.DB
+}
+
+// NewBoltUserRepo initializes a UserRepo on a database opened with OpenBoltDB.
+func NewBoltUserRepo(db *bbolt.DB) interfaces.UserRepo {
+	return &BoltUserRepo{db: db}
+}
+
+// SaveUser saves a new user. Usernames must be unique.
+func (repo *BoltUserRepo) SaveUser(ctx context.Context, user entities.User) error {
+	return boltUpdate(ctx, repo.db, func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(boltUsersBucket))
+		if bucket.Get([]byte(user.Username)) != nil {
+			return fmt.Errorf("user %s already exists", user.Username)
+		}
+		return putBoltUser(bucket, user)
+	})
+}
+
+func (repo *BoltUserRepo) FindByUsername(ctx context.Context, username string) (*entities.User, error) {
+	var user *entities.User
+	err := boltView(ctx, repo.db, func(tx *bbolt.Tx) error {
+		data := tx.Bucket([]byte(boltUsersBucket)).Get([]byte(username))
+		if data == nil {
+			return nil // No user found
+		}
+
+		var found entities.User
+		if err := bson.Unmarshal(data, &found); err != nil {
+			return fmt.Errorf("failed to decode user %s: %w", username, err)
+		}
+		user = &found
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// CheckPassword verifies the user's password.
+func (repo *BoltUserRepo) CheckPassword(ctx context.Context, username, password string) (bool, error) {
+	user, err := repo.FindByUsername(ctx, username)
+	if err != nil {
+		return false, err
+	}
+	if user == nil {
+		return false, nil // User not found
+	}
+
+	return utils.CheckPasswordHash(password, user.PasswordHash), nil
+}
+
+func (repo *BoltUserRepo) UpdateUser(ctx context.Context, user entities.User) error {
+	return boltUpdate(ctx, repo.db, func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(boltUsersBucket))
+		if bucket.Get([]byte(user.Username)) == nil {
+			return errors.New("user not found")
+		}
+		return putBoltUser(bucket, user)
+	})
+}
+
+// FindAll returns every user, ordered by username.
+func (repo *BoltUserRepo) FindAll(ctx context.Context) ([]entities.User, error) {
+	var users []entities.User
+	err := boltView(ctx, repo.db, func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(boltUsersBucket)).ForEach(func(key, data []byte) error {
+			var user entities.User
+			if err := bson.Unmarshal(data, &user); err != nil {
+				return fmt.Errorf("failed to decode user %s: %w", key, err)
+			}
+			users = append(users, user)
+			return nil
+		})
+	})
+	return users, err
+}
+
+func (repo *BoltUserRepo) Delete(ctx context.Context, username string) error {
+	return boltUpdate(ctx, repo.db, func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(boltUsersBucket)).Delete([]byte(username))
+	})
+}
+
+// putBoltUser writes the user under its username, replacing any existing entry.
+func putBoltUser(bucket *bbolt.Bucket, user entities.User) error {
+	data, err := bson.Marshal(user)
+	if err != nil {
+		return fmt.Errorf("failed to encode user %s: %w", user.Username, err)
+	}
+	return bucket.Put([]byte(user.Username), data)
+}
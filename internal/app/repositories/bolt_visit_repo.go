@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+// BoltVisitRepo is a VisitRepo stored in an embedded BoltDB file.
+// Slots are BSON encoded and keyed by their ObjectID.
+type BoltVisitRepo struct {
+	db *bbolt.DB
+}
+
+// NewBoltVisitRepo initializes a VisitRepo on a database opened with OpenBoltDB.
+func NewBoltVisitRepo(db *bbolt.DB) interfaces.VisitRepo {
+	return &BoltVisitRepo{db: db}
+}
+
+// SaveSlot saves the slot, assigning a new ID when it has none.
+func (repo *BoltVisitRepo) SaveSlot(ctx context.Context, slot entities.VisitSlot) error {
+	if slot.ID.IsZero() {
+		slot.ID = primitive.NewObjectID()
+	}
+	return boltUpdate(ctx, repo.db, func(tx *bbolt.Tx) error {
+		return putBoltSlot(tx.Bucket([]byte(boltVisitBucket)), slot)
+	})
+}
+
+// FindSlotByID returns the slot, or nil if there is none with the ID.
+func (repo *BoltVisitRepo) FindSlotByID(ctx context.Context, id primitive.ObjectID) (*entities.VisitSlot, error) {
+	var slot *entities.VisitSlot
+	err := boltView(ctx, repo.db, func(tx *bbolt.Tx) error {
+		data := tx.Bucket([]byte(boltVisitBucket)).Get(id[:])
+		if data == nil {
+			return nil
+		}
+		slot = &entities.VisitSlot{}
+		if err := bson.Unmarshal(data, slot); err != nil {
+			return fmt.Errorf("failed to decode visit slot %s: %w", id.Hex(), err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return slot, nil
+}
+
+func (repo *BoltVisitRepo) FindSlotsByProperty(ctx context.Context, propertyID primitive.ObjectID) ([]entities.VisitSlot, error) {
+	return repo.filter(ctx, func(slot entities.VisitSlot) bool {
+		return slot.PropertyID == propertyID
+	})
+}
+
+func (repo *BoltVisitRepo) FindSlotsByLandlord(ctx context.Context, landlordName string) ([]entities.VisitSlot, error) {
+	return repo.filter(ctx, func(slot entities.VisitSlot) bool {
+		return slot.LandlordName == landlordName
+	})
+}
+
+func (repo *BoltVisitRepo) FindSlotsByTenant(ctx context.Context, tenantName string) ([]entities.VisitSlot, error) {
+	return repo.filter(ctx, func(slot entities.VisitSlot) bool {
+		return slot.TenantName == tenantName
+	})
+}
+
+// BookSlot books the slot for the tenant if it is still open.
+// Bolt runs one update transaction at a time, so two tenants cannot both find the slot open.
+func (repo *BoltVisitRepo) BookSlot(ctx context.Context, id primitive.ObjectID, tenantName string, at time.Time) (bool, error) {
+	return repo.update(ctx, id, func(slot *entities.VisitSlot) bool {
+		return bookSlot(slot, tenantName, at)
+	})
+}
+
+// FreeSlot opens the slot again if it is still booked by the tenant.
+func (repo *BoltVisitRepo) FreeSlot(ctx context.Context, id primitive.ObjectID, tenantName string, at time.Time) (bool, error) {
+	return repo.update(ctx, id, func(slot *entities.VisitSlot) bool {
+		return freeSlot(slot, tenantName, at)
+	})
+}
+
+// CancelSlot cancels the slot if it is still in from.
+func (repo *BoltVisitRepo) CancelSlot(ctx context.Context, id primitive.ObjectID, from entities.VisitStatus, by string, at time.Time) (bool, error) {
+	return repo.update(ctx, id, func(slot *entities.VisitSlot) bool {
+		return cancelSlot(slot, from, by, at)
+	})
+}
+
+// update reads the slot, lets change modify it, and writes it back if change reports it did.
+func (repo *BoltVisitRepo) update(ctx context.Context, id primitive.ObjectID, change func(*entities.VisitSlot) bool) (bool, error) {
+	updated := false
+	err := boltUpdate(ctx, repo.db, func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(boltVisitBucket))
+		data := bucket.Get(id[:])
+		if data == nil {
+			return nil
+		}
+
+		var stored entities.VisitSlot
+		if err := bson.Unmarshal(data, &stored); err != nil {
+			return fmt.Errorf("failed to decode visit slot %s: %w", id.Hex(), err)
+		}
+		if !change(&stored) {
+			return nil
+		}
+		updated = true
+		return putBoltSlot(bucket, stored)
+	})
+	if err != nil {
+		return false, err
+	}
+	return updated, nil
+}
+
+// filter returns all slots matching the predicate, ordered by their start.
+func (repo *BoltVisitRepo) filter(ctx context.Context, match func(entities.VisitSlot) bool) ([]entities.VisitSlot, error) {
+	var slots []entities.VisitSlot
+	err := boltView(ctx, repo.db, func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(boltVisitBucket)).ForEach(func(key, data []byte) error {
+			var slot entities.VisitSlot
+			if err := bson.Unmarshal(data, &slot); err != nil {
+				return fmt.Errorf("failed to decode visit slot: %w", err)
+			}
+			if match(slot) {
+				slots = append(slots, slot)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortSlots(slots)
+	return slots, nil
+}
+
+// putBoltSlot writes the slot under its ID, replacing any existing entry.
+func putBoltSlot(bucket *bbolt.Bucket, slot entities.VisitSlot) error {
+	data, err := bson.Marshal(slot)
+	if err != nil {
+		return fmt.Errorf("failed to encode visit slot %s: %w", slot.ID.Hex(), err)
+	}
+	return bucket.Put(slot.ID[:], data)
+}
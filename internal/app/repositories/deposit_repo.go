@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+type DepositRepo struct {
+	client     *mongo.Client
+	collection *mongo.Collection
+}
+
+// NewDepositRepo initializes a new DepositRepo on the shared MongoDB client.
+func NewDepositRepo(client *mongo.Client, dbName string, collectionName string) interfaces.DepositRepo {
+	return &DepositRepo{
+		client:     client,
+		collection: client.Database(dbName).Collection(collectionName),
+	}
+}
+
+// leaseDepositIndex is the name of the unique index over the lease of deposits.
+const leaseDepositIndex = "leaseID"
+
+// EnsureDepositIndexes creates the indexes of the deposit collection if they do not exist yet.
+// The unique index over the lease makes CreateDeposit safe against a deposit being recorded twice at the same time.
+func EnsureDepositIndexes(ctx context.Context, client *mongo.Client, dbName string, collectionName string) error {
+	index := mongo.IndexModel{
+		Keys:    bson.D{{Key: "leaseID", Value: 1}},
+		Options: options.Index().SetName(leaseDepositIndex).SetUnique(true),
+	}
+	if _, err := client.Database(dbName).Collection(collectionName).Indexes().CreateOne(ctx, index); err != nil {
+		return fmt.Errorf("failed to create index %s on %s: %w", leaseDepositIndex, collectionName, err)
+	}
+	return nil
+}
+
+// CreateDeposit saves the deposit unless its lease already has one, which the index created by
+// EnsureDepositIndexes rejects.
+func (repo *DepositRepo) CreateDeposit(ctx context.Context, deposit entities.Deposit) (bool, error) {
+	if deposit.ID.IsZero() {
+		deposit.ID = primitive.NewObjectID()
+	}
+	_, err := repo.collection.InsertOne(ctx, deposit)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// FindDepositByLease returns the deposit of the lease, or nil if none was recorded.
+func (repo *DepositRepo) FindDepositByLease(ctx context.Context, leaseID primitive.ObjectID) (*entities.Deposit, error) {
+	var deposit entities.Deposit
+	err := repo.collection.FindOne(ctx, bson.M{"leaseID": leaseID}).Decode(&deposit)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &deposit, nil
+}
+
+// UpdateDeposit replaces the deposit if the stored one is still in from.
+func (repo *DepositRepo) UpdateDeposit(ctx context.Context, deposit entities.Deposit, from entities.DepositStatus) (bool, error) {
+	result, err := repo.collection.ReplaceOne(ctx, bson.M{"_id": deposit.ID, "leaseID": deposit.LeaseID, "status": from}, deposit)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+type LeaseRepo struct {
+	client     *mongo.Client
+	collection *mongo.Collection
+}
+
+// NewLeaseRepo initializes a new LeaseRepo on the shared MongoDB client.
+func NewLeaseRepo(client *mongo.Client, dbName string, collectionName string) interfaces.LeaseRepo {
+	collection := client.Database(dbName).Collection(collectionName)
+	return &LeaseRepo{
+		client:     client,
+		collection: collection,
+	}
+}
+
+// SaveLease stores the lease, replacing any lease with the same ID.
+func (repo *LeaseRepo) SaveLease(ctx context.Context, lease entities.Lease) error {
+	if lease.ID.IsZero() {
+		lease.ID = primitive.NewObjectID()
+	}
+	_, err := repo.collection.ReplaceOne(ctx, bson.M{"_id": lease.ID}, lease, options.Replace().SetUpsert(true))
+	return err
+}
+
+// FindLeaseByID returns the lease, or nil if there is none with the ID.
+func (repo *LeaseRepo) FindLeaseByID(ctx context.Context, id primitive.ObjectID) (*entities.Lease, error) {
+	return repo.findOne(ctx, bson.M{"_id": id})
+}
+
+// FindLeaseByRequestID returns the lease drawn up for the request, or nil if there is none.
+func (repo *LeaseRepo) FindLeaseByRequestID(ctx context.Context, requestID primitive.ObjectID) (*entities.Lease, error) {
+	return repo.findOne(ctx, bson.M{"requestID": requestID})
+}
+
+func (repo *LeaseRepo) FindLeasesByTenant(ctx context.Context, tenantName string) ([]entities.Lease, error) {
+	return repo.find(ctx, bson.D{{Key: "tenantName", Value: tenantName}})
+}
+
+func (repo *LeaseRepo) FindLeasesByLandlord(ctx context.Context, landlordName string) ([]entities.Lease, error) {
+	return repo.find(ctx, bson.D{{Key: "landlordName", Value: landlordName}})
+}
+
+func (repo *LeaseRepo) FindLeasesByStatus(ctx context.Context, status entities.LeaseStatus) ([]entities.Lease, error) {
+	return repo.find(ctx, bson.D{{Key: "status", Value: status}})
+}
+
+// UpdateLeaseStatus changes the status if the stored lease is still in from.
+// The status is part of the filter, so of two concurrent changes from the same status only one matches.
+func (repo *LeaseRepo) UpdateLeaseStatus(ctx context.Context, id primitive.ObjectID, from, to entities.LeaseStatus) (bool, error) {
+	filter := bson.M{"_id": id, "status": from}
+	update := bson.M{"$set": bson.M{"status": to}}
+	result, err := repo.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+// UpdateLease replaces the lease if the stored one is still in from, with the status in the filter as in UpdateLeaseStatus.
+func (repo *LeaseRepo) UpdateLease(ctx context.Context, lease entities.Lease, from entities.LeaseStatus) (bool, error) {
+	result, err := repo.collection.ReplaceOne(ctx, bson.M{"_id": lease.ID, "status": from}, lease)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+func (repo *LeaseRepo) findOne(ctx context.Context, filter bson.M) (*entities.Lease, error) {
+	var lease entities.Lease
+	err := repo.collection.FindOne(ctx, filter).Decode(&lease)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &lease, nil
+}
+
+func (repo *LeaseRepo) find(ctx context.Context, filter bson.D) ([]entities.Lease, error) {
+	cursor, err := repo.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var leases []entities.Lease
+	if err = cursor.All(ctx, &leases); err != nil {
+		return nil, err
+	}
+	return leases, nil
+}
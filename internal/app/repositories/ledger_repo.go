@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+type LedgerRepo struct {
+	client   *mongo.Client
+	dues     *mongo.Collection
+	payments *mongo.Collection
+}
+
+// NewLedgerRepo initializes a new LedgerRepo on the shared MongoDB client.
+func NewLedgerRepo(client *mongo.Client, dbName string, duesCollection string, paymentsCollection string) interfaces.LedgerRepo {
+	database := client.Database(dbName)
+	return &LedgerRepo{
+		client:   client,
+		dues:     database.Collection(duesCollection),
+		payments: database.Collection(paymentsCollection),
+	}
+}
+
+// leaseDueIndex is the name of the unique index over the lease and period of rent dues.
+const leaseDueIndex = "leaseID_period"
+
+// EnsureLedgerIndexes creates the indexes of the rent due collection if they do not exist yet.
+// The unique index over lease and period makes CreateDue safe against the dues of a lease being
+// generated twice at the same time.
+func EnsureLedgerIndexes(ctx context.Context, client *mongo.Client, dbName string, duesCollection string) error {
+	index := mongo.IndexModel{
+		Keys:    bson.D{{Key: "leaseID", Value: 1}, {Key: "period", Value: 1}},
+		Options: options.Index().SetName(leaseDueIndex).SetUnique(true),
+	}
+	if _, err := client.Database(dbName).Collection(duesCollection).Indexes().CreateOne(ctx, index); err != nil {
+		return fmt.Errorf("failed to create index %s on %s: %w", leaseDueIndex, duesCollection, err)
+	}
+	return nil
+}
+
+// CreateDue saves the due unless the lease already has one for the period, which the index created by
+// EnsureLedgerIndexes rejects.
+func (repo *LedgerRepo) CreateDue(ctx context.Context, due entities.RentDue) (bool, error) {
+	if due.ID.IsZero() {
+		due.ID = primitive.NewObjectID()
+	}
+	_, err := repo.dues.InsertOne(ctx, due)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// FindDuesByLease returns the dues of the lease ordered by period.
+func (repo *LedgerRepo) FindDuesByLease(ctx context.Context, leaseID primitive.ObjectID) ([]entities.RentDue, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "period", Value: 1}})
+	cursor, err := repo.dues.Find(ctx, bson.D{{Key: "leaseID", Value: leaseID}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var dues []entities.RentDue
+	if err = cursor.All(ctx, &dues); err != nil {
+		return nil, err
+	}
+	return dues, nil
+}
+
+// SavePayment stores the payment, assigning a new ID when it has none.
+func (repo *LedgerRepo) SavePayment(ctx context.Context, payment entities.Payment) error {
+	if payment.ID.IsZero() {
+		payment.ID = primitive.NewObjectID()
+	}
+	_, err := repo.payments.InsertOne(ctx, payment)
+	return err
+}
+
+// FindPaymentsByLease returns the payments of the lease ordered by the day they were made.
+func (repo *LedgerRepo) FindPaymentsByLease(ctx context.Context, leaseID primitive.ObjectID) ([]entities.Payment, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "paidOn", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := repo.payments.Find(ctx, bson.D{{Key: "leaseID", Value: leaseID}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var payments []entities.Payment
+	if err = cursor.All(ctx, &payments); err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+type MaintenanceRepo struct {
+	client     *mongo.Client
+	collection *mongo.Collection
+}
+
+// NewMaintenanceRepo initializes a new MaintenanceRepo on the shared MongoDB client.
+func NewMaintenanceRepo(client *mongo.Client, dbName string, collectionName string) interfaces.MaintenanceRepo {
+	return &MaintenanceRepo{
+		client:     client,
+		collection: client.Database(dbName).Collection(collectionName),
+	}
+}
+
+// SaveTicket stores the ticket, replacing any ticket with the same ID.
+func (repo *MaintenanceRepo) SaveTicket(ctx context.Context, ticket entities.MaintenanceTicket) error {
+	if ticket.ID.IsZero() {
+		ticket.ID = primitive.NewObjectID()
+	}
+	if ticket.Comments == nil {
+		ticket.Comments = []entities.TicketComment{} // $push needs an array to append to
+	}
+	_, err := repo.collection.ReplaceOne(ctx, bson.M{"_id": ticket.ID}, ticket, options.Replace().SetUpsert(true))
+	return err
+}
+
+// FindTicketByID returns the ticket, or nil if there is none with the ID.
+func (repo *MaintenanceRepo) FindTicketByID(ctx context.Context, id primitive.ObjectID) (*entities.MaintenanceTicket, error) {
+	var ticket entities.MaintenanceTicket
+	err := repo.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&ticket)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+func (repo *MaintenanceRepo) FindTicketsByTenant(ctx context.Context, tenantName string) ([]entities.MaintenanceTicket, error) {
+	return repo.find(ctx, bson.D{{Key: "tenantName", Value: tenantName}})
+}
+
+func (repo *MaintenanceRepo) FindTicketsByLandlord(ctx context.Context, landlordName string) ([]entities.MaintenanceTicket, error) {
+	return repo.find(ctx, bson.D{{Key: "landlordName", Value: landlordName}})
+}
+
+// UpdateTicketStatus changes the status if the stored ticket is still in from.
+// The status is part of the filter, so of two concurrent changes from the same status only one matches,
+// and the note is pushed rather than the comments replaced, so comments added meanwhile are kept.
+// A reopened ticket is due again from its priority, which never changes, so it is read beforehand.
+func (repo *MaintenanceRepo) UpdateTicketStatus(ctx context.Context, id primitive.ObjectID, from, to entities.TicketStatus, at time.Time, note *entities.TicketComment) (bool, error) {
+	set := bson.M{"status": to, "updatedAt": at}
+	update := bson.M{}
+	switch to {
+	case entities.TicketResolved:
+		set["resolvedAt"] = at
+	case entities.TicketOpen:
+		ticket, err := repo.FindTicketByID(ctx, id)
+		if err != nil || ticket == nil {
+			return false, err
+		}
+		set["dueBy"] = at.Add(ticket.Priority.ResolveWithin())
+		update["$unset"] = bson.M{"resolvedAt": ""}
+	}
+	update["$set"] = set
+	if note != nil {
+		update["$push"] = bson.M{"comments": note}
+	}
+	result, err := repo.collection.UpdateOne(ctx, bson.M{"_id": id, "status": from}, update)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+// AddTicketComment appends the comment unless the ticket is closed.
+func (repo *MaintenanceRepo) AddTicketComment(ctx context.Context, id primitive.ObjectID, comment entities.TicketComment) (bool, error) {
+	filter := bson.M{"_id": id, "status": bson.M{"$ne": entities.TicketClosed}}
+	update := bson.M{
+		"$push": bson.M{"comments": comment},
+		"$set":  bson.M{"updatedAt": comment.PostedAt},
+	}
+	result, err := repo.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+func (repo *MaintenanceRepo) find(ctx context.Context, filter bson.D) ([]entities.MaintenanceTicket, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := repo.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var tickets []entities.MaintenanceTicket
+	if err = cursor.All(ctx, &tickets); err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
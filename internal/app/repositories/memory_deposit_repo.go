@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+// InMemoryDepositRepo is a DepositRepo that keeps security deposits in process memory.
+// It mirrors the behaviour of the MongoDB DepositRepo and is meant for local runs and tests.
+type InMemoryDepositRepo struct {
+	mu       sync.RWMutex
+	deposits map[primitive.ObjectID]entities.Deposit // By lease ID
+}
+
+// NewInMemoryDepositRepo initializes an empty in-memory DepositRepo.
+func NewInMemoryDepositRepo() interfaces.DepositRepo {
+	return &InMemoryDepositRepo{deposits: make(map[primitive.ObjectID]entities.Deposit)}
+}
+
+// CreateDeposit saves the deposit, assigning a new ID when it has none, unless its lease already has one.
+func (repo *InMemoryDepositRepo) CreateDeposit(ctx context.Context, deposit entities.Deposit) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, exists := repo.deposits[deposit.LeaseID]; exists {
+		return false, nil
+	}
+	if deposit.ID.IsZero() {
+		deposit.ID = primitive.NewObjectID()
+	}
+	repo.deposits[deposit.LeaseID] = copyDeposit(deposit)
+	return true, nil
+}
+
+// FindDepositByLease returns the deposit of the lease, or nil if none was recorded.
+func (repo *InMemoryDepositRepo) FindDepositByLease(ctx context.Context, leaseID primitive.ObjectID) (*entities.Deposit, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	deposit, ok := repo.deposits[leaseID]
+	if !ok {
+		return nil, nil
+	}
+	deposit = copyDeposit(deposit)
+	return &deposit, nil
+}
+
+// UpdateDeposit replaces the deposit if the stored one is still in from.
+func (repo *InMemoryDepositRepo) UpdateDeposit(ctx context.Context, deposit entities.Deposit, from entities.DepositStatus) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	stored, ok := repo.deposits[deposit.LeaseID]
+	if !ok || stored.ID != deposit.ID || stored.Status != from {
+		return false, nil
+	}
+	repo.deposits[deposit.LeaseID] = copyDeposit(deposit)
+	return true, nil
+}
+
+// copyDeposit copies the deductions, so that callers cannot change the stored deposit through them.
+func copyDeposit(deposit entities.Deposit) entities.Deposit {
+	deposit.Deductions = append(make([]entities.DepositDeduction, 0, len(deposit.Deductions)), deposit.Deductions...)
+	return deposit
+}
@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+// InMemoryLeaseRepo is a LeaseRepo that keeps leases in process memory.
+// It mirrors the behaviour of the MongoDB LeaseRepo and is meant for local runs and tests.
+type InMemoryLeaseRepo struct {
+	mu     sync.RWMutex
+	leases []entities.Lease
+}
+
+// NewInMemoryLeaseRepo initializes an empty in-memory LeaseRepo.
+func NewInMemoryLeaseRepo() interfaces.LeaseRepo {
+	return &InMemoryLeaseRepo{}
+}
+
+// SaveLease stores the lease, assigning a new ID when it has none and replacing any lease with the same ID.
+func (repo *InMemoryLeaseRepo) SaveLease(ctx context.Context, lease entities.Lease) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if lease.ID.IsZero() {
+		lease.ID = primitive.NewObjectID()
+	}
+	for i := range repo.leases {
+		if repo.leases[i].ID == lease.ID {
+			repo.leases[i] = lease
+			return nil
+		}
+	}
+	repo.leases = append(repo.leases, lease)
+	return nil
+}
+
+// FindLeaseByID returns a copy of the lease, or nil if there is none with the ID.
+func (repo *InMemoryLeaseRepo) FindLeaseByID(ctx context.Context, id primitive.ObjectID) (*entities.Lease, error) {
+	return repo.findOne(func(lease entities.Lease) bool {
+		return lease.ID == id
+	})
+}
+
+// FindLeaseByRequestID returns a copy of the lease drawn up for the request, or nil if there is none.
+func (repo *InMemoryLeaseRepo) FindLeaseByRequestID(ctx context.Context, requestID primitive.ObjectID) (*entities.Lease, error) {
+	return repo.findOne(func(lease entities.Lease) bool {
+		return lease.RequestID == requestID
+	})
+}
+
+// FindLeasesByTenant returns all leases of the tenant.
+func (repo *InMemoryLeaseRepo) FindLeasesByTenant(ctx context.Context, tenantName string) ([]entities.Lease, error) {
+	return repo.filter(func(lease entities.Lease) bool {
+		return lease.TenantName == tenantName
+	})
+}
+
+// FindLeasesByLandlord returns all leases of the landlord's properties.
+func (repo *InMemoryLeaseRepo) FindLeasesByLandlord(ctx context.Context, landlordName string) ([]entities.Lease, error) {
+	return repo.filter(func(lease entities.Lease) bool {
+		return lease.LandlordName == landlordName
+	})
+}
+
+// FindLeasesByStatus returns all leases in the status.
+func (repo *InMemoryLeaseRepo) FindLeasesByStatus(ctx context.Context, status entities.LeaseStatus) ([]entities.Lease, error) {
+	return repo.filter(func(lease entities.Lease) bool {
+		return lease.Status == status
+	})
+}
+
+// UpdateLeaseStatus changes the status if the stored lease is still in from.
+func (repo *InMemoryLeaseRepo) UpdateLeaseStatus(ctx context.Context, id primitive.ObjectID, from, to entities.LeaseStatus) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i := range repo.leases {
+		if repo.leases[i].ID != id {
+			continue
+		}
+		if repo.leases[i].Status != from {
+			return false, nil
+		}
+		repo.leases[i].Status = to
+		return true, nil
+	}
+	return false, nil
+}
+
+// UpdateLease replaces the lease if the stored one is still in from.
+func (repo *InMemoryLeaseRepo) UpdateLease(ctx context.Context, lease entities.Lease, from entities.LeaseStatus) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i := range repo.leases {
+		if repo.leases[i].ID != lease.ID {
+			continue
+		}
+		if repo.leases[i].Status != from {
+			return false, nil
+		}
+		repo.leases[i] = lease
+		return true, nil
+	}
+	return false, nil
+}
+
+func (repo *InMemoryLeaseRepo) findOne(match func(entities.Lease) bool) (*entities.Lease, error) {
+	leases, err := repo.filter(match)
+	if err != nil || len(leases) == 0 {
+		return nil, err
+	}
+	return &leases[0], nil
+}
+
+func (repo *InMemoryLeaseRepo) filter(match func(entities.Lease) bool) ([]entities.Lease, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	var leases []entities.Lease
+	for _, lease := range repo.leases {
+		if match(lease) {
+			leases = append(leases, lease)
+		}
+	}
+	return leases, nil
+}
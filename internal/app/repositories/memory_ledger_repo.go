@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+// InMemoryLedgerRepo is a LedgerRepo that keeps rent dues and payments in process memory.
+// It mirrors the behaviour of the MongoDB LedgerRepo and is meant for local runs and tests.
+type InMemoryLedgerRepo struct {
+	mu       sync.RWMutex
+	dues     []entities.RentDue
+	payments []entities.Payment
+}
+
+// NewInMemoryLedgerRepo initializes an empty in-memory LedgerRepo.
+func NewInMemoryLedgerRepo() interfaces.LedgerRepo {
+	return &InMemoryLedgerRepo{}
+}
+
+// CreateDue saves the due, assigning a new ID when it has none, unless the lease already has one for the period.
+func (repo *InMemoryLedgerRepo) CreateDue(ctx context.Context, due entities.RentDue) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, existing := range repo.dues {
+		if existing.LeaseID == due.LeaseID && existing.Period == due.Period {
+			return false, nil
+		}
+	}
+	if due.ID.IsZero() {
+		due.ID = primitive.NewObjectID()
+	}
+	repo.dues = append(repo.dues, due)
+	return true, nil
+}
+
+// FindDuesByLease returns the dues of the lease ordered by period.
+func (repo *InMemoryLedgerRepo) FindDuesByLease(ctx context.Context, leaseID primitive.ObjectID) ([]entities.RentDue, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	var dues []entities.RentDue
+	for _, due := range repo.dues {
+		if due.LeaseID == leaseID {
+			dues = append(dues, due)
+		}
+	}
+	sort.Slice(dues, func(i, j int) bool { return dues[i].Period < dues[j].Period })
+	return dues, nil
+}
+
+// SavePayment stores the payment, assigning a new ID when it has none.
+func (repo *InMemoryLedgerRepo) SavePayment(ctx context.Context, payment entities.Payment) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if payment.ID.IsZero() {
+		payment.ID = primitive.NewObjectID()
+	}
+	repo.payments = append(repo.payments, payment)
+	return nil
+}
+
+// FindPaymentsByLease returns the payments of the lease ordered by the day they were made.
+func (repo *InMemoryLedgerRepo) FindPaymentsByLease(ctx context.Context, leaseID primitive.ObjectID) ([]entities.Payment, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	var payments []entities.Payment
+	for _, payment := range repo.payments {
+		if payment.LeaseID == leaseID {
+			payments = append(payments, payment)
+		}
+	}
+	sort.SliceStable(payments, func(i, j int) bool { return payments[i].PaidOn.Before(payments[j].PaidOn) })
+	return payments, nil
+}
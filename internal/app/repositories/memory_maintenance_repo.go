@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+// InMemoryMaintenanceRepo is a MaintenanceRepo that keeps maintenance tickets in process memory.
+// It mirrors the behaviour of the MongoDB MaintenanceRepo and is meant for local runs and tests.
+type InMemoryMaintenanceRepo struct {
+	mu      sync.RWMutex
+	tickets []entities.MaintenanceTicket
+}
+
+// NewInMemoryMaintenanceRepo initializes an empty in-memory MaintenanceRepo.
+func NewInMemoryMaintenanceRepo() interfaces.MaintenanceRepo {
+	return &InMemoryMaintenanceRepo{}
+}
+
+// SaveTicket stores the ticket, assigning a new ID when it has none and replacing any ticket with the same ID.
+func (repo *InMemoryMaintenanceRepo) SaveTicket(ctx context.Context, ticket entities.MaintenanceTicket) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if ticket.ID.IsZero() {
+		ticket.ID = primitive.NewObjectID()
+	}
+	ticket = copyTicket(ticket)
+	for i := range repo.tickets {
+		if repo.tickets[i].ID == ticket.ID {
+			repo.tickets[i] = ticket
+			return nil
+		}
+	}
+	repo.tickets = append(repo.tickets, ticket)
+	return nil
+}
+
+// FindTicketByID returns a copy of the ticket, or nil if there is none with the ID.
+func (repo *InMemoryMaintenanceRepo) FindTicketByID(ctx context.Context, id primitive.ObjectID) (*entities.MaintenanceTicket, error) {
+	tickets, err := repo.filter(func(ticket entities.MaintenanceTicket) bool {
+		return ticket.ID == id
+	})
+	if err != nil || len(tickets) == 0 {
+		return nil, err
+	}
+	return &tickets[0], nil
+}
+
+// FindTicketsByTenant returns all tickets the tenant reported.
+func (repo *InMemoryMaintenanceRepo) FindTicketsByTenant(ctx context.Context, tenantName string) ([]entities.MaintenanceTicket, error) {
+	return repo.filter(func(ticket entities.MaintenanceTicket) bool {
+		return ticket.TenantName == tenantName
+	})
+}
+
+// FindTicketsByLandlord returns all tickets reported on the landlord's properties.
+func (repo *InMemoryMaintenanceRepo) FindTicketsByLandlord(ctx context.Context, landlordName string) ([]entities.MaintenanceTicket, error) {
+	return repo.filter(func(ticket entities.MaintenanceTicket) bool {
+		return ticket.LandlordName == landlordName
+	})
+}
+
+// UpdateTicketStatus changes the status if the stored ticket is still in from.
+func (repo *InMemoryMaintenanceRepo) UpdateTicketStatus(ctx context.Context, id primitive.ObjectID, from, to entities.TicketStatus, at time.Time, note *entities.TicketComment) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i := range repo.tickets {
+		if repo.tickets[i].ID != id {
+			continue
+		}
+		if repo.tickets[i].Status != from {
+			return false, nil
+		}
+		changeTicketStatus(&repo.tickets[i], to, at, note)
+		return true, nil
+	}
+	return false, nil
+}
+
+// AddTicketComment appends the comment unless the ticket is closed.
+func (repo *InMemoryMaintenanceRepo) AddTicketComment(ctx context.Context, id primitive.ObjectID, comment entities.TicketComment) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i := range repo.tickets {
+		if repo.tickets[i].ID != id {
+			continue
+		}
+		if repo.tickets[i].Status == entities.TicketClosed {
+			return false, nil
+		}
+		repo.tickets[i].Comments = append(repo.tickets[i].Comments, comment)
+		repo.tickets[i].UpdatedAt = comment.PostedAt
+		return true, nil
+	}
+	return false, nil
+}
+
+// filter returns copies of the tickets matching the predicate, ordered by when they were reported.
+func (repo *InMemoryMaintenanceRepo) filter(match func(entities.MaintenanceTicket) bool) ([]entities.MaintenanceTicket, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	var tickets []entities.MaintenanceTicket
+	for _, ticket := range repo.tickets {
+		if match(ticket) {
+			tickets = append(tickets, copyTicket(ticket))
+		}
+	}
+	sortTickets(tickets)
+	return tickets, nil
+}
+
+// changeTicketStatus applies a status change the way the MongoDB MaintenanceRepo's update does.
+func changeTicketStatus(ticket *entities.MaintenanceTicket, to entities.TicketStatus, at time.Time, note *entities.TicketComment) {
+	ticket.Status = to
+	ticket.UpdatedAt = at
+	switch to {
+	case entities.TicketResolved:
+		ticket.ResolvedAt = &at
+	case entities.TicketOpen:
+		ticket.ResolvedAt = nil
+		ticket.DueBy = at.Add(ticket.Priority.ResolveWithin())
+	}
+	if note != nil {
+		ticket.Comments = append(ticket.Comments, *note)
+	}
+}
+
+// sortTickets orders tickets by when they were reported, then by ID.
+func sortTickets(tickets []entities.MaintenanceTicket) {
+	sort.SliceStable(tickets, func(i, j int) bool {
+		if !tickets[i].CreatedAt.Equal(tickets[j].CreatedAt) {
+			return tickets[i].CreatedAt.Before(tickets[j].CreatedAt)
+		}
+		return tickets[i].ID.Hex() < tickets[j].ID.Hex()
+	})
+}
+
+// copyTicket copies the comments, so that callers cannot change the stored ticket through them.
+func copyTicket(ticket entities.MaintenanceTicket) entities.MaintenanceTicket {
+	ticket.Comments = append(make([]entities.TicketComment, 0, len(ticket.Comments)), ticket.Comments...)
+	return ticket
+}
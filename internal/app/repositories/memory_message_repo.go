@@ -0,0 +1,156 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+// InMemoryMessageRepo is a MessageRepo that keeps message threads in process memory.
+// It mirrors the behaviour of the MongoDB MessageRepo and is meant for local runs and tests.
+type InMemoryMessageRepo struct {
+	mu      sync.RWMutex
+	threads []entities.Thread
+}
+
+// NewInMemoryMessageRepo initializes an empty in-memory MessageRepo.
+func NewInMemoryMessageRepo() interfaces.MessageRepo {
+	return &InMemoryMessageRepo{}
+}
+
+// CreateThread stores the thread, assigning a new ID when it has none, unless the tenant already has a
+// thread about the property.
+func (repo *InMemoryMessageRepo) CreateThread(ctx context.Context, thread entities.Thread) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, stored := range repo.threads {
+		if stored.PropertyID == thread.PropertyID && stored.TenantName == thread.TenantName {
+			return false, nil
+		}
+	}
+	if thread.ID.IsZero() {
+		thread.ID = primitive.NewObjectID()
+	}
+	repo.threads = append(repo.threads, copyThread(thread))
+	return true, nil
+}
+
+// FindThreadByID returns a copy of the thread, or nil if there is none with the ID.
+func (repo *InMemoryMessageRepo) FindThreadByID(ctx context.Context, id primitive.ObjectID) (*entities.Thread, error) {
+	return repo.first(func(thread entities.Thread) bool {
+		return thread.ID == id
+	})
+}
+
+// FindThread returns a copy of the tenant's thread about the property, or nil if they have none.
+func (repo *InMemoryMessageRepo) FindThread(ctx context.Context, propertyID primitive.ObjectID, tenantName string) (*entities.Thread, error) {
+	return repo.first(func(thread entities.Thread) bool {
+		return thread.PropertyID == propertyID && thread.TenantName == tenantName
+	})
+}
+
+// FindThreadsByUser returns all threads the user is a party to.
+func (repo *InMemoryMessageRepo) FindThreadsByUser(ctx context.Context, username string) ([]entities.Thread, error) {
+	return repo.filter(func(thread entities.Thread) bool {
+		return thread.HasParty(username)
+	})
+}
+
+// AddMessage appends the message if its sender is a party to the thread.
+func (repo *InMemoryMessageRepo) AddMessage(ctx context.Context, threadID primitive.ObjectID, message entities.Message) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i := range repo.threads {
+		if repo.threads[i].ID == threadID {
+			return addMessage(&repo.threads[i], message), nil
+		}
+	}
+	return false, nil
+}
+
+// MarkThreadRead clears the unread count of the user if they are a party to the thread.
+func (repo *InMemoryMessageRepo) MarkThreadRead(ctx context.Context, threadID primitive.ObjectID, username string) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i := range repo.threads {
+		if repo.threads[i].ID == threadID {
+			return markThreadRead(&repo.threads[i], username), nil
+		}
+	}
+	return false, nil
+}
+
+func (repo *InMemoryMessageRepo) first(match func(entities.Thread) bool) (*entities.Thread, error) {
+	threads, err := repo.filter(match)
+	if err != nil || len(threads) == 0 {
+		return nil, err
+	}
+	return &threads[0], nil
+}
+
+// filter returns copies of the threads matching the predicate, latest message first.
+func (repo *InMemoryMessageRepo) filter(match func(entities.Thread) bool) ([]entities.Thread, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	var threads []entities.Thread
+	for _, thread := range repo.threads {
+		if match(thread) {
+			threads = append(threads, copyThread(thread))
+		}
+	}
+	sortThreads(threads)
+	return threads, nil
+}
+
+// addMessage applies a new message the way the MongoDB MessageRepo's update does.
+func addMessage(thread *entities.Thread, message entities.Message) bool {
+	switch message.Sender {
+	case thread.TenantName:
+		thread.LandlordUnread++
+	case thread.LandlordName:
+		thread.TenantUnread++
+	default:
+		return false
+	}
+	thread.Messages = append(thread.Messages, message)
+	thread.LastMessageAt = message.SentAt
+	return true
+}
+
+// markThreadRead clears the unread count of the user the way the MongoDB MessageRepo's update does.
+func markThreadRead(thread *entities.Thread, username string) bool {
+	switch username {
+	case thread.TenantName:
+		thread.TenantUnread = 0
+	case thread.LandlordName:
+		thread.LandlordUnread = 0
+	default:
+		return false
+	}
+	return true
+}
+
+// sortThreads orders threads by their latest message, the most recent first, then by ID.
+func sortThreads(threads []entities.Thread) {
+	sort.SliceStable(threads, func(i, j int) bool {
+		if !threads[i].LastMessageAt.Equal(threads[j].LastMessageAt) {
+			return threads[i].LastMessageAt.After(threads[j].LastMessageAt)
+		}
+		return threads[i].ID.Hex() < threads[j].ID.Hex()
+	})
+}
+
+// copyThread copies the messages, so that callers cannot change the stored thread through them.
+func copyThread(thread entities.Thread) entities.Thread {
+	thread.Messages = append(make([]entities.Message, 0, len(thread.Messages)), thread.Messages...)
+	return thread
+}
@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+// InMemoryNotificationRepo is a NotificationRepo that keeps notifications in process memory.
+// It mirrors the behaviour of the MongoDB NotificationRepo and is meant for local runs and tests.
+type InMemoryNotificationRepo struct {
+	mu            sync.RWMutex
+	notifications []entities.Notification
+}
+
+// NewInMemoryNotificationRepo initializes an empty in-memory NotificationRepo.
+func NewInMemoryNotificationRepo() interfaces.NotificationRepo {
+	return &InMemoryNotificationRepo{}
+}
+
+// SaveNotification stores the notification, assigning a new ID when it has none.
+func (repo *InMemoryNotificationRepo) SaveNotification(ctx context.Context, notification entities.Notification) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if notification.ID.IsZero() {
+		notification.ID = primitive.NewObjectID()
+	}
+	repo.notifications = append(repo.notifications, notification)
+	return nil
+}
+
+// FindNotificationsByUser returns copies of the notifications of the user, newest first.
+func (repo *InMemoryNotificationRepo) FindNotificationsByUser(ctx context.Context, username string) ([]entities.Notification, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	var notifications []entities.Notification
+	for _, notification := range repo.notifications {
+		if notification.Username == username {
+			notifications = append(notifications, notification)
+		}
+	}
+	sortNotifications(notifications)
+	return notifications, nil
+}
+
+// MarkNotificationRead marks the notification read if it belongs to the user.
+func (repo *InMemoryNotificationRepo) MarkNotificationRead(ctx context.Context, id primitive.ObjectID, username string) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i := range repo.notifications {
+		if repo.notifications[i].ID == id && repo.notifications[i].Username == username {
+			repo.notifications[i].Read = true
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// MarkAllNotificationsRead marks the unread notifications of the user read.
+func (repo *InMemoryNotificationRepo) MarkAllNotificationsRead(ctx context.Context, username string) (int, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	marked := 0
+	for i := range repo.notifications {
+		if repo.notifications[i].Username == username && !repo.notifications[i].Read {
+			repo.notifications[i].Read = true
+			marked++
+		}
+	}
+	return marked, nil
+}
+
+// DeleteNotification removes the notification if it belongs to the user.
+func (repo *InMemoryNotificationRepo) DeleteNotification(ctx context.Context, id primitive.ObjectID, username string) (bool, error) {
+	deleted := repo.deleteWhere(func(notification entities.Notification) bool {
+		return notification.ID == id && notification.Username == username
+	})
+	return deleted == 1, nil
+}
+
+// DeleteReadNotifications removes the notifications the user has read.
+func (repo *InMemoryNotificationRepo) DeleteReadNotifications(ctx context.Context, username string) (int, error) {
+	return repo.deleteWhere(func(notification entities.Notification) bool {
+		return notification.Username == username && notification.Read
+	}), nil
+}
+
+// deleteWhere removes the notifications matching the predicate and returns how many there were.
+func (repo *InMemoryNotificationRepo) deleteWhere(match func(entities.Notification) bool) int {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	kept := repo.notifications[:0]
+	for _, notification := range repo.notifications {
+		if !match(notification) {
+			kept = append(kept, notification)
+		}
+	}
+	deleted := len(repo.notifications) - len(kept)
+	repo.notifications = kept
+	return deleted
+}
+
+// sortNotifications orders notifications by when they were created, the newest first, then by ID.
+func sortNotifications(notifications []entities.Notification) {
+	sort.SliceStable(notifications, func(i, j int) bool {
+		if !notifications[i].CreatedAt.Equal(notifications[j].CreatedAt) {
+			return notifications[i].CreatedAt.After(notifications[j].CreatedAt)
+		}
+		return notifications[i].ID.Hex() > notifications[j].ID.Hex()
+	})
+}
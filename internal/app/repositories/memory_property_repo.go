@@ -0,0 +1,227 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+// InMemoryPropertyRepo is a PropertyRepo that keeps properties in process memory.
+// It mirrors the behaviour of the MongoDB PropertyRepo and is meant for local runs and tests.
+type InMemoryPropertyRepo struct {
+	mu         sync.RWMutex
+	properties []entities.Property
+}
+
+// NewInMemoryPropertyRepo initializes an empty in-memory PropertyRepo.
+func NewInMemoryPropertyRepo() interfaces.PropertyRepo {
+	return &InMemoryPropertyRepo{}
+}
+
+// SaveProperty stores a copy of the property. Property IDs must be unique.
+func (r *InMemoryPropertyRepo) SaveProperty(ctx context.Context, property entities.Property) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.properties {
+		if existing.ID == property.ID {
+			return fmt.Errorf("property with ID %s already exists", property.ID.Hex())
+		}
+	}
+
+	stored, err := copyProperty(property)
+	if err != nil {
+		return err
+	}
+	r.properties = append(r.properties, stored)
+	return nil
+}
+
+// GetAllListedProperties retrieves the properties of the given landlord.
+// If `landlordUsername` is empty, it returns all properties that are not rented.
+func (r *InMemoryPropertyRepo) GetAllListedProperties(ctx context.Context, landlordUsername string) ([]entities.Property, error) {
+	return r.filter(func(property entities.Property) bool {
+		if landlordUsername != "" {
+			return property.LandlordUsername == landlordUsername
+		}
+		return !property.IsRented
+	})
+}
+
+// UpdateListedProperty updates the editable fields of an existing property.
+func (r *InMemoryPropertyRepo) UpdateListedProperty(ctx context.Context, property entities.Property) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	details, err := copyDetails(property.Details)
+	if err != nil {
+		return err
+	}
+
+	for i := range r.properties {
+		if r.properties[i].ID == property.ID {
+			r.properties[i].Title = property.Title
+			r.properties[i].Address = property.Address
+			r.properties[i].RentAmount = property.RentAmount
+			r.properties[i].Deposit = property.Deposit
+			r.properties[i].IsApprovedByAdmin = property.IsApprovedByAdmin
+			r.properties[i].IsRented = property.IsRented
+			r.properties[i].Details = details
+			return nil
+		}
+	}
+	return nil
+}
+
+// DeleteListedProperty deletes the property with the given ID. Deleting an unknown property is not an error.
+func (r *InMemoryPropertyRepo) DeleteListedProperty(ctx context.Context, propertyID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, property := range r.properties {
+		if property.ID == propertyID {
+			r.properties = append(r.properties[:i], r.properties[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// FindByID retrieves a property by its ID, or nil if there is none.
+func (r *InMemoryPropertyRepo) FindByID(ctx context.Context, id primitive.ObjectID) (*entities.Property, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, property := range r.properties {
+		if property.ID == id {
+			found, err := copyProperty(property)
+			if err != nil {
+				return nil, err
+			}
+			return &found, nil
+		}
+	}
+	return nil, nil // No property found
+}
+
+// UpdateApprovalStatus sets the approval flag of a property.
+func (r *InMemoryPropertyRepo) UpdateApprovalStatus(ctx context.Context, propertyID primitive.ObjectID, approved bool, adminUsername string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.properties {
+		if r.properties[i].ID == propertyID {
+			r.properties[i].IsApprovedByAdmin = approved
+			return nil
+		}
+	}
+	return nil
+}
+
+// SetRented changes whether the property is rented if it is not in that state already.
+func (r *InMemoryPropertyRepo) SetRented(ctx context.Context, propertyID primitive.ObjectID, rented bool) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.properties {
+		if r.properties[i].ID == propertyID {
+			if r.properties[i].IsRented == rented {
+				return false, nil
+			}
+			r.properties[i].IsRented = rented
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// FindPendingProperties returns all properties not yet approved by an admin.
+func (r *InMemoryPropertyRepo) FindPendingProperties(ctx context.Context) ([]entities.Property, error) {
+	return r.filter(func(property entities.Property) bool {
+		return !property.IsApprovedByAdmin
+	})
+}
+
+// DeleteAllListedPropertiesOfaUser deletes all the listed properties of the given landlord.
+func (r *InMemoryPropertyRepo) DeleteAllListedPropertiesOfaUser(ctx context.Context, username string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.properties[:0]
+	for _, property := range r.properties {
+		if property.LandlordUsername != username {
+			kept = append(kept, property)
+		}
+	}
+	r.properties = kept
+	return nil
+}
+
+// filter returns copies of all properties matching the predicate, in insertion order.
+func (r *InMemoryPropertyRepo) filter(match func(entities.Property) bool) ([]entities.Property, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var properties []entities.Property
+	for _, property := range r.properties {
+		if !match(property) {
+			continue
+		}
+		found, err := copyProperty(property)
+		if err != nil {
+			return nil, err
+		}
+		properties = append(properties, found)
+	}
+	return properties, nil
+}
+
+// copyProperty returns a copy of the property that shares no slices with the original.
+func copyProperty(property entities.Property) (entities.Property, error) {
+	if property.Applications != nil {
+		property.Applications = append([]string{}, property.Applications...)
+	}
+	details, err := copyDetails(property.Details)
+	if err != nil {
+		return entities.Property{}, err
+	}
+	property.Details = details
+	return property, nil
+}
+
+// copyDetails copies one of the typed property details, accepting pointers as the
+// MongoDB driver does. Any other type is rejected since it could not be read back.
+func copyDetails(details interface{}) (interface{}, error) {
+	switch d := details.(type) {
+	case nil:
+		return nil, nil
+	case entities.CommercialDetails:
+		return d, nil
+	case *entities.CommercialDetails:
+		return *d, nil
+	case entities.HouseDetails:
+		d.Amenities = copyStrings(d.Amenities)
+		return d, nil
+	case *entities.HouseDetails:
+		return copyDetails(*d)
+	case entities.FlatDetails:
+		d.Amenities = copyStrings(d.Amenities)
+		return d, nil
+	case *entities.FlatDetails:
+		return copyDetails(*d)
+	default:
+		return nil, fmt.Errorf("unsupported property details type %T", details)
+	}
+}
+
+func copyStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	return append([]string{}, values...)
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+// InMemoryRefreshTokenRepo is a RefreshTokenRepo that keeps tokens in process memory.
+type InMemoryRefreshTokenRepo struct {
+	mu     sync.RWMutex
+	tokens map[string]entities.RefreshToken
+}
+
+// NewInMemoryRefreshTokenRepo initializes an empty in-memory RefreshTokenRepo.
+func NewInMemoryRefreshTokenRepo() interfaces.RefreshTokenRepo {
+	return &InMemoryRefreshTokenRepo{tokens: make(map[string]entities.RefreshToken)}
+}
+
+// SaveRefreshToken stores the token, replacing any token with the same ID.
+func (repo *InMemoryRefreshTokenRepo) SaveRefreshToken(ctx context.Context, token entities.RefreshToken) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.tokens[token.ID] = token
+	return nil
+}
+
+// RotateRefreshToken replaces the token if it still has the previous hash and is not revoked.
+func (repo *InMemoryRefreshTokenRepo) RotateRefreshToken(ctx context.Context, token entities.RefreshToken, previousHash string) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	stored, ok := repo.tokens[token.ID]
+	if !ok || stored.Revoked || stored.TokenHash != previousHash {
+		return false, nil
+	}
+	repo.tokens[token.ID] = token
+	return true, nil
+}
+
+// FindRefreshToken returns the token with the given ID, or nil if there is none.
+func (repo *InMemoryRefreshTokenRepo) FindRefreshToken(ctx context.Context, id string) (*entities.RefreshToken, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	token, ok := repo.tokens[id]
+	if !ok {
+		return nil, nil
+	}
+	return &token, nil
+}
+
+// RevokeRefreshToken revokes the token with the given ID. Revoking an unknown token is not an error.
+func (repo *InMemoryRefreshTokenRepo) RevokeRefreshToken(ctx context.Context, id string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if token, ok := repo.tokens[id]; ok {
+		token.Revoked = true
+		repo.tokens[id] = token
+	}
+	return nil
+}
+
+// RevokeUserRefreshTokens revokes every token of the user.
+func (repo *InMemoryRefreshTokenRepo) RevokeUserRefreshTokens(ctx context.Context, username string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for id, token := range repo.tokens {
+		if token.Username == username {
+			token.Revoked = true
+			repo.tokens[id] = token
+		}
+	}
+	return nil
+}
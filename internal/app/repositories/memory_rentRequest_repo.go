@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+// InMemoryRequestRepo is a RequestRepo that keeps rent requests in process memory.
+// It mirrors the behaviour of the MongoDB RequestRepo and is meant for local runs and tests.
+type InMemoryRequestRepo struct {
+	mu       sync.RWMutex
+	requests []entities.Request
+}
+
+// NewInMemoryRequestRepo initializes an empty in-memory RequestRepo.
+func NewInMemoryRequestRepo() interfaces.RequestRepo {
+	return &InMemoryRequestRepo{}
+}
+
+// SaveRequest stores the request, assigning a new ID when it has none.
+func (repo *InMemoryRequestRepo) SaveRequest(ctx context.Context, request entities.Request) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if request.ID.IsZero() {
+		request.ID = primitive.NewObjectID()
+	}
+	repo.requests = append(repo.requests, request)
+	return nil
+}
+
+// CreateRequest saves the request unless the tenant already has an open request for the property.
+func (repo *InMemoryRequestRepo) CreateRequest(ctx context.Context, request entities.Request) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, stored := range repo.requests {
+		if stored.TenantName == request.TenantName && stored.PropertyID == request.PropertyID && !stored.RequestStatus.IsFinal() {
+			return false, nil
+		}
+	}
+	if request.ID.IsZero() {
+		request.ID = primitive.NewObjectID()
+	}
+	repo.requests = append(repo.requests, request)
+	return true, nil
+}
+
+// FindByTenantUsername returns all requests made by the tenant.
+func (repo *InMemoryRequestRepo) FindByTenantUsername(ctx context.Context, tenantUsername string) ([]entities.Request, error) {
+	return repo.filter(func(request entities.Request) bool {
+		return request.TenantName == tenantUsername
+	})
+}
+
+// FindByLandlordName returns all requests made for the landlord's properties.
+func (repo *InMemoryRequestRepo) FindByLandlordName(ctx context.Context, landlordName string) ([]entities.Request, error) {
+	return repo.filter(func(request entities.Request) bool {
+		return request.LandlordName == landlordName
+	})
+}
+
+// FindByStatus returns all requests with the status.
+func (repo *InMemoryRequestRepo) FindByStatus(ctx context.Context, status entities.RequestStatus) ([]entities.Request, error) {
+	return repo.filter(func(request entities.Request) bool {
+		return request.RequestStatus == status
+	})
+}
+
+// FindByPropertyID returns all requests made for the property.
+func (repo *InMemoryRequestRepo) FindByPropertyID(ctx context.Context, propertyID primitive.ObjectID) ([]entities.Request, error) {
+	return repo.filter(func(request entities.Request) bool {
+		return request.PropertyID == propertyID
+	})
+}
+
+// FindRequestByID returns a copy of the request, or nil if there is none with the ID.
+func (repo *InMemoryRequestRepo) FindRequestByID(ctx context.Context, id primitive.ObjectID) (*entities.Request, error) {
+	requests, err := repo.filter(func(request entities.Request) bool {
+		return request.ID == id
+	})
+	if err != nil || len(requests) == 0 {
+		return nil, err
+	}
+	return &requests[0], nil
+}
+
+// UpdateRequestStatus applies the change if the stored request is still in change.From.
+func (repo *InMemoryRequestRepo) UpdateRequestStatus(ctx context.Context, id primitive.ObjectID, change entities.StatusChange) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i := range repo.requests {
+		stored := &repo.requests[i]
+		if stored.ID != id {
+			continue
+		}
+		if stored.RequestStatus != change.From {
+			return false, nil
+		}
+		stored.RequestStatus = change.To
+		// Copy the history, as copies of the request handed out earlier share its array
+		stored.History = append(append([]entities.StatusChange(nil), stored.History...), change)
+		return true, nil
+	}
+	return false, nil
+}
+
+func (repo *InMemoryRequestRepo) filter(match func(entities.Request) bool) ([]entities.Request, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	var requests []entities.Request
+	for _, request := range repo.requests {
+		if match(request) {
+			requests = append(requests, request)
+		}
+	}
+	return requests, nil
+}
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+	"rentease/pkg/utils"
+)
+
+// InMemoryUserRepo is a UserRepo that keeps users in process memory.
+// It mirrors the behaviour of the MongoDB UserRepo and is meant for local runs and tests.
+type InMemoryUserRepo struct {
+	mu    sync.RWMutex
+	users []entities.User
+}
+
+// NewInMemoryUserRepo initializes an empty in-memory UserRepo.
+func NewInMemoryUserRepo() interfaces.UserRepo {
+	return &InMemoryUserRepo{}
+}
+
+// SaveUser stores a copy of the user.
+func (repo *InMemoryUserRepo) SaveUser(ctx context.Context, user entities.User) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.users = append(repo.users, copyUser(user))
+	return nil
+}
+
+// FindByUsername returns the user with the given username, or nil if there is none.
+func (repo *InMemoryUserRepo) FindByUsername(ctx context.Context, username string) (*entities.User, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	for _, user := range repo.users {
+		if user.Username == username {
+			found := copyUser(user)
+			return &found, nil
+		}
+	}
+	return nil, nil // No user found
+}
+
+// CheckPassword verifies the user's password.
+func (repo *InMemoryUserRepo) CheckPassword(ctx context.Context, username, password string) (bool, error) {
+	user, err := repo.FindByUsername(ctx, username)
+	if err != nil {
+		return false, err
+	}
+	if user == nil {
+		return false, nil // User not found
+	}
+
+	return utils.CheckPasswordHash(password, user.PasswordHash), nil
+}
+
+// UpdateUser replaces the stored user that has the same username.
+func (repo *InMemoryUserRepo) UpdateUser(ctx context.Context, user entities.User) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i := range repo.users {
+		if repo.users[i].Username == user.Username {
+			repo.users[i] = copyUser(user)
+			return nil
+		}
+	}
+	return errors.New("user not found")
+}
+
+// FindAll returns every stored user in insertion order.
+func (repo *InMemoryUserRepo) FindAll(ctx context.Context) ([]entities.User, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	var users []entities.User
+	for _, user := range repo.users {
+		users = append(users, copyUser(user))
+	}
+	return users, nil
+}
+
+// Delete removes the user with the given username. Deleting an unknown user is not an error.
+func (repo *InMemoryUserRepo) Delete(ctx context.Context, username string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i, user := range repo.users {
+		if user.Username == username {
+			repo.users = append(repo.users[:i], repo.users[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// copyUser returns a copy of the user that does not share its wishlist with the original.
+func copyUser(user entities.User) entities.User {
+	if user.Wishlist != nil {
+		user.Wishlist = append([]primitive.ObjectID{}, user.Wishlist...)
+	}
+	return user
+}
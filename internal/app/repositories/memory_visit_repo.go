@@ -0,0 +1,168 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+// InMemoryVisitRepo is a VisitRepo that keeps visit slots in process memory.
+// It mirrors the behaviour of the MongoDB VisitRepo and is meant for local runs and tests.
+type InMemoryVisitRepo struct {
+	mu    sync.RWMutex
+	slots []entities.VisitSlot
+}
+
+// NewInMemoryVisitRepo initializes an empty in-memory VisitRepo.
+func NewInMemoryVisitRepo() interfaces.VisitRepo {
+	return &InMemoryVisitRepo{}
+}
+
+// SaveSlot stores the slot, assigning a new ID when it has none and replacing any slot with the same ID.
+func (repo *InMemoryVisitRepo) SaveSlot(ctx context.Context, slot entities.VisitSlot) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if slot.ID.IsZero() {
+		slot.ID = primitive.NewObjectID()
+	}
+	for i := range repo.slots {
+		if repo.slots[i].ID == slot.ID {
+			repo.slots[i] = slot
+			return nil
+		}
+	}
+	repo.slots = append(repo.slots, slot)
+	return nil
+}
+
+// FindSlotByID returns a copy of the slot, or nil if there is none with the ID.
+func (repo *InMemoryVisitRepo) FindSlotByID(ctx context.Context, id primitive.ObjectID) (*entities.VisitSlot, error) {
+	slots, err := repo.filter(func(slot entities.VisitSlot) bool {
+		return slot.ID == id
+	})
+	if err != nil || len(slots) == 0 {
+		return nil, err
+	}
+	return &slots[0], nil
+}
+
+// FindSlotsByProperty returns all slots offered for the property.
+func (repo *InMemoryVisitRepo) FindSlotsByProperty(ctx context.Context, propertyID primitive.ObjectID) ([]entities.VisitSlot, error) {
+	return repo.filter(func(slot entities.VisitSlot) bool {
+		return slot.PropertyID == propertyID
+	})
+}
+
+// FindSlotsByLandlord returns all slots the landlord offered.
+func (repo *InMemoryVisitRepo) FindSlotsByLandlord(ctx context.Context, landlordName string) ([]entities.VisitSlot, error) {
+	return repo.filter(func(slot entities.VisitSlot) bool {
+		return slot.LandlordName == landlordName
+	})
+}
+
+// FindSlotsByTenant returns all slots the tenant booked and still holds or that were cancelled on them.
+func (repo *InMemoryVisitRepo) FindSlotsByTenant(ctx context.Context, tenantName string) ([]entities.VisitSlot, error) {
+	return repo.filter(func(slot entities.VisitSlot) bool {
+		return slot.TenantName == tenantName
+	})
+}
+
+// BookSlot books the slot for the tenant if it is still open.
+func (repo *InMemoryVisitRepo) BookSlot(ctx context.Context, id primitive.ObjectID, tenantName string, at time.Time) (bool, error) {
+	return repo.update(id, func(slot *entities.VisitSlot) bool {
+		return bookSlot(slot, tenantName, at)
+	})
+}
+
+// FreeSlot opens the slot again if it is still booked by the tenant.
+func (repo *InMemoryVisitRepo) FreeSlot(ctx context.Context, id primitive.ObjectID, tenantName string, at time.Time) (bool, error) {
+	return repo.update(id, func(slot *entities.VisitSlot) bool {
+		return freeSlot(slot, tenantName, at)
+	})
+}
+
+// CancelSlot cancels the slot if it is still in from.
+func (repo *InMemoryVisitRepo) CancelSlot(ctx context.Context, id primitive.ObjectID, from entities.VisitStatus, by string, at time.Time) (bool, error) {
+	return repo.update(id, func(slot *entities.VisitSlot) bool {
+		return cancelSlot(slot, from, by, at)
+	})
+}
+
+// update lets change modify the stored slot under the lock, and reports whether it did.
+func (repo *InMemoryVisitRepo) update(id primitive.ObjectID, change func(*entities.VisitSlot) bool) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i := range repo.slots {
+		if repo.slots[i].ID == id {
+			return change(&repo.slots[i]), nil
+		}
+	}
+	return false, nil
+}
+
+// filter returns copies of the slots matching the predicate, ordered by their start.
+func (repo *InMemoryVisitRepo) filter(match func(entities.VisitSlot) bool) ([]entities.VisitSlot, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	var slots []entities.VisitSlot
+	for _, slot := range repo.slots {
+		if match(slot) {
+			slots = append(slots, slot)
+		}
+	}
+	sortSlots(slots)
+	return slots, nil
+}
+
+// bookSlot, freeSlot and cancelSlot apply a change the way the MongoDB VisitRepo's updates do,
+// reporting false and changing nothing when the slot does not match their filter.
+func bookSlot(slot *entities.VisitSlot, tenantName string, at time.Time) bool {
+	if slot.Status != entities.VisitOpen {
+		return false
+	}
+	slot.Status = entities.VisitBooked
+	slot.TenantName = tenantName
+	slot.BookedAt = &at
+	slot.UpdatedAt = at
+	return true
+}
+
+func freeSlot(slot *entities.VisitSlot, tenantName string, at time.Time) bool {
+	if slot.Status != entities.VisitBooked || slot.TenantName != tenantName {
+		return false
+	}
+	slot.Status = entities.VisitOpen
+	slot.TenantName = ""
+	slot.BookedAt = nil
+	slot.UpdatedAt = at
+	return true
+}
+
+func cancelSlot(slot *entities.VisitSlot, from entities.VisitStatus, by string, at time.Time) bool {
+	if slot.Status != from {
+		return false
+	}
+	slot.Status = entities.VisitCancelled
+	slot.CancelledBy = by
+	slot.UpdatedAt = at
+	return true
+}
+
+// sortSlots orders slots by their start, then by ID.
+func sortSlots(slots []entities.VisitSlot) {
+	sort.SliceStable(slots, func(i, j int) bool {
+		if !slots[i].Start.Equal(slots[j].Start) {
+			return slots[i].Start.Before(slots[j].Start)
+		}
+		return slots[i].ID.Hex() < slots[j].ID.Hex()
+	})
+}
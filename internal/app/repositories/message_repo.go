@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+type MessageRepo struct {
+	client     *mongo.Client
+	collection *mongo.Collection
+}
+
+// NewMessageRepo initializes a new MessageRepo on the shared MongoDB client.
+func NewMessageRepo(client *mongo.Client, dbName string, collectionName string) interfaces.MessageRepo {
+	return &MessageRepo{
+		client:     client,
+		collection: client.Database(dbName).Collection(collectionName),
+	}
+}
+
+// threadPartiesIndex is the name of the unique index over the property and tenant of message threads.
+const threadPartiesIndex = "propertyID_tenantName"
+
+// EnsureMessageIndexes creates the indexes of the message thread collection if they do not exist yet.
+// The unique index over the property and tenant makes CreateThread safe against a thread being started twice at the same time.
+func EnsureMessageIndexes(ctx context.Context, client *mongo.Client, dbName string, collectionName string) error {
+	index := mongo.IndexModel{
+		Keys:    bson.D{{Key: "propertyID", Value: 1}, {Key: "tenantName", Value: 1}},
+		Options: options.Index().SetName(threadPartiesIndex).SetUnique(true),
+	}
+	if _, err := client.Database(dbName).Collection(collectionName).Indexes().CreateOne(ctx, index); err != nil {
+		return fmt.Errorf("failed to create index %s on %s: %w", threadPartiesIndex, collectionName, err)
+	}
+	return nil
+}
+
+// CreateThread saves the thread unless the tenant already has one about the property, which the index
+// created by EnsureMessageIndexes rejects.
+func (repo *MessageRepo) CreateThread(ctx context.Context, thread entities.Thread) (bool, error) {
+	if thread.ID.IsZero() {
+		thread.ID = primitive.NewObjectID()
+	}
+	if thread.Messages == nil {
+		thread.Messages = []entities.Message{} // $push needs an array to append to
+	}
+	_, err := repo.collection.InsertOne(ctx, thread)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// FindThreadByID returns the thread, or nil if there is none with the ID.
+func (repo *MessageRepo) FindThreadByID(ctx context.Context, id primitive.ObjectID) (*entities.Thread, error) {
+	return repo.findOne(ctx, bson.M{"_id": id})
+}
+
+// FindThread returns the tenant's thread about the property, or nil if they have none.
+func (repo *MessageRepo) FindThread(ctx context.Context, propertyID primitive.ObjectID, tenantName string) (*entities.Thread, error) {
+	return repo.findOne(ctx, bson.M{"propertyID": propertyID, "tenantName": tenantName})
+}
+
+func (repo *MessageRepo) FindThreadsByUser(ctx context.Context, username string) ([]entities.Thread, error) {
+	filter := bson.M{"$or": bson.A{bson.M{"tenantName": username}, bson.M{"landlordName": username}}}
+	opts := options.Find().SetSort(bson.D{{Key: "lastMessageAt", Value: -1}, {Key: "_id", Value: 1}})
+	cursor, err := repo.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var threads []entities.Thread
+	if err = cursor.All(ctx, &threads); err != nil {
+		return nil, err
+	}
+	return threads, nil
+}
+
+// AddMessage appends the message if its sender is a party to the thread. The sender is part of the
+// filter, so the message is pushed and the other party's count raised in one update.
+func (repo *MessageRepo) AddMessage(ctx context.Context, threadID primitive.ObjectID, message entities.Message) (bool, error) {
+	update := func(unread string) bson.M {
+		return bson.M{
+			"$push": bson.M{"messages": message},
+			"$set":  bson.M{"lastMessageAt": message.SentAt},
+			"$inc":  bson.M{unread: 1},
+		}
+	}
+	return repo.updateAsParty(ctx, threadID, message.Sender, update("landlordUnread"), update("tenantUnread"))
+}
+
+// MarkThreadRead clears the unread count of the user if they are a party to the thread.
+func (repo *MessageRepo) MarkThreadRead(ctx context.Context, threadID primitive.ObjectID, username string) (bool, error) {
+	return repo.updateAsParty(ctx, threadID, username,
+		bson.M{"$set": bson.M{"tenantUnread": 0}},
+		bson.M{"$set": bson.M{"landlordUnread": 0}})
+}
+
+// updateAsParty applies asTenant to the thread if the user is its tenant, otherwise asLandlord if they
+// are its landlord. It reports whether either matched.
+func (repo *MessageRepo) updateAsParty(ctx context.Context, threadID primitive.ObjectID, username string, asTenant, asLandlord bson.M) (bool, error) {
+	result, err := repo.collection.UpdateOne(ctx, bson.M{"_id": threadID, "tenantName": username}, asTenant)
+	if err != nil {
+		return false, err
+	}
+	if result.MatchedCount == 1 {
+		return true, nil
+	}
+	result, err = repo.collection.UpdateOne(ctx, bson.M{"_id": threadID, "landlordName": username}, asLandlord)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+func (repo *MessageRepo) findOne(ctx context.Context, filter bson.M) (*entities.Thread, error) {
+	var thread entities.Thread
+	err := repo.collection.FindOne(ctx, filter).Decode(&thread)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &thread, nil
+}
@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+
+	"rentease/config"
+)
+
+// initialRetryBackoff is the wait before the first retry of the startup health check; it doubles after each attempt.
+const initialRetryBackoff = 500 * time.Millisecond
+
+// NewMongoClient creates the single MongoDB client shared by all repositories.
+// The client owns the connection pool, applies cfg.OperationTimeout to every operation
+// without an earlier deadline and lets the driver retry reads and writes once on
+// transient errors. It pings the server before returning, retrying up to cfg.ConnectRetries
+// times, so a missing database is reported at startup instead of on first use.
+// Callers must release the client with DisconnectMongoClient.
+func NewMongoClient(ctx context.Context, cfg config.MongoConfig) (*mongo.Client, error) {
+	clientOptions := options.Client().
+		ApplyURI(cfg.URI).
+		SetMaxPoolSize(cfg.MaxPoolSize).
+		SetMinPoolSize(cfg.MinPoolSize).
+		SetConnectTimeout(cfg.ConnectTimeout).
+		SetServerSelectionTimeout(cfg.ConnectTimeout).
+		SetTimeout(cfg.OperationTimeout).
+		SetRetryReads(true).
+		SetRetryWrites(true)
+
+	client, err := mongo.Connect(ctx, clientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create MongoDB client: %w", err)
+	}
+
+	if err := pingWithRetry(ctx, client, cfg.ConnectTimeout, cfg.ConnectRetries); err != nil {
+		_ = client.Disconnect(context.Background())
+		return nil, err
+	}
+	log.Println("Connected to MongoDB!")
+	return client, nil
+}
+
+// pingWithRetry pings the primary until it answers, trying at most retries+1 times with exponential backoff.
+func pingWithRetry(ctx context.Context, client *mongo.Client, timeout time.Duration, retries int) error {
+	backoff := initialRetryBackoff
+	var err error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			log.Printf("MongoDB health check failed: %v (retrying in %s)", err, backoff)
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			backoff *= 2
+		}
+
+		pingCtx, cancel := context.WithTimeout(ctx, timeout)
+		err = client.Ping(pingCtx, readpref.Primary())
+		cancel()
+		if err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("MongoDB health check failed after %d attempt(s): %w", retries+1, err)
+}
+
+// DisconnectMongoClient closes all pooled connections, waiting at most timeout for in-use ones.
+func DisconnectMongoClient(client *mongo.Client, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := client.Disconnect(ctx); err != nil {
+		return fmt.Errorf("failed to disconnect from MongoDB: %w", err)
+	}
+	log.Println("Disconnected from MongoDB.")
+	return nil
+}
@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+type NotificationRepo struct {
+	client     *mongo.Client
+	collection *mongo.Collection
+}
+
+// NewNotificationRepo initializes a new NotificationRepo on the shared MongoDB client.
+func NewNotificationRepo(client *mongo.Client, dbName string, collectionName string) interfaces.NotificationRepo {
+	return &NotificationRepo{
+		client:     client,
+		collection: client.Database(dbName).Collection(collectionName),
+	}
+}
+
+func (repo *NotificationRepo) SaveNotification(ctx context.Context, notification entities.Notification) error {
+	if notification.ID.IsZero() {
+		notification.ID = primitive.NewObjectID()
+	}
+	_, err := repo.collection.InsertOne(ctx, notification)
+	return err
+}
+
+// FindNotificationsByUser returns the notifications of the user, newest first.
+func (repo *NotificationRepo) FindNotificationsByUser(ctx context.Context, username string) ([]entities.Notification, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}})
+	cursor, err := repo.collection.Find(ctx, bson.M{"username": username}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var notifications []entities.Notification
+	if err = cursor.All(ctx, &notifications); err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// MarkNotificationRead marks the notification read if it belongs to the user.
+func (repo *NotificationRepo) MarkNotificationRead(ctx context.Context, id primitive.ObjectID, username string) (bool, error) {
+	result, err := repo.collection.UpdateOne(ctx, bson.M{"_id": id, "username": username}, bson.M{"$set": bson.M{"read": true}})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+// MarkAllNotificationsRead marks the unread notifications of the user read.
+func (repo *NotificationRepo) MarkAllNotificationsRead(ctx context.Context, username string) (int, error) {
+	result, err := repo.collection.UpdateMany(ctx, bson.M{"username": username, "read": false}, bson.M{"$set": bson.M{"read": true}})
+	if err != nil {
+		return 0, err
+	}
+	return int(result.ModifiedCount), nil
+}
+
+// DeleteNotification removes the notification if it belongs to the user.
+func (repo *NotificationRepo) DeleteNotification(ctx context.Context, id primitive.ObjectID, username string) (bool, error) {
+	result, err := repo.collection.DeleteOne(ctx, bson.M{"_id": id, "username": username})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount == 1, nil
+}
+
+// DeleteReadNotifications removes the notifications the user has read.
+func (repo *NotificationRepo) DeleteReadNotifications(ctx context.Context, username string) (int, error) {
+	result, err := repo.collection.DeleteMany(ctx, bson.M{"username": username, "read": true})
+	if err != nil {
+		return 0, err
+	}
+	return int(result.DeletedCount), nil
+}
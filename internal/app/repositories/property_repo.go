@@ -8,7 +8,6 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 	"rentease/internal/domain/entities"
 	"rentease/internal/domain/interfaces"
-	"rentease/pkg/utils"
 )
 
 type PropertyRepo struct {
@@ -16,84 +15,46 @@ type PropertyRepo struct {
 	collection *mongo.Collection
 }
 
-// NewPropertyRepo initializes a new PropertyRepo with a MongoDB connection.
-func NewPropertyRepo(uri string, dbName string, collectionName string) (interfaces.PropertyRepo, error) {
-	client, err := connectToMongoDB(uri)
-	if err != nil {
-		return nil, err
-	}
-
+// NewPropertyRepo initializes a new PropertyRepo on the shared MongoDB client.
+func NewPropertyRepo(client *mongo.Client, dbName string, collectionName string) interfaces.PropertyRepo {
 	collection := client.Database(dbName).Collection(collectionName)
 	return &PropertyRepo{
 		client:     client,
 		collection: collection,
-	}, nil
+	}
 }
 
 // SaveProperty saves a property to the MongoDB collection.
-func (r *PropertyRepo) SaveProperty(property entities.Property) error {
-	_, err := r.collection.InsertOne(context.TODO(), property)
+func (r *PropertyRepo) SaveProperty(ctx context.Context, property entities.Property) error {
+	_, err := r.collection.InsertOne(ctx, property)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-// GetProperties retrieves properties based on the provided filter option.
-// If `forActiveUserOnly` is true, it returns properties for the active user only.
-// If `forActiveUserOnly` is false, it returns properties for all users.
-
-func (r *PropertyRepo) GetAllListedProperties(forActiveUserOnly bool) ([]entities.Property, error) {
-	var filter bson.D
-
-	// Apply filter based on the forActiveUserOnly flag
-	if forActiveUserOnly {
-		// Filter for the active user only
-		filter = bson.D{{"landlord_username", utils.ActiveUser}}
-	} else {
-		// No filter, retrieve all properties
-		filter = bson.D{{"is_rented", false}}
+// GetAllListedProperties retrieves the properties of the given landlord.
+// If `landlordUsername` is empty, it returns all properties that are not rented.
+func (r *PropertyRepo) GetAllListedProperties(ctx context.Context, landlordUsername string) ([]entities.Property, error) {
+	// Only the landlord's own properties, or everything still available
+	filter := bson.D{{Key: "is_rented", Value: false}}
+	if landlordUsername != "" {
+		filter = bson.D{{Key: "landlord_username", Value: landlordUsername}}
 	}
 
 	// Query the database with the filter
-	cursor, err := r.collection.Find(context.TODO(), filter)
+	cursor, err := r.collection.Find(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query properties: %w", err)
 	}
-	defer cursor.Close(context.TODO())
+	defer cursor.Close(ctx)
 
 	var properties []entities.Property
-	for cursor.Next(context.TODO()) {
-		var property entities.Property
-		// Decode the base property structure first
-		if err := cursor.Decode(&property); err != nil {
-			return nil, fmt.Errorf("failed to decode property: %w", err)
-		}
-
-		// Decode the Details field based on PropertyType
-		switch property.PropertyType {
-		case 1: // Commercial
-			var details entities.CommercialDetails
-			if err := bson.Unmarshal(cursor.Current.Lookup("details").Value, &details); err != nil {
-				return nil, fmt.Errorf("failed to decode commercial details: %w", err)
-			}
-			property.Details = details
-		case 2: // House
-			var details entities.HouseDetails
-			if err := bson.Unmarshal(cursor.Current.Lookup("details").Value, &details); err != nil {
-				return nil, fmt.Errorf("failed to decode house details: %w", err)
-			}
-			property.Details = details
-		case 3: // Flat
-			var details entities.FlatDetails
-			if err := bson.Unmarshal(cursor.Current.Lookup("details").Value, &details); err != nil {
-				return nil, fmt.Errorf("failed to decode flat details: %w", err)
-			}
-			property.Details = details
-		default:
-			// Unknown property type, Details will remain nil
+	for cursor.Next(ctx) {
+		property, err := decodeProperty(cursor.Current)
+		if err != nil {
+			return nil, err
 		}
-
 		properties = append(properties, property)
 	}
 
@@ -105,12 +66,12 @@ func (r *PropertyRepo) GetAllListedProperties(forActiveUserOnly bool) ([]entitie
 }
 
 // DeleteAllListedPropertiesOfaUser deletes all the listed properties for a particular user based on their username.
-func (r *PropertyRepo) DeleteAllListedPropertiesOfaUser(username string) error {
+func (r *PropertyRepo) DeleteAllListedPropertiesOfaUser(ctx context.Context, username string) error {
 	// Create a filter to find all properties listed by the given username
-	filter := bson.D{{"landlord_username", username}}
+	filter := bson.D{{Key: "landlord_username", Value: username}}
 
 	// Execute the delete operation
-	_, err := r.collection.DeleteMany(context.TODO(), filter)
+	_, err := r.collection.DeleteMany(ctx, filter)
 	if err != nil {
 		return fmt.Errorf("failed to delete properties for user %s: %w", username, err)
 	}
@@ -119,17 +80,18 @@ func (r *PropertyRepo) DeleteAllListedPropertiesOfaUser(username string) error {
 }
 
 // UpdateListedProperty updates an existing property in the collection.
-func (r *PropertyRepo) UpdateListedProperty(property entities.Property) error {
+func (r *PropertyRepo) UpdateListedProperty(ctx context.Context, property entities.Property) error {
 
-	filter := bson.D{{"_id", property.ID}}
+	filter := bson.D{{Key: "_id", Value: property.ID}}
 	update := bson.D{
-		{"$set", bson.D{
-			{"title", property.Title},
-			{"address", property.Address},
-			{"rent_amount", property.RentAmount},
-			{"is_approved_by_admin", property.IsApprovedByAdmin},
-			{"is_rented", property.IsRented},
-			{"details", property.Details},
+		{Key: "$set", Value: bson.D{
+			{Key: "title", Value: property.Title},
+			{Key: "address", Value: property.Address},
+			{Key: "rent_amount", Value: property.RentAmount},
+			{Key: "deposit", Value: property.Deposit},
+			{Key: "is_approved_by_admin", Value: property.IsApprovedByAdmin},
+			{Key: "is_rented", Value: property.IsRented},
+			{Key: "details", Value: property.Details},
 		}},
 	}
 
@@ -138,17 +100,17 @@ func (r *PropertyRepo) UpdateListedProperty(property entities.Property) error {
 		property.IsApprovedByAdmin = false
 	}
 
-	_, err := r.collection.UpdateOne(context.TODO(), filter, update)
+	_, err := r.collection.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-// DeleteListedProperty deletes a property from the collection by title.
-func (r *PropertyRepo) DeleteListedProperty(propertyTitle string) error {
-	filter := bson.D{{"title", propertyTitle}}
-	_, err := r.collection.DeleteOne(context.TODO(), filter)
+// DeleteListedProperty deletes a property from the collection by ID.
+func (r *PropertyRepo) DeleteListedProperty(ctx context.Context, propertyID primitive.ObjectID) error {
+	filter := bson.D{{Key: "_id", Value: propertyID}}
+	_, err := r.collection.DeleteOne(ctx, filter)
 	if err != nil {
 		return err
 	}
@@ -157,10 +119,9 @@ func (r *PropertyRepo) DeleteListedProperty(propertyTitle string) error {
 
 // FindByID retrieves a property by its ID from the MongoDB collection.
 func (r *PropertyRepo) FindByID(ctx context.Context, id primitive.ObjectID) (*entities.Property, error) {
-	var property entities.Property
-	filter := bson.D{{"_id", id}}
+	filter := bson.D{{Key: "_id", Value: id}}
 
-	err := r.collection.FindOne(ctx, filter).Decode(&property)
+	raw, err := r.collection.FindOne(ctx, filter).Raw()
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, nil // No document found
@@ -168,12 +129,15 @@ func (r *PropertyRepo) FindByID(ctx context.Context, id primitive.ObjectID) (*en
 		return nil, fmt.Errorf("failed to find property by ID: %w", err) // Wrap other errors
 	}
 
+	property, err := decodeProperty(raw)
+	if err != nil {
+		return nil, err
+	}
 	return &property, nil
 }
 
 // For admin
-func (r *PropertyRepo) FindPendingProperties() ([]entities.Property, error) {
-	ctx := context.TODO()
+func (r *PropertyRepo) FindPendingProperties(ctx context.Context) ([]entities.Property, error) {
 	filter := bson.M{"is_approved_by_admin": false}
 	cursor, err := r.collection.Find(ctx, filter)
 	if err != nil {
@@ -182,12 +146,17 @@ func (r *PropertyRepo) FindPendingProperties() ([]entities.Property, error) {
 	defer cursor.Close(ctx)
 
 	var properties []entities.Property
-	err = cursor.All(ctx, &properties)
-	return properties, err
+	for cursor.Next(ctx) {
+		property, err := decodeProperty(cursor.Current)
+		if err != nil {
+			return nil, err
+		}
+		properties = append(properties, property)
+	}
+	return properties, cursor.Err()
 }
 
-func (r *PropertyRepo) UpdateApprovalStatus(propertyID primitive.ObjectID, approved bool, adminUsername string) error {
-	ctx := context.TODO()
+func (r *PropertyRepo) UpdateApprovalStatus(ctx context.Context, propertyID primitive.ObjectID, approved bool, adminUsername string) error {
 	filter := bson.M{"_id": propertyID}
 	update := bson.M{
 		"$set": bson.M{
@@ -197,3 +166,63 @@ func (r *PropertyRepo) UpdateApprovalStatus(propertyID primitive.ObjectID, appro
 	_, err := r.collection.UpdateOne(ctx, filter, update)
 	return err
 }
+
+// SetRented changes whether the property is rented if it is not in that state already.
+// The state is part of the filter, so of two concurrent calls renting out the same property only one matches.
+func (r *PropertyRepo) SetRented(ctx context.Context, propertyID primitive.ObjectID, rented bool) (bool, error) {
+	filter := bson.M{"_id": propertyID, "is_rented": bson.M{"$ne": rented}}
+	update := bson.M{
+		"$set": bson.M{
+			"is_rented": rented,
+		},
+	}
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+// decodeProperty decodes a raw property document, including the Details field
+// whose concrete type depends on PropertyType.
+func decodeProperty(raw bson.Raw) (entities.Property, error) {
+	var property entities.Property
+	// Decode the base property structure first
+	if err := bson.Unmarshal(raw, &property); err != nil {
+		return entities.Property{}, fmt.Errorf("failed to decode property: %w", err)
+	}
+
+	// Decode the Details field based on PropertyType
+	detailsRaw, err := raw.LookupErr("details")
+	if err != nil {
+		// No details stored, Details will remain nil
+		property.Details = nil
+		return property, nil
+	}
+
+	switch property.PropertyType {
+	case 1: // Commercial
+		var details entities.CommercialDetails
+		if err := bson.Unmarshal(detailsRaw.Value, &details); err != nil {
+			return entities.Property{}, fmt.Errorf("failed to decode commercial details: %w", err)
+		}
+		property.Details = details
+	case 2: // House
+		var details entities.HouseDetails
+		if err := bson.Unmarshal(detailsRaw.Value, &details); err != nil {
+			return entities.Property{}, fmt.Errorf("failed to decode house details: %w", err)
+		}
+		property.Details = details
+	case 3: // Flat
+		var details entities.FlatDetails
+		if err := bson.Unmarshal(detailsRaw.Value, &details); err != nil {
+			return entities.Property{}, fmt.Errorf("failed to decode flat details: %w", err)
+		}
+		property.Details = details
+	default:
+		// Unknown property type, Details will remain nil
+		property.Details = nil
+	}
+
+	return property, nil
+}
@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+type RefreshTokenRepo struct {
+	client     *mongo.Client
+	collection *mongo.Collection
+}
+
+// NewRefreshTokenRepo initializes a new RefreshTokenRepo on the shared MongoDB client.
+func NewRefreshTokenRepo(client *mongo.Client, dbName string, collectionName string) interfaces.RefreshTokenRepo {
+	collection := client.Database(dbName).Collection(collectionName)
+	return &RefreshTokenRepo{
+		client:     client,
+		collection: collection,
+	}
+}
+
+// SaveRefreshToken stores the token, replacing any token with the same ID.
+func (repo *RefreshTokenRepo) SaveRefreshToken(ctx context.Context, token entities.RefreshToken) error {
+	filter := bson.M{"_id": token.ID}
+	_, err := repo.collection.ReplaceOne(ctx, filter, token, options.Replace().SetUpsert(true))
+	return err
+}
+
+// RotateRefreshToken replaces the token if it still has the previous hash. The hash is part of the filter,
+// so of two concurrent refreshes with the same token only one matches.
+func (repo *RefreshTokenRepo) RotateRefreshToken(ctx context.Context, token entities.RefreshToken, previousHash string) (bool, error) {
+	filter := bson.M{"_id": token.ID, "tokenHash": previousHash, "revoked": false}
+	result, err := repo.collection.ReplaceOne(ctx, filter, token)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+func (repo *RefreshTokenRepo) FindRefreshToken(ctx context.Context, id string) (*entities.RefreshToken, error) {
+	var token entities.RefreshToken
+	err := repo.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&token)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil // No document found
+		}
+		return nil, fmt.Errorf("failed to find refresh token: %w", err)
+	}
+	return &token, nil
+}
+
+func (repo *RefreshTokenRepo) RevokeRefreshToken(ctx context.Context, id string) error {
+	_, err := repo.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"revoked": true}})
+	return err
+}
+
+func (repo *RefreshTokenRepo) RevokeUserRefreshTokens(ctx context.Context, username string) error {
+	_, err := repo.collection.UpdateMany(ctx, bson.M{"username": username}, bson.M{"$set": bson.M{"revoked": true}})
+	return err
+}
@@ -3,10 +3,12 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
-	"log"
 	"rentease/internal/domain/entities"
 	"rentease/internal/domain/interfaces"
 )
@@ -16,33 +18,72 @@ type RequestRepo struct {
 	collection *mongo.Collection
 }
 
-// NewRequestRepo initializes a new RequestRepo with a MongoDB connection.
-func NewRequestRepo(uri string, dbName string, collectionName string) (interfaces.RequestRepo, error) {
-	clientOptions := options.Client().ApplyURI(uri)
-	client, err := mongo.Connect(context.TODO(), clientOptions)
-	if err != nil {
-		return nil, err
-	}
-	err = client.Ping(context.TODO(), nil)
-	if err != nil {
-		return nil, err
-	}
-	log.Println("Connected to MongoDB!")
-
+// NewRequestRepo initializes a new RequestRepo on the shared MongoDB client.
+func NewRequestRepo(client *mongo.Client, dbName string, collectionName string) interfaces.RequestRepo {
 	collection := client.Database(dbName).Collection(collectionName)
 	return &RequestRepo{
 		client:     client,
 		collection: collection,
-	}, nil
+	}
 }
 
-func (repo *RequestRepo) SaveRequest(request entities.Request) error {
-	_, err := repo.collection.InsertOne(context.TODO(), request)
+func (repo *RequestRepo) SaveRequest(ctx context.Context, request entities.Request) error {
+	_, err := repo.collection.InsertOne(ctx, request)
 	return err
 }
 
+// openRequestIndex is the name of the unique index over the pending requests of a tenant for a property.
+const openRequestIndex = "tenantName_propertyID_pending"
+
+// EnsureRequestIndexes creates the indexes of the rent request collection if they do not exist yet.
+// The unique index over pending requests makes CreateRequest safe against two requests created at the
+// same time, which are both pending. Index creation fails while the collection still holds duplicates.
+func EnsureRequestIndexes(ctx context.Context, client *mongo.Client, dbName string, collectionName string) error {
+	index := mongo.IndexModel{
+		Keys: bson.D{{Key: "tenantName", Value: 1}, {Key: "propertyID", Value: 1}},
+		Options: options.Index().
+			SetName(openRequestIndex).
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"requestStatus": entities.RequestPending}),
+	}
+	if _, err := client.Database(dbName).Collection(collectionName).Indexes().CreateOne(ctx, index); err != nil {
+		return fmt.Errorf("failed to create index %s on %s: %w", openRequestIndex, collectionName, err)
+	}
+	return nil
+}
+
+// CreateRequest saves the request unless the tenant already has an open request for the property.
+// The lookup covers accepted requests as well; the unique index created by EnsureRequestIndexes
+// rejects a pending request inserted between the lookup and the insert.
+func (repo *RequestRepo) CreateRequest(ctx context.Context, request entities.Request) (bool, error) {
+	filter := bson.M{
+		"tenantName":    request.TenantName,
+		"propertyID":    request.PropertyID,
+		"requestStatus": bson.M{"$in": entities.OpenRequestStatuses()},
+	}
+	open, err := repo.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return false, err
+	}
+	if open > 0 {
+		return false, nil
+	}
+
+	if request.ID.IsZero() {
+		request.ID = primitive.NewObjectID()
+	}
+	_, err = repo.collection.InsertOne(ctx, request)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 func (repo *RequestRepo) FindByTenantUsername(ctx context.Context, tenantUsername string) ([]entities.Request, error) {
-	filter := bson.D{{"tenantName", tenantUsername}}
+	filter := bson.D{{Key: "tenantName", Value: tenantUsername}}
 	cursor, err := repo.collection.Find(ctx, filter)
 	if err != nil {
 		return nil, err
@@ -58,7 +99,7 @@ func (repo *RequestRepo) FindByTenantUsername(ctx context.Context, tenantUsernam
 }
 
 func (repo *RequestRepo) FindByLandlordName(ctx context.Context, landlordName string) ([]entities.Request, error) {
-	filter := bson.D{{"landlordName", landlordName}}
+	filter := bson.D{{Key: "landlordName", Value: landlordName}}
 	cursor, err := repo.collection.Find(ctx, filter)
 	if err != nil {
 		return nil, err
@@ -73,10 +114,66 @@ func (repo *RequestRepo) FindByLandlordName(ctx context.Context, landlordName st
 	return requests, nil
 }
 
-func (repo *RequestRepo) UpdateRequest(request entities.Request, status string) error {
-	filter := bson.M{"_id": request.ID}
-	update := bson.M{"$set": bson.M{"requestStatus": status}}
-	_, err := repo.collection.UpdateOne(context.Background(), filter, update)
+func (repo *RequestRepo) FindByStatus(ctx context.Context, status entities.RequestStatus) ([]entities.Request, error) {
+	filter := bson.D{{Key: "requestStatus", Value: status}}
+	cursor, err := repo.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
 
-	return err
+	var requests []entities.Request
+	if err = cursor.All(ctx, &requests); err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
+
+func (repo *RequestRepo) FindByPropertyID(ctx context.Context, propertyID primitive.ObjectID) ([]entities.Request, error) {
+	filter := bson.D{{Key: "propertyID", Value: propertyID}}
+	cursor, err := repo.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var requests []entities.Request
+	if err = cursor.All(ctx, &requests); err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
+
+// FindRequestByID returns the request, or nil if there is none with the ID.
+func (repo *RequestRepo) FindRequestByID(ctx context.Context, id primitive.ObjectID) (*entities.Request, error) {
+	var request entities.Request
+	err := repo.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&request)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// UpdateRequestStatus applies the change if the stored request is still in change.From.
+// The status is part of the filter, so of two concurrent changes from the same status only one matches.
+func (repo *RequestRepo) UpdateRequestStatus(ctx context.Context, id primitive.ObjectID, change entities.StatusChange) (bool, error) {
+	filter := bson.M{"_id": id, "requestStatus": change.From}
+	// A pipeline update, as requests saved before the history existed may have none or a null one
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"requestStatus": change.To,
+		"history": bson.M{"$concatArrays": bson.A{
+			bson.M{"$ifNull": bson.A{"$history", bson.A{}}},
+			bson.M{"$literal": bson.A{change}},
+		}},
+	}}}}
+	result, err := repo.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
 }
@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"rentease/config"
+	"rentease/internal/domain/interfaces"
+)
+
+// Storage holds the repositories of the configured storage backend.
+type Storage struct {
+	Users         interfaces.UserRepo
+	Properties    interfaces.PropertyRepo
+	RentRequests  interfaces.RequestRepo
+	RefreshTokens interfaces.RefreshTokenRepo
+	Leases        interfaces.LeaseRepo
+	Ledger        interfaces.LedgerRepo
+	Deposits      interfaces.DepositRepo
+	Maintenance   interfaces.MaintenanceRepo
+	Visits        interfaces.VisitRepo
+	Messages      interfaces.MessageRepo
+	Notifications interfaces.NotificationRepo
+
+	closeOnce sync.Once
+	close     func() error
+	closeErr  error
+}
+
+// OpenStorage creates the repositories for the storage backend selected in cfg.
+// The returned Storage must be closed by the caller.
+func OpenStorage(ctx context.Context, cfg config.Config) (*Storage, error) {
+	switch cfg.Storage {
+	case config.StorageMemory:
+		return &Storage{
+			Users:         NewInMemoryUserRepo(),
+			Properties:    NewInMemoryPropertyRepo(),
+			RentRequests:  NewInMemoryRequestRepo(),
+			RefreshTokens: NewInMemoryRefreshTokenRepo(),
+			Leases:        NewInMemoryLeaseRepo(),
+			Ledger:        NewInMemoryLedgerRepo(),
+			Deposits:      NewInMemoryDepositRepo(),
+			Maintenance:   NewInMemoryMaintenanceRepo(),
+			Visits:        NewInMemoryVisitRepo(),
+			Messages:      NewInMemoryMessageRepo(),
+			Notifications: NewInMemoryNotificationRepo(),
+			close:         func() error { return nil },
+		}, nil
+
+	case config.StorageBolt:
+		db, err := OpenBoltDB(cfg.Bolt.Path)
+		if err != nil {
+			return nil, err
+		}
+		return &Storage{
+			Users:         NewBoltUserRepo(db),
+			Properties:    NewBoltPropertyRepo(db),
+			RentRequests:  NewBoltRequestRepo(db),
+			RefreshTokens: NewBoltRefreshTokenRepo(db),
+			Leases:        NewBoltLeaseRepo(db),
+			Ledger:        NewBoltLedgerRepo(db),
+			Deposits:      NewBoltDepositRepo(db),
+			Maintenance:   NewBoltMaintenanceRepo(db),
+			Visits:        NewBoltVisitRepo(db),
+			Messages:      NewBoltMessageRepo(db),
+			Notifications: NewBoltNotificationRepo(db),
+			close:         db.Close,
+		}, nil
+
+	case config.StorageMongo:
+		// One client, and so one connection pool, is shared by all repositories
+		client, err := NewMongoClient(ctx, cfg.Mongo)
+		if err != nil {
+			return nil, err
+		}
+		if err := EnsureRequestIndexes(ctx, client, cfg.Mongo.Database, cfg.Mongo.Collections.RentRequests); err != nil {
+			_ = DisconnectMongoClient(client, cfg.Mongo.OperationTimeout)
+			return nil, err
+		}
+		if err := EnsureLedgerIndexes(ctx, client, cfg.Mongo.Database, cfg.Mongo.Collections.RentDues); err != nil {
+			_ = DisconnectMongoClient(client, cfg.Mongo.OperationTimeout)
+			return nil, err
+		}
+		if err := EnsureDepositIndexes(ctx, client, cfg.Mongo.Database, cfg.Mongo.Collections.Deposits); err != nil {
+			_ = DisconnectMongoClient(client, cfg.Mongo.OperationTimeout)
+			return nil, err
+		}
+		if err := EnsureMessageIndexes(ctx, client, cfg.Mongo.Database, cfg.Mongo.Collections.Messages); err != nil {
+			_ = DisconnectMongoClient(client, cfg.Mongo.OperationTimeout)
+			return nil, err
+		}
+		return &Storage{
+			Users:         NewUserRepo(client, cfg.Mongo.Database, cfg.Mongo.Collections.Users),
+			Properties:    NewPropertyRepo(client, cfg.Mongo.Database, cfg.Mongo.Collections.Properties),
+			RentRequests:  NewRequestRepo(client, cfg.Mongo.Database, cfg.Mongo.Collections.RentRequests),
+			RefreshTokens: NewRefreshTokenRepo(client, cfg.Mongo.Database, cfg.Mongo.Collections.RefreshTokens),
+			Leases:        NewLeaseRepo(client, cfg.Mongo.Database, cfg.Mongo.Collections.Leases),
+			Ledger:        NewLedgerRepo(client, cfg.Mongo.Database, cfg.Mongo.Collections.RentDues, cfg.Mongo.Collections.Payments),
+			Deposits:      NewDepositRepo(client, cfg.Mongo.Database, cfg.Mongo.Collections.Deposits),
+			Maintenance:   NewMaintenanceRepo(client, cfg.Mongo.Database, cfg.Mongo.Collections.Maintenance),
+			Visits:        NewVisitRepo(client, cfg.Mongo.Database, cfg.Mongo.Collections.Visits),
+			Messages:      NewMessageRepo(client, cfg.Mongo.Database, cfg.Mongo.Collections.Messages),
+			Notifications: NewNotificationRepo(client, cfg.Mongo.Database, cfg.Mongo.Collections.Notifications),
+			close: func() error {
+				return DisconnectMongoClient(client, cfg.Mongo.OperationTimeout)
+			},
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q (expected mongo, bolt or memory)", cfg.Storage)
+	}
+}
+
+// Close releases the storage. It is safe to call more than once.
+func (s *Storage) Close() error {
+	s.closeOnce.Do(func() { s.closeErr = s.close() })
+	return s.closeErr
+}
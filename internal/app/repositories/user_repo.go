@@ -5,10 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"go.mongodb.org/mongo-driver/bson"
-	"log"
 
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"rentease/internal/domain/entities"
 	"rentease/internal/domain/interfaces"
@@ -20,38 +18,18 @@ type UserRepo struct {
 	collection *mongo.Collection
 }
 
-// NewUserRepo initializes a new UserRepo with a MongoDB connection.
-func NewUserRepo(uri string, dbName string, collectionName string) (interfaces.UserRepo, error) {
-	client, err := connectToMongoDB(uri)
-	if err != nil {
-		return nil, err
-	}
-
+// NewUserRepo initializes a new UserRepo on the shared MongoDB client.
+func NewUserRepo(client *mongo.Client, dbName string, collectionName string) interfaces.UserRepo {
 	collection := client.Database(dbName).Collection(collectionName)
 	return &UserRepo{
 		client:     client,
 		collection: collection,
-	}, nil
-}
-
-// connectToMongoDB creates a new MongoDB client and connects to the database.
-func connectToMongoDB(uri string) (*mongo.Client, error) {
-	clientOptions := options.Client().ApplyURI(uri)
-	client, err := mongo.Connect(context.TODO(), clientOptions)
-	if err != nil {
-		return nil, err
-	}
-	err = client.Ping(context.TODO(), nil)
-	if err != nil {
-		return nil, err
 	}
-	log.Println("Connected to MongoDB!")
-	return client, nil
 }
 
 // SaveUser saves a user to the MongoDB collection.
-func (repo *UserRepo) SaveUser(user entities.User) error {
-	_, err := repo.collection.InsertOne(context.TODO(), user)
+func (repo *UserRepo) SaveUser(ctx context.Context, user entities.User) error {
+	_, err := repo.collection.InsertOne(ctx, user)
 	if err != nil {
 		return err
 	}
@@ -60,7 +38,7 @@ func (repo *UserRepo) SaveUser(user entities.User) error {
 
 func (repo *UserRepo) FindByUsername(ctx context.Context, username string) (*entities.User, error) {
 	var user entities.User
-	filter := bson.D{{"username", username}}
+	filter := bson.D{{Key: "username", Value: username}}
 
 	err := repo.collection.FindOne(ctx, filter).Decode(&user)
 	if err != nil {
@@ -87,10 +65,10 @@ func (repo *UserRepo) CheckPassword(ctx context.Context, username, password stri
 	return utils.CheckPasswordHash(password, user.PasswordHash), nil
 }
 
-func (repo *UserRepo) UpdateUser(user entities.User) error {
+func (repo *UserRepo) UpdateUser(ctx context.Context, user entities.User) error {
 	filter := bson.M{"username": user.Username}
 	update := bson.M{"$set": user}
-	result, err := repo.collection.UpdateOne(context.Background(), filter, update)
+	result, err := repo.collection.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return err
 	}
@@ -102,8 +80,7 @@ func (repo *UserRepo) UpdateUser(user entities.User) error {
 
 //Admin related
 
-func (ur *UserRepo) FindAll() ([]entities.User, error) {
-	ctx := context.TODO()
+func (ur *UserRepo) FindAll(ctx context.Context) ([]entities.User, error) {
 	cursor, err := ur.collection.Find(ctx, bson.M{})
 	if err != nil {
 		return nil, err
@@ -115,8 +92,7 @@ func (ur *UserRepo) FindAll() ([]entities.User, error) {
 	return users, err
 }
 
-func (ur *UserRepo) Delete(username string) error {
-	ctx := context.TODO()
+func (ur *UserRepo) Delete(ctx context.Context, username string) error {
 	_, err := ur.collection.DeleteOne(ctx, bson.M{"username": username})
 	return err
 }
@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+type VisitRepo struct {
+	client     *mongo.Client
+	collection *mongo.Collection
+}
+
+// NewVisitRepo initializes a new VisitRepo on the shared MongoDB client.
+func NewVisitRepo(client *mongo.Client, dbName string, collectionName string) interfaces.VisitRepo {
+	return &VisitRepo{
+		client:     client,
+		collection: client.Database(dbName).Collection(collectionName),
+	}
+}
+
+// SaveSlot stores the slot, replacing any slot with the same ID.
+func (repo *VisitRepo) SaveSlot(ctx context.Context, slot entities.VisitSlot) error {
+	if slot.ID.IsZero() {
+		slot.ID = primitive.NewObjectID()
+	}
+	_, err := repo.collection.ReplaceOne(ctx, bson.M{"_id": slot.ID}, slot, options.Replace().SetUpsert(true))
+	return err
+}
+
+// FindSlotByID returns the slot, or nil if there is none with the ID.
+func (repo *VisitRepo) FindSlotByID(ctx context.Context, id primitive.ObjectID) (*entities.VisitSlot, error) {
+	var slot entities.VisitSlot
+	err := repo.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&slot)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &slot, nil
+}
+
+func (repo *VisitRepo) FindSlotsByProperty(ctx context.Context, propertyID primitive.ObjectID) ([]entities.VisitSlot, error) {
+	return repo.find(ctx, bson.D{{Key: "propertyID", Value: propertyID}})
+}
+
+func (repo *VisitRepo) FindSlotsByLandlord(ctx context.Context, landlordName string) ([]entities.VisitSlot, error) {
+	return repo.find(ctx, bson.D{{Key: "landlordName", Value: landlordName}})
+}
+
+func (repo *VisitRepo) FindSlotsByTenant(ctx context.Context, tenantName string) ([]entities.VisitSlot, error) {
+	return repo.find(ctx, bson.D{{Key: "tenantName", Value: tenantName}})
+}
+
+// BookSlot books the slot for the tenant if it is still open.
+// The status is part of the filter, so of two tenants booking the same slot only one matches.
+func (repo *VisitRepo) BookSlot(ctx context.Context, id primitive.ObjectID, tenantName string, at time.Time) (bool, error) {
+	update := bson.M{"$set": bson.M{
+		"status":     entities.VisitBooked,
+		"tenantName": tenantName,
+		"bookedAt":   at,
+		"updatedAt":  at,
+	}}
+	return repo.updateOne(ctx, bson.M{"_id": id, "status": entities.VisitOpen}, update)
+}
+
+// FreeSlot opens the slot again if it is still booked by the tenant.
+func (repo *VisitRepo) FreeSlot(ctx context.Context, id primitive.ObjectID, tenantName string, at time.Time) (bool, error) {
+	update := bson.M{
+		"$set":   bson.M{"status": entities.VisitOpen, "updatedAt": at},
+		"$unset": bson.M{"tenantName": "", "bookedAt": ""},
+	}
+	return repo.updateOne(ctx, bson.M{"_id": id, "status": entities.VisitBooked, "tenantName": tenantName}, update)
+}
+
+// CancelSlot cancels the slot if it is still in from.
+func (repo *VisitRepo) CancelSlot(ctx context.Context, id primitive.ObjectID, from entities.VisitStatus, by string, at time.Time) (bool, error) {
+	update := bson.M{"$set": bson.M{"status": entities.VisitCancelled, "cancelledBy": by, "updatedAt": at}}
+	return repo.updateOne(ctx, bson.M{"_id": id, "status": from}, update)
+}
+
+func (repo *VisitRepo) updateOne(ctx context.Context, filter bson.M, update bson.M) (bool, error) {
+	result, err := repo.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+func (repo *VisitRepo) find(ctx context.Context, filter bson.D) ([]entities.VisitSlot, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "start", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := repo.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var slots []entities.VisitSlot
+	if err = cursor.All(ctx, &slots); err != nil {
+		return nil, err
+	}
+	return slots, nil
+}
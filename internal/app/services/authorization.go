@@ -0,0 +1,49 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"rentease/internal/domain/entities"
+)
+
+// ErrForbidden is matched by every ForbiddenError, so callers can test for it with errors.Is.
+var ErrForbidden = errors.New("forbidden")
+
+// ForbiddenError is returned when the caller lacks the permission or ownership an action needs.
+type ForbiddenError struct {
+	Username string // Who tried
+	Action   string // What they tried, e.g. "delete the property"
+	Reason   string // Why it was refused
+}
+
+func (e *ForbiddenError) Error() string {
+	return fmt.Sprintf("%s may not %s: %s", e.Username, e.Action, e.Reason)
+}
+
+// Is makes errors.Is(err, ErrForbidden) true for a ForbiddenError.
+func (e *ForbiddenError) Is(target error) bool {
+	return target == ErrForbidden
+}
+
+// authorize checks that the session is valid and its role grants the permission.
+func authorize(session *entities.Session, permission entities.Permission, action string) error {
+	if err := checkSession(session); err != nil {
+		return err
+	}
+	if !session.Can(permission) {
+		return &ForbiddenError{Username: session.Username(), Action: action, Reason: fmt.Sprintf("role %q lacks the %s permission", session.Role, permission)}
+	}
+	return nil
+}
+
+// authorizeOwner is like authorize but also requires the session to belong to the owner of the resource.
+func authorizeOwner(session *entities.Session, owner string, permission entities.Permission, action string) error {
+	if err := authorize(session, permission, action); err != nil {
+		return err
+	}
+	if session.Username() != owner {
+		return &ForbiddenError{Username: session.Username(), Action: action, Reason: "it belongs to another user"}
+	}
+	return nil
+}
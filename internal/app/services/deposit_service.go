@@ -0,0 +1,270 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+	"strings"
+	"time"
+)
+
+var ErrInvalidDeposit = errors.New("invalid deposit settlement")
+
+// DepositStateError is returned for an action the security deposit does not allow in its status,
+// such as acknowledging deductions that were not itemised yet.
+type DepositStateError struct {
+	Status entities.DepositStatus
+	Action string
+}
+
+func (e *DepositStateError) Error() string {
+	return fmt.Sprintf("cannot %s: the deposit is %s", e.Action, e.Status)
+}
+
+// Is makes errors.Is(err, ErrInvalidTransition) true for a DepositStateError.
+func (e *DepositStateError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// DepositService tracks the security deposits of leases. The landlord records receiving the deposit and,
+// once the tenant moves out, itemises what they keep back of it. The tenant acknowledges the deductions,
+// which closes the deposit, or disputes them, after which the landlord revises them.
+type DepositService struct {
+	depositRepo interfaces.DepositRepo
+	leaseRepo   interfaces.LeaseRepo
+}
+
+// NewDepositService creates the service on the deposits and the leases they are paid under.
+func NewDepositService(depositRepo interfaces.DepositRepo, leaseRepo interfaces.LeaseRepo) *DepositService {
+	return &DepositService{
+		depositRepo: depositRepo,
+		leaseRepo:   leaseRepo,
+	}
+}
+
+// Deposit shows the security deposit of a lease to its tenant or landlord. Moderators can see every deposit.
+// A deposit that was not received yet is awaited, for the amount the lease sets.
+func (ds *DepositService) Deposit(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID) (entities.Deposit, error) {
+	if err := checkSession(session); err != nil {
+		return entities.Deposit{}, err
+	}
+	lease, err := ds.findLease(ctx, leaseID)
+	if err != nil {
+		return entities.Deposit{}, err
+	}
+	if !isPartyTo(session, lease) && !session.Can(entities.PermModerateProperties) {
+		return entities.Deposit{}, &ForbiddenError{Username: session.Username(), Action: "see the security deposit", Reason: "they are not a party to the lease"}
+	}
+	return ds.current(ctx, lease)
+}
+
+// RecordDeposit lets the landlord record receiving the security deposit of a signed lease of one of their
+// properties. A deposit without a date was received today. It can be recorded once.
+func (ds *DepositService) RecordDeposit(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID, receipt entities.Payment) (entities.Deposit, error) {
+	const action = "record the security deposit"
+	if err := authorize(session, entities.PermListProperties, action); err != nil {
+		return entities.Deposit{}, err
+	}
+	now := time.Now()
+	if receipt.PaidOn.IsZero() {
+		receipt.PaidOn = leaseDay(now)
+	}
+	if err := validatePayment(receipt, now); err != nil {
+		return entities.Deposit{}, err
+	}
+	lease, err := ds.landlordLease(ctx, session, leaseID, action)
+	if err != nil {
+		return entities.Deposit{}, err
+	}
+	if !lease.Status.IsSigned() {
+		return entities.Deposit{}, &LeaseStateError{Status: lease.Status, Action: action}
+	}
+
+	received := entities.RoundMoney(receipt.Amount)
+	deposit := entities.Deposit{
+		ID:         primitive.NewObjectID(),
+		LeaseID:    lease.ID,
+		Agreed:     lease.Deposit,
+		Received:   received,
+		ReceivedOn: leaseDay(receipt.PaidOn),
+		Mode:       receipt.Mode,
+		Reference:  receipt.Reference,
+		RecordedBy: session.Username(),
+		RecordedAt: now,
+		Status:     entities.DepositHeld,
+		Deductions: []entities.DepositDeduction{},
+		Refund:     received,
+	}
+	created, err := ds.depositRepo.CreateDeposit(ctx, deposit)
+	if err != nil {
+		return entities.Deposit{}, err
+	}
+	if !created {
+		return entities.Deposit{}, ds.stateError(ctx, lease, action)
+	}
+	return deposit, nil
+}
+
+// ProposeDeductions lets the landlord itemise what they keep back of the deposit once the tenant moves out,
+// that is after notice was given or the lease ended. The rest of the deposit is refunded. Deductions that
+// are waiting for the tenant or were disputed can be revised.
+func (ds *DepositService) ProposeDeductions(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID, deductions []entities.DepositDeduction) (entities.Deposit, error) {
+	const action = "itemise the deposit deductions"
+	if err := authorize(session, entities.PermListProperties, action); err != nil {
+		return entities.Deposit{}, err
+	}
+	lease, err := ds.landlordLease(ctx, session, leaseID, action)
+	if err != nil {
+		return entities.Deposit{}, err
+	}
+	if lease.Status != entities.LeaseEnding && lease.Status != entities.LeaseEnded {
+		return entities.Deposit{}, &LeaseStateError{Status: lease.Status, Action: action}
+	}
+	deposit, err := ds.current(ctx, lease)
+	if err != nil {
+		return entities.Deposit{}, err
+	}
+	from := deposit.Status
+	if from != entities.DepositHeld && from != entities.DepositProposed && from != entities.DepositDisputed {
+		return entities.Deposit{}, &DepositStateError{Status: from, Action: action}
+	}
+
+	deposit.Deductions = make([]entities.DepositDeduction, 0, len(deductions))
+	for _, deduction := range deductions {
+		deduction.Reason = strings.TrimSpace(deduction.Reason)
+		deduction.Note = strings.TrimSpace(deduction.Note)
+		if deduction.Reason == "" {
+			return entities.Deposit{}, fmt.Errorf("%w: every deduction needs a reason", ErrInvalidDeposit)
+		}
+		if deduction.Amount <= 0 {
+			return entities.Deposit{}, fmt.Errorf("%w: the deduction for %q must be positive", ErrInvalidDeposit, deduction.Reason)
+		}
+		deduction.Amount = entities.RoundMoney(deduction.Amount)
+		deposit.Deductions = append(deposit.Deductions, deduction)
+	}
+	if deducted := deposit.Deducted(); deducted > deposit.Received {
+		return entities.Deposit{}, fmt.Errorf("%w: the deductions of %.2f exceed the deposit of %.2f", ErrInvalidDeposit, deducted, deposit.Received)
+	}
+
+	now := time.Now()
+	deposit.Refund = entities.RoundMoney(deposit.Received - deposit.Deducted())
+	deposit.ProposedAt = &now
+	deposit.Status = entities.DepositProposed
+	return ds.update(ctx, lease, deposit, from, action)
+}
+
+// AcknowledgeDeductions lets the tenant accept the deductions the landlord itemised, which closes the deposit.
+func (ds *DepositService) AcknowledgeDeductions(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID) (entities.Deposit, error) {
+	const action = "acknowledge the deposit deductions"
+	lease, deposit, err := ds.proposal(ctx, session, leaseID, action)
+	if err != nil {
+		return entities.Deposit{}, err
+	}
+	now := time.Now()
+	deposit.ClosedAt = &now
+	deposit.Status = entities.DepositClosed
+	return ds.update(ctx, lease, deposit, entities.DepositProposed, action)
+}
+
+// DisputeDeductions lets the tenant reject the deductions the landlord itemised, giving the reason.
+// The deposit stays open until the tenant acknowledges revised deductions.
+func (ds *DepositService) DisputeDeductions(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID, reason string) (entities.Deposit, error) {
+	const action = "dispute the deposit deductions"
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return entities.Deposit{}, fmt.Errorf("%w: a dispute needs a reason", ErrInvalidDeposit)
+	}
+	lease, deposit, err := ds.proposal(ctx, session, leaseID, action)
+	if err != nil {
+		return entities.Deposit{}, err
+	}
+	deposit.Dispute = &entities.DepositDispute{Reason: reason, RaisedAt: time.Now()}
+	deposit.Status = entities.DepositDisputed
+	return ds.update(ctx, lease, deposit, entities.DepositProposed, action)
+}
+
+// proposal finds the lease of the logged in tenant and its deposit, which has to wait for the tenant.
+func (ds *DepositService) proposal(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID, action string) (*entities.Lease, entities.Deposit, error) {
+	if err := checkSession(session); err != nil {
+		return nil, entities.Deposit{}, err
+	}
+	lease, err := ds.findLease(ctx, leaseID)
+	if err != nil {
+		return nil, entities.Deposit{}, err
+	}
+	if lease.TenantName != session.Username() {
+		return nil, entities.Deposit{}, &ForbiddenError{Username: session.Username(), Action: action, Reason: "it is not their lease"}
+	}
+	deposit, err := ds.current(ctx, lease)
+	if err != nil {
+		return nil, entities.Deposit{}, err
+	}
+	if deposit.Status != entities.DepositProposed {
+		return nil, entities.Deposit{}, &DepositStateError{Status: deposit.Status, Action: action}
+	}
+	return lease, deposit, nil
+}
+
+// update replaces the deposit if it is still in from. A deposit that changed meanwhile is reported
+// in its new status.
+func (ds *DepositService) update(ctx context.Context, lease *entities.Lease, deposit entities.Deposit, from entities.DepositStatus, action string) (entities.Deposit, error) {
+	updated, err := ds.depositRepo.UpdateDeposit(ctx, deposit, from)
+	if err != nil {
+		return entities.Deposit{}, err
+	}
+	if !updated {
+		return entities.Deposit{}, ds.stateError(ctx, lease, action)
+	}
+	return deposit, nil
+}
+
+func (ds *DepositService) stateError(ctx context.Context, lease *entities.Lease, action string) error {
+	current, err := ds.current(ctx, lease)
+	if err != nil {
+		return err
+	}
+	return &DepositStateError{Status: current.Status, Action: action}
+}
+
+// current gives the deposit of the lease, or an awaited one if none was received yet.
+func (ds *DepositService) current(ctx context.Context, lease *entities.Lease) (entities.Deposit, error) {
+	deposit, err := ds.depositRepo.FindDepositByLease(ctx, lease.ID)
+	if err != nil {
+		return entities.Deposit{}, err
+	}
+	if deposit == nil {
+		return entities.Deposit{
+			LeaseID:    lease.ID,
+			Agreed:     lease.Deposit,
+			Status:     entities.DepositAwaited,
+			Deductions: []entities.DepositDeduction{},
+		}, nil
+	}
+	return *deposit, nil
+}
+
+// landlordLease finds the lease, which has to be of a property of the logged in landlord.
+func (ds *DepositService) landlordLease(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID, action string) (*entities.Lease, error) {
+	lease, err := ds.findLease(ctx, leaseID)
+	if err != nil {
+		return nil, err
+	}
+	if lease.LandlordName != session.Username() {
+		return nil, &ForbiddenError{Username: session.Username(), Action: action, Reason: "it is not a lease of one of their properties"}
+	}
+	return lease, nil
+}
+
+func (ds *DepositService) findLease(ctx context.Context, id primitive.ObjectID) (*entities.Lease, error) {
+	lease, err := ds.leaseRepo.FindLeaseByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if lease == nil {
+		return nil, ErrLeaseNotFound
+	}
+	return lease, nil
+}
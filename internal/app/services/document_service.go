@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+	"strings"
+	"time"
+)
+
+var ErrPaymentNotFound = errors.New("payment not found")
+
+// DocumentService draws up rent receipts and printable copies of lease agreements for the parties to a lease.
+type DocumentService struct {
+	leaseRepo    interfaces.LeaseRepo
+	ledgerRepo   interfaces.LedgerRepo
+	propertyRepo interfaces.PropertyRepo
+	userRepo     interfaces.UserRepo
+	renderer     interfaces.DocumentRenderer
+}
+
+// NewDocumentService creates the service drawing up documents with the renderer.
+func NewDocumentService(leaseRepo interfaces.LeaseRepo, ledgerRepo interfaces.LedgerRepo, propertyRepo interfaces.PropertyRepo, userRepo interfaces.UserRepo, renderer interfaces.DocumentRenderer) *DocumentService {
+	return &DocumentService{
+		leaseRepo:    leaseRepo,
+		ledgerRepo:   ledgerRepo,
+		propertyRepo: propertyRepo,
+		userRepo:     userRepo,
+		renderer:     renderer,
+	}
+}
+
+// RentReceipt draws up the receipt of a payment recorded for a lease, for its tenant or landlord.
+func (ds *DocumentService) RentReceipt(ctx context.Context, session *entities.Session, leaseID, paymentID primitive.ObjectID) (entities.Document, error) {
+	lease, err := ds.partyLease(ctx, session, leaseID, "get a rent receipt")
+	if err != nil {
+		return entities.Document{}, err
+	}
+	payments, err := ds.ledgerRepo.FindPaymentsByLease(ctx, lease.ID)
+	if err != nil {
+		return entities.Document{}, err
+	}
+	var payment *entities.Payment
+	for i := range payments {
+		if payments[i].ID == paymentID {
+			payment = &payments[i]
+			break
+		}
+	}
+	if payment == nil {
+		return entities.Document{}, ErrPaymentNotFound
+	}
+
+	data := entities.ReceiptData{
+		Number:   ReceiptNumber(*payment),
+		IssuedOn: time.Now(),
+		Payment:  *payment,
+		Lease:    *lease,
+	}
+	if data.Property, data.Landlord, data.Tenant, err = ds.parties(ctx, lease); err != nil {
+		return entities.Document{}, err
+	}
+	return ds.renderer.RentReceipt(data)
+}
+
+// LeaseAgreement draws up a printable copy of a lease agreement for its tenant or landlord.
+// Cancelled leases were never in force and have none.
+func (ds *DocumentService) LeaseAgreement(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID) (entities.Document, error) {
+	const action = "get a copy of the lease agreement"
+	lease, err := ds.partyLease(ctx, session, leaseID, action)
+	if err != nil {
+		return entities.Document{}, err
+	}
+	if lease.Status == entities.LeaseCancelled {
+		return entities.Document{}, &LeaseStateError{Status: lease.Status, Action: action}
+	}
+
+	data := entities.LeaseDocumentData{IssuedOn: time.Now(), Lease: *lease}
+	if data.Property, data.Landlord, data.Tenant, err = ds.parties(ctx, lease); err != nil {
+		return entities.Document{}, err
+	}
+	return ds.renderer.LeaseAgreement(data)
+}
+
+// ReceiptNumber gives the number of the receipt of a payment: the month it was paid in and the end of its ID.
+func ReceiptNumber(payment entities.Payment) string {
+	hex := payment.ID.Hex()
+	return "RNT-" + payment.PaidOn.Format("200601") + "-" + strings.ToUpper(hex[len(hex)-8:])
+}
+
+// partyLease finds the lease, which the logged in user has to be the tenant or landlord of.
+func (ds *DocumentService) partyLease(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID, action string) (*entities.Lease, error) {
+	if err := checkSession(session); err != nil {
+		return nil, err
+	}
+	lease, err := ds.leaseRepo.FindLeaseByID(ctx, leaseID)
+	if err != nil {
+		return nil, err
+	}
+	if lease == nil {
+		return nil, ErrLeaseNotFound
+	}
+	if !isPartyTo(session, lease) {
+		return nil, &ForbiddenError{Username: session.Username(), Action: action, Reason: "they are not a party to the lease"}
+	}
+	return lease, nil
+}
+
+// parties looks up the property and the users named on the documents of the lease. Documents can still be
+// drawn up after the property or a user was deleted, with what the lease records of them.
+func (ds *DocumentService) parties(ctx context.Context, lease *entities.Lease) (entities.Property, entities.Party, entities.Party, error) {
+	property := entities.Property{ID: lease.PropertyID}
+	found, err := ds.propertyRepo.FindByID(ctx, lease.PropertyID)
+	if err != nil {
+		return entities.Property{}, entities.Party{}, entities.Party{}, err
+	}
+	if found != nil {
+		property = *found
+	}
+	landlord, err := ds.party(ctx, lease.LandlordName)
+	if err != nil {
+		return entities.Property{}, entities.Party{}, entities.Party{}, err
+	}
+	tenant, err := ds.party(ctx, lease.TenantName)
+	if err != nil {
+		return entities.Property{}, entities.Party{}, entities.Party{}, err
+	}
+	return property, landlord, tenant, nil
+}
+
+func (ds *DocumentService) party(ctx context.Context, username string) (entities.Party, error) {
+	party := entities.Party{Username: username}
+	user, err := ds.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return entities.Party{}, err
+	}
+	if user != nil {
+		party.Name = user.Name
+		party.Email = user.Email
+		party.PhoneNumber = user.PhoneNumber
+	}
+	return party, nil
+}
@@ -0,0 +1,458 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+	"time"
+)
+
+var (
+	ErrLeaseNotFound     = errors.New("lease not found")
+	ErrNoRenewalOffer    = errors.New("the lease has no open renewal offer")
+	ErrInvalidLeaseTerms = errors.New("invalid lease terms")
+	ErrInvalidRentRules  = errors.New("invalid rent rules")
+)
+
+// LeaseStateError is returned for an action the lease does not allow in its status,
+// such as giving notice on a lease that is not signed yet.
+type LeaseStateError struct {
+	Status entities.LeaseStatus
+	Action string
+}
+
+func (e *LeaseStateError) Error() string {
+	return fmt.Sprintf("cannot %s: the lease is %s", e.Action, e.Status)
+}
+
+// Is makes errors.Is(err, ErrInvalidTransition) true for a LeaseStateError.
+func (e *LeaseStateError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// Terms of the leases drawn up when a rent request is accepted.
+const (
+	DefaultLeaseMonths      = 11 // Length of the lease
+	DefaultDepositMonths    = 2  // Security deposit, in months of rent
+	DefaultNoticePeriodDays = 30 // Notice needed to end the lease early
+)
+
+type LeaseService struct {
+	leaseRepo           interfaces.LeaseRepo
+	propertyRepo        interfaces.PropertyRepo
+	relistNeedsApproval bool
+}
+
+// NewLeaseService creates the service. With relistNeedsApproval a property whose lease ends has to be
+// approved again before it is shown to tenants.
+func NewLeaseService(leaseRepo interfaces.LeaseRepo, propertyRepo interfaces.PropertyRepo, relistNeedsApproval bool) *LeaseService {
+	return &LeaseService{
+		leaseRepo:           leaseRepo,
+		propertyRepo:        propertyRepo,
+		relistNeedsApproval: relistNeedsApproval,
+	}
+}
+
+// GetLeasesForTenant gives all the leases of the logged in tenant.
+func (ls *LeaseService) GetLeasesForTenant(ctx context.Context, session *entities.Session) ([]entities.Lease, error) {
+	if err := authorize(session, entities.PermRentProperties, "see their leases"); err != nil {
+		return nil, err
+	}
+	return ls.leaseRepo.FindLeasesByTenant(ctx, session.Username())
+}
+
+// GetLeasesForLandlord gives all the leases of the properties of the logged in landlord.
+func (ls *LeaseService) GetLeasesForLandlord(ctx context.Context, session *entities.Session) ([]entities.Lease, error) {
+	if err := authorize(session, entities.PermListProperties, "see the leases of their properties"); err != nil {
+		return nil, err
+	}
+	return ls.leaseRepo.FindLeasesByLandlord(ctx, session.Username())
+}
+
+// GetLease gives a lease the logged in user is the tenant or the landlord of. Moderators can see every lease.
+func (ls *LeaseService) GetLease(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID) (entities.Lease, error) {
+	if err := checkSession(session); err != nil {
+		return entities.Lease{}, err
+	}
+	lease, err := ls.findLease(ctx, leaseID)
+	if err != nil {
+		return entities.Lease{}, err
+	}
+	if !isPartyTo(session, lease) && !session.Can(entities.PermModerateProperties) {
+		return entities.Lease{}, &ForbiddenError{Username: session.Username(), Action: "see the lease", Reason: "they are not a party to it"}
+	}
+	return *lease, nil
+}
+
+// GiveNotice ends an active lease early on behalf of its tenant or landlord. The lease keeps running for
+// its notice period from today, or until its end date if that comes first, and an open renewal offer lapses.
+// EndLeases ends it on the move-out date.
+func (ls *LeaseService) GiveNotice(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID) (entities.Lease, error) {
+	const action = "give notice on the lease"
+	if err := checkSession(session); err != nil {
+		return entities.Lease{}, err
+	}
+	lease, err := ls.findLease(ctx, leaseID)
+	if err != nil {
+		return entities.Lease{}, err
+	}
+	if !isPartyTo(session, lease) {
+		return entities.Lease{}, &ForbiddenError{Username: session.Username(), Action: action, Reason: "they are not a party to it"}
+	}
+	if lease.Status != entities.LeaseActive {
+		return entities.Lease{}, &LeaseStateError{Status: lease.Status, Action: action}
+	}
+
+	now := time.Now()
+	if moveOut := leaseDay(now).AddDate(0, 0, lease.NoticePeriodDays); moveOut.Before(lease.EndDate) {
+		lease.EndDate = moveOut
+	}
+	lease.Notice = &entities.Notice{GivenBy: session.Username(), GivenAt: now}
+	lapseRenewal(lease, now)
+	lease.Status = entities.LeaseEnding
+	return ls.update(ctx, lease, entities.LeaseActive, action)
+}
+
+// OfferRenewal lets the landlord offer to extend an active lease by some months at a new monthly rent.
+// A new offer replaces one the tenant has not answered yet.
+func (ls *LeaseService) OfferRenewal(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID, monthlyRent float64, months int) (entities.Lease, error) {
+	const action = "offer to renew the lease"
+	if err := authorize(session, entities.PermListProperties, action); err != nil {
+		return entities.Lease{}, err
+	}
+	if monthlyRent <= 0 {
+		return entities.Lease{}, fmt.Errorf("%w: the monthly rent must be positive", ErrInvalidLeaseTerms)
+	}
+	if months <= 0 {
+		return entities.Lease{}, fmt.Errorf("%w: the lease must be renewed for at least a month", ErrInvalidLeaseTerms)
+	}
+	lease, err := ls.findLease(ctx, leaseID)
+	if err != nil {
+		return entities.Lease{}, err
+	}
+	if lease.LandlordName != session.Username() {
+		return entities.Lease{}, &ForbiddenError{Username: session.Username(), Action: action, Reason: "it is not a lease of one of their properties"}
+	}
+	if lease.Status != entities.LeaseActive {
+		return entities.Lease{}, &LeaseStateError{Status: lease.Status, Action: action}
+	}
+
+	lease.Renewal = &entities.RenewalOffer{
+		MonthlyRent: monthlyRent,
+		EndDate:     lease.EndDate.AddDate(0, months, 0),
+		OfferedAt:   time.Now(),
+		Status:      entities.RenewalOffered,
+	}
+	return ls.update(ctx, lease, entities.LeaseActive, action)
+}
+
+// AnswerRenewal lets the tenant accept or decline the open renewal offer of their lease.
+// Accepting extends the lease to the offered end date. The offered rent is charged from the end of the
+// current term on, and an escalation rule of the lease counts again from then.
+func (ls *LeaseService) AnswerRenewal(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID, accept bool) (entities.Lease, error) {
+	const action = "answer the renewal offer"
+	if err := authorize(session, entities.PermRentProperties, action); err != nil {
+		return entities.Lease{}, err
+	}
+	lease, err := ls.findLease(ctx, leaseID)
+	if err != nil {
+		return entities.Lease{}, err
+	}
+	if lease.TenantName != session.Username() {
+		return entities.Lease{}, &ForbiddenError{Username: session.Username(), Action: action, Reason: "it is not their lease"}
+	}
+	if lease.Status != entities.LeaseActive {
+		return entities.Lease{}, &LeaseStateError{Status: lease.Status, Action: action}
+	}
+	if !lease.HasOpenRenewal() {
+		return entities.Lease{}, ErrNoRenewalOffer
+	}
+
+	now := time.Now()
+	offer := *lease.Renewal
+	offer.AnsweredAt = &now
+	offer.Status = entities.RenewalDeclined
+	if accept {
+		offer.Status = entities.RenewalAccepted
+		past := entities.PastRent{MonthlyRent: lease.MonthlyRent, From: lease.RentFrom(), Until: lease.EndDate}
+		lease.PastRents = append(append([]entities.PastRent(nil), lease.PastRents...), past)
+		lease.MonthlyRent = offer.MonthlyRent
+		lease.EndDate = offer.EndDate
+		if lease.Rules != nil && lease.Rules.Escalation != nil {
+			rules, escalation := *lease.Rules, *lease.Rules.Escalation
+			escalation.Since = past.Until
+			rules.Escalation = &escalation
+			lease.Rules = &rules
+		}
+	}
+	lease.Renewal = &offer
+	return ls.update(ctx, lease, entities.LeaseActive, action)
+}
+
+// SetRentRules lets the landlord set the rules for raising the rent and charging late fees on a lease of one of
+// their properties that is not over yet. The rules replace those set before; leaving both out removes them.
+// Dues already generated keep their amount.
+func (ls *LeaseService) SetRentRules(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID, rules entities.RentRules) (entities.Lease, error) {
+	const action = "set the rent rules"
+	if err := authorize(session, entities.PermListProperties, action); err != nil {
+		return entities.Lease{}, err
+	}
+	if err := validateRentRules(rules); err != nil {
+		return entities.Lease{}, err
+	}
+	lease, err := ls.findLease(ctx, leaseID)
+	if err != nil {
+		return entities.Lease{}, err
+	}
+	if lease.LandlordName != session.Username() {
+		return entities.Lease{}, &ForbiddenError{Username: session.Username(), Action: action, Reason: "it is not a lease of one of their properties"}
+	}
+	if !lease.Status.OccupiesProperty() {
+		return entities.Lease{}, &LeaseStateError{Status: lease.Status, Action: action}
+	}
+
+	lease.Rules = nil
+	if rules.Escalation != nil || rules.LateFee != nil {
+		if rules.Escalation != nil {
+			escalation := *rules.Escalation
+			escalation.Since = lease.RentFrom()
+			rules.Escalation = &escalation
+		}
+		if rules.LateFee != nil {
+			lateFee := *rules.LateFee
+			rules.LateFee = &lateFee
+		}
+		lease.Rules = &rules
+	}
+	return ls.update(ctx, lease, lease.Status, action)
+}
+
+// EndLeases ends the active leases and those under notice whose end date is not after the given time,
+// and returns how many were ended. Their properties are put back on the market, waiting for approval
+// again if the service was created so. Leases changed meanwhile are left alone.
+func (ls *LeaseService) EndLeases(ctx context.Context, session *entities.Session, now time.Time) (int, error) {
+	if err := authorize(session, entities.PermReviewProperties, "end leases"); err != nil {
+		return 0, err
+	}
+
+	ended := 0
+	for _, status := range []entities.LeaseStatus{entities.LeaseActive, entities.LeaseEnding} {
+		leases, err := ls.leaseRepo.FindLeasesByStatus(ctx, status)
+		if err != nil {
+			return ended, err
+		}
+		for i := range leases {
+			if leases[i].EndDate.After(now) {
+				continue
+			}
+			updated, err := ls.end(ctx, leases[i], now)
+			if err != nil {
+				return ended, err
+			}
+			if !updated {
+				continue
+			}
+			if ls.relistNeedsApproval {
+				if err := ls.propertyRepo.UpdateApprovalStatus(ctx, leases[i].PropertyID, false, session.Username()); err != nil {
+					return ended, err
+				}
+			}
+			ended++
+		}
+	}
+	return ended, nil
+}
+
+// end marks the lease ended, letting an open renewal offer lapse, and puts the property back on the market.
+// It reports false when the lease was changed meanwhile.
+func (ls *LeaseService) end(ctx context.Context, lease entities.Lease, now time.Time) (bool, error) {
+	from := lease.Status
+	lapseRenewal(&lease, now)
+	lease.Status = entities.LeaseEnded
+	updated, err := ls.leaseRepo.UpdateLease(ctx, lease, from)
+	if err != nil || !updated {
+		return false, err
+	}
+	if _, err := ls.propertyRepo.SetRented(ctx, lease.PropertyID, false); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (ls *LeaseService) findLease(ctx context.Context, id primitive.ObjectID) (*entities.Lease, error) {
+	lease, err := ls.leaseRepo.FindLeaseByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if lease == nil {
+		return nil, ErrLeaseNotFound
+	}
+	return lease, nil
+}
+
+// update stores the changed lease if it is still in from. A lease changed by someone else
+// in the meantime fails with a LeaseStateError from its new status.
+func (ls *LeaseService) update(ctx context.Context, lease *entities.Lease, from entities.LeaseStatus, action string) (entities.Lease, error) {
+	updated, err := ls.leaseRepo.UpdateLease(ctx, *lease, from)
+	if err != nil {
+		return entities.Lease{}, err
+	}
+	if !updated {
+		current, err := ls.findLease(ctx, lease.ID)
+		if err != nil {
+			return entities.Lease{}, err
+		}
+		return entities.Lease{}, &LeaseStateError{Status: current.Status, Action: action}
+	}
+	return *lease, nil
+}
+
+func validateRentRules(rules entities.RentRules) error {
+	if e := rules.Escalation; e != nil {
+		if e.Percent < 0 || e.Amount < 0 || e.Percent == 0 && e.Amount == 0 {
+			return fmt.Errorf("%w: the rent must be raised by a positive percentage or amount", ErrInvalidRentRules)
+		}
+		if e.EveryMonths <= 0 {
+			return fmt.Errorf("%w: the rent must be raised every month or less often", ErrInvalidRentRules)
+		}
+	}
+	if f := rules.LateFee; f != nil {
+		if f.GraceDays < 0 {
+			return fmt.Errorf("%w: the grace days must not be negative", ErrInvalidRentRules)
+		}
+		if f.Flat < 0 || f.PerDay < 0 || f.Flat == 0 && f.PerDay == 0 {
+			return fmt.Errorf("%w: the late fee must charge a positive flat fee or fee per day", ErrInvalidRentRules)
+		}
+		if f.Max < 0 {
+			return fmt.Errorf("%w: the most a late fee can be must not be negative", ErrInvalidRentRules)
+		}
+	}
+	return nil
+}
+
+func isPartyTo(session *entities.Session, lease *entities.Lease) bool {
+	return lease.TenantName == session.Username() || lease.LandlordName == session.Username()
+}
+
+// lapseRenewal marks a renewal offer the tenant has not answered as lapsed.
+func lapseRenewal(lease *entities.Lease, now time.Time) {
+	if !lease.HasOpenRenewal() {
+		return
+	}
+	offer := *lease.Renewal
+	offer.Status = entities.RenewalLapsed
+	offer.AnsweredAt = &now
+	lease.Renewal = &offer
+}
+
+// leaseDay is the start, in UTC, of the day of t. Leases start and end on whole days.
+func leaseDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// leaseKeeper draws up leases and changes their status, keeping the rented flag of the property in step:
+// a property is rented out exactly while it has a pending or active lease.
+type leaseKeeper struct {
+	leaseRepo    interfaces.LeaseRepo
+	propertyRepo interfaces.PropertyRepo
+}
+
+// open rents out the property of the request and draws up a pending lease for it with the default terms,
+// starting on the day it is drawn up, and the deposit the listing asks for. Renting out the property comes
+// first and only succeeds for a property that is not rented, so only one lease can be drawn up for it at a
+// time; otherwise ErrPropertyRented is returned.
+func (k leaseKeeper) open(ctx context.Context, request *entities.Request, now time.Time) (*entities.Lease, error) {
+	property, err := k.propertyRepo.FindByID(ctx, request.PropertyID)
+	if err != nil {
+		return nil, err
+	}
+	if property == nil {
+		return nil, ErrPropertyNotFound
+	}
+	claimed, err := k.propertyRepo.SetRented(ctx, request.PropertyID, true)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrPropertyRented
+	}
+
+	deposit := property.Deposit
+	if deposit <= 0 {
+		deposit = property.RentAmount * DefaultDepositMonths
+	}
+	start := leaseDay(now)
+	lease := &entities.Lease{
+		ID:               primitive.NewObjectID(),
+		RequestID:        request.ID,
+		PropertyID:       request.PropertyID,
+		TenantName:       request.TenantName,
+		LandlordName:     request.LandlordName,
+		StartDate:        start,
+		EndDate:          start.AddDate(0, DefaultLeaseMonths, 0),
+		MonthlyRent:      property.RentAmount,
+		Deposit:          deposit,
+		NoticePeriodDays: DefaultNoticePeriodDays,
+		Status:           entities.LeasePending,
+		CreatedAt:        now,
+	}
+	if err := k.leaseRepo.SaveLease(ctx, *lease); err != nil {
+		if _, releaseErr := k.propertyRepo.SetRented(ctx, request.PropertyID, false); releaseErr != nil {
+			return nil, errors.Join(err, releaseErr)
+		}
+		return nil, err
+	}
+	return lease, nil
+}
+
+// setStatus moves the lease from its status to the given one, keeping the property rented out exactly while
+// the lease occupies it. A lease changed by someone else meanwhile fails with a LeaseStateError from its new
+// status; one that would occupy a property rented out meanwhile fails with ErrPropertyRented.
+func (k leaseKeeper) setStatus(ctx context.Context, lease *entities.Lease, to entities.LeaseStatus) error {
+	claims := !lease.Status.OccupiesProperty() && to.OccupiesProperty()
+	releases := lease.Status.OccupiesProperty() && !to.OccupiesProperty()
+	if claims {
+		claimed, err := k.propertyRepo.SetRented(ctx, lease.PropertyID, true)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			return ErrPropertyRented
+		}
+	}
+
+	updated, err := k.leaseRepo.UpdateLeaseStatus(ctx, lease.ID, lease.Status, to)
+	if err == nil && !updated {
+		err = k.stateError(ctx, lease.ID, to)
+	}
+	if err != nil {
+		if claims {
+			if _, releaseErr := k.propertyRepo.SetRented(ctx, lease.PropertyID, false); releaseErr != nil {
+				return errors.Join(err, releaseErr)
+			}
+		}
+		return err
+	}
+	if releases {
+		if _, err := k.propertyRepo.SetRented(ctx, lease.PropertyID, false); err != nil {
+			return err
+		}
+	}
+	lease.Status = to
+	return nil
+}
+
+// stateError explains why the lease could not be moved to the status: it has since been moved on or removed.
+func (k leaseKeeper) stateError(ctx context.Context, id primitive.ObjectID, to entities.LeaseStatus) error {
+	current, err := k.leaseRepo.FindLeaseByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return ErrLeaseNotFound
+	}
+	return &LeaseStateError{Status: current.Status, Action: fmt.Sprintf("mark the lease %s", to)}
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+	"time"
+)
+
+var ErrInvalidPayment = errors.New("invalid payment")
+
+// How many months a rent schedule looks ahead.
+const (
+	DefaultScheduleMonths = 12
+	MaxScheduleMonths     = 120
+)
+
+// LedgerService keeps the rent account of signed leases. The rent of each month is due on the day of the month
+// the lease started; dues are generated when the ledger of a lease is read or written, at the rent of the lease
+// at that time, so a renewal at a new rent applies to the months that are not due yet. The rent rules of the
+// lease raise the rent of the dues generated and charge late fees on the statement.
+type LedgerService struct {
+	ledgerRepo interfaces.LedgerRepo
+	leaseRepo  interfaces.LeaseRepo
+}
+
+// NewLedgerService creates the service on the ledger and the leases it keeps the account of.
+func NewLedgerService(ledgerRepo interfaces.LedgerRepo, leaseRepo interfaces.LeaseRepo) *LedgerService {
+	return &LedgerService{
+		ledgerRepo: ledgerRepo,
+		leaseRepo:  leaseRepo,
+	}
+}
+
+// Statement gives the account of a lease today to its tenant or landlord. Moderators can see every statement.
+func (ls *LedgerService) Statement(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID) (entities.Statement, error) {
+	if err := checkSession(session); err != nil {
+		return entities.Statement{}, err
+	}
+	lease, err := ls.findLease(ctx, leaseID)
+	if err != nil {
+		return entities.Statement{}, err
+	}
+	if !isPartyTo(session, lease) && !session.Can(entities.PermModerateProperties) {
+		return entities.Statement{}, &ForbiddenError{Username: session.Username(), Action: "see the rent statement", Reason: "they are not a party to the lease"}
+	}
+
+	now := time.Now()
+	if err := ls.syncDues(ctx, lease, now); err != nil {
+		return entities.Statement{}, err
+	}
+	dues, err := ls.ledgerRepo.FindDuesByLease(ctx, lease.ID)
+	if err != nil {
+		return entities.Statement{}, err
+	}
+	payments, err := ls.ledgerRepo.FindPaymentsByLease(ctx, lease.ID)
+	if err != nil {
+		return entities.Statement{}, err
+	}
+	return entities.NewStatement(*lease, dues, payments, now), nil
+}
+
+// RecordPayment lets the landlord record rent received under a signed lease of one of their properties.
+// A payment without a date was made today; payments cannot be dated in the future.
+func (ls *LedgerService) RecordPayment(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID, payment entities.Payment) (entities.Payment, error) {
+	const action = "record a rent payment"
+	if err := authorize(session, entities.PermListProperties, action); err != nil {
+		return entities.Payment{}, err
+	}
+	now := time.Now()
+	if payment.PaidOn.IsZero() {
+		payment.PaidOn = leaseDay(now)
+	}
+	if err := validatePayment(payment, now); err != nil {
+		return entities.Payment{}, err
+	}
+	lease, err := ls.findLease(ctx, leaseID)
+	if err != nil {
+		return entities.Payment{}, err
+	}
+	if lease.LandlordName != session.Username() {
+		return entities.Payment{}, &ForbiddenError{Username: session.Username(), Action: action, Reason: "it is not a lease of one of their properties"}
+	}
+	if !lease.Status.IsSigned() {
+		return entities.Payment{}, &LeaseStateError{Status: lease.Status, Action: action}
+	}
+	if err := ls.syncDues(ctx, lease, now); err != nil {
+		return entities.Payment{}, err
+	}
+
+	payment.ID = primitive.NewObjectID()
+	payment.LeaseID = lease.ID
+	payment.PaidOn = leaseDay(payment.PaidOn)
+	payment.RecordedBy = session.Username()
+	payment.RecordedAt = now
+	if err := ls.ledgerRepo.SavePayment(ctx, payment); err != nil {
+		return entities.Payment{}, err
+	}
+	return payment, nil
+}
+
+// Schedule shows a party to the lease, or a moderator, the rent due in the months ahead, at most months of them,
+// under the rent rules of the lease or, as a dry run, under the rules given instead. Nothing is saved.
+func (ls *LedgerService) Schedule(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID, rules *entities.RentRules, months int) (entities.Schedule, error) {
+	if err := checkSession(session); err != nil {
+		return entities.Schedule{}, err
+	}
+	if months <= 0 || months > MaxScheduleMonths {
+		return entities.Schedule{}, fmt.Errorf("%w: the schedule covers 1 to %d months", ErrInvalidRentRules, MaxScheduleMonths)
+	}
+	lease, err := ls.findLease(ctx, leaseID)
+	if err != nil {
+		return entities.Schedule{}, err
+	}
+	if !isPartyTo(session, lease) && !session.Can(entities.PermModerateProperties) {
+		return entities.Schedule{}, &ForbiddenError{Username: session.Username(), Action: "see the rent schedule", Reason: "they are not a party to the lease"}
+	}
+	if rules != nil {
+		if err := validateRentRules(*rules); err != nil {
+			return entities.Schedule{}, err
+		}
+		dryRun := *rules
+		if dryRun.Escalation != nil {
+			escalation := *dryRun.Escalation
+			escalation.Since = lease.RentFrom()
+			dryRun.Escalation = &escalation
+		}
+		changed := *lease
+		changed.Rules = &dryRun
+		lease = &changed
+	}
+
+	schedule := entities.Schedule{LeaseID: lease.ID, Dues: []entities.ScheduledDue{}}
+	if lease.Rules != nil {
+		schedule.Rules = *lease.Rules
+	}
+	if !lease.Status.OccupiesProperty() {
+		return schedule, nil // Nothing more will fall due
+	}
+	today := leaseDay(time.Now())
+	previous := 0.0
+	for period := 1; len(schedule.Dues) < months; period++ {
+		due, ok := rentDue(lease, period)
+		if !ok {
+			break
+		}
+		if lease.Status.IsSigned() && !due.DueDate.After(today) {
+			previous = due.Amount
+			continue // Already due
+		}
+		scheduled := entities.ScheduledDue{Period: period, DueDate: due.DueDate, Amount: due.Amount}
+		if previous > 0 {
+			scheduled.Increase = entities.RoundMoney(due.Amount - previous)
+		}
+		if lateFee := schedule.Rules.LateFee; lateFee != nil {
+			lateFrom := due.DueDate.AddDate(0, 0, lateFee.GraceDays+1)
+			scheduled.LateFrom = &lateFrom
+			scheduled.LateFee = lateFee.For(lateFee.GraceDays + 1)
+		}
+		schedule.Dues = append(schedule.Dues, scheduled)
+		previous = due.Amount
+	}
+	return schedule, nil
+}
+
+func validatePayment(payment entities.Payment, now time.Time) error {
+	if payment.Amount <= 0 {
+		return fmt.Errorf("%w: the amount must be positive", ErrInvalidPayment)
+	}
+	if !payment.Mode.IsValid() {
+		return fmt.Errorf("%w: unknown payment mode %q", ErrInvalidPayment, payment.Mode)
+	}
+	if leaseDay(payment.PaidOn).After(leaseDay(now)) {
+		return fmt.Errorf("%w: the payment date is in the future", ErrInvalidPayment)
+	}
+	return nil
+}
+
+// syncDues creates the dues of a signed lease that fell due up to now and are missing. A due that another
+// caller created meanwhile is left as it is.
+func (ls *LedgerService) syncDues(ctx context.Context, lease *entities.Lease, now time.Time) error {
+	if !lease.Status.IsSigned() {
+		return nil
+	}
+	dues, err := ls.ledgerRepo.FindDuesByLease(ctx, lease.ID)
+	if err != nil {
+		return err
+	}
+	existing := make(map[int]bool, len(dues))
+	for _, due := range dues {
+		existing[due.Period] = true
+	}
+
+	for period := 1; ; period++ {
+		due, ok := rentDue(lease, period)
+		if !ok || due.DueDate.After(now) {
+			return nil
+		}
+		if existing[period] {
+			continue
+		}
+		due.CreatedAt = now
+		if _, err := ls.ledgerRepo.CreateDue(ctx, due); err != nil {
+			return err
+		}
+	}
+}
+
+// rentDue gives the due of the lease for the period, or false if the lease ends before the period starts.
+// The rent is that of the term the period starts in, raised under the escalation rule of the lease, if any,
+// from the start of that term. The rent of a last month cut short by the end of the lease is charged for the
+// days of it that are leased.
+func rentDue(lease *entities.Lease, period int) (entities.RentDue, bool) {
+	start := lease.StartDate.AddDate(0, period-1, 0)
+	if !start.Before(lease.EndDate) {
+		return entities.RentDue{}, false
+	}
+	amount, since := lease.RentOn(start)
+	if lease.Rules != nil && lease.Rules.Escalation != nil {
+		escalation := *lease.Rules.Escalation
+		if start.Before(lease.RentFrom()) {
+			escalation.Since = since
+		}
+		amount = escalation.Apply(amount, start)
+	}
+	if next := lease.StartDate.AddDate(0, period, 0); next.After(lease.EndDate) {
+		amount = entities.RoundMoney(amount * float64(entities.DaysBetween(start, lease.EndDate)) / float64(entities.DaysBetween(start, next)))
+	}
+	return entities.RentDue{
+		ID:      primitive.NewObjectID(),
+		LeaseID: lease.ID,
+		Period:  period,
+		DueDate: start,
+		Amount:  amount,
+	}, true
+}
+
+func (ls *LedgerService) findLease(ctx context.Context, id primitive.ObjectID) (*entities.Lease, error) {
+	lease, err := ls.leaseRepo.FindLeaseByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if lease == nil {
+		return nil, ErrLeaseNotFound
+	}
+	return lease, nil
+}
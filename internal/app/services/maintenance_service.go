@@ -0,0 +1,328 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	ErrTicketNotFound = errors.New("maintenance ticket not found")
+	ErrInvalidTicket  = errors.New("invalid maintenance ticket")
+)
+
+// TicketStateError is returned for a change the maintenance ticket does not allow in its status,
+// such as closing a ticket the landlord has not resolved yet.
+type TicketStateError struct {
+	Status entities.TicketStatus
+	Action string
+}
+
+func (e *TicketStateError) Error() string {
+	return fmt.Sprintf("cannot %s: the ticket is %s", e.Action, e.Status)
+}
+
+// Is makes errors.Is(err, ErrInvalidTransition) true for a TicketStateError.
+func (e *TicketStateError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// landlordTicketMoves lists the statuses the landlord can move a ticket to from each status.
+// The landlord only moves a ticket forward; skipping a step is fine for a quick fix.
+var landlordTicketMoves = map[entities.TicketStatus][]entities.TicketStatus{
+	entities.TicketOpen:         {entities.TicketAcknowledged, entities.TicketInProgress, entities.TicketResolved},
+	entities.TicketAcknowledged: {entities.TicketInProgress, entities.TicketResolved},
+	entities.TicketInProgress:   {entities.TicketResolved},
+}
+
+// tenantTicketMoves lists the statuses the tenant can move a ticket to: once it is resolved they close it,
+// or reopen it if the issue is not fixed.
+var tenantTicketMoves = map[entities.TicketStatus][]entities.TicketStatus{
+	entities.TicketResolved: {entities.TicketClosed, entities.TicketOpen},
+}
+
+// MaintenanceService handles the issues tenants report with the properties they rent. The landlord works
+// through them in their queue, where tickets past the time their priority allows come first.
+type MaintenanceService struct {
+	ticketRepo interfaces.MaintenanceRepo
+	leaseRepo  interfaces.LeaseRepo
+}
+
+// NewMaintenanceService creates the service on the tickets and the leases they are reported under.
+func NewMaintenanceService(ticketRepo interfaces.MaintenanceRepo, leaseRepo interfaces.LeaseRepo) *MaintenanceService {
+	return &MaintenanceService{
+		ticketRepo: ticketRepo,
+		leaseRepo:  leaseRepo,
+	}
+}
+
+// ReportIssue lets the tenant of an active lease, or one they are moving out under, report an issue with
+// the property. An issue without a priority has medium priority.
+func (ms *MaintenanceService) ReportIssue(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID, category entities.MaintenanceCategory, priority entities.MaintenancePriority, description string) (entities.MaintenanceTicket, error) {
+	const action = "report a maintenance issue"
+	if err := authorize(session, entities.PermRentProperties, action); err != nil {
+		return entities.MaintenanceTicket{}, err
+	}
+	if priority == "" {
+		priority = entities.PriorityMedium
+	}
+	description = strings.TrimSpace(description)
+	if err := validateIssue(category, priority, description); err != nil {
+		return entities.MaintenanceTicket{}, err
+	}
+	lease, err := ms.leaseRepo.FindLeaseByID(ctx, leaseID)
+	if err != nil {
+		return entities.MaintenanceTicket{}, err
+	}
+	if lease == nil {
+		return entities.MaintenanceTicket{}, ErrLeaseNotFound
+	}
+	if lease.TenantName != session.Username() {
+		return entities.MaintenanceTicket{}, &ForbiddenError{Username: session.Username(), Action: action, Reason: "it is not their lease"}
+	}
+	if lease.Status != entities.LeaseActive && lease.Status != entities.LeaseEnding {
+		return entities.MaintenanceTicket{}, &LeaseStateError{Status: lease.Status, Action: action}
+	}
+
+	now := time.Now()
+	ticket := entities.MaintenanceTicket{
+		ID:           primitive.NewObjectID(),
+		PropertyID:   lease.PropertyID,
+		LeaseID:      lease.ID,
+		TenantName:   lease.TenantName,
+		LandlordName: lease.LandlordName,
+		Category:     category,
+		Description:  description,
+		Priority:     priority,
+		Status:       entities.TicketOpen,
+		Comments:     []entities.TicketComment{},
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		DueBy:        now.Add(priority.ResolveWithin()),
+	}
+	if err := ms.ticketRepo.SaveTicket(ctx, ticket); err != nil {
+		return entities.MaintenanceTicket{}, err
+	}
+	return ticket, nil
+}
+
+// Ticket shows a maintenance ticket to its tenant or landlord. Moderators can see every ticket.
+func (ms *MaintenanceService) Ticket(ctx context.Context, session *entities.Session, ticketID primitive.ObjectID) (entities.MaintenanceTicket, error) {
+	if err := checkSession(session); err != nil {
+		return entities.MaintenanceTicket{}, err
+	}
+	ticket, err := ms.findTicket(ctx, ticketID)
+	if err != nil {
+		return entities.MaintenanceTicket{}, err
+	}
+	if !isPartyToTicket(session, ticket) && !session.Can(entities.PermModerateProperties) {
+		return entities.MaintenanceTicket{}, &ForbiddenError{Username: session.Username(), Action: "see the maintenance ticket", Reason: "they are not a party to it"}
+	}
+	return *ticket, nil
+}
+
+// TicketsForTenant gives all the tickets the logged in tenant reported, oldest first.
+func (ms *MaintenanceService) TicketsForTenant(ctx context.Context, session *entities.Session) ([]entities.MaintenanceTicket, error) {
+	if err := authorize(session, entities.PermRentProperties, "see their maintenance tickets"); err != nil {
+		return nil, err
+	}
+	tickets, err := ms.ticketRepo.FindTicketsByTenant(ctx, session.Username())
+	if err != nil {
+		return nil, err
+	}
+	markOverdue(tickets, time.Now())
+	return tickets, nil
+}
+
+// MaintenanceQueue gives the tickets on the properties of the logged in landlord that are not closed yet.
+// Overdue tickets come first, then the ones still waiting for the landlord by priority and due time, and
+// last the resolved ones waiting for the tenant.
+func (ms *MaintenanceService) MaintenanceQueue(ctx context.Context, session *entities.Session) ([]entities.MaintenanceTicket, error) {
+	if err := authorize(session, entities.PermListProperties, "see their maintenance queue"); err != nil {
+		return nil, err
+	}
+	tickets, err := ms.ticketRepo.FindTicketsByLandlord(ctx, session.Username())
+	if err != nil {
+		return nil, err
+	}
+	queue := make([]entities.MaintenanceTicket, 0, len(tickets))
+	for _, ticket := range tickets {
+		if ticket.Status != entities.TicketClosed {
+			queue = append(queue, ticket)
+		}
+	}
+	markOverdue(queue, time.Now())
+	sort.SliceStable(queue, func(i, j int) bool {
+		a, b := queue[i], queue[j]
+		if a.Overdue != b.Overdue {
+			return a.Overdue
+		}
+		if a.Status.IsOpen() != b.Status.IsOpen() {
+			return a.Status.IsOpen()
+		}
+		if a.Priority.Rank() != b.Priority.Rank() {
+			return a.Priority.Rank() > b.Priority.Rank()
+		}
+		return a.DueBy.Before(b.DueBy)
+	})
+	return queue, nil
+}
+
+// UpdateTicketStatus moves a ticket on. The landlord acknowledges it, starts the work and resolves it;
+// the tenant then closes it, or reopens it if the issue is not fixed. A note is added to the comments.
+func (ms *MaintenanceService) UpdateTicketStatus(ctx context.Context, session *entities.Session, ticketID primitive.ObjectID, to entities.TicketStatus, note string) (entities.MaintenanceTicket, error) {
+	action := fmt.Sprintf("move the ticket to %s", to)
+	if err := checkSession(session); err != nil {
+		return entities.MaintenanceTicket{}, err
+	}
+	if !isTicketStatus(to) {
+		return entities.MaintenanceTicket{}, fmt.Errorf("%w: unknown status %q", ErrInvalidTicket, to)
+	}
+	ticket, err := ms.findTicket(ctx, ticketID)
+	if err != nil {
+		return entities.MaintenanceTicket{}, err
+	}
+
+	var moves map[entities.TicketStatus][]entities.TicketStatus
+	switch session.Username() {
+	case ticket.LandlordName:
+		if to == entities.TicketClosed {
+			return entities.MaintenanceTicket{}, &ForbiddenError{Username: session.Username(), Action: action, Reason: "only the tenant confirms an issue is fixed"}
+		}
+		moves = landlordTicketMoves
+	case ticket.TenantName:
+		if to != entities.TicketClosed && to != entities.TicketOpen {
+			return entities.MaintenanceTicket{}, &ForbiddenError{Username: session.Username(), Action: action, Reason: "only the landlord works on an issue"}
+		}
+		moves = tenantTicketMoves
+	default:
+		return entities.MaintenanceTicket{}, &ForbiddenError{Username: session.Username(), Action: action, Reason: "they are not a party to the ticket"}
+	}
+	if !canMoveTicket(moves, ticket.Status, to) {
+		return entities.MaintenanceTicket{}, &TicketStateError{Status: ticket.Status, Action: action}
+	}
+
+	now := time.Now()
+	var comment *entities.TicketComment
+	if note = strings.TrimSpace(note); note != "" {
+		comment = &entities.TicketComment{Author: session.Username(), Text: note, PostedAt: now}
+	}
+	updated, err := ms.ticketRepo.UpdateTicketStatus(ctx, ticket.ID, ticket.Status, to, now, comment)
+	if err != nil {
+		return entities.MaintenanceTicket{}, err
+	}
+	if !updated {
+		return entities.MaintenanceTicket{}, ms.stateError(ctx, ticket.ID, action)
+	}
+	return ms.reread(ctx, ticket.ID)
+}
+
+// AddComment lets the tenant or the landlord comment on a ticket that is not closed.
+func (ms *MaintenanceService) AddComment(ctx context.Context, session *entities.Session, ticketID primitive.ObjectID, text string) (entities.MaintenanceTicket, error) {
+	const action = "comment on the maintenance ticket"
+	if err := checkSession(session); err != nil {
+		return entities.MaintenanceTicket{}, err
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return entities.MaintenanceTicket{}, fmt.Errorf("%w: a comment needs text", ErrInvalidTicket)
+	}
+	ticket, err := ms.findTicket(ctx, ticketID)
+	if err != nil {
+		return entities.MaintenanceTicket{}, err
+	}
+	if !isPartyToTicket(session, ticket) {
+		return entities.MaintenanceTicket{}, &ForbiddenError{Username: session.Username(), Action: action, Reason: "they are not a party to the ticket"}
+	}
+	if ticket.Status == entities.TicketClosed {
+		return entities.MaintenanceTicket{}, &TicketStateError{Status: ticket.Status, Action: action}
+	}
+
+	comment := entities.TicketComment{Author: session.Username(), Text: text, PostedAt: time.Now()}
+	added, err := ms.ticketRepo.AddTicketComment(ctx, ticket.ID, comment)
+	if err != nil {
+		return entities.MaintenanceTicket{}, err
+	}
+	if !added {
+		return entities.MaintenanceTicket{}, ms.stateError(ctx, ticket.ID, action)
+	}
+	return ms.reread(ctx, ticket.ID)
+}
+
+// reread gives the ticket as stored after a change, with the comments others added meanwhile.
+func (ms *MaintenanceService) reread(ctx context.Context, id primitive.ObjectID) (entities.MaintenanceTicket, error) {
+	ticket, err := ms.findTicket(ctx, id)
+	if err != nil {
+		return entities.MaintenanceTicket{}, err
+	}
+	return *ticket, nil
+}
+
+// stateError reports a ticket that changed before a change to it was stored, in its new status.
+func (ms *MaintenanceService) stateError(ctx context.Context, id primitive.ObjectID, action string) error {
+	current, err := ms.findTicket(ctx, id)
+	if err != nil {
+		return err
+	}
+	return &TicketStateError{Status: current.Status, Action: action}
+}
+
+// findTicket gives the ticket with Overdue set as of now.
+func (ms *MaintenanceService) findTicket(ctx context.Context, id primitive.ObjectID) (*entities.MaintenanceTicket, error) {
+	ticket, err := ms.ticketRepo.FindTicketByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if ticket == nil {
+		return nil, ErrTicketNotFound
+	}
+	ticket.Overdue = ticket.IsOverdue(time.Now())
+	return ticket, nil
+}
+
+func validateIssue(category entities.MaintenanceCategory, priority entities.MaintenancePriority, description string) error {
+	if !category.IsValid() {
+		return fmt.Errorf("%w: unknown category %q", ErrInvalidTicket, category)
+	}
+	if !priority.IsValid() {
+		return fmt.Errorf("%w: unknown priority %q", ErrInvalidTicket, priority)
+	}
+	if description == "" {
+		return fmt.Errorf("%w: describe the issue", ErrInvalidTicket)
+	}
+	return nil
+}
+
+func isTicketStatus(status entities.TicketStatus) bool {
+	for _, known := range entities.TicketStatuses() {
+		if status == known {
+			return true
+		}
+	}
+	return false
+}
+
+func canMoveTicket(moves map[entities.TicketStatus][]entities.TicketStatus, from, to entities.TicketStatus) bool {
+	for _, allowed := range moves[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+func isPartyToTicket(session *entities.Session, ticket *entities.MaintenanceTicket) bool {
+	return ticket.TenantName == session.Username() || ticket.LandlordName == session.Username()
+}
+
+func markOverdue(tickets []entities.MaintenanceTicket, now time.Time) {
+	for i := range tickets {
+		tickets[i].Overdue = tickets[i].IsOverdue(now)
+	}
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrThreadNotFound = errors.New("message thread not found")
+	ErrInvalidMessage = errors.New("invalid message")
+)
+
+// MaxMessageLength is the most characters a message can have.
+const MaxMessageLength = 2000
+
+// MessageService lets tenants and landlords talk about a property on the platform. Each tenant has one
+// thread with the landlord per property, which they open from a search result or their wishlist, and
+// either of them from a rent request.
+type MessageService struct {
+	messageRepo  interfaces.MessageRepo
+	propertyRepo interfaces.PropertyRepo
+	requestRepo  interfaces.RequestRepo
+}
+
+// NewMessageService creates the service on the message threads and the properties and rent requests
+// they are started from.
+func NewMessageService(messageRepo interfaces.MessageRepo, propertyRepo interfaces.PropertyRepo, requestRepo interfaces.RequestRepo) *MessageService {
+	return &MessageService{
+		messageRepo:  messageRepo,
+		propertyRepo: propertyRepo,
+		requestRepo:  requestRepo,
+	}
+}
+
+// ContactLandlord sends a message from the logged in tenant to the landlord of an approved property,
+// in the thread the tenant has about it, which is started if there is none yet.
+func (ms *MessageService) ContactLandlord(ctx context.Context, session *entities.Session, propertyID primitive.ObjectID, text string) (entities.Thread, error) {
+	const action = "message the landlord"
+	if err := authorize(session, entities.PermRentProperties, action); err != nil {
+		return entities.Thread{}, err
+	}
+	text, err := validateMessage(text)
+	if err != nil {
+		return entities.Thread{}, err
+	}
+	property, err := ms.propertyRepo.FindByID(ctx, propertyID)
+	if err != nil {
+		return entities.Thread{}, err
+	}
+	if property == nil {
+		return entities.Thread{}, ErrPropertyNotFound
+	}
+	if property.LandlordUsername == session.Username() {
+		return entities.Thread{}, &ForbiddenError{Username: session.Username(), Action: action, Reason: "it is their own property"}
+	}
+	if !property.IsApprovedByAdmin {
+		return entities.Thread{}, &ForbiddenError{Username: session.Username(), Action: action, Reason: "the property has not been approved yet"}
+	}
+
+	thread, err := ms.openThread(ctx, property.ID, session.Username(), property.LandlordUsername)
+	if err != nil {
+		return entities.Thread{}, err
+	}
+	return ms.post(ctx, session, thread.ID, text)
+}
+
+// MessageAboutRequest sends a message from the tenant or the landlord of a rent request to the other,
+// in the thread the tenant has about the property, which is started if there is none yet.
+func (ms *MessageService) MessageAboutRequest(ctx context.Context, session *entities.Session, requestID primitive.ObjectID, text string) (entities.Thread, error) {
+	if err := checkSession(session); err != nil {
+		return entities.Thread{}, err
+	}
+	text, err := validateMessage(text)
+	if err != nil {
+		return entities.Thread{}, err
+	}
+	request, err := ms.requestRepo.FindRequestByID(ctx, requestID)
+	if err != nil {
+		return entities.Thread{}, err
+	}
+	if request == nil {
+		return entities.Thread{}, ErrRequestNotFound
+	}
+	if request.TenantName != session.Username() && request.LandlordName != session.Username() {
+		return entities.Thread{}, &ForbiddenError{Username: session.Username(), Action: "message about the rent request", Reason: "they are not a party to it"}
+	}
+
+	thread, err := ms.openThread(ctx, request.PropertyID, request.TenantName, request.LandlordName)
+	if err != nil {
+		return entities.Thread{}, err
+	}
+	return ms.post(ctx, session, thread.ID, text)
+}
+
+// SendMessage adds a message from the tenant or the landlord of a thread to it.
+func (ms *MessageService) SendMessage(ctx context.Context, session *entities.Session, threadID primitive.ObjectID, text string) (entities.Thread, error) {
+	if err := checkSession(session); err != nil {
+		return entities.Thread{}, err
+	}
+	text, err := validateMessage(text)
+	if err != nil {
+		return entities.Thread{}, err
+	}
+	if _, err := ms.findThread(ctx, session, threadID, "write in the thread"); err != nil {
+		return entities.Thread{}, err
+	}
+	return ms.post(ctx, session, threadID, text)
+}
+
+// ReadThread shows a thread to its tenant or landlord and marks its messages read for them.
+// The thread is given as it was before, so the caller can still tell which messages are new.
+func (ms *MessageService) ReadThread(ctx context.Context, session *entities.Session, threadID primitive.ObjectID) (entities.Thread, error) {
+	if err := checkSession(session); err != nil {
+		return entities.Thread{}, err
+	}
+	thread, err := ms.findThread(ctx, session, threadID, "read the thread")
+	if err != nil {
+		return entities.Thread{}, err
+	}
+	if thread.UnreadFor(session.Username()) > 0 {
+		if _, err := ms.messageRepo.MarkThreadRead(ctx, thread.ID, session.Username()); err != nil {
+			return entities.Thread{}, err
+		}
+	}
+	return *thread, nil
+}
+
+// Threads gives the threads of the logged in user, as tenant or landlord, latest message first.
+func (ms *MessageService) Threads(ctx context.Context, session *entities.Session) ([]entities.Thread, error) {
+	if err := checkSession(session); err != nil {
+		return nil, err
+	}
+	return ms.messageRepo.FindThreadsByUser(ctx, session.Username())
+}
+
+// UnreadMessages counts the messages the logged in user has not read yet.
+func (ms *MessageService) UnreadMessages(ctx context.Context, session *entities.Session) (entities.UnreadMessages, error) {
+	threads, err := ms.Threads(ctx, session)
+	if err != nil {
+		return entities.UnreadMessages{}, err
+	}
+	var unread entities.UnreadMessages
+	for _, thread := range threads {
+		if thread.TenantName == session.Username() {
+			unread.AsTenant += thread.TenantUnread
+		} else {
+			unread.AsLandlord += thread.LandlordUnread
+		}
+	}
+	return unread, nil
+}
+
+// openThread gives the tenant's thread about the property, starting it if there is none yet.
+func (ms *MessageService) openThread(ctx context.Context, propertyID primitive.ObjectID, tenantName, landlordName string) (*entities.Thread, error) {
+	thread, err := ms.messageRepo.FindThread(ctx, propertyID, tenantName)
+	if err != nil || thread != nil {
+		return thread, err
+	}
+	now := time.Now()
+	_, err = ms.messageRepo.CreateThread(ctx, entities.Thread{
+		ID:            primitive.NewObjectID(),
+		PropertyID:    propertyID,
+		TenantName:    tenantName,
+		LandlordName:  landlordName,
+		Messages:      []entities.Message{},
+		CreatedAt:     now,
+		LastMessageAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Read it back: when it was started by another message meanwhile, that thread is the one to use
+	thread, err = ms.messageRepo.FindThread(ctx, propertyID, tenantName)
+	if err != nil {
+		return nil, err
+	}
+	if thread == nil {
+		return nil, ErrThreadNotFound
+	}
+	return thread, nil
+}
+
+// post adds the message to the thread, where it counts unread for the other party. Messages the sender has
+// not read yet stay unread for them until they read the thread.
+func (ms *MessageService) post(ctx context.Context, session *entities.Session, threadID primitive.ObjectID, text string) (entities.Thread, error) {
+	message := entities.Message{Sender: session.Username(), Text: text, SentAt: time.Now()}
+	added, err := ms.messageRepo.AddMessage(ctx, threadID, message)
+	if err != nil {
+		return entities.Thread{}, err
+	}
+	if !added {
+		return entities.Thread{}, ErrThreadNotFound
+	}
+	thread, err := ms.messageRepo.FindThreadByID(ctx, threadID)
+	if err != nil {
+		return entities.Thread{}, err
+	}
+	if thread == nil {
+		return entities.Thread{}, ErrThreadNotFound
+	}
+	return *thread, nil
+}
+
+// findThread gives the thread if the logged in user is a party to it.
+func (ms *MessageService) findThread(ctx context.Context, session *entities.Session, id primitive.ObjectID, action string) (*entities.Thread, error) {
+	thread, err := ms.messageRepo.FindThreadByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if thread == nil {
+		return nil, ErrThreadNotFound
+	}
+	if !thread.HasParty(session.Username()) {
+		return nil, &ForbiddenError{Username: session.Username(), Action: action, Reason: "they are not a party to it"}
+	}
+	return thread, nil
+}
+
+func validateMessage(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", fmt.Errorf("%w: a message needs text", ErrInvalidMessage)
+	}
+	if utf8.RuneCountInString(text) > MaxMessageLength {
+		return "", fmt.Errorf("%w: a message has at most %d characters", ErrInvalidMessage, MaxMessageLength)
+	}
+	return text, nil
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// NotificationService is the inbox of the logged in user. The notifications in it are recorded by
+// SubscribeNotifications when the other services publish something that concerns the user.
+type NotificationService struct {
+	notificationRepo interfaces.NotificationRepo
+}
+
+func NewNotificationService(notificationRepo interfaces.NotificationRepo) *NotificationService {
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+	}
+}
+
+// Notifications gives the notifications of the logged in user, newest first.
+func (ns *NotificationService) Notifications(ctx context.Context, session *entities.Session) ([]entities.Notification, error) {
+	if err := checkSession(session); err != nil {
+		return nil, err
+	}
+	return ns.notificationRepo.FindNotificationsByUser(ctx, session.Username())
+}
+
+// UnreadNotifications counts the notifications the logged in user has not read yet.
+func (ns *NotificationService) UnreadNotifications(ctx context.Context, session *entities.Session) (int, error) {
+	notifications, err := ns.Notifications(ctx, session)
+	if err != nil {
+		return 0, err
+	}
+	unread := 0
+	for _, notification := range notifications {
+		if !notification.Read {
+			unread++
+		}
+	}
+	return unread, nil
+}
+
+// MarkNotificationRead marks one notification of the logged in user read.
+func (ns *NotificationService) MarkNotificationRead(ctx context.Context, session *entities.Session, id primitive.ObjectID) error {
+	if err := checkSession(session); err != nil {
+		return err
+	}
+	marked, err := ns.notificationRepo.MarkNotificationRead(ctx, id, session.Username())
+	if err != nil {
+		return err
+	}
+	if !marked {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+// MarkAllNotificationsRead marks every notification of the logged in user read and returns how many were unread.
+func (ns *NotificationService) MarkAllNotificationsRead(ctx context.Context, session *entities.Session) (int, error) {
+	if err := checkSession(session); err != nil {
+		return 0, err
+	}
+	return ns.notificationRepo.MarkAllNotificationsRead(ctx, session.Username())
+}
+
+// DeleteNotification removes one notification from the inbox of the logged in user.
+func (ns *NotificationService) DeleteNotification(ctx context.Context, session *entities.Session, id primitive.ObjectID) error {
+	if err := checkSession(session); err != nil {
+		return err
+	}
+	deleted, err := ns.notificationRepo.DeleteNotification(ctx, id, session.Username())
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+// ClearReadNotifications removes the notifications the logged in user has read and returns how many there were.
+// Unread ones stay, so nothing is cleared away before it was seen.
+func (ns *NotificationService) ClearReadNotifications(ctx context.Context, session *entities.Session) (int, error) {
+	if err := checkSession(session); err != nil {
+		return 0, err
+	}
+	return ns.notificationRepo.DeleteReadNotifications(ctx, session.Username())
+}
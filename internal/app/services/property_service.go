@@ -2,52 +2,97 @@ package services
 
 import (
 	"context"
+	"errors"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"rentease/internal/domain/entities"
+	"rentease/internal/domain/events"
 	"rentease/internal/domain/interfaces"
 	"strings"
 )
 
+var ErrPropertyNotFound = errors.New("property not found")
+
+// ErrPropertyApproved is returned when rejecting a property that has already been approved.
+var ErrPropertyApproved = errors.New("the property has already been approved")
+
 type PropertyService struct {
 	propertyRepo interfaces.PropertyRepo
+	publisher    interfaces.EventPublisher
 }
 
-func NewPropertyService(propertyRepo interfaces.PropertyRepo) *PropertyService {
+func NewPropertyService(propertyRepo interfaces.PropertyRepo, publisher interfaces.EventPublisher) *PropertyService {
 	return &PropertyService{
 		propertyRepo: propertyRepo,
+		publisher:    publisher,
 	}
 }
 
-// ListProperty saves a property to the repository.
-func (ps *PropertyService) ListProperty(property entities.Property) error {
-	return ps.propertyRepo.SaveProperty(property)
+// ListProperty saves a property of the logged in landlord to the repository.
+func (ps *PropertyService) ListProperty(ctx context.Context, session *entities.Session, property entities.Property) error {
+	if err := authorize(session, entities.PermListProperties, "list a property"); err != nil {
+		return err
+	}
+	property.LandlordUsername = session.Username()
+	return ps.propertyRepo.SaveProperty(ctx, property)
 }
 
-// GetAllListedProperties retrieves all listed properties from the repository.
-func (ps *PropertyService) GetAllListedProperties(activeUseronly bool) ([]entities.Property, error) {
-	return ps.propertyRepo.GetAllListedProperties(activeUseronly)
+// GetAllListedProperties retrieves the properties of the given landlord,
+// or all properties that are not rented when landlordUsername is empty.
+func (ps *PropertyService) GetAllListedProperties(ctx context.Context, landlordUsername string) ([]entities.Property, error) {
+	return ps.propertyRepo.GetAllListedProperties(ctx, landlordUsername)
 }
 
-// UpdateListedProperty updates a property in the repository.
-func (ps *PropertyService) UpdateListedProperty(property entities.Property) error {
+// UpdateListedProperty updates a property of the logged in landlord in the repository.
+func (ps *PropertyService) UpdateListedProperty(ctx context.Context, session *entities.Session, property entities.Property) error {
+	if err := checkSession(session); err != nil {
+		return err
+	}
+	stored, err := ps.propertyRepo.FindByID(ctx, property.ID)
+	if err != nil {
+		return err
+	}
+	if stored == nil {
+		return ErrPropertyNotFound
+	}
+	if err := authorizeOwner(session, stored.LandlordUsername, entities.PermListProperties, "update the property"); err != nil {
+		return err
+	}
+	property.LandlordUsername = stored.LandlordUsername
+	// Only accepting a rent request rents the property out
+	property.IsRented = stored.IsRented
 
 	// Check if the property is approved before updating
 	if property.IsApprovedByAdmin && !property.IsRented {
 		// Reset approval status if the property was approved
 		property.IsApprovedByAdmin = false
 	}
-	return ps.propertyRepo.UpdateListedProperty(property)
+	return ps.propertyRepo.UpdateListedProperty(ctx, property)
 }
 
 // DeleteListedProperty deletes a property from the repository by ID.
-func (ps *PropertyService) DeleteListedProperty(propertyID string) error {
-
-	return ps.propertyRepo.DeleteListedProperty(propertyID)
+// Landlords may delete their own properties, moderators and admins any property.
+func (ps *PropertyService) DeleteListedProperty(ctx context.Context, session *entities.Session, propertyID primitive.ObjectID) error {
+	if err := checkSession(session); err != nil {
+		return err
+	}
+	property, err := ps.propertyRepo.FindByID(ctx, propertyID)
+	if err != nil {
+		return err
+	}
+	if property == nil {
+		return ErrPropertyNotFound
+	}
+	if !session.Can(entities.PermModerateProperties) {
+		if err := authorizeOwner(session, property.LandlordUsername, entities.PermListProperties, "delete the property"); err != nil {
+			return err
+		}
+	}
+	return ps.propertyRepo.DeleteListedProperty(ctx, propertyID)
 }
 
 // SearchProperties searches for properties based on the given criteria.
-func (ps *PropertyService) SearchProperties(area, city, state string, pincode, propertyType int) ([]entities.Property, error) {
-	properties, err := ps.propertyRepo.GetAllListedProperties(false)
+func (ps *PropertyService) SearchProperties(ctx context.Context, area, city, state string, pincode, propertyType int) ([]entities.Property, error) {
+	properties, err := ps.propertyRepo.GetAllListedProperties(ctx, "")
 	if err != nil {
 		return nil, err
 	}
@@ -80,10 +125,7 @@ func (ps *PropertyService) SearchProperties(area, city, state string, pincode, p
 }
 
 // FindByID retrieves a property by its ID.
-func (ps *PropertyService) FindByID(id primitive.ObjectID) (entities.Property, error) {
-	// Use context in a real application
-	ctx := context.TODO()
-
+func (ps *PropertyService) FindByID(ctx context.Context, id primitive.ObjectID) (entities.Property, error) {
 	property, err := ps.propertyRepo.FindByID(ctx, id)
 	if err != nil {
 		return entities.Property{}, err
@@ -95,16 +137,72 @@ func (ps *PropertyService) FindByID(id primitive.ObjectID) (entities.Property, e
 	return *property, nil
 }
 
-func (ps *PropertyService) DeleteAllListedPropertiesOfaUser(username string) error {
-	return ps.propertyRepo.DeleteAllListedPropertiesOfaUser(username)
+// DeleteAllListedPropertiesOfaUser deletes every property of the user, as when the user is deleted.
+func (ps *PropertyService) DeleteAllListedPropertiesOfaUser(ctx context.Context, session *entities.Session, username string) error {
+	if err := authorize(session, entities.PermManageUsers, "delete the properties of a user"); err != nil {
+		return err
+	}
+	return ps.propertyRepo.DeleteAllListedPropertiesOfaUser(ctx, username)
 }
 
 // Admin
 
-func (ps *PropertyService) GetPendingProperties() ([]entities.Property, error) {
-	return ps.propertyRepo.FindPendingProperties()
+func (ps *PropertyService) GetPendingProperties(ctx context.Context, session *entities.Session) ([]entities.Property, error) {
+	if err := authorize(session, entities.PermReviewProperties, "review properties"); err != nil {
+		return nil, err
+	}
+	return ps.propertyRepo.FindPendingProperties(ctx)
+}
+
+// ApproveProperty approves the property on behalf of the admin or moderator of the session,
+// and publishes PropertyApproved.
+func (ps *PropertyService) ApproveProperty(ctx context.Context, session *entities.Session, propertyID primitive.ObjectID) error {
+	if err := authorize(session, entities.PermReviewProperties, "approve the property"); err != nil {
+		return err
+	}
+	property, err := ps.propertyRepo.FindByID(ctx, propertyID)
+	if err != nil {
+		return err
+	}
+	if property == nil {
+		return ErrPropertyNotFound
+	}
+	if err := ps.propertyRepo.UpdateApprovalStatus(ctx, propertyID, true, session.Username()); err != nil {
+		return err
+	}
+	return ps.publisher.Publish(ctx, events.PropertyApproved{
+		PropertyID: propertyID,
+		Title:      property.Title,
+		Landlord:   property.LandlordUsername,
+		By:         session.Username(),
+	})
 }
 
-func (ps *PropertyService) ApproveProperty(propertyID primitive.ObjectID, adminUsername string) error {
-	return ps.propertyRepo.UpdateApprovalStatus(propertyID, true, adminUsername)
+// RejectProperty turns down a property waiting for approval on behalf of the admin or moderator of the
+// session. The listing is removed and PropertyRejected published, with the reason if one is given.
+func (ps *PropertyService) RejectProperty(ctx context.Context, session *entities.Session, propertyID primitive.ObjectID, reason string) error {
+	if err := authorize(session, entities.PermReviewProperties, "reject the property"); err != nil {
+		return err
+	}
+	property, err := ps.propertyRepo.FindByID(ctx, propertyID)
+	if err != nil {
+		return err
+	}
+	if property == nil {
+		return ErrPropertyNotFound
+	}
+	if property.IsApprovedByAdmin {
+		return ErrPropertyApproved
+	}
+	if err := ps.propertyRepo.DeleteListedProperty(ctx, propertyID); err != nil {
+		return err
+	}
+
+	return ps.publisher.Publish(ctx, events.PropertyRejected{
+		PropertyID: propertyID,
+		Title:      property.Title,
+		Landlord:   property.LandlordUsername,
+		By:         session.Username(),
+		Reason:     strings.TrimSpace(reason),
+	})
 }
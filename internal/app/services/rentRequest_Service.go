@@ -3,47 +3,394 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"rentease/internal/domain/entities"
+	"rentease/internal/domain/events"
 	"rentease/internal/domain/interfaces"
 	"time"
 )
 
+var ErrRequestNotFound = errors.New("rent request not found")
+
+// ErrPropertyRented is returned when accepting a request for a property that is already rented out.
+var ErrPropertyRented = errors.New("property is already rented")
+
+// Rules for creating rent requests, wrapped in a RequestNotAllowedError when broken.
+var (
+	ErrDuplicateRequest    = errors.New("you already have an open rent request for this property")
+	ErrOwnProperty         = errors.New("you cannot request your own property")
+	ErrPropertyNotApproved = errors.New("the property has not been approved yet")
+)
+
+// ErrRequestNotAllowed is matched by every RequestNotAllowedError, so callers can test for it with errors.Is.
+var ErrRequestNotAllowed = errors.New("rent request not allowed")
+
+// RequestNotAllowedError is returned when a rent request cannot be created for a property. Err is the rule that
+// was broken: ErrDuplicateRequest, ErrOwnProperty, ErrPropertyNotApproved or ErrPropertyRented.
+type RequestNotAllowedError struct {
+	PropertyID primitive.ObjectID
+	Err        error
+}
+
+func (e *RequestNotAllowedError) Error() string {
+	return fmt.Sprintf("cannot request the property: %v", e.Err)
+}
+
+// Is makes errors.Is(err, ErrRequestNotAllowed) true for a RequestNotAllowedError.
+func (e *RequestNotAllowedError) Is(target error) bool {
+	return target == ErrRequestNotAllowed
+}
+
+// Unwrap returns the broken rule, so errors.Is(err, ErrDuplicateRequest) and the like work too.
+func (e *RequestNotAllowedError) Unwrap() error {
+	return e.Err
+}
+
+// ReasonPropertyRented is recorded on the pending requests rejected because another request for the property was accepted.
+const ReasonPropertyRented = "the property was rented to another tenant"
+
+// ErrInvalidTransition is matched by every TransitionError, so callers can test for it with errors.Is.
+var ErrInvalidTransition = errors.New("invalid rent request transition")
+
+// TransitionError is returned when a request cannot move from its status to the one asked for.
+type TransitionError struct {
+	From entities.RequestStatus
+	To   entities.RequestStatus
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("a %s rent request cannot be changed to %q", e.From, e.To)
+}
+
+// Is makes errors.Is(err, ErrInvalidTransition) true for a TransitionError.
+func (e *TransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// landlordStatuses are the statuses a landlord may give requests for their properties.
+// Tenants withdraw their own requests, and pending requests expire through ExpireRequests.
+var landlordStatuses = []entities.RequestStatus{
+	entities.RequestAccepted,
+	entities.RequestRejected,
+	entities.RequestLeaseSigned,
+	entities.RequestCancelled,
+}
+
 type RequestService struct {
-	requestRepo interfaces.RequestRepo
+	requestRepo  interfaces.RequestRepo
+	propertyRepo interfaces.PropertyRepo
+	leases       leaseKeeper
+	publisher    interfaces.EventPublisher
 }
 
-func NewRequestService(requestRepo interfaces.RequestRepo) *RequestService {
+func NewRequestService(requestRepo interfaces.RequestRepo, propertyRepo interfaces.PropertyRepo, leaseRepo interfaces.LeaseRepo, publisher interfaces.EventPublisher) *RequestService {
 	return &RequestService{
-		requestRepo: requestRepo,
+		requestRepo:  requestRepo,
+		propertyRepo: propertyRepo,
+		leases:       leaseKeeper{leaseRepo: leaseRepo, propertyRepo: propertyRepo},
+		publisher:    publisher,
 	}
 }
 
-func (rs *RequestService) CreateRentRequest(tenantName string, propertyID primitive.ObjectID, landlordName string) error {
+// CreateRentRequest creates a pending request from the logged in tenant for the property.
+// The property must be approved and not rented, must not be the tenant's own, and the tenant
+// must not have an open request for it already; otherwise a RequestNotAllowedError is returned.
+// RentRequestCreated is published for the landlord to hear of it.
+func (rs *RequestService) CreateRentRequest(ctx context.Context, session *entities.Session, propertyID primitive.ObjectID) error {
+	if err := authorize(session, entities.PermRentProperties, "request a property"); err != nil {
+		return err
+	}
+
+	property, err := rs.propertyRepo.FindByID(ctx, propertyID)
+	if err != nil {
+		return err
+	}
+	if property == nil {
+		return ErrPropertyNotFound
+	}
+	switch {
+	case property.LandlordUsername == session.Username():
+		return &RequestNotAllowedError{PropertyID: propertyID, Err: ErrOwnProperty}
+	case !property.IsApprovedByAdmin:
+		return &RequestNotAllowedError{PropertyID: propertyID, Err: ErrPropertyNotApproved}
+	case property.IsRented:
+		return &RequestNotAllowedError{PropertyID: propertyID, Err: ErrPropertyRented}
+	}
 
+	now := time.Now()
 	request := entities.Request{
 		PropertyID:    propertyID,
-		TenantName:    tenantName,
-		LandlordName:  landlordName,
-		RequestStatus: "pending",
-		CreatedAt:     time.Now(),
+		TenantName:    session.Username(),
+		LandlordName:  property.LandlordUsername,
+		RequestStatus: entities.RequestPending,
+		CreatedAt:     now,
+		History: []entities.StatusChange{
+			{To: entities.RequestPending, ChangedAt: now, ChangedBy: session.Username()},
+		},
+	}
+
+	created, err := rs.requestRepo.CreateRequest(ctx, request)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return &RequestNotAllowedError{PropertyID: propertyID, Err: ErrDuplicateRequest}
+	}
+	return rs.publisher.Publish(ctx, events.RentRequestCreated{
+		PropertyID:    propertyID,
+		PropertyTitle: property.Title,
+		Tenant:        session.Username(),
+		Landlord:      property.LandlordUsername,
+	})
+}
+
+// GetRentRequestsInfoForLandlord gives all the rent requests for the logged in landlord
+func (rs *RequestService) GetRentRequestsInfoForLandlord(ctx context.Context, session *entities.Session) ([]entities.Request, error) {
+	if err := authorize(session, entities.PermListProperties, "see received rent requests"); err != nil {
+		return nil, err
+	}
+	return rs.requestRepo.FindByLandlordName(ctx, session.Username())
+}
+
+// UpdateRequestStatus answers a request for one of the properties of the logged in landlord.
+// The request must be able to move from its current status to the new one.
+// Accepting a request draws up a lease, which rents out the property, and rejects the other pending
+// requests for it. Signing activates the lease, and cancelling calls it off and puts the property back
+// on the market.
+func (rs *RequestService) UpdateRequestStatus(ctx context.Context, session *entities.Session, requestID primitive.ObjectID, status entities.RequestStatus) error {
+	const action = "answer the rent request"
+	if err := authorize(session, entities.PermListProperties, action); err != nil {
+		return err
+	}
+
+	request, err := rs.findRequest(ctx, requestID)
+	if err != nil {
+		return err
+	}
+	if request.LandlordName != session.Username() {
+		return &ForbiddenError{Username: session.Username(), Action: action, Reason: "it is not a request for one of their properties"}
+	}
+	if !request.RequestStatus.CanTransitionTo(status) {
+		return &TransitionError{From: request.RequestStatus, To: status}
+	}
+	if !isLandlordStatus(status) {
+		return &ForbiddenError{Username: session.Username(), Action: action, Reason: fmt.Sprintf("landlords cannot mark requests %s", status)}
+	}
+
+	switch status {
+	case entities.RequestAccepted:
+		return rs.accept(ctx, request, session.Username())
+	case entities.RequestLeaseSigned:
+		return rs.followLease(ctx, request, status, session.Username(), entities.LeaseActive)
+	case entities.RequestCancelled:
+		return rs.followLease(ctx, request, status, session.Username(), entities.LeaseCancelled)
+	default:
+		return rs.transition(ctx, request, status, session.Username(), "")
+	}
+}
+
+// accept draws up the lease of the request, accepts the request and rejects the other pending requests for
+// the property. The lease comes first and can only be drawn up for a property that is not rented, so of two
+// requests accepted at the same time only one wins; the other fails with ErrPropertyRented.
+func (rs *RequestService) accept(ctx context.Context, request *entities.Request, username string) error {
+	lease, err := rs.leases.open(ctx, request, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if err := rs.changeStatus(ctx, request, entities.RequestAccepted, username, ""); err != nil {
+		// The request was answered or withdrawn meanwhile, so the lease is called off
+		if cancelErr := rs.leases.setStatus(ctx, lease, entities.LeaseCancelled); cancelErr != nil {
+			return errors.Join(err, cancelErr)
+		}
+		return err
+	}
+	// The request stands once accepted, so a failing subscriber does not keep the others from being rejected
+	publishErr := rs.publishDecision(ctx, request, entities.RequestAccepted, username, "")
+	return errors.Join(publishErr, rs.rejectCompeting(ctx, request, username))
+}
+
+// followLease moves the accepted request to the status and its lease along with it. The lease comes first,
+// and is moved back when the request was changed meanwhile, so the two stay in step.
+// Requests accepted before leases were drawn up have none; cancelling those only puts the property back on the market.
+func (rs *RequestService) followLease(ctx context.Context, request *entities.Request, status entities.RequestStatus, username string, leaseStatus entities.LeaseStatus) error {
+	lease, err := rs.leases.leaseRepo.FindLeaseByRequestID(ctx, request.ID)
+	if err != nil {
+		return err
+	}
+	if lease == nil {
+		if err := rs.transition(ctx, request, status, username, ""); err != nil {
+			return err
+		}
+		if leaseStatus.OccupiesProperty() {
+			return nil
+		}
+		_, err := rs.propertyRepo.SetRented(ctx, request.PropertyID, false)
+		return err
+	}
+
+	from := lease.Status
+	if err := rs.leases.setStatus(ctx, lease, leaseStatus); err != nil {
+		return err
+	}
+	if err := rs.changeStatus(ctx, request, status, username, ""); err != nil {
+		if rollbackErr := rs.leases.setStatus(ctx, lease, from); rollbackErr != nil {
+			return errors.Join(err, rollbackErr)
+		}
+		return err
+	}
+	return rs.publishDecision(ctx, request, status, username, "")
+}
+
+// rejectCompeting rejects the pending requests for the property of the accepted request, recording
+// ReasonPropertyRented so the tenants see why. Requests withdrawn or expired meanwhile are left alone.
+func (rs *RequestService) rejectCompeting(ctx context.Context, accepted *entities.Request, username string) error {
+	requests, err := rs.requestRepo.FindByPropertyID(ctx, accepted.PropertyID)
+	if err != nil {
+		return err
+	}
+	for i := range requests {
+		if requests[i].ID == accepted.ID || requests[i].RequestStatus != entities.RequestPending {
+			continue
+		}
+		err := rs.transition(ctx, &requests[i], entities.RequestRejected, username, ReasonPropertyRented)
+		if errors.Is(err, ErrInvalidTransition) || errors.Is(err, ErrRequestNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WithdrawRequest lets the logged in tenant take back one of their requests that is still pending.
+func (rs *RequestService) WithdrawRequest(ctx context.Context, session *entities.Session, requestID primitive.ObjectID) error {
+	const action = "withdraw the rent request"
+	if err := authorize(session, entities.PermRentProperties, action); err != nil {
+		return err
+	}
+
+	request, err := rs.findRequest(ctx, requestID)
+	if err != nil {
+		return err
 	}
+	if request.TenantName != session.Username() {
+		return &ForbiddenError{Username: session.Username(), Action: action, Reason: "it was sent by another user"}
+	}
+	return rs.transition(ctx, request, entities.RequestWithdrawn, session.Username(), "")
+}
+
+// ExpireRequests expires the pending requests created before the given time and returns how many were expired.
+// Requests answered meanwhile are left alone.
+func (rs *RequestService) ExpireRequests(ctx context.Context, session *entities.Session, createdBefore time.Time) (int, error) {
+	if err := authorize(session, entities.PermReviewProperties, "expire rent requests"); err != nil {
+		return 0, err
+	}
+
+	pending, err := rs.requestRepo.FindByStatus(ctx, entities.RequestPending)
+	if err != nil {
+		return 0, err
+	}
+	expired := 0
+	for i := range pending {
+		if !pending[i].CreatedAt.Before(createdBefore) {
+			continue
+		}
+		err := rs.transition(ctx, &pending[i], entities.RequestExpired, session.Username(), "")
+		if errors.Is(err, ErrInvalidTransition) || errors.Is(err, ErrRequestNotFound) {
+			continue
+		}
+		if err != nil {
+			return expired, err
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+// GetRentRequestsInfoForTenant gives all the rent requests of the logged in tenant
+func (rs *RequestService) GetRentRequestsInfoForTenant(ctx context.Context, session *entities.Session) ([]entities.Request, error) {
+	if err := authorize(session, entities.PermRentProperties, "see sent rent requests"); err != nil {
+		return nil, err
+	}
+	return rs.requestRepo.FindByTenantUsername(ctx, session.Username())
+}
 
-	return rs.requestRepo.SaveRequest(request)
+func (rs *RequestService) findRequest(ctx context.Context, id primitive.ObjectID) (*entities.Request, error) {
+	request, err := rs.requestRepo.FindRequestByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if request == nil {
+		return nil, ErrRequestNotFound
+	}
+	return request, nil
 }
 
-// GetRentRequestsInfoForLandlord gives  all the rent requests for the landlord
-func (rs *RequestService) GetRentRequestsInfoForLandlord(landlordName string) ([]entities.Request, error) {
-	ctx := context.TODO()
-	return rs.requestRepo.FindByLandlordName(ctx, landlordName)
+// transition moves the request to the status, like changeStatus, and publishes the decision.
+func (rs *RequestService) transition(ctx context.Context, request *entities.Request, to entities.RequestStatus, username, reason string) error {
+	if err := rs.changeStatus(ctx, request, to, username, reason); err != nil {
+		return err
+	}
+	return rs.publishDecision(ctx, request, to, username, reason)
 }
 
-func (rs *RequestService) UpdateRequestStatus(request entities.Request, status string) error {
-	return rs.requestRepo.UpdateRequest(request, status)
+// changeStatus moves the request to the status, recording who did it and the reason, if any, in its history.
+// The repository only applies the change if the status is still the one read, so a request
+// changed by someone else in the meantime fails with a TransitionError from its new status.
+func (rs *RequestService) changeStatus(ctx context.Context, request *entities.Request, to entities.RequestStatus, username, reason string) error {
+	if !request.RequestStatus.CanTransitionTo(to) {
+		return &TransitionError{From: request.RequestStatus, To: to}
+	}
+
+	change := entities.StatusChange{From: request.RequestStatus, To: to, ChangedAt: time.Now(), ChangedBy: username, Reason: reason}
+	updated, err := rs.requestRepo.UpdateRequestStatus(ctx, request.ID, change)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		current, err := rs.findRequest(ctx, request.ID)
+		if err != nil {
+			return err
+		}
+		return &TransitionError{From: current.RequestStatus, To: to}
+	}
+	return nil
 }
 
-// GetRentRequestsInfoForTenant gives all the rent requests for the tenant
-func (rs *RequestService) GetRentRequestsInfoForTenant(tenantName string) ([]entities.Request, error) {
-	ctx := context.TODO()
-	return rs.requestRepo.FindByTenantUsername(ctx, tenantName)
+// publishDecision publishes RentRequestAccepted or RentRequestRejected when the request has been moved
+// to either status, and nothing for the others.
+func (rs *RequestService) publishDecision(ctx context.Context, request *entities.Request, to entities.RequestStatus, username, reason string) error {
+	switch to {
+	case entities.RequestAccepted:
+		return rs.publisher.Publish(ctx, events.RentRequestAccepted{
+			RequestID:  request.ID,
+			PropertyID: request.PropertyID,
+			Tenant:     request.TenantName,
+			Landlord:   request.LandlordName,
+			By:         username,
+		})
+	case entities.RequestRejected:
+		return rs.publisher.Publish(ctx, events.RentRequestRejected{
+			RequestID:  request.ID,
+			PropertyID: request.PropertyID,
+			Tenant:     request.TenantName,
+			Landlord:   request.LandlordName,
+			By:         username,
+			Reason:     reason,
+		})
+	}
+	return nil
+}
+
+func isLandlordStatus(status entities.RequestStatus) bool {
+	for _, allowed := range landlordStatuses {
+		if status == allowed {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,27 @@
+package services
+
+import (
+	"errors"
+	"rentease/internal/domain/entities"
+	"time"
+)
+
+// SessionDuration is how long a session created by Login stays valid.
+const SessionDuration = 12 * time.Hour
+
+var (
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrNotLoggedIn        = errors.New("not logged in")
+	ErrSessionExpired     = errors.New("session expired, please log in again")
+)
+
+// checkSession makes sure the caller passed a session that is still valid.
+func checkSession(session *entities.Session) error {
+	if session == nil {
+		return ErrNotLoggedIn
+	}
+	if session.IsExpired(time.Now()) {
+		return ErrSessionExpired
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"log"
+	"rentease/internal/app/eventbus"
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/events"
+	"rentease/internal/domain/interfaces"
+	"time"
+)
+
+// SubscribeNotifications records a notification in the inbox of the user each event concerns.
+func SubscribeNotifications(bus *eventbus.Bus, notificationRepo interfaces.NotificationRepo) {
+	n := notifier{notificationRepo: notificationRepo}
+
+	eventbus.Subscribe(bus, func(ctx context.Context, e events.RentRequestCreated) error {
+		n.notify(ctx, e.Landlord, entities.NotificationRequestCreated, e.PropertyID,
+			"%s asked to rent %s", e.Tenant, e.PropertyTitle)
+		return nil
+	})
+	eventbus.Subscribe(bus, func(ctx context.Context, e events.RentRequestAccepted) error {
+		n.notify(ctx, e.Tenant, entities.NotificationRequestAccepted, e.PropertyID,
+			"%s accepted your rent request", e.By)
+		return nil
+	})
+	eventbus.Subscribe(bus, func(ctx context.Context, e events.RentRequestRejected) error {
+		if e.Reason == "" {
+			n.notify(ctx, e.Tenant, entities.NotificationRequestRejected, e.PropertyID,
+				"%s rejected your rent request", e.By)
+		} else {
+			n.notify(ctx, e.Tenant, entities.NotificationRequestRejected, e.PropertyID,
+				"%s rejected your rent request: %s", e.By, e.Reason)
+		}
+		return nil
+	})
+	eventbus.Subscribe(bus, func(ctx context.Context, e events.PropertyApproved) error {
+		n.notify(ctx, e.Landlord, entities.NotificationPropertyApproved, e.PropertyID,
+			"%s approved your listing %s", e.By, e.Title)
+		return nil
+	})
+	eventbus.Subscribe(bus, func(ctx context.Context, e events.PropertyRejected) error {
+		if e.Reason == "" {
+			n.notify(ctx, e.Landlord, entities.NotificationPropertyRejected, e.PropertyID,
+				"%s rejected your listing %s", e.By, e.Title)
+		} else {
+			n.notify(ctx, e.Landlord, entities.NotificationPropertyRejected, e.PropertyID,
+				"%s rejected your listing %s: %s", e.By, e.Title, e.Reason)
+		}
+		return nil
+	})
+	// A change the user did not make themselves does not go unnoticed
+	eventbus.Subscribe(bus, func(ctx context.Context, e events.UserUpdated) error {
+		if e.By == e.Username {
+			n.notify(ctx, e.Username, entities.NotificationAccountChanged, primitive.NilObjectID,
+				"Your account details were changed")
+		} else {
+			n.notify(ctx, e.Username, entities.NotificationAccountChanged, primitive.NilObjectID,
+				"%s changed your account details", e.By)
+		}
+		return nil
+	})
+	eventbus.Subscribe(bus, func(ctx context.Context, e events.UserRoleChanged) error {
+		n.notify(ctx, e.Username, entities.NotificationAccountChanged, primitive.NilObjectID,
+			"%s changed your role to %s", e.By, e.Role)
+		return nil
+	})
+}
+
+// SubscribeEmails has users emailed when they sign up, when a rent request comes in or is decided,
+// and when their listing is approved.
+func SubscribeEmails(bus *eventbus.Bus, emailNotifier interfaces.EmailNotifier) {
+	email := func(ctx context.Context, notice entities.EmailNotice) error {
+		// Emails are best effort, like notifications
+		if err := emailNotifier.Notify(ctx, notice); err != nil {
+			log.Printf("failed to email %s about %s: %v", notice.Username, notice.Kind, err)
+		}
+		return nil
+	}
+
+	eventbus.Subscribe(bus, func(ctx context.Context, e events.UserSignedUp) error {
+		return email(ctx, entities.EmailNotice{Kind: entities.EmailWelcome, Username: e.Username})
+	})
+	eventbus.Subscribe(bus, func(ctx context.Context, e events.RentRequestCreated) error {
+		return email(ctx, entities.EmailNotice{Kind: entities.EmailRequestCreated, Username: e.Landlord, PropertyID: e.PropertyID, By: e.Tenant})
+	})
+	eventbus.Subscribe(bus, func(ctx context.Context, e events.RentRequestAccepted) error {
+		return email(ctx, entities.EmailNotice{Kind: entities.EmailRequestDecided, Username: e.Tenant, PropertyID: e.PropertyID, By: e.By, Status: entities.RequestAccepted})
+	})
+	eventbus.Subscribe(bus, func(ctx context.Context, e events.RentRequestRejected) error {
+		return email(ctx, entities.EmailNotice{Kind: entities.EmailRequestDecided, Username: e.Tenant, PropertyID: e.PropertyID, By: e.By, Status: entities.RequestRejected, Reason: e.Reason})
+	})
+	eventbus.Subscribe(bus, func(ctx context.Context, e events.PropertyApproved) error {
+		return email(ctx, entities.EmailNotice{Kind: entities.EmailListingApproved, Username: e.Landlord, PropertyID: e.PropertyID, By: e.By})
+	})
+}
+
+// notifier records notifications for the subscribers.
+type notifier struct {
+	notificationRepo interfaces.NotificationRepo
+}
+
+// notify puts a notification in the inbox of the user. The change it tells about has already been made,
+// so failing to record it is logged rather than failing that change.
+func (n notifier) notify(ctx context.Context, username string, kind entities.NotificationKind, propertyID primitive.ObjectID, format string, args ...interface{}) {
+	notification := entities.Notification{
+		ID:         primitive.NewObjectID(),
+		Username:   username,
+		Kind:       kind,
+		Text:       fmt.Sprintf(format, args...),
+		PropertyID: propertyID,
+		CreatedAt:  time.Now(),
+	}
+	if err := n.notificationRepo.SaveNotification(ctx, notification); err != nil {
+		log.Printf("failed to notify %s of %s: %v", username, kind, err)
+	}
+}
@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrTokenExpired = errors.New("token expired")
+	ErrTokenRevoked = errors.New("token revoked, please log in again")
+)
+
+// tokenHeader is the fixed JOSE header of every access token. Tokens with any other
+// algorithm are rejected, so a client cannot pick "none" or a key of its own.
+var tokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// tokenClaims is the payload of an access token.
+type tokenClaims struct {
+	Subject   string `json:"sub"`  // Username
+	Role      string `json:"role"` // Role at the time the token was issued
+	SessionID string `json:"sid"`  // ID of the refresh token behind the login
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// TokenService issues and verifies the credentials of API clients.
+//
+// Access tokens are HS256 signed JWTs that expire after accessTTL. Each login also gets a
+// refresh token, stored hashed in the RefreshTokenRepo, that is exchanged for a new pair
+// before the access token runs out. Every access token names its refresh token, so
+// revoking the refresh token ends the login at once.
+type TokenService struct {
+	userRepo   interfaces.UserRepo
+	tokenRepo  interfaces.RefreshTokenRepo
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+func NewTokenService(userRepo interfaces.UserRepo, tokenRepo interfaces.RefreshTokenRepo, secret []byte, accessTTL, refreshTTL time.Duration) *TokenService {
+	return &TokenService{
+		userRepo:   userRepo,
+		tokenRepo:  tokenRepo,
+		secret:     secret,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+	}
+}
+
+// GenerateTokenSecret returns a random secret for signing access tokens.
+func GenerateTokenSecret() ([]byte, error) {
+	return randomBytes(32)
+}
+
+// Issue creates the tokens of a session returned by UserService.Login.
+func (ts *TokenService) Issue(ctx context.Context, session *entities.Session) (entities.TokenPair, error) {
+	if err := checkSession(session); err != nil {
+		return entities.TokenPair{}, err
+	}
+
+	id, err := randomString(16)
+	if err != nil {
+		return entities.TokenPair{}, err
+	}
+	now := time.Now()
+	record := entities.RefreshToken{
+		ID:        id,
+		Username:  session.Username(),
+		IssuedAt:  now,
+		ExpiresAt: now.Add(ts.refreshTTL),
+	}
+	return ts.issuePair(ctx, &record, session.User, now)
+}
+
+// Authenticate verifies the access token and returns the session of its user.
+// The user and role are read from the repository, so they reflect any change made since login.
+func (ts *TokenService) Authenticate(ctx context.Context, accessToken string) (*entities.Session, error) {
+	claims, err := ts.verify(accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := ts.tokenRepo.FindRefreshToken(ctx, claims.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil || record.Username != claims.Subject {
+		return nil, ErrInvalidToken
+	}
+	if record.Revoked {
+		return nil, ErrTokenRevoked
+	}
+
+	user, err := ts.userRepo.FindByUsername(ctx, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrInvalidToken // User deleted since login
+	}
+
+	return &entities.Session{
+		ID:        record.ID,
+		User:      *user,
+		Role:      user.Role,
+		LoginTime: record.IssuedAt,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+	}, nil
+}
+
+// Refresh exchanges a refresh token for a new pair. Each refresh token can be used once:
+// presenting an old one again means it leaked, and the whole login is revoked.
+func (ts *TokenService) Refresh(ctx context.Context, refreshToken string) (entities.TokenPair, error) {
+	id, secret, ok := strings.Cut(refreshToken, ".")
+	if !ok || id == "" || secret == "" {
+		return entities.TokenPair{}, ErrInvalidToken
+	}
+
+	record, err := ts.tokenRepo.FindRefreshToken(ctx, id)
+	if err != nil {
+		return entities.TokenPair{}, err
+	}
+	if record == nil {
+		return entities.TokenPair{}, ErrInvalidToken
+	}
+	if record.Revoked {
+		return entities.TokenPair{}, ErrTokenRevoked
+	}
+	now := time.Now()
+	if !record.IsActive(now) {
+		return entities.TokenPair{}, ErrTokenExpired
+	}
+	if subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(record.TokenHash)) != 1 {
+		if err := ts.tokenRepo.RevokeRefreshToken(ctx, id); err != nil {
+			return entities.TokenPair{}, err
+		}
+		return entities.TokenPair{}, ErrTokenRevoked
+	}
+
+	user, err := ts.userRepo.FindByUsername(ctx, record.Username)
+	if err != nil {
+		return entities.TokenPair{}, err
+	}
+	if user == nil {
+		return entities.TokenPair{}, ErrInvalidToken
+	}
+
+	record.ExpiresAt = now.Add(ts.refreshTTL)
+	return ts.issuePair(ctx, record, *user, now)
+}
+
+// Revoke ends the login behind the session, as on logout.
+func (ts *TokenService) Revoke(ctx context.Context, session *entities.Session) error {
+	if session == nil || session.ID == "" {
+		return ErrNotLoggedIn
+	}
+	return ts.tokenRepo.RevokeRefreshToken(ctx, session.ID)
+}
+
+// RevokeAll ends every login of the user.
+func (ts *TokenService) RevokeAll(ctx context.Context, username string) error {
+	return ts.tokenRepo.RevokeUserRefreshTokens(ctx, username)
+}
+
+// issuePair gives the record a new refresh secret, saves it and signs a matching access token.
+// A record that already has a secret is only rotated if no other refresh has rotated it meanwhile,
+// so each refresh token is used once; the one that lost the race fails with ErrInvalidToken.
+func (ts *TokenService) issuePair(ctx context.Context, record *entities.RefreshToken, user entities.User, now time.Time) (entities.TokenPair, error) {
+	secret, err := randomString(32)
+	if err != nil {
+		return entities.TokenPair{}, err
+	}
+	previousHash := record.TokenHash
+	record.TokenHash = hashSecret(secret)
+	if previousHash == "" {
+		if err := ts.tokenRepo.SaveRefreshToken(ctx, *record); err != nil {
+			return entities.TokenPair{}, err
+		}
+	} else {
+		rotated, err := ts.tokenRepo.RotateRefreshToken(ctx, *record, previousHash)
+		if err != nil {
+			return entities.TokenPair{}, err
+		}
+		if !rotated {
+			return entities.TokenPair{}, ErrInvalidToken
+		}
+	}
+
+	claims := tokenClaims{
+		Subject:   user.Username,
+		Role:      user.Role,
+		SessionID: record.ID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ts.accessTTL).Unix(),
+	}
+	accessToken, err := ts.sign(claims)
+	if err != nil {
+		return entities.TokenPair{}, err
+	}
+
+	return entities.TokenPair{
+		AccessToken:      accessToken,
+		AccessExpiresAt:  time.Unix(claims.ExpiresAt, 0),
+		RefreshToken:     record.ID + "." + secret,
+		RefreshExpiresAt: record.ExpiresAt,
+	}, nil
+}
+
+func (ts *TokenService) sign(claims tokenClaims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := tokenHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(ts.signature(signingInput)), nil
+}
+
+// verify checks the signature and expiry of an access token and returns its claims.
+func (ts *TokenService) verify(token string) (tokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != tokenHeader {
+		return tokenClaims{}, ErrInvalidToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, ts.signature(parts[0]+"."+parts[1])) {
+		return tokenClaims{}, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return tokenClaims{}, ErrInvalidToken
+	}
+	var claims tokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Subject == "" || claims.SessionID == "" {
+		return tokenClaims{}, ErrInvalidToken
+	}
+	if !time.Now().Before(time.Unix(claims.ExpiresAt, 0)) {
+		return tokenClaims{}, ErrTokenExpired
+	}
+	return claims, nil
+}
+
+func (ts *TokenService) signature(signingInput string) []byte {
+	mac := hmac.New(sha256.New, ts.secret)
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil)
+}
+
+// hashSecret is how refresh secrets are stored, so a leaked database does not leak logins.
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func randomString(n int) (string, error) {
+	b, err := randomBytes(n)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
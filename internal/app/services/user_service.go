@@ -5,36 +5,59 @@ import (
 	"errors"
 	"fmt"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"rentease/internal/domain/entities"
+	"rentease/internal/domain/events"
 	"rentease/internal/domain/interfaces"
-	"rentease/pkg/utils"
+	"time"
+)
+
+var (
+	ErrUsernameTaken     = errors.New("username already exists")
+	ErrAlreadyInWishlist = errors.New("property is already in the wishlist")
+	ErrUserNotFound      = errors.New("user not found")
+	ErrInvalidRole       = errors.New("invalid role")
 )
 
 type UserService struct {
-	userRepo interfaces.UserRepo
+	userRepo         interfaces.UserRepo
+	propertyRepo     interfaces.PropertyRepo
+	refreshTokenRepo interfaces.RefreshTokenRepo
+	publisher        interfaces.EventPublisher
 }
 
-func NewUserService(userRepo interfaces.UserRepo) *UserService {
+// NewUserService manages the users. The properties and refresh tokens are needed to remove what belongs
+// to a user being deleted.
+func NewUserService(userRepo interfaces.UserRepo, propertyRepo interfaces.PropertyRepo, refreshTokenRepo interfaces.RefreshTokenRepo, publisher interfaces.EventPublisher) *UserService {
 	return &UserService{
-		userRepo: userRepo,
+		userRepo:         userRepo,
+		propertyRepo:     propertyRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		publisher:        publisher,
 	}
 }
 
 //func (us *UserService) Login(user entities.User) error {
 //}
 
-func (us *UserService) SignUp(user entities.User) error {
-	err := us.userRepo.SaveUser(user)
-	if err != nil {
-		fmt.Println(err)
+// SignUp saves a new user, rejecting usernames that are already taken, and publishes UserSignedUp.
+func (us *UserService) SignUp(ctx context.Context, user entities.User) error {
+	existing, err := us.userRepo.FindByUsername(ctx, user.Username)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
 		return err
 	}
-	return nil
+	if existing != nil {
+		return ErrUsernameTaken
+	}
 
+	if err := us.userRepo.SaveUser(ctx, user); err != nil {
+		return err
+	}
+	return us.publisher.Publish(ctx, events.UserSignedUp{Username: user.Username})
 }
 
-func (us *UserService) FindByUsername(username string) (entities.User, error) {
-	user, err := us.userRepo.FindByUsername(context.Background(), username)
+func (us *UserService) FindByUsername(ctx context.Context, username string) (entities.User, error) {
+	user, err := us.userRepo.FindByUsername(ctx, username)
 	if err != nil {
 		return entities.User{}, err
 	}
@@ -44,35 +67,52 @@ func (us *UserService) FindByUsername(username string) (entities.User, error) {
 	return *user, nil
 }
 
-func (us *UserService) Login(username, password string) (bool, error) {
-	exist, err := us.userRepo.CheckPassword(context.Background(), username, password)
+// Login checks the credentials and starts a new session for the user.
+func (us *UserService) Login(ctx context.Context, username, password string) (*entities.Session, error) {
+	exist, err := us.userRepo.CheckPassword(ctx, username, password)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 	if !exist {
-		return false, nil
+		return nil, ErrInvalidCredentials
 	}
-	utils.ActiveUser = username
-	return true, nil
 
+	user, err := us.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	now := time.Now()
+	return &entities.Session{
+		User:      *user,
+		Role:      user.Role,
+		LoginTime: now,
+		ExpiresAt: now.Add(SessionDuration),
+	}, nil
 }
 
-func (us *UserService) AddToWishlist(username string, propertyID primitive.ObjectID) error {
-	ctx := context.TODO() // Use a proper context in real applications
+// AddToWishlist adds the property to the wishlist of the logged in user.
+func (us *UserService) AddToWishlist(ctx context.Context, session *entities.Session, propertyID primitive.ObjectID) error {
+	if err := authorize(session, entities.PermRentProperties, "keep a wishlist"); err != nil {
+		return err
+	}
 
-	user, err := us.userRepo.FindByUsername(ctx, username)
+	user, err := us.userRepo.FindByUsername(ctx, session.Username())
 	if err != nil {
 		return err
 	}
 
 	if user == nil {
-		return errors.New("user not found")
+		return ErrUserNotFound
 	}
 
 	// Check if the property is already in the wishlist
 	for _, id := range user.Wishlist {
 		if id == propertyID {
-			return errors.New("property is already in the wishlist")
+			return ErrAlreadyInWishlist
 		}
 	}
 
@@ -80,7 +120,7 @@ func (us *UserService) AddToWishlist(username string, propertyID primitive.Objec
 	user.Wishlist = append(user.Wishlist, propertyID)
 
 	// Update the user record
-	err = us.userRepo.UpdateUser(*user)
+	err = us.userRepo.UpdateUser(ctx, *user)
 	if err != nil {
 		return err
 	}
@@ -88,15 +128,94 @@ func (us *UserService) AddToWishlist(username string, propertyID primitive.Objec
 	return nil
 }
 
-func (us *UserService) UpdateUser(user entities.User) error {
-	return us.userRepo.UpdateUser(user)
+// UpdateUser saves changes to the user. Users may only update themselves, unless their role
+// can manage users. The role is never changed here, see SetRole. UserUpdated is published.
+func (us *UserService) UpdateUser(ctx context.Context, session *entities.Session, user entities.User) error {
+	if err := checkSession(session); err != nil {
+		return err
+	}
+	if session.Username() != user.Username && !session.Can(entities.PermManageUsers) {
+		return &ForbiddenError{Username: session.Username(), Action: "update the user", Reason: "users may only update themselves"}
+	}
+
+	existing, err := us.userRepo.FindByUsername(ctx, user.Username)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return ErrUserNotFound
+	}
+	user.Role = existing.Role
+	if err := us.userRepo.UpdateUser(ctx, user); err != nil {
+		return err
+	}
+	return us.publisher.Publish(ctx, events.UserUpdated{Username: user.Username, By: session.Username()})
 }
 
 // Admin specific services
-func (us *UserService) GetAllUsers() ([]entities.User, error) {
-	return us.userRepo.FindAll()
+func (us *UserService) GetAllUsers(ctx context.Context, session *entities.Session) ([]entities.User, error) {
+	if err := authorize(session, entities.PermManageUsers, "list users"); err != nil {
+		return nil, err
+	}
+	return us.userRepo.FindAll(ctx)
+}
+
+// DeleteUser deletes the user, after revoking their logins and deleting the properties they listed; should
+// that fail, the user is left to be deleted again. UserDeleted is published once they are gone. Admins cannot
+// be deleted.
+func (us *UserService) DeleteUser(ctx context.Context, session *entities.Session, username string) error {
+	const action = "delete the user"
+	if err := authorize(session, entities.PermManageUsers, action); err != nil {
+		return err
+	}
+
+	user, err := us.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+	if user.Role == entities.RoleAdmin {
+		return &ForbiddenError{Username: session.Username(), Action: action, Reason: "admins cannot be deleted"}
+	}
+
+	if err := us.refreshTokenRepo.RevokeUserRefreshTokens(ctx, username); err != nil {
+		return fmt.Errorf("failed to revoke the logins of %s: %w", username, err)
+	}
+	if err := us.propertyRepo.DeleteAllListedPropertiesOfaUser(ctx, username); err != nil {
+		return fmt.Errorf("failed to delete the properties of %s: %w", username, err)
+	}
+	if err := us.userRepo.Delete(ctx, username); err != nil {
+		return err
+	}
+	return us.publisher.Publish(ctx, events.UserDeleted{Username: username, By: session.Username()})
 }
 
-func (us *UserService) DeleteUser(username string) error {
-	return us.userRepo.Delete(username)
+// SetRole changes the role of the user. Callers cannot change their own role,
+// so the last admin cannot lock everyone out by accident. UserRoleChanged is published.
+func (us *UserService) SetRole(ctx context.Context, session *entities.Session, username, role string) error {
+	const action = "change the role of the user"
+	if err := authorize(session, entities.PermManageUsers, action); err != nil {
+		return err
+	}
+	if !entities.IsValidRole(role) {
+		return fmt.Errorf("%w %q", ErrInvalidRole, role)
+	}
+	if username == session.Username() {
+		return &ForbiddenError{Username: session.Username(), Action: action, Reason: "users cannot change their own role"}
+	}
+
+	user, err := us.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+	user.Role = role
+	if err := us.userRepo.UpdateUser(ctx, *user); err != nil {
+		return err
+	}
+	return us.publisher.Publish(ctx, events.UserRoleChanged{Username: username, Role: role, By: session.Username()})
 }
@@ -0,0 +1,442 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+	"slices"
+	"time"
+)
+
+var (
+	ErrSlotNotFound = errors.New("visit slot not found")
+	ErrInvalidVisit = errors.New("invalid visit slot")
+)
+
+// Rules for offering and booking visit slots, wrapped in a VisitNotAllowedError when broken.
+var (
+	ErrSlotOverlap     = errors.New("the landlord already offers a slot at that time")
+	ErrSlotTaken       = errors.New("the slot is not open for booking")
+	ErrSlotPassed      = errors.New("the slot has already started")
+	ErrVisitClash      = errors.New("you already have a visit booked at that time")
+	ErrAlreadyVisiting = errors.New("you already have a visit booked at this property")
+)
+
+// ErrVisitNotAllowed is matched by every VisitNotAllowedError, so callers can test for it with errors.Is.
+var ErrVisitNotAllowed = errors.New("visit not allowed")
+
+// VisitNotAllowedError is returned when a slot cannot be offered or booked. Err is the rule that was broken:
+// one of the slot rules above, or ErrOwnProperty, ErrPropertyNotApproved or ErrPropertyRented.
+type VisitNotAllowedError struct {
+	SlotID primitive.ObjectID
+	Err    error
+}
+
+func (e *VisitNotAllowedError) Error() string {
+	return fmt.Sprintf("cannot schedule the visit: %v", e.Err)
+}
+
+// Is makes errors.Is(err, ErrVisitNotAllowed) true for a VisitNotAllowedError.
+func (e *VisitNotAllowedError) Is(target error) bool {
+	return target == ErrVisitNotAllowed
+}
+
+// Unwrap returns the broken rule, so errors.Is(err, ErrSlotTaken) and the like work too.
+func (e *VisitNotAllowedError) Unwrap() error {
+	return e.Err
+}
+
+// VisitStateError is returned for a change the visit slot does not allow in its status,
+// such as rescheduling a visit that was never booked.
+type VisitStateError struct {
+	Status entities.VisitStatus
+	Action string
+}
+
+func (e *VisitStateError) Error() string {
+	return fmt.Sprintf("cannot %s: the slot is %s", e.Action, e.Status)
+}
+
+// Is makes errors.Is(err, ErrInvalidTransition) true for a VisitStateError.
+func (e *VisitStateError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+const (
+	// DefaultVisitLength is how long a slot lasts when the landlord does not say.
+	DefaultVisitLength = 30 * time.Minute
+	// MaxVisitLength is the longest slot the landlord can offer.
+	MaxVisitLength = 4 * time.Hour
+)
+
+// VisitService lets landlords offer times for showing their properties and tenants book them,
+// so that a tenant can see a place before sending a rent request for it.
+type VisitService struct {
+	visitRepo    interfaces.VisitRepo
+	propertyRepo interfaces.PropertyRepo
+}
+
+// NewVisitService creates the service on the visit slots and the properties they are offered for.
+func NewVisitService(visitRepo interfaces.VisitRepo, propertyRepo interfaces.PropertyRepo) *VisitService {
+	return &VisitService{
+		visitRepo:    visitRepo,
+		propertyRepo: propertyRepo,
+	}
+}
+
+// PublishSlots offers slots of the given length starting at each of starts for a property of the logged in
+// landlord. A length of 0 gives DefaultVisitLength. No slot is saved unless all of them are in the future and
+// none overlaps another, or a slot the landlord already offers for any of their properties.
+func (vs *VisitService) PublishSlots(ctx context.Context, session *entities.Session, propertyID primitive.ObjectID, starts []time.Time, length time.Duration) ([]entities.VisitSlot, error) {
+	const action = "offer visit slots"
+	if err := authorize(session, entities.PermListProperties, action); err != nil {
+		return nil, err
+	}
+	if length == 0 {
+		length = DefaultVisitLength
+	}
+	if length < 0 || length > MaxVisitLength {
+		return nil, fmt.Errorf("%w: a visit lasts up to %s", ErrInvalidVisit, MaxVisitLength)
+	}
+	if len(starts) == 0 {
+		return nil, fmt.Errorf("%w: give at least one start time", ErrInvalidVisit)
+	}
+	property, err := vs.findProperty(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeOwner(session, property.LandlordUsername, entities.PermListProperties, action); err != nil {
+		return nil, err
+	}
+	if property.IsRented {
+		return nil, &VisitNotAllowedError{Err: ErrPropertyRented}
+	}
+
+	offered, err := vs.visitRepo.FindSlotsByLandlord(ctx, session.Username())
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	slots := make([]entities.VisitSlot, 0, len(starts))
+	for _, start := range starts {
+		if !start.After(now) {
+			return nil, fmt.Errorf("%w: %s is in the past", ErrInvalidVisit, start.Format(time.RFC3339))
+		}
+		end := start.Add(length)
+		if other := overlappingSlot(offered, start, end); other != nil {
+			return nil, &VisitNotAllowedError{SlotID: other.ID, Err: ErrSlotOverlap}
+		}
+		if other := overlappingSlot(slots, start, end); other != nil {
+			return nil, fmt.Errorf("%w: the slots at %s and %s overlap", ErrInvalidVisit, other.Start.Format(time.RFC3339), start.Format(time.RFC3339))
+		}
+		slots = append(slots, entities.VisitSlot{
+			ID:           primitive.NewObjectID(),
+			PropertyID:   property.ID,
+			LandlordName: property.LandlordUsername,
+			Start:        start,
+			End:          end,
+			Status:       entities.VisitOpen,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		})
+	}
+	for _, slot := range slots {
+		if err := vs.visitRepo.SaveSlot(ctx, slot); err != nil {
+			return nil, err
+		}
+	}
+	return slots, nil
+}
+
+// VisitSlots gives the slots of a property. Its landlord and moderators see every slot;
+// anyone else only sees the open slots that have not started yet.
+func (vs *VisitService) VisitSlots(ctx context.Context, session *entities.Session, propertyID primitive.ObjectID) ([]entities.VisitSlot, error) {
+	if err := checkSession(session); err != nil {
+		return nil, err
+	}
+	property, err := vs.findProperty(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	slots, err := vs.visitRepo.FindSlotsByProperty(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	if property.LandlordUsername == session.Username() || session.Can(entities.PermModerateProperties) {
+		return slots, nil
+	}
+	now := time.Now()
+	open := make([]entities.VisitSlot, 0, len(slots))
+	for _, slot := range slots {
+		if slot.Status == entities.VisitOpen && slot.Start.After(now) {
+			open = append(open, slot)
+		}
+	}
+	return open, nil
+}
+
+// BookVisit books an open slot for the logged in tenant. A tenant holds one visit per property at a time and
+// cannot book two visits at once; of two tenants booking the same slot only the first gets it. The tenant's
+// visits are checked again once the slot is booked, so that of two bookings of theirs racing each other at
+// most one is kept.
+func (vs *VisitService) BookVisit(ctx context.Context, session *entities.Session, slotID primitive.ObjectID) (entities.VisitSlot, error) {
+	if err := authorize(session, entities.PermRentProperties, "book a visit"); err != nil {
+		return entities.VisitSlot{}, err
+	}
+	slot, err := vs.findSlot(ctx, slotID)
+	if err != nil {
+		return entities.VisitSlot{}, err
+	}
+	if err := vs.checkBooking(ctx, session.Username(), slot, primitive.NilObjectID); err != nil {
+		return entities.VisitSlot{}, err
+	}
+	now := time.Now()
+	booked, err := vs.visitRepo.BookSlot(ctx, slot.ID, session.Username(), now)
+	if err != nil {
+		return entities.VisitSlot{}, err
+	}
+	if !booked {
+		return entities.VisitSlot{}, &VisitNotAllowedError{SlotID: slot.ID, Err: ErrSlotTaken}
+	}
+	if err := vs.checkTenantVisits(ctx, session.Username(), slot, slot.ID); err != nil {
+		if _, freeErr := vs.visitRepo.FreeSlot(ctx, slot.ID, session.Username(), now); freeErr != nil {
+			return entities.VisitSlot{}, freeErr
+		}
+		return entities.VisitSlot{}, err
+	}
+	return vs.reread(ctx, slot.ID)
+}
+
+// RescheduleVisit moves a booked visit to another open slot of the same property. When the tenant moves it,
+// the slot they leave is open to others again; when the landlord moves it, the slot they leave is cancelled.
+func (vs *VisitService) RescheduleVisit(ctx context.Context, session *entities.Session, slotID, toSlotID primitive.ObjectID) (entities.VisitSlot, error) {
+	const action = "reschedule the visit"
+	if err := checkSession(session); err != nil {
+		return entities.VisitSlot{}, err
+	}
+	from, err := vs.findSlot(ctx, slotID)
+	if err != nil {
+		return entities.VisitSlot{}, err
+	}
+	to, err := vs.findSlot(ctx, toSlotID)
+	if err != nil {
+		return entities.VisitSlot{}, err
+	}
+	if to.PropertyID != from.PropertyID || to.ID == from.ID {
+		return entities.VisitSlot{}, fmt.Errorf("%w: a visit moves to another slot of the same property", ErrInvalidVisit)
+	}
+	if from.Status != entities.VisitBooked {
+		return entities.VisitSlot{}, &VisitStateError{Status: from.Status, Action: action}
+	}
+	tenant := from.TenantName
+
+	var leave func(at time.Time) (bool, error)
+	switch session.Username() {
+	case tenant:
+		leave = func(at time.Time) (bool, error) { return vs.visitRepo.FreeSlot(ctx, from.ID, tenant, at) }
+	case from.LandlordName:
+		leave = func(at time.Time) (bool, error) {
+			return vs.visitRepo.CancelSlot(ctx, from.ID, entities.VisitBooked, session.Username(), at)
+		}
+	default:
+		return entities.VisitSlot{}, &ForbiddenError{Username: session.Username(), Action: action, Reason: "they are not a party to the visit"}
+	}
+	if err := vs.checkBooking(ctx, tenant, to, from.ID); err != nil {
+		return entities.VisitSlot{}, err
+	}
+
+	now := time.Now()
+	booked, err := vs.visitRepo.BookSlot(ctx, to.ID, tenant, now)
+	if err != nil {
+		return entities.VisitSlot{}, err
+	}
+	if !booked {
+		return entities.VisitSlot{}, &VisitNotAllowedError{SlotID: to.ID, Err: ErrSlotTaken}
+	}
+	err = vs.checkTenantVisits(ctx, tenant, to, to.ID, from.ID)
+	if err == nil {
+		var left bool
+		if left, err = leave(now); err == nil && !left {
+			// The visit was cancelled meanwhile, so there is nothing to move: give the new slot back
+			err = vs.stateError(ctx, from.ID, action)
+		}
+	}
+	if err != nil {
+		if _, freeErr := vs.visitRepo.FreeSlot(ctx, to.ID, tenant, now); freeErr != nil {
+			return entities.VisitSlot{}, freeErr
+		}
+		return entities.VisitSlot{}, err
+	}
+	return vs.reread(ctx, to.ID)
+}
+
+// CancelVisit calls off a slot. The tenant who booked it cancels their visit and the slot is open to others
+// again; the landlord cancels the slot, whether booked or not, and it is not offered any more.
+func (vs *VisitService) CancelVisit(ctx context.Context, session *entities.Session, slotID primitive.ObjectID) (entities.VisitSlot, error) {
+	const action = "cancel the visit"
+	if err := checkSession(session); err != nil {
+		return entities.VisitSlot{}, err
+	}
+	slot, err := vs.findSlot(ctx, slotID)
+	if err != nil {
+		return entities.VisitSlot{}, err
+	}
+
+	now := time.Now()
+	var cancelled bool
+	switch {
+	case slot.LandlordName == session.Username():
+		if slot.Status == entities.VisitCancelled {
+			return entities.VisitSlot{}, &VisitStateError{Status: slot.Status, Action: action}
+		}
+		cancelled, err = vs.visitRepo.CancelSlot(ctx, slot.ID, slot.Status, session.Username(), now)
+	case slot.TenantName == session.Username():
+		if slot.Status != entities.VisitBooked {
+			return entities.VisitSlot{}, &VisitStateError{Status: slot.Status, Action: action}
+		}
+		cancelled, err = vs.visitRepo.FreeSlot(ctx, slot.ID, session.Username(), now)
+	default:
+		return entities.VisitSlot{}, &ForbiddenError{Username: session.Username(), Action: action, Reason: "they are not a party to the visit"}
+	}
+	if err != nil {
+		return entities.VisitSlot{}, err
+	}
+	if !cancelled {
+		return entities.VisitSlot{}, vs.stateError(ctx, slot.ID, action)
+	}
+	return vs.reread(ctx, slot.ID)
+}
+
+// UpcomingVisitsForTenant gives the visits the logged in tenant booked that have not ended yet, soonest first.
+func (vs *VisitService) UpcomingVisitsForTenant(ctx context.Context, session *entities.Session) ([]entities.VisitSlot, error) {
+	if err := authorize(session, entities.PermRentProperties, "see their visits"); err != nil {
+		return nil, err
+	}
+	slots, err := vs.visitRepo.FindSlotsByTenant(ctx, session.Username())
+	if err != nil {
+		return nil, err
+	}
+	return upcomingVisits(slots, time.Now()), nil
+}
+
+// UpcomingVisitsForLandlord gives the booked visits to the logged in landlord's properties that have not ended yet,
+// soonest first.
+func (vs *VisitService) UpcomingVisitsForLandlord(ctx context.Context, session *entities.Session) ([]entities.VisitSlot, error) {
+	if err := authorize(session, entities.PermListProperties, "see the visits to their properties"); err != nil {
+		return nil, err
+	}
+	slots, err := vs.visitRepo.FindSlotsByLandlord(ctx, session.Username())
+	if err != nil {
+		return nil, err
+	}
+	return upcomingVisits(slots, time.Now()), nil
+}
+
+// checkBooking checks the rules for the tenant booking the slot. The tenant's visit in the slot except is
+// left out, as it is the one they are moving.
+func (vs *VisitService) checkBooking(ctx context.Context, tenant string, slot *entities.VisitSlot, except primitive.ObjectID) error {
+	property, err := vs.findProperty(ctx, slot.PropertyID)
+	if err != nil {
+		return err
+	}
+	var rule error
+	switch {
+	case property.LandlordUsername == tenant:
+		rule = ErrOwnProperty
+	case !property.IsApprovedByAdmin:
+		rule = ErrPropertyNotApproved
+	case property.IsRented:
+		rule = ErrPropertyRented
+	case slot.Status != entities.VisitOpen:
+		rule = ErrSlotTaken
+	case !slot.Start.After(time.Now()):
+		rule = ErrSlotPassed
+	}
+	if rule != nil {
+		return &VisitNotAllowedError{SlotID: slot.ID, Err: rule}
+	}
+	return vs.checkTenantVisits(ctx, tenant, slot, except)
+}
+
+// checkTenantVisits checks that the slot neither clashes with an upcoming visit of the tenant nor is at a
+// property they already have a visit to. The visits in the slots skipped are left out.
+func (vs *VisitService) checkTenantVisits(ctx context.Context, tenant string, slot *entities.VisitSlot, skipped ...primitive.ObjectID) error {
+	booked, err := vs.visitRepo.FindSlotsByTenant(ctx, tenant)
+	if err != nil {
+		return err
+	}
+	for _, visit := range upcomingVisits(booked, time.Now()) {
+		switch {
+		case slices.Contains(skipped, visit.ID):
+		case visit.PropertyID == slot.PropertyID:
+			return &VisitNotAllowedError{SlotID: slot.ID, Err: ErrAlreadyVisiting}
+		case visit.Overlaps(slot.Start, slot.End):
+			return &VisitNotAllowedError{SlotID: slot.ID, Err: ErrVisitClash}
+		}
+	}
+	return nil
+}
+
+// reread gives the slot as stored after a change.
+func (vs *VisitService) reread(ctx context.Context, id primitive.ObjectID) (entities.VisitSlot, error) {
+	slot, err := vs.findSlot(ctx, id)
+	if err != nil {
+		return entities.VisitSlot{}, err
+	}
+	return *slot, nil
+}
+
+// stateError reports a slot that changed before a change to it was stored, in its new status.
+func (vs *VisitService) stateError(ctx context.Context, id primitive.ObjectID, action string) error {
+	current, err := vs.findSlot(ctx, id)
+	if err != nil {
+		return err
+	}
+	return &VisitStateError{Status: current.Status, Action: action}
+}
+
+func (vs *VisitService) findSlot(ctx context.Context, id primitive.ObjectID) (*entities.VisitSlot, error) {
+	slot, err := vs.visitRepo.FindSlotByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if slot == nil {
+		return nil, ErrSlotNotFound
+	}
+	return slot, nil
+}
+
+func (vs *VisitService) findProperty(ctx context.Context, id primitive.ObjectID) (*entities.Property, error) {
+	property, err := vs.propertyRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if property == nil {
+		return nil, ErrPropertyNotFound
+	}
+	return property, nil
+}
+
+// overlappingSlot gives the first slot that is not cancelled and shares time with the one from start to end, if any.
+func overlappingSlot(slots []entities.VisitSlot, start, end time.Time) *entities.VisitSlot {
+	for i := range slots {
+		if slots[i].Status != entities.VisitCancelled && slots[i].Overlaps(start, end) {
+			return &slots[i]
+		}
+	}
+	return nil
+}
+
+// upcomingVisits gives the booked slots that have not ended by now.
+func upcomingVisits(slots []entities.VisitSlot, now time.Time) []entities.VisitSlot {
+	upcoming := make([]entities.VisitSlot, 0, len(slots))
+	for _, slot := range slots {
+		if slot.Status == entities.VisitBooked && slot.End.After(now) {
+			upcoming = append(upcoming, slot)
+		}
+	}
+	return upcoming
+}
@@ -0,0 +1,79 @@
+package cli
+
+import "rentease/internal/domain/entities"
+
+func (c *CLI) adminPending(inv *invocation) error {
+	if err := inv.parse(0, 0); err != nil {
+		return err
+	}
+	return c.writePendingProperties(inv)
+}
+
+// writePendingProperties lists the properties waiting for approval, as shown to reviewers.
+func (c *CLI) writePendingProperties(inv *invocation) error {
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+	properties, err := c.propertyService.GetPendingProperties(c.ctx, session)
+	if err != nil {
+		return err
+	}
+	return c.write(inv, propertiesResult(properties))
+}
+
+// adminApprove approves the properties in order, stopping at the first that cannot be approved.
+func (c *CLI) adminApprove(inv *invocation) error {
+	if err := inv.parse(1, -1); err != nil {
+		return err
+	}
+	ids, err := parseObjectIDs(inv)
+	if err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	approved := []entities.Property{}
+	for _, id := range ids {
+		if _, err := c.findProperty(id); err != nil {
+			return err
+		}
+		if err := c.propertyService.ApproveProperty(c.ctx, session, id); err != nil {
+			return err
+		}
+		property, err := c.findProperty(id)
+		if err != nil {
+			return err
+		}
+		approved = append(approved, property)
+	}
+	return c.write(inv, propertiesResult(approved))
+}
+
+// adminReject turns down a property waiting for approval and shows it as it was.
+func (c *CLI) adminReject(inv *invocation) error {
+	reason := inv.flags.String("reason", "", "why the listing is turned down, shown to its landlord")
+	if err := inv.parse(1, 1); err != nil {
+		return err
+	}
+	ids, err := parseObjectIDs(inv)
+	if err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	property, err := c.findProperty(ids[0])
+	if err != nil {
+		return err
+	}
+	if err := c.propertyService.RejectProperty(c.ctx, session, ids[0], *reason); err != nil {
+		return err
+	}
+	return c.write(inv, propertiesResult([]entities.Property{property}))
+}
@@ -0,0 +1,241 @@
+// Package cli runs RentEase commands without the menus, so they can be used from scripts and scheduled jobs.
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"rentease/internal/app/services"
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+// Exit codes returned by Run.
+const (
+	ExitOK       = 0
+	ExitFailure  = 1 // The command failed
+	ExitUsage    = 2 // Unknown command or invalid flags or arguments
+	ExitDenied   = 3 // Login failed or the user may not run the command
+	ExitNotFound = 4 // A user, property, rent request, lease, payment, maintenance ticket, visit slot or message thread does not exist
+)
+
+// Environment variables holding the credentials that commands log in with.
+const (
+	EnvUsername = "RENTEASE_USER"
+	EnvPassword = "RENTEASE_PASSWORD"
+)
+
+// errUsageReported is returned once the usage of a command has been printed for invalid flags or arguments.
+var errUsageReported = errors.New("invalid usage")
+
+// CLI runs one command against the same services as the menus and the API.
+type CLI struct {
+	ctx                 context.Context
+	userService         interfaces.UserService
+	propertyService     interfaces.PropertyService
+	requestService      interfaces.RentRequestService
+	leaseService        interfaces.LeaseService
+	ledgerService       interfaces.LedgerService
+	depositService      interfaces.DepositService
+	maintenanceService  interfaces.MaintenanceService
+	visitService        interfaces.VisitService
+	messageService      interfaces.MessageService
+	notificationService interfaces.NotificationService
+	documentService     interfaces.DocumentService
+
+	stdout io.Writer // Results
+	stderr io.Writer // Usage and errors
+}
+
+// New creates a CLI writing results to stdout and errors to stderr.
+// ctx is used for all service calls.
+func New(ctx context.Context, userService interfaces.UserService, propertyService interfaces.PropertyService, requestService interfaces.RentRequestService, leaseService interfaces.LeaseService, ledgerService interfaces.LedgerService, depositService interfaces.DepositService, maintenanceService interfaces.MaintenanceService, visitService interfaces.VisitService, messageService interfaces.MessageService, notificationService interfaces.NotificationService, documentService interfaces.DocumentService, stdout, stderr io.Writer) *CLI {
+	return &CLI{
+		ctx:                 ctx,
+		userService:         userService,
+		propertyService:     propertyService,
+		requestService:      requestService,
+		leaseService:        leaseService,
+		ledgerService:       ledgerService,
+		depositService:      depositService,
+		maintenanceService:  maintenanceService,
+		visitService:        visitService,
+		messageService:      messageService,
+		notificationService: notificationService,
+		documentService:     documentService,
+		stdout:              stdout,
+		stderr:              stderr,
+	}
+}
+
+// command is a subcommand such as "property list".
+type command struct {
+	group   string
+	name    string
+	args    string // Positional arguments, as shown in the usage
+	summary string
+	run     func(c *CLI, inv *invocation) error
+}
+
+var commands = []command{
+	{"property", "list", "", "List approved properties, or those of a landlord or waiting for approval", (*CLI).propertyList},
+	{"property", "show", "<id>", "Show a property", (*CLI).propertyShow},
+	{"property", "delete", "<id>...", "Delete properties of the user, or any property as a moderator", (*CLI).propertyDelete},
+	{"request", "list", "", "List the rent requests sent by the user, or received for their properties", (*CLI).requestList},
+	{"request", "accept", "<id>", "Accept a rent request for a property of the user; the property is rented out and other requests rejected", (*CLI).requestAccept},
+	{"request", "reject", "<id>", "Reject a rent request for a property of the user", (*CLI).requestReject},
+	{"request", "withdraw", "<id>", "Withdraw a pending rent request sent by the user", (*CLI).requestWithdraw},
+	{"request", "expire", "", "Expire rent requests left pending for too long (needs a reviewer login)", (*CLI).requestExpire},
+	{"lease", "list", "", "List the leases of the user as tenant, or with -landlord those of their properties", (*CLI).leaseList},
+	{"lease", "show", "<id>", "Show a lease of the user", (*CLI).leaseShow},
+	{"lease", "notice", "<id>", "Give notice on an active lease of the user; it ends after the notice period", (*CLI).leaseNotice},
+	{"lease", "offer-renewal", "<id>", "Offer to renew a lease of a property of the user for -months at -rent", (*CLI).leaseOfferRenewal},
+	{"lease", "rules", "<id>", "Set the rent rules of a lease of a property of the user: -escalate-percent every -escalate-every months, -late-per-day after -late-grace days", (*CLI).leaseRules},
+	{"lease", "accept-renewal", "<id>", "Accept the renewal offer of a lease of the user", (*CLI).leaseAcceptRenewal},
+	{"lease", "decline-renewal", "<id>", "Decline the renewal offer of a lease of the user", (*CLI).leaseDeclineRenewal},
+	{"lease", "end-due", "", "End the leases past their end date and put their properties back on the market (needs a reviewer login)", (*CLI).leaseEndDue},
+	{"ledger", "show", "<lease-id>", "Show the rent statement of a lease of the user: rent due, payments and what is outstanding", (*CLI).ledgerShow},
+	{"ledger", "record", "<lease-id>", "Record a rent payment of -amount made on -date by -mode for a lease of a property of the user", (*CLI).ledgerRecord},
+	{"ledger", "schedule", "<lease-id>", "Show the rent due in the next -months of a lease of the user, or with rule flags as in lease rules a dry run under those rules", (*CLI).ledgerSchedule},
+	{"ledger", "payments", "<lease-id>", "List the rent payments recorded for a lease of the user", (*CLI).ledgerPayments},
+	{"deposit", "show", "<lease-id>", "Show the security deposit of a lease of the user: received, deducted and refunded", (*CLI).depositShow},
+	{"deposit", "record", "<lease-id>", "Record receiving the deposit of -amount on -date by -mode for a lease of a property of the user", (*CLI).depositRecord},
+	{"deposit", "deduct", "<lease-id>", "Itemise the -deduct amount:reason[:note] kept back of the deposit at move-out; the rest is refunded", (*CLI).depositDeduct},
+	{"deposit", "acknowledge", "<lease-id>", "Accept the deposit deductions of a lease of the user, closing the deposit", (*CLI).depositAcknowledge},
+	{"deposit", "dispute", "<lease-id>", "Dispute the deposit deductions of a lease of the user, giving the -reason", (*CLI).depositDispute},
+	{"maintenance", "report", "<lease-id>", "Report a -category issue with the property of a lease of the user, with its -priority and -description", (*CLI).maintenanceReport},
+	{"maintenance", "list", "", "List the maintenance tickets the user reported, or with -landlord the open ones on their properties, overdue first", (*CLI).maintenanceList},
+	{"maintenance", "show", "<id>", "Show a maintenance ticket of the user with its comments", (*CLI).maintenanceShow},
+	{"maintenance", "status", "<id>", "Move a maintenance ticket of the user to -status, with an optional -note", (*CLI).maintenanceStatus},
+	{"maintenance", "comment", "<id>", "Add the -text comment to a maintenance ticket of the user", (*CLI).maintenanceComment},
+	{"visit", "publish", "<property-id>", "Offer slots to visit a property of the user, starting -at each given time and lasting -length", (*CLI).visitPublish},
+	{"visit", "slots", "<property-id>", "List the open visit slots of a property, or all of them for its landlord", (*CLI).visitSlots},
+	{"visit", "list", "", "List the upcoming visits the user booked, or with -landlord those to their properties", (*CLI).visitList},
+	{"visit", "book", "<slot-id>", "Book an open visit slot", (*CLI).visitBook},
+	{"visit", "reschedule", "<slot-id> <to-slot-id>", "Move a booked visit to another open slot of the same property", (*CLI).visitReschedule},
+	{"visit", "cancel", "<slot-id>", "Call off a visit the user booked, or withdraw a slot of their property", (*CLI).visitCancel},
+	{"message", "list", "", "List the message threads of the user with their unread messages, latest first", (*CLI).messageList},
+	{"message", "unread", "", "Count the messages the user has not read, as tenant and as landlord", (*CLI).messageUnread},
+	{"message", "show", "<thread-id>", "Show the messages of a thread of the user and mark them read", (*CLI).messageShow},
+	{"message", "send", "<thread-id>", "Send the -text message in a thread of the user", (*CLI).messageSend},
+	{"message", "property", "<property-id>", "Send the -text message to the landlord of a property, starting a thread about it if needed", (*CLI).messageProperty},
+	{"message", "request", "<request-id>", "Send the -text message to the other party of a rent request, in the thread about its property", (*CLI).messageRequest},
+	{"notification", "list", "", "List the notifications of the user, newest first, or with -unread those not read yet", (*CLI).notificationList},
+	{"notification", "unread", "", "Count the notifications the user has not read", (*CLI).notificationUnread},
+	{"notification", "read", "[<id>...]", "Mark notifications of the user read, or with -all every one of them", (*CLI).notificationRead},
+	{"notification", "delete", "<id>...", "Remove notifications of the user", (*CLI).notificationDelete},
+	{"notification", "clear", "", "Remove the notifications the user has read", (*CLI).notificationClear},
+	{"document", "lease", "<lease-id>", "Save a PDF copy of a lease agreement of the user to -out", (*CLI).documentLease},
+	{"document", "receipt", "<lease-id> <payment-id>", "Save the PDF receipt of a rent payment for a lease of the user to -out", (*CLI).documentReceipt},
+	{"document", "export-templates", "<dir>", "Write the built-in receipt and lease templates into a directory to customise them", (*CLI).documentExportTemplates},
+	{"admin", "pending", "", "List the properties waiting for approval", (*CLI).adminPending},
+	{"admin", "approve", "<id>...", "Approve properties", (*CLI).adminApprove},
+	{"admin", "reject", "<id>", "Turn down a property waiting for approval, giving the -reason; the listing is removed", (*CLI).adminReject},
+	{"user", "list", "", "List all users", (*CLI).userList},
+	{"user", "delete", "<username>...", "Delete users with their properties and logins", (*CLI).userDelete},
+	{"user", "set-role", "<username> <role>", "Change the role of a user", (*CLI).userSetRole},
+}
+
+// Run runs the command named by args, e.g. ["property", "list", "-json"], and returns the exit code.
+func (c *CLI) Run(args []string) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" {
+		c.usage(c.stdout)
+		return ExitOK
+	}
+	if len(args) < 2 {
+		fmt.Fprintf(c.stderr, "Error: missing subcommand of %q\n\n", args[0])
+		c.usage(c.stderr)
+		return ExitUsage
+	}
+
+	cmd := findCommand(args[0], args[1])
+	if cmd == nil {
+		fmt.Fprintf(c.stderr, "Error: unknown command %q\n\n", args[0]+" "+args[1])
+		c.usage(c.stderr)
+		return ExitUsage
+	}
+
+	err := cmd.run(c, newInvocation(cmd, args[2:], c.stderr))
+	code := exitCode(err)
+	if code != ExitOK && !errors.Is(err, errUsageReported) {
+		fmt.Fprintln(c.stderr, "Error:", err)
+	}
+	return code
+}
+
+func findCommand(group, name string) *command {
+	for i := range commands {
+		if commands[i].group == group && commands[i].name == name {
+			return &commands[i]
+		}
+	}
+	return nil
+}
+
+// usage lists the commands.
+func (c *CLI) usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: rentease [configuration flags] <command> <subcommand> [flags] [arguments]")
+	fmt.Fprintln(w, "Without a command the interactive menus are started.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-40s %s\n", strings.TrimSpace(cmd.group+" "+cmd.name+" "+cmd.args), cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Commands that need a login log in as -user (or %s) with the password in %s.\n", EnvUsername, EnvPassword)
+	fmt.Fprintln(w, "Results are printed as a table, or with -json or -csv for other programs.")
+	fmt.Fprintf(w, "Exit codes: %d ok, %d failed, %d invalid usage, %d not logged in or not allowed, %d not found.\n",
+		ExitOK, ExitFailure, ExitUsage, ExitDenied, ExitNotFound)
+}
+
+// exitCode maps the error returned by a command onto an exit code.
+func exitCode(err error) int {
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return ExitOK
+	case errors.Is(err, errUsageReported),
+		errors.Is(err, services.ErrInvalidRole),
+		errors.Is(err, services.ErrInvalidLeaseTerms),
+		errors.Is(err, services.ErrInvalidPayment),
+		errors.Is(err, services.ErrInvalidDeposit),
+		errors.Is(err, services.ErrInvalidRentRules),
+		errors.Is(err, services.ErrInvalidTicket),
+		errors.Is(err, services.ErrInvalidVisit),
+		errors.Is(err, services.ErrInvalidMessage):
+		return ExitUsage
+	case errors.Is(err, services.ErrInvalidCredentials),
+		errors.Is(err, services.ErrNotLoggedIn),
+		errors.Is(err, services.ErrSessionExpired),
+		errors.Is(err, services.ErrForbidden):
+		return ExitDenied
+	case errors.Is(err, services.ErrUserNotFound),
+		errors.Is(err, services.ErrPropertyNotFound),
+		errors.Is(err, services.ErrRequestNotFound),
+		errors.Is(err, services.ErrLeaseNotFound),
+		errors.Is(err, services.ErrPaymentNotFound),
+		errors.Is(err, services.ErrTicketNotFound),
+		errors.Is(err, services.ErrSlotNotFound),
+		errors.Is(err, services.ErrThreadNotFound),
+		errors.Is(err, services.ErrNotificationNotFound):
+		return ExitNotFound
+	default:
+		return ExitFailure
+	}
+}
+
+// login logs in as the user given by -user or RENTEASE_USER, with the password in RENTEASE_PASSWORD.
+func (c *CLI) login(inv *invocation) (*entities.Session, error) {
+	username := inv.user
+	if username == "" {
+		username = os.Getenv(EnvUsername)
+	}
+	if username == "" {
+		return nil, fmt.Errorf("%w: give the user with -user or %s and the password in %s", services.ErrNotLoggedIn, EnvUsername, EnvPassword)
+	}
+	return c.userService.Login(c.ctx, username, os.Getenv(EnvPassword))
+}
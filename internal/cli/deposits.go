@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"rentease/internal/app/services"
+	"rentease/internal/domain/entities"
+)
+
+// deductionsFlag collects the deductions given with repeated -deduct flags, each as amount:reason[:note].
+type deductionsFlag []entities.DepositDeduction
+
+func (d *deductionsFlag) String() string {
+	items := make([]string, 0, len(*d))
+	for _, deduction := range *d {
+		items = append(items, formatMoney(deduction.Amount)+":"+deduction.Reason)
+	}
+	return strings.Join(items, ", ")
+}
+
+func (d *deductionsFlag) Set(value string) error {
+	parts := strings.SplitN(value, ":", 3)
+	if len(parts) < 2 {
+		return fmt.Errorf("expected amount:reason[:note], got %q", value)
+	}
+	amount, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return fmt.Errorf("invalid amount %q", parts[0])
+	}
+	deduction := entities.DepositDeduction{Amount: amount, Reason: parts[1]}
+	if len(parts) == 3 {
+		deduction.Note = parts[2]
+	}
+	*d = append(*d, deduction)
+	return nil
+}
+
+// depositResult shows what was received of the deposit, the deductions and the refund.
+func depositResult(deposit entities.Deposit) result {
+	status := string(deposit.Status)
+	if deposit.Status == entities.DepositDisputed && deposit.Dispute != nil {
+		status += ": " + deposit.Dispute.Reason
+	}
+	rows := [][]string{
+		{"Status", "", status},
+		{"Agreed", formatMoney(deposit.Agreed), ""},
+	}
+	if deposit.Status != entities.DepositAwaited {
+		received := fmt.Sprintf("on %s by %s", deposit.ReceivedOn.Format(dateLayout), deposit.Mode)
+		if deposit.Reference != "" {
+			received += " (" + deposit.Reference + ")"
+		}
+		rows = append(rows, []string{"Received", formatMoney(deposit.Received), received})
+		for _, deduction := range deposit.Deductions {
+			rows = append(rows, []string{"Deduction: " + deduction.Reason, formatMoney(deduction.Amount), deduction.Note})
+		}
+		rows = append(rows, []string{"Refund", formatMoney(deposit.Refund), ""})
+	}
+	return result{
+		noun:   "deposit",
+		value:  deposit,
+		header: []string{"Item", "Amount", "Note"},
+		rows:   rows,
+	}
+}
+
+// depositShow shows the security deposit of a lease the user is a party to.
+func (c *CLI) depositShow(inv *invocation) error {
+	if err := inv.parse(1, 1); err != nil {
+		return err
+	}
+	ids, err := parseObjectIDs(inv)
+	if err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	deposit, err := c.depositService.Deposit(c.ctx, session, ids[0])
+	if err != nil {
+		return err
+	}
+	return c.write(inv, depositResult(deposit))
+}
+
+// depositRecord records receiving the security deposit of a lease of a property of the user.
+func (c *CLI) depositRecord(inv *invocation) error {
+	amount := inv.flags.Float64("amount", 0, "amount received")
+	date := inv.flags.String("date", "", "day the deposit was received, e.g. 2024-05-01 (default: today)")
+	mode := inv.flags.String("mode", string(entities.PaymentBankTransfer), fmt.Sprintf("how it was paid, one of %v", entities.PaymentModes()))
+	reference := inv.flags.String("reference", "", "cheque number, transaction ID and so on")
+	if err := inv.parse(1, 1); err != nil {
+		return err
+	}
+	ids, err := parseObjectIDs(inv)
+	if err != nil {
+		return err
+	}
+	receipt := entities.Payment{Amount: *amount, Mode: entities.PaymentMode(*mode), Reference: *reference}
+	if *date != "" {
+		if receipt.PaidOn, err = time.Parse(dateLayout, *date); err != nil {
+			return fmt.Errorf("%w: -date must be a day such as %s", services.ErrInvalidPayment, dateLayout)
+		}
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	deposit, err := c.depositService.RecordDeposit(c.ctx, session, ids[0], receipt)
+	if err != nil {
+		return err
+	}
+	return c.write(inv, depositResult(deposit))
+}
+
+// depositDeduct itemises what the user keeps back of the deposit of a lease of their property at move-out.
+func (c *CLI) depositDeduct(inv *invocation) error {
+	var deductions deductionsFlag
+	inv.flags.Var(&deductions, "deduct", "a deduction as amount:reason[:note]; repeat for each (none refunds the whole deposit)")
+	if err := inv.parse(1, 1); err != nil {
+		return err
+	}
+	ids, err := parseObjectIDs(inv)
+	if err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	deposit, err := c.depositService.ProposeDeductions(c.ctx, session, ids[0], deductions)
+	if err != nil {
+		return err
+	}
+	return c.write(inv, depositResult(deposit))
+}
+
+// depositAcknowledge accepts the deposit deductions of a lease of the user, closing the deposit.
+func (c *CLI) depositAcknowledge(inv *invocation) error {
+	if err := inv.parse(1, 1); err != nil {
+		return err
+	}
+	ids, err := parseObjectIDs(inv)
+	if err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	deposit, err := c.depositService.AcknowledgeDeductions(c.ctx, session, ids[0])
+	if err != nil {
+		return err
+	}
+	return c.write(inv, depositResult(deposit))
+}
+
+// depositDispute disputes the deposit deductions of a lease of the user.
+func (c *CLI) depositDispute(inv *invocation) error {
+	reason := inv.flags.String("reason", "", "why the deductions are disputed")
+	if err := inv.parse(1, 1); err != nil {
+		return err
+	}
+	ids, err := parseObjectIDs(inv)
+	if err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	deposit, err := c.depositService.DisputeDeductions(c.ctx, session, ids[0], *reason)
+	if err != nil {
+		return err
+	}
+	return c.write(inv, depositResult(deposit))
+}
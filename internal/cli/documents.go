@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"os"
+	"strconv"
+
+	"rentease/internal/documents"
+	"rentease/internal/domain/entities"
+)
+
+// documentLease saves a copy of a lease agreement the user is a party to.
+func (c *CLI) documentLease(inv *invocation) error {
+	out := inv.flags.String("out", "", "file to save the PDF to, or - for standard output (default: lease-<id>.pdf)")
+	if err := inv.parse(1, 1); err != nil {
+		return err
+	}
+	ids, err := parseObjectIDs(inv)
+	if err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	document, err := c.documentService.LeaseAgreement(c.ctx, session, ids[0])
+	if err != nil {
+		return err
+	}
+	return c.saveDocument(inv, document, *out)
+}
+
+// documentReceipt saves the receipt of a rent payment for a lease the user is a party to.
+func (c *CLI) documentReceipt(inv *invocation) error {
+	out := inv.flags.String("out", "", "file to save the PDF to, or - for standard output (default: receipt-<number>.pdf)")
+	if err := inv.parse(2, 2); err != nil {
+		return err
+	}
+	ids, err := parseObjectIDs(inv)
+	if err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	document, err := c.documentService.RentReceipt(c.ctx, session, ids[0], ids[1])
+	if err != nil {
+		return err
+	}
+	return c.saveDocument(inv, document, *out)
+}
+
+// documentExportTemplates writes the built-in document templates into a directory, to be customised
+// and then used through documents.template_dir.
+func (c *CLI) documentExportTemplates(inv *invocation) error {
+	if err := inv.parse(1, 1); err != nil {
+		return err
+	}
+	written, err := documents.ExportTemplates(inv.positional[0])
+	if err != nil {
+		return err
+	}
+	rows := make([][]string, 0, len(written))
+	for _, path := range written {
+		rows = append(rows, []string{path})
+	}
+	return c.write(inv, result{noun: "templates", value: written, header: []string{"Template"}, rows: rows})
+}
+
+// saveDocument writes the document to the file, or to standard output for "-", and reports where it went.
+func (c *CLI) saveDocument(inv *invocation, document entities.Document, path string) error {
+	if path == "-" {
+		_, err := c.stdout.Write(document.Content)
+		return err
+	}
+	if path == "" {
+		path = document.FileName
+	}
+	if err := os.WriteFile(path, document.Content, 0o644); err != nil {
+		return err
+	}
+	return c.write(inv, result{
+		noun:   "documents",
+		value:  map[string]interface{}{"file": path, "bytes": len(document.Content)},
+		header: []string{"File", "Bytes"},
+		rows:   [][]string{{path, strconv.Itoa(len(document.Content))}},
+	})
+}
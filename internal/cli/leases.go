@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/app/services"
+	"rentease/internal/domain/entities"
+)
+
+func leasesResult(leases []entities.Lease) result {
+	if leases == nil {
+		leases = []entities.Lease{}
+	}
+	rows := make([][]string, 0, len(leases))
+	for _, l := range leases {
+		rows = append(rows, []string{
+			l.ID.Hex(),
+			l.PropertyID.Hex(),
+			l.TenantName,
+			l.LandlordName,
+			l.StartDate.Format(dateLayout),
+			l.EndDate.Format(dateLayout),
+			strconv.FormatFloat(l.MonthlyRent, 'f', 2, 64),
+			strconv.FormatFloat(l.Deposit, 'f', 2, 64),
+			strconv.Itoa(l.NoticePeriodDays),
+			string(l.Status),
+			renewalSummary(l.Renewal),
+		})
+	}
+	return result{
+		noun:   "leases",
+		value:  leases,
+		header: []string{"ID", "Property", "Tenant", "Landlord", "Start", "End", "Rent", "Deposit", "Notice Days", "Status", "Renewal"},
+		rows:   rows,
+	}
+}
+
+// renewalSummary describes the latest renewal offer of a lease in one cell.
+func renewalSummary(offer *entities.RenewalOffer) string {
+	if offer == nil {
+		return ""
+	}
+	return string(offer.Status) + ": " + strconv.FormatFloat(offer.MonthlyRent, 'f', 2, 64) + " until " + offer.EndDate.Format(dateLayout)
+}
+
+// dateLayout formats the dates of leases, which have no time of day.
+const dateLayout = "2006-01-02"
+
+// leaseList lists the leases of the user as tenant, or with -landlord those of their properties.
+func (c *CLI) leaseList(inv *invocation) error {
+	landlord := inv.flags.Bool("landlord", false, "list the leases of the properties of the user")
+	if err := inv.parse(0, 0); err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	var leases []entities.Lease
+	if *landlord {
+		leases, err = c.leaseService.GetLeasesForLandlord(c.ctx, session)
+	} else {
+		leases, err = c.leaseService.GetLeasesForTenant(c.ctx, session)
+	}
+	if err != nil {
+		return err
+	}
+	return c.write(inv, leasesResult(leases))
+}
+
+// leaseShow shows a lease the user is a party to.
+func (c *CLI) leaseShow(inv *invocation) error {
+	if err := inv.parse(1, 1); err != nil {
+		return err
+	}
+	ids, err := parseObjectIDs(inv)
+	if err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	lease, err := c.leaseService.GetLease(c.ctx, session, ids[0])
+	if err != nil {
+		return err
+	}
+	r := leasesResult([]entities.Lease{lease})
+	r.value = lease
+	return c.write(inv, r)
+}
+
+// leaseNotice gives notice on a lease the user is the tenant or landlord of.
+func (c *CLI) leaseNotice(inv *invocation) error {
+	return c.changeLease(inv, func(session *entities.Session, id primitive.ObjectID) (entities.Lease, error) {
+		return c.leaseService.GiveNotice(c.ctx, session, id)
+	})
+}
+
+// leaseOfferRenewal offers the tenant to renew a lease of a property of the user.
+func (c *CLI) leaseOfferRenewal(inv *invocation) error {
+	rent := inv.flags.Float64("rent", 0, "monthly rent of the renewed lease (default: the current rent)")
+	months := inv.flags.Int("months", services.DefaultLeaseMonths, "months to extend the lease by")
+	return c.changeLease(inv, func(session *entities.Session, id primitive.ObjectID) (entities.Lease, error) {
+		monthlyRent := *rent
+		if monthlyRent == 0 {
+			lease, err := c.leaseService.GetLease(c.ctx, session, id)
+			if err != nil {
+				return entities.Lease{}, err
+			}
+			monthlyRent = lease.MonthlyRent
+		}
+		return c.leaseService.OfferRenewal(c.ctx, session, id, monthlyRent, *months)
+	})
+}
+
+// leaseRules sets the rent escalation and late fee rules of a lease of a property of the user. Without rule
+// flags the rules are removed.
+func (c *CLI) leaseRules(inv *invocation) error {
+	ruleFlags := addRentRuleFlags(inv)
+	return c.changeLease(inv, func(session *entities.Session, id primitive.ObjectID) (entities.Lease, error) {
+		rules, _ := ruleFlags.rules(inv)
+		return c.leaseService.SetRentRules(c.ctx, session, id, rules)
+	})
+}
+
+// leaseAcceptRenewal accepts the renewal offer of a lease of the user.
+func (c *CLI) leaseAcceptRenewal(inv *invocation) error {
+	return c.changeLease(inv, func(session *entities.Session, id primitive.ObjectID) (entities.Lease, error) {
+		return c.leaseService.AnswerRenewal(c.ctx, session, id, true)
+	})
+}
+
+// leaseDeclineRenewal declines the renewal offer of a lease of the user.
+func (c *CLI) leaseDeclineRenewal(inv *invocation) error {
+	return c.changeLease(inv, func(session *entities.Session, id primitive.ObjectID) (entities.Lease, error) {
+		return c.leaseService.AnswerRenewal(c.ctx, session, id, false)
+	})
+}
+
+// changeLease runs a change of the lease given as the only argument and shows the changed lease.
+func (c *CLI) changeLease(inv *invocation, change func(session *entities.Session, id primitive.ObjectID) (entities.Lease, error)) error {
+	if err := inv.parse(1, 1); err != nil {
+		return err
+	}
+	ids, err := parseObjectIDs(inv)
+	if err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	lease, err := change(session, ids[0])
+	if err != nil {
+		return err
+	}
+	r := leasesResult([]entities.Lease{lease})
+	r.value = lease
+	return c.write(inv, r)
+}
+
+// leaseEndDue ends the leases whose end date has passed, e.g. from cron.
+func (c *CLI) leaseEndDue(inv *invocation) error {
+	if err := inv.parse(0, 0); err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	ended, err := c.leaseService.EndLeases(c.ctx, session, time.Now())
+	if err != nil {
+		return err
+	}
+	return c.write(inv, result{
+		noun:   "ended leases",
+		value:  map[string]int{"ended": ended},
+		header: []string{"Ended Leases"},
+		rows:   [][]string{{strconv.Itoa(ended)}},
+	})
+}
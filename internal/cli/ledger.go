@@ -0,0 +1,230 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"rentease/internal/app/services"
+	"rentease/internal/domain/entities"
+)
+
+// statementResult lists the dues of the statement with what was paid against them, followed by the totals.
+func statementResult(statement entities.Statement) result {
+	rows := make([][]string, 0, len(statement.Lines)+1)
+	for _, line := range statement.Lines {
+		paidOn := ""
+		if line.PaidOn != nil {
+			paidOn = line.PaidOn.Format(dateLayout)
+		}
+		rows = append(rows, []string{
+			strconv.Itoa(line.Due.Period),
+			line.Due.DueDate.Format(dateLayout),
+			formatMoney(line.Due.Amount),
+			formatMoney(line.Paid),
+			formatMoney(line.Balance),
+			paidOn,
+			strconv.Itoa(line.LateDays),
+			formatMoney(line.LateFee),
+		})
+	}
+	if len(rows) > 0 {
+		rows = append(rows, []string{"Total", "", formatMoney(statement.TotalDue), formatMoney(statement.TotalPaid), formatMoney(statement.Outstanding), "", "", formatMoney(statement.LateFees)})
+	}
+	return result{
+		noun:   "rent dues",
+		value:  statement,
+		header: []string{"Month", "Due Date", "Rent", "Paid", "Balance", "Paid On", "Late Days", "Late Fee"},
+		rows:   rows,
+	}
+}
+
+func paymentsResult(payments []entities.Payment) result {
+	rows := make([][]string, 0, len(payments))
+	for _, p := range payments {
+		rows = append(rows, []string{
+			p.ID.Hex(),
+			p.LeaseID.Hex(),
+			formatMoney(p.Amount),
+			p.PaidOn.Format(dateLayout),
+			string(p.Mode),
+			p.Reference,
+			p.RecordedBy,
+		})
+	}
+	return result{
+		noun:   "payments",
+		value:  payments,
+		header: []string{"ID", "Lease", "Amount", "Paid On", "Mode", "Reference", "Recorded By"},
+		rows:   rows,
+	}
+}
+
+func formatMoney(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+// ledgerShow shows the rent statement of a lease the user is a party to.
+func (c *CLI) ledgerShow(inv *invocation) error {
+	if err := inv.parse(1, 1); err != nil {
+		return err
+	}
+	ids, err := parseObjectIDs(inv)
+	if err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	statement, err := c.ledgerService.Statement(c.ctx, session, ids[0])
+	if err != nil {
+		return err
+	}
+	return c.write(inv, statementResult(statement))
+}
+
+// ledgerRecord records a rent payment for a lease of a property of the user.
+func (c *CLI) ledgerRecord(inv *invocation) error {
+	amount := inv.flags.Float64("amount", 0, "amount paid")
+	date := inv.flags.String("date", "", "day the payment was made, e.g. 2024-05-01 (default: today)")
+	mode := inv.flags.String("mode", string(entities.PaymentBankTransfer), fmt.Sprintf("how it was paid, one of %v", entities.PaymentModes()))
+	reference := inv.flags.String("reference", "", "cheque number, transaction ID and so on")
+	if err := inv.parse(1, 1); err != nil {
+		return err
+	}
+	ids, err := parseObjectIDs(inv)
+	if err != nil {
+		return err
+	}
+	payment := entities.Payment{Amount: *amount, Mode: entities.PaymentMode(*mode), Reference: *reference}
+	if *date != "" {
+		if payment.PaidOn, err = time.Parse(dateLayout, *date); err != nil {
+			return fmt.Errorf("%w: -date must be a day such as %s", services.ErrInvalidPayment, dateLayout)
+		}
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	payment, err = c.ledgerService.RecordPayment(c.ctx, session, ids[0], payment)
+	if err != nil {
+		return err
+	}
+	r := paymentsResult([]entities.Payment{payment})
+	r.value = payment
+	return c.write(inv, r)
+}
+
+// ledgerPayments lists the rent payments recorded for a lease the user is a party to.
+func (c *CLI) ledgerPayments(inv *invocation) error {
+	if err := inv.parse(1, 1); err != nil {
+		return err
+	}
+	ids, err := parseObjectIDs(inv)
+	if err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	statement, err := c.ledgerService.Statement(c.ctx, session, ids[0])
+	if err != nil {
+		return err
+	}
+	return c.write(inv, paymentsResult(statement.Payments))
+}
+
+// rentRuleFlags are the flags that give the rent escalation and late fee rules of a lease.
+type rentRuleFlags struct {
+	escalatePercent, escalateAmount *float64
+	escalateEvery                   *int
+	lateGrace                       *int
+	lateFlat, latePerDay, lateMax   *float64
+}
+
+func addRentRuleFlags(inv *invocation) rentRuleFlags {
+	return rentRuleFlags{
+		escalatePercent: inv.flags.Float64("escalate-percent", 0, "raise the rent by this percentage, compounding"),
+		escalateAmount:  inv.flags.Float64("escalate-amount", 0, "raise the rent by this amount"),
+		escalateEvery:   inv.flags.Int("escalate-every", 12, "months between rent raises"),
+		lateGrace:       inv.flags.Int("late-grace", 0, "days after the due date rent can be paid without a late fee"),
+		lateFlat:        inv.flags.Float64("late-flat", 0, "late fee charged once the grace days are over"),
+		latePerDay:      inv.flags.Float64("late-per-day", 0, "late fee for every day after the grace days"),
+		lateMax:         inv.flags.Float64("late-max", 0, "most late fee charged on one month's rent (default: no limit)"),
+	}
+}
+
+// rules gives the rules the flags set, and whether any were given. Any -escalate- flag sets an escalation
+// and any -late- flag a late fee.
+func (f rentRuleFlags) rules(inv *invocation) (entities.RentRules, bool) {
+	var rules entities.RentRules
+	inv.flags.Visit(func(fl *flag.Flag) {
+		switch {
+		case strings.HasPrefix(fl.Name, "escalate-") && rules.Escalation == nil:
+			rules.Escalation = &entities.Escalation{Percent: *f.escalatePercent, Amount: *f.escalateAmount, EveryMonths: *f.escalateEvery}
+		case strings.HasPrefix(fl.Name, "late-") && rules.LateFee == nil:
+			rules.LateFee = &entities.LateFee{GraceDays: *f.lateGrace, Flat: *f.lateFlat, PerDay: *f.latePerDay, Max: *f.lateMax}
+		}
+	})
+	return rules, rules.Escalation != nil || rules.LateFee != nil
+}
+
+// scheduleResult lists the rent due in the months ahead.
+func scheduleResult(schedule entities.Schedule) result {
+	rows := make([][]string, 0, len(schedule.Dues))
+	for _, due := range schedule.Dues {
+		lateFrom, lateFee := "", ""
+		if due.LateFrom != nil {
+			lateFrom, lateFee = due.LateFrom.Format(dateLayout), formatMoney(due.LateFee)
+		}
+		rows = append(rows, []string{
+			strconv.Itoa(due.Period),
+			due.DueDate.Format(dateLayout),
+			formatMoney(due.Amount),
+			formatMoney(due.Increase),
+			lateFrom,
+			lateFee,
+		})
+	}
+	return result{
+		noun:   "upcoming rent dues",
+		value:  schedule,
+		header: []string{"Month", "Due Date", "Rent", "Increase", "Late From", "Late Fee"},
+		rows:   rows,
+	}
+}
+
+// ledgerSchedule shows the rent due in the months ahead under the rules of a lease the user is a party to,
+// or as a dry run under the rules given by the flags. Nothing is saved.
+func (c *CLI) ledgerSchedule(inv *invocation) error {
+	months := inv.flags.Int("months", services.DefaultScheduleMonths, "months to look ahead")
+	ruleFlags := addRentRuleFlags(inv)
+	if err := inv.parse(1, 1); err != nil {
+		return err
+	}
+	ids, err := parseObjectIDs(inv)
+	if err != nil {
+		return err
+	}
+	var dryRun *entities.RentRules
+	if rules, given := ruleFlags.rules(inv); given {
+		dryRun = &rules
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	schedule, err := c.ledgerService.Schedule(c.ctx, session, ids[0], dryRun, *months)
+	if err != nil {
+		return err
+	}
+	return c.write(inv, scheduleResult(schedule))
+}
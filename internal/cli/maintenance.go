@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/domain/entities"
+)
+
+// timeLayout formats the times of maintenance tickets and their comments.
+const timeLayout = "2006-01-02 15:04"
+
+func ticketsResult(tickets []entities.MaintenanceTicket) result {
+	if tickets == nil {
+		tickets = []entities.MaintenanceTicket{}
+	}
+	rows := make([][]string, 0, len(tickets))
+	for _, t := range tickets {
+		rows = append(rows, []string{
+			t.ID.Hex(),
+			t.LeaseID.Hex(),
+			t.TenantName,
+			t.LandlordName,
+			string(t.Category),
+			string(t.Priority),
+			ticketStatus(t),
+			t.CreatedAt.Format(timeLayout),
+			t.DueBy.Format(timeLayout),
+			strconv.Itoa(len(t.Comments)),
+		})
+	}
+	return result{
+		noun:   "maintenance tickets",
+		value:  tickets,
+		header: []string{"ID", "Lease", "Tenant", "Landlord", "Category", "Priority", "Status", "Reported", "Due By", "Comments"},
+		rows:   rows,
+	}
+}
+
+// ticketResult shows a ticket as its thread: the issue as reported, the comments and where it stands now.
+func ticketResult(ticket entities.MaintenanceTicket) result {
+	rows := [][]string{
+		{ticket.CreatedAt.Format(timeLayout), ticket.TenantName, fmt.Sprintf("Reported a %s %s issue: %s", ticket.Priority, ticket.Category, ticket.Description)},
+	}
+	for _, comment := range ticket.Comments {
+		rows = append(rows, []string{comment.PostedAt.Format(timeLayout), comment.Author, comment.Text})
+	}
+	rows = append(rows, []string{ticket.UpdatedAt.Format(timeLayout), "", fmt.Sprintf("Status: %s, due by %s", ticketStatus(ticket), ticket.DueBy.Format(timeLayout))})
+	return result{
+		noun:   "maintenance ticket",
+		value:  ticket,
+		header: []string{"When", "Who", "What"},
+		rows:   rows,
+	}
+}
+
+// ticketStatus gives the status of a ticket, marked when it is overdue.
+func ticketStatus(ticket entities.MaintenanceTicket) string {
+	if ticket.Overdue {
+		return string(ticket.Status) + " (OVERDUE)"
+	}
+	return string(ticket.Status)
+}
+
+// maintenanceReport reports an issue with the property of a lease of the user.
+func (c *CLI) maintenanceReport(inv *invocation) error {
+	category := inv.flags.String("category", "", fmt.Sprintf("kind of issue, one of %v", entities.MaintenanceCategories()))
+	priority := inv.flags.String("priority", string(entities.PriorityMedium), fmt.Sprintf("how urgent it is, one of %v", entities.MaintenancePriorities()))
+	description := inv.flags.String("description", "", "what is wrong")
+	if err := inv.parse(1, 1); err != nil {
+		return err
+	}
+	ids, err := parseObjectIDs(inv)
+	if err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	ticket, err := c.maintenanceService.ReportIssue(c.ctx, session, ids[0], entities.MaintenanceCategory(*category), entities.MaintenancePriority(*priority), *description)
+	if err != nil {
+		return err
+	}
+	return c.write(inv, ticketResult(ticket))
+}
+
+// maintenanceList lists the tickets the user reported, or with -landlord the queue of their properties.
+func (c *CLI) maintenanceList(inv *invocation) error {
+	landlord := inv.flags.Bool("landlord", false, "list the open tickets on the properties of the user, overdue first")
+	if err := inv.parse(0, 0); err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	var tickets []entities.MaintenanceTicket
+	if *landlord {
+		tickets, err = c.maintenanceService.MaintenanceQueue(c.ctx, session)
+	} else {
+		tickets, err = c.maintenanceService.TicketsForTenant(c.ctx, session)
+	}
+	if err != nil {
+		return err
+	}
+	return c.write(inv, ticketsResult(tickets))
+}
+
+// maintenanceShow shows a ticket the user is a party to, with its comments.
+func (c *CLI) maintenanceShow(inv *invocation) error {
+	return c.changeTicket(inv, func(session *entities.Session, id primitive.ObjectID) (entities.MaintenanceTicket, error) {
+		return c.maintenanceService.Ticket(c.ctx, session, id)
+	})
+}
+
+// maintenanceStatus moves a ticket the user is a party to on to -status.
+func (c *CLI) maintenanceStatus(inv *invocation) error {
+	status := inv.flags.String("status", "", fmt.Sprintf("new status, one of %v", entities.TicketStatuses()))
+	note := inv.flags.String("note", "", "a comment to add with the change")
+	return c.changeTicket(inv, func(session *entities.Session, id primitive.ObjectID) (entities.MaintenanceTicket, error) {
+		return c.maintenanceService.UpdateTicketStatus(c.ctx, session, id, entities.TicketStatus(*status), *note)
+	})
+}
+
+// maintenanceComment comments on a ticket the user is a party to.
+func (c *CLI) maintenanceComment(inv *invocation) error {
+	text := inv.flags.String("text", "", "the comment")
+	return c.changeTicket(inv, func(session *entities.Session, id primitive.ObjectID) (entities.MaintenanceTicket, error) {
+		return c.maintenanceService.AddComment(c.ctx, session, id, *text)
+	})
+}
+
+// changeTicket runs change on the ticket given as the argument and shows the ticket it returns.
+func (c *CLI) changeTicket(inv *invocation, change func(session *entities.Session, id primitive.ObjectID) (entities.MaintenanceTicket, error)) error {
+	if err := inv.parse(1, 1); err != nil {
+		return err
+	}
+	ids, err := parseObjectIDs(inv)
+	if err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	ticket, err := change(session, ids[0])
+	if err != nil {
+		return err
+	}
+	return c.write(inv, ticketResult(ticket))
+}
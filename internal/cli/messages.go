@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/domain/entities"
+)
+
+// threadsResult lists threads with how many of their messages the user has not read.
+func threadsResult(threads []entities.Thread, username string) result {
+	if threads == nil {
+		threads = []entities.Thread{}
+	}
+	rows := make([][]string, 0, len(threads))
+	for _, t := range threads {
+		rows = append(rows, []string{
+			t.ID.Hex(),
+			t.PropertyID.Hex(),
+			t.TenantName,
+			t.LandlordName,
+			strconv.Itoa(len(t.Messages)),
+			strconv.Itoa(t.UnreadFor(username)),
+			t.LastMessageAt.Format(timeLayout),
+		})
+	}
+	return result{
+		noun:   "message threads",
+		value:  threads,
+		header: []string{"ID", "Property", "Tenant", "Landlord", "Messages", "Unread", "Last Message"},
+		rows:   rows,
+	}
+}
+
+// threadResult shows the messages of a thread, oldest first.
+func threadResult(thread entities.Thread) result {
+	rows := make([][]string, 0, len(thread.Messages))
+	for _, message := range thread.Messages {
+		rows = append(rows, []string{message.SentAt.Format(timeLayout), message.Sender, message.Text})
+	}
+	return result{
+		noun:   "messages",
+		value:  thread,
+		header: []string{"When", "Who", "What"},
+		rows:   rows,
+	}
+}
+
+// messageList lists the threads of the user, latest message first.
+func (c *CLI) messageList(inv *invocation) error {
+	if err := inv.parse(0, 0); err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	threads, err := c.messageService.Threads(c.ctx, session)
+	if err != nil {
+		return err
+	}
+	return c.write(inv, threadsResult(threads, session.Username()))
+}
+
+// messageUnread counts the messages the user has not read, as tenant and as landlord.
+func (c *CLI) messageUnread(inv *invocation) error {
+	if err := inv.parse(0, 0); err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	unread, err := c.messageService.UnreadMessages(c.ctx, session)
+	if err != nil {
+		return err
+	}
+	return c.write(inv, result{
+		noun:   "unread messages",
+		value:  unread,
+		header: []string{"As", "Unread"},
+		rows: [][]string{
+			{"tenant", strconv.Itoa(unread.AsTenant)},
+			{"landlord", strconv.Itoa(unread.AsLandlord)},
+		},
+	})
+}
+
+// messageShow shows a thread of the user and marks it read.
+func (c *CLI) messageShow(inv *invocation) error {
+	return c.changeThread(inv, func(session *entities.Session, id primitive.ObjectID) (entities.Thread, error) {
+		return c.messageService.ReadThread(c.ctx, session, id)
+	})
+}
+
+// messageSend writes in a thread of the user.
+func (c *CLI) messageSend(inv *invocation) error {
+	text := inv.flags.String("text", "", "the message")
+	return c.changeThread(inv, func(session *entities.Session, id primitive.ObjectID) (entities.Thread, error) {
+		return c.messageService.SendMessage(c.ctx, session, id, *text)
+	})
+}
+
+// messageProperty writes to the landlord of a property, in the thread the user has about it.
+func (c *CLI) messageProperty(inv *invocation) error {
+	text := inv.flags.String("text", "", "the message")
+	return c.changeThread(inv, func(session *entities.Session, id primitive.ObjectID) (entities.Thread, error) {
+		return c.messageService.ContactLandlord(c.ctx, session, id, *text)
+	})
+}
+
+// messageRequest writes to the other party of a rent request, in the thread about its property.
+func (c *CLI) messageRequest(inv *invocation) error {
+	text := inv.flags.String("text", "", "the message")
+	return c.changeThread(inv, func(session *entities.Session, id primitive.ObjectID) (entities.Thread, error) {
+		return c.messageService.MessageAboutRequest(c.ctx, session, id, *text)
+	})
+}
+
+// changeThread runs change on the ID given as the argument and shows the thread it returns.
+func (c *CLI) changeThread(inv *invocation, change func(session *entities.Session, id primitive.ObjectID) (entities.Thread, error)) error {
+	if err := inv.parse(1, 1); err != nil {
+		return err
+	}
+	ids, err := parseObjectIDs(inv)
+	if err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	thread, err := change(session, ids[0])
+	if err != nil {
+		return err
+	}
+	return c.write(inv, threadResult(thread))
+}
@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"strconv"
+
+	"rentease/internal/domain/entities"
+)
+
+// notificationsResult lists notifications, newest first.
+func notificationsResult(notifications []entities.Notification) result {
+	if notifications == nil {
+		notifications = []entities.Notification{}
+	}
+	rows := make([][]string, 0, len(notifications))
+	for _, n := range notifications {
+		property := ""
+		if !n.PropertyID.IsZero() {
+			property = n.PropertyID.Hex()
+		}
+		rows = append(rows, []string{
+			n.ID.Hex(),
+			n.CreatedAt.Format(timeLayout),
+			string(n.Kind),
+			n.Text,
+			property,
+			strconv.FormatBool(n.Read),
+		})
+	}
+	return result{
+		noun:   "notifications",
+		value:  notifications,
+		header: []string{"ID", "When", "Kind", "Text", "Property", "Read"},
+		rows:   rows,
+	}
+}
+
+// notificationList lists the notifications of the user, or with -unread those not read yet.
+func (c *CLI) notificationList(inv *invocation) error {
+	unread := inv.flags.Bool("unread", false, "list only the notifications not read yet")
+	if err := inv.parse(0, 0); err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+	return c.writeNotifications(inv, session, *unread)
+}
+
+// notificationUnread counts the notifications the user has not read.
+func (c *CLI) notificationUnread(inv *invocation) error {
+	if err := inv.parse(0, 0); err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	unread, err := c.notificationService.UnreadNotifications(c.ctx, session)
+	if err != nil {
+		return err
+	}
+	return c.write(inv, result{
+		noun:   "unread notifications",
+		value:  unread,
+		header: []string{"Unread"},
+		rows:   [][]string{{strconv.Itoa(unread)}},
+	})
+}
+
+// notificationRead marks notifications of the user read, or with -all every one of them, and lists the inbox.
+func (c *CLI) notificationRead(inv *invocation) error {
+	all := inv.flags.Bool("all", false, "mark every notification read")
+	if err := inv.parse(0, -1); err != nil {
+		return err
+	}
+	ids, err := parseObjectIDs(inv)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 && !*all {
+		return inv.usageError("give the notifications to mark read, or -all")
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	if *all {
+		if _, err := c.notificationService.MarkAllNotificationsRead(c.ctx, session); err != nil {
+			return err
+		}
+	}
+	for _, id := range ids {
+		if err := c.notificationService.MarkNotificationRead(c.ctx, session, id); err != nil {
+			return err
+		}
+	}
+	return c.writeNotifications(inv, session, false)
+}
+
+// notificationDelete removes notifications of the user and lists the inbox.
+func (c *CLI) notificationDelete(inv *invocation) error {
+	if err := inv.parse(1, -1); err != nil {
+		return err
+	}
+	ids, err := parseObjectIDs(inv)
+	if err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := c.notificationService.DeleteNotification(c.ctx, session, id); err != nil {
+			return err
+		}
+	}
+	return c.writeNotifications(inv, session, false)
+}
+
+// notificationClear removes the notifications the user has read and lists the inbox.
+func (c *CLI) notificationClear(inv *invocation) error {
+	if err := inv.parse(0, 0); err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.notificationService.ClearReadNotifications(c.ctx, session); err != nil {
+		return err
+	}
+	return c.writeNotifications(inv, session, false)
+}
+
+// writeNotifications lists the notifications of the user, only the unread ones if asked.
+func (c *CLI) writeNotifications(inv *invocation, session *entities.Session, unreadOnly bool) error {
+	notifications, err := c.notificationService.Notifications(c.ctx, session)
+	if err != nil {
+		return err
+	}
+	if unreadOnly {
+		unread := []entities.Notification{}
+		for _, n := range notifications {
+			if !n.Read {
+				unread = append(unread, n)
+			}
+		}
+		notifications = unread
+	}
+	return c.write(inv, notificationsResult(notifications))
+}
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// Output formats of the results.
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+// invocation holds the flags and arguments of a command being run.
+type invocation struct {
+	cmd        *command
+	flags      *flag.FlagSet
+	args       []string // Everything after the subcommand
+	positional []string // The arguments left after parse
+
+	user    string
+	format  string
+	jsonOut bool
+	csvOut  bool
+}
+
+// newInvocation prepares the flags every command accepts. Commands add their own before calling parse.
+func newInvocation(cmd *command, args []string, stderr io.Writer) *invocation {
+	inv := &invocation{cmd: cmd, args: args}
+	fs := flag.NewFlagSet(cmd.group+" "+cmd.name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&inv.user, "user", "", "username to log in with (env "+EnvUsername+")")
+	fs.StringVar(&inv.format, "output", formatTable, "output format: table, json or csv")
+	fs.BoolVar(&inv.jsonOut, "json", false, "same as -output=json")
+	fs.BoolVar(&inv.csvOut, "csv", false, "same as -output=csv")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: rentease %s %s [flags] %s\n\n%s\n\nFlags:\n", cmd.group, cmd.name, cmd.args, cmd.summary)
+		fs.PrintDefaults()
+	}
+	inv.flags = fs
+	return inv
+}
+
+// parse parses the flags and checks that between min and max positional arguments are given; max < 0 allows any number.
+// Flags may also follow the arguments, as in `request accept <id> -json`, unless "--" ends the flags.
+func (inv *invocation) parse(min, max int) error {
+	args := inv.args
+	for {
+		if err := inv.flags.Parse(args); err != nil {
+			if err == flag.ErrHelp {
+				return err
+			}
+			return errUsageReported // The flag package has reported the problem
+		}
+		rest := inv.flags.Args()
+		if consumed := args[:len(args)-len(rest)]; len(consumed) > 0 && consumed[len(consumed)-1] == "--" {
+			inv.positional = append(inv.positional, rest...)
+			break
+		}
+		if len(rest) == 0 {
+			break
+		}
+		inv.positional = append(inv.positional, rest[0])
+		args = rest[1:]
+	}
+
+	switch {
+	case inv.jsonOut && inv.csvOut:
+		return inv.usageError("-json and -csv cannot be combined")
+	case inv.jsonOut:
+		inv.format = formatJSON
+	case inv.csvOut:
+		inv.format = formatCSV
+	}
+	if inv.format != formatTable && inv.format != formatJSON && inv.format != formatCSV {
+		return inv.usageError(fmt.Sprintf("unknown output format %q", inv.format))
+	}
+
+	if n := len(inv.positional); n < min || (max >= 0 && n > max) {
+		if inv.cmd.args == "" {
+			return inv.usageError("no arguments expected")
+		}
+		return inv.usageError("expected arguments " + inv.cmd.args)
+	}
+	return nil
+}
+
+// usageError reports a problem with the flags or arguments along with the usage of the command.
+func (inv *invocation) usageError(message string) error {
+	fmt.Fprintln(inv.flags.Output(), "Error:", message)
+	inv.flags.Usage()
+	return errUsageReported
+}
+
+// result is the output of a command: the value itself for JSON, or its rows for a table or CSV.
+type result struct {
+	noun   string // What the rows are, e.g. "properties"
+	value  interface{}
+	header []string
+	rows   [][]string
+}
+
+// write prints the result in the format selected by the flags.
+func (c *CLI) write(inv *invocation, r result) error {
+	switch inv.format {
+	case formatJSON:
+		encoder := json.NewEncoder(c.stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r.value)
+	case formatCSV:
+		writer := csv.NewWriter(c.stdout)
+		if err := writer.Write(r.header); err != nil {
+			return err
+		}
+		if err := writer.WriteAll(r.rows); err != nil {
+			return err
+		}
+		return writer.Error()
+	default:
+		if len(r.rows) == 0 {
+			_, err := fmt.Fprintf(c.stdout, "No %s found.\n", r.noun)
+			return err
+		}
+		table := tablewriter.NewWriter(c.stdout)
+		table.SetHeader(r.header)
+		table.SetAutoWrapText(false)
+		table.AppendBulk(r.rows)
+		table.Render()
+		return nil
+	}
+}
@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/app/services"
+	"rentease/internal/domain/entities"
+	"rentease/pkg/utils"
+)
+
+func propertiesResult(properties []entities.Property) result {
+	if properties == nil {
+		properties = []entities.Property{}
+	}
+	rows := make([][]string, 0, len(properties))
+	for _, p := range properties {
+		rows = append(rows, []string{
+			p.ID.Hex(),
+			p.Title,
+			utils.PropertyTypeToString(p.PropertyType),
+			utils.FormatAddress(p.Address),
+			strconv.FormatFloat(p.RentAmount, 'f', 2, 64),
+			p.LandlordUsername,
+			strconv.FormatBool(p.IsApprovedByAdmin),
+			strconv.FormatBool(p.IsRented),
+		})
+	}
+	return result{
+		noun:   "properties",
+		value:  properties,
+		header: []string{"ID", "Title", "Type", "Address", "Rent", "Landlord", "Approved", "Rented"},
+		rows:   rows,
+	}
+}
+
+// parseObjectIDs parses the positional arguments as IDs, so that nothing is changed when one is invalid.
+func parseObjectIDs(inv *invocation) ([]primitive.ObjectID, error) {
+	ids := make([]primitive.ObjectID, 0, len(inv.positional))
+	for _, arg := range inv.positional {
+		id, err := primitive.ObjectIDFromHex(arg)
+		if err != nil {
+			return nil, inv.usageError(fmt.Sprintf("invalid id %q", arg))
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// findProperty loads the property, failing with ErrPropertyNotFound if it does not exist.
+func (c *CLI) findProperty(id primitive.ObjectID) (entities.Property, error) {
+	property, err := c.propertyService.FindByID(c.ctx, id)
+	if err != nil {
+		return entities.Property{}, err
+	}
+	if property.ID.IsZero() {
+		return entities.Property{}, fmt.Errorf("%w: %s", services.ErrPropertyNotFound, id.Hex())
+	}
+	return property, nil
+}
+
+// propertyList lists the approved properties, all properties of -landlord, or with -pending those waiting for approval.
+func (c *CLI) propertyList(inv *invocation) error {
+	landlord := inv.flags.String("landlord", "", "list all properties of this landlord")
+	pending := inv.flags.Bool("pending", false, "list the properties waiting for approval (needs a reviewer login)")
+	if err := inv.parse(0, 0); err != nil {
+		return err
+	}
+	if *pending {
+		if *landlord != "" {
+			return inv.usageError("-landlord and -pending cannot be combined")
+		}
+		return c.writePendingProperties(inv)
+	}
+
+	properties, err := c.propertyService.GetAllListedProperties(c.ctx, *landlord)
+	if err != nil {
+		return err
+	}
+	if *landlord == "" {
+		approved := []entities.Property{}
+		for _, property := range properties {
+			if property.IsApprovedByAdmin {
+				approved = append(approved, property)
+			}
+		}
+		properties = approved
+	}
+	return c.write(inv, propertiesResult(properties))
+}
+
+func (c *CLI) propertyShow(inv *invocation) error {
+	if err := inv.parse(1, 1); err != nil {
+		return err
+	}
+	ids, err := parseObjectIDs(inv)
+	if err != nil {
+		return err
+	}
+	property, err := c.findProperty(ids[0])
+	if err != nil {
+		return err
+	}
+
+	r := propertiesResult([]entities.Property{property})
+	r.value = property
+	return c.write(inv, r)
+}
+
+// propertyDelete deletes the properties in order, stopping at the first that cannot be deleted.
+func (c *CLI) propertyDelete(inv *invocation) error {
+	if err := inv.parse(1, -1); err != nil {
+		return err
+	}
+	ids, err := parseObjectIDs(inv)
+	if err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	deleted := []string{}
+	for _, id := range ids {
+		if _, err := c.findProperty(id); err != nil {
+			return err
+		}
+		if err := c.propertyService.DeleteListedProperty(c.ctx, session, id); err != nil {
+			return err
+		}
+		deleted = append(deleted, id.Hex())
+	}
+	return c.write(inv, deletedResult("Deleted property", deleted))
+}
+
+// deletedResult lists what a command deleted.
+func deletedResult(header string, deleted []string) result {
+	rows := make([][]string, 0, len(deleted))
+	for _, name := range deleted {
+		rows = append(rows, []string{name})
+	}
+	return result{value: deleted, header: []string{header}, rows: rows}
+}
@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/app/services"
+	"rentease/internal/domain/entities"
+)
+
+func requestsResult(requests []entities.Request) result {
+	if requests == nil {
+		requests = []entities.Request{}
+	}
+	rows := make([][]string, 0, len(requests))
+	for _, r := range requests {
+		rows = append(rows, []string{
+			r.ID.Hex(),
+			r.PropertyID.Hex(),
+			r.TenantName,
+			r.LandlordName,
+			string(r.RequestStatus),
+			r.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return result{
+		noun:   "rent requests",
+		value:  requests,
+		header: []string{"ID", "Property", "Tenant", "Landlord", "Status", "Created At"},
+		rows:   rows,
+	}
+}
+
+// requestList lists the rent requests sent by the user, or with -received those for their properties.
+func (c *CLI) requestList(inv *invocation) error {
+	received := inv.flags.Bool("received", false, "list the requests received for the properties of the user")
+	if err := inv.parse(0, 0); err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	var requests []entities.Request
+	if *received {
+		requests, err = c.requestService.GetRentRequestsInfoForLandlord(c.ctx, session)
+	} else {
+		requests, err = c.requestService.GetRentRequestsInfoForTenant(c.ctx, session)
+	}
+	if err != nil {
+		return err
+	}
+	return c.write(inv, requestsResult(requests))
+}
+
+func (c *CLI) requestAccept(inv *invocation) error {
+	return c.answerRequest(inv, entities.RequestAccepted)
+}
+
+func (c *CLI) requestReject(inv *invocation) error {
+	return c.answerRequest(inv, entities.RequestRejected)
+}
+
+// answerRequest sets the status of a request received by the user, as the landlord dashboard does.
+func (c *CLI) answerRequest(inv *invocation, status entities.RequestStatus) error {
+	if err := inv.parse(1, 1); err != nil {
+		return err
+	}
+	ids, err := parseObjectIDs(inv)
+	if err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	request, err := c.receivedRequest(session, ids[0])
+	if err != nil {
+		return err
+	}
+	if err := c.requestService.UpdateRequestStatus(c.ctx, session, request.ID, status); err != nil {
+		return err
+	}
+
+	if request, err = c.receivedRequest(session, request.ID); err != nil {
+		return err
+	}
+	return c.writeRequest(inv, request)
+}
+
+// requestWithdraw withdraws a pending request sent by the user.
+func (c *CLI) requestWithdraw(inv *invocation) error {
+	if err := inv.parse(1, 1); err != nil {
+		return err
+	}
+	ids, err := parseObjectIDs(inv)
+	if err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	if err := c.requestService.WithdrawRequest(c.ctx, session, ids[0]); err != nil {
+		return err
+	}
+	sent, err := c.requestService.GetRentRequestsInfoForTenant(c.ctx, session)
+	if err != nil {
+		return err
+	}
+	for _, request := range sent {
+		if request.ID == ids[0] {
+			return c.writeRequest(inv, request)
+		}
+	}
+	return fmt.Errorf("%w: %s", services.ErrRequestNotFound, ids[0].Hex())
+}
+
+// requestExpire expires the requests that have been pending for longer than -older-than.
+func (c *CLI) requestExpire(inv *invocation) error {
+	olderThan := inv.flags.Duration("older-than", 30*24*time.Hour, "expire requests pending for longer than this")
+	if err := inv.parse(0, 0); err != nil {
+		return err
+	}
+	if *olderThan <= 0 {
+		return inv.usageError("-older-than must be positive")
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	expired, err := c.requestService.ExpireRequests(c.ctx, session, time.Now().Add(-*olderThan))
+	if err != nil {
+		return err
+	}
+	return c.write(inv, result{
+		noun:   "expired requests",
+		value:  map[string]int{"expired": expired},
+		header: []string{"Expired Requests"},
+		rows:   [][]string{{strconv.Itoa(expired)}},
+	})
+}
+
+// receivedRequest finds a request for one of the properties of the user.
+func (c *CLI) receivedRequest(session *entities.Session, id primitive.ObjectID) (entities.Request, error) {
+	requests, err := c.requestService.GetRentRequestsInfoForLandlord(c.ctx, session)
+	if err != nil {
+		return entities.Request{}, err
+	}
+	for _, request := range requests {
+		if request.ID == id {
+			return request, nil
+		}
+	}
+	return entities.Request{}, fmt.Errorf("%w: %s", services.ErrRequestNotFound, id.Hex())
+}
+
+// writeRequest prints one request, with its status history in the JSON output.
+func (c *CLI) writeRequest(inv *invocation, request entities.Request) error {
+	r := requestsResult([]entities.Request{request})
+	r.value = request
+	return c.write(inv, r)
+}
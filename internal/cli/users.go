@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"strconv"
+
+	"rentease/internal/domain/entities"
+)
+
+func usersResult(users []entities.User) result {
+	if users == nil {
+		users = []entities.User{}
+	}
+	rows := make([][]string, 0, len(users))
+	for _, u := range users {
+		rows = append(rows, []string{u.Username, u.Name, strconv.Itoa(u.Age), u.Email, u.PhoneNumber, u.Address, u.Role})
+	}
+	return result{
+		noun:   "users",
+		value:  users,
+		header: []string{"Username", "Name", "Age", "Email", "Phone Number", "Address", "Role"},
+		rows:   rows,
+	}
+}
+
+func (c *CLI) userList(inv *invocation) error {
+	if err := inv.parse(0, 0); err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+	users, err := c.userService.GetAllUsers(c.ctx, session)
+	if err != nil {
+		return err
+	}
+	return c.write(inv, usersResult(users))
+}
+
+// userDelete deletes the users with their listed properties and logins, as the admin API does.
+// It stops at the first user that cannot be deleted.
+func (c *CLI) userDelete(inv *invocation) error {
+	if err := inv.parse(1, -1); err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	deleted := []string{}
+	for _, username := range inv.positional {
+		if err := c.userService.DeleteUser(c.ctx, session, username); err != nil {
+			return err
+		}
+		deleted = append(deleted, username)
+	}
+	return c.write(inv, deletedResult("Deleted user", deleted))
+}
+
+func (c *CLI) userSetRole(inv *invocation) error {
+	if err := inv.parse(2, 2); err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	username, role := inv.positional[0], inv.positional[1]
+	if err := c.userService.SetRole(c.ctx, session, username, role); err != nil {
+		return err
+	}
+	user, err := c.userService.FindByUsername(c.ctx, username)
+	if err != nil {
+		return err
+	}
+	r := usersResult([]entities.User{user})
+	r.value = user
+	return c.write(inv, r)
+}
@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"rentease/internal/domain/entities"
+)
+
+// startsFlag collects the start times given with repeated -at flags, in local time.
+type startsFlag []time.Time
+
+func (s *startsFlag) String() string {
+	items := make([]string, 0, len(*s))
+	for _, start := range *s {
+		items = append(items, start.Format(timeLayout))
+	}
+	return strings.Join(items, ", ")
+}
+
+func (s *startsFlag) Set(value string) error {
+	start, err := time.ParseInLocation(timeLayout, strings.TrimSpace(value), time.Local)
+	if err != nil {
+		return fmt.Errorf("expected a time such as %s, got %q", timeLayout, value)
+	}
+	*s = append(*s, start)
+	return nil
+}
+
+func slotsResult(slots []entities.VisitSlot) result {
+	if slots == nil {
+		slots = []entities.VisitSlot{}
+	}
+	rows := make([][]string, 0, len(slots))
+	for _, s := range slots {
+		rows = append(rows, []string{
+			s.ID.Hex(),
+			s.PropertyID.Hex(),
+			s.Start.Local().Format(timeLayout),
+			s.End.Local().Format(timeLayout),
+			string(s.Status),
+			s.TenantName,
+			s.LandlordName,
+		})
+	}
+	return result{
+		noun:   "visit slots",
+		value:  slots,
+		header: []string{"ID", "Property", "Start", "End", "Status", "Tenant", "Landlord"},
+		rows:   rows,
+	}
+}
+
+// slotResult shows one slot as a row of slotsResult.
+func slotResult(slot entities.VisitSlot) result {
+	r := slotsResult([]entities.VisitSlot{slot})
+	r.noun = "visit slot"
+	r.value = slot
+	return r
+}
+
+// visitPublish offers visit slots for a property of the user.
+func (c *CLI) visitPublish(inv *invocation) error {
+	var starts startsFlag
+	inv.flags.Var(&starts, "at", "start of a slot, e.g. 2024-05-01 10:00; repeat for each")
+	length := inv.flags.Duration("length", 0, "how long each slot lasts, e.g. 45m (default 30m)")
+	if err := inv.parse(1, 1); err != nil {
+		return err
+	}
+	ids, err := parseObjectIDs(inv)
+	if err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	slots, err := c.visitService.PublishSlots(c.ctx, session, ids[0], starts, *length)
+	if err != nil {
+		return err
+	}
+	return c.write(inv, slotsResult(slots))
+}
+
+// visitSlots lists the slots of a property: all of them for its landlord, the bookable ones for others.
+func (c *CLI) visitSlots(inv *invocation) error {
+	if err := inv.parse(1, 1); err != nil {
+		return err
+	}
+	ids, err := parseObjectIDs(inv)
+	if err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	slots, err := c.visitService.VisitSlots(c.ctx, session, ids[0])
+	if err != nil {
+		return err
+	}
+	return c.write(inv, slotsResult(slots))
+}
+
+// visitList lists the upcoming visits the user booked, or with -landlord those to their properties.
+func (c *CLI) visitList(inv *invocation) error {
+	landlord := inv.flags.Bool("landlord", false, "list the upcoming visits to the properties of the user")
+	if err := inv.parse(0, 0); err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	var slots []entities.VisitSlot
+	if *landlord {
+		slots, err = c.visitService.UpcomingVisitsForLandlord(c.ctx, session)
+	} else {
+		slots, err = c.visitService.UpcomingVisitsForTenant(c.ctx, session)
+	}
+	if err != nil {
+		return err
+	}
+	return c.write(inv, slotsResult(slots))
+}
+
+// visitBook books an open slot for the user.
+func (c *CLI) visitBook(inv *invocation) error {
+	return c.changeSlot(inv, 1, func(session *entities.Session, ids []primitive.ObjectID) (entities.VisitSlot, error) {
+		return c.visitService.BookVisit(c.ctx, session, ids[0])
+	})
+}
+
+// visitReschedule moves a booked visit to another slot of the same property.
+func (c *CLI) visitReschedule(inv *invocation) error {
+	return c.changeSlot(inv, 2, func(session *entities.Session, ids []primitive.ObjectID) (entities.VisitSlot, error) {
+		return c.visitService.RescheduleVisit(c.ctx, session, ids[0], ids[1])
+	})
+}
+
+// visitCancel calls off a visit of the user, or withdraws a slot of their property.
+func (c *CLI) visitCancel(inv *invocation) error {
+	return c.changeSlot(inv, 1, func(session *entities.Session, ids []primitive.ObjectID) (entities.VisitSlot, error) {
+		return c.visitService.CancelVisit(c.ctx, session, ids[0])
+	})
+}
+
+// changeSlot runs change on the slots given as the arguments and shows the slot it returns.
+func (c *CLI) changeSlot(inv *invocation, args int, change func(session *entities.Session, ids []primitive.ObjectID) (entities.VisitSlot, error)) error {
+	if err := inv.parse(args, args); err != nil {
+		return err
+	}
+	ids, err := parseObjectIDs(inv)
+	if err != nil {
+		return err
+	}
+	session, err := c.login(inv)
+	if err != nil {
+		return err
+	}
+
+	slot, err := change(session, ids)
+	if err != nil {
+		return err
+	}
+	return c.write(inv, slotResult(slot))
+}
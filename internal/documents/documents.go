@@ -0,0 +1,215 @@
+// Package documents lays out rent receipts and lease agreements as PDF from text templates.
+// The built-in templates can be replaced by files of the same name in a template directory.
+package documents
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"rentease/internal/domain/entities"
+	"rentease/pkg/pdf"
+)
+
+// Names of the templates, which are also the names of the files that replace them.
+const (
+	ReceiptTemplate = "receipt.tmpl"
+	LeaseTemplate   = "lease.tmpl"
+)
+
+//go:embed templates/*.tmpl
+var builtinTemplates embed.FS
+
+// Renderer draws up documents from the built-in templates or the ones that replace them.
+type Renderer struct {
+	receipt *template.Template
+	lease   *template.Template
+}
+
+// NewRenderer loads the templates. A template found in dir replaces the built-in one; an empty dir uses
+// only the built-in templates. A template that does not parse is an error, so mistakes show at startup.
+func NewRenderer(dir string) (*Renderer, error) {
+	receipt, err := loadTemplate(dir, ReceiptTemplate)
+	if err != nil {
+		return nil, err
+	}
+	lease, err := loadTemplate(dir, LeaseTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return &Renderer{receipt: receipt, lease: lease}, nil
+}
+
+func loadTemplate(dir, name string) (*template.Template, error) {
+	source, err := builtinTemplates.ReadFile("templates/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if dir != "" {
+		custom, err := os.ReadFile(filepath.Join(dir, name))
+		switch {
+		case err == nil:
+			source = custom
+		case !errors.Is(err, fs.ErrNotExist):
+			return nil, fmt.Errorf("failed to read template %s: %w", name, err)
+		}
+	}
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(string(source))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// ExportTemplates writes the built-in templates into dir as a starting point for customising them.
+// Existing files are left alone and reported as an error.
+func ExportTemplates(dir string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	var written []string
+	for _, name := range []string{ReceiptTemplate, LeaseTemplate} {
+		source, err := builtinTemplates.ReadFile("templates/" + name)
+		if err != nil {
+			return written, err
+		}
+		path := filepath.Join(dir, name)
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return written, fmt.Errorf("failed to export template %s: %w", name, err)
+		}
+		_, err = file.Write(source)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return written, fmt.Errorf("failed to export template %s: %w", name, err)
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
+
+// RentReceipt draws up the receipt of a rent payment.
+func (r *Renderer) RentReceipt(data entities.ReceiptData) (entities.Document, error) {
+	content, err := render(r.receipt, "Rent Receipt "+data.Number, data)
+	if err != nil {
+		return entities.Document{}, err
+	}
+	return entities.Document{FileName: "receipt-" + data.Number + ".pdf", ContentType: "application/pdf", Content: content}, nil
+}
+
+// LeaseAgreement draws up the printable copy of a lease agreement.
+func (r *Renderer) LeaseAgreement(data entities.LeaseDocumentData) (entities.Document, error) {
+	content, err := render(r.lease, "Lease Agreement "+data.Lease.ID.Hex(), data)
+	if err != nil {
+		return entities.Document{}, err
+	}
+	return entities.Document{FileName: "lease-" + data.Lease.ID.Hex() + ".pdf", ContentType: "application/pdf", Content: content}, nil
+}
+
+// render runs the template and lays out its output. Lines starting with "# " become the title,
+// "## " a heading and "~ " fine print; "---" draws a line and an empty line leaves some space.
+func render(tmpl *template.Template, title string, data interface{}) ([]byte, error) {
+	var text bytes.Buffer
+	if err := tmpl.Execute(&text, data); err != nil {
+		return nil, fmt.Errorf("failed to fill in template %s: %w", tmpl.Name(), err)
+	}
+
+	doc := pdf.New(title)
+	for _, line := range strings.Split(strings.TrimSpace(text.String()), "\n") {
+		line = strings.TrimRight(line, " \r")
+		switch {
+		case line == "":
+			doc.Space(8)
+		case line == "---":
+			doc.Rule()
+		case strings.HasPrefix(line, "# "):
+			doc.Text(pdf.Title, line[2:])
+		case strings.HasPrefix(line, "## "):
+			doc.Space(4)
+			doc.Text(pdf.Heading, line[3:])
+		case strings.HasPrefix(line, "~ "):
+			doc.Text(pdf.Small, line[2:])
+		default:
+			doc.Text(pdf.Body, line)
+		}
+	}
+	return doc.Bytes(), nil
+}
+
+var templateFuncs = template.FuncMap{
+	"date":    func(t time.Time) string { return t.Format("02 Jan 2006") },
+	"month":   func(t time.Time) string { return t.Format("January 2006") },
+	"money":   FormatRupees,
+	"words":   AmountInWords,
+	"address": FormatAddress,
+	"party":   formatParty,
+	"mode":    formatPaymentMode,
+}
+
+// FormatRupees formats an amount with two decimals and the Indian digit grouping, e.g. 1,20,000.50.
+func FormatRupees(amount float64) string {
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	formatted := strconv.FormatFloat(amount, 'f', 2, 64)
+	whole, fraction := formatted[:len(formatted)-3], formatted[len(formatted)-3:]
+	if len(whole) > 3 {
+		// The last three digits form the thousands, the others go in pairs
+		head, tail := whole[:len(whole)-3], whole[len(whole)-3:]
+		var groups []string
+		for len(head) > 2 {
+			groups = append([]string{head[len(head)-2:]}, groups...)
+			head = head[:len(head)-2]
+		}
+		groups = append([]string{head}, groups...)
+		whole = strings.Join(groups, ",") + "," + tail
+	}
+	return sign + whole + fraction
+}
+
+// FormatAddress writes the address on one line, leaving out the parts that are not known.
+func FormatAddress(address entities.Address) string {
+	var parts []string
+	for _, part := range []string{address.Area, address.City, address.State} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	formatted := strings.Join(parts, ", ")
+	if address.Pincode != 0 {
+		formatted += " - " + strconv.Itoa(address.Pincode)
+	}
+	if formatted == "" {
+		return "Address not available"
+	}
+	return formatted
+}
+
+func formatParty(party entities.Party) string {
+	if party.Name == "" {
+		return party.Username
+	}
+	return party.Name + " (" + party.Username + ")"
+}
+
+func formatPaymentMode(mode entities.PaymentMode) string {
+	switch mode {
+	case entities.PaymentBankTransfer:
+		return "bank transfer"
+	case entities.PaymentUPI:
+		return "UPI"
+	default:
+		return string(mode)
+	}
+}
@@ -0,0 +1,25 @@
+package documents
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatRupees(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		expected string
+	}{
+		{0, "0.00"},
+		{999, "999.00"},
+		{1000, "1,000.00"},
+		{125000.5, "1,25,000.50"},
+		{12345678.9, "1,23,45,678.90"},
+		{-1500, "-1,500.00"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, FormatRupees(tt.amount))
+	}
+}
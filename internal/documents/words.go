@@ -0,0 +1,52 @@
+package documents
+
+import (
+	"math"
+	"strings"
+)
+
+var (
+	ones = []string{"", "One", "Two", "Three", "Four", "Five", "Six", "Seven", "Eight", "Nine", "Ten",
+		"Eleven", "Twelve", "Thirteen", "Fourteen", "Fifteen", "Sixteen", "Seventeen", "Eighteen", "Nineteen"}
+	tens = []string{"", "", "Twenty", "Thirty", "Forty", "Fifty", "Sixty", "Seventy", "Eighty", "Ninety"}
+)
+
+// AmountInWords spells out an amount of rupees the way receipts and cheques do, with the Indian
+// grouping into thousands, lakhs and crores, e.g. "Rupees One Lakh Twenty Thousand and Fifty Paise Only".
+func AmountInWords(amount float64) string {
+	paise := int64(math.Round(math.Abs(amount) * 100))
+	rupees, paise := paise/100, paise%100
+
+	words := "Rupees " + numberInWords(rupees)
+	if rupees == 0 {
+		words = "Rupees Zero"
+	}
+	if paise > 0 {
+		words += " and " + numberInWords(paise) + " Paise"
+	}
+	return words + " Only"
+}
+
+// numberInWords spells out a whole number, or gives "" for 0.
+func numberInWords(n int64) string {
+	var parts []string
+	for _, unit := range []struct {
+		value int64
+		name  string
+	}{{10000000, "Crore"}, {100000, "Lakh"}, {1000, "Thousand"}, {100, "Hundred"}} {
+		if n >= unit.value {
+			parts = append(parts, numberInWords(n/unit.value), unit.name)
+			n %= unit.value
+		}
+	}
+	switch {
+	case n >= 20:
+		parts = append(parts, tens[n/10])
+		if n%10 > 0 {
+			parts = append(parts, ones[n%10])
+		}
+	case n > 0:
+		parts = append(parts, ones[n])
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,25 @@
+package documents
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAmountInWords(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		expected string
+	}{
+		{0, "Rupees Zero Only"},
+		{1, "Rupees One Only"},
+		{15, "Rupees Fifteen Only"},
+		{999.99, "Rupees Nine Hundred Ninety Nine and Ninety Nine Paise Only"},
+		{120000.5, "Rupees One Lakh Twenty Thousand and Fifty Paise Only"},
+		{25000000, "Rupees Two Crore Fifty Lakh Only"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, AmountInWords(tt.amount))
+	}
+}
@@ -0,0 +1,59 @@
+package entities
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// Deposit is the security deposit of a lease, from the landlord receiving it until it is settled at move-out.
+// The landlord itemises what they keep back, and the settlement closes once the tenant acknowledges it.
+type Deposit struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	LeaseID    primitive.ObjectID `bson:"leaseID" json:"lease_id"`
+	Agreed     float64            `bson:"agreed" json:"agreed"` // The deposit set by the lease
+	Received   float64            `bson:"received" json:"received"`
+	ReceivedOn time.Time          `bson:"receivedOn" json:"received_on"`
+	Mode       PaymentMode        `bson:"mode" json:"mode"`
+	Reference  string             `bson:"reference,omitempty" json:"reference,omitempty"`
+	RecordedBy string             `bson:"recordedBy" json:"recorded_by"`
+	RecordedAt time.Time          `bson:"recordedAt" json:"recorded_at"`
+	Status     DepositStatus      `bson:"status" json:"status"`
+	Deductions []DepositDeduction `bson:"deductions" json:"deductions"`
+	Refund     float64            `bson:"refund" json:"refund"` // What is returned to the tenant: the deposit received less the deductions
+	ProposedAt *time.Time         `bson:"proposedAt,omitempty" json:"proposed_at,omitempty"`
+	Dispute    *DepositDispute    `bson:"dispute,omitempty" json:"dispute,omitempty"` // The tenant's latest dispute, if any
+	ClosedAt   *time.Time         `bson:"closedAt,omitempty" json:"closed_at,omitempty"`
+}
+
+// DepositDeduction is an amount the landlord keeps back from the deposit, such as the cost of a repair.
+type DepositDeduction struct {
+	Reason string  `bson:"reason" json:"reason"`
+	Amount float64 `bson:"amount" json:"amount"`
+	Note   string  `bson:"note,omitempty" json:"note,omitempty"`
+}
+
+// DepositDispute records why the tenant disputed the deductions, and when.
+type DepositDispute struct {
+	Reason   string    `bson:"reason" json:"reason"`
+	RaisedAt time.Time `bson:"raisedAt" json:"raised_at"`
+}
+
+// DepositStatus is the state of a security deposit.
+type DepositStatus string
+
+const (
+	DepositAwaited  DepositStatus = "awaited"  // Not received yet
+	DepositHeld     DepositStatus = "held"     // Received, held by the landlord while the lease runs
+	DepositProposed DepositStatus = "proposed" // Deductions itemised at move-out, waiting for the tenant
+	DepositDisputed DepositStatus = "disputed" // The tenant disputed the deductions; the landlord revises them
+	DepositClosed   DepositStatus = "closed"   // The tenant acknowledged the deductions and the refund
+)
+
+// Deducted gives the total of the deductions.
+func (d *Deposit) Deducted() float64 {
+	total := 0.0
+	for _, deduction := range d.Deductions {
+		total += deduction.Amount
+	}
+	return RoundMoney(total)
+}
@@ -0,0 +1,39 @@
+package entities
+
+import "time"
+
+// Document is a generated file, such as a rent receipt, ready to be saved or sent.
+type Document struct {
+	FileName    string // Suggested name to save it under
+	ContentType string
+	Content     []byte
+}
+
+// Party is the tenant or the landlord of a lease as named on its documents.
+// Name, Email and PhoneNumber are empty when the user no longer exists.
+type Party struct {
+	Username    string
+	Name        string
+	Email       string
+	PhoneNumber string
+}
+
+// ReceiptData is what a rent receipt is drawn up from.
+type ReceiptData struct {
+	Number   string // Receipt number, unique per payment
+	IssuedOn time.Time
+	Payment  Payment
+	Lease    Lease
+	Property Property // Zero apart from its ID when the property was deleted
+	Landlord Party
+	Tenant   Party
+}
+
+// LeaseDocumentData is what the printable copy of a lease agreement is drawn up from.
+type LeaseDocumentData struct {
+	IssuedOn time.Time
+	Lease    Lease
+	Property Property // Zero apart from its ID when the property was deleted
+	Landlord Party
+	Tenant   Party
+}
@@ -0,0 +1,24 @@
+package entities
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// EmailKind is the event an email tells the user about. Each kind has its own template.
+type EmailKind string
+
+const (
+	EmailWelcome         EmailKind = "welcome"          // To a user who just signed up
+	EmailRequestCreated  EmailKind = "request_created"  // To the landlord, a tenant asked to rent their property
+	EmailRequestDecided  EmailKind = "request_decided"  // To the tenant, their request was accepted or rejected
+	EmailListingApproved EmailKind = "listing_approved" // To the landlord
+)
+
+// EmailNotice is what a user is emailed about. Their address and name, and the title of the property,
+// are looked up when the email is written.
+type EmailNotice struct {
+	Kind       EmailKind
+	Username   string
+	PropertyID primitive.ObjectID // The property it is about, if any
+	By         string             // Who did what the email tells about, e.g. the tenant asking to rent
+	Status     RequestStatus      // The decision on a rent request
+	Reason     string             // Why a rent request was rejected, if given
+}
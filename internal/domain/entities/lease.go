@@ -0,0 +1,109 @@
+package entities
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// Lease is the rental agreement drawn up when a landlord accepts a rent request.
+// The property is rented out until the lease is cancelled or ends.
+type Lease struct {
+	ID               primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	RequestID        primitive.ObjectID `bson:"requestID" json:"request_id"` // The accepted rent request
+	PropertyID       primitive.ObjectID `bson:"propertyID" json:"property_id"`
+	TenantName       string             `bson:"tenantName" json:"tenant_name"`
+	LandlordName     string             `bson:"landlordName" json:"landlord_name"`
+	StartDate        time.Time          `bson:"startDate" json:"start_date"`
+	EndDate          time.Time          `bson:"endDate" json:"end_date"`         // The move-out date once notice is given
+	MonthlyRent      float64            `bson:"monthlyRent" json:"monthly_rent"` // The rent of the latest term
+	Deposit          float64            `bson:"deposit" json:"deposit"`
+	NoticePeriodDays int                `bson:"noticePeriodDays" json:"notice_period_days"` // Days of notice needed to end the lease early
+	Status           LeaseStatus        `bson:"status" json:"status"`
+	CreatedAt        time.Time          `bson:"createdAt" json:"created_at"`
+	Notice           *Notice            `bson:"notice,omitempty" json:"notice,omitempty"`
+	Renewal          *RenewalOffer      `bson:"renewal,omitempty" json:"renewal,omitempty"`      // The latest renewal offer, if any
+	Rules            *RentRules         `bson:"rules,omitempty" json:"rules,omitempty"`          // Rent escalation and late fees, if any
+	PastRents        []PastRent         `bson:"pastRents,omitempty" json:"past_rents,omitempty"` // The rents of the terms renewed since, oldest first
+}
+
+// PastRent is the rent of a term of the lease that an accepted renewal followed.
+type PastRent struct {
+	MonthlyRent float64   `bson:"monthlyRent" json:"monthly_rent"`
+	From        time.Time `bson:"from" json:"from"`
+	Until       time.Time `bson:"until" json:"until"` // The end of the term, from which the renewal's rent is charged
+}
+
+// Notice records who gave notice to end a lease, and when.
+type Notice struct {
+	GivenBy string    `bson:"givenBy" json:"given_by"`
+	GivenAt time.Time `bson:"givenAt" json:"given_at"`
+}
+
+// RenewalOffer is a landlord's offer to extend a lease, possibly at a new rent.
+type RenewalOffer struct {
+	MonthlyRent float64       `bson:"monthlyRent" json:"monthly_rent"`
+	EndDate     time.Time     `bson:"endDate" json:"end_date"` // End of the lease if the offer is accepted
+	OfferedAt   time.Time     `bson:"offeredAt" json:"offered_at"`
+	Status      RenewalStatus `bson:"status" json:"status"`
+	AnsweredAt  *time.Time    `bson:"answeredAt,omitempty" json:"answered_at,omitempty"`
+}
+
+// RenewalStatus is the state of a renewal offer.
+type RenewalStatus string
+
+const (
+	RenewalOffered  RenewalStatus = "offered"  // Waiting for the tenant
+	RenewalAccepted RenewalStatus = "accepted" // The lease was extended on the new terms
+	RenewalDeclined RenewalStatus = "declined" // The lease runs until its end date
+	RenewalLapsed   RenewalStatus = "lapsed"   // Notice was given or the lease ended before the tenant answered
+)
+
+// LeaseStatus is the state of a lease. It follows the rent request the lease was drawn up for.
+type LeaseStatus string
+
+const (
+	LeasePending   LeaseStatus = "pending"   // Drawn up on acceptance, not signed yet
+	LeaseActive    LeaseStatus = "active"    // Signed by both parties
+	LeaseEnding    LeaseStatus = "ending"    // Notice was given, runs until the move-out date
+	LeaseEnded     LeaseStatus = "ended"     // Ran until its end date; the property is back on the market
+	LeaseCancelled LeaseStatus = "cancelled" // Called off before it was signed
+)
+
+// LeaseStatuses returns every known status.
+func LeaseStatuses() []LeaseStatus {
+	return []LeaseStatus{LeasePending, LeaseActive, LeaseEnding, LeaseEnded, LeaseCancelled}
+}
+
+// OccupiesProperty reports whether a lease in this status keeps the property rented out.
+func (s LeaseStatus) OccupiesProperty() bool {
+	return s == LeasePending || s == LeaseActive || s == LeaseEnding
+}
+
+// IsSigned reports whether a lease in this status was signed, so rent is owed under it.
+func (s LeaseStatus) IsSigned() bool {
+	return s == LeaseActive || s == LeaseEnding || s == LeaseEnded
+}
+
+// RentFrom gives the day the rent of the latest term takes effect: the end of the term before it, or the
+// start of the lease.
+func (l *Lease) RentFrom() time.Time {
+	if len(l.PastRents) > 0 {
+		return l.PastRents[len(l.PastRents)-1].Until
+	}
+	return l.StartDate
+}
+
+// RentOn gives the rent agreed for a month starting on day, and the day that rent took effect.
+func (l *Lease) RentOn(day time.Time) (float64, time.Time) {
+	for _, past := range l.PastRents {
+		if day.Before(past.Until) {
+			return past.MonthlyRent, past.From
+		}
+	}
+	return l.MonthlyRent, l.RentFrom()
+}
+
+// HasOpenRenewal reports whether the lease has a renewal offer the tenant has not answered yet.
+func (l *Lease) HasOpenRenewal() bool {
+	return l.Renewal != nil && l.Renewal.Status == RenewalOffered
+}
@@ -0,0 +1,153 @@
+package entities
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"math"
+	"sort"
+	"time"
+)
+
+// RentDue is the rent owed for one month of a lease. Period 1 is the first month of the lease;
+// the rent of a month is due on its first day.
+type RentDue struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	LeaseID   primitive.ObjectID `bson:"leaseID" json:"lease_id"`
+	Period    int                `bson:"period" json:"period"`
+	DueDate   time.Time          `bson:"dueDate" json:"due_date"`
+	Amount    float64            `bson:"amount" json:"amount"`
+	CreatedAt time.Time          `bson:"createdAt" json:"created_at"`
+}
+
+// Payment is rent the landlord received from the tenant of a lease.
+type Payment struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	LeaseID    primitive.ObjectID `bson:"leaseID" json:"lease_id"`
+	Amount     float64            `bson:"amount" json:"amount"`
+	PaidOn     time.Time          `bson:"paidOn" json:"paid_on"`
+	Mode       PaymentMode        `bson:"mode" json:"mode"`
+	Reference  string             `bson:"reference,omitempty" json:"reference,omitempty"` // Cheque number, transaction ID and so on
+	RecordedBy string             `bson:"recordedBy" json:"recorded_by"`
+	RecordedAt time.Time          `bson:"recordedAt" json:"recorded_at"`
+}
+
+// PaymentMode is how a payment was made.
+type PaymentMode string
+
+const (
+	PaymentCash         PaymentMode = "cash"
+	PaymentBankTransfer PaymentMode = "bank-transfer"
+	PaymentUPI          PaymentMode = "upi"
+	PaymentCheque       PaymentMode = "cheque"
+	PaymentCard         PaymentMode = "card"
+)
+
+// PaymentModes returns every known payment mode.
+func PaymentModes() []PaymentMode {
+	return []PaymentMode{PaymentCash, PaymentBankTransfer, PaymentUPI, PaymentCheque, PaymentCard}
+}
+
+// IsValid reports whether the mode is one of PaymentModes.
+func (m PaymentMode) IsValid() bool {
+	for _, known := range PaymentModes() {
+		if m == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Statement is the account of a lease on a given day: the rent due so far, the payments made and what is left.
+type Statement struct {
+	Lease       Lease           `json:"lease"`
+	AsOf        time.Time       `json:"as_of"`
+	Lines       []StatementLine `json:"lines"`
+	Payments    []Payment       `json:"payments"`
+	TotalDue    float64         `json:"total_due"`
+	TotalPaid   float64         `json:"total_paid"`
+	LateFees    float64         `json:"late_fees"`
+	Outstanding float64         `json:"outstanding"` // Negative when the tenant paid in advance
+}
+
+// StatementLine is one due of a statement with the payments set against it.
+type StatementLine struct {
+	Due      RentDue    `json:"due"`
+	Paid     float64    `json:"paid"`
+	Balance  float64    `json:"balance"`
+	PaidOn   *time.Time `json:"paid_on,omitempty"` // The day the due was paid in full
+	LateDays int        `json:"late_days"`         // Days after the due date it was paid, or is still unpaid
+	LateFee  float64    `json:"late_fee"`          // Charged under the late fee rule of the lease
+}
+
+// NewStatement builds the statement of the lease on asOf. Payments are set against the dues oldest first,
+// so a payment first settles the oldest due that is not fully paid. Late fees are charged on top of the dues
+// from the days each was paid late, and are owed until payments beyond the dues cover them.
+func NewStatement(lease Lease, dues []RentDue, payments []Payment, asOf time.Time) Statement {
+	dues = append([]RentDue(nil), dues...)
+	sort.Slice(dues, func(i, j int) bool { return dues[i].Period < dues[j].Period })
+	payments = append(make([]Payment, 0, len(payments)), payments...)
+	sort.SliceStable(payments, func(i, j int) bool { return payments[i].PaidOn.Before(payments[j].PaidOn) })
+
+	statement := Statement{Lease: lease, AsOf: asOf, Lines: make([]StatementLine, 0, len(dues)), Payments: payments}
+	for _, payment := range payments {
+		statement.TotalPaid += payment.Amount
+	}
+
+	next := 0        // The payment being set against the dues
+	available := 0.0 // What is left of it
+	if len(payments) > 0 {
+		available = payments[0].Amount
+	}
+	for _, due := range dues {
+		line := StatementLine{Due: due}
+		for RoundMoney(due.Amount-line.Paid) > 0 && next < len(payments) {
+			applied := math.Min(available, due.Amount-line.Paid)
+			line.Paid += applied
+			available -= applied
+			if RoundMoney(due.Amount-line.Paid) <= 0 {
+				paidOn := payments[next].PaidOn
+				line.PaidOn = &paidOn
+			}
+			if RoundMoney(available) <= 0 {
+				next++
+				if next < len(payments) {
+					available = payments[next].Amount
+				}
+			}
+		}
+		line.Paid = RoundMoney(line.Paid)
+		line.Balance = RoundMoney(due.Amount - line.Paid)
+
+		settled := asOf
+		if line.PaidOn != nil {
+			settled = *line.PaidOn
+		}
+		line.LateDays = DaysBetween(due.DueDate, settled)
+		if lease.Rules != nil && lease.Rules.LateFee != nil {
+			line.LateFee = lease.Rules.LateFee.For(line.LateDays)
+		}
+
+		statement.TotalDue += due.Amount
+		statement.LateFees += line.LateFee
+		statement.Lines = append(statement.Lines, line)
+	}
+	statement.TotalDue = RoundMoney(statement.TotalDue)
+	statement.TotalPaid = RoundMoney(statement.TotalPaid)
+	statement.LateFees = RoundMoney(statement.LateFees)
+	statement.Outstanding = RoundMoney(statement.TotalDue + statement.LateFees - statement.TotalPaid)
+	return statement
+}
+
+// RoundMoney rounds an amount to whole paise (or cents).
+func RoundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// DaysBetween gives the number of whole days from one day to a later one, or 0 if it is not later.
+func DaysBetween(from, to time.Time) int {
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	if !to.After(from) {
+		return 0
+	}
+	return int(to.Sub(from).Hours() / 24)
+}
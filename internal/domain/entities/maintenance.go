@@ -0,0 +1,130 @@
+package entities
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// MaintenanceTicket is an issue with a rented property the tenant reports to the landlord, such as a leaking tap.
+// Both of them follow it up in its comments until the landlord resolves it and the tenant closes it.
+type MaintenanceTicket struct {
+	ID           primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	PropertyID   primitive.ObjectID  `bson:"propertyID" json:"property_id"`
+	LeaseID      primitive.ObjectID  `bson:"leaseID" json:"lease_id"`
+	TenantName   string              `bson:"tenantName" json:"tenant_name"`
+	LandlordName string              `bson:"landlordName" json:"landlord_name"`
+	Category     MaintenanceCategory `bson:"category" json:"category"`
+	Description  string              `bson:"description" json:"description"`
+	Priority     MaintenancePriority `bson:"priority" json:"priority"`
+	Status       TicketStatus        `bson:"status" json:"status"`
+	Comments     []TicketComment     `bson:"comments" json:"comments"`
+	CreatedAt    time.Time           `bson:"createdAt" json:"created_at"`
+	UpdatedAt    time.Time           `bson:"updatedAt" json:"updated_at"`
+	DueBy        time.Time           `bson:"dueBy" json:"due_by"` // When the landlord should have resolved it, from its priority
+	ResolvedAt   *time.Time          `bson:"resolvedAt,omitempty" json:"resolved_at,omitempty"`
+	Overdue      bool                `bson:"-" json:"overdue"` // Set when the ticket is read
+}
+
+// TicketComment is a message on a ticket from its tenant or landlord. Status changes with a note add one too.
+type TicketComment struct {
+	Author   string    `bson:"author" json:"author"`
+	Text     string    `bson:"text" json:"text"`
+	PostedAt time.Time `bson:"postedAt" json:"posted_at"`
+}
+
+// IsOverdue reports whether the ticket is still waiting for the landlord after its due time.
+func (t *MaintenanceTicket) IsOverdue(now time.Time) bool {
+	return t.Status.IsOpen() && now.After(t.DueBy)
+}
+
+// MaintenanceCategory is the kind of work an issue needs.
+type MaintenanceCategory string
+
+const (
+	CategoryPlumbing    MaintenanceCategory = "plumbing"
+	CategoryElectrical  MaintenanceCategory = "electrical"
+	CategoryAppliance   MaintenanceCategory = "appliance"
+	CategoryStructural  MaintenanceCategory = "structural"
+	CategoryPestControl MaintenanceCategory = "pest-control"
+	CategoryOther       MaintenanceCategory = "other"
+)
+
+// MaintenanceCategories returns every known category.
+func MaintenanceCategories() []MaintenanceCategory {
+	return []MaintenanceCategory{CategoryPlumbing, CategoryElectrical, CategoryAppliance, CategoryStructural, CategoryPestControl, CategoryOther}
+}
+
+// IsValid reports whether the category is one of MaintenanceCategories.
+func (c MaintenanceCategory) IsValid() bool {
+	for _, known := range MaintenanceCategories() {
+		if c == known {
+			return true
+		}
+	}
+	return false
+}
+
+// MaintenancePriority is how urgent an issue is. It sets how long the landlord has to resolve it.
+type MaintenancePriority string
+
+const (
+	PriorityLow    MaintenancePriority = "low"
+	PriorityMedium MaintenancePriority = "medium"
+	PriorityHigh   MaintenancePriority = "high"
+	PriorityUrgent MaintenancePriority = "urgent" // No water, no power, a safety hazard
+)
+
+// MaintenancePriorities returns every known priority, most urgent last.
+func MaintenancePriorities() []MaintenancePriority {
+	return []MaintenancePriority{PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent}
+}
+
+// IsValid reports whether the priority is one of MaintenancePriorities.
+func (p MaintenancePriority) IsValid() bool {
+	return p.Rank() > 0
+}
+
+// Rank orders the priorities from 1 for low to 4 for urgent; it is 0 for an unknown priority.
+func (p MaintenancePriority) Rank() int {
+	for i, known := range MaintenancePriorities() {
+		if p == known {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// ResolveWithin gives how long the landlord has to resolve an issue of this priority.
+func (p MaintenancePriority) ResolveWithin() time.Duration {
+	switch p {
+	case PriorityUrgent:
+		return 24 * time.Hour
+	case PriorityHigh:
+		return 3 * 24 * time.Hour
+	case PriorityMedium:
+		return 7 * 24 * time.Hour
+	default:
+		return 14 * 24 * time.Hour
+	}
+}
+
+// TicketStatus is the state of a maintenance ticket.
+type TicketStatus string
+
+const (
+	TicketOpen         TicketStatus = "open"         // Reported, not seen by the landlord yet
+	TicketAcknowledged TicketStatus = "acknowledged" // The landlord has seen it
+	TicketInProgress   TicketStatus = "in-progress"  // The work is under way
+	TicketResolved     TicketStatus = "resolved"     // The landlord reports it fixed; the tenant closes or reopens it
+	TicketClosed       TicketStatus = "closed"       // The tenant confirmed the fix
+)
+
+// TicketStatuses returns every known status.
+func TicketStatuses() []TicketStatus {
+	return []TicketStatus{TicketOpen, TicketAcknowledged, TicketInProgress, TicketResolved, TicketClosed}
+}
+
+// IsOpen reports whether a ticket in this status still waits for the landlord.
+func (s TicketStatus) IsOpen() bool {
+	return s == TicketOpen || s == TicketAcknowledged || s == TicketInProgress
+}
@@ -0,0 +1,51 @@
+package entities
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// Thread is the conversation between a tenant and the landlord about one property. A tenant has one thread
+// per property, however they start it: from a search result, their wishlist or a rent request.
+type Thread struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	PropertyID     primitive.ObjectID `bson:"propertyID" json:"property_id"`
+	TenantName     string             `bson:"tenantName" json:"tenant_name"`
+	LandlordName   string             `bson:"landlordName" json:"landlord_name"`
+	Messages       []Message          `bson:"messages" json:"messages"`              // Oldest first
+	TenantUnread   int                `bson:"tenantUnread" json:"tenant_unread"`     // Messages from the landlord the tenant has not read
+	LandlordUnread int                `bson:"landlordUnread" json:"landlord_unread"` // Messages from the tenant the landlord has not read
+	CreatedAt      time.Time          `bson:"createdAt" json:"created_at"`
+	LastMessageAt  time.Time          `bson:"lastMessageAt" json:"last_message_at"`
+}
+
+// Message is one message in a thread.
+type Message struct {
+	Sender string    `bson:"sender" json:"sender"`
+	Text   string    `bson:"text" json:"text"`
+	SentAt time.Time `bson:"sentAt" json:"sent_at"`
+}
+
+// HasParty reports whether the user is the tenant or the landlord of the thread.
+func (t *Thread) HasParty(username string) bool {
+	return t.TenantName == username || t.LandlordName == username
+}
+
+// UnreadFor gives how many messages in the thread the user has not read yet.
+func (t *Thread) UnreadFor(username string) int {
+	switch username {
+	case t.TenantName:
+		return t.TenantUnread
+	case t.LandlordName:
+		return t.LandlordUnread
+	default:
+		return 0
+	}
+}
+
+// UnreadMessages counts the messages a user has not read yet, in the threads where they are the tenant
+// and in those where they are the landlord.
+type UnreadMessages struct {
+	AsTenant   int `json:"as_tenant"`
+	AsLandlord int `json:"as_landlord"`
+}
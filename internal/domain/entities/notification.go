@@ -0,0 +1,29 @@
+package entities
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// NotificationKind is the event a notification tells the user about.
+type NotificationKind string
+
+const (
+	NotificationRequestCreated   NotificationKind = "request_created"   // To the landlord, a tenant asked to rent their property
+	NotificationRequestAccepted  NotificationKind = "request_accepted"  // To the tenant
+	NotificationRequestRejected  NotificationKind = "request_rejected"  // To the tenant
+	NotificationPropertyApproved NotificationKind = "property_approved" // To the landlord
+	NotificationPropertyRejected NotificationKind = "property_rejected" // To the landlord, the listing was turned down and removed
+	NotificationAccountChanged   NotificationKind = "account_changed"   // To the user, their details or role were changed
+)
+
+// Notification is an entry in the inbox of a user, recorded when something happens that concerns them.
+type Notification struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Username   string             `bson:"username" json:"username"`
+	Kind       NotificationKind   `bson:"kind" json:"kind"`
+	Text       string             `bson:"text" json:"text"`
+	PropertyID primitive.ObjectID `bson:"propertyID,omitempty" json:"property_id,omitempty"` // The property it is about, if any
+	Read       bool               `bson:"read" json:"read"`
+	CreatedAt  time.Time          `bson:"createdAt" json:"created_at"`
+}
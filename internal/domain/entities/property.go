@@ -3,38 +3,39 @@ package entities
 import "go.mongodb.org/mongo-driver/bson/primitive"
 
 type Property struct {
-	ID                primitive.ObjectID `bson:"_id"`           // MongoDB unique ID
-	PropertyType      int                `bson:"property_type"` // 1: Commercial, 2: House, 3: Flat
-	Title             string             `bson:"title"`
-	Address           Address            `bson:"address"`
-	LandlordUsername  string             `bson:"landlord_username"`
-	RentAmount        float64            `bson:"rent_amount"`
-	Applications      []string           `bson:"applications"`
-	IsApprovedByAdmin bool               `bson:"is_approved_by_admin"`
-	IsRented          bool               `bson:"is_rented"`
-	Details           interface{}        `bson:"details"` // Holds specific details based on property type
+	ID                primitive.ObjectID `bson:"_id" json:"id"`                      // MongoDB unique ID
+	PropertyType      int                `bson:"property_type" json:"property_type"` // 1: Commercial, 2: House, 3: Flat
+	Title             string             `bson:"title" json:"title"`
+	Address           Address            `bson:"address" json:"address"`
+	LandlordUsername  string             `bson:"landlord_username" json:"landlord_username"`
+	RentAmount        float64            `bson:"rent_amount" json:"rent_amount"`
+	Deposit           float64            `bson:"deposit" json:"deposit"` // Security deposit asked for; 0 for the default of the leases
+	Applications      []string           `bson:"applications" json:"applications"`
+	IsApprovedByAdmin bool               `bson:"is_approved_by_admin" json:"is_approved_by_admin"`
+	IsRented          bool               `bson:"is_rented" json:"is_rented"`
+	Details           interface{}        `bson:"details" json:"details"` // Holds specific details based on property type
 }
 
 type Address struct {
-	Area    string `bson:"area"`
-	City    string `bson:"city"`
-	State   string `bson:"state"`
-	Pincode int    `bson:"pincode"`
+	Area    string `bson:"area" json:"area"`
+	City    string `bson:"city" json:"city"`
+	State   string `bson:"state" json:"state"`
+	Pincode int    `bson:"pincode" json:"pincode"`
 }
 
 type CommercialDetails struct {
-	FloorArea string `bson:"floor_area"`
-	SubType   string `bson:"sub_type"` // shop, factory, warehouse
+	FloorArea string `bson:"floor_area" json:"floor_area"`
+	SubType   string `bson:"sub_type" json:"sub_type"` // shop, factory, warehouse
 }
 
 type HouseDetails struct {
-	NoOfRooms         int      `bson:"no_of_rooms"`
-	FurnishedCategory string   `bson:"furnished_category"`
-	Amenities         []string `bson:"amenities"`
+	NoOfRooms         int      `bson:"no_of_rooms" json:"no_of_rooms"`
+	FurnishedCategory string   `bson:"furnished_category" json:"furnished_category"`
+	Amenities         []string `bson:"amenities" json:"amenities"`
 }
 
 type FlatDetails struct {
-	FurnishedCategory string   `bson:"furnished_category"`
-	Amenities         []string `bson:"amenities"`
-	BHK               int      `bson:"bhk"`
+	FurnishedCategory string   `bson:"furnished_category" json:"furnished_category"`
+	Amenities         []string `bson:"amenities" json:"amenities"`
+	BHK               int      `bson:"bhk" json:"bhk"`
 }
@@ -0,0 +1,19 @@
+package entities
+
+import "time"
+
+// RefreshToken is the stored side of an API login. Access tokens carry its ID,
+// so revoking it logs the client out even before the access token expires.
+type RefreshToken struct {
+	ID        string    `bson:"_id" json:"id"`
+	Username  string    `bson:"username" json:"username"`
+	TokenHash string    `bson:"tokenHash" json:"-"` // SHA-256 of the current refresh secret, replaced on every refresh
+	IssuedAt  time.Time `bson:"issuedAt" json:"issued_at"`
+	ExpiresAt time.Time `bson:"expiresAt" json:"expires_at"`
+	Revoked   bool      `bson:"revoked" json:"revoked"`
+}
+
+// IsActive reports whether the token can still be used at the given time.
+func (t *RefreshToken) IsActive(now time.Time) bool {
+	return !t.Revoked && now.Before(t.ExpiresAt)
+}
@@ -6,10 +6,88 @@ import (
 )
 
 type Request struct {
-	ID            primitive.ObjectID `bson:"_id,omitempty"`
-	TenantName    string             `bson:"tenantName"`
-	PropertyID    primitive.ObjectID `bson:"propertyID"`
-	LandlordName  string             `bson:"landlordName"`
-	RequestStatus string             `bson:"requestStatus"` // e.g., "Pending", "Accepted", "Rejected"
-	CreatedAt     time.Time          `bson:"created_at"`
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TenantName    string             `bson:"tenantName" json:"tenant_name"`
+	PropertyID    primitive.ObjectID `bson:"propertyID" json:"property_id"`
+	LandlordName  string             `bson:"landlordName" json:"landlord_name"`
+	RequestStatus RequestStatus      `bson:"requestStatus" json:"request_status"`
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+	History       []StatusChange     `bson:"history" json:"history"` // Every status the request has had, oldest first
+}
+
+// RequestStatus is the state of a rent request. Requests start out pending and
+// move only along the transitions allowed by CanTransitionTo.
+type RequestStatus string
+
+const (
+	RequestPending     RequestStatus = "pending"      // Waiting for the landlord
+	RequestAccepted    RequestStatus = "accepted"     // Accepted by the landlord, lease not signed yet
+	RequestRejected    RequestStatus = "rejected"     // Turned down by the landlord
+	RequestWithdrawn   RequestStatus = "withdrawn"    // Taken back by the tenant before an answer
+	RequestExpired     RequestStatus = "expired"      // Not answered in time
+	RequestLeaseSigned RequestStatus = "lease-signed" // Both parties signed the lease
+	RequestCancelled   RequestStatus = "cancelled"    // Called off after it was accepted
+)
+
+var requestTransitions = map[RequestStatus][]RequestStatus{
+	RequestPending:  {RequestAccepted, RequestRejected, RequestWithdrawn, RequestExpired},
+	RequestAccepted: {RequestLeaseSigned, RequestCancelled},
+}
+
+// RequestStatuses returns every known status.
+func RequestStatuses() []RequestStatus {
+	return []RequestStatus{RequestPending, RequestAccepted, RequestRejected, RequestWithdrawn, RequestExpired, RequestLeaseSigned, RequestCancelled}
+}
+
+// OpenRequestStatuses returns the statuses of requests that are not final yet.
+func OpenRequestStatuses() []RequestStatus {
+	var open []RequestStatus
+	for _, status := range RequestStatuses() {
+		if !status.IsFinal() {
+			open = append(open, status)
+		}
+	}
+	return open
+}
+
+// IsValid reports whether the status is one of the known statuses.
+func (s RequestStatus) IsValid() bool {
+	for _, status := range RequestStatuses() {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// CanTransitionTo reports whether a request may move from this status to next.
+func (s RequestStatus) CanTransitionTo(next RequestStatus) bool {
+	for _, allowed := range requestTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFinal reports whether no further transition is possible.
+func (s RequestStatus) IsFinal() bool {
+	return len(requestTransitions[s]) == 0
+}
+
+// StatusReason returns the reason recorded for the latest status change, or "" if there is none.
+func (r Request) StatusReason() string {
+	if len(r.History) == 0 {
+		return ""
+	}
+	return r.History[len(r.History)-1].Reason
+}
+
+// StatusChange records one transition of a rent request.
+type StatusChange struct {
+	From      RequestStatus `bson:"from" json:"from,omitempty"` // Empty for the creation of the request
+	To        RequestStatus `bson:"to" json:"to"`
+	ChangedAt time.Time     `bson:"changedAt" json:"changed_at"`
+	ChangedBy string        `bson:"changedBy" json:"changed_by"`              // Username of who made the change
+	Reason    string        `bson:"reason,omitempty" json:"reason,omitempty"` // Why the change was made, when it was not the user's own choice
 }
@@ -0,0 +1,83 @@
+package entities
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// RentRules are the rules the landlord sets on a lease for raising the rent and charging for late payment.
+// Either rule may be left out.
+type RentRules struct {
+	Escalation *Escalation `bson:"escalation,omitempty" json:"escalation,omitempty"`
+	LateFee    *LateFee    `bson:"lateFee,omitempty" json:"late_fee,omitempty"`
+}
+
+// Escalation raises the rent every so many months by a percentage, a fixed amount or both,
+// such as 5% every 12 months. Each raise applies to the rent as last raised.
+type Escalation struct {
+	Percent     float64   `bson:"percent" json:"percent"`
+	Amount      float64   `bson:"amount" json:"amount"`
+	EveryMonths int       `bson:"everyMonths" json:"every_months"`
+	Since       time.Time `bson:"since" json:"since"` // When the rent it raises took effect: the lease start or the accepted renewal
+}
+
+// Steps gives how many times the rent was raised by a month starting on day.
+func (e *Escalation) Steps(day time.Time) int {
+	if e.EveryMonths <= 0 {
+		return 0
+	}
+	months := 0
+	for !e.Since.AddDate(0, months+1, 0).After(day) {
+		months++
+	}
+	return months / e.EveryMonths
+}
+
+// Apply gives the rent of a month starting on day, raised from rent as many times as the rule says.
+func (e *Escalation) Apply(rent float64, day time.Time) float64 {
+	for steps := e.Steps(day); steps > 0; steps-- {
+		rent = rent*(1+e.Percent/100) + e.Amount
+	}
+	return RoundMoney(rent)
+}
+
+// LateFee is charged on rent still unpaid more than GraceDays after its due date: a flat fee plus a fee for
+// every day beyond the grace days, such as 100 a day after the 5th of the month. Max, when set, caps the fee
+// charged on one month's rent.
+type LateFee struct {
+	GraceDays int     `bson:"graceDays" json:"grace_days"`
+	Flat      float64 `bson:"flat" json:"flat"`
+	PerDay    float64 `bson:"perDay" json:"per_day"`
+	Max       float64 `bson:"max" json:"max"`
+}
+
+// For gives the fee on rent paid, or still unpaid, lateDays after its due date.
+func (f *LateFee) For(lateDays int) float64 {
+	charged := lateDays - f.GraceDays
+	if charged <= 0 {
+		return 0
+	}
+	fee := f.Flat + f.PerDay*float64(charged)
+	if f.Max > 0 && fee > f.Max {
+		fee = f.Max
+	}
+	return RoundMoney(fee)
+}
+
+// Schedule is the rent a lease will ask for in the months ahead under a set of rules, worked out without
+// changing the ledger.
+type Schedule struct {
+	LeaseID primitive.ObjectID `json:"lease_id"`
+	Rules   RentRules          `json:"rules"`
+	Dues    []ScheduledDue     `json:"dues"`
+}
+
+// ScheduledDue is the rent of one month ahead.
+type ScheduledDue struct {
+	Period   int        `json:"period"`
+	DueDate  time.Time  `json:"due_date"`
+	Amount   float64    `json:"amount"`
+	Increase float64    `json:"increase"`            // Over the rent of the month before
+	LateFrom *time.Time `json:"late_from,omitempty"` // The first day a late fee is charged if the rent is unpaid
+	LateFee  float64    `json:"late_fee,omitempty"`  // The fee charged on that day
+}
@@ -0,0 +1,50 @@
+package entities
+
+// Roles a user can have. Users who sign up get RoleUser, which can both rent and let out properties.
+const (
+	RoleUser      = "User"
+	RoleTenant    = "Tenant"
+	RoleLandlord  = "Landlord"
+	RoleModerator = "Moderator"
+	RoleAdmin     = "Admin"
+)
+
+// Permission is an action that only some roles may take.
+type Permission string
+
+const (
+	PermRentProperties     Permission = "rent_properties"     // Keep a wishlist and send rent requests
+	PermListProperties     Permission = "list_properties"     // List own properties and answer their rent requests
+	PermReviewProperties   Permission = "review_properties"   // See pending properties and approve them
+	PermModerateProperties Permission = "moderate_properties" // Delete the properties of any landlord
+	PermManageUsers        Permission = "manage_users"        // See, delete and change the role of users
+)
+
+var rolePermissions = map[string][]Permission{
+	RoleUser:      {PermRentProperties, PermListProperties},
+	RoleTenant:    {PermRentProperties},
+	RoleLandlord:  {PermListProperties},
+	RoleModerator: {PermReviewProperties, PermModerateProperties},
+	RoleAdmin:     {PermReviewProperties, PermModerateProperties, PermManageUsers},
+}
+
+// Roles returns every known role.
+func Roles() []string {
+	return []string{RoleUser, RoleTenant, RoleLandlord, RoleModerator, RoleAdmin}
+}
+
+// IsValidRole reports whether role is one of the known roles.
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// HasPermission reports whether the role grants the permission. Unknown roles grant nothing.
+func HasPermission(role string, permission Permission) bool {
+	for _, granted := range rolePermissions[role] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+package entities
+
+import "time"
+
+// Session is the login state of a user, created by UserService.Login.
+type Session struct {
+	ID        string    // ID of the refresh token behind an API login, empty in the terminal UI
+	User      User      // The logged in user
+	Role      string    // Role of the user at login time, e.g. "Admin"
+	LoginTime time.Time // When the user logged in
+	ExpiresAt time.Time // After this the session is no longer valid
+}
+
+// Username returns the username of the logged in user.
+func (s *Session) Username() string {
+	return s.User.Username
+}
+
+// IsAdmin reports whether the session belongs to an admin.
+func (s *Session) IsAdmin() bool {
+	return s.Role == RoleAdmin
+}
+
+// Can reports whether the role of the session grants the permission.
+func (s *Session) Can(permission Permission) bool {
+	return HasPermission(s.Role, permission)
+}
+
+// IsExpired reports whether the session has expired at the given time.
+func (s *Session) IsExpired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
@@ -0,0 +1,11 @@
+package entities
+
+import "time"
+
+// TokenPair is the set of credentials handed to an API client at login and on every refresh.
+type TokenPair struct {
+	AccessToken      string    // Signed token sent with every request
+	AccessExpiresAt  time.Time // After this the access token must be refreshed
+	RefreshToken     string    // Opaque token exchanged for a new pair, usable once
+	RefreshExpiresAt time.Time // After this the client has to log in again
+}
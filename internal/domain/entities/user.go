@@ -3,13 +3,13 @@ package entities
 import "go.mongodb.org/mongo-driver/bson/primitive"
 
 type User struct {
-	Username     string               `bson:"username"`
-	PasswordHash string               `bson:"password_hash"`
-	Name         string               `bson:"name"`
-	Age          int                  `bson:"age"`
-	Email        string               `bson:"email"`
-	PhoneNumber  string               `bson:"phone_number"`
-	Address      string               `bson:"address"`
-	Role         string               `bson:"role"`
-	Wishlist     []primitive.ObjectID `bson:"wishlist"` // List of property IDs in the wishlist
+	Username     string               `bson:"username" json:"username"`
+	PasswordHash string               `bson:"password_hash" json:"-"`
+	Name         string               `bson:"name" json:"name"`
+	Age          int                  `bson:"age" json:"age"`
+	Email        string               `bson:"email" json:"email"`
+	PhoneNumber  string               `bson:"phone_number" json:"phone_number"`
+	Address      string               `bson:"address" json:"address"`
+	Role         string               `bson:"role" json:"role"`
+	Wishlist     []primitive.ObjectID `bson:"wishlist" json:"wishlist"` // List of property IDs in the wishlist
 }
@@ -0,0 +1,36 @@
+package entities
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// VisitSlot is a time the landlord offers for showing a property to tenants. A tenant books it to visit the
+// property; the booking holds the slot until either of them cancels it or the tenant moves it to another slot.
+type VisitSlot struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	PropertyID   primitive.ObjectID `bson:"propertyID" json:"property_id"`
+	LandlordName string             `bson:"landlordName" json:"landlord_name"`
+	Start        time.Time          `bson:"start" json:"start"`
+	End          time.Time          `bson:"end" json:"end"`
+	Status       VisitStatus        `bson:"status" json:"status"`
+	TenantName   string             `bson:"tenantName,omitempty" json:"tenant_name,omitempty"` // Who booked it; kept when a booked slot is cancelled
+	BookedAt     *time.Time         `bson:"bookedAt,omitempty" json:"booked_at,omitempty"`
+	CancelledBy  string             `bson:"cancelledBy,omitempty" json:"cancelled_by,omitempty"`
+	CreatedAt    time.Time          `bson:"createdAt" json:"created_at"`
+	UpdatedAt    time.Time          `bson:"updatedAt" json:"updated_at"`
+}
+
+// Overlaps reports whether the slot shares any time with the one from start to end.
+func (s *VisitSlot) Overlaps(start, end time.Time) bool {
+	return s.Start.Before(end) && start.Before(s.End)
+}
+
+// VisitStatus is the state of a visit slot.
+type VisitStatus string
+
+const (
+	VisitOpen      VisitStatus = "open"      // Offered, waiting for a tenant to book it
+	VisitBooked    VisitStatus = "booked"    // A tenant will visit then
+	VisitCancelled VisitStatus = "cancelled" // Withdrawn by the landlord, or the visit called off by the landlord
+)
@@ -0,0 +1,90 @@
+// Package events defines what the services announce when something has happened, so that the side
+// effects of a change, such as notifications, emails and audit logging, can subscribe to it
+// instead of being wired into every caller.
+package events
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// Event is something that has happened. Events are published once the change has been made.
+type Event interface {
+	// EventName names the kind of event, e.g. "user.deleted". Every value of a type gives the same name.
+	EventName() string
+}
+
+// UserSignedUp is published when a new account is created.
+type UserSignedUp struct {
+	Username string `json:"username"`
+}
+
+// UserUpdated is published when the details of a user are changed, by themselves or by an admin.
+type UserUpdated struct {
+	Username string `json:"username"`
+	By       string `json:"by"`
+}
+
+// UserRoleChanged is published when an admin changes the role of a user.
+type UserRoleChanged struct {
+	Username string `json:"username"`
+	Role     string `json:"role"`
+	By       string `json:"by"`
+}
+
+// UserDeleted is published when an admin has deleted a user, along with their listings and logins.
+type UserDeleted struct {
+	Username string `json:"username"`
+	By       string `json:"by"`
+}
+
+// PropertyApproved is published when a moderator approves a listing.
+type PropertyApproved struct {
+	PropertyID primitive.ObjectID `json:"property_id"`
+	Title      string             `json:"title"`
+	Landlord   string             `json:"landlord"`
+	By         string             `json:"by"`
+}
+
+// PropertyRejected is published when a moderator turns down a listing, which has then been removed.
+type PropertyRejected struct {
+	PropertyID primitive.ObjectID `json:"property_id"`
+	Title      string             `json:"title"`
+	Landlord   string             `json:"landlord"`
+	By         string             `json:"by"`
+	Reason     string             `json:"reason,omitempty"`
+}
+
+// RentRequestCreated is published when a tenant asks to rent a property.
+type RentRequestCreated struct {
+	PropertyID    primitive.ObjectID `json:"property_id"`
+	PropertyTitle string             `json:"property_title"`
+	Tenant        string             `json:"tenant"`
+	Landlord      string             `json:"landlord"`
+}
+
+// RentRequestAccepted is published when the landlord accepts a rent request.
+type RentRequestAccepted struct {
+	RequestID  primitive.ObjectID `json:"request_id"`
+	PropertyID primitive.ObjectID `json:"property_id"`
+	Tenant     string             `json:"tenant"`
+	Landlord   string             `json:"landlord"`
+	By         string             `json:"by"`
+}
+
+// RentRequestRejected is published when the landlord rejects a rent request.
+type RentRequestRejected struct {
+	RequestID  primitive.ObjectID `json:"request_id"`
+	PropertyID primitive.ObjectID `json:"property_id"`
+	Tenant     string             `json:"tenant"`
+	Landlord   string             `json:"landlord"`
+	By         string             `json:"by"`
+	Reason     string             `json:"reason,omitempty"`
+}
+
+func (UserSignedUp) EventName() string        { return "user.signed_up" }
+func (UserUpdated) EventName() string         { return "user.updated" }
+func (UserRoleChanged) EventName() string     { return "user.role_changed" }
+func (UserDeleted) EventName() string         { return "user.deleted" }
+func (PropertyApproved) EventName() string    { return "property.approved" }
+func (PropertyRejected) EventName() string    { return "property.rejected" }
+func (RentRequestCreated) EventName() string  { return "rent_request.created" }
+func (RentRequestAccepted) EventName() string { return "rent_request.accepted" }
+func (RentRequestRejected) EventName() string { return "rent_request.rejected" }
@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+)
+
+type DepositRepo interface {
+	// CreateDeposit saves the deposit unless its lease already has one, in which case it reports false.
+	CreateDeposit(ctx context.Context, deposit entities.Deposit) (bool, error)
+	// FindDepositByLease returns the deposit of the lease, or nil if none was recorded.
+	FindDepositByLease(ctx context.Context, leaseID primitive.ObjectID) (*entities.Deposit, error)
+	// UpdateDeposit replaces the deposit if the stored one is still in from, and reports whether it was.
+	UpdateDeposit(ctx context.Context, deposit entities.Deposit, from entities.DepositStatus) (bool, error)
+}
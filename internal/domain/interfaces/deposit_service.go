@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+)
+
+type DepositService interface {
+	Deposit(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID) (entities.Deposit, error)
+	RecordDeposit(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID, receipt entities.Payment) (entities.Deposit, error)
+	ProposeDeductions(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID, deductions []entities.DepositDeduction) (entities.Deposit, error)
+	AcknowledgeDeductions(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID) (entities.Deposit, error)
+	DisputeDeductions(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID, reason string) (entities.Deposit, error)
+}
@@ -0,0 +1,9 @@
+package interfaces
+
+import "rentease/internal/domain/entities"
+
+// DocumentRenderer lays out documents from their data, e.g. as PDF.
+type DocumentRenderer interface {
+	RentReceipt(data entities.ReceiptData) (entities.Document, error)
+	LeaseAgreement(data entities.LeaseDocumentData) (entities.Document, error)
+}
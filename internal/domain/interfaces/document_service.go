@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+)
+
+type DocumentService interface {
+	RentReceipt(ctx context.Context, session *entities.Session, leaseID, paymentID primitive.ObjectID) (entities.Document, error)
+	LeaseAgreement(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID) (entities.Document, error)
+}
@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"context"
+	"rentease/internal/domain/entities"
+)
+
+// EmailNotifier emails users about what happened to them. Emails are sent in the background, so an
+// error only means the email could not be written or queued.
+type EmailNotifier interface {
+	Notify(ctx context.Context, notice entities.EmailNotice) error
+}
@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"context"
+	"rentease/internal/domain/events"
+)
+
+// EventPublisher hands events to their subscribers. The error joins those of the subscribers; the ones
+// whose work is only best effort, such as notifications, log their failures instead.
+type EventPublisher interface {
+	Publish(ctx context.Context, event events.Event) error
+}
@@ -0,0 +1,23 @@
+package interfaces
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+)
+
+type LeaseRepo interface {
+	SaveLease(ctx context.Context, lease entities.Lease) error
+	// FindLeaseByID and FindLeaseByRequestID return nil and no error when there is no such lease.
+	FindLeaseByID(ctx context.Context, id primitive.ObjectID) (*entities.Lease, error)
+	FindLeaseByRequestID(ctx context.Context, requestID primitive.ObjectID) (*entities.Lease, error)
+	FindLeasesByTenant(ctx context.Context, tenantName string) ([]entities.Lease, error)
+	FindLeasesByLandlord(ctx context.Context, landlordName string) ([]entities.Lease, error)
+	FindLeasesByStatus(ctx context.Context, status entities.LeaseStatus) ([]entities.Lease, error)
+	// UpdateLeaseStatus moves the lease from one status to another.
+	// It reports false, changing nothing, when the lease does not exist or is no longer in from.
+	UpdateLeaseStatus(ctx context.Context, id primitive.ObjectID, from, to entities.LeaseStatus) (bool, error)
+	// UpdateLease replaces the stored lease with the given one if the stored lease is still in from.
+	// It reports false, changing nothing, when the lease does not exist or is no longer in from.
+	UpdateLease(ctx context.Context, lease entities.Lease, from entities.LeaseStatus) (bool, error)
+}
@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+	"time"
+)
+
+type LeaseService interface {
+	GetLeasesForTenant(ctx context.Context, session *entities.Session) ([]entities.Lease, error)
+	GetLeasesForLandlord(ctx context.Context, session *entities.Session) ([]entities.Lease, error)
+	GetLease(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID) (entities.Lease, error)
+	GiveNotice(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID) (entities.Lease, error)
+	OfferRenewal(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID, monthlyRent float64, months int) (entities.Lease, error)
+	AnswerRenewal(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID, accept bool) (entities.Lease, error)
+	SetRentRules(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID, rules entities.RentRules) (entities.Lease, error)
+	EndLeases(ctx context.Context, session *entities.Session, now time.Time) (int, error)
+}
@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+)
+
+type LedgerRepo interface {
+	// CreateDue saves the due unless the lease already has one for the period, in which case it reports false.
+	CreateDue(ctx context.Context, due entities.RentDue) (bool, error)
+	// FindDuesByLease returns the dues of the lease ordered by period.
+	FindDuesByLease(ctx context.Context, leaseID primitive.ObjectID) ([]entities.RentDue, error)
+	SavePayment(ctx context.Context, payment entities.Payment) error
+	// FindPaymentsByLease returns the payments of the lease ordered by the day they were made.
+	FindPaymentsByLease(ctx context.Context, leaseID primitive.ObjectID) ([]entities.Payment, error)
+}
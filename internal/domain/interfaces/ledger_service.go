@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+)
+
+type LedgerService interface {
+	Statement(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID) (entities.Statement, error)
+	RecordPayment(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID, payment entities.Payment) (entities.Payment, error)
+	Schedule(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID, rules *entities.RentRules, months int) (entities.Schedule, error)
+}
@@ -0,0 +1,25 @@
+package interfaces
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+	"time"
+)
+
+type MaintenanceRepo interface {
+	SaveTicket(ctx context.Context, ticket entities.MaintenanceTicket) error
+	// FindTicketByID returns nil and no error when the ticket does not exist.
+	FindTicketByID(ctx context.Context, id primitive.ObjectID) (*entities.MaintenanceTicket, error)
+	// FindTicketsByTenant and FindTicketsByLandlord return the tickets ordered by when they were reported.
+	FindTicketsByTenant(ctx context.Context, tenantName string) ([]entities.MaintenanceTicket, error)
+	FindTicketsByLandlord(ctx context.Context, landlordName string) ([]entities.MaintenanceTicket, error)
+	// UpdateTicketStatus moves the ticket from one status to another at the given time, appending the note
+	// to its comments when there is one. Moving to resolved sets when it was resolved, which closing keeps.
+	// Reopening clears it and makes the ticket due again from its priority, counted from the given time.
+	// It reports false, changing nothing, when the ticket does not exist or is no longer in from.
+	UpdateTicketStatus(ctx context.Context, id primitive.ObjectID, from, to entities.TicketStatus, at time.Time, note *entities.TicketComment) (bool, error)
+	// AddTicketComment appends the comment to the ticket.
+	// It reports false, changing nothing, when the ticket does not exist or is closed.
+	AddTicketComment(ctx context.Context, id primitive.ObjectID, comment entities.TicketComment) (bool, error)
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+)
+
+type MaintenanceService interface {
+	ReportIssue(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID, category entities.MaintenanceCategory, priority entities.MaintenancePriority, description string) (entities.MaintenanceTicket, error)
+	Ticket(ctx context.Context, session *entities.Session, ticketID primitive.ObjectID) (entities.MaintenanceTicket, error)
+	TicketsForTenant(ctx context.Context, session *entities.Session) ([]entities.MaintenanceTicket, error)
+	MaintenanceQueue(ctx context.Context, session *entities.Session) ([]entities.MaintenanceTicket, error)
+	UpdateTicketStatus(ctx context.Context, session *entities.Session, ticketID primitive.ObjectID, to entities.TicketStatus, note string) (entities.MaintenanceTicket, error)
+	AddComment(ctx context.Context, session *entities.Session, ticketID primitive.ObjectID, text string) (entities.MaintenanceTicket, error)
+}
@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+)
+
+type MessageRepo interface {
+	// CreateThread saves a new thread unless the tenant already has one about the property.
+	// It reports false, saving nothing, when there is one.
+	CreateThread(ctx context.Context, thread entities.Thread) (bool, error)
+	// FindThreadByID and FindThread return nil and no error when the thread does not exist.
+	FindThreadByID(ctx context.Context, id primitive.ObjectID) (*entities.Thread, error)
+	FindThread(ctx context.Context, propertyID primitive.ObjectID, tenantName string) (*entities.Thread, error)
+	// FindThreadsByUser returns the threads the user is the tenant or the landlord of, latest message first.
+	FindThreadsByUser(ctx context.Context, username string) ([]entities.Thread, error)
+	// AddMessage appends the message to the thread and counts it unread for the party who did not send it.
+	// It reports false, changing nothing, when the thread does not exist or the sender is not a party to it.
+	AddMessage(ctx context.Context, threadID primitive.ObjectID, message entities.Message) (bool, error)
+	// MarkThreadRead sets the unread count of the user in the thread back to zero.
+	// It reports false when the thread does not exist or the user is not a party to it.
+	MarkThreadRead(ctx context.Context, threadID primitive.ObjectID, username string) (bool, error)
+}
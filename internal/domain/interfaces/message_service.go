@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+)
+
+type MessageService interface {
+	ContactLandlord(ctx context.Context, session *entities.Session, propertyID primitive.ObjectID, text string) (entities.Thread, error)
+	MessageAboutRequest(ctx context.Context, session *entities.Session, requestID primitive.ObjectID, text string) (entities.Thread, error)
+	SendMessage(ctx context.Context, session *entities.Session, threadID primitive.ObjectID, text string) (entities.Thread, error)
+	ReadThread(ctx context.Context, session *entities.Session, threadID primitive.ObjectID) (entities.Thread, error)
+	Threads(ctx context.Context, session *entities.Session) ([]entities.Thread, error)
+	UnreadMessages(ctx context.Context, session *entities.Session) (entities.UnreadMessages, error)
+}
@@ -0,0 +1,23 @@
+package interfaces
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+)
+
+type NotificationRepo interface {
+	SaveNotification(ctx context.Context, notification entities.Notification) error
+	// FindNotificationsByUser returns the notifications of the user, newest first.
+	FindNotificationsByUser(ctx context.Context, username string) ([]entities.Notification, error)
+	// MarkNotificationRead marks one notification of the user read.
+	// It reports false when the user has no notification with the ID.
+	MarkNotificationRead(ctx context.Context, id primitive.ObjectID, username string) (bool, error)
+	// MarkAllNotificationsRead marks every notification of the user read and returns how many were unread.
+	MarkAllNotificationsRead(ctx context.Context, username string) (int, error)
+	// DeleteNotification removes one notification of the user.
+	// It reports false when the user has no notification with the ID.
+	DeleteNotification(ctx context.Context, id primitive.ObjectID, username string) (bool, error)
+	// DeleteReadNotifications removes the notifications the user has read and returns how many there were.
+	DeleteReadNotifications(ctx context.Context, username string) (int, error)
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+)
+
+type NotificationService interface {
+	Notifications(ctx context.Context, session *entities.Session) ([]entities.Notification, error)
+	UnreadNotifications(ctx context.Context, session *entities.Session) (int, error)
+	MarkNotificationRead(ctx context.Context, session *entities.Session, id primitive.ObjectID) error
+	MarkAllNotificationsRead(ctx context.Context, session *entities.Session) (int, error)
+	DeleteNotification(ctx context.Context, session *entities.Session, id primitive.ObjectID) error
+	ClearReadNotifications(ctx context.Context, session *entities.Session) (int, error)
+}
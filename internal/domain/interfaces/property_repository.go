@@ -7,15 +7,18 @@ import (
 )
 
 type PropertyRepo interface {
-	SaveProperty(property entities.Property) error
-	GetAllListedProperties(activerUseronly bool) ([]entities.Property, error)
-	UpdateListedProperty(property entities.Property) error
-	DeleteListedProperty(propertyID string) error
+	SaveProperty(ctx context.Context, property entities.Property) error
+	GetAllListedProperties(ctx context.Context, landlordUsername string) ([]entities.Property, error)
+	UpdateListedProperty(ctx context.Context, property entities.Property) error
+	DeleteListedProperty(ctx context.Context, propertyID primitive.ObjectID) error
 	//SearchProperties(area, city, state string, pincode int) ([]entities.Property, error)
 	FindByID(ctx context.Context, id primitive.ObjectID) (*entities.Property, error)
-	UpdateApprovalStatus(propertyID primitive.ObjectID, approved bool, adminUsername string) error
-	FindPendingProperties() ([]entities.Property, error)
-	DeleteAllListedPropertiesOfaUser(username string) error
+	UpdateApprovalStatus(ctx context.Context, propertyID primitive.ObjectID, approved bool, adminUsername string) error
+	// SetRented sets whether the property is rented out, in one step with checking that it was not already.
+	// It reports false, changing nothing, when the property does not exist or is already in that state.
+	SetRented(ctx context.Context, propertyID primitive.ObjectID, rented bool) (bool, error)
+	FindPendingProperties(ctx context.Context) ([]entities.Property, error)
+	DeleteAllListedPropertiesOfaUser(ctx context.Context, username string) error
 }
 
 //type PropertyService interface {
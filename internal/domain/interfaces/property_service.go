@@ -1,26 +1,29 @@
 package interfaces
 
 import (
+	"context"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"rentease/internal/domain/entities"
 )
 
 type PropertyService interface {
-	ListProperty(property entities.Property) error
+	ListProperty(ctx context.Context, session *entities.Session, property entities.Property) error
 
-	GetAllListedProperties(activeUseronly bool) ([]entities.Property, error)
+	GetAllListedProperties(ctx context.Context, landlordUsername string) ([]entities.Property, error)
 
-	UpdateListedProperty(property entities.Property) error
+	UpdateListedProperty(ctx context.Context, session *entities.Session, property entities.Property) error
 
-	DeleteListedProperty(propertyID string) error
+	DeleteListedProperty(ctx context.Context, session *entities.Session, propertyID primitive.ObjectID) error
 
-	SearchProperties(area, city, state string, pincode, propertyType int) ([]entities.Property, error)
+	SearchProperties(ctx context.Context, area, city, state string, pincode, propertyType int) ([]entities.Property, error)
 
-	FindByID(id primitive.ObjectID) (entities.Property, error)
+	FindByID(ctx context.Context, id primitive.ObjectID) (entities.Property, error)
 
-	DeleteAllListedPropertiesOfaUser(username string) error
+	DeleteAllListedPropertiesOfaUser(ctx context.Context, session *entities.Session, username string) error
 
-	GetPendingProperties() ([]entities.Property, error)
+	GetPendingProperties(ctx context.Context, session *entities.Session) ([]entities.Property, error)
 
-	ApproveProperty(propertyID primitive.ObjectID, adminUsername string) error
+	ApproveProperty(ctx context.Context, session *entities.Session, propertyID primitive.ObjectID) error
+
+	RejectProperty(ctx context.Context, session *entities.Session, propertyID primitive.ObjectID, reason string) error
 }
@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+	"rentease/internal/domain/entities"
+)
+
+type RefreshTokenRepo interface {
+	SaveRefreshToken(ctx context.Context, token entities.RefreshToken) error
+	// RotateRefreshToken replaces the stored token with the same ID only while its hash is still previousHash
+	// and it is not revoked. It reports false, changing nothing, when another refresh got there first.
+	RotateRefreshToken(ctx context.Context, token entities.RefreshToken, previousHash string) (bool, error)
+	FindRefreshToken(ctx context.Context, id string) (*entities.RefreshToken, error)
+	RevokeRefreshToken(ctx context.Context, id string) error
+	RevokeUserRefreshTokens(ctx context.Context, username string) error
+}
@@ -3,12 +3,22 @@ package interfaces
 
 import (
 	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"rentease/internal/domain/entities"
 )
 
 type RequestRepo interface {
-	SaveRequest(request entities.Request) error
+	SaveRequest(ctx context.Context, request entities.Request) error
+	// CreateRequest saves a new request unless the tenant already has an open request for the property.
+	// It reports false, saving nothing, when there is one.
+	CreateRequest(ctx context.Context, request entities.Request) (bool, error)
+	// FindRequestByID returns nil and no error when the request does not exist.
+	FindRequestByID(ctx context.Context, id primitive.ObjectID) (*entities.Request, error)
 	FindByTenantUsername(ctx context.Context, tenantUsername string) ([]entities.Request, error)
 	FindByLandlordName(ctx context.Context, landlordName string) ([]entities.Request, error)
-	UpdateRequest(request entities.Request, status string) error
+	FindByStatus(ctx context.Context, status entities.RequestStatus) ([]entities.Request, error)
+	FindByPropertyID(ctx context.Context, propertyID primitive.ObjectID) ([]entities.Request, error)
+	// UpdateRequestStatus moves the request from change.From to change.To and appends change to its history.
+	// It reports false, changing nothing, when the request does not exist or is no longer in change.From.
+	UpdateRequestStatus(ctx context.Context, id primitive.ObjectID, change entities.StatusChange) (bool, error)
 }
@@ -1,13 +1,17 @@
 package interfaces
 
 import (
+	"context"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"rentease/internal/domain/entities"
+	"time"
 )
 
 type RentRequestService interface {
-	CreateRentRequest(tenantName string, propertyID primitive.ObjectID, landlordName string) error
-	GetRentRequestsInfoForLandlord(landlordName string) ([]entities.Request, error)
-	UpdateRequestStatus(request entities.Request, status string) error
-	GetRentRequestsInfoForTenant(tenantName string) ([]entities.Request, error)
+	CreateRentRequest(ctx context.Context, session *entities.Session, propertyID primitive.ObjectID) error
+	GetRentRequestsInfoForLandlord(ctx context.Context, session *entities.Session) ([]entities.Request, error)
+	UpdateRequestStatus(ctx context.Context, session *entities.Session, requestID primitive.ObjectID, status entities.RequestStatus) error
+	WithdrawRequest(ctx context.Context, session *entities.Session, requestID primitive.ObjectID) error
+	ExpireRequests(ctx context.Context, session *entities.Session, createdBefore time.Time) (int, error)
+	GetRentRequestsInfoForTenant(ctx context.Context, session *entities.Session) ([]entities.Request, error)
 }
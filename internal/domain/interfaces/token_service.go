@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+	"rentease/internal/domain/entities"
+)
+
+type TokenService interface {
+	Issue(ctx context.Context, session *entities.Session) (entities.TokenPair, error)
+	Authenticate(ctx context.Context, accessToken string) (*entities.Session, error)
+	Refresh(ctx context.Context, refreshToken string) (entities.TokenPair, error)
+	Revoke(ctx context.Context, session *entities.Session) error
+	RevokeAll(ctx context.Context, username string) error
+}
@@ -6,12 +6,12 @@ import (
 )
 
 type UserRepo interface {
-	SaveUser(user entities.User) error
+	SaveUser(ctx context.Context, user entities.User) error
 	FindByUsername(ctx context.Context, username string) (*entities.User, error)
 	CheckPassword(ctx context.Context, username string, password string) (bool, error)
-	UpdateUser(user entities.User) error
-	Delete(username string) error
-	FindAll() ([]entities.User, error)
+	UpdateUser(ctx context.Context, user entities.User) error
+	Delete(ctx context.Context, username string) error
+	FindAll(ctx context.Context) ([]entities.User, error)
 }
 
 // Admin
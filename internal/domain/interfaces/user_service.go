@@ -1,16 +1,18 @@
 package interfaces
 
 import (
+	"context"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"rentease/internal/domain/entities"
 )
 
 type UserService interface {
-	SignUp(user entities.User) error
-	FindByUsername(username string) (entities.User, error)
-	Login(username, password string) (bool, error)
-	AddToWishlist(username string, propertyID primitive.ObjectID) error
-	UpdateUser(user entities.User) error
-	GetAllUsers() ([]entities.User, error)
-	DeleteUser(username string) error
+	SignUp(ctx context.Context, user entities.User) error
+	FindByUsername(ctx context.Context, username string) (entities.User, error)
+	Login(ctx context.Context, username, password string) (*entities.Session, error)
+	AddToWishlist(ctx context.Context, session *entities.Session, propertyID primitive.ObjectID) error
+	UpdateUser(ctx context.Context, session *entities.Session, user entities.User) error
+	GetAllUsers(ctx context.Context, session *entities.Session) ([]entities.User, error)
+	DeleteUser(ctx context.Context, session *entities.Session, username string) error
+	SetRole(ctx context.Context, session *entities.Session, username, role string) error
 }
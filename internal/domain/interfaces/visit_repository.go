@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+	"time"
+)
+
+type VisitRepo interface {
+	SaveSlot(ctx context.Context, slot entities.VisitSlot) error
+	// FindSlotByID returns the slot, or nil if there is none with the ID.
+	FindSlotByID(ctx context.Context, id primitive.ObjectID) (*entities.VisitSlot, error)
+	// FindSlotsByProperty, FindSlotsByLandlord and FindSlotsByTenant return slots ordered by their start.
+	FindSlotsByProperty(ctx context.Context, propertyID primitive.ObjectID) ([]entities.VisitSlot, error)
+	FindSlotsByLandlord(ctx context.Context, landlordName string) ([]entities.VisitSlot, error)
+	FindSlotsByTenant(ctx context.Context, tenantName string) ([]entities.VisitSlot, error)
+	// BookSlot books the slot for the tenant if it is still open, and reports whether it was.
+	BookSlot(ctx context.Context, id primitive.ObjectID, tenantName string, at time.Time) (bool, error)
+	// FreeSlot opens the slot again if it is still booked by the tenant, and reports whether it was.
+	FreeSlot(ctx context.Context, id primitive.ObjectID, tenantName string, at time.Time) (bool, error)
+	// CancelSlot cancels the slot if it is still in from, keeping who booked it, and reports whether it was.
+	CancelSlot(ctx context.Context, id primitive.ObjectID, from entities.VisitStatus, by string, at time.Time) (bool, error)
+}
@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+	"time"
+)
+
+type VisitService interface {
+	PublishSlots(ctx context.Context, session *entities.Session, propertyID primitive.ObjectID, starts []time.Time, length time.Duration) ([]entities.VisitSlot, error)
+	VisitSlots(ctx context.Context, session *entities.Session, propertyID primitive.ObjectID) ([]entities.VisitSlot, error)
+	BookVisit(ctx context.Context, session *entities.Session, slotID primitive.ObjectID) (entities.VisitSlot, error)
+	RescheduleVisit(ctx context.Context, session *entities.Session, slotID, toSlotID primitive.ObjectID) (entities.VisitSlot, error)
+	CancelVisit(ctx context.Context, session *entities.Session, slotID primitive.ObjectID) (entities.VisitSlot, error)
+	UpcomingVisitsForTenant(ctx context.Context, session *entities.Session) ([]entities.VisitSlot, error)
+	UpcomingVisitsForLandlord(ctx context.Context, session *entities.Session) ([]entities.VisitSlot, error)
+}
@@ -0,0 +1,102 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	netmail "net/mail"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"rentease/config"
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+)
+
+// Notifier emails users about what happened to them. It looks up their address, writes the email from
+// its template and queues it to be sent.
+type Notifier struct {
+	from         string
+	templates    *Templates
+	queue        *Queue
+	userRepo     interfaces.UserRepo
+	propertyRepo interfaces.PropertyRepo
+}
+
+func NewNotifier(from string, templates *Templates, queue *Queue, userRepo interfaces.UserRepo, propertyRepo interfaces.PropertyRepo) *Notifier {
+	return &Notifier{
+		from:         from,
+		templates:    templates,
+		queue:        queue,
+		userRepo:     userRepo,
+		propertyRepo: propertyRepo,
+	}
+}
+
+// Open starts a notifier sending through the sender selected in the configuration.
+func Open(cfg config.EmailConfig, userRepo interfaces.UserRepo, propertyRepo interfaces.PropertyRepo) (*Notifier, error) {
+	templates, err := LoadTemplates(cfg.TemplateDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var sender Sender
+	switch cfg.Sender {
+	case config.EmailSMTP:
+		sender = NewSMTPSender(cfg.SMTP.Host, cfg.SMTP.Port, cfg.SMTP.Username, cfg.SMTP.Password, cfg.SMTP.Timeout)
+	case config.EmailFile:
+		sender = NewFileSender(cfg.File)
+	case config.EmailMemory:
+		sender = NewMemorySender()
+	default:
+		return nil, fmt.Errorf("unknown email sender %q", cfg.Sender)
+	}
+	queue := NewQueue(sender, cfg.QueueSize, cfg.MaxAttempts, cfg.RetryDelay)
+	return NewNotifier(cfg.From, templates, queue, userRepo, propertyRepo), nil
+}
+
+// Notify writes the email about the notice and queues it. Users without an email address, or who no
+// longer exist, are not emailed.
+func (n *Notifier) Notify(ctx context.Context, notice entities.EmailNotice) error {
+	user, err := n.userRepo.FindByUsername(ctx, notice.Username)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return err
+	}
+	if user == nil || user.Email == "" {
+		return nil
+	}
+
+	data := templateData{EmailNotice: notice, Name: user.Name}
+	if data.Name == "" {
+		data.Name = user.Username
+	}
+	if !notice.PropertyID.IsZero() {
+		property, err := n.propertyRepo.FindByID(ctx, notice.PropertyID)
+		if err != nil {
+			return err
+		}
+		if property != nil {
+			data.PropertyTitle = property.Title
+		}
+	}
+
+	subject, body, err := n.templates.write(notice.Kind, data)
+	if err != nil {
+		return err
+	}
+	to := (&netmail.Address{Name: data.Name, Address: user.Email}).String()
+	return n.queue.Enqueue(Message{From: n.from, To: to, Subject: subject, Body: body})
+}
+
+// Close stops taking emails and waits for the queued ones to be sent, or for ctx to be done.
+func (n *Notifier) Close(ctx context.Context) error {
+	return n.queue.Close(ctx)
+}
+
+// address gives the bare address of "Name <address>", as the SMTP envelope needs it.
+func address(s string) (string, error) {
+	parsed, err := netmail.ParseAddress(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid email address %q: %w", s, err)
+	}
+	return parsed.Address, nil
+}
@@ -0,0 +1,118 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+var (
+	ErrQueueFull   = errors.New("the email queue is full")
+	ErrQueueClosed = errors.New("the email queue is closed")
+)
+
+// Queue sends messages in the background, one after the other. A message that fails is tried again
+// after a delay that doubles every time, until it has been tried maxAttempts times.
+type Queue struct {
+	sender      Sender
+	maxAttempts int
+	retryDelay  time.Duration
+
+	mu      sync.RWMutex
+	closed  bool
+	pending chan Message
+
+	// ctx is cancelled when Close gives up waiting, which stops the retries
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewQueue starts sending the messages given to it. At most size messages wait to be sent.
+func NewQueue(sender Sender, size, maxAttempts int, retryDelay time.Duration) *Queue {
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &Queue{
+		sender:      sender,
+		maxAttempts: maxAttempts,
+		retryDelay:  retryDelay,
+		pending:     make(chan Message, size),
+		ctx:         ctx,
+		cancel:      cancel,
+		done:        make(chan struct{}),
+	}
+	go q.run()
+	return q
+}
+
+// Enqueue hands the message over to be sent. It does not wait for the message to be sent, and fails
+// when too many messages are waiting already or the queue is closed.
+func (q *Queue) Enqueue(message Message) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return ErrQueueClosed
+	}
+	select {
+	case q.pending <- message:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Close stops taking messages and waits for the waiting ones to be sent. When ctx is done first, the
+// messages not sent yet are given up and ctx's error is returned right away; the email being sent at
+// that moment is abandoned in the background.
+func (q *Queue) Close(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.pending)
+	}
+	q.mu.Unlock()
+
+	select {
+	case <-q.done:
+		return nil
+	case <-ctx.Done():
+		q.cancel()
+		return ctx.Err()
+	}
+}
+
+func (q *Queue) run() {
+	defer close(q.done)
+	defer q.cancel()
+	for message := range q.pending {
+		q.deliver(message)
+	}
+}
+
+// deliver sends the message, trying again while attempts are left.
+func (q *Queue) deliver(message Message) {
+	delay := q.retryDelay
+	for attempt := 1; ; attempt++ {
+		if q.ctx.Err() != nil {
+			log.Printf("mail: gave up the email %q to %s on shutdown", message.Subject, message.To)
+			return
+		}
+		err := q.sender.Send(q.ctx, message)
+		if err == nil {
+			return
+		}
+		if attempt >= q.maxAttempts {
+			log.Printf("mail: gave up the email %q to %s after %d attempts: %v", message.Subject, message.To, attempt, err)
+			return
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-q.ctx.Done():
+			timer.Stop()
+		}
+		delay *= 2
+	}
+}
@@ -0,0 +1,97 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"rentease/internal/domain/entities"
+)
+
+//go:embed templates/*.tmpl
+var builtinTemplates embed.FS
+
+// kinds lists every kind of email, each written from the template named after it.
+var kinds = []entities.EmailKind{
+	entities.EmailWelcome,
+	entities.EmailRequestCreated,
+	entities.EmailRequestDecided,
+	entities.EmailListingApproved,
+}
+
+// TemplateName gives the name of the template of the kind of email, which is also the name of the
+// file replacing it.
+func TemplateName(kind entities.EmailKind) string {
+	return string(kind) + ".tmpl"
+}
+
+// Templates writes emails from the built-in templates or the ones that replace them. A template starts
+// with a "Subject: " line and a blank line; the rest is the body.
+type Templates struct {
+	templates map[entities.EmailKind]*template.Template
+}
+
+// templateData is what the templates are filled in with.
+type templateData struct {
+	entities.EmailNotice
+	Name          string // Of the user the email goes to
+	PropertyTitle string
+}
+
+// Accepted tells whether the rent request was accepted, for the request_decided template.
+func (d templateData) Accepted() bool {
+	return d.Status == entities.RequestAccepted
+}
+
+// LoadTemplates loads the templates. A template found in dir replaces the built-in one; an empty dir uses
+// only the built-in templates. A template that does not parse is an error, so mistakes show at startup.
+func LoadTemplates(dir string) (*Templates, error) {
+	t := &Templates{templates: make(map[entities.EmailKind]*template.Template, len(kinds))}
+	for _, kind := range kinds {
+		name := TemplateName(kind)
+		source, err := builtinTemplates.ReadFile("templates/" + name)
+		if err != nil {
+			return nil, err
+		}
+		if dir != "" {
+			custom, err := os.ReadFile(filepath.Join(dir, name))
+			switch {
+			case err == nil:
+				source = custom
+			case !errors.Is(err, fs.ErrNotExist):
+				return nil, fmt.Errorf("failed to read template %s: %w", name, err)
+			}
+		}
+		tmpl, err := template.New(name).Option("missingkey=error").Parse(string(source))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+		}
+		t.templates[kind] = tmpl
+	}
+	return t, nil
+}
+
+// write fills in the template of the kind of email, giving its subject and body.
+func (t *Templates) write(kind entities.EmailKind, data templateData) (subject, body string, err error) {
+	tmpl, ok := t.templates[kind]
+	if !ok {
+		return "", "", fmt.Errorf("no template for %s emails", kind)
+	}
+	var text bytes.Buffer
+	if err := tmpl.Execute(&text, data); err != nil {
+		return "", "", fmt.Errorf("failed to fill in template %s: %w", tmpl.Name(), err)
+	}
+
+	header, body, found := strings.Cut(strings.ReplaceAll(text.String(), "\r\n", "\n"), "\n\n")
+	subject, hasSubject := strings.CutPrefix(header, "Subject: ")
+	if !found || !hasSubject || strings.Contains(subject, "\n") {
+		return "", "", fmt.Errorf("template %s must start with a Subject: line and a blank line", tmpl.Name())
+	}
+	return strings.TrimSpace(subject), strings.TrimSpace(body) + "\n", nil
+}
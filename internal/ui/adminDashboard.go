@@ -13,14 +13,20 @@ var allUsers []entities.User
 
 func (ui *UI) AdminDashboard() {
 	for {
+		if ui.sessionExpired() {
+			return
+		}
+
 		fmt.Println("\n\033[1;34m╔════════════════════════════════════════╗\033[0m") // Blue border
 		fmt.Println("\033[1;34m║            Admin Dashboard             ║\033[0m")   // Blue header
 		fmt.Println("\033[1;34m╚════════════════════════════════════════╝\033[0m")   // Blue border
+		ui.printUnreadNotifications()
 		fmt.Println()
 		fmt.Println("	1. \033[1;36mView all users\033[0m")     // Cyan
 		fmt.Println("	2. \033[1;36mDelete a user\033[0m")      // Cyan
 		fmt.Println("	3. \033[1;36mApprove properties\033[0m") // Cyan
-		fmt.Println("	4. \033[1;36mLogout\033[0m")             // Red for Logout
+		fmt.Println("	4. \033[1;36mNotifications\033[0m")      // Cyan
+		fmt.Println("	5. \033[1;36mLogout\033[0m")             // Red for Logout
 
 		// Read and convert choice input
 		choiceTemp := utils.ReadInput("\n\033[1;33mEnter your choice: \033[0m")
@@ -38,6 +44,8 @@ func (ui *UI) AdminDashboard() {
 		case 3:
 			ui.ApproveProperties()
 		case 4:
+			ui.ShowNotifications()
+		case 5:
 			fmt.Println("\033[1;32mLogout successful.\033[0m") // Green
 			return
 		default:
@@ -49,7 +57,7 @@ func (ui *UI) AdminDashboard() {
 func (ui *UI) ViewAllUsers() {
 
 	//Fetching all users from the database
-	users, err := ui.UserService.GetAllUsers()
+	users, err := ui.UserService.GetAllUsers(ui.ctx, ui.session)
 	if err != nil {
 		fmt.Printf("\033[1;31mError retrieving users: %v\033[0m\n", err) // Red
 		return
@@ -69,7 +77,7 @@ func (ui *UI) ViewAllUsers() {
 	table.SetAutoWrapText(false)
 	// Populate the table with user data
 	for _, user := range allUsers {
-		if user.Role != "Admin" {
+		if user.Role != entities.RoleAdmin {
 			table.Append([]string{user.Username, user.Name, fmt.Sprintf("%d", user.Age), user.Address, user.PhoneNumber, user.Email})
 		}
 	}
@@ -80,12 +88,10 @@ func (ui *UI) ViewAllUsers() {
 
 	// Gather all properties for all users
 	var allProperties []entities.Property
-	activeUserRecord := utils.ActiveUser
 	for _, user := range allUsers {
-		if user.Role != "Admin" {
+		if user.Role != entities.RoleAdmin {
 			// Get properties for each user
-			utils.ActiveUser = user.Username
-			properties, err := ui.PropertyService.GetAllListedProperties(true)
+			properties, err := ui.PropertyService.GetAllListedProperties(ui.ctx, user.Username)
 			if err != nil {
 				fmt.Printf("\033[1;31mError retrieving properties for user %s: %v\033[0m\n", user.Username, err)
 				continue
@@ -94,7 +100,6 @@ func (ui *UI) ViewAllUsers() {
 			allProperties = append(allProperties, properties...)
 		}
 	}
-	utils.ActiveUser = activeUserRecord
 
 	fmt.Println()
 	// Display all properties in a single table
@@ -153,7 +158,7 @@ func (ui *UI) DeleteUser() {
 
 		userFound := false
 		for _, user := range allUsers {
-			if user.Username == username && user.Role != "Admin" {
+			if user.Username == username && user.Role != entities.RoleAdmin {
 				userFound = true
 				break
 			}
@@ -164,23 +169,17 @@ func (ui *UI) DeleteUser() {
 			continue
 		}
 
-		err := ui.UserService.DeleteUser(username)
+		err := ui.UserService.DeleteUser(ui.ctx, ui.session, username)
 		if err != nil {
 			fmt.Printf("\033[1;31mError deleting user: %v\033[0m\n", err) // Red
 		} else {
-			fmt.Println("\033[1;32mUser deleted successfully.\033[0m") // Green
-			err = ui.PropertyService.DeleteAllListedPropertiesOfaUser(username)
-			if err != nil {
-				fmt.Printf("\033[1;31mError in deleting the properties of this user: %v\033[0m\n", err) // Red
-			} else {
-				fmt.Println("\033[1;32mProperties of this user also deleted successfully.\033[0m") // Green
-			}
+			fmt.Println("\033[1;32mUser and their properties deleted successfully.\033[0m") // Green
 		}
 	}
 }
 
 func (ui *UI) ApproveProperties() {
-	properties, err := ui.PropertyService.GetPendingProperties()
+	properties, err := ui.PropertyService.GetPendingProperties(ui.ctx, ui.session)
 	if err != nil {
 		fmt.Printf("\033[1;31mError retrieving properties: %v\033[0m\n", err) // Red
 		return
@@ -198,7 +197,7 @@ func (ui *UI) ApproveProperties() {
 		fmt.Println()
 		ui.DisplayPropertyShortInfo(properties)
 
-		choiceTemp := utils.ReadInput("\n\033[1;33mEnter 0 to go back, 1 to see more details, 2 to approve a property: \033[0m")
+		choiceTemp := utils.ReadInput("\n\033[1;33mEnter 0 to go back, 1 to see more details, 2 to approve a property, 3 to reject one: \033[0m")
 		choice, err := strconv.Atoi(choiceTemp)
 		if err != nil {
 			fmt.Println("\033[1;31mInvalid input, please enter a number.\033[0m")
@@ -230,12 +229,32 @@ func (ui *UI) ApproveProperties() {
 			}
 
 			selectedProperty := properties[propertyIndex-1]
-			err = ui.PropertyService.ApproveProperty(selectedProperty.ID, utils.ActiveUser) // `ActiveUser` is the admin who approves the property
+			err = ui.PropertyService.ApproveProperty(ui.ctx, ui.session, selectedProperty.ID) // The session admin or moderator approves the property
 			if err != nil {
 				fmt.Printf("\033[1;31mError approving property: %v\033[0m\n", err) // Red
 			} else {
 				fmt.Println("\033[1;32mProperty approved successfully.\033[0m") // Green
-				properties, _ = ui.PropertyService.GetPendingProperties()
+				properties, _ = ui.PropertyService.GetPendingProperties(ui.ctx, ui.session)
+			}
+
+		case 3:
+			var propertyIndex int
+			propertyIndexTemp := utils.ReadInput("\033[1;33mEnter the property number to reject: \033[0m")
+			propertyIndex, _ = strconv.Atoi(propertyIndexTemp)
+
+			if propertyIndex < 1 || propertyIndex > len(properties) {
+				fmt.Println("\033[1;31mInvalid property number.\033[0m") // Red
+				continue
+			}
+
+			// The landlord sees the reason in their notifications
+			reason := utils.ReadInput("\033[1;33mReason for the landlord: \033[0m")
+			err = ui.PropertyService.RejectProperty(ui.ctx, ui.session, properties[propertyIndex-1].ID, reason)
+			if err != nil {
+				fmt.Printf("\033[1;31mError rejecting property: %v\033[0m\n", err) // Red
+			} else {
+				fmt.Println("\033[1;32mProperty rejected and removed.\033[0m") // Green
+				properties, _ = ui.PropertyService.GetPendingProperties(ui.ctx, ui.session)
 			}
 		}
 	}
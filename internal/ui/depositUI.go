@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"fmt"
+	"github.com/olekukonko/tablewriter"
+	"os"
+	"rentease/internal/domain/entities"
+	"rentease/pkg/utils"
+	"strconv"
+	"strings"
+)
+
+// ManageDeposit prints the security deposit of a lease and offers what the user can do with it next:
+// the landlord records the deposit and itemises the deductions at move-out, the tenant acknowledges
+// or disputes them.
+func (ui *UI) ManageDeposit(lease entities.Lease, asLandlord bool) {
+	deposit, err := ui.DepositService.Deposit(ui.ctx, ui.session, lease.ID)
+	if err != nil {
+		ui.displayError("retrieving the deposit", err)
+		return
+	}
+	printDeposit(deposit)
+
+	switch {
+	case asLandlord && deposit.Status == entities.DepositAwaited:
+		if utils.ReadInput("\nRecord the deposit as received? (y/n): ") != "y" {
+			return
+		}
+		receipt, ok := readPayment()
+		if !ok {
+			return
+		}
+		deposit, err = ui.DepositService.RecordDeposit(ui.ctx, ui.session, lease.ID, receipt)
+	case asLandlord && deposit.Status != entities.DepositClosed:
+		if lease.Status != entities.LeaseEnding && lease.Status != entities.LeaseEnded {
+			return // Deductions are itemised once the tenant moves out
+		}
+		if utils.ReadInput("\nItemise the deductions from the deposit? (y/n): ") != "y" {
+			return
+		}
+		deductions, ok := readDeductions()
+		if !ok {
+			return
+		}
+		deposit, err = ui.DepositService.ProposeDeductions(ui.ctx, ui.session, lease.ID, deductions)
+	case !asLandlord && deposit.Status == entities.DepositProposed:
+		fmt.Println("\033[1;32m1. Acknowledge Deductions\033[0m")
+		fmt.Println("\033[1;32m2. Dispute Deductions\033[0m")
+		fmt.Println("\033[1;31m0. Go Back\033[0m")
+		switch utils.ReadInput("\nEnter your choice: ") {
+		case "1":
+			deposit, err = ui.DepositService.AcknowledgeDeductions(ui.ctx, ui.session, lease.ID)
+		case "2":
+			deposit, err = ui.DepositService.DisputeDeductions(ui.ctx, ui.session, lease.ID, utils.ReadInput("Why do you dispute the deductions: "))
+		default:
+			return
+		}
+	default:
+		return
+	}
+	if err != nil {
+		ui.displayError("updating the deposit", err)
+		return
+	}
+	fmt.Printf("\033[1;32mDeposit updated: %s, refund %.2f.\033[0m\n", deposit.Status, deposit.Refund) // Green
+}
+
+// printDeposit prints what was received of the deposit, the deductions and the refund.
+func printDeposit(deposit entities.Deposit) {
+	fmt.Printf("\n\033[1;34mSecurity Deposit: %s\033[0m\n", deposit.Status) // Blue
+	if deposit.Status == entities.DepositAwaited {
+		fmt.Printf("\033[1;33mA deposit of %.2f has not been received yet.\033[0m\n", deposit.Agreed) // Yellow
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Item", "Amount", "Note"})
+	table.SetAutoWrapText(false)
+	table.Append([]string{"Received on " + deposit.ReceivedOn.Format("02 Jan 2006"), fmt.Sprintf("%.2f", deposit.Received), string(deposit.Mode) + " " + deposit.Reference})
+	for _, deduction := range deposit.Deductions {
+		table.Append([]string{deduction.Reason, fmt.Sprintf("-%.2f", deduction.Amount), deduction.Note})
+	}
+	table.SetFooter([]string{"Refund", fmt.Sprintf("%.2f", deposit.Refund), ""})
+	table.SetBorder(true)
+	table.Render()
+
+	if deposit.Status == entities.DepositDisputed && deposit.Dispute != nil {
+		fmt.Printf("\033[1;31mDisputed by the tenant: %s\033[0m\n", deposit.Dispute.Reason) // Red
+	}
+}
+
+// readDeductions asks for the deductions one by one until an empty reason is entered.
+func readDeductions() ([]entities.DepositDeduction, bool) {
+	var deductions []entities.DepositDeduction
+	fmt.Println("Enter each deduction; leave the reason empty when done.")
+	for {
+		reason := strings.TrimSpace(utils.ReadInput("Reason: "))
+		if reason == "" {
+			return deductions, true
+		}
+		amount, err := strconv.ParseFloat(utils.ReadInput("Amount: "), 64)
+		if err != nil {
+			fmt.Println("\033[1;31mInvalid amount.\033[0m") // Red
+			return nil, false
+		}
+		note := strings.TrimSpace(utils.ReadInput("Note (enter for none): "))
+		deductions = append(deductions, entities.DepositDeduction{Reason: reason, Amount: amount, Note: note})
+	}
+}
@@ -13,12 +13,18 @@ func (ui *UI) landlordDashboard() {
 		fmt.Println("\n\n\n\033[1;36m-------------------------------------------------\033[0m")  // Sky blue
 		fmt.Println("\033[1;33m              LANDLORD DASHBOARD                        \033[0m") // Red bold
 		fmt.Println("\033[1;36m-------------------------------------------------\033[0m")        // Sky blue
+		ui.printUnreadMessages(true)
+		ui.printUpcomingVisits(true)
 
 		// Display the options available to the landlord
 		fmt.Println("     \033[1;32m1. List Your Property\033[0m")              // Green
 		fmt.Println("     \033[1;32m2. View and Manage Listed Property\033[0m") // Green
 		fmt.Println("     \033[1;32m3. Manage Rent Requests\033[0m")            // Green
-		fmt.Println("     \033[1;32m4. Back to Main Dashboard\033[0m")          // Red
+		fmt.Println("     \033[1;32m4. View Leases\033[0m")                     // Green
+		fmt.Println("     \033[1;32m5. Maintenance Queue\033[0m")               // Green
+		fmt.Println("     \033[1;32m6. Property Visits\033[0m")                 // Green
+		fmt.Println("     \033[1;32m7. Messages\033[0m")                        // Green
+		fmt.Println("     \033[1;32m8. Back to Main Dashboard\033[0m")          // Red
 
 		// Read user input for the selected option
 		var choice int
@@ -45,6 +51,22 @@ func (ui *UI) landlordDashboard() {
 			ui.RentRequestsDashboardForLandlord()
 
 		case 4:
+			// Leases of the landlord's properties
+			ui.ShowLeases(true)
+
+		case 5:
+			// Open maintenance tickets on the landlord's properties, overdue first
+			ui.MaintenanceQueue()
+
+		case 6:
+			// Visit slots of the landlord's properties and the visits booked in them
+			ui.VisitsDashboard(true)
+
+		case 7:
+			// Conversations with tenants about the landlord's properties
+			ui.MessagesDashboard(true)
+
+		case 8:
 			// Go back to the main dashboard
 			return
 
@@ -58,7 +80,7 @@ func (ui *UI) landlordDashboard() {
 func (ui *UI) viewAndManageListedProperties(isViewingProfile bool) {
 
 	// Fetch all listed properties
-	listedProperties, err := ui.PropertyService.GetAllListedProperties(true)
+	listedProperties, err := ui.PropertyService.GetAllListedProperties(ui.ctx, ui.session.Username())
 	if err != nil {
 		// Display error if fetching properties fails
 		ui.displayError("fetching listed properties", err)
@@ -138,7 +160,7 @@ func (ui *UI) handlePropertyAction(property entities.Property) {
 		ui.UpdatePropertyUI(property)
 	case 2:
 		// Delete the selected property
-		err := ui.PropertyService.DeleteListedProperty(property.Title)
+		err := ui.PropertyService.DeleteListedProperty(ui.ctx, ui.session, property.ID)
 		if err != nil {
 			ui.displayError("deleting property :", err)
 		} else {
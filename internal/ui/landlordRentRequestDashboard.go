@@ -12,15 +12,8 @@ import (
 // RentRequestsDashboardForLandlord handles the dashboard for landlords to manage property rental requests.
 func (ui *UI) RentRequestsDashboardForLandlord() {
 
-	// Fetch the landlord's details based on the active user
-	landlord, err := ui.fetchLandlordDetails()
-	if err != nil {
-		fmt.Printf("\033[1;31mError fetching landlord details: %v\033[0m\n", err) // Red
-		return
-	}
-
-	// Fetch rental requests associated with the landlord
-	requests, err := ui.fetchRequestsForLandlord(landlord.Username)
+	// Fetch rental requests associated with the logged in landlord
+	requests, err := ui.fetchRequestsForLandlord()
 	if err != nil {
 		fmt.Printf("\033[1;31mError retrieving requests: %v\033[0m\n", err) // Red
 		return
@@ -49,6 +42,13 @@ func (ui *UI) RentRequestsDashboardForLandlord() {
 
 	req := requests[choice-1]
 
+	fmt.Println("\033[1;32m1. Change the Status\033[0m")
+	fmt.Println("\033[1;32m2. Message the Tenant\033[0m")
+	if utils.ReadInput("\nEnter your choice: ") == "2" {
+		ui.messageAboutRequest(req)
+		return
+	}
+
 	// Get the new status for the selected request
 	status := ui.getRequestStatusChoice()
 	if status == "" {
@@ -56,23 +56,17 @@ func (ui *UI) RentRequestsDashboardForLandlord() {
 	}
 
 	// Update the status of the selected request
-	err = ui.RequestService.UpdateRequestStatus(req, status)
+	err = ui.RequestService.UpdateRequestStatus(ui.ctx, ui.session, req.ID, status)
 	if err != nil {
 		fmt.Printf("\033[1;31mError updating request status: %v\033[0m\n", err) // Red
 	} else {
 		fmt.Println("\033[1;32mRequest status updated successfully.\033[0m") // Green
-		ui.updatePropertyRentalStatus(req, status)
 	}
 }
 
-// fetchLandlordDetails retrieves the details of the landlord from the user service.
-func (ui *UI) fetchLandlordDetails() (entities.User, error) {
-	return ui.UserService.FindByUsername(utils.ActiveUser)
-}
-
-// fetchRequestsForLandlord retrieves all property rental requests for a given landlord.
-func (ui *UI) fetchRequestsForLandlord(username string) ([]entities.Request, error) {
-	return ui.RequestService.GetRentRequestsInfoForLandlord(username)
+// fetchRequestsForLandlord retrieves all property rental requests for the logged in landlord.
+func (ui *UI) fetchRequestsForLandlord() ([]entities.Request, error) {
+	return ui.RequestService.GetRentRequestsInfoForLandlord(ui.ctx, ui.session)
 }
 
 // displayRequests prints the details of all rental requests to the console.
@@ -89,14 +83,14 @@ func (ui *UI) displayRequests(requests []entities.Request) {
 
 	for i, req := range requests {
 		// Fetch property details using PropertyID
-		property, err := ui.PropertyService.FindByID(req.PropertyID)
+		property, err := ui.PropertyService.FindByID(ui.ctx, req.PropertyID)
 		if err != nil {
 			fmt.Printf("\033[1;31mError fetching property details for request %d: %v\033[0m\n", i+1, err) // Red
 			continue
 		}
 
 		// Fetch tenant details using TenantName
-		tenant, err := ui.UserService.FindByUsername(req.TenantName)
+		tenant, err := ui.UserService.FindByUsername(ui.ctx, req.TenantName)
 		if err != nil {
 			fmt.Printf("\033[1;31mError fetching tenant details for request %d: %v\033[0m\n", i+1, err) // Red
 			continue
@@ -113,7 +107,7 @@ func (ui *UI) displayRequests(requests []entities.Request) {
 			tenant.PhoneNumber,
 			tenant.Email,
 			address,
-			req.RequestStatus,
+			string(req.RequestStatus),
 		})
 	}
 
@@ -131,28 +125,24 @@ func (ui *UI) getRequestActionChoice(maxOptions int) int {
 }
 
 // getRequestStatusChoice prompts the user to select the new status for the request.
-func (ui *UI) getRequestStatusChoice() string {
+// Pending requests can be accepted or rejected, accepted ones marked lease signed or cancelled.
+func (ui *UI) getRequestStatusChoice() entities.RequestStatus {
 
 	var statusChoice int
-	choiceTemp := utils.ReadInput("Enter new status (1 for Accepted, 2 for Rejected): ")
+	choiceTemp := utils.ReadInput("Enter new status (1 for Accepted, 2 for Rejected, 3 for Lease signed, 4 for Cancelled): ")
 	statusChoice, _ = strconv.Atoi(choiceTemp)
 
 	switch statusChoice {
 	case 1:
-		return "accepted"
+		return entities.RequestAccepted
 	case 2:
-		return "rejected"
+		return entities.RequestRejected
+	case 3:
+		return entities.RequestLeaseSigned
+	case 4:
+		return entities.RequestCancelled
 	default:
 		fmt.Println("\033[1;31mInvalid choice.\033[0m") // Red
 		return ""
 	}
 }
-
-// updatePropertyRentalStatus updates the property status to rented if the request is accepted.
-func (ui *UI) updatePropertyRentalStatus(req entities.Request, status string) {
-	if status == "accepted" {
-		prop, _ := ui.PropertyService.FindByID(req.PropertyID)
-		prop.IsRented = true
-		_ = ui.PropertyService.UpdateListedProperty(prop)
-	}
-}
@@ -0,0 +1,163 @@
+package ui
+
+import (
+	"fmt"
+	"github.com/olekukonko/tablewriter"
+	"os"
+	"rentease/internal/domain/entities"
+	"rentease/pkg/utils"
+	"strconv"
+)
+
+// ShowLeases lists the leases of the logged in user, as landlord of their properties or as tenant,
+// and lets them give notice on one, see its rent statement, save a copy of it or deal with its renewal.
+func (ui *UI) ShowLeases(asLandlord bool) {
+	var leases []entities.Lease
+	var err error
+	if asLandlord {
+		leases, err = ui.LeaseService.GetLeasesForLandlord(ui.ctx, ui.session)
+	} else {
+		leases, err = ui.LeaseService.GetLeasesForTenant(ui.ctx, ui.session)
+	}
+	if err != nil {
+		fmt.Printf("\033[1;31mError retrieving leases: %v\033[0m\n", err) // Red
+		return
+	}
+
+	if len(leases) == 0 {
+		fmt.Println("\033[1;33mNo leases yet.\033[0m") // Yellow
+		return
+	}
+
+	fmt.Println("\n\033[1;34mYour Leases\033[0m") // Blue
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"No.", "Property", "Tenant", "Landlord", "From", "To", "Monthly Rent", "Deposit", "Notice", "Status", "Renewal"})
+	table.SetAutoWrapText(false)
+
+	for i, lease := range leases {
+		// Show the title of the property, falling back to its ID when it is gone
+		title := lease.PropertyID.Hex()
+		if property, err := ui.PropertyService.FindByID(ui.ctx, lease.PropertyID); err == nil && property.Title != "" {
+			title = property.Title
+		}
+
+		table.Append([]string{
+			fmt.Sprintf("%d", i+1),
+			title,
+			lease.TenantName,
+			lease.LandlordName,
+			lease.StartDate.Format("02 Jan 2006"),
+			lease.EndDate.Format("02 Jan 2006"),
+			fmt.Sprintf("%.2f", lease.MonthlyRent),
+			fmt.Sprintf("%.2f", lease.Deposit),
+			fmt.Sprintf("%d days", lease.NoticePeriodDays),
+			string(lease.Status),
+			renewalText(lease.Renewal),
+		})
+	}
+
+	table.SetBorder(true)
+	table.Render()
+
+	ui.manageLease(leases, asLandlord)
+}
+
+func renewalText(offer *entities.RenewalOffer) string {
+	if offer == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%s: %.2f until %s", offer.Status, offer.MonthlyRent, offer.EndDate.Format("02 Jan 2006"))
+}
+
+// manageLease lets the user pick one of the leases and give notice on it, see its rent statement or save
+// a copy of the agreement, offer to renew it or record a payment as the landlord, or answer the renewal
+// offer as the tenant.
+func (ui *UI) manageLease(leases []entities.Lease, asLandlord bool) {
+	choiceTemp := utils.ReadInput("\nEnter the number of a lease to manage (or 0 to go back): ")
+	choice, err := strconv.Atoi(choiceTemp)
+	if err != nil || choice == 0 {
+		return
+	}
+	if choice < 1 || choice > len(leases) {
+		fmt.Println("\033[1;31mInvalid lease number.\033[0m") // Red
+		return
+	}
+	lease := leases[choice-1]
+
+	fmt.Println("\033[1;32m1. Give Notice\033[0m")
+	fmt.Println("\033[1;32m2. View Rent Statement\033[0m")
+	if asLandlord {
+		fmt.Println("\033[1;32m3. Offer Renewal\033[0m")
+		fmt.Println("\033[1;32m4. Record Rent Payment\033[0m")
+	} else {
+		fmt.Println("\033[1;32m3. Accept Renewal Offer\033[0m")
+		fmt.Println("\033[1;32m4. Decline Renewal Offer\033[0m")
+	}
+	fmt.Println("\033[1;32m5. Save Lease Agreement as PDF\033[0m")
+	fmt.Println("\033[1;32m6. Security Deposit\033[0m")
+	fmt.Println("\033[1;32m7. Rent Schedule and Rules\033[0m")
+	fmt.Println("\033[1;31m0. Go Back\033[0m")
+
+	switch utils.ReadInput("\nEnter your choice: ") {
+	case "1":
+		confirm := utils.ReadInput(fmt.Sprintf("The lease will end after %d days of notice, or on its end date if sooner. Continue? (y/n): ", lease.NoticePeriodDays))
+		if confirm != "y" && confirm != "Y" {
+			return
+		}
+		lease, err = ui.LeaseService.GiveNotice(ui.ctx, ui.session, lease.ID)
+	case "2":
+		ui.ShowStatement(lease.ID)
+		return
+	case "3":
+		if asLandlord {
+			lease, err = ui.offerRenewal(lease)
+		} else {
+			lease, err = ui.LeaseService.AnswerRenewal(ui.ctx, ui.session, lease.ID, true)
+		}
+	case "4":
+		if asLandlord {
+			ui.RecordPayment(lease.ID)
+			return
+		}
+		lease, err = ui.LeaseService.AnswerRenewal(ui.ctx, ui.session, lease.ID, false)
+	case "5":
+		document, err := ui.DocumentService.LeaseAgreement(ui.ctx, ui.session, lease.ID)
+		if err != nil {
+			ui.displayError("drawing up the lease agreement", err)
+			return
+		}
+		ui.saveDocument(document)
+		return
+	case "6":
+		ui.ManageDeposit(lease, asLandlord)
+		return
+	case "7":
+		ui.ShowSchedule(lease, asLandlord)
+		return
+	default:
+		return
+	}
+	if err != nil {
+		ui.displayError("updating lease", err)
+		return
+	}
+	fmt.Printf("\033[1;32mLease updated: %s, ends on %s.\033[0m\n", lease.Status, lease.EndDate.Format("02 Jan 2006")) // Green
+}
+
+// offerRenewal asks the landlord for the terms of the renewal and offers it to the tenant.
+func (ui *UI) offerRenewal(lease entities.Lease) (entities.Lease, error) {
+	rent := lease.MonthlyRent
+	rentTemp := utils.ReadInput(fmt.Sprintf("New monthly rent (enter to keep %.2f): ", rent))
+	if rentTemp != "" {
+		parsed, err := strconv.ParseFloat(rentTemp, 64)
+		if err != nil {
+			return entities.Lease{}, fmt.Errorf("invalid rent %q", rentTemp)
+		}
+		rent = parsed
+	}
+	months, err := strconv.Atoi(utils.ReadInput("Extend the lease by how many months: "))
+	if err != nil {
+		return entities.Lease{}, fmt.Errorf("invalid number of months")
+	}
+	return ui.LeaseService.OfferRenewal(ui.ctx, ui.session, lease.ID, rent, months)
+}
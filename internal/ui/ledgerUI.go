@@ -0,0 +1,259 @@
+package ui
+
+import (
+	"fmt"
+	"github.com/olekukonko/tablewriter"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"os"
+	"rentease/internal/app/services"
+	"rentease/internal/domain/entities"
+	"rentease/pkg/utils"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ShowStatement prints the rent statement of a lease: every month's rent with what was paid against it,
+// the payments received and what is outstanding. The user can then save the receipt of a payment.
+func (ui *UI) ShowStatement(leaseID primitive.ObjectID) {
+	statement, err := ui.LedgerService.Statement(ui.ctx, ui.session, leaseID)
+	if err != nil {
+		ui.displayError("retrieving the rent statement", err)
+		return
+	}
+
+	fmt.Printf("\n\033[1;34mRent Statement as of %s\033[0m\n", statement.AsOf.Format("02 Jan 2006")) // Blue
+	if len(statement.Lines) == 0 {
+		fmt.Println("\033[1;33mNo rent is due yet.\033[0m") // Yellow
+	} else {
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Month", "Due On", "Rent", "Paid", "Balance", "Paid On", "Days Late", "Late Fee"})
+		table.SetAutoWrapText(false)
+		for _, line := range statement.Lines {
+			paidOn := "-"
+			if line.PaidOn != nil {
+				paidOn = line.PaidOn.Format("02 Jan 2006")
+			}
+			table.Append([]string{
+				fmt.Sprintf("%d", line.Due.Period),
+				line.Due.DueDate.Format("02 Jan 2006"),
+				fmt.Sprintf("%.2f", line.Due.Amount),
+				fmt.Sprintf("%.2f", line.Paid),
+				fmt.Sprintf("%.2f", line.Balance),
+				paidOn,
+				fmt.Sprintf("%d", line.LateDays),
+				fmt.Sprintf("%.2f", line.LateFee),
+			})
+		}
+		table.SetFooter([]string{"", "Total", fmt.Sprintf("%.2f", statement.TotalDue), fmt.Sprintf("%.2f", statement.TotalPaid), fmt.Sprintf("%.2f", statement.Outstanding), "", "", fmt.Sprintf("%.2f", statement.LateFees)})
+		table.SetBorder(true)
+		table.Render()
+	}
+
+	if len(statement.Payments) > 0 {
+		fmt.Println("\n\033[1;34mPayments Received\033[0m") // Blue
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"No.", "Paid On", "Amount", "Mode", "Reference"})
+		table.SetAutoWrapText(false)
+		for i, payment := range statement.Payments {
+			table.Append([]string{
+				fmt.Sprintf("%d", i+1),
+				payment.PaidOn.Format("02 Jan 2006"),
+				fmt.Sprintf("%.2f", payment.Amount),
+				string(payment.Mode),
+				payment.Reference,
+			})
+		}
+		table.SetBorder(true)
+		table.Render()
+	}
+
+	switch {
+	case statement.Outstanding > 0:
+		fmt.Printf("\033[1;31mOutstanding: %.2f\033[0m\n", statement.Outstanding) // Red
+	case statement.Outstanding < 0:
+		fmt.Printf("\033[1;32mPaid in advance: %.2f\033[0m\n", -statement.Outstanding) // Green
+	default:
+		fmt.Println("\033[1;32mAll rent due has been paid.\033[0m") // Green
+	}
+
+	if len(statement.Payments) > 0 {
+		ui.saveReceipt(leaseID, statement.Payments)
+	}
+}
+
+// saveReceipt lets the user pick one of the payments and saves its receipt as a PDF in the current directory.
+func (ui *UI) saveReceipt(leaseID primitive.ObjectID, payments []entities.Payment) {
+	choice, err := strconv.Atoi(utils.ReadInput("\nEnter a payment number to save its receipt as PDF (or 0 to go back): "))
+	if err != nil || choice == 0 {
+		return
+	}
+	if choice < 1 || choice > len(payments) {
+		fmt.Println("\033[1;31mInvalid payment number.\033[0m") // Red
+		return
+	}
+	document, err := ui.DocumentService.RentReceipt(ui.ctx, ui.session, leaseID, payments[choice-1].ID)
+	if err != nil {
+		ui.displayError("drawing up the receipt", err)
+		return
+	}
+	ui.saveDocument(document)
+}
+
+// saveDocument writes the document into the current directory under its own file name.
+func (ui *UI) saveDocument(document entities.Document) {
+	if err := os.WriteFile(document.FileName, document.Content, 0o644); err != nil {
+		ui.displayError("saving "+document.FileName, err)
+		return
+	}
+	fmt.Printf("\033[1;32mSaved %s.\033[0m\n", document.FileName) // Green
+}
+
+// RecordPayment asks the landlord for the details of a rent payment received for the lease and records it.
+func (ui *UI) RecordPayment(leaseID primitive.ObjectID) {
+	payment, ok := readPayment()
+	if !ok {
+		return
+	}
+	payment, err := ui.LedgerService.RecordPayment(ui.ctx, ui.session, leaseID, payment)
+	if err != nil {
+		ui.displayError("recording the payment", err)
+		return
+	}
+	fmt.Printf("\033[1;32mPayment of %.2f on %s recorded.\033[0m\n", payment.Amount, payment.PaidOn.Format("02 Jan 2006")) // Green
+}
+
+// readPayment asks for the amount, date, mode and reference of money received.
+func readPayment() (entities.Payment, bool) {
+	amount, err := strconv.ParseFloat(utils.ReadInput("Amount received: "), 64)
+	if err != nil {
+		fmt.Println("\033[1;31mInvalid amount.\033[0m") // Red
+		return entities.Payment{}, false
+	}
+
+	payment := entities.Payment{Amount: amount}
+	if dateTemp := utils.ReadInput("Paid on (YYYY-MM-DD, enter for today): "); dateTemp != "" {
+		payment.PaidOn, err = time.Parse("2006-01-02", dateTemp)
+		if err != nil {
+			fmt.Println("\033[1;31mInvalid date.\033[0m") // Red
+			return entities.Payment{}, false
+		}
+	}
+
+	modes := entities.PaymentModes()
+	for i, mode := range modes {
+		fmt.Printf("%d. %s\n", i+1, mode)
+	}
+	modeChoice, err := strconv.Atoi(utils.ReadInput("How was it paid: "))
+	if err != nil || modeChoice < 1 || modeChoice > len(modes) {
+		fmt.Println("\033[1;31mInvalid payment mode.\033[0m") // Red
+		return entities.Payment{}, false
+	}
+	payment.Mode = modes[modeChoice-1]
+	payment.Reference = strings.TrimSpace(utils.ReadInput("Reference (cheque number, transaction ID, enter for none): "))
+	return payment, true
+}
+
+// ShowSchedule prints the rent due in the year ahead under the rules of the lease. The landlord can then try
+// other rules, see the schedule under them, and set them on the lease.
+func (ui *UI) ShowSchedule(lease entities.Lease, asLandlord bool) {
+	schedule, err := ui.LedgerService.Schedule(ui.ctx, ui.session, lease.ID, nil, services.DefaultScheduleMonths)
+	if err != nil {
+		ui.displayError("retrieving the rent schedule", err)
+		return
+	}
+	printSchedule(schedule)
+	if !asLandlord || utils.ReadInput("\nChange the rent rules? (y/n): ") != "y" {
+		return
+	}
+
+	rules, ok := readRentRules()
+	if !ok {
+		return
+	}
+	schedule, err = ui.LedgerService.Schedule(ui.ctx, ui.session, lease.ID, &rules, services.DefaultScheduleMonths)
+	if err != nil {
+		ui.displayError("working out the rent schedule", err)
+		return
+	}
+	printSchedule(schedule)
+	if utils.ReadInput("\nSet these rules on the lease? (y/n): ") != "y" {
+		return
+	}
+	if _, err := ui.LeaseService.SetRentRules(ui.ctx, ui.session, lease.ID, rules); err != nil {
+		ui.displayError("setting the rent rules", err)
+		return
+	}
+	fmt.Println("\033[1;32mRent rules updated.\033[0m") // Green
+}
+
+// printSchedule prints the rules of a schedule and the rent due under them.
+func printSchedule(schedule entities.Schedule) {
+	fmt.Println("\n\033[1;34mRent Schedule\033[0m") // Blue
+	if e := schedule.Rules.Escalation; e != nil {
+		fmt.Printf("Rent raised by %.2f%% and %.2f every %d months\n", e.Percent, e.Amount, e.EveryMonths)
+	}
+	if f := schedule.Rules.LateFee; f != nil {
+		fmt.Printf("Late fee of %.2f plus %.2f a day after %d days of grace", f.Flat, f.PerDay, f.GraceDays)
+		if f.Max > 0 {
+			fmt.Printf(", at most %.2f", f.Max)
+		}
+		fmt.Println()
+	}
+	if len(schedule.Dues) == 0 {
+		fmt.Println("\033[1;33mNo more rent falls due under this lease.\033[0m") // Yellow
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Month", "Due On", "Rent", "Increase", "Late From", "Late Fee"})
+	table.SetAutoWrapText(false)
+	for _, due := range schedule.Dues {
+		lateFrom, lateFee := "-", "-"
+		if due.LateFrom != nil {
+			lateFrom, lateFee = due.LateFrom.Format("02 Jan 2006"), fmt.Sprintf("%.2f", due.LateFee)
+		}
+		table.Append([]string{
+			fmt.Sprintf("%d", due.Period),
+			due.DueDate.Format("02 Jan 2006"),
+			fmt.Sprintf("%.2f", due.Amount),
+			fmt.Sprintf("%.2f", due.Increase),
+			lateFrom,
+			lateFee,
+		})
+	}
+	table.SetBorder(true)
+	table.Render()
+}
+
+// readRentRules asks for the escalation and late fee rules; an empty answer leaves a rule out.
+func readRentRules() (entities.RentRules, bool) {
+	var rules entities.RentRules
+	if input := utils.ReadInput("Raise the rent by what percentage (enter for no escalation): "); input != "" {
+		percent, err := strconv.ParseFloat(input, 64)
+		if err != nil {
+			fmt.Println("\033[1;31mInvalid percentage.\033[0m") // Red
+			return rules, false
+		}
+		every, err := strconv.Atoi(utils.ReadInput("Every how many months: "))
+		if err != nil {
+			fmt.Println("\033[1;31mInvalid number of months.\033[0m") // Red
+			return rules, false
+		}
+		rules.Escalation = &entities.Escalation{Percent: percent, EveryMonths: every}
+	}
+	if input := utils.ReadInput("Late fee per day (enter for no late fee): "); input != "" {
+		perDay, err := strconv.ParseFloat(input, 64)
+		if err != nil {
+			fmt.Println("\033[1;31mInvalid late fee.\033[0m") // Red
+			return rules, false
+		}
+		grace, err := strconv.Atoi(utils.ReadInput("Days after the due date without a late fee: "))
+		if err != nil {
+			fmt.Println("\033[1;31mInvalid number of days.\033[0m") // Red
+			return rules, false
+		}
+		rules.LateFee = &entities.LateFee{GraceDays: grace, PerDay: perDay}
+	}
+	return rules, true
+}
@@ -38,6 +38,16 @@ func (ui *UI) ListPropertyUI() {
 		log.Fatalf("Invalid input, please enter a valid number: %v", err)
 	}
 
+	// Collect the security deposit, two months of rent unless the landlord asks for another amount
+	var deposit float64
+	if input := utils.ReadInput("Enter the security deposit (in rupees, enter for two months of rent): "); input != "" {
+		deposit, err = strconv.ParseFloat(input, 64)
+		if err != nil || deposit < 0 {
+			fmt.Println("Invalid deposit, using two months of rent.")
+			deposit = 0
+		}
+	}
+
 	// Determine property details based on the selected property type
 	var details interface{}
 	switch propertyType {
@@ -56,7 +66,7 @@ func (ui *UI) ListPropertyUI() {
 	}
 
 	// Retrieve active landlord's username
-	landlordUsername := utils.ActiveUser
+	landlordUsername := ui.session.Username()
 
 	// Create a new property entity with collected details
 	property := entities.Property{
@@ -64,6 +74,7 @@ func (ui *UI) ListPropertyUI() {
 		PropertyType:      propertyType,
 		Title:             title,
 		RentAmount:        rentAmount,
+		Deposit:           deposit,
 		Address:           address,
 		LandlordUsername:  landlordUsername,
 		IsRented:          false,
@@ -72,7 +83,7 @@ func (ui *UI) ListPropertyUI() {
 	}
 
 	// Save the property to the repository
-	err = ui.PropertyService.ListProperty(property)
+	err = ui.PropertyService.ListProperty(ui.ctx, ui.session, property)
 	if err != nil {
 		fmt.Println("\nError listing property:", err)
 	} else {
@@ -1,7 +1,10 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
+	"rentease/internal/app/services"
+	"rentease/internal/domain/entities"
 	"rentease/pkg/utils"
 	"rentease/pkg/validation"
 	"strconv"
@@ -34,21 +37,20 @@ func (ui *UI) LoginDashboard() {
 		}
 
 		// Check credentials
-		loginSuccessful, _ := ui.UserService.Login(username, password)
+		session, err := ui.UserService.Login(ui.ctx, username, password)
+		if err != nil && !errors.Is(err, services.ErrInvalidCredentials) {
+			fmt.Println("\033[1;31mError during login:\033[0m", err)
+			return
+		}
 
-		if loginSuccessful {
+		if session != nil {
 			fmt.Println("\033[1;32mLogin successful!\n\n\033[0m") // Green
 
-			// Checking for admin
-			user, err := ui.UserService.FindByUsername(username)
-			if err != nil {
-				fmt.Println("\033[1;31mError finding user :\033[0m", err)
-				return
-			}
-
-			utils.ActiveUserobject = user
+			ui.session = session
+			defer func() { ui.session = nil }()
 
-			if user.Role == "Admin" {
+			// Admins and moderators get the admin dashboard
+			if session.Can(entities.PermReviewProperties) {
 				ui.AdminDashboard()
 				return
 			} else {
@@ -0,0 +1,240 @@
+package ui
+
+import (
+	"fmt"
+	"github.com/olekukonko/tablewriter"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"os"
+	"rentease/internal/domain/entities"
+	"rentease/pkg/utils"
+	"strconv"
+	"strings"
+)
+
+// MaintenanceDashboard lets the tenant report an issue with a property they rent and follow up the
+// tickets they reported.
+func (ui *UI) MaintenanceDashboard() {
+	for {
+		fmt.Println("\n\033[1;34mMaintenance Requests\033[0m") // Blue
+		fmt.Println("\033[1;32m1. Report an Issue\033[0m")
+		fmt.Println("\033[1;32m2. View Your Tickets\033[0m")
+		fmt.Println("\033[1;31m0. Go Back\033[0m")
+
+		switch utils.ReadInput("\nEnter your choice: ") {
+		case "1":
+			ui.reportIssue()
+		case "2":
+			tickets, err := ui.MaintenanceService.TicketsForTenant(ui.ctx, ui.session)
+			if err != nil {
+				ui.displayError("retrieving your tickets", err)
+				continue
+			}
+			ui.manageTickets(tickets, false)
+		case "0":
+			return
+		default:
+			fmt.Println("\033[1;31mInvalid choice, please try again.\033[0m") // Red
+		}
+	}
+}
+
+// MaintenanceQueue shows the landlord the open tickets on their properties, overdue ones first and in red,
+// and lets them move one on or comment on it.
+func (ui *UI) MaintenanceQueue() {
+	tickets, err := ui.MaintenanceService.MaintenanceQueue(ui.ctx, ui.session)
+	if err != nil {
+		ui.displayError("retrieving the maintenance queue", err)
+		return
+	}
+	ui.manageTickets(tickets, true)
+}
+
+// reportIssue asks the tenant which lease the issue is under and what it is, and reports it.
+func (ui *UI) reportIssue() {
+	leases, err := ui.LeaseService.GetLeasesForTenant(ui.ctx, ui.session)
+	if err != nil {
+		ui.displayError("retrieving your leases", err)
+		return
+	}
+	var current []entities.Lease
+	for _, lease := range leases {
+		if lease.Status == entities.LeaseActive || lease.Status == entities.LeaseEnding {
+			current = append(current, lease)
+		}
+	}
+	if len(current) == 0 {
+		fmt.Println("\033[1;33mYou have no active lease to report an issue under.\033[0m") // Yellow
+		return
+	}
+
+	lease := current[0]
+	if len(current) > 1 {
+		for i, l := range current {
+			fmt.Printf("%d. Lease from %s of %s\n", i+1, l.StartDate.Format("02 Jan 2006"), ui.propertyTitle(l.PropertyID))
+		}
+		choice, err := strconv.Atoi(utils.ReadInput("\nWhich lease is the issue under: "))
+		if err != nil || choice < 1 || choice > len(current) {
+			fmt.Println("\033[1;31mInvalid lease number.\033[0m") // Red
+			return
+		}
+		lease = current[choice-1]
+	}
+
+	categories := entities.MaintenanceCategories()
+	for i, category := range categories {
+		fmt.Printf("%d. %s\n", i+1, category)
+	}
+	choice, err := strconv.Atoi(utils.ReadInput("\nWhat kind of issue is it: "))
+	if err != nil || choice < 1 || choice > len(categories) {
+		fmt.Println("\033[1;31mInvalid category.\033[0m") // Red
+		return
+	}
+	category := categories[choice-1]
+
+	priority := entities.MaintenancePriority(strings.ToLower(strings.TrimSpace(utils.ReadInput("Priority (low, medium, high, urgent; enter for medium): "))))
+	description := utils.ReadInput("Describe the issue: ")
+
+	ticket, err := ui.MaintenanceService.ReportIssue(ui.ctx, ui.session, lease.ID, category, priority, description)
+	if err != nil {
+		ui.displayError("reporting the issue", err)
+		return
+	}
+	fmt.Printf("\033[1;32mIssue reported. Your landlord should resolve it by %s.\033[0m\n", ticket.DueBy.Format("02 Jan 2006 15:04")) // Green
+}
+
+// manageTickets lists the tickets and lets the user pick one to follow up.
+func (ui *UI) manageTickets(tickets []entities.MaintenanceTicket, asLandlord bool) {
+	if len(tickets) == 0 {
+		fmt.Println("\033[1;33mNo maintenance tickets.\033[0m") // Yellow
+		return
+	}
+	printTickets(tickets)
+
+	choice, err := strconv.Atoi(utils.ReadInput("\nEnter the number of a ticket to open (or 0 to go back): "))
+	if err != nil || choice == 0 {
+		return
+	}
+	if choice < 1 || choice > len(tickets) {
+		fmt.Println("\033[1;31mInvalid ticket number.\033[0m") // Red
+		return
+	}
+	ui.followUpTicket(tickets[choice-1], asLandlord)
+}
+
+// followUpTicket prints the thread of a ticket and offers what the user can do with it next: the landlord
+// moves it forward, the tenant closes or reopens it once it is resolved, and both can comment.
+func (ui *UI) followUpTicket(ticket entities.MaintenanceTicket, asLandlord bool) {
+	printTicketThread(ticket)
+	if ticket.Status == entities.TicketClosed {
+		return
+	}
+
+	var moves []entities.TicketStatus
+	switch {
+	case asLandlord && ticket.Status == entities.TicketOpen:
+		moves = []entities.TicketStatus{entities.TicketAcknowledged, entities.TicketInProgress, entities.TicketResolved}
+	case asLandlord && ticket.Status == entities.TicketAcknowledged:
+		moves = []entities.TicketStatus{entities.TicketInProgress, entities.TicketResolved}
+	case asLandlord && ticket.Status == entities.TicketInProgress:
+		moves = []entities.TicketStatus{entities.TicketResolved}
+	case !asLandlord && ticket.Status == entities.TicketResolved:
+		moves = []entities.TicketStatus{entities.TicketClosed, entities.TicketOpen}
+	}
+	for i, to := range moves {
+		fmt.Printf("\033[1;32m%d. %s\033[0m\n", i+1, ticketMoveText(to))
+	}
+	fmt.Printf("\033[1;32m%d. Add a Comment\033[0m\n", len(moves)+1)
+	fmt.Println("\033[1;31m0. Go Back\033[0m")
+
+	choice, err := strconv.Atoi(utils.ReadInput("\nEnter your choice: "))
+	if err != nil || choice < 1 || choice > len(moves)+1 {
+		return
+	}
+	if choice == len(moves)+1 {
+		ticket, err = ui.MaintenanceService.AddComment(ui.ctx, ui.session, ticket.ID, utils.ReadInput("Comment: "))
+	} else {
+		note := utils.ReadInput("Note for the other party (enter for none): ")
+		ticket, err = ui.MaintenanceService.UpdateTicketStatus(ui.ctx, ui.session, ticket.ID, moves[choice-1], note)
+	}
+	if err != nil {
+		ui.displayError("updating the ticket", err)
+		return
+	}
+	fmt.Printf("\033[1;32mTicket updated: %s.\033[0m\n", ticket.Status) // Green
+}
+
+func ticketMoveText(to entities.TicketStatus) string {
+	switch to {
+	case entities.TicketAcknowledged:
+		return "Acknowledge"
+	case entities.TicketInProgress:
+		return "Start Work"
+	case entities.TicketResolved:
+		return "Mark Resolved"
+	case entities.TicketClosed:
+		return "Confirm Fixed and Close"
+	default:
+		return "Reopen, It Is Not Fixed"
+	}
+}
+
+// printTickets prints the tickets as a numbered table with the overdue ones in red.
+func printTickets(tickets []entities.MaintenanceTicket) {
+	fmt.Println("\n\033[1;34mMaintenance Tickets\033[0m") // Blue
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"No.", "Tenant", "Category", "Priority", "Status", "Reported", "Due By", "Issue"})
+	table.SetAutoWrapText(false)
+	for i, ticket := range tickets {
+		row := []string{
+			fmt.Sprintf("%d", i+1),
+			ticket.TenantName,
+			string(ticket.Category),
+			string(ticket.Priority),
+			string(ticket.Status),
+			ticket.CreatedAt.Format("02 Jan 2006"),
+			ticket.DueBy.Format("02 Jan 2006 15:04"),
+			shortText(ticket.Description, 40),
+		}
+		if ticket.Overdue {
+			row[4] += " (overdue)"
+			colors := make([]tablewriter.Colors, len(row))
+			for j := range colors {
+				colors[j] = tablewriter.Colors{tablewriter.FgRedColor}
+			}
+			table.Rich(row, colors)
+			continue
+		}
+		table.Append(row)
+	}
+	table.SetBorder(true)
+	table.Render()
+}
+
+// printTicketThread prints the issue as reported and the comments on it.
+func printTicketThread(ticket entities.MaintenanceTicket) {
+	fmt.Printf("\n\033[1;34m%s issue, %s priority: %s\033[0m\n", ticket.Category, ticket.Priority, ticket.Status) // Blue
+	fmt.Println(ticket.Description)
+	if ticket.Overdue {
+		fmt.Printf("\033[1;31mOverdue: it should have been resolved by %s.\033[0m\n", ticket.DueBy.Format("02 Jan 2006 15:04")) // Red
+	}
+	for _, comment := range ticket.Comments {
+		fmt.Printf("\033[1;36m[%s] %s:\033[0m %s\n", comment.PostedAt.Format("02 Jan 2006 15:04"), comment.Author, comment.Text) // Cyan
+	}
+}
+
+// propertyTitle gives the title of the property, falling back to its ID when it is gone.
+func (ui *UI) propertyTitle(id primitive.ObjectID) string {
+	if property, err := ui.PropertyService.FindByID(ui.ctx, id); err == nil && property.Title != "" {
+		return property.Title
+	}
+	return id.Hex()
+}
+
+// shortText cuts text to at most n characters for a table cell.
+func shortText(text string, n int) string {
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
+	}
+	return string(runes[:n-3]) + "..."
+}
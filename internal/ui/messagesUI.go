@@ -0,0 +1,152 @@
+package ui
+
+import (
+	"fmt"
+	"github.com/olekukonko/tablewriter"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"os"
+	"rentease/internal/domain/entities"
+	"rentease/pkg/utils"
+	"strconv"
+	"strings"
+)
+
+// MessagesDashboard lists the threads of the tenant, or those about the landlord's properties, with how
+// many messages are unread, and opens the one the user picks.
+func (ui *UI) MessagesDashboard(asLandlord bool) {
+	for {
+		threads, err := ui.MessageService.Threads(ui.ctx, ui.session)
+		if err != nil {
+			ui.displayError("retrieving your messages", err)
+			return
+		}
+		var mine []entities.Thread
+		for _, thread := range threads {
+			if (thread.LandlordName == ui.session.Username()) == asLandlord {
+				mine = append(mine, thread)
+			}
+		}
+		if len(mine) == 0 {
+			if asLandlord {
+				fmt.Println("\033[1;33mNo tenant has written to you yet.\033[0m") // Yellow
+			} else {
+				fmt.Println("\033[1;33mNo messages yet. Message a landlord from a search result, your wishlist or a rent request.\033[0m") // Yellow
+			}
+			return
+		}
+
+		fmt.Println("\n\033[1;34mMessages\033[0m") // Blue
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"No.", "Property", "With", "Last Message", "Unread"})
+		for i, thread := range mine {
+			with := thread.LandlordName
+			if asLandlord {
+				with = thread.TenantName
+			}
+			last := ""
+			if n := len(thread.Messages); n > 0 {
+				last = shortText(thread.Messages[n-1].Text, 40)
+			}
+			table.Append([]string{
+				strconv.Itoa(i + 1),
+				shortText(ui.propertyTitle(thread.PropertyID), 30),
+				with,
+				last,
+				strconv.Itoa(thread.UnreadFor(ui.session.Username())),
+			})
+		}
+		table.SetBorder(true)
+		table.Render()
+
+		choice, err := strconv.Atoi(utils.ReadInput("\nEnter the number of a conversation to open (or 0 to go back): "))
+		if err != nil || choice == 0 {
+			return
+		}
+		if choice < 1 || choice > len(mine) {
+			fmt.Println("\033[1;31mInvalid number.\033[0m") // Red
+			continue
+		}
+		ui.openThread(mine[choice-1].ID)
+	}
+}
+
+// printUnreadMessages tells the tenant, or the landlord, how many messages they have not read yet.
+// It prints nothing when there are none.
+func (ui *UI) printUnreadMessages(asLandlord bool) {
+	unread, err := ui.MessageService.UnreadMessages(ui.ctx, ui.session)
+	if err != nil {
+		return
+	}
+	count := unread.AsTenant
+	if asLandlord {
+		count = unread.AsLandlord
+	}
+	if count > 0 {
+		fmt.Printf("\033[1;33mYou have %d unread message(s).\033[0m\n", count) // Yellow
+	}
+}
+
+// openThread prints the messages of a thread, the new ones marked, and lets the user reply until they
+// enter nothing.
+func (ui *UI) openThread(threadID primitive.ObjectID) {
+	thread, err := ui.MessageService.ReadThread(ui.ctx, ui.session, threadID)
+	if err != nil {
+		ui.displayError("opening the conversation", err)
+		return
+	}
+	ui.printThread(thread, thread.UnreadFor(ui.session.Username()))
+	for {
+		text := strings.TrimSpace(utils.ReadInput("\nWrite a reply (or press enter to go back): "))
+		if text == "" {
+			return
+		}
+		thread, err = ui.MessageService.SendMessage(ui.ctx, ui.session, threadID, text)
+		if err != nil {
+			ui.displayError("sending the message", err)
+			continue
+		}
+		fmt.Println("\033[1;32mMessage sent.\033[0m") // Green
+	}
+}
+
+// contactLandlord asks the tenant for a message to the landlord of the property they are looking at.
+func (ui *UI) contactLandlord(prop entities.Property) {
+	text := strings.TrimSpace(utils.ReadInput("Your message to the landlord (or press enter to go back): "))
+	if text == "" {
+		return
+	}
+	thread, err := ui.MessageService.ContactLandlord(ui.ctx, ui.session, prop.ID, text)
+	if err != nil {
+		ui.displayError("sending the message", err)
+		return
+	}
+	fmt.Println("\033[1;32mMessage sent. Follow the conversation under Messages.\033[0m") // Green
+	ui.printThread(thread, 0)
+}
+
+// messageAboutRequest asks the tenant or landlord of a rent request for a message to the other party.
+func (ui *UI) messageAboutRequest(req entities.Request) {
+	text := strings.TrimSpace(utils.ReadInput("Your message (or press enter to go back): "))
+	if text == "" {
+		return
+	}
+	thread, err := ui.MessageService.MessageAboutRequest(ui.ctx, ui.session, req.ID, text)
+	if err != nil {
+		ui.displayError("sending the message", err)
+		return
+	}
+	fmt.Println("\033[1;32mMessage sent. Follow the conversation under Messages.\033[0m") // Green
+	ui.printThread(thread, 0)
+}
+
+// printThread prints the messages of a thread, oldest first, marking the last unread ones as new.
+func (ui *UI) printThread(thread entities.Thread, unread int) {
+	fmt.Printf("\n\033[1;34mConversation about %s\033[0m\n", ui.propertyTitle(thread.PropertyID)) // Blue
+	for i, message := range thread.Messages {
+		marker := ""
+		if i >= len(thread.Messages)-unread {
+			marker = " \033[1;33m(new)\033[0m" // Yellow
+		}
+		fmt.Printf("\033[1;36m[%s] %s:\033[0m %s%s\n", message.SentAt.Local().Format(visitTimeLayout), message.Sender, message.Text, marker) // Cyan
+	}
+}
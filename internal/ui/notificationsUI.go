@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"fmt"
+	"github.com/olekukonko/tablewriter"
+	"os"
+	"rentease/internal/domain/entities"
+	"rentease/pkg/utils"
+	"strconv"
+)
+
+// ShowNotifications is the inbox of the logged in user. It lists their notifications, newest first, and
+// lets them mark them read or clear them away.
+func (ui *UI) ShowNotifications() {
+	for {
+		notifications, err := ui.NotificationService.Notifications(ui.ctx, ui.session)
+		if err != nil {
+			ui.displayError("retrieving your notifications", err)
+			return
+		}
+		if len(notifications) == 0 {
+			fmt.Println("\033[1;33mNo notifications.\033[0m") // Yellow
+			return
+		}
+
+		fmt.Println("\n\033[1;34mNotifications\033[0m") // Blue
+		ui.printNotifications(notifications)
+
+		fmt.Println("\033[1;32m1. Mark One as Read\033[0m")
+		fmt.Println("\033[1;32m2. Mark All as Read\033[0m")
+		fmt.Println("\033[1;32m3. Delete One\033[0m")
+		fmt.Println("\033[1;32m4. Clear the Read Ones\033[0m")
+		fmt.Println("\033[1;31m0. Go Back\033[0m")
+
+		switch utils.ReadInput("\nEnter your choice: ") {
+		case "0":
+			return
+		case "1":
+			if notification, ok := pickNotification(notifications); ok {
+				if err := ui.NotificationService.MarkNotificationRead(ui.ctx, ui.session, notification.ID); err != nil {
+					ui.displayError("marking the notification read", err)
+				}
+			}
+		case "2":
+			if _, err := ui.NotificationService.MarkAllNotificationsRead(ui.ctx, ui.session); err != nil {
+				ui.displayError("marking the notifications read", err)
+			}
+		case "3":
+			if notification, ok := pickNotification(notifications); ok {
+				if err := ui.NotificationService.DeleteNotification(ui.ctx, ui.session, notification.ID); err != nil {
+					ui.displayError("deleting the notification", err)
+				}
+			}
+		case "4":
+			cleared, err := ui.NotificationService.ClearReadNotifications(ui.ctx, ui.session)
+			if err != nil {
+				ui.displayError("clearing the notifications", err)
+				continue
+			}
+			fmt.Printf("\033[1;32m%d notification(s) cleared.\033[0m\n", cleared) // Green
+		default:
+			fmt.Println("\033[1;31mInvalid choice, please try again.\033[0m") // Red
+		}
+	}
+}
+
+// printUnreadNotifications prints how many notifications the user has not read under the dashboard
+// heading. It prints nothing when there are none.
+func (ui *UI) printUnreadNotifications() {
+	unread, err := ui.NotificationService.UnreadNotifications(ui.ctx, ui.session)
+	if err != nil || unread == 0 {
+		return
+	}
+	fmt.Printf("\033[1;33mYou have %d unread notification(s).\033[0m\n", unread) // Yellow
+}
+
+// printNotifications prints the notifications as a numbered table, marking the unread ones.
+func (ui *UI) printNotifications(notifications []entities.Notification) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"No.", "When", "Notification", "Property", ""})
+	table.SetAutoWrapText(false)
+	for i, notification := range notifications {
+		property := ""
+		// A rejected listing has been removed, its title is in the text
+		if !notification.PropertyID.IsZero() && notification.Kind != entities.NotificationPropertyRejected {
+			property = shortText(ui.propertyTitle(notification.PropertyID), 30)
+		}
+		status := ""
+		if !notification.Read {
+			status = "new"
+		}
+		table.Append([]string{
+			strconv.Itoa(i + 1),
+			notification.CreatedAt.Local().Format(visitTimeLayout),
+			notification.Text,
+			property,
+			status,
+		})
+	}
+	table.SetBorder(true)
+	table.Render()
+}
+
+// pickNotification asks for the number of one of the notifications.
+func pickNotification(notifications []entities.Notification) (entities.Notification, bool) {
+	choice, err := strconv.Atoi(utils.ReadInput("Enter the notification number: "))
+	if err != nil || choice < 1 || choice > len(notifications) {
+		fmt.Println("\033[1;31mInvalid notification number.\033[0m") // Red
+		return entities.Notification{}, false
+	}
+	return notifications[choice-1], true
+}
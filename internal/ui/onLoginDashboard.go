@@ -11,16 +11,22 @@ func init() {
 }
 func (ui *UI) onLoginDashboard() {
 	for {
+		if ui.sessionExpired() {
+			return
+		}
+
 		// Display the dashboard
 		fmt.Println()
 		fmt.Println("\033[1;36m-----------------------------------------------\033[0m")       // Sky blue
 		fmt.Println("\033[1;35m                DASHBOARD                            \033[0m") // Red bold
 		fmt.Println("\033[1;36m-----------------------------------------------\033[0m")       // Sky blue
+		ui.printUnreadNotifications()
 
 		fmt.Println("\033[1;32m	1. LandLord Section\033[0m") // Green
 		fmt.Println("\033[1;32m	2. Tenant Section\033[0m")   // Green
 		fmt.Println("\033[1;32m	3. View Profile\033[0m")     // Green
-		fmt.Println("\033[1;31m	4. Logout\033[0m")           // Red
+		fmt.Println("\033[1;32m	4. Notifications\033[0m")    // Green
+		fmt.Println("\033[1;31m	5. Logout\033[0m")           // Red
 
 		var choice int
 		choiceTemp := utils.ReadInput("\nEnter your choice: ")
@@ -44,6 +50,10 @@ func (ui *UI) onLoginDashboard() {
 			ui.userProfile()
 
 		case 4:
+			// The inbox of the logged in user
+			ui.ShowNotifications()
+
+		case 5:
 			// Logging out of the account
 			fmt.Println("\033[1;32m\nYou have been logged out.\033[0m") // Green
 			return
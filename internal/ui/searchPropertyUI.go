@@ -68,7 +68,7 @@ func (ui *UI) SearchPropertyUI() {
 	}
 
 	// Search for properties based on the criteria
-	properties, err := ui.PropertyService.SearchProperties(address.Area, address.City, address.State, pincode, propertyType)
+	properties, err := ui.PropertyService.SearchProperties(ui.ctx, address.Area, address.City, address.State, pincode, propertyType)
 	if err != nil {
 		fmt.Printf("\033[1;31mError searching properties: %v\033[0m\n", err) // Red
 		return
@@ -124,7 +124,7 @@ func (ui *UI) handlePropertyActions(properties []entities.Property) {
 		utils.DisplayProperty(prop)
 
 		// Fetch landlord details
-		landlord, err := ui.UserService.FindByUsername(prop.LandlordUsername)
+		landlord, err := ui.UserService.FindByUsername(ui.ctx, prop.LandlordUsername)
 		if err != nil {
 			fmt.Printf("\033[1;31mError fetching landlord details: %v\033[0m\n", err) // Red
 			continue
@@ -156,8 +156,10 @@ func (ui *UI) performPropertyAction(prop entities.Property) string {
 		fmt.Println("\n\033[1;36mWhat would you like to do?\033[0m")
 		fmt.Println("1. Add to Wishlist")
 		fmt.Println("2. Request Property")
-		fmt.Println("3. View Another Property")
-		fmt.Println("4. Back to Tenant Dashboard")
+		fmt.Println("3. Book a Visit")
+		fmt.Println("4. Message the Landlord")
+		fmt.Println("5. View Another Property")
+		fmt.Println("6. Back to Tenant Dashboard")
 
 		var action int
 		actionTemp := utils.ReadInput("\nEnter your choice: ")
@@ -169,9 +171,13 @@ func (ui *UI) performPropertyAction(prop entities.Property) string {
 		case 2:
 			ui.handlePropertyRequest(prop)
 		case 3:
+			ui.bookVisit(prop)
+		case 4:
+			ui.contactLandlord(prop)
+		case 5:
 			// Break inner loop to return to property list
 			return "not exiting"
-		case 4:
+		case 6:
 			// Exit the entire action loop and go back to the main menu or previous screen
 			return "exiting"
 		default:
@@ -182,7 +188,7 @@ func (ui *UI) performPropertyAction(prop entities.Property) string {
 
 // handleAddToWishlist adds the selected property to the user's wishlist.
 func (ui *UI) handleAddToWishlist(propertyID primitive.ObjectID) {
-	err := ui.UserService.AddToWishlist(utils.ActiveUser, propertyID)
+	err := ui.UserService.AddToWishlist(ui.ctx, ui.session, propertyID)
 	if err != nil {
 		fmt.Printf("\033[1;31mError adding property to wishlist: %v\033[0m\n", err) // Red
 	} else {
@@ -192,14 +198,11 @@ func (ui *UI) handleAddToWishlist(propertyID primitive.ObjectID) {
 
 // handlePropertyRequest sends a request to rent the selected property.
 func (ui *UI) handlePropertyRequest(prop entities.Property) {
-	if utils.ActiveUser != prop.LandlordUsername {
-		err := ui.RequestService.CreateRentRequest(utils.ActiveUser, prop.ID, prop.LandlordUsername)
-		if err != nil {
-			fmt.Printf("\033[1;31mError requesting property: %v\033[0m\n", err) // Red
-		} else {
-			fmt.Println("\033[1;32mProperty request sent successfully.\033[0m") // Green
-		}
+	// The service refuses requests for own, unapproved or rented properties and repeated requests
+	err := ui.RequestService.CreateRentRequest(ui.ctx, ui.session, prop.ID)
+	if err != nil {
+		fmt.Printf("\033[1;31mError requesting property: %v\033[0m\n", err) // Red
 	} else {
-		fmt.Println("\033[1;31mYou cannot request your own property!\033[0m") // Red
+		fmt.Println("\033[1;32mProperty request sent successfully.\033[0m") // Green
 	}
 }
@@ -58,7 +58,7 @@ func (ui *UI) SignUpDashboard() {
 	}
 
 	// Call userService to save user
-	if err := ui.UserService.SignUp(user); err != nil {
+	if err := ui.UserService.SignUp(ui.ctx, user); err != nil {
 		fmt.Printf("\033[1;31mError signing up: %v\033[0m\n", err)
 		return
 	}
@@ -77,7 +77,7 @@ func (ui *UI) promptForUsername() string {
 		} else {
 			fmt.Println("\033[1;31mInvalid username.\nPlease enter a valid username.\n\033[0m")
 		}
-		user, _ := ui.UserService.FindByUsername(username)
+		user, _ := ui.UserService.FindByUsername(ui.ctx, username)
 		if user.Username != "" {
 			fmt.Println("This username already exists.")
 			valid = false
@@ -11,10 +11,16 @@ func (ui *UI) TenantDashboardUI() {
 		fmt.Println("\033[1;34m\n========================\033[0m") // Blue
 		fmt.Println("\033[1;34m   Tenant Dashboard\033[0m")        // Blue
 		fmt.Println("\033[1;34m========================\033[0m")   // Blue
+		ui.printUnreadMessages(false)
+		ui.printUpcomingVisits(false)
 		fmt.Println("1. Search Property")
 		fmt.Println("2. Your Wishlist")
 		fmt.Println("3. Your Rent Requests' Status")
-		fmt.Println("4. Go Back")
+		fmt.Println("4. Your Leases")
+		fmt.Println("5. Maintenance Requests")
+		fmt.Println("6. Your Visits")
+		fmt.Println("7. Messages")
+		fmt.Println("8. Go Back")
 
 		choice := utils.ReadInput("\nEnter your choice: ")
 
@@ -28,9 +34,21 @@ func (ui *UI) TenantDashboardUI() {
 			}
 
 		case "3":
-			ui.ShowNotifications()
+			ui.ShowRentRequests()
 
 		case "4":
+			ui.ShowLeases(false)
+
+		case "5":
+			ui.MaintenanceDashboard()
+
+		case "6":
+			ui.VisitsDashboard(false)
+
+		case "7":
+			ui.MessagesDashboard(false)
+
+		case "8":
 			fmt.Println("\033[1;32mLogging out...\033[0m") // Green
 			return
 		default:
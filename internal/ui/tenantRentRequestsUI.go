@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"fmt"
+	"github.com/olekukonko/tablewriter"
+	"log"
+	"os"
+	"rentease/internal/domain/entities"
+	"rentease/pkg/utils"
+	"strconv"
+)
+
+// ShowRentRequests shows the status of the tenant's rent requests and lets them withdraw one or message
+// the landlord about it.
+func (ui *UI) ShowRentRequests() {
+	requests, err := ui.RequestService.GetRentRequestsInfoForTenant(ui.ctx, ui.session)
+	if err != nil {
+		fmt.Printf("\033[1;31mError retrieving rent requests: %v\033[0m\n", err) // Red
+		return
+	}
+
+	if len(requests) == 0 {
+		fmt.Println("\033[1;33mNo rent requests.\033[0m") // Yellow
+		return
+	}
+
+	fmt.Println("\n\033[1;34mYour Property Requests\033[0m") // Blue
+	var properties []entities.Property
+	for _, req := range requests {
+
+		property, err := ui.PropertyService.FindByID(ui.ctx, req.PropertyID)
+		if err != nil {
+			log.Println("\033[1;31mError finding property by id: \033[0m\n", err)
+		}
+		properties = append(properties, property)
+
+	}
+	ui.DisplayRentRequestStatusToTenant(properties, requests)
+
+	fmt.Println("\033[1;32m1. Withdraw a Request\033[0m")
+	fmt.Println("\033[1;32m2. Message the Landlord about a Request\033[0m")
+	fmt.Println("\033[1;31m0. Go Back\033[0m")
+	switch utils.ReadInput("\nEnter your choice: ") {
+	case "1":
+		ui.withdrawRequest(requests)
+	case "2":
+		choice, err := strconv.Atoi(utils.ReadInput("Enter the request number: "))
+		if err != nil || choice < 1 || choice > len(requests) {
+			fmt.Println("\033[1;31mInvalid request number.\033[0m") // Red
+			return
+		}
+		ui.messageAboutRequest(requests[choice-1])
+	}
+}
+
+func (ui *UI) DisplayRentRequestStatusToTenant(properties []entities.Property, requests []entities.Request) {
+	// Create a new table writer
+	table := tablewriter.NewWriter(os.Stdout)
+
+	// Set the header for the table
+	table.SetHeader([]string{"No.", "Title", "Rent Amount", "Address", "Request Status"})
+
+	// Set column width and auto-wrap
+	table.SetColMinWidth(3, 50) // Minimum width for "Address" column
+	table.SetAutoWrapText(false)
+
+	// Populate the table with property data, numbered like the requests
+	for i, property := range properties {
+		if property.Address.Pincode != 0 && property.Title != "" {
+			address := fmt.Sprintf("%s, %s, %s, %d", property.Address.Area, property.Address.City, property.Address.State, property.Address.Pincode)
+			requestStatus := "N/A"
+			if requests != nil && i < len(requests) {
+				requestStatus = string(requests[i].RequestStatus)
+				if reason := requests[i].StatusReason(); reason != "" {
+					requestStatus += " (" + reason + ")"
+				}
+			}
+
+			// Append data to the table
+			table.Append([]string{
+				fmt.Sprintf("%d", i+1),
+				property.Title,
+				fmt.Sprintf("%.2f", property.RentAmount),
+				address,
+				requestStatus,
+			})
+		}
+	}
+
+	// Render the table
+	table.SetBorder(true)
+	table.Render()
+}
+
+// withdrawRequest lets the tenant take back one of their pending requests.
+func (ui *UI) withdrawRequest(requests []entities.Request) {
+	choiceTemp := utils.ReadInput("\nEnter the number of a pending request to withdraw (or 0 to go back): ")
+	choice, err := strconv.Atoi(choiceTemp)
+	if err != nil || choice == 0 {
+		return
+	}
+	if choice < 1 || choice > len(requests) {
+		fmt.Println("\033[1;31mInvalid request number.\033[0m") // Red
+		return
+	}
+
+	req := requests[choice-1]
+	if req.RequestStatus != entities.RequestPending {
+		fmt.Printf("\033[1;31mOnly pending requests can be withdrawn, this one is %s.\033[0m\n", req.RequestStatus) // Red
+		return
+	}
+	if err := ui.RequestService.WithdrawRequest(ui.ctx, ui.session, req.ID); err != nil {
+		fmt.Printf("\033[1;31mError withdrawing request: %v\033[0m\n", err) // Red
+		return
+	}
+	fmt.Println("\033[1;32mRequest withdrawn successfully.\033[0m") // Green
+}
@@ -1,21 +1,60 @@
 package ui
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	"rentease/internal/app/services"
+	"rentease/internal/domain/entities"
 )
 
-// UI struct holds the UserService, PropertyService and RequestService
+// UI struct holds the UserService, PropertyService, RequestService, LeaseService, LedgerService, DepositService,
+// MaintenanceService, VisitService, MessageService, NotificationService and DocumentService
 type UI struct {
-	UserService     *services.UserService
-	PropertyService *services.PropertyService
-	RequestService  *services.RequestService
+	UserService         *services.UserService
+	PropertyService     *services.PropertyService
+	RequestService      *services.RequestService
+	LeaseService        *services.LeaseService
+	LedgerService       *services.LedgerService
+	DepositService      *services.DepositService
+	MaintenanceService  *services.MaintenanceService
+	VisitService        *services.VisitService
+	MessageService      *services.MessageService
+	NotificationService *services.NotificationService
+	DocumentService     *services.DocumentService
+
+	// ctx is passed to every service call made from the dashboards
+	ctx context.Context
+
+	// session of the logged in user, nil when nobody is logged in
+	session *entities.Session
 }
 
-// NewUI initializes the UI with the provided services
-func NewUI(userService *services.UserService, propertyService *services.PropertyService, requestService *services.RequestService) *UI {
+// NewUI initializes the UI with the provided services.
+// ctx is used for all service calls and should be cancelled on shutdown.
+func NewUI(ctx context.Context, userService *services.UserService, propertyService *services.PropertyService, requestService *services.RequestService, leaseService *services.LeaseService, ledgerService *services.LedgerService, depositService *services.DepositService, maintenanceService *services.MaintenanceService, visitService *services.VisitService, messageService *services.MessageService, notificationService *services.NotificationService, documentService *services.DocumentService) *UI {
 	return &UI{
-		UserService:     userService,
-		PropertyService: propertyService,
-		RequestService:  requestService,
+		UserService:         userService,
+		PropertyService:     propertyService,
+		RequestService:      requestService,
+		LeaseService:        leaseService,
+		LedgerService:       ledgerService,
+		DepositService:      depositService,
+		MaintenanceService:  maintenanceService,
+		VisitService:        visitService,
+		MessageService:      messageService,
+		NotificationService: notificationService,
+		DocumentService:     documentService,
+		ctx:                 ctx,
+	}
+}
+
+// sessionExpired reports whether the login session is over, telling the user to log in again.
+func (ui *UI) sessionExpired() bool {
+	if ui.session != nil && !ui.session.IsExpired(time.Now()) {
+		return false
 	}
+	fmt.Println("\033[1;31mYour session has expired. Please log in again.\033[0m") // Red
+	return true
 }
@@ -22,6 +22,9 @@ func (ui *UI) UpdatePropertyUI(property entities.Property) {
 	// Update Rent Amount
 	ui.updateRentAmount(&updatedProperty)
 
+	// Update Security Deposit
+	ui.updateDeposit(&updatedProperty)
+
 	// Update Details based on Property Type
 	ui.updateDetails(&updatedProperty)
 
@@ -31,7 +34,7 @@ func (ui *UI) UpdatePropertyUI(property entities.Property) {
 	}
 
 	// Save updated property
-	if err := ui.PropertyService.UpdateListedProperty(updatedProperty); err != nil {
+	if err := ui.PropertyService.UpdateListedProperty(ui.ctx, ui.session, updatedProperty); err != nil {
 		fmt.Printf("\033[1;31mError updating property: %v\033[0m\n", err)
 	} else {
 		fmt.Println("\033[1;32mProperty updated successfully.\033[0m")
@@ -77,6 +80,20 @@ func (ui *UI) updateRentAmount(property *entities.Property) {
 	}
 }
 
+// updateDeposit updates the security deposit asked for the property.
+func (ui *UI) updateDeposit(property *entities.Property) {
+	fmt.Println("\nCurrent security deposit (0 for two months of rent):", property.Deposit)
+	newDepositStr := utils.ReadInput("Enter new security deposit (leave blank to skip): ")
+	if newDepositStr != "" {
+		var newDeposit float64
+		if _, err := fmt.Sscanf(newDepositStr, "%f", &newDeposit); err == nil && newDeposit >= 0 {
+			property.Deposit = newDeposit
+		} else {
+			fmt.Println("\033[1;31mInvalid security deposit format.\033[0m") // Red
+		}
+	}
+}
+
 // updateDetails updates the details of the property based on its type.
 func (ui *UI) updateDetails(property *entities.Property) {
 	switch property.Details.(type) {
@@ -3,7 +3,6 @@ package ui
 import (
 	"fmt"
 	"rentease/internal/domain/entities"
-	"rentease/pkg/utils"
 )
 
 func (ui *UI) userProfile() {
@@ -13,19 +12,19 @@ func (ui *UI) userProfile() {
 	fmt.Println("\033[1;35m                          YOUR PROFILE                           \033[0m") // Red bold
 	fmt.Println("\033[1;36m----------------------------------------------------------------\033[0m")
 	fmt.Println()
-	ui.DisplayUserInfo(utils.ActiveUserobject)
+	ui.DisplayUserInfo(ui.session.User)
 	fmt.Println("\n\033[1;36m----------------------------------------------------------------\033[0m")
 	fmt.Println("\nYour listed properties are :")
 	ui.viewAndManageListedProperties(true)
 
 }
 
-func (ui *UI) DisplayUserInfo(ActiveUserobject entities.User) {
-	fmt.Println("		Username     : ", ActiveUserobject.Username)
-	fmt.Println("		Full Name    : ", ActiveUserobject.Name)
-	fmt.Println("		Age          : ", ActiveUserobject.Age)
-	fmt.Println("		Address      : ", ActiveUserobject.Address)
-	fmt.Println("		Phone Number : ", ActiveUserobject.PhoneNumber)
-	fmt.Println("		Email        : ", ActiveUserobject.Email)
+func (ui *UI) DisplayUserInfo(user entities.User) {
+	fmt.Println("		Username     : ", user.Username)
+	fmt.Println("		Full Name    : ", user.Name)
+	fmt.Println("		Age          : ", user.Age)
+	fmt.Println("		Address      : ", user.Address)
+	fmt.Println("		Phone Number : ", user.PhoneNumber)
+	fmt.Println("		Email        : ", user.Email)
 
 }
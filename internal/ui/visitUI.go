@@ -0,0 +1,263 @@
+package ui
+
+import (
+	"fmt"
+	"github.com/olekukonko/tablewriter"
+	"os"
+	"rentease/internal/domain/entities"
+	"rentease/pkg/utils"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// visitTimeLayout is how visit times are entered and shown in the dashboards.
+const visitTimeLayout = "02 Jan 2006 15:04"
+
+// VisitsDashboard shows the upcoming visits of the tenant, or of the landlord's properties, and lets them
+// reschedule or cancel one. Landlords also offer new slots from here.
+func (ui *UI) VisitsDashboard(asLandlord bool) {
+	for {
+		fmt.Println("\n\033[1;34mProperty Visits\033[0m") // Blue
+		if asLandlord {
+			fmt.Println("\033[1;32m1. Offer Visit Slots\033[0m")
+			fmt.Println("\033[1;32m2. Upcoming Visits\033[0m")
+			fmt.Println("\033[1;32m3. Manage Slots of a Property\033[0m")
+		} else {
+			fmt.Println("\033[1;32m1. Upcoming Visits\033[0m")
+		}
+		fmt.Println("\033[1;31m0. Go Back\033[0m")
+
+		choice := utils.ReadInput("\nEnter your choice: ")
+		switch {
+		case choice == "0":
+			return
+		case asLandlord && choice == "1":
+			ui.publishSlots()
+		case asLandlord && choice == "2", !asLandlord && choice == "1":
+			ui.manageUpcomingVisits(asLandlord)
+		case asLandlord && choice == "3":
+			ui.managePropertySlots()
+		default:
+			fmt.Println("\033[1;31mInvalid choice, please try again.\033[0m") // Red
+		}
+	}
+}
+
+// printUpcomingVisits prints the next visits of the tenant, or to the landlord's properties, under the
+// dashboard heading. It prints nothing when there are none.
+func (ui *UI) printUpcomingVisits(asLandlord bool) {
+	var visits []entities.VisitSlot
+	var err error
+	if asLandlord {
+		visits, err = ui.VisitService.UpcomingVisitsForLandlord(ui.ctx, ui.session)
+	} else {
+		visits, err = ui.VisitService.UpcomingVisitsForTenant(ui.ctx, ui.session)
+	}
+	if err != nil || len(visits) == 0 {
+		return
+	}
+	fmt.Printf("\033[1;33mUpcoming visits (%d):\033[0m\n", len(visits)) // Yellow
+	for i, visit := range visits {
+		if i == 3 {
+			fmt.Printf("  ... and %d more\n", len(visits)-i)
+			break
+		}
+		who := visit.LandlordName
+		if asLandlord {
+			who = visit.TenantName
+		}
+		fmt.Printf("  %s  %s with %s\n", visit.Start.Local().Format(visitTimeLayout), ui.propertyTitle(visit.PropertyID), who)
+	}
+}
+
+// bookVisit shows the open slots of the property the tenant is looking at and books the one they pick.
+func (ui *UI) bookVisit(prop entities.Property) {
+	slots, err := ui.VisitService.VisitSlots(ui.ctx, ui.session, prop.ID)
+	if err != nil {
+		ui.displayError("retrieving visit slots", err)
+		return
+	}
+	if len(slots) == 0 {
+		fmt.Println("\033[1;33mThe landlord has not offered any times to visit this property yet.\033[0m") // Yellow
+		return
+	}
+	slot, ok := ui.pickSlot(slots, "Enter the number of the slot to book (or 0 to go back): ")
+	if !ok {
+		return
+	}
+	booked, err := ui.VisitService.BookVisit(ui.ctx, ui.session, slot.ID)
+	if err != nil {
+		ui.displayError("booking the visit", err)
+		return
+	}
+	fmt.Printf("\033[1;32mVisit booked for %s.\033[0m\n", booked.Start.Local().Format(visitTimeLayout)) // Green
+}
+
+// publishSlots asks the landlord for one of their properties and the times to offer for visiting it.
+func (ui *UI) publishSlots() {
+	prop, ok := ui.pickOwnProperty()
+	if !ok {
+		return
+	}
+	input := utils.ReadInput("Start times, separated by commas (e.g. " + time.Now().Add(24*time.Hour).Format(visitTimeLayout) + "): ")
+	var starts []time.Time
+	for _, item := range strings.Split(input, ",") {
+		start, err := time.ParseInLocation(visitTimeLayout, strings.TrimSpace(item), time.Local)
+		if err != nil {
+			fmt.Printf("\033[1;31mInvalid time %q; use the format %s.\033[0m\n", strings.TrimSpace(item), visitTimeLayout) // Red
+			return
+		}
+		starts = append(starts, start)
+	}
+	minutes := 0
+	if text := strings.TrimSpace(utils.ReadInput("Minutes per visit (enter for 30): ")); text != "" {
+		var err error
+		if minutes, err = strconv.Atoi(text); err != nil || minutes <= 0 {
+			fmt.Println("\033[1;31mInvalid number of minutes.\033[0m") // Red
+			return
+		}
+	}
+
+	slots, err := ui.VisitService.PublishSlots(ui.ctx, ui.session, prop.ID, starts, time.Duration(minutes)*time.Minute)
+	if err != nil {
+		ui.displayError("offering the slots", err)
+		return
+	}
+	fmt.Printf("\033[1;32m%d visit slot(s) offered for %s.\033[0m\n", len(slots), prop.Title) // Green
+}
+
+// managePropertySlots shows every slot of one of the landlord's properties and lets them withdraw one.
+func (ui *UI) managePropertySlots() {
+	prop, ok := ui.pickOwnProperty()
+	if !ok {
+		return
+	}
+	slots, err := ui.VisitService.VisitSlots(ui.ctx, ui.session, prop.ID)
+	if err != nil {
+		ui.displayError("retrieving visit slots", err)
+		return
+	}
+	if len(slots) == 0 {
+		fmt.Println("\033[1;33mNo visit slots offered for this property.\033[0m") // Yellow
+		return
+	}
+	slot, ok := ui.pickSlot(slots, "Enter the number of a slot to withdraw (or 0 to go back): ")
+	if !ok {
+		return
+	}
+	if _, err := ui.VisitService.CancelVisit(ui.ctx, ui.session, slot.ID); err != nil {
+		ui.displayError("withdrawing the slot", err)
+		return
+	}
+	fmt.Println("\033[1;32mSlot withdrawn.\033[0m") // Green
+}
+
+// manageUpcomingVisits lists the upcoming visits and lets the user move one to another slot or cancel it.
+func (ui *UI) manageUpcomingVisits(asLandlord bool) {
+	var visits []entities.VisitSlot
+	var err error
+	if asLandlord {
+		visits, err = ui.VisitService.UpcomingVisitsForLandlord(ui.ctx, ui.session)
+	} else {
+		visits, err = ui.VisitService.UpcomingVisitsForTenant(ui.ctx, ui.session)
+	}
+	if err != nil {
+		ui.displayError("retrieving your visits", err)
+		return
+	}
+	if len(visits) == 0 {
+		fmt.Println("\033[1;33mNo upcoming visits.\033[0m") // Yellow
+		return
+	}
+	visit, ok := ui.pickSlot(visits, "Enter the number of a visit to change (or 0 to go back): ")
+	if !ok {
+		return
+	}
+
+	fmt.Println("\033[1;32m1. Reschedule\033[0m")
+	fmt.Println("\033[1;32m2. Cancel the Visit\033[0m")
+	fmt.Println("\033[1;31m0. Go Back\033[0m")
+	switch utils.ReadInput("\nEnter your choice: ") {
+	case "1":
+		slots, err := ui.VisitService.VisitSlots(ui.ctx, ui.session, visit.PropertyID)
+		if err != nil {
+			ui.displayError("retrieving visit slots", err)
+			return
+		}
+		var open []entities.VisitSlot
+		for _, slot := range slots {
+			if slot.Status == entities.VisitOpen && slot.Start.After(time.Now()) {
+				open = append(open, slot)
+			}
+		}
+		if len(open) == 0 {
+			fmt.Println("\033[1;33mThere is no other open slot for this property.\033[0m") // Yellow
+			return
+		}
+		to, ok := ui.pickSlot(open, "Enter the number of the new slot (or 0 to go back): ")
+		if !ok {
+			return
+		}
+		moved, err := ui.VisitService.RescheduleVisit(ui.ctx, ui.session, visit.ID, to.ID)
+		if err != nil {
+			ui.displayError("rescheduling the visit", err)
+			return
+		}
+		fmt.Printf("\033[1;32mVisit moved to %s.\033[0m\n", moved.Start.Local().Format(visitTimeLayout)) // Green
+	case "2":
+		if _, err := ui.VisitService.CancelVisit(ui.ctx, ui.session, visit.ID); err != nil {
+			ui.displayError("cancelling the visit", err)
+			return
+		}
+		fmt.Println("\033[1;32mVisit cancelled.\033[0m") // Green
+	}
+}
+
+// pickOwnProperty lists the landlord's properties and gives the one they pick.
+func (ui *UI) pickOwnProperty() (entities.Property, bool) {
+	properties, err := ui.PropertyService.GetAllListedProperties(ui.ctx, ui.session.Username())
+	if err != nil {
+		ui.displayError("fetching listed properties", err)
+		return entities.Property{}, false
+	}
+	if len(properties) == 0 {
+		fmt.Println("\033[1;31mNo listed properties found.\033[0m") // Red
+		return entities.Property{}, false
+	}
+	utils.DisplayProperties(properties)
+	choice, err := strconv.Atoi(utils.ReadInput("\nEnter the property number (or 0 to go back): "))
+	if err != nil || choice < 1 || choice > len(properties) {
+		return entities.Property{}, false
+	}
+	return properties[choice-1], true
+}
+
+// pickSlot prints the slots as a numbered table and gives the one the user picks.
+func (ui *UI) pickSlot(slots []entities.VisitSlot, prompt string) (entities.VisitSlot, bool) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"No.", "Property", "Start", "End", "Status", "Tenant", "Landlord"})
+	for i, slot := range slots {
+		table.Append([]string{
+			strconv.Itoa(i + 1),
+			shortText(ui.propertyTitle(slot.PropertyID), 30),
+			slot.Start.Local().Format(visitTimeLayout),
+			slot.End.Local().Format("15:04"),
+			string(slot.Status),
+			slot.TenantName,
+			slot.LandlordName,
+		})
+	}
+	table.SetBorder(true)
+	table.Render()
+
+	choice, err := strconv.Atoi(utils.ReadInput("\n" + prompt))
+	if err != nil || choice == 0 {
+		return entities.VisitSlot{}, false
+	}
+	if choice < 1 || choice > len(slots) {
+		fmt.Println("\033[1;31mInvalid number.\033[0m") // Red
+		return entities.VisitSlot{}, false
+	}
+	return slots[choice-1], true
+}
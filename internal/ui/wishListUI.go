@@ -13,7 +13,7 @@ import (
 // ShowWishlist displays the properties in the currently active user's wishlist.
 func (ui *UI) ShowWishlist() error {
 	// Fetch the user details from the UserService
-	user, err := ui.UserService.FindByUsername(utils.ActiveUser)
+	user, err := ui.UserService.FindByUsername(ui.ctx, ui.session.Username())
 	if err != nil {
 		return err
 	}
@@ -44,7 +44,7 @@ func (ui *UI) ShowWishlist() error {
 func (ui *UI) getPropertiesFromWishlist(wishlist []primitive.ObjectID) ([]entities.Property, error) {
 	var properties []entities.Property
 	for _, propertyID := range wishlist {
-		prop, err := ui.PropertyService.FindByID(propertyID)
+		prop, err := ui.PropertyService.FindByID(ui.ctx, propertyID)
 		if err != nil {
 			fmt.Printf("\033[1;31mError fetching property details: %v\033[0m\n", err) // Red
 			continue
@@ -58,7 +58,7 @@ func (ui *UI) getPropertiesFromWishlist(wishlist []primitive.ObjectID) ([]entiti
 func (ui *UI) handleWishlistActions(user entities.User, properties []entities.Property) error {
 	for {
 		var choice int
-		choiceTemp := utils.ReadInput("\nEnter the property number to see more details (or 0 to exit, -1 to remove a property, 1 to request a property): ")
+		choiceTemp := utils.ReadInput("\nEnter the property number to see more details (or 0 to exit, -1 to remove a property, -2 to message a landlord, 1 to request a property): ")
 		choice, _ = strconv.Atoi(choiceTemp)
 
 		switch choice {
@@ -75,6 +75,9 @@ func (ui *UI) handleWishlistActions(user entities.User, properties []entities.Pr
 				return err
 			}
 
+		case -2:
+			ui.messageLandlordFromWishlist(properties)
+
 		default:
 			if choice < 1 || choice > len(properties) {
 				fmt.Println("\033[1;31mInvalid property number.\033[0m") // Red
@@ -97,7 +100,7 @@ func (ui *UI) requestPropertyFromWishlist(user entities.User, properties []entit
 	}
 
 	prop := properties[choice-1]
-	err := ui.RequestService.CreateRentRequest(utils.ActiveUser, prop.ID, prop.LandlordUsername)
+	err := ui.RequestService.CreateRentRequest(ui.ctx, ui.session, prop.ID)
 	if err != nil {
 		fmt.Printf("\033[1;31mError creating property request: %v\033[0m\n", err) // Red
 		return err
@@ -105,7 +108,7 @@ func (ui *UI) requestPropertyFromWishlist(user entities.User, properties []entit
 
 	// Remove the property from the wishlist
 	user.Wishlist = removePropertyFromList(user.Wishlist, prop.ID)
-	err = ui.UserService.UpdateUser(user)
+	err = ui.UserService.UpdateUser(ui.ctx, ui.session, user)
 	if err != nil {
 		fmt.Printf("\033[1;31mError updating user wishlist: %v\033[0m\n", err) // Red
 		return err
@@ -115,6 +118,19 @@ func (ui *UI) requestPropertyFromWishlist(user entities.User, properties []entit
 	return nil
 }
 
+// messageLandlordFromWishlist asks which wishlist property to ask its landlord about and sends the message.
+func (ui *UI) messageLandlordFromWishlist(properties []entities.Property) {
+	var choice int
+	choiceTemp := utils.ReadInput("Enter the property number to message its landlord: ")
+	choice, _ = strconv.Atoi(choiceTemp)
+
+	if choice < 1 || choice > len(properties) {
+		fmt.Println("\033[1;31mInvalid property number.\033[0m") // Red
+		return
+	}
+	ui.contactLandlord(properties[choice-1])
+}
+
 // removePropertyFromWishlist removes a property ID from the wishlist.
 func removePropertyFromList(wishlist []primitive.ObjectID, propertyID primitive.ObjectID) []primitive.ObjectID {
 	for i, id := range wishlist {
@@ -138,7 +154,7 @@ func (ui *UI) removePropertyFromWishlist(user entities.User, properties []entiti
 
 	prop := properties[choice-1]
 	user.Wishlist = removePropertyFromList(user.Wishlist, prop.ID)
-	err := ui.UserService.UpdateUser(user)
+	err := ui.UserService.UpdateUser(ui.ctx, ui.session, user)
 	if err != nil {
 		fmt.Printf("\033[1;31mError updating user wishlist: %v\033[0m\n", err) // Red
 		return err
@@ -154,7 +170,7 @@ func (ui *UI) displayPropertyDetails(prop entities.Property) {
 	utils.DisplayProperty(prop)
 	fmt.Println("Landlord Details are:")
 
-	landlord, err := ui.UserService.FindByUsername(prop.LandlordUsername)
+	landlord, err := ui.UserService.FindByUsername(ui.ctx, prop.LandlordUsername)
 	if err != nil {
 		fmt.Printf("\033[1;31mError fetching landlord details: %v\033[0m\n", err) // Red
 		return
@@ -0,0 +1,223 @@
+// Package pdf writes simple text documents as PDF. Only the standard Helvetica fonts are used, which
+// every PDF reader provides, so no fonts have to be embedded.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Style selects the font and size of a paragraph.
+type Style int
+
+const (
+	Body    Style = iota // Regular text
+	Title                // Large bold text at the top of a document
+	Heading              // Bold text starting a section
+	Small                // Fine print
+)
+
+type font struct {
+	name    string // Resource name of the font in the page
+	size    float64
+	leading float64 // Distance between the baselines of two lines
+}
+
+var fonts = map[Style]font{
+	Body:    {"F1", 10.5, 15},
+	Title:   {"F2", 17, 24},
+	Heading: {"F2", 12, 18},
+	Small:   {"F1", 8.5, 12},
+}
+
+// A4 in points, with 2 cm margins.
+const (
+	pageWidth  = 595.28
+	pageHeight = 841.89
+	margin     = 56.69
+	textWidth  = pageWidth - 2*margin
+)
+
+// Document is a PDF document being laid out, top to bottom, over as many pages as needed.
+type Document struct {
+	title string
+	pages []*bytes.Buffer // Content stream of each page
+	y     float64         // Baseline of the next line on the last page
+}
+
+// New starts an empty document with the given title, which readers show in the window title.
+func New(title string) *Document {
+	d := &Document{title: title}
+	d.newPage()
+	return d
+}
+
+func (d *Document) newPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.y = pageHeight - margin
+}
+
+// Text adds a paragraph, wrapping it at the page width. Line breaks in the text start new lines.
+func (d *Document) Text(style Style, text string) {
+	f := fonts[style]
+	for _, paragraph := range strings.Split(text, "\n") {
+		for _, line := range wrap(paragraph, f, style != Body && style != Small) {
+			d.line(f, line)
+		}
+	}
+}
+
+func (d *Document) line(f font, text string) {
+	if d.y-f.leading < margin {
+		d.newPage()
+	}
+	d.y -= f.leading
+	page := d.pages[len(d.pages)-1]
+	fmt.Fprintf(page, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", f.name, f.size, margin, d.y, escape(encode(text)))
+}
+
+// Space leaves the given number of points empty below the last line.
+func (d *Document) Space(points float64) {
+	d.y -= points
+	if d.y < margin {
+		d.newPage()
+	}
+}
+
+// Rule draws a thin horizontal line across the page below the last line.
+func (d *Document) Rule() {
+	d.Space(8)
+	page := d.pages[len(d.pages)-1]
+	fmt.Fprintf(page, "0.5 w %.2f %.2f m %.2f %.2f l S\n", margin, d.y, pageWidth-margin, d.y)
+	d.Space(4)
+}
+
+// Bytes returns the finished document.
+func (d *Document) Bytes() []byte {
+	var out bytes.Buffer
+	_, _ = d.WriteTo(&out)
+	return out.Bytes()
+}
+
+// WriteTo writes the finished document.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// Objects 1 to 5 are fixed; each page then takes a page and a content object
+	const firstPage = 6
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title (%s) /Producer (RentEase) /CreationDate (D:%s) >>", escape(encode(d.title)), time.Now().UTC().Format("20060102150405Z")))
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, firstPage+2*i+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	n, err := w.Write(out.Bytes())
+	return int64(n), err
+}
+
+// wrap splits the text into lines that fit the page width, breaking between words.
+func wrap(text string, f font, bold bool) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return []string{""}
+	}
+	var lines []string
+	current := words[0]
+	for _, word := range words[1:] {
+		if width(current+" "+word, f.size, bold) > textWidth {
+			lines = append(lines, current)
+			current = word
+			continue
+		}
+		current += " " + word
+	}
+	return append(lines, current)
+}
+
+// width estimates the width of the text in points from the Helvetica character widths.
+func width(text string, size float64, bold bool) float64 {
+	units := 0
+	for _, b := range encode(text) {
+		if b >= 32 && int(b-32) < len(helveticaWidths) {
+			units += helveticaWidths[b-32]
+		} else {
+			units += 556
+		}
+	}
+	w := float64(units) * size / 1000
+	if bold {
+		w *= 1.08 // Helvetica-Bold runs a little wider
+	}
+	return w
+}
+
+// helveticaWidths are the widths of the printable ASCII characters in Helvetica, in thousandths of the font size.
+var helveticaWidths = []int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space to /
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556, // 0 to ?
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778, // @ to O
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556, // P to _
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556, // ` to o
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584, // p to ~
+}
+
+// replacements spell out characters that WinAnsiEncoding lacks.
+var replacements = strings.NewReplacer("₹", "Rs.", "–", "-", "—", "-", "‘", "'", "’", "'", "“", "\"", "”", "\"", "…", "...")
+
+// encode converts the text to WinAnsiEncoding, which matches Latin-1 for the characters used here.
+// Characters it cannot show become question marks.
+func encode(text string) []byte {
+	text = replacements.Replace(text)
+	encoded := make([]byte, 0, len(text))
+	for _, r := range text {
+		if r > 0xff || (r < 32 && r != '\t') {
+			r = '?'
+		}
+		if r == '\t' {
+			r = ' '
+		}
+		encoded = append(encoded, byte(r))
+	}
+	return encoded
+}
+
+// escape quotes the text for a PDF string literal.
+func escape(text []byte) string {
+	var b strings.Builder
+	for _, c := range text {
+		switch c {
+		case '(', ')', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,104 @@
+package pdf
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// checkStructure verifies that the cross-reference table points at the objects it lists.
+func checkStructure(t *testing.T, out []byte) {
+	t.Helper()
+	text := string(out)
+	require.True(t, strings.HasPrefix(text, "%PDF-1.4\n"))
+	require.True(t, strings.HasSuffix(text, "%%EOF\n"))
+
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(text)
+	require.NotNil(t, startxref)
+	xref, err := strconv.Atoi(startxref[1])
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(text[xref:], "xref\n"))
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(text[xref:], -1)
+	require.NotEmpty(t, entries)
+	for i, entry := range entries {
+		offset, err := strconv.Atoi(entry[1])
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(text[offset:], fmt.Sprintf("%d 0 obj\n", i+1)), "object %d", i+1)
+	}
+
+	for _, stream := range regexp.MustCompile(`<< /Length (\d+) >>\nstream\n`).FindAllStringSubmatchIndex(text, -1) {
+		length, err := strconv.Atoi(text[stream[2]:stream[3]])
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(text[stream[1]+length:], "endstream"))
+	}
+}
+
+func TestDocument(t *testing.T) {
+	d := New("Rent (Receipt)")
+	d.Text(Title, "Rent Receipt")
+	d.Rule()
+	d.Text(Body, `Paid ₹ 1,500 by cheque (no. 12) to C:\rent`)
+	d.Space(10)
+	d.Text(Small, "Fine print")
+
+	out := d.Bytes()
+	checkStructure(t, out)
+	assert.Contains(t, string(out), "/Title (Rent \\(Receipt\\))")
+	assert.Contains(t, string(out), "/Count 1 >>")
+	assert.Contains(t, string(out), "(Rent Receipt) Tj")
+	assert.Contains(t, string(out), `(Paid Rs. 1,500 by cheque \(no. 12\) to C:\\rent) Tj`)
+	assert.Contains(t, string(out), "(Fine print) Tj")
+}
+
+func TestDocument_WrapsLongLines(t *testing.T) {
+	d := New("Wrap")
+	d.Text(Body, strings.Repeat("tenant ", 60))
+
+	out := string(d.Bytes())
+	lines := regexp.MustCompile(`\((tenant[^)]*)\) Tj`).FindAllStringSubmatch(out, -1)
+	require.Greater(t, len(lines), 1)
+	var joined []string
+	for _, line := range lines {
+		assert.LessOrEqual(t, width(line[1], fonts[Body].size, false), textWidth)
+		joined = append(joined, line[1])
+	}
+	assert.Equal(t, strings.TrimSpace(strings.Repeat("tenant ", 60)), strings.Join(joined, " "))
+}
+
+func TestDocument_BreaksPages(t *testing.T) {
+	d := New("Pages")
+	for i := 0; i < 120; i++ {
+		d.Text(Body, fmt.Sprintf("Line %d", i))
+	}
+
+	out := d.Bytes()
+	checkStructure(t, out)
+	assert.Contains(t, string(out), "/Count 3 >>")
+	assert.Contains(t, string(out), "(Line 119) Tj")
+}
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "ASCII", input: "Pune 411045", expected: "Pune 411045"},
+		{name: "Rupee sign", input: "₹500", expected: "Rs.500"},
+		{name: "Latin-1", input: "Café", expected: "Caf\xe9"},
+		{name: "Typographic quotes", input: "“lease” – tenant’s", expected: "\"lease\" - tenant's"},
+		{name: "Outside WinAnsi", input: "मकान", expected: "????"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, string(encode(tt.input)))
+		})
+	}
+}
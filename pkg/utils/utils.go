@@ -16,12 +16,6 @@ import (
 	"strings"
 )
 
-// Creating an active user (only username)
-var ActiveUser string
-
-// Creating an active uesr object
-var ActiveUserobject entities.User
-
 // ReadInput reads input from the user with a prompt.
 func ReadInput(prompt string) string {
 	reader1 := bufio.NewReader(os.Stdin)
@@ -110,10 +104,10 @@ func DisplayProperties(properties []entities.Property) {
 	// Add rows to the table
 	for i, property := range properties {
 		details := getPropertyDetails(property)
-		address := formatAddress(property.Address)
+		address := FormatAddress(property.Address)
 		table.Append([]string{
 			fmt.Sprintf("%d", i+1),
-			propertyTypeToString(property.PropertyType),
+			PropertyTypeToString(property.PropertyType),
 			property.Title,
 			address,
 			fmt.Sprintf("%.2f", property.RentAmount),
@@ -125,7 +119,8 @@ func DisplayProperties(properties []entities.Property) {
 	table.Render()
 }
 
-func propertyTypeToString(propertyType int) string {
+// PropertyTypeToString names the property type.
+func PropertyTypeToString(propertyType int) string {
 	switch propertyType {
 	case 1:
 		return "Commercial"
@@ -138,7 +133,8 @@ func propertyTypeToString(propertyType int) string {
 	}
 }
 
-func formatAddress(address entities.Address) string {
+// FormatAddress writes the address on one line.
+func FormatAddress(address entities.Address) string {
 	return fmt.Sprintf("%s, %s, %s, %d", address.Area, address.City, address.State, address.Pincode)
 }
 
@@ -203,7 +199,7 @@ func GetHiddenInput(prompt string) (string, error) {
 	fmt.Print(prompt)
 	bytePassword, err := term.ReadPassword(int(os.Stdin.Fd()))
 	if err != nil {
-		fmt.Errorf("Error reading password: " + err.Error())
+		fmt.Println("Error reading password:", err)
 		return "", err
 	}
 	fmt.Println() // Print a newline after input
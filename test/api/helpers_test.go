@@ -0,0 +1,219 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/api"
+	"rentease/internal/app/eventbus"
+	"rentease/internal/app/repositories"
+	"rentease/internal/app/services"
+	"rentease/internal/documents"
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+	"rentease/internal/mail"
+	"rentease/pkg/utils"
+)
+
+const testPassword = "Secret@123"
+
+// apiTest runs the API in process on top of the real services and in-memory repositories.
+type apiTest struct {
+	t        *testing.T
+	handler  http.Handler
+	userRepo interfaces.UserRepo
+	emails   *mail.MemorySender
+}
+
+func newAPITest(t *testing.T) *apiTest {
+	return newAPITestWithTTL(t, 15*time.Minute, time.Hour)
+}
+
+// newAPITestWithTTL is like newAPITest with the given lifetimes of access and refresh tokens.
+func newAPITestWithTTL(t *testing.T, accessTTL, refreshTTL time.Duration) *apiTest {
+	userRepo := repositories.NewInMemoryUserRepo()
+	propertyRepo := repositories.NewInMemoryPropertyRepo()
+	leaseRepo := repositories.NewInMemoryLeaseRepo()
+	ledgerRepo := repositories.NewInMemoryLedgerRepo()
+	requestRepo := repositories.NewInMemoryRequestRepo()
+	notificationRepo := repositories.NewInMemoryNotificationRepo()
+	refreshTokenRepo := repositories.NewInMemoryRefreshTokenRepo()
+	renderer, err := documents.NewRenderer("")
+	require.NoError(t, err)
+	templates, err := mail.LoadTemplates("")
+	require.NoError(t, err)
+	emails := mail.NewMemorySender()
+	emailNotifier := mail.NewNotifier("RentEase <no-reply@rentease.test>", templates, mail.NewQueue(emails, 100, 1, time.Millisecond), userRepo, propertyRepo)
+	t.Cleanup(func() { _ = emailNotifier.Close(context.Background()) })
+	bus := eventbus.New()
+	services.SubscribeNotifications(bus, notificationRepo)
+	services.SubscribeEmails(bus, emailNotifier)
+	handler := api.NewServer(
+		services.NewUserService(userRepo, propertyRepo, refreshTokenRepo, bus),
+		services.NewPropertyService(propertyRepo, bus),
+		services.NewRequestService(requestRepo, propertyRepo, leaseRepo, bus),
+		services.NewLeaseService(leaseRepo, propertyRepo, true),
+		services.NewLedgerService(ledgerRepo, leaseRepo),
+		services.NewDepositService(repositories.NewInMemoryDepositRepo(), leaseRepo),
+		services.NewMaintenanceService(repositories.NewInMemoryMaintenanceRepo(), leaseRepo),
+		services.NewVisitService(repositories.NewInMemoryVisitRepo(), propertyRepo),
+		services.NewMessageService(repositories.NewInMemoryMessageRepo(), propertyRepo, requestRepo),
+		services.NewNotificationService(notificationRepo),
+		services.NewDocumentService(leaseRepo, ledgerRepo, propertyRepo, userRepo, renderer),
+		services.NewTokenService(userRepo, refreshTokenRepo, []byte("test-secret-of-at-least-32-bytes"), accessTTL, refreshTTL),
+	)
+	return &apiTest{t: t, handler: handler, userRepo: userRepo, emails: emails}
+}
+
+// do sends the request and returns the recorded response. body is encoded as JSON unless nil.
+func (at *apiTest) do(method, path, token string, body interface{}) *httptest.ResponseRecorder {
+	at.t.Helper()
+	var payload bytes.Buffer
+	if body != nil {
+		require.NoError(at.t, json.NewEncoder(&payload).Encode(body))
+	}
+	req := httptest.NewRequest(method, path, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	at.handler.ServeHTTP(rec, req)
+	return rec
+}
+
+// decode decodes the JSON response body into v after checking the status.
+func (at *apiTest) decode(rec *httptest.ResponseRecorder, status int, v interface{}) {
+	at.t.Helper()
+	require.Equal(at.t, status, rec.Code, rec.Body.String())
+	if v != nil {
+		require.NoError(at.t, json.Unmarshal(rec.Body.Bytes(), v))
+	}
+}
+
+// requireError checks the status and error code of a failed request.
+func (at *apiTest) requireError(rec *httptest.ResponseRecorder, status int, code string) {
+	at.t.Helper()
+	var body struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	at.decode(rec, status, &body)
+	require.Equal(at.t, code, body.Error.Code)
+	require.NotEmpty(at.t, body.Error.Message)
+}
+
+// signUp creates a user through the API.
+func (at *apiTest) signUp(username string) {
+	at.t.Helper()
+	rec := at.do(http.MethodPost, "/api/v1/signup", "", map[string]interface{}{
+		"username":     username,
+		"password":     testPassword,
+		"name":         "Test " + username,
+		"age":          30,
+		"email":        username + "@example.com",
+		"phone_number": "9876543210",
+		"address":      "12 Test Street",
+	})
+	at.decode(rec, http.StatusCreated, nil)
+}
+
+// waitForEmails waits until n emails have been sent and returns them, oldest first.
+func (at *apiTest) waitForEmails(n int) []mail.Message {
+	at.t.Helper()
+	require.Eventually(at.t, func() bool { return len(at.emails.Messages()) >= n }, time.Second, time.Millisecond)
+	messages := at.emails.Messages()
+	require.Len(at.t, messages, n)
+	return messages
+}
+
+// addAdmin stores an admin directly, as admins cannot sign up.
+func (at *apiTest) addAdmin(username string) {
+	at.t.Helper()
+	hash, err := utils.HashPassword(testPassword)
+	require.NoError(at.t, err)
+	require.NoError(at.t, at.userRepo.SaveUser(context.Background(), entities.User{Username: username, PasswordHash: hash, Role: "Admin"}))
+}
+
+// tokens is the body of a successful login or token refresh.
+type tokens struct {
+	AccessToken      string         `json:"access_token"`
+	TokenType        string         `json:"token_type"`
+	ExpiresAt        time.Time      `json:"expires_at"`
+	RefreshToken     string         `json:"refresh_token"`
+	RefreshExpiresAt time.Time      `json:"refresh_expires_at"`
+	User             *entities.User `json:"user"`
+}
+
+// loginTokens logs the user in and returns the issued tokens.
+func (at *apiTest) loginTokens(username string) tokens {
+	at.t.Helper()
+	var body tokens
+	at.decode(at.do(http.MethodPost, "/api/v1/login", "", map[string]string{"username": username, "password": testPassword}), http.StatusOK, &body)
+	require.NotEmpty(at.t, body.AccessToken)
+	require.NotEmpty(at.t, body.RefreshToken)
+	return body
+}
+
+// login logs the user in and returns the bearer access token.
+func (at *apiTest) login(username string) string {
+	at.t.Helper()
+	return at.loginTokens(username).AccessToken
+}
+
+// refresh exchanges the refresh token for new tokens.
+func (at *apiTest) refresh(refreshToken string) *httptest.ResponseRecorder {
+	at.t.Helper()
+	return at.do(http.MethodPost, "/api/v1/token/refresh", "", map[string]string{"refresh_token": refreshToken})
+}
+
+// testHouse is the body of a house listing.
+func testHouse(title string) map[string]interface{} {
+	return map[string]interface{}{
+		"property_type": 2,
+		"title":         title,
+		"address":       map[string]interface{}{"area": "Suburb", "city": "Smalltown", "state": "Punjab", "pincode": 141001},
+		"rent_amount":   15000,
+		"details": map[string]interface{}{
+			"no_of_rooms":        3,
+			"furnished_category": "Semi-furnished",
+			"amenities":          []string{"Parking", "Garden"},
+		},
+	}
+}
+
+// listApprovedHouse lists a house as the landlord and has the admin approve it.
+func (at *apiTest) listApprovedHouse(landlordToken, adminToken, title string) primitive.ObjectID {
+	at.t.Helper()
+	var property struct {
+		ID primitive.ObjectID `json:"id"`
+	}
+	at.decode(at.do(http.MethodPost, "/api/v1/properties", landlordToken, testHouse(title)), http.StatusCreated, &property)
+	at.decode(at.do(http.MethodPost, "/api/v1/admin/properties/"+property.ID.Hex()+"/approve", adminToken, nil), http.StatusOK, nil)
+	return property.ID
+}
+
+// signedLease has the tenant ask to rent the property and the landlord accept and sign, and returns the lease.
+func (at *apiTest) signedLease(landlordToken, tenantToken string, propertyID primitive.ObjectID) entities.Lease {
+	at.t.Helper()
+	at.decode(at.do(http.MethodPost, "/api/v1/rent-requests", tenantToken, map[string]string{"property_id": propertyID.Hex()}), http.StatusCreated, nil)
+	var received []entities.Request
+	at.decode(at.do(http.MethodGet, "/api/v1/rent-requests/received", landlordToken, nil), http.StatusOK, &received)
+	require.NotEmpty(at.t, received)
+	statusPath := "/api/v1/rent-requests/" + received[len(received)-1].ID.Hex() + "/status"
+	at.decode(at.do(http.MethodPut, statusPath, landlordToken, map[string]string{"status": "accepted"}), http.StatusOK, nil)
+	at.decode(at.do(http.MethodPut, statusPath, landlordToken, map[string]string{"status": "lease-signed"}), http.StatusOK, nil)
+	var leases []entities.Lease
+	at.decode(at.do(http.MethodGet, "/api/v1/leases/as-tenant", tenantToken, nil), http.StatusOK, &leases)
+	require.NotEmpty(at.t, leases)
+	return leases[len(leases)-1]
+}
@@ -0,0 +1,1039 @@
+package api_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/app/services"
+	"rentease/internal/domain/entities"
+)
+
+func TestAPI_SignUpAndLogin(t *testing.T) {
+	at := newAPITest(t)
+	at.signUp("alice")
+
+	t.Run("Duplicate username", func(t *testing.T) {
+		rec := at.do(http.MethodPost, "/api/v1/signup", "", map[string]interface{}{
+			"username": "alice", "password": testPassword, "name": "Alice", "age": 30,
+			"email": "alice@example.com", "phone_number": "9876543210",
+		})
+		at.requireError(rec, http.StatusConflict, "conflict")
+	})
+
+	t.Run("Invalid signup", func(t *testing.T) {
+		rec := at.do(http.MethodPost, "/api/v1/signup", "", map[string]interface{}{
+			"username": "bob", "password": "weak", "name": "Bob", "age": 30,
+			"email": "bob@example.com", "phone_number": "9876543210",
+		})
+		at.requireError(rec, http.StatusBadRequest, "bad_request")
+	})
+
+	t.Run("Unknown field", func(t *testing.T) {
+		rec := at.do(http.MethodPost, "/api/v1/login", "", map[string]string{"user": "alice"})
+		at.requireError(rec, http.StatusBadRequest, "bad_request")
+	})
+
+	t.Run("Wrong password", func(t *testing.T) {
+		rec := at.do(http.MethodPost, "/api/v1/login", "", map[string]string{"username": "alice", "password": "Wrong@1234"})
+		at.requireError(rec, http.StatusUnauthorized, "unauthorized")
+	})
+
+	t.Run("Me", func(t *testing.T) {
+		token := at.login("alice")
+		var user map[string]interface{}
+		at.decode(at.do(http.MethodGet, "/api/v1/me", token, nil), http.StatusOK, &user)
+		assert.Equal(t, "alice", user["username"])
+		assert.NotContains(t, user, "password_hash")
+		assert.NotContains(t, user, "PasswordHash")
+	})
+
+	t.Run("Logout revokes the token", func(t *testing.T) {
+		token := at.login("alice")
+		at.decode(at.do(http.MethodPost, "/api/v1/logout", token, nil), http.StatusNoContent, nil)
+		at.requireError(at.do(http.MethodGet, "/api/v1/me", token, nil), http.StatusUnauthorized, "unauthorized")
+	})
+
+	t.Run("Missing token", func(t *testing.T) {
+		at.requireError(at.do(http.MethodGet, "/api/v1/me", "", nil), http.StatusUnauthorized, "unauthorized")
+		at.requireError(at.do(http.MethodGet, "/api/v1/me", "not-a-token", nil), http.StatusUnauthorized, "unauthorized")
+	})
+}
+
+func TestAPI_Tokens(t *testing.T) {
+	at := newAPITest(t)
+	at.signUp("alice")
+
+	t.Run("Login", func(t *testing.T) {
+		login := at.loginTokens("alice")
+		assert.Equal(t, "Bearer", login.TokenType)
+		require.NotNil(t, login.User)
+		assert.Equal(t, "alice", login.User.Username)
+		assert.True(t, login.ExpiresAt.After(time.Now()))
+		assert.True(t, login.RefreshExpiresAt.After(login.ExpiresAt))
+	})
+
+	t.Run("Refresh rotates the refresh token", func(t *testing.T) {
+		login := at.loginTokens("alice")
+		var refreshed tokens
+		at.decode(at.refresh(login.RefreshToken), http.StatusOK, &refreshed)
+		assert.NotEqual(t, login.RefreshToken, refreshed.RefreshToken)
+		assert.Nil(t, refreshed.User)
+		at.decode(at.do(http.MethodGet, "/api/v1/me", refreshed.AccessToken, nil), http.StatusOK, nil)
+
+		// Reusing the old refresh token ends the login
+		at.requireError(at.refresh(login.RefreshToken), http.StatusUnauthorized, "unauthorized")
+		at.requireError(at.refresh(refreshed.RefreshToken), http.StatusUnauthorized, "unauthorized")
+		at.requireError(at.do(http.MethodGet, "/api/v1/me", refreshed.AccessToken, nil), http.StatusUnauthorized, "unauthorized")
+	})
+
+	t.Run("Logout revokes the refresh token", func(t *testing.T) {
+		login := at.loginTokens("alice")
+		at.decode(at.do(http.MethodPost, "/api/v1/logout", login.AccessToken, nil), http.StatusNoContent, nil)
+		at.requireError(at.refresh(login.RefreshToken), http.StatusUnauthorized, "unauthorized")
+	})
+
+	t.Run("Logout everywhere", func(t *testing.T) {
+		first, second := at.loginTokens("alice"), at.loginTokens("alice")
+		at.decode(at.do(http.MethodPost, "/api/v1/logout/all", first.AccessToken, nil), http.StatusNoContent, nil)
+		at.requireError(at.do(http.MethodGet, "/api/v1/me", second.AccessToken, nil), http.StatusUnauthorized, "unauthorized")
+		at.requireError(at.refresh(second.RefreshToken), http.StatusUnauthorized, "unauthorized")
+	})
+
+	t.Run("Tampered tokens", func(t *testing.T) {
+		login := at.loginTokens("alice")
+		tampered := login.AccessToken[:len(login.AccessToken)-2] + "xx"
+		at.requireError(at.do(http.MethodGet, "/api/v1/me", tampered, nil), http.StatusUnauthorized, "unauthorized")
+		at.requireError(at.refresh("unknown.secret"), http.StatusUnauthorized, "unauthorized")
+		at.requireError(at.refresh(""), http.StatusBadRequest, "bad_request")
+	})
+
+	t.Run("Deleted users are logged out", func(t *testing.T) {
+		at.signUp("bob")
+		at.addAdmin("admin")
+		bob := at.loginTokens("bob")
+		at.decode(at.do(http.MethodDelete, "/api/v1/admin/users/bob", at.login("admin"), nil), http.StatusNoContent, nil)
+		at.requireError(at.do(http.MethodGet, "/api/v1/me", bob.AccessToken, nil), http.StatusUnauthorized, "unauthorized")
+		at.requireError(at.refresh(bob.RefreshToken), http.StatusUnauthorized, "unauthorized")
+	})
+}
+
+func TestAPI_ExpiredTokens(t *testing.T) {
+	at := newAPITestWithTTL(t, time.Nanosecond, time.Nanosecond)
+	at.signUp("alice")
+	login := at.loginTokens("alice")
+
+	at.requireError(at.do(http.MethodGet, "/api/v1/me", login.AccessToken, nil), http.StatusUnauthorized, "unauthorized")
+	at.requireError(at.refresh(login.RefreshToken), http.StatusUnauthorized, "unauthorized")
+}
+
+func TestAPI_PropertyLifecycle(t *testing.T) {
+	at := newAPITest(t)
+	at.signUp("landlord")
+	at.signUp("other")
+	at.addAdmin("admin")
+	landlord, other, admin := at.login("landlord"), at.login("other"), at.login("admin")
+
+	var created entities.Property
+	at.decode(at.do(http.MethodPost, "/api/v1/properties", landlord, testHouse("Family House")), http.StatusCreated, &created)
+	assert.Equal(t, "landlord", created.LandlordUsername)
+	assert.False(t, created.IsApprovedByAdmin)
+	path := "/api/v1/properties/" + created.ID.Hex()
+
+	// Typed details survive the round trip
+	var fetched struct {
+		PropertyType int                   `json:"property_type"`
+		Details      entities.HouseDetails `json:"details"`
+	}
+	at.decode(at.do(http.MethodGet, path, "", nil), http.StatusOK, &fetched)
+	assert.Equal(t, 2, fetched.PropertyType)
+	assert.Equal(t, entities.HouseDetails{NoOfRooms: 3, FurnishedCategory: "Semi-furnished", Amenities: []string{"Parking", "Garden"}}, fetched.Details)
+
+	// Pending properties are not public
+	var listed []entities.Property
+	at.decode(at.do(http.MethodGet, "/api/v1/properties", "", nil), http.StatusOK, &listed)
+	assert.Empty(t, listed)
+
+	var pending []entities.Property
+	at.decode(at.do(http.MethodGet, "/api/v1/admin/properties/pending", admin, nil), http.StatusOK, &pending)
+	require.Len(t, pending, 1)
+	at.decode(at.do(http.MethodPost, "/api/v1/admin/properties/"+created.ID.Hex()+"/approve", admin, nil), http.StatusOK, nil)
+
+	at.decode(at.do(http.MethodGet, "/api/v1/properties", "", nil), http.StatusOK, &listed)
+	require.Len(t, listed, 1)
+	assert.Equal(t, created.ID, listed[0].ID)
+
+	var found []entities.Property
+	at.decode(at.do(http.MethodGet, "/api/v1/properties/search?type=2&city=smalltown&state=punjab", "", nil), http.StatusOK, &found)
+	assert.Len(t, found, 1)
+	at.decode(at.do(http.MethodGet, "/api/v1/properties/search?type=3&city=smalltown&state=punjab", "", nil), http.StatusOK, &found)
+	assert.Empty(t, found)
+	at.requireError(at.do(http.MethodGet, "/api/v1/properties/search?type=9", "", nil), http.StatusBadRequest, "bad_request")
+
+	// Only the landlord may change the property, and changes need a new approval
+	update := testHouse("Bigger Family House")
+	at.requireError(at.do(http.MethodPut, path, other, update), http.StatusForbidden, "forbidden")
+	var updated entities.Property
+	at.decode(at.do(http.MethodPut, path, landlord, update), http.StatusOK, &updated)
+	assert.Equal(t, "Bigger Family House", updated.Title)
+	assert.False(t, updated.IsApprovedByAdmin)
+
+	var mine []entities.Property
+	at.decode(at.do(http.MethodGet, "/api/v1/properties/mine", landlord, nil), http.StatusOK, &mine)
+	assert.Len(t, mine, 1)
+
+	at.requireError(at.do(http.MethodDelete, path, other, nil), http.StatusForbidden, "forbidden")
+	at.decode(at.do(http.MethodDelete, path, landlord, nil), http.StatusNoContent, nil)
+	at.requireError(at.do(http.MethodGet, path, "", nil), http.StatusNotFound, "not_found")
+}
+
+func TestAPI_PropertyValidation(t *testing.T) {
+	at := newAPITest(t)
+	at.signUp("landlord")
+	token := at.login("landlord")
+
+	wrongDetails := testHouse("House")
+	wrongDetails["details"] = map[string]interface{}{"bhk": 2}
+	at.requireError(at.do(http.MethodPost, "/api/v1/properties", token, wrongDetails), http.StatusBadRequest, "bad_request")
+
+	unknownType := testHouse("House")
+	unknownType["property_type"] = 7
+	at.requireError(at.do(http.MethodPost, "/api/v1/properties", token, unknownType), http.StatusBadRequest, "bad_request")
+
+	at.requireError(at.do(http.MethodGet, "/api/v1/properties/not-an-id", "", nil), http.StatusBadRequest, "bad_request")
+	at.requireError(at.do(http.MethodGet, "/api/v1/properties/"+primitive.NewObjectID().Hex(), "", nil), http.StatusNotFound, "not_found")
+}
+
+func TestAPI_WishlistAndRentRequests(t *testing.T) {
+	at := newAPITest(t)
+	at.signUp("landlord")
+	at.signUp("tenant")
+	at.addAdmin("admin")
+	landlord, tenant, admin := at.login("landlord"), at.login("tenant"), at.login("admin")
+	propertyID := at.listApprovedHouse(landlord, admin, "Family House")
+	reference := map[string]string{"property_id": propertyID.Hex()}
+
+	// Wishlist
+	at.decode(at.do(http.MethodPost, "/api/v1/wishlist", tenant, reference), http.StatusNoContent, nil)
+	at.requireError(at.do(http.MethodPost, "/api/v1/wishlist", tenant, reference), http.StatusConflict, "conflict")
+	var wishlist []entities.Property
+	at.decode(at.do(http.MethodGet, "/api/v1/wishlist", tenant, nil), http.StatusOK, &wishlist)
+	require.Len(t, wishlist, 1)
+	assert.Equal(t, propertyID, wishlist[0].ID)
+
+	// Rent requests
+	at.requireError(at.do(http.MethodPost, "/api/v1/rent-requests", landlord, reference), http.StatusBadRequest, "bad_request")
+	at.decode(at.do(http.MethodPost, "/api/v1/rent-requests", tenant, reference), http.StatusCreated, nil)
+	// One open request per tenant and property
+	at.requireError(at.do(http.MethodPost, "/api/v1/rent-requests", tenant, reference), http.StatusConflict, "conflict")
+	at.requireError(at.do(http.MethodPost, "/api/v1/rent-requests", tenant, map[string]string{"property_id": primitive.NewObjectID().Hex()}), http.StatusNotFound, "not_found")
+
+	var received []entities.Request
+	at.decode(at.do(http.MethodGet, "/api/v1/rent-requests/received", landlord, nil), http.StatusOK, &received)
+	require.Len(t, received, 1)
+	assert.Equal(t, "tenant", received[0].TenantName)
+	assert.Equal(t, entities.RequestPending, received[0].RequestStatus)
+	statusPath := "/api/v1/rent-requests/" + received[0].ID.Hex() + "/status"
+
+	// Only the landlord of the property can answer the request
+	at.requireError(at.do(http.MethodPut, statusPath, tenant, map[string]string{"status": "accepted"}), http.StatusNotFound, "not_found")
+	at.requireError(at.do(http.MethodPut, statusPath, landlord, map[string]string{"status": "maybe"}), http.StatusBadRequest, "bad_request")
+	at.decode(at.do(http.MethodPut, statusPath, landlord, map[string]string{"status": "accepted"}), http.StatusOK, nil)
+
+	var sent []entities.Request
+	at.decode(at.do(http.MethodGet, "/api/v1/rent-requests/sent", tenant, nil), http.StatusOK, &sent)
+	require.Len(t, sent, 1)
+	assert.Equal(t, entities.RequestAccepted, sent[0].RequestStatus)
+
+	var property entities.Property
+	at.decode(at.do(http.MethodGet, "/api/v1/properties/"+propertyID.Hex(), "", nil), http.StatusOK, &property)
+	assert.True(t, property.IsRented)
+
+	// Accepted requests can no longer be rejected or withdrawn, only signed or cancelled
+	withdrawPath := "/api/v1/rent-requests/" + received[0].ID.Hex() + "/withdraw"
+	at.requireError(at.do(http.MethodPut, statusPath, landlord, map[string]string{"status": "rejected"}), http.StatusConflict, "conflict")
+	at.requireError(at.do(http.MethodPost, withdrawPath, tenant, nil), http.StatusConflict, "conflict")
+	var request entities.Request
+	at.decode(at.do(http.MethodPut, statusPath, landlord, map[string]string{"status": "lease-signed"}), http.StatusOK, &request)
+	assert.Equal(t, entities.RequestLeaseSigned, request.RequestStatus)
+	require.Len(t, request.History, 3)
+	assert.Equal(t, []entities.RequestStatus{entities.RequestPending, entities.RequestAccepted, entities.RequestLeaseSigned},
+		[]entities.RequestStatus{request.History[0].To, request.History[1].To, request.History[2].To})
+	assert.Equal(t, "landlord", request.History[2].ChangedBy)
+}
+
+func TestAPI_AcceptResolvesCompetingRequests(t *testing.T) {
+	at := newAPITest(t)
+	at.signUp("landlord")
+	at.addAdmin("admin")
+	landlord, admin := at.login("landlord"), at.login("admin")
+	propertyID := at.listApprovedHouse(landlord, admin, "Family House")
+	tenants := []string{"tenant1", "tenant2", "tenant3"}
+	for _, tenant := range tenants {
+		at.signUp(tenant)
+		at.decode(at.do(http.MethodPost, "/api/v1/rent-requests", at.login(tenant), map[string]string{"property_id": propertyID.Hex()}), http.StatusCreated, nil)
+	}
+	var received []entities.Request
+	at.decode(at.do(http.MethodGet, "/api/v1/rent-requests/received", landlord, nil), http.StatusOK, &received)
+	require.Len(t, received, 3)
+
+	// The landlord accepts the first two requests at the same time: only one of them wins
+	statuses := make([]int, 2)
+	var wg sync.WaitGroup
+	for i := range statuses {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			statuses[i] = at.do(http.MethodPut, "/api/v1/rent-requests/"+received[i].ID.Hex()+"/status", landlord, map[string]string{"status": "accepted"}).Code
+		}(i)
+	}
+	wg.Wait()
+	assert.ElementsMatch(t, []int{http.StatusOK, http.StatusConflict}, statuses)
+
+	at.decode(at.do(http.MethodGet, "/api/v1/rent-requests/received", landlord, nil), http.StatusOK, &received)
+	accepted := 0
+	for _, request := range received {
+		if request.RequestStatus == entities.RequestAccepted {
+			accepted++
+			continue
+		}
+		// The other tenants see why their request was rejected
+		assert.Equal(t, entities.RequestRejected, request.RequestStatus, request.TenantName)
+		assert.Equal(t, services.ReasonPropertyRented, request.StatusReason(), request.TenantName)
+	}
+	assert.Equal(t, 1, accepted)
+
+	var property entities.Property
+	at.decode(at.do(http.MethodGet, "/api/v1/properties/"+propertyID.Hex(), "", nil), http.StatusOK, &property)
+	assert.True(t, property.IsRented)
+
+	// Cancelling the accepted request puts the property back on the market
+	for _, request := range received {
+		if request.RequestStatus == entities.RequestAccepted {
+			at.decode(at.do(http.MethodPut, "/api/v1/rent-requests/"+request.ID.Hex()+"/status", landlord, map[string]string{"status": "cancelled"}), http.StatusOK, nil)
+		}
+	}
+	at.decode(at.do(http.MethodGet, "/api/v1/properties/"+propertyID.Hex(), "", nil), http.StatusOK, &property)
+	assert.False(t, property.IsRented)
+}
+
+func TestAPI_WithdrawRentRequest(t *testing.T) {
+	at := newAPITest(t)
+	at.signUp("landlord")
+	at.signUp("tenant")
+	at.signUp("other")
+	at.addAdmin("admin")
+	landlord, tenant, admin := at.login("landlord"), at.login("tenant"), at.login("admin")
+	propertyID := at.listApprovedHouse(landlord, admin, "Family House")
+	at.decode(at.do(http.MethodPost, "/api/v1/rent-requests", tenant, map[string]string{"property_id": propertyID.Hex()}), http.StatusCreated, nil)
+
+	var sent []entities.Request
+	at.decode(at.do(http.MethodGet, "/api/v1/rent-requests/sent", tenant, nil), http.StatusOK, &sent)
+	require.Len(t, sent, 1)
+	withdrawPath := "/api/v1/rent-requests/" + sent[0].ID.Hex() + "/withdraw"
+
+	at.requireError(at.do(http.MethodPost, withdrawPath, at.login("other"), nil), http.StatusForbidden, "forbidden")
+	at.requireError(at.do(http.MethodPost, "/api/v1/rent-requests/"+primitive.NewObjectID().Hex()+"/withdraw", tenant, nil), http.StatusNotFound, "not_found")
+
+	var request entities.Request
+	at.decode(at.do(http.MethodPost, withdrawPath, tenant, nil), http.StatusOK, &request)
+	assert.Equal(t, entities.RequestWithdrawn, request.RequestStatus)
+
+	// A withdrawn request can no longer be answered
+	statusPath := "/api/v1/rent-requests/" + sent[0].ID.Hex() + "/status"
+	at.requireError(at.do(http.MethodPut, statusPath, landlord, map[string]string{"status": "accepted"}), http.StatusConflict, "conflict")
+	at.requireError(at.do(http.MethodPut, statusPath, landlord, map[string]string{"status": "withdrawn"}), http.StatusConflict, "conflict")
+}
+
+func TestAPI_Leases(t *testing.T) {
+	at := newAPITest(t)
+	at.signUp("landlord")
+	at.signUp("tenant")
+	at.signUp("other")
+	at.addAdmin("admin")
+	landlord, tenant, admin := at.login("landlord"), at.login("tenant"), at.login("admin")
+	propertyID := at.listApprovedHouse(landlord, admin, "Family House")
+	at.decode(at.do(http.MethodPost, "/api/v1/rent-requests", tenant, map[string]string{"property_id": propertyID.Hex()}), http.StatusCreated, nil)
+	var received []entities.Request
+	at.decode(at.do(http.MethodGet, "/api/v1/rent-requests/received", landlord, nil), http.StatusOK, &received)
+	require.Len(t, received, 1)
+	statusPath := "/api/v1/rent-requests/" + received[0].ID.Hex() + "/status"
+
+	// Accepting the request draws up a pending lease with the default terms
+	at.decode(at.do(http.MethodPut, statusPath, landlord, map[string]string{"status": "accepted"}), http.StatusOK, nil)
+	var leases []entities.Lease
+	at.decode(at.do(http.MethodGet, "/api/v1/leases/as-tenant", tenant, nil), http.StatusOK, &leases)
+	require.Len(t, leases, 1)
+	lease := leases[0]
+	assert.Equal(t, received[0].ID, lease.RequestID)
+	assert.Equal(t, propertyID, lease.PropertyID)
+	assert.Equal(t, "landlord", lease.LandlordName)
+	assert.Equal(t, entities.LeasePending, lease.Status)
+	assert.Equal(t, 15000.0, lease.MonthlyRent)
+	assert.Equal(t, 15000.0*services.DefaultDepositMonths, lease.Deposit)
+	assert.Equal(t, lease.StartDate.AddDate(0, services.DefaultLeaseMonths, 0), lease.EndDate)
+	at.decode(at.do(http.MethodGet, "/api/v1/leases/as-landlord", landlord, nil), http.StatusOK, &leases)
+	require.Len(t, leases, 1)
+	assert.Equal(t, lease.ID, leases[0].ID)
+
+	// Only the parties to the lease can see it
+	leasePath := "/api/v1/leases/" + lease.ID.Hex()
+	at.requireError(at.do(http.MethodGet, leasePath, at.login("other"), nil), http.StatusForbidden, "forbidden")
+	at.requireError(at.do(http.MethodGet, "/api/v1/leases/"+primitive.NewObjectID().Hex(), tenant, nil), http.StatusNotFound, "not_found")
+
+	// Signing the lease makes it active
+	at.decode(at.do(http.MethodPut, statusPath, landlord, map[string]string{"status": "lease-signed"}), http.StatusOK, nil)
+	at.decode(at.do(http.MethodGet, leasePath, tenant, nil), http.StatusOK, &lease)
+	assert.Equal(t, entities.LeaseActive, lease.Status)
+	var property entities.Property
+	at.decode(at.do(http.MethodGet, "/api/v1/properties/"+propertyID.Hex(), "", nil), http.StatusOK, &property)
+	assert.True(t, property.IsRented)
+
+	// The landlord offers a renewal at a new rent, which the tenant accepts
+	end := lease.EndDate
+	at.requireError(at.do(http.MethodPost, leasePath+"/renewal/accept", tenant, nil), http.StatusConflict, "conflict")
+	at.requireError(at.do(http.MethodPost, leasePath+"/renewal", landlord, map[string]interface{}{"monthly_rent": 16000, "months": 0}), http.StatusBadRequest, "bad_request")
+	at.requireError(at.do(http.MethodPost, leasePath+"/renewal", tenant, map[string]interface{}{"monthly_rent": 16000, "months": 12}), http.StatusForbidden, "forbidden")
+	at.decode(at.do(http.MethodPost, leasePath+"/renewal", landlord, map[string]interface{}{"monthly_rent": 16000, "months": 12}), http.StatusOK, &lease)
+	require.NotNil(t, lease.Renewal)
+	assert.Equal(t, entities.RenewalOffered, lease.Renewal.Status)
+	at.decode(at.do(http.MethodPost, leasePath+"/renewal/accept", tenant, nil), http.StatusOK, &lease)
+	assert.Equal(t, entities.RenewalAccepted, lease.Renewal.Status)
+	assert.Equal(t, 16000.0, lease.MonthlyRent)
+	assert.Equal(t, end.AddDate(0, 12, 0), lease.EndDate)
+
+	// A second offer is declined and changes nothing
+	at.decode(at.do(http.MethodPost, leasePath+"/renewal", landlord, map[string]interface{}{"monthly_rent": 20000, "months": 6}), http.StatusOK, nil)
+	at.decode(at.do(http.MethodPost, leasePath+"/renewal/decline", tenant, nil), http.StatusOK, &lease)
+	assert.Equal(t, entities.RenewalDeclined, lease.Renewal.Status)
+	assert.Equal(t, 16000.0, lease.MonthlyRent)
+
+	// The tenant gives notice; the lease ends after the notice period and cannot be renewed any more
+	at.requireError(at.do(http.MethodPost, leasePath+"/notice", at.login("other"), nil), http.StatusForbidden, "forbidden")
+	at.decode(at.do(http.MethodPost, leasePath+"/notice", tenant, nil), http.StatusOK, &lease)
+	assert.Equal(t, entities.LeaseEnding, lease.Status)
+	assert.Equal(t, lease.StartDate.AddDate(0, 0, services.DefaultNoticePeriodDays), lease.EndDate)
+	require.NotNil(t, lease.Notice)
+	assert.Equal(t, "tenant", lease.Notice.GivenBy)
+	at.requireError(at.do(http.MethodPost, leasePath+"/notice", landlord, nil), http.StatusConflict, "conflict")
+	at.requireError(at.do(http.MethodPost, leasePath+"/renewal", landlord, map[string]interface{}{"monthly_rent": 16000, "months": 12}), http.StatusConflict, "conflict")
+}
+
+func TestAPI_RentLedger(t *testing.T) {
+	at := newAPITest(t)
+	at.signUp("landlord")
+	at.signUp("tenant")
+	at.signUp("other")
+	at.addAdmin("admin")
+	landlord, tenant, admin := at.login("landlord"), at.login("tenant"), at.login("admin")
+	propertyID := at.listApprovedHouse(landlord, admin, "Family House")
+	at.decode(at.do(http.MethodPost, "/api/v1/rent-requests", tenant, map[string]string{"property_id": propertyID.Hex()}), http.StatusCreated, nil)
+	var received []entities.Request
+	at.decode(at.do(http.MethodGet, "/api/v1/rent-requests/received", landlord, nil), http.StatusOK, &received)
+	require.Len(t, received, 1)
+	statusPath := "/api/v1/rent-requests/" + received[0].ID.Hex() + "/status"
+	at.decode(at.do(http.MethodPut, statusPath, landlord, map[string]string{"status": "accepted"}), http.StatusOK, nil)
+	var leases []entities.Lease
+	at.decode(at.do(http.MethodGet, "/api/v1/leases/as-tenant", tenant, nil), http.StatusOK, &leases)
+	require.Len(t, leases, 1)
+	leasePath := "/api/v1/leases/" + leases[0].ID.Hex()
+
+	// No rent is owed, nor can it be paid, before the lease is signed
+	var statement entities.Statement
+	at.decode(at.do(http.MethodGet, leasePath+"/statement", tenant, nil), http.StatusOK, &statement)
+	assert.Empty(t, statement.Lines)
+	payment := map[string]interface{}{"amount": 10000, "mode": "upi", "reference": "UPI-1"}
+	at.requireError(at.do(http.MethodPost, leasePath+"/payments", landlord, payment), http.StatusConflict, "conflict")
+
+	// The rent of the first month is due on the day the lease starts
+	at.decode(at.do(http.MethodPut, statusPath, landlord, map[string]string{"status": "lease-signed"}), http.StatusOK, nil)
+	at.requireError(at.do(http.MethodPost, leasePath+"/payments", tenant, payment), http.StatusForbidden, "forbidden")
+	at.requireError(at.do(http.MethodPost, leasePath+"/payments", landlord, map[string]interface{}{"amount": 0, "mode": "upi"}), http.StatusBadRequest, "bad_request")
+	at.requireError(at.do(http.MethodPost, leasePath+"/payments", landlord, map[string]interface{}{"amount": 100, "mode": "upi", "paid_on": "yesterday"}), http.StatusBadRequest, "bad_request")
+	var recorded entities.Payment
+	at.decode(at.do(http.MethodPost, leasePath+"/payments", landlord, payment), http.StatusCreated, &recorded)
+	assert.Equal(t, 10000.0, recorded.Amount)
+	assert.Equal(t, entities.PaymentUPI, recorded.Mode)
+	assert.Equal(t, "UPI-1", recorded.Reference)
+	assert.Equal(t, "landlord", recorded.RecordedBy)
+
+	at.decode(at.do(http.MethodGet, leasePath+"/statement", tenant, nil), http.StatusOK, &statement)
+	require.Len(t, statement.Lines, 1)
+	assert.Equal(t, 15000.0, statement.Lines[0].Due.Amount)
+	assert.Equal(t, 10000.0, statement.Lines[0].Paid)
+	assert.Equal(t, 5000.0, statement.Outstanding)
+	require.Len(t, statement.Payments, 1)
+	assert.Equal(t, recorded.ID, statement.Payments[0].ID)
+
+	// Only the parties to the lease and moderators can see the statement
+	at.decode(at.do(http.MethodGet, leasePath+"/statement", landlord, nil), http.StatusOK, nil)
+	at.decode(at.do(http.MethodGet, leasePath+"/statement", admin, nil), http.StatusOK, nil)
+	at.requireError(at.do(http.MethodGet, leasePath+"/statement", at.login("other"), nil), http.StatusForbidden, "forbidden")
+	at.requireError(at.do(http.MethodGet, "/api/v1/leases/"+primitive.NewObjectID().Hex()+"/statement", tenant, nil), http.StatusNotFound, "not_found")
+}
+
+func TestAPI_RentRules(t *testing.T) {
+	at := newAPITest(t)
+	at.signUp("landlord")
+	at.signUp("tenant")
+	at.signUp("other")
+	at.addAdmin("admin")
+	landlord, tenant, admin := at.login("landlord"), at.login("tenant"), at.login("admin")
+	propertyID := at.listApprovedHouse(landlord, admin, "Family House")
+	at.decode(at.do(http.MethodPost, "/api/v1/rent-requests", tenant, map[string]string{"property_id": propertyID.Hex()}), http.StatusCreated, nil)
+	var received []entities.Request
+	at.decode(at.do(http.MethodGet, "/api/v1/rent-requests/received", landlord, nil), http.StatusOK, &received)
+	require.Len(t, received, 1)
+	statusPath := "/api/v1/rent-requests/" + received[0].ID.Hex() + "/status"
+	at.decode(at.do(http.MethodPut, statusPath, landlord, map[string]string{"status": "accepted"}), http.StatusOK, nil)
+	at.decode(at.do(http.MethodPut, statusPath, landlord, map[string]string{"status": "lease-signed"}), http.StatusOK, nil)
+	var leases []entities.Lease
+	at.decode(at.do(http.MethodGet, "/api/v1/leases/as-tenant", tenant, nil), http.StatusOK, &leases)
+	require.Len(t, leases, 1)
+	leasePath := "/api/v1/leases/" + leases[0].ID.Hex()
+
+	// A dry run shows the rent under the rules without setting them
+	rules := map[string]interface{}{
+		"escalation": map[string]interface{}{"percent": 10, "every_months": 6},
+		"late_fee":   map[string]interface{}{"grace_days": 4, "per_day": 100},
+	}
+	var schedule entities.Schedule
+	at.decode(at.do(http.MethodPost, leasePath+"/schedule/dry-run?months=12", landlord, rules), http.StatusOK, &schedule)
+	require.Len(t, schedule.Dues, services.DefaultLeaseMonths-1)
+	assert.Equal(t, 15000.0, schedule.Dues[0].Amount)
+	assert.Equal(t, 16500.0, schedule.Dues[5].Amount)
+	assert.Equal(t, 1500.0, schedule.Dues[5].Increase)
+	require.NotNil(t, schedule.Dues[0].LateFrom)
+	assert.Equal(t, 100.0, schedule.Dues[0].LateFee)
+	var current entities.Schedule
+	at.decode(at.do(http.MethodGet, leasePath+"/schedule?months=2", tenant, nil), http.StatusOK, &current)
+	require.Len(t, current.Dues, 2)
+	assert.Nil(t, current.Rules.Escalation)
+	assert.Nil(t, current.Dues[0].LateFrom)
+	at.requireError(at.do(http.MethodGet, leasePath+"/schedule?months=lots", tenant, nil), http.StatusBadRequest, "bad_request")
+	at.requireError(at.do(http.MethodGet, leasePath+"/schedule?months=1000", tenant, nil), http.StatusBadRequest, "bad_request")
+	at.requireError(at.do(http.MethodGet, leasePath+"/schedule", at.login("other"), nil), http.StatusForbidden, "forbidden")
+
+	// The landlord sets the rules, which the schedule then follows
+	at.requireError(at.do(http.MethodPut, leasePath+"/rules", tenant, rules), http.StatusForbidden, "forbidden")
+	at.requireError(at.do(http.MethodPut, leasePath+"/rules", landlord, map[string]interface{}{"late_fee": map[string]interface{}{"grace_days": 4}}), http.StatusBadRequest, "bad_request")
+	var lease entities.Lease
+	at.decode(at.do(http.MethodPut, leasePath+"/rules", landlord, rules), http.StatusOK, &lease)
+	require.NotNil(t, lease.Rules)
+	require.NotNil(t, lease.Rules.Escalation)
+	assert.True(t, lease.StartDate.Equal(lease.Rules.Escalation.Since))
+	at.decode(at.do(http.MethodGet, leasePath+"/schedule", tenant, nil), http.StatusOK, &schedule)
+	assert.Equal(t, 16500.0, schedule.Dues[5].Amount)
+
+	// Rent already due keeps its amount
+	var statement entities.Statement
+	at.decode(at.do(http.MethodGet, leasePath+"/statement", tenant, nil), http.StatusOK, &statement)
+	require.Len(t, statement.Lines, 1)
+	assert.Equal(t, 15000.0, statement.Lines[0].Due.Amount)
+	assert.Equal(t, 0.0, statement.LateFees)
+
+	var cleared entities.Lease
+	at.decode(at.do(http.MethodPut, leasePath+"/rules", landlord, map[string]interface{}{}), http.StatusOK, &cleared)
+	assert.Nil(t, cleared.Rules)
+}
+
+func TestAPI_Documents(t *testing.T) {
+	at := newAPITest(t)
+	at.signUp("landlord")
+	at.signUp("tenant")
+	at.signUp("other")
+	at.addAdmin("admin")
+	landlord, tenant, admin := at.login("landlord"), at.login("tenant"), at.login("admin")
+	propertyID := at.listApprovedHouse(landlord, admin, "Family House")
+	at.decode(at.do(http.MethodPost, "/api/v1/rent-requests", tenant, map[string]string{"property_id": propertyID.Hex()}), http.StatusCreated, nil)
+	var received []entities.Request
+	at.decode(at.do(http.MethodGet, "/api/v1/rent-requests/received", landlord, nil), http.StatusOK, &received)
+	require.Len(t, received, 1)
+	statusPath := "/api/v1/rent-requests/" + received[0].ID.Hex() + "/status"
+	at.decode(at.do(http.MethodPut, statusPath, landlord, map[string]string{"status": "accepted"}), http.StatusOK, nil)
+	at.decode(at.do(http.MethodPut, statusPath, landlord, map[string]string{"status": "lease-signed"}), http.StatusOK, nil)
+	var leases []entities.Lease
+	at.decode(at.do(http.MethodGet, "/api/v1/leases/as-tenant", tenant, nil), http.StatusOK, &leases)
+	require.Len(t, leases, 1)
+	leasePath := "/api/v1/leases/" + leases[0].ID.Hex()
+	var payment entities.Payment
+	at.decode(at.do(http.MethodPost, leasePath+"/payments", landlord, map[string]interface{}{"amount": 15000, "mode": "cash"}), http.StatusCreated, &payment)
+
+	rec := at.do(http.MethodGet, leasePath+"/agreement", tenant, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "application/pdf", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="lease-`+leases[0].ID.Hex()+`.pdf"`, rec.Header().Get("Content-Disposition"))
+	assert.True(t, bytes.HasPrefix(rec.Body.Bytes(), []byte("%PDF-")))
+	assert.Contains(t, rec.Body.String(), "(Family House) Tj")
+
+	rec = at.do(http.MethodGet, leasePath+"/payments/"+payment.ID.Hex()+"/receipt", landlord, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "application/pdf", rec.Header().Get("Content-Type"))
+	assert.Equal(t, strconv.Itoa(rec.Body.Len()), rec.Header().Get("Content-Length"))
+	assert.Contains(t, rec.Body.String(), "Rs. 15,000.00")
+
+	// Only the parties to the lease get its documents
+	other := at.login("other")
+	at.requireError(at.do(http.MethodGet, leasePath+"/agreement", other, nil), http.StatusForbidden, "forbidden")
+	at.requireError(at.do(http.MethodGet, leasePath+"/payments/"+payment.ID.Hex()+"/receipt", other, nil), http.StatusForbidden, "forbidden")
+	at.requireError(at.do(http.MethodGet, leasePath+"/agreement", "", nil), http.StatusUnauthorized, "unauthorized")
+	at.requireError(at.do(http.MethodGet, leasePath+"/payments/"+primitive.NewObjectID().Hex()+"/receipt", tenant, nil), http.StatusNotFound, "not_found")
+	at.requireError(at.do(http.MethodGet, leasePath+"/payments/nope/receipt", tenant, nil), http.StatusBadRequest, "bad_request")
+}
+
+func TestAPI_Deposits(t *testing.T) {
+	at := newAPITest(t)
+	at.signUp("landlord")
+	at.signUp("tenant")
+	at.signUp("other")
+	at.addAdmin("admin")
+	landlord, tenant, admin := at.login("landlord"), at.login("tenant"), at.login("admin")
+	propertyID := at.listApprovedHouse(landlord, admin, "Family House")
+
+	// The landlord asks for a deposit other than the default
+	house := testHouse("Family House")
+	house["deposit"] = -1
+	at.requireError(at.do(http.MethodPut, "/api/v1/properties/"+propertyID.Hex(), landlord, house), http.StatusBadRequest, "bad_request")
+	house["deposit"] = 20000
+	var property entities.Property
+	at.decode(at.do(http.MethodPut, "/api/v1/properties/"+propertyID.Hex(), landlord, house), http.StatusOK, &property)
+	assert.Equal(t, 20000.0, property.Deposit)
+	at.decode(at.do(http.MethodPost, "/api/v1/admin/properties/"+propertyID.Hex()+"/approve", admin, nil), http.StatusOK, nil)
+
+	at.decode(at.do(http.MethodPost, "/api/v1/rent-requests", tenant, map[string]string{"property_id": propertyID.Hex()}), http.StatusCreated, nil)
+	var received []entities.Request
+	at.decode(at.do(http.MethodGet, "/api/v1/rent-requests/received", landlord, nil), http.StatusOK, &received)
+	require.Len(t, received, 1)
+	statusPath := "/api/v1/rent-requests/" + received[0].ID.Hex() + "/status"
+	at.decode(at.do(http.MethodPut, statusPath, landlord, map[string]string{"status": "accepted"}), http.StatusOK, nil)
+	at.decode(at.do(http.MethodPut, statusPath, landlord, map[string]string{"status": "lease-signed"}), http.StatusOK, nil)
+	var leases []entities.Lease
+	at.decode(at.do(http.MethodGet, "/api/v1/leases/as-tenant", tenant, nil), http.StatusOK, &leases)
+	require.Len(t, leases, 1)
+	assert.Equal(t, 20000.0, leases[0].Deposit)
+	depositPath := "/api/v1/leases/" + leases[0].ID.Hex() + "/deposit"
+
+	var deposit entities.Deposit
+	at.decode(at.do(http.MethodGet, depositPath, tenant, nil), http.StatusOK, &deposit)
+	assert.Equal(t, entities.DepositAwaited, deposit.Status)
+	assert.Equal(t, 20000.0, deposit.Agreed)
+
+	receipt := map[string]interface{}{"amount": 20000, "mode": "bank-transfer", "reference": "NEFT-7"}
+	at.requireError(at.do(http.MethodPost, depositPath, tenant, receipt), http.StatusForbidden, "forbidden")
+	at.decode(at.do(http.MethodPost, depositPath, landlord, receipt), http.StatusCreated, &deposit)
+	assert.Equal(t, entities.DepositHeld, deposit.Status)
+	assert.Equal(t, 20000.0, deposit.Refund)
+	at.requireError(at.do(http.MethodPost, depositPath, landlord, receipt), http.StatusConflict, "conflict")
+
+	// Deductions are itemised once the tenant gives notice
+	deductions := map[string]interface{}{"deductions": []map[string]interface{}{
+		{"reason": "Repainting", "amount": 4500, "note": "Living room"},
+		{"reason": "Broken tap", "amount": 500},
+	}}
+	at.requireError(at.do(http.MethodPut, depositPath+"/deductions", landlord, deductions), http.StatusConflict, "conflict")
+	at.decode(at.do(http.MethodPost, "/api/v1/leases/"+leases[0].ID.Hex()+"/notice", tenant, nil), http.StatusOK, nil)
+	at.requireError(at.do(http.MethodPut, depositPath+"/deductions", landlord, map[string]interface{}{"deductions": []map[string]interface{}{{"reason": "Everything", "amount": 25000}}}), http.StatusBadRequest, "bad_request")
+	at.requireError(at.do(http.MethodPost, depositPath+"/acknowledge", tenant, nil), http.StatusConflict, "conflict")
+	at.decode(at.do(http.MethodPut, depositPath+"/deductions", landlord, deductions), http.StatusOK, &deposit)
+	assert.Equal(t, entities.DepositProposed, deposit.Status)
+	require.Len(t, deposit.Deductions, 2)
+	assert.Equal(t, 15000.0, deposit.Refund)
+
+	// The tenant disputes the deductions, and acknowledges the revised ones
+	at.requireError(at.do(http.MethodPost, depositPath+"/dispute", tenant, map[string]string{"reason": ""}), http.StatusBadRequest, "bad_request")
+	at.requireError(at.do(http.MethodPost, depositPath+"/dispute", landlord, map[string]string{"reason": "No"}), http.StatusForbidden, "forbidden")
+	at.decode(at.do(http.MethodPost, depositPath+"/dispute", tenant, map[string]string{"reason": "The walls were not painted"}), http.StatusOK, &deposit)
+	assert.Equal(t, entities.DepositDisputed, deposit.Status)
+	require.NotNil(t, deposit.Dispute)
+	at.decode(at.do(http.MethodPut, depositPath+"/deductions", landlord, map[string]interface{}{"deductions": []map[string]interface{}{{"reason": "Broken tap", "amount": 500}}}), http.StatusOK, &deposit)
+	assert.Equal(t, 19500.0, deposit.Refund)
+	at.decode(at.do(http.MethodPost, depositPath+"/acknowledge", tenant, nil), http.StatusOK, &deposit)
+	assert.Equal(t, entities.DepositClosed, deposit.Status)
+	assert.NotNil(t, deposit.ClosedAt)
+	at.requireError(at.do(http.MethodPut, depositPath+"/deductions", landlord, deductions), http.StatusConflict, "conflict")
+
+	// Only the parties to the lease and moderators see the deposit
+	at.decode(at.do(http.MethodGet, depositPath, admin, nil), http.StatusOK, nil)
+	at.requireError(at.do(http.MethodGet, depositPath, at.login("other"), nil), http.StatusForbidden, "forbidden")
+	at.requireError(at.do(http.MethodGet, "/api/v1/leases/"+primitive.NewObjectID().Hex()+"/deposit", tenant, nil), http.StatusNotFound, "not_found")
+}
+
+func TestAPI_Maintenance(t *testing.T) {
+	at := newAPITest(t)
+	at.signUp("landlord")
+	at.signUp("tenant")
+	at.signUp("other")
+	at.addAdmin("admin")
+	landlord, tenant, other, admin := at.login("landlord"), at.login("tenant"), at.login("other"), at.login("admin")
+	lease := at.signedLease(landlord, tenant, at.listApprovedHouse(landlord, admin, "Family House"))
+	reportPath := "/api/v1/leases/" + lease.ID.Hex() + "/maintenance"
+
+	issue := map[string]string{"category": "plumbing", "priority": "urgent", "description": "The kitchen tap is leaking"}
+	at.requireError(at.do(http.MethodPost, reportPath, landlord, issue), http.StatusForbidden, "forbidden")
+	at.requireError(at.do(http.MethodPost, reportPath, tenant, map[string]string{"category": "roof", "description": "Leaks"}), http.StatusBadRequest, "bad_request")
+	var ticket entities.MaintenanceTicket
+	at.decode(at.do(http.MethodPost, reportPath, tenant, issue), http.StatusCreated, &ticket)
+	assert.Equal(t, entities.TicketOpen, ticket.Status)
+	assert.Equal(t, lease.PropertyID, ticket.PropertyID)
+	assert.Equal(t, 24*time.Hour, ticket.DueBy.Sub(ticket.CreatedAt))
+	assert.False(t, ticket.Overdue)
+	ticketPath := "/api/v1/maintenance/" + ticket.ID.Hex()
+
+	var tickets []entities.MaintenanceTicket
+	at.decode(at.do(http.MethodGet, "/api/v1/maintenance/as-tenant", tenant, nil), http.StatusOK, &tickets)
+	require.Len(t, tickets, 1)
+	var queue []entities.MaintenanceTicket
+	at.decode(at.do(http.MethodGet, "/api/v1/maintenance/queue", landlord, nil), http.StatusOK, &queue)
+	require.Len(t, queue, 1)
+	assert.Equal(t, ticket.ID, queue[0].ID)
+	at.requireError(at.do(http.MethodGet, ticketPath, other, nil), http.StatusForbidden, "forbidden")
+	at.decode(at.do(http.MethodGet, ticketPath, admin, nil), http.StatusOK, nil)
+	at.requireError(at.do(http.MethodGet, "/api/v1/maintenance/"+primitive.NewObjectID().Hex(), tenant, nil), http.StatusNotFound, "not_found")
+
+	// The landlord works on it and the tenant follows up in the comments
+	at.requireError(at.do(http.MethodPut, ticketPath+"/status", tenant, map[string]string{"status": "resolved"}), http.StatusForbidden, "forbidden")
+	var acknowledged entities.MaintenanceTicket
+	at.decode(at.do(http.MethodPut, ticketPath+"/status", landlord, map[string]string{"status": "acknowledged", "note": "A plumber comes tomorrow"}), http.StatusOK, &acknowledged)
+	assert.Equal(t, entities.TicketAcknowledged, acknowledged.Status)
+	require.Len(t, acknowledged.Comments, 1)
+	assert.Equal(t, "landlord", acknowledged.Comments[0].Author)
+	at.requireError(at.do(http.MethodPost, ticketPath+"/comments", other, map[string]string{"text": "Hello"}), http.StatusForbidden, "forbidden")
+	var commented entities.MaintenanceTicket
+	at.decode(at.do(http.MethodPost, ticketPath+"/comments", tenant, map[string]string{"text": "Thanks, I will be home"}), http.StatusCreated, &commented)
+	require.Len(t, commented.Comments, 2)
+
+	// Closing needs the landlord to resolve it first
+	at.requireError(at.do(http.MethodPut, ticketPath+"/status", tenant, map[string]string{"status": "closed"}), http.StatusConflict, "conflict")
+	var resolved entities.MaintenanceTicket
+	at.decode(at.do(http.MethodPut, ticketPath+"/status", landlord, map[string]string{"status": "resolved"}), http.StatusOK, &resolved)
+	assert.NotNil(t, resolved.ResolvedAt)
+	at.requireError(at.do(http.MethodPut, ticketPath+"/status", landlord, map[string]string{"status": "in-progress"}), http.StatusConflict, "conflict")
+	var closed entities.MaintenanceTicket
+	at.decode(at.do(http.MethodPut, ticketPath+"/status", tenant, map[string]string{"status": "closed"}), http.StatusOK, &closed)
+	assert.Equal(t, entities.TicketClosed, closed.Status)
+	at.requireError(at.do(http.MethodPost, ticketPath+"/comments", tenant, map[string]string{"text": "Thanks"}), http.StatusConflict, "conflict")
+
+	// Closed tickets leave the queue
+	queue = nil
+	at.decode(at.do(http.MethodGet, "/api/v1/maintenance/queue", landlord, nil), http.StatusOK, &queue)
+	assert.Empty(t, queue)
+}
+
+func TestAPI_Visits(t *testing.T) {
+	at := newAPITest(t)
+	at.signUp("landlord")
+	at.signUp("tenant")
+	at.signUp("other")
+	at.addAdmin("admin")
+	landlord, tenant, other, admin := at.login("landlord"), at.login("tenant"), at.login("other"), at.login("admin")
+	propertyID := at.listApprovedHouse(landlord, admin, "Family House")
+	slotsPath := "/api/v1/properties/" + propertyID.Hex() + "/visits"
+	tomorrow := time.Now().Add(24 * time.Hour).Truncate(time.Minute)
+
+	offer := map[string]interface{}{"starts": []time.Time{tomorrow, tomorrow.Add(time.Hour)}, "length_minutes": 45}
+	at.requireError(at.do(http.MethodPost, slotsPath, tenant, offer), http.StatusForbidden, "forbidden")
+	at.requireError(at.do(http.MethodPost, slotsPath, landlord, map[string]interface{}{"starts": []time.Time{tomorrow.Add(-48 * time.Hour)}}), http.StatusBadRequest, "bad_request")
+	var slots []entities.VisitSlot
+	at.decode(at.do(http.MethodPost, slotsPath, landlord, offer), http.StatusCreated, &slots)
+	require.Len(t, slots, 2)
+	assert.Equal(t, 45*time.Minute, slots[0].End.Sub(slots[0].Start))
+	at.requireError(at.do(http.MethodPost, slotsPath, landlord, map[string]interface{}{"starts": []time.Time{tomorrow.Add(30 * time.Minute)}}), http.StatusConflict, "conflict")
+
+	// A slot is booked once; the other tenant only sees the one left
+	var booked entities.VisitSlot
+	at.decode(at.do(http.MethodPost, "/api/v1/visits/"+slots[0].ID.Hex()+"/book", tenant, nil), http.StatusOK, &booked)
+	assert.Equal(t, entities.VisitBooked, booked.Status)
+	at.requireError(at.do(http.MethodPost, "/api/v1/visits/"+slots[0].ID.Hex()+"/book", other, nil), http.StatusConflict, "conflict")
+	at.requireError(at.do(http.MethodPost, "/api/v1/visits/"+slots[1].ID.Hex()+"/book", tenant, nil), http.StatusConflict, "conflict")
+	var open []entities.VisitSlot
+	at.decode(at.do(http.MethodGet, slotsPath, other, nil), http.StatusOK, &open)
+	require.Len(t, open, 1)
+	assert.Equal(t, slots[1].ID, open[0].ID)
+
+	at.requireError(at.do(http.MethodPost, "/api/v1/visits/"+slots[0].ID.Hex()+"/reschedule", other, map[string]string{"to_slot_id": slots[1].ID.Hex()}), http.StatusForbidden, "forbidden")
+	var moved entities.VisitSlot
+	at.decode(at.do(http.MethodPost, "/api/v1/visits/"+slots[0].ID.Hex()+"/reschedule", tenant, map[string]string{"to_slot_id": slots[1].ID.Hex()}), http.StatusOK, &moved)
+	assert.Equal(t, slots[1].ID, moved.ID)
+	var visits []entities.VisitSlot
+	at.decode(at.do(http.MethodGet, "/api/v1/visits/as-landlord", landlord, nil), http.StatusOK, &visits)
+	require.Len(t, visits, 1)
+	assert.Equal(t, "tenant", visits[0].TenantName)
+
+	// The landlord calls the visit off; the tenant no longer has one coming up
+	var cancelled entities.VisitSlot
+	at.decode(at.do(http.MethodPost, "/api/v1/visits/"+slots[1].ID.Hex()+"/cancel", landlord, nil), http.StatusOK, &cancelled)
+	assert.Equal(t, entities.VisitCancelled, cancelled.Status)
+	at.requireError(at.do(http.MethodPost, "/api/v1/visits/"+slots[1].ID.Hex()+"/cancel", landlord, nil), http.StatusConflict, "conflict")
+	var upcoming []entities.VisitSlot
+	at.decode(at.do(http.MethodGet, "/api/v1/visits/as-tenant", tenant, nil), http.StatusOK, &upcoming)
+	assert.Empty(t, upcoming)
+	at.requireError(at.do(http.MethodPost, "/api/v1/visits/"+primitive.NewObjectID().Hex()+"/book", tenant, nil), http.StatusNotFound, "not_found")
+}
+
+func TestAPI_Messages(t *testing.T) {
+	at := newAPITest(t)
+	at.signUp("landlord")
+	at.signUp("tenant")
+	at.signUp("other")
+	at.addAdmin("admin")
+	landlord, tenant, other, admin := at.login("landlord"), at.login("tenant"), at.login("other"), at.login("admin")
+	propertyID := at.listApprovedHouse(landlord, admin, "Family House")
+	contactPath := "/api/v1/properties/" + propertyID.Hex() + "/messages"
+
+	at.requireError(at.do(http.MethodPost, contactPath, tenant, map[string]string{"text": "  "}), http.StatusBadRequest, "bad_request")
+	at.requireError(at.do(http.MethodPost, contactPath, landlord, map[string]string{"text": "Hello"}), http.StatusForbidden, "forbidden")
+	var thread entities.Thread
+	at.decode(at.do(http.MethodPost, contactPath, tenant, map[string]string{"text": "Is it still available?"}), http.StatusCreated, &thread)
+	require.Len(t, thread.Messages, 1)
+	threadPath := "/api/v1/threads/" + thread.ID.Hex()
+
+	// The landlord sees the message as unread until they open the thread
+	var unread entities.UnreadMessages
+	at.decode(at.do(http.MethodGet, "/api/v1/threads/unread", landlord, nil), http.StatusOK, &unread)
+	assert.Equal(t, 1, unread.AsLandlord)
+	var opened entities.Thread
+	at.decode(at.do(http.MethodGet, threadPath, landlord, nil), http.StatusOK, &opened)
+	assert.Equal(t, 1, opened.LandlordUnread)
+	var none entities.UnreadMessages
+	at.decode(at.do(http.MethodGet, "/api/v1/threads/unread", landlord, nil), http.StatusOK, &none)
+	assert.Zero(t, none.AsLandlord)
+
+	// The rent request leads to the same thread
+	at.decode(at.do(http.MethodPost, "/api/v1/rent-requests", tenant, map[string]string{"property_id": propertyID.Hex()}), http.StatusCreated, nil)
+	var received []entities.Request
+	at.decode(at.do(http.MethodGet, "/api/v1/rent-requests/received", landlord, nil), http.StatusOK, &received)
+	require.Len(t, received, 1)
+	requestPath := "/api/v1/rent-requests/" + received[0].ID.Hex() + "/messages"
+	at.requireError(at.do(http.MethodPost, requestPath, other, map[string]string{"text": "Hello"}), http.StatusForbidden, "forbidden")
+	var answered entities.Thread
+	at.decode(at.do(http.MethodPost, requestPath, landlord, map[string]string{"text": "Yes, it is"}), http.StatusCreated, &answered)
+	assert.Equal(t, thread.ID, answered.ID)
+	assert.Len(t, answered.Messages, 2)
+
+	var replied entities.Thread
+	at.decode(at.do(http.MethodPost, threadPath+"/messages", tenant, map[string]string{"text": "Great, thanks"}), http.StatusCreated, &replied)
+	assert.Len(t, replied.Messages, 3)
+	var threads []entities.Thread
+	at.decode(at.do(http.MethodGet, "/api/v1/threads", landlord, nil), http.StatusOK, &threads)
+	require.Len(t, threads, 1)
+	assert.Equal(t, 1, threads[0].LandlordUnread)
+	at.requireError(at.do(http.MethodGet, threadPath, other, nil), http.StatusForbidden, "forbidden")
+	at.requireError(at.do(http.MethodGet, "/api/v1/threads/"+primitive.NewObjectID().Hex(), tenant, nil), http.StatusNotFound, "not_found")
+}
+
+func TestAPI_CancellingCallsOffTheLease(t *testing.T) {
+	at := newAPITest(t)
+	at.signUp("landlord")
+	at.signUp("tenant")
+	at.addAdmin("admin")
+	landlord, tenant, admin := at.login("landlord"), at.login("tenant"), at.login("admin")
+	propertyID := at.listApprovedHouse(landlord, admin, "Family House")
+	at.decode(at.do(http.MethodPost, "/api/v1/rent-requests", tenant, map[string]string{"property_id": propertyID.Hex()}), http.StatusCreated, nil)
+	var received []entities.Request
+	at.decode(at.do(http.MethodGet, "/api/v1/rent-requests/received", landlord, nil), http.StatusOK, &received)
+	require.Len(t, received, 1)
+	statusPath := "/api/v1/rent-requests/" + received[0].ID.Hex() + "/status"
+
+	at.decode(at.do(http.MethodPut, statusPath, landlord, map[string]string{"status": "accepted"}), http.StatusOK, nil)
+	at.decode(at.do(http.MethodPut, statusPath, landlord, map[string]string{"status": "cancelled"}), http.StatusOK, nil)
+
+	var leases []entities.Lease
+	at.decode(at.do(http.MethodGet, "/api/v1/leases/as-landlord", landlord, nil), http.StatusOK, &leases)
+	require.Len(t, leases, 1)
+	assert.Equal(t, entities.LeaseCancelled, leases[0].Status)
+	var property entities.Property
+	at.decode(at.do(http.MethodGet, "/api/v1/properties/"+propertyID.Hex(), "", nil), http.StatusOK, &property)
+	assert.False(t, property.IsRented)
+}
+
+func TestAPI_Notifications(t *testing.T) {
+	at := newAPITest(t)
+	at.signUp("landlord")
+	at.signUp("tenant")
+	at.addAdmin("admin")
+	landlord, tenant, admin := at.login("landlord"), at.login("tenant"), at.login("admin")
+	propertyID := at.listApprovedHouse(landlord, admin, "Family House")
+
+	// The landlord hears of the approval and of the new request
+	at.decode(at.do(http.MethodPost, "/api/v1/rent-requests", tenant, map[string]string{"property_id": propertyID.Hex()}), http.StatusCreated, nil)
+	var unread struct {
+		Unread int `json:"unread"`
+	}
+	at.decode(at.do(http.MethodGet, "/api/v1/notifications/unread", landlord, nil), http.StatusOK, &unread)
+	assert.Equal(t, 2, unread.Unread)
+	var notifications []entities.Notification
+	at.decode(at.do(http.MethodGet, "/api/v1/notifications", landlord, nil), http.StatusOK, &notifications)
+	require.Len(t, notifications, 2)
+	assert.Equal(t, entities.NotificationRequestCreated, notifications[0].Kind)
+	assert.Equal(t, propertyID, notifications[0].PropertyID)
+
+	// The tenant hears of the decision
+	var received []entities.Request
+	at.decode(at.do(http.MethodGet, "/api/v1/rent-requests/received", landlord, nil), http.StatusOK, &received)
+	require.Len(t, received, 1)
+	at.decode(at.do(http.MethodPut, "/api/v1/rent-requests/"+received[0].ID.Hex()+"/status", landlord, map[string]string{"status": "accepted"}), http.StatusOK, nil)
+	var tenantNotifications []entities.Notification
+	at.decode(at.do(http.MethodGet, "/api/v1/notifications?unread=true", tenant, nil), http.StatusOK, &tenantNotifications)
+	require.Len(t, tenantNotifications, 1)
+	assert.Equal(t, entities.NotificationRequestAccepted, tenantNotifications[0].Kind)
+
+	readPath := "/api/v1/notifications/" + notifications[0].ID.Hex() + "/read"
+	at.requireError(at.do(http.MethodPost, readPath, tenant, nil), http.StatusNotFound, "not_found")
+	rec := at.do(http.MethodPost, readPath, landlord, nil)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	var stillUnread []entities.Notification
+	at.decode(at.do(http.MethodGet, "/api/v1/notifications?unread=true", landlord, nil), http.StatusOK, &stillUnread)
+	require.Len(t, stillUnread, 1)
+	assert.Equal(t, notifications[1].ID, stillUnread[0].ID)
+	var cleared []entities.Notification
+	at.decode(at.do(http.MethodDelete, "/api/v1/notifications", landlord, nil), http.StatusOK, &cleared)
+	require.Len(t, cleared, 1)
+
+	// Rejecting a pending listing removes it and tells the landlord why
+	var pending struct {
+		ID primitive.ObjectID `json:"id"`
+	}
+	at.decode(at.do(http.MethodPost, "/api/v1/properties", landlord, testHouse("Small Flat")), http.StatusCreated, &pending)
+	rejectPath := "/api/v1/admin/properties/" + pending.ID.Hex() + "/reject"
+	at.requireError(at.do(http.MethodPost, rejectPath, landlord, map[string]string{"reason": "No photos"}), http.StatusForbidden, "forbidden")
+	at.requireError(at.do(http.MethodPost, "/api/v1/admin/properties/"+propertyID.Hex()+"/reject", admin, map[string]string{}), http.StatusConflict, "conflict")
+	var rejected entities.Property
+	at.decode(at.do(http.MethodPost, rejectPath, admin, map[string]string{"reason": "No photos"}), http.StatusOK, &rejected)
+	assert.Equal(t, "Small Flat", rejected.Title)
+	at.requireError(at.do(http.MethodPost, rejectPath, admin, map[string]string{}), http.StatusNotFound, "not_found")
+
+	var all []entities.Notification
+	at.decode(at.do(http.MethodPost, "/api/v1/notifications/read", landlord, nil), http.StatusOK, &all)
+	require.Len(t, all, 2)
+	assert.Equal(t, "admin rejected your listing Small Flat: No photos", all[0].Text)
+	assert.True(t, all[0].Read && all[1].Read)
+	rec = at.do(http.MethodDelete, "/api/v1/notifications/"+all[0].ID.Hex(), landlord, nil)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	at.requireError(at.do(http.MethodDelete, "/api/v1/notifications/"+all[0].ID.Hex(), landlord, nil), http.StatusNotFound, "not_found")
+	at.requireError(at.do(http.MethodGet, "/api/v1/notifications", "", nil), http.StatusUnauthorized, "unauthorized")
+}
+
+func TestAPI_Emails(t *testing.T) {
+	at := newAPITest(t)
+	at.signUp("landlord")
+	at.signUp("tenant")
+	at.addAdmin("admin")
+	welcome := at.waitForEmails(2)
+	assert.Equal(t, `"Test landlord" <landlord@example.com>`, welcome[0].To)
+	assert.Equal(t, "RentEase <no-reply@rentease.test>", welcome[0].From)
+	assert.Equal(t, "Welcome to RentEase, Test landlord", welcome[0].Subject)
+	assert.Contains(t, welcome[1].Body, "Your RentEase account tenant is ready.")
+
+	landlord, tenant, admin := at.login("landlord"), at.login("tenant"), at.login("admin")
+	propertyID := at.listApprovedHouse(landlord, admin, "Family House")
+	approved := at.waitForEmails(3)[2]
+	assert.Equal(t, `"Test landlord" <landlord@example.com>`, approved.To)
+	assert.Equal(t, "Your listing Family House was approved", approved.Subject)
+	assert.Contains(t, approved.Body, "admin approved your listing Family House.")
+
+	at.decode(at.do(http.MethodPost, "/api/v1/rent-requests", tenant, map[string]string{"property_id": propertyID.Hex()}), http.StatusCreated, nil)
+	created := at.waitForEmails(4)[3]
+	assert.Equal(t, `"Test landlord" <landlord@example.com>`, created.To)
+	assert.Equal(t, "tenant asked to rent Family House", created.Subject)
+
+	var received []entities.Request
+	at.decode(at.do(http.MethodGet, "/api/v1/rent-requests/received", landlord, nil), http.StatusOK, &received)
+	require.Len(t, received, 1)
+	at.decode(at.do(http.MethodPut, "/api/v1/rent-requests/"+received[0].ID.Hex()+"/status", landlord, map[string]string{"status": "rejected"}), http.StatusOK, nil)
+	decided := at.waitForEmails(5)[4]
+	assert.Equal(t, `"Test tenant" <tenant@example.com>`, decided.To)
+	assert.Equal(t, "Your request for Family House was rejected", decided.Subject)
+	assert.Contains(t, decided.Body, "landlord rejected your request to rent Family House.")
+}
+
+func TestAPI_Admin(t *testing.T) {
+	at := newAPITest(t)
+	at.signUp("landlord")
+	at.addAdmin("admin")
+	landlord, admin := at.login("landlord"), at.login("admin")
+	propertyID := at.listApprovedHouse(landlord, admin, "Family House")
+
+	at.requireError(at.do(http.MethodGet, "/api/v1/admin/users", landlord, nil), http.StatusForbidden, "forbidden")
+	at.requireError(at.do(http.MethodDelete, "/api/v1/admin/users/admin", admin, nil), http.StatusForbidden, "forbidden")
+	at.requireError(at.do(http.MethodDelete, "/api/v1/admin/users/nobody", admin, nil), http.StatusNotFound, "not_found")
+
+	var users []entities.User
+	at.decode(at.do(http.MethodGet, "/api/v1/admin/users", admin, nil), http.StatusOK, &users)
+	assert.Len(t, users, 2)
+
+	// Deleting a user also deletes their properties
+	at.decode(at.do(http.MethodDelete, "/api/v1/admin/users/landlord", admin, nil), http.StatusNoContent, nil)
+	at.decode(at.do(http.MethodGet, "/api/v1/admin/users", admin, nil), http.StatusOK, &users)
+	assert.Len(t, users, 1)
+	at.requireError(at.do(http.MethodGet, "/api/v1/properties/"+propertyID.Hex(), "", nil), http.StatusNotFound, "not_found")
+}
+
+func TestAPI_Roles(t *testing.T) {
+	at := newAPITest(t)
+	at.signUp("landlord")
+	at.signUp("tenant")
+	at.signUp("moderator")
+	at.addAdmin("admin")
+	landlord, admin := at.login("landlord"), at.login("admin")
+	setRole := func(username, role string) *httptest.ResponseRecorder {
+		return at.do(http.MethodPut, "/api/v1/admin/users/"+username+"/role", admin, map[string]string{"role": role})
+	}
+
+	var user entities.User
+	at.decode(setRole("moderator", entities.RoleModerator), http.StatusOK, &user)
+	assert.Equal(t, entities.RoleModerator, user.Role)
+	at.decode(setRole("tenant", entities.RoleTenant), http.StatusOK, nil)
+	at.requireError(setRole("tenant", "Superuser"), http.StatusBadRequest, "bad_request")
+	at.requireError(setRole("admin", entities.RoleUser), http.StatusForbidden, "forbidden")
+	at.requireError(setRole("nobody", entities.RoleUser), http.StatusNotFound, "not_found")
+	moderator, tenant := at.login("moderator"), at.login("tenant")
+
+	// Moderators review and remove properties but cannot manage users
+	var property struct {
+		ID primitive.ObjectID `json:"id"`
+	}
+	at.decode(at.do(http.MethodPost, "/api/v1/properties", landlord, testHouse("Family House")), http.StatusCreated, &property)
+	at.decode(at.do(http.MethodGet, "/api/v1/admin/properties/pending", moderator, nil), http.StatusOK, nil)
+	at.decode(at.do(http.MethodPost, "/api/v1/admin/properties/"+property.ID.Hex()+"/approve", moderator, nil), http.StatusOK, nil)
+	at.requireError(at.do(http.MethodGet, "/api/v1/admin/users", moderator, nil), http.StatusForbidden, "forbidden")
+	at.requireError(at.do(http.MethodPut, "/api/v1/admin/users/tenant/role", moderator, map[string]string{"role": entities.RoleAdmin}), http.StatusForbidden, "forbidden")
+
+	// Tenants rent but cannot list properties
+	at.requireError(at.do(http.MethodPost, "/api/v1/properties", tenant, testHouse("Tenant House")), http.StatusForbidden, "forbidden")
+	at.requireError(at.do(http.MethodGet, "/api/v1/rent-requests/received", tenant, nil), http.StatusForbidden, "forbidden")
+	at.decode(at.do(http.MethodPost, "/api/v1/rent-requests", tenant, map[string]string{"property_id": property.ID.Hex()}), http.StatusCreated, nil)
+	at.requireError(at.do(http.MethodGet, "/api/v1/admin/properties/pending", tenant, nil), http.StatusForbidden, "forbidden")
+
+	path := "/api/v1/properties/" + property.ID.Hex()
+	at.requireError(at.do(http.MethodDelete, path, tenant, nil), http.StatusForbidden, "forbidden")
+	at.decode(at.do(http.MethodDelete, path, moderator, nil), http.StatusNoContent, nil)
+	at.requireError(at.do(http.MethodDelete, path, moderator, nil), http.StatusNotFound, "not_found")
+}
+
+func TestAPI_OpenAPIDocument(t *testing.T) {
+	at := newAPITest(t)
+	rec := at.do(http.MethodGet, "/api/v1/openapi.yaml", "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, strings.HasPrefix(rec.Body.String(), "openapi: 3"))
+
+	// Every endpoint is documented
+	for _, path := range []string{
+		"/signup:", "/login:", "/token/refresh:", "/logout:", "/logout/all:", "/me:", "/wishlist:", "/properties:", "/properties/search:",
+		"/properties/mine:", "/properties/{id}:", "/rent-requests:", "/rent-requests/sent:", "/rent-requests/received:",
+		"/rent-requests/{id}/status:", "/rent-requests/{id}/withdraw:", "/admin/users:", "/admin/users/{username}:", "/admin/users/{username}/role:", "/admin/properties/pending:",
+		"/admin/properties/{id}/approve:", "/leases/as-tenant:", "/leases/as-landlord:", "/leases/{id}:",
+		"/leases/{id}/notice:", "/leases/{id}/renewal:", "/leases/{id}/renewal/accept:", "/leases/{id}/renewal/decline:",
+	} {
+		assert.Contains(t, rec.Body.String(), "\n  "+path+"\n", path)
+	}
+
+	at.requireError(at.do(http.MethodGet, "/api/v1/nothing-here", "", nil), http.StatusNotFound, "not_found")
+}
@@ -0,0 +1,868 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/app/eventbus"
+	"rentease/internal/app/repositories"
+	"rentease/internal/app/services"
+	"rentease/internal/cli"
+	"rentease/internal/documents"
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/interfaces"
+	"rentease/internal/mail"
+	"rentease/pkg/utils"
+)
+
+const testPassword = "Secret@123"
+
+// cliTest runs commands on top of the real services and in-memory repositories.
+type cliTest struct {
+	t                   *testing.T
+	userRepo            interfaces.UserRepo
+	leaseRepo           interfaces.LeaseRepo
+	maintenanceRepo     interfaces.MaintenanceRepo
+	userService         *services.UserService
+	propertyService     *services.PropertyService
+	requestService      *services.RequestService
+	leaseService        *services.LeaseService
+	ledgerService       *services.LedgerService
+	depositService      *services.DepositService
+	maintenanceService  *services.MaintenanceService
+	visitService        *services.VisitService
+	messageService      *services.MessageService
+	notificationService *services.NotificationService
+	documentService     *services.DocumentService
+}
+
+func newCLITest(t *testing.T) *cliTest {
+	t.Setenv(cli.EnvUsername, "")
+	t.Setenv(cli.EnvPassword, testPassword)
+
+	userRepo := repositories.NewInMemoryUserRepo()
+	propertyRepo := repositories.NewInMemoryPropertyRepo()
+	leaseRepo := repositories.NewInMemoryLeaseRepo()
+	ledgerRepo := repositories.NewInMemoryLedgerRepo()
+	maintenanceRepo := repositories.NewInMemoryMaintenanceRepo()
+	requestRepo := repositories.NewInMemoryRequestRepo()
+	notificationRepo := repositories.NewInMemoryNotificationRepo()
+	renderer, err := documents.NewRenderer("")
+	require.NoError(t, err)
+	bus := newEventBus(t, mail.NewMemorySender(), userRepo, propertyRepo, notificationRepo)
+	ct := &cliTest{
+		t:                   t,
+		userRepo:            userRepo,
+		leaseRepo:           leaseRepo,
+		maintenanceRepo:     maintenanceRepo,
+		userService:         services.NewUserService(userRepo, propertyRepo, repositories.NewInMemoryRefreshTokenRepo(), bus),
+		propertyService:     services.NewPropertyService(propertyRepo, bus),
+		requestService:      services.NewRequestService(requestRepo, propertyRepo, leaseRepo, bus),
+		leaseService:        services.NewLeaseService(leaseRepo, propertyRepo, true),
+		ledgerService:       services.NewLedgerService(ledgerRepo, leaseRepo),
+		depositService:      services.NewDepositService(repositories.NewInMemoryDepositRepo(), leaseRepo),
+		maintenanceService:  services.NewMaintenanceService(maintenanceRepo, leaseRepo),
+		visitService:        services.NewVisitService(repositories.NewInMemoryVisitRepo(), propertyRepo),
+		messageService:      services.NewMessageService(repositories.NewInMemoryMessageRepo(), propertyRepo, requestRepo),
+		notificationService: services.NewNotificationService(notificationRepo),
+		documentService:     services.NewDocumentService(leaseRepo, ledgerRepo, propertyRepo, userRepo, renderer),
+	}
+	ct.addUser("landlord", entities.RoleUser)
+	ct.addUser("tenant", entities.RoleUser)
+	ct.addUser("admin", entities.RoleAdmin)
+	return ct
+}
+
+// newEventBus delivers the events of the services as the app does, emailing through the sender with the
+// built-in templates. The email queue is waited for on cleanup.
+func newEventBus(t *testing.T, sender mail.Sender, userRepo interfaces.UserRepo, propertyRepo interfaces.PropertyRepo, notificationRepo interfaces.NotificationRepo) *eventbus.Bus {
+	templates, err := mail.LoadTemplates("")
+	require.NoError(t, err)
+	emailNotifier := mail.NewNotifier("RentEase <no-reply@rentease.test>", templates, mail.NewQueue(sender, 100, 1, time.Millisecond), userRepo, propertyRepo)
+	t.Cleanup(func() { _ = emailNotifier.Close(context.Background()) })
+
+	bus := eventbus.New()
+	services.SubscribeNotifications(bus, notificationRepo)
+	services.SubscribeEmails(bus, emailNotifier)
+	return bus
+}
+
+// run runs the command and returns its exit code and output.
+func (ct *cliTest) run(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := cli.New(context.Background(), ct.userService, ct.propertyService, ct.requestService, ct.leaseService, ct.ledgerService, ct.depositService, ct.maintenanceService, ct.visitService, ct.messageService, ct.notificationService, ct.documentService, &stdout, &stderr).Run(args)
+	return code, stdout.String(), stderr.String()
+}
+
+// runJSON runs the command with -json, checks that it succeeded and decodes the output into v.
+func (ct *cliTest) runJSON(v interface{}, args ...string) {
+	ct.t.Helper()
+	code, stdout, stderr := ct.run(append(args, "-json")...)
+	require.Equal(ct.t, cli.ExitOK, code, stderr)
+	require.NoError(ct.t, json.Unmarshal([]byte(stdout), v), stdout)
+}
+
+func (ct *cliTest) addUser(username, role string) {
+	ct.t.Helper()
+	hash, err := utils.HashPassword(testPassword)
+	require.NoError(ct.t, err)
+	require.NoError(ct.t, ct.userRepo.SaveUser(context.Background(), entities.User{Username: username, Name: "Test " + username, PasswordHash: hash, Role: role}))
+}
+
+func (ct *cliTest) session(username string) *entities.Session {
+	ct.t.Helper()
+	session, err := ct.userService.Login(context.Background(), username, testPassword)
+	require.NoError(ct.t, err)
+	return session
+}
+
+// listHouse lists a house of the landlord, approved by the admin if approve is set.
+func (ct *cliTest) listHouse(title string, approve bool) primitive.ObjectID {
+	ct.t.Helper()
+	property := entities.Property{
+		ID:           primitive.NewObjectID(),
+		PropertyType: 2,
+		Title:        title,
+		Address:      entities.Address{Area: "Suburb", City: "Smalltown", State: "Punjab", Pincode: 141001},
+		RentAmount:   15000,
+		Details:      entities.HouseDetails{NoOfRooms: 3, FurnishedCategory: "Furnished"},
+	}
+	require.NoError(ct.t, ct.propertyService.ListProperty(context.Background(), ct.session("landlord"), property))
+	if approve {
+		require.NoError(ct.t, ct.propertyService.ApproveProperty(context.Background(), ct.session("admin"), property.ID))
+	}
+	return property.ID
+}
+
+func TestCLI_Usage(t *testing.T) {
+	ct := newCLITest(t)
+
+	code, stdout, _ := ct.run()
+	assert.Equal(t, cli.ExitOK, code)
+	assert.Contains(t, stdout, "property list")
+	assert.Contains(t, stdout, "Exit codes")
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"Unknown command", []string{"property", "rent"}},
+		{"Missing subcommand", []string{"user"}},
+		{"Unknown flag", []string{"property", "list", "-colour"}},
+		{"Missing argument", []string{"property", "show"}},
+		{"Extra argument", []string{"user", "list", "everyone"}},
+		{"Invalid ID", []string{"admin", "approve", "not-an-id"}},
+		{"Unknown output format", []string{"property", "list", "-output", "xml"}},
+		{"Two output formats", []string{"property", "list", "-json", "-csv"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := ct.run(tt.args...)
+			assert.Equal(t, cli.ExitUsage, code)
+			assert.Empty(t, stdout)
+			assert.Contains(t, stderr, "Usage:")
+		})
+	}
+
+	t.Run("Help of a command", func(t *testing.T) {
+		code, _, stderr := ct.run("property", "list", "-h")
+		assert.Equal(t, cli.ExitOK, code)
+		assert.Contains(t, stderr, "-landlord")
+	})
+}
+
+func TestCLI_PropertyList(t *testing.T) {
+	ct := newCLITest(t)
+	approved := ct.listHouse("Approved House", true)
+	ct.listHouse("Pending House", false)
+
+	t.Run("Table", func(t *testing.T) {
+		code, stdout, _ := ct.run("property", "list")
+		assert.Equal(t, cli.ExitOK, code)
+		assert.Contains(t, stdout, "Approved House")
+		assert.NotContains(t, stdout, "Pending House")
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		var properties []entities.Property
+		ct.runJSON(&properties, "property", "list")
+		require.Len(t, properties, 1)
+		assert.Equal(t, approved, properties[0].ID)
+
+		ct.runJSON(&properties, "property", "list", "-landlord", "landlord")
+		assert.Len(t, properties, 2)
+		ct.runJSON(&properties, "property", "list", "-landlord", "nobody")
+		assert.Empty(t, properties)
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		code, stdout, _ := ct.run("property", "list", "-output", "csv")
+		assert.Equal(t, cli.ExitOK, code)
+		records, err := csv.NewReader(bytes.NewBufferString(stdout)).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, "ID", records[0][0])
+		assert.Equal(t, []string{approved.Hex(), "Approved House", "House"}, records[1][:3])
+	})
+
+	t.Run("Pending properties need a reviewer", func(t *testing.T) {
+		code, _, stderr := ct.run("property", "list", "-pending")
+		assert.Equal(t, cli.ExitDenied, code)
+		assert.Contains(t, stderr, cli.EnvUsername)
+
+		code, _, _ = ct.run("property", "list", "-pending", "-user", "landlord")
+		assert.Equal(t, cli.ExitDenied, code)
+
+		var properties []entities.Property
+		ct.runJSON(&properties, "property", "list", "-pending", "-user", "admin")
+		require.Len(t, properties, 1)
+		assert.Equal(t, "Pending House", properties[0].Title)
+	})
+
+	t.Run("Show", func(t *testing.T) {
+		var property entities.Property
+		ct.runJSON(&property, "property", "show", approved.Hex())
+		assert.Equal(t, "Approved House", property.Title)
+
+		code, _, _ := ct.run("property", "show", primitive.NewObjectID().Hex())
+		assert.Equal(t, cli.ExitNotFound, code)
+	})
+}
+
+func TestCLI_AdminApprove(t *testing.T) {
+	ct := newCLITest(t)
+	first := ct.listHouse("First House", false)
+	second := ct.listHouse("Second House", false)
+
+	code, _, _ := ct.run("admin", "approve", first.Hex(), "-user", "landlord")
+	assert.Equal(t, cli.ExitDenied, code)
+
+	t.Setenv(cli.EnvPassword, "wrong")
+	code, _, _ = ct.run("admin", "approve", first.Hex(), "-user", "admin")
+	assert.Equal(t, cli.ExitDenied, code)
+	t.Setenv(cli.EnvPassword, testPassword)
+
+	t.Setenv(cli.EnvUsername, "admin")
+	var properties []entities.Property
+	ct.runJSON(&properties, "admin", "approve", first.Hex(), second.Hex())
+	require.Len(t, properties, 2)
+	assert.True(t, properties[0].IsApprovedByAdmin)
+	assert.True(t, properties[1].IsApprovedByAdmin)
+
+	ct.runJSON(&properties, "admin", "pending")
+	assert.Empty(t, properties)
+
+	code, _, _ = ct.run("admin", "approve", primitive.NewObjectID().Hex())
+	assert.Equal(t, cli.ExitNotFound, code)
+}
+
+func TestCLI_RequestAccept(t *testing.T) {
+	ct := newCLITest(t)
+	propertyID := ct.listHouse("Family House", true)
+	require.NoError(t, ct.requestService.CreateRentRequest(context.Background(), ct.session("tenant"), propertyID))
+
+	var requests []entities.Request
+	ct.runJSON(&requests, "request", "list", "-user", "tenant")
+	require.Len(t, requests, 1)
+	requestID := requests[0].ID.Hex()
+
+	// Only the landlord of the property can answer the request
+	code, _, _ := ct.run("request", "accept", requestID, "-user", "tenant")
+	assert.Equal(t, cli.ExitNotFound, code)
+
+	var request entities.Request
+	ct.runJSON(&request, "request", "accept", requestID, "-user", "landlord")
+	assert.Equal(t, entities.RequestAccepted, request.RequestStatus)
+
+	ct.runJSON(&requests, "request", "list", "-received", "-user", "landlord")
+	require.Len(t, requests, 1)
+	assert.Equal(t, entities.RequestAccepted, requests[0].RequestStatus)
+
+	property, err := ct.propertyService.FindByID(context.Background(), propertyID)
+	require.NoError(t, err)
+	assert.True(t, property.IsRented)
+
+	// Accepted requests can no longer be withdrawn or expired
+	code, _, stderr := ct.run("request", "withdraw", requestID, "-user", "tenant")
+	assert.Equal(t, cli.ExitFailure, code)
+	assert.Contains(t, stderr, "accepted")
+	var expired map[string]int
+	ct.runJSON(&expired, "request", "expire", "-older-than", "1ns", "-user", "admin")
+	assert.Equal(t, 0, expired["expired"])
+}
+
+func TestCLI_Leases(t *testing.T) {
+	ct := newCLITest(t)
+	propertyID := ct.listHouse("Family House", true)
+	require.NoError(t, ct.requestService.CreateRentRequest(context.Background(), ct.session("tenant"), propertyID))
+	var requests []entities.Request
+	ct.runJSON(&requests, "request", "list", "-user", "tenant")
+	require.Len(t, requests, 1)
+	ct.runJSON(&requests[0], "request", "accept", requests[0].ID.Hex(), "-user", "landlord")
+
+	var leases []entities.Lease
+	ct.runJSON(&leases, "lease", "list", "-user", "tenant")
+	require.Len(t, leases, 1)
+	assert.Equal(t, propertyID, leases[0].PropertyID)
+	assert.Equal(t, entities.LeasePending, leases[0].Status)
+	ct.runJSON(&leases, "lease", "list", "-landlord", "-user", "landlord")
+	require.Len(t, leases, 1)
+
+	code, stdout, stderr := ct.run("lease", "show", leases[0].ID.Hex(), "-user", "landlord")
+	assert.Equal(t, cli.ExitOK, code, stderr)
+	assert.Contains(t, stdout, leases[0].StartDate.Format("2006-01-02"))
+	assert.Contains(t, stdout, "15000.00")
+
+	code, _, _ = ct.run("lease", "show", leases[0].ID.Hex(), "-user", "admin")
+	assert.Equal(t, cli.ExitOK, code)
+	code, _, _ = ct.run("lease", "show", primitive.NewObjectID().Hex(), "-user", "tenant")
+	assert.Equal(t, cli.ExitNotFound, code)
+}
+
+func TestCLI_LeaseNoticeAndEnd(t *testing.T) {
+	ct := newCLITest(t)
+	propertyID := ct.listHouse("Family House", true)
+	require.NoError(t, ct.requestService.CreateRentRequest(context.Background(), ct.session("tenant"), propertyID))
+	var requests []entities.Request
+	ct.runJSON(&requests, "request", "list", "-user", "tenant")
+	require.Len(t, requests, 1)
+	landlord := ct.session("landlord")
+	require.NoError(t, ct.requestService.UpdateRequestStatus(context.Background(), landlord, requests[0].ID, entities.RequestAccepted))
+	require.NoError(t, ct.requestService.UpdateRequestStatus(context.Background(), landlord, requests[0].ID, entities.RequestLeaseSigned))
+	var leases []entities.Lease
+	ct.runJSON(&leases, "lease", "list", "-user", "tenant")
+	require.Len(t, leases, 1)
+	leaseID := leases[0].ID.Hex()
+
+	code, _, _ := ct.run("lease", "offer-renewal", leaseID, "-months", "0", "-user", "landlord")
+	assert.Equal(t, cli.ExitUsage, code)
+	var lease entities.Lease
+	ct.runJSON(&lease, "lease", "offer-renewal", leaseID, "-rent", "16000", "-user", "landlord")
+	require.NotNil(t, lease.Renewal)
+	assert.Equal(t, leases[0].EndDate.AddDate(0, services.DefaultLeaseMonths, 0), lease.Renewal.EndDate)
+	ct.runJSON(&lease, "lease", "decline-renewal", leaseID, "-user", "tenant")
+	assert.Equal(t, entities.RenewalDeclined, lease.Renewal.Status)
+
+	ct.runJSON(&lease, "lease", "notice", leaseID, "-user", "landlord")
+	assert.Equal(t, entities.LeaseEnding, lease.Status)
+
+	// Nothing is due before the move-out date
+	var ended map[string]int
+	ct.runJSON(&ended, "lease", "end-due", "-user", "admin")
+	assert.Equal(t, 0, ended["ended"])
+	code, _, _ = ct.run("lease", "end-due", "-user", "landlord")
+	assert.Equal(t, cli.ExitDenied, code)
+
+	// Move the move-out date to the past
+	lease.EndDate = time.Now().Add(-time.Hour)
+	updated, err := ct.leaseRepo.UpdateLease(context.Background(), lease, entities.LeaseEnding)
+	require.NoError(t, err)
+	require.True(t, updated)
+	ct.runJSON(&ended, "lease", "end-due", "-user", "admin")
+	assert.Equal(t, 1, ended["ended"])
+	ct.runJSON(&lease, "lease", "show", leaseID, "-user", "tenant")
+	assert.Equal(t, entities.LeaseEnded, lease.Status)
+
+	// The property is back on the market once it is approved again
+	property, err := ct.propertyService.FindByID(context.Background(), propertyID)
+	require.NoError(t, err)
+	assert.False(t, property.IsRented)
+	assert.False(t, property.IsApprovedByAdmin)
+	require.NoError(t, ct.propertyService.ApproveProperty(context.Background(), ct.session("admin"), propertyID))
+	require.NoError(t, ct.requestService.CreateRentRequest(context.Background(), ct.session("tenant"), propertyID))
+}
+
+func TestCLI_Ledger(t *testing.T) {
+	ct := newCLITest(t)
+	propertyID := ct.listHouse("Family House", true)
+	require.NoError(t, ct.requestService.CreateRentRequest(context.Background(), ct.session("tenant"), propertyID))
+	var requests []entities.Request
+	ct.runJSON(&requests, "request", "list", "-user", "tenant")
+	require.Len(t, requests, 1)
+	landlord := ct.session("landlord")
+	require.NoError(t, ct.requestService.UpdateRequestStatus(context.Background(), landlord, requests[0].ID, entities.RequestAccepted))
+	require.NoError(t, ct.requestService.UpdateRequestStatus(context.Background(), landlord, requests[0].ID, entities.RequestLeaseSigned))
+	var leases []entities.Lease
+	ct.runJSON(&leases, "lease", "list", "-user", "tenant")
+	require.Len(t, leases, 1)
+	leaseID := leases[0].ID.Hex()
+
+	// Move the start of the lease two months back, so three months of rent are due
+	lease := leases[0]
+	lease.StartDate = lease.StartDate.AddDate(0, -2, 0)
+	updated, err := ct.leaseRepo.UpdateLease(context.Background(), lease, entities.LeaseActive)
+	require.NoError(t, err)
+	require.True(t, updated)
+
+	var payment entities.Payment
+	paidOn := lease.StartDate.AddDate(0, 0, 3).Format("2006-01-02")
+	ct.runJSON(&payment, "ledger", "record", leaseID, "-amount", "20000", "-date", paidOn, "-mode", "cheque", "-reference", "000123", "-user", "landlord")
+	assert.Equal(t, entities.PaymentCheque, payment.Mode)
+	code, _, _ := ct.run("ledger", "record", leaseID, "-amount", "100", "-mode", "barter", "-user", "landlord")
+	assert.Equal(t, cli.ExitUsage, code)
+	code, _, _ = ct.run("ledger", "record", leaseID, "-amount", "100", "-date", "someday", "-user", "landlord")
+	assert.Equal(t, cli.ExitUsage, code)
+	code, _, _ = ct.run("ledger", "record", leaseID, "-amount", "100", "-user", "tenant")
+	assert.Equal(t, cli.ExitDenied, code)
+
+	var statement entities.Statement
+	ct.runJSON(&statement, "ledger", "show", leaseID, "-user", "tenant")
+	require.Len(t, statement.Lines, 3)
+	assert.Equal(t, 45000.0, statement.TotalDue)
+	assert.Equal(t, 25000.0, statement.Outstanding)
+	assert.Equal(t, 3, statement.Lines[0].LateDays)
+	assert.Equal(t, 5000.0, statement.Lines[1].Paid)
+
+	code, stdout, _ := ct.run("ledger", "show", leaseID, "-user", "landlord")
+	assert.Equal(t, cli.ExitOK, code)
+	assert.Contains(t, stdout, "25000.00")
+	code, _, _ = ct.run("ledger", "show", primitive.NewObjectID().Hex(), "-user", "landlord")
+	assert.Equal(t, cli.ExitNotFound, code)
+}
+
+func TestCLI_RentRules(t *testing.T) {
+	ct := newCLITest(t)
+	propertyID := ct.listHouse("Family House", true)
+	require.NoError(t, ct.requestService.CreateRentRequest(context.Background(), ct.session("tenant"), propertyID))
+	var requests []entities.Request
+	ct.runJSON(&requests, "request", "list", "-user", "tenant")
+	require.Len(t, requests, 1)
+	landlord := ct.session("landlord")
+	require.NoError(t, ct.requestService.UpdateRequestStatus(context.Background(), landlord, requests[0].ID, entities.RequestAccepted))
+	require.NoError(t, ct.requestService.UpdateRequestStatus(context.Background(), landlord, requests[0].ID, entities.RequestLeaseSigned))
+	var leases []entities.Lease
+	ct.runJSON(&leases, "lease", "list", "-user", "tenant")
+	require.Len(t, leases, 1)
+	leaseID := leases[0].ID.Hex()
+
+	// A dry run with rule flags leaves the lease as it is
+	var schedule entities.Schedule
+	ct.runJSON(&schedule, "ledger", "schedule", leaseID, "-months", "6", "-escalate-amount", "500", "-escalate-every", "3", "-late-per-day", "100", "-late-grace", "4", "-user", "tenant")
+	require.Len(t, schedule.Dues, 6)
+	assert.Equal(t, 15000.0, schedule.Dues[1].Amount)
+	assert.Equal(t, 15500.0, schedule.Dues[2].Amount)
+	assert.Equal(t, 16000.0, schedule.Dues[5].Amount)
+	var lease entities.Lease
+	ct.runJSON(&lease, "lease", "show", leaseID, "-user", "tenant")
+	assert.Nil(t, lease.Rules)
+
+	code, _, _ := ct.run("lease", "rules", leaseID, "-late-grace", "4", "-user", "landlord")
+	assert.Equal(t, cli.ExitUsage, code)
+	code, _, _ = ct.run("lease", "rules", leaseID, "-escalate-percent", "5", "-user", "tenant")
+	assert.Equal(t, cli.ExitDenied, code)
+	ct.runJSON(&lease, "lease", "rules", leaseID, "-escalate-percent", "5", "-late-flat", "250", "-late-max", "1000", "-user", "landlord")
+	require.NotNil(t, lease.Rules)
+	assert.Equal(t, 12, lease.Rules.Escalation.EveryMonths)
+	assert.Equal(t, 250.0, lease.Rules.LateFee.Flat)
+
+	code, stdout, _ := ct.run("ledger", "schedule", leaseID, "-months", "3", "-user", "landlord")
+	assert.Equal(t, cli.ExitOK, code)
+	assert.Contains(t, stdout, "LATE FEE")
+	assert.Contains(t, stdout, "250.00")
+	code, _, _ = ct.run("ledger", "schedule", leaseID, "-months", "0", "-user", "landlord")
+	assert.Equal(t, cli.ExitUsage, code)
+
+	// No rule flags remove the rules
+	var cleared entities.Lease
+	ct.runJSON(&cleared, "lease", "rules", leaseID, "-user", "landlord")
+	assert.Nil(t, cleared.Rules)
+}
+
+func TestCLI_Documents(t *testing.T) {
+	ct := newCLITest(t)
+	propertyID := ct.listHouse("Family House", true)
+	require.NoError(t, ct.requestService.CreateRentRequest(context.Background(), ct.session("tenant"), propertyID))
+	var requests []entities.Request
+	ct.runJSON(&requests, "request", "list", "-user", "tenant")
+	require.Len(t, requests, 1)
+	landlord := ct.session("landlord")
+	require.NoError(t, ct.requestService.UpdateRequestStatus(context.Background(), landlord, requests[0].ID, entities.RequestAccepted))
+	require.NoError(t, ct.requestService.UpdateRequestStatus(context.Background(), landlord, requests[0].ID, entities.RequestLeaseSigned))
+	var leases []entities.Lease
+	ct.runJSON(&leases, "lease", "list", "-user", "tenant")
+	require.Len(t, leases, 1)
+	leaseID := leases[0].ID.Hex()
+	var payment entities.Payment
+	ct.runJSON(&payment, "ledger", "record", leaseID, "-amount", "15000", "-mode", "upi", "-user", "landlord")
+
+	var payments []entities.Payment
+	ct.runJSON(&payments, "ledger", "payments", leaseID, "-user", "tenant")
+	require.Len(t, payments, 1)
+	assert.Equal(t, payment.ID, payments[0].ID)
+	dir := t.TempDir()
+
+	var saved map[string]interface{}
+	receipt := filepath.Join(dir, "receipt.pdf")
+	ct.runJSON(&saved, "document", "receipt", leaseID, payments[0].ID.Hex(), "-out", receipt, "-user", "tenant")
+	assert.Equal(t, receipt, saved["file"])
+	content, err := os.ReadFile(receipt)
+	require.NoError(t, err)
+	assert.EqualValues(t, len(content), saved["bytes"])
+	assert.Contains(t, string(content), "(Rent Receipt) Tj")
+
+	code, stdout, _ := ct.run("document", "lease", leaseID, "-out", "-", "-user", "landlord")
+	assert.Equal(t, cli.ExitOK, code)
+	assert.True(t, strings.HasPrefix(stdout, "%PDF-"))
+	assert.Contains(t, stdout, "(Lease Agreement) Tj")
+
+	code, _, _ = ct.run("document", "receipt", leaseID, primitive.NewObjectID().Hex(), "-out", receipt, "-user", "tenant")
+	assert.Equal(t, cli.ExitNotFound, code)
+	code, _, _ = ct.run("document", "lease", leaseID, "-out", "-", "-user", "admin")
+	assert.Equal(t, cli.ExitDenied, code)
+
+	templates := filepath.Join(dir, "templates")
+	var written []string
+	ct.runJSON(&written, "document", "export-templates", templates)
+	assert.Len(t, written, 2)
+	code, _, _ = ct.run("document", "export-templates", templates)
+	assert.Equal(t, cli.ExitFailure, code)
+}
+
+func TestCLI_Deposits(t *testing.T) {
+	ct := newCLITest(t)
+	propertyID := ct.listHouse("Family House", true)
+	require.NoError(t, ct.requestService.CreateRentRequest(context.Background(), ct.session("tenant"), propertyID))
+	var requests []entities.Request
+	ct.runJSON(&requests, "request", "list", "-user", "tenant")
+	require.Len(t, requests, 1)
+	landlord := ct.session("landlord")
+	require.NoError(t, ct.requestService.UpdateRequestStatus(context.Background(), landlord, requests[0].ID, entities.RequestAccepted))
+	require.NoError(t, ct.requestService.UpdateRequestStatus(context.Background(), landlord, requests[0].ID, entities.RequestLeaseSigned))
+	var leases []entities.Lease
+	ct.runJSON(&leases, "lease", "list", "-user", "tenant")
+	require.Len(t, leases, 1)
+	leaseID := leases[0].ID.Hex()
+
+	var deposit entities.Deposit
+	ct.runJSON(&deposit, "deposit", "show", leaseID, "-user", "tenant")
+	assert.Equal(t, entities.DepositAwaited, deposit.Status)
+	ct.runJSON(&deposit, "deposit", "record", leaseID, "-amount", "30000", "-mode", "cheque", "-reference", "000321", "-user", "landlord")
+	assert.Equal(t, entities.DepositHeld, deposit.Status)
+	code, _, _ := ct.run("deposit", "record", leaseID, "-amount", "30000", "-user", "landlord")
+	assert.Equal(t, cli.ExitFailure, code)
+
+	var lease entities.Lease
+	ct.runJSON(&lease, "lease", "notice", leaseID, "-user", "tenant")
+	code, _, _ = ct.run("deposit", "deduct", leaseID, "-deduct", "lots", "-user", "landlord")
+	assert.Equal(t, cli.ExitUsage, code)
+	code, _, _ = ct.run("deposit", "deduct", leaseID, "-deduct", "40000:Everything", "-user", "landlord")
+	assert.Equal(t, cli.ExitUsage, code)
+	ct.runJSON(&deposit, "deposit", "deduct", leaseID, "-deduct", "2500:Cleaning", "-deduct", "1200:Broken window:Bedroom, replaced", "-user", "landlord")
+	assert.Equal(t, entities.DepositProposed, deposit.Status)
+	require.Len(t, deposit.Deductions, 2)
+	assert.Equal(t, "Bedroom, replaced", deposit.Deductions[1].Note)
+	assert.Equal(t, 26300.0, deposit.Refund)
+
+	code, _, _ = ct.run("deposit", "dispute", leaseID, "-user", "tenant")
+	assert.Equal(t, cli.ExitUsage, code)
+	ct.runJSON(&deposit, "deposit", "dispute", leaseID, "-reason", "The window was broken before", "-user", "tenant")
+	assert.Equal(t, entities.DepositDisputed, deposit.Status)
+	code, stdout, _ := ct.run("deposit", "show", leaseID, "-user", "landlord")
+	assert.Equal(t, cli.ExitOK, code)
+	assert.Contains(t, stdout, "The window was broken before")
+	ct.runJSON(&deposit, "deposit", "deduct", leaseID, "-deduct", "2500:Cleaning", "-user", "landlord")
+	ct.runJSON(&deposit, "deposit", "acknowledge", leaseID, "-user", "tenant")
+	assert.Equal(t, entities.DepositClosed, deposit.Status)
+	assert.Equal(t, 27500.0, deposit.Refund)
+
+	code, _, _ = ct.run("deposit", "acknowledge", leaseID, "-user", "landlord")
+	assert.Equal(t, cli.ExitDenied, code)
+	code, _, _ = ct.run("deposit", "show", primitive.NewObjectID().Hex(), "-user", "tenant")
+	assert.Equal(t, cli.ExitNotFound, code)
+}
+
+func TestCLI_Maintenance(t *testing.T) {
+	ct := newCLITest(t)
+	propertyID := ct.listHouse("Family House", true)
+	require.NoError(t, ct.requestService.CreateRentRequest(context.Background(), ct.session("tenant"), propertyID))
+	var requests []entities.Request
+	ct.runJSON(&requests, "request", "list", "-user", "tenant")
+	require.Len(t, requests, 1)
+	landlord := ct.session("landlord")
+	require.NoError(t, ct.requestService.UpdateRequestStatus(context.Background(), landlord, requests[0].ID, entities.RequestAccepted))
+	require.NoError(t, ct.requestService.UpdateRequestStatus(context.Background(), landlord, requests[0].ID, entities.RequestLeaseSigned))
+	var leases []entities.Lease
+	ct.runJSON(&leases, "lease", "list", "-user", "tenant")
+	require.Len(t, leases, 1)
+	leaseID := leases[0].ID.Hex()
+
+	code, _, _ := ct.run("maintenance", "report", leaseID, "-category", "roof", "-description", "Leaks", "-user", "tenant")
+	assert.Equal(t, cli.ExitUsage, code)
+	var ticket entities.MaintenanceTicket
+	ct.runJSON(&ticket, "maintenance", "report", leaseID, "-category", "plumbing", "-priority", "high", "-description", "The kitchen tap is leaking", "-user", "tenant")
+	assert.Equal(t, entities.TicketOpen, ticket.Status)
+	assert.Equal(t, entities.PriorityHigh, ticket.Priority)
+	ticketID := ticket.ID.Hex()
+
+	// An urgent issue reported ten days ago is overdue and heads the queue
+	reported := time.Now().Add(-10 * 24 * time.Hour)
+	require.NoError(t, ct.maintenanceRepo.SaveTicket(context.Background(), entities.MaintenanceTicket{
+		ID: primitive.NewObjectID(), PropertyID: propertyID, LeaseID: leases[0].ID, TenantName: "tenant", LandlordName: "landlord",
+		Category: entities.CategoryElectrical, Description: "No power in the kitchen", Priority: entities.PriorityUrgent,
+		Status: entities.TicketOpen, CreatedAt: reported, UpdatedAt: reported, DueBy: reported.Add(entities.PriorityUrgent.ResolveWithin()),
+	}))
+	var queue []entities.MaintenanceTicket
+	ct.runJSON(&queue, "maintenance", "list", "-landlord", "-user", "landlord")
+	require.Len(t, queue, 2)
+	assert.True(t, queue[0].Overdue)
+	assert.Equal(t, ticket.ID, queue[1].ID)
+	code, stdout, _ := ct.run("maintenance", "list", "-landlord", "-user", "landlord")
+	assert.Equal(t, cli.ExitOK, code)
+	assert.Contains(t, stdout, "open (OVERDUE)")
+
+	var acknowledged entities.MaintenanceTicket
+	ct.runJSON(&acknowledged, "maintenance", "status", ticketID, "-status", "acknowledged", "-note", "A plumber comes tomorrow", "-user", "landlord")
+	assert.Equal(t, entities.TicketAcknowledged, acknowledged.Status)
+	code, _, _ = ct.run("maintenance", "status", ticketID, "-status", "closed", "-user", "tenant")
+	assert.Equal(t, cli.ExitFailure, code)
+	code, _, _ = ct.run("maintenance", "status", ticketID, "-status", "resolved", "-user", "tenant")
+	assert.Equal(t, cli.ExitDenied, code)
+	var commented entities.MaintenanceTicket
+	ct.runJSON(&commented, "maintenance", "comment", ticketID, "-text", "Thanks, I will be home", "-user", "tenant")
+	require.Len(t, commented.Comments, 2)
+
+	code, stdout, _ = ct.run("maintenance", "show", ticketID, "-user", "tenant")
+	assert.Equal(t, cli.ExitOK, code)
+	assert.Contains(t, stdout, "The kitchen tap is leaking")
+	assert.Contains(t, stdout, "A plumber comes tomorrow")
+	assert.Contains(t, stdout, "Thanks, I will be home")
+
+	var tickets []entities.MaintenanceTicket
+	ct.runJSON(&tickets, "maintenance", "list", "-user", "tenant")
+	assert.Len(t, tickets, 2)
+	code, _, _ = ct.run("maintenance", "show", primitive.NewObjectID().Hex(), "-user", "tenant")
+	assert.Equal(t, cli.ExitNotFound, code)
+}
+
+func TestCLI_Visits(t *testing.T) {
+	ct := newCLITest(t)
+	ct.addUser("tenant2", entities.RoleUser)
+	propertyID := ct.listHouse("Family House", true).Hex()
+	tomorrow := time.Now().Add(24 * time.Hour)
+	first := tomorrow.Format("2006-01-02") + " 10:00"
+	second := tomorrow.Format("2006-01-02") + " 11:00"
+
+	code, _, _ := ct.run("visit", "publish", propertyID, "-at", "tomorrow", "-user", "landlord")
+	assert.Equal(t, cli.ExitUsage, code)
+	code, _, _ = ct.run("visit", "publish", propertyID, "-at", first, "-user", "tenant")
+	assert.Equal(t, cli.ExitDenied, code)
+	var slots []entities.VisitSlot
+	ct.runJSON(&slots, "visit", "publish", propertyID, "-at", first, "-at", second, "-length", "45m", "-user", "landlord")
+	require.Len(t, slots, 2)
+	assert.Equal(t, 45*time.Minute, slots[0].End.Sub(slots[0].Start))
+	// The landlord cannot be in two places at once
+	code, _, _ = ct.run("visit", "publish", propertyID, "-at", first, "-user", "landlord")
+	assert.Equal(t, cli.ExitFailure, code)
+
+	var booked entities.VisitSlot
+	ct.runJSON(&booked, "visit", "book", slots[0].ID.Hex(), "-user", "tenant")
+	assert.Equal(t, entities.VisitBooked, booked.Status)
+	assert.Equal(t, "tenant", booked.TenantName)
+	code, _, _ = ct.run("visit", "book", slots[0].ID.Hex(), "-user", "tenant2")
+	assert.Equal(t, cli.ExitFailure, code)
+	var open []entities.VisitSlot
+	ct.runJSON(&open, "visit", "slots", propertyID, "-user", "tenant2")
+	require.Len(t, open, 1)
+	assert.Equal(t, slots[1].ID, open[0].ID)
+
+	var moved entities.VisitSlot
+	ct.runJSON(&moved, "visit", "reschedule", slots[0].ID.Hex(), slots[1].ID.Hex(), "-user", "tenant")
+	assert.Equal(t, slots[1].ID, moved.ID)
+	var visits []entities.VisitSlot
+	ct.runJSON(&visits, "visit", "list", "-landlord", "-user", "landlord")
+	require.Len(t, visits, 1)
+	assert.Equal(t, "tenant", visits[0].TenantName)
+	code, stdout, _ := ct.run("visit", "list", "-user", "tenant")
+	assert.Equal(t, cli.ExitOK, code)
+	assert.Contains(t, stdout, second)
+
+	var cancelled entities.VisitSlot
+	ct.runJSON(&cancelled, "visit", "cancel", slots[1].ID.Hex(), "-user", "landlord")
+	assert.Equal(t, entities.VisitCancelled, cancelled.Status)
+	var none []entities.VisitSlot
+	ct.runJSON(&none, "visit", "list", "-user", "tenant")
+	assert.Empty(t, none)
+	code, _, _ = ct.run("visit", "cancel", slots[1].ID.Hex(), "-user", "landlord")
+	assert.Equal(t, cli.ExitFailure, code)
+	code, _, _ = ct.run("visit", "book", primitive.NewObjectID().Hex(), "-user", "tenant")
+	assert.Equal(t, cli.ExitNotFound, code)
+}
+
+func TestCLI_Messages(t *testing.T) {
+	ct := newCLITest(t)
+	propertyID := ct.listHouse("Family House", true)
+	require.NoError(t, ct.requestService.CreateRentRequest(context.Background(), ct.session("tenant"), propertyID))
+	var requests []entities.Request
+	ct.runJSON(&requests, "request", "list", "-user", "tenant")
+	require.Len(t, requests, 1)
+
+	code, _, _ := ct.run("message", "property", propertyID.Hex(), "-user", "tenant")
+	assert.Equal(t, cli.ExitUsage, code)
+	code, _, _ = ct.run("message", "property", propertyID.Hex(), "-text", "Hello", "-user", "landlord")
+	assert.Equal(t, cli.ExitDenied, code)
+	var thread entities.Thread
+	ct.runJSON(&thread, "message", "property", propertyID.Hex(), "-text", "Is it still available?", "-user", "tenant")
+	require.Len(t, thread.Messages, 1)
+	assert.Equal(t, "landlord", thread.LandlordName)
+
+	// A message about the request goes to the same thread
+	var unread entities.UnreadMessages
+	ct.runJSON(&unread, "message", "unread", "-user", "landlord")
+	assert.Equal(t, entities.UnreadMessages{AsLandlord: 1}, unread)
+	var answered entities.Thread
+	ct.runJSON(&answered, "message", "request", requests[0].ID.Hex(), "-text", "Yes, it is", "-user", "landlord")
+	assert.Equal(t, thread.ID, answered.ID)
+	assert.Len(t, answered.Messages, 2)
+	// The tenant's message stays unread for the landlord, who answered without opening the thread
+	assert.Equal(t, 1, answered.LandlordUnread)
+	assert.Equal(t, 1, answered.TenantUnread)
+
+	code, stdout, _ := ct.run("message", "list", "-user", "tenant")
+	assert.Equal(t, cli.ExitOK, code)
+	assert.Contains(t, stdout, thread.ID.Hex())
+	code, stdout, _ = ct.run("message", "show", thread.ID.Hex(), "-user", "tenant")
+	assert.Equal(t, cli.ExitOK, code)
+	assert.Contains(t, stdout, "Yes, it is")
+	var read entities.UnreadMessages
+	ct.runJSON(&read, "message", "unread", "-user", "tenant")
+	assert.Zero(t, read.AsTenant)
+
+	var replied entities.Thread
+	ct.runJSON(&replied, "message", "send", thread.ID.Hex(), "-text", "Great, thanks", "-user", "tenant")
+	assert.Len(t, replied.Messages, 3)
+	ct.addUser("tenant2", entities.RoleUser)
+	code, _, _ = ct.run("message", "show", thread.ID.Hex(), "-user", "tenant2")
+	assert.Equal(t, cli.ExitDenied, code)
+	code, _, _ = ct.run("message", "send", primitive.NewObjectID().Hex(), "-text", "Hello", "-user", "tenant")
+	assert.Equal(t, cli.ExitNotFound, code)
+}
+
+func TestCLI_Notifications(t *testing.T) {
+	ct := newCLITest(t)
+	approved := ct.listHouse("Family House", true)
+	pending := ct.listHouse("Small Flat", false)
+
+	// The landlord hears of the approval and of the new request
+	require.NoError(t, ct.requestService.CreateRentRequest(context.Background(), ct.session("tenant"), approved))
+	var unread int
+	ct.runJSON(&unread, "notification", "unread", "-user", "landlord")
+	assert.Equal(t, 2, unread)
+	var notifications []entities.Notification
+	ct.runJSON(&notifications, "notification", "list", "-user", "landlord")
+	require.Len(t, notifications, 2)
+	assert.Equal(t, entities.NotificationRequestCreated, notifications[0].Kind)
+	assert.Equal(t, entities.NotificationPropertyApproved, notifications[1].Kind)
+
+	code, _, _ := ct.run("notification", "read", "-user", "landlord")
+	assert.Equal(t, cli.ExitUsage, code)
+	code, _, _ = ct.run("notification", "read", notifications[0].ID.Hex(), "-user", "tenant")
+	assert.Equal(t, cli.ExitNotFound, code)
+	var read []entities.Notification
+	ct.runJSON(&read, "notification", "read", notifications[0].ID.Hex(), "-user", "landlord")
+	require.Len(t, read, 2)
+	assert.True(t, read[0].Read)
+	var stillUnread []entities.Notification
+	ct.runJSON(&stillUnread, "notification", "list", "-unread", "-user", "landlord")
+	require.Len(t, stillUnread, 1)
+	assert.Equal(t, notifications[1].ID, stillUnread[0].ID)
+
+	// Clearing only removes what was read
+	var cleared []entities.Notification
+	ct.runJSON(&cleared, "notification", "clear", "-user", "landlord")
+	require.Len(t, cleared, 1)
+	assert.Equal(t, notifications[1].ID, cleared[0].ID)
+
+	// Rejecting a listing removes it and tells the landlord why
+	code, _, _ = ct.run("admin", "reject", pending.Hex(), "-user", "landlord")
+	assert.Equal(t, cli.ExitDenied, code)
+	code, _, _ = ct.run("admin", "reject", approved.Hex(), "-user", "admin")
+	assert.Equal(t, cli.ExitFailure, code)
+	var rejected []entities.Property
+	ct.runJSON(&rejected, "admin", "reject", pending.Hex(), "-reason", "No photos", "-user", "admin")
+	require.Len(t, rejected, 1)
+	assert.Equal(t, "Small Flat", rejected[0].Title)
+	property, err := ct.propertyService.FindByID(context.Background(), pending)
+	require.NoError(t, err)
+	assert.True(t, property.ID.IsZero())
+
+	var all []entities.Notification
+	ct.runJSON(&all, "notification", "read", "-all", "-user", "landlord")
+	require.Len(t, all, 2)
+	assert.Equal(t, "admin rejected your listing Small Flat: No photos", all[0].Text)
+	assert.True(t, all[0].Read && all[1].Read)
+	code, _, _ = ct.run("notification", "delete", all[0].ID.Hex(), all[1].ID.Hex(), "-user", "landlord")
+	assert.Equal(t, cli.ExitOK, code)
+	ct.runJSON(&unread, "notification", "unread", "-user", "landlord")
+	assert.Zero(t, unread)
+	code, _, _ = ct.run("notification", "delete", all[0].ID.Hex(), "-user", "landlord")
+	assert.Equal(t, cli.ExitNotFound, code)
+}
+
+func TestCLI_RequestWithdrawAndExpire(t *testing.T) {
+	ct := newCLITest(t)
+	first := ct.listHouse("First House", true)
+	second := ct.listHouse("Second House", true)
+	tenant := ct.session("tenant")
+	require.NoError(t, ct.requestService.CreateRentRequest(context.Background(), tenant, first))
+	require.NoError(t, ct.requestService.CreateRentRequest(context.Background(), tenant, second))
+
+	var requests []entities.Request
+	ct.runJSON(&requests, "request", "list", "-user", "tenant")
+	require.Len(t, requests, 2)
+
+	var request entities.Request
+	ct.runJSON(&request, "request", "withdraw", requests[0].ID.Hex(), "-user", "tenant")
+	assert.Equal(t, entities.RequestWithdrawn, request.RequestStatus)
+
+	code, _, _ := ct.run("request", "expire", "-user", "landlord")
+	assert.Equal(t, cli.ExitDenied, code)
+	var expired map[string]int
+	ct.runJSON(&expired, "request", "expire", "-older-than", "1ns", "-user", "admin")
+	assert.Equal(t, 1, expired["expired"])
+
+	ct.runJSON(&requests, "request", "list", "-user", "tenant")
+	statuses := map[primitive.ObjectID]entities.RequestStatus{}
+	for _, r := range requests {
+		statuses[r.PropertyID] = r.RequestStatus
+	}
+	assert.Equal(t, map[primitive.ObjectID]entities.RequestStatus{first: entities.RequestWithdrawn, second: entities.RequestExpired}, statuses)
+}
+
+func TestCLI_UserCommands(t *testing.T) {
+	ct := newCLITest(t)
+	ct.listHouse("Family House", true)
+	t.Setenv(cli.EnvUsername, "admin")
+
+	var user entities.User
+	ct.runJSON(&user, "user", "set-role", "tenant", entities.RoleTenant)
+	assert.Equal(t, entities.RoleTenant, user.Role)
+
+	code, _, _ := ct.run("user", "set-role", "tenant", "Superuser")
+	assert.Equal(t, cli.ExitUsage, code)
+	code, _, _ = ct.run("user", "delete", "nobody")
+	assert.Equal(t, cli.ExitNotFound, code)
+	code, _, _ = ct.run("user", "list", "-user", "landlord")
+	assert.Equal(t, cli.ExitDenied, code)
+
+	var deleted []string
+	ct.runJSON(&deleted, "user", "delete", "landlord")
+	assert.Equal(t, []string{"landlord"}, deleted)
+
+	var users []entities.User
+	ct.runJSON(&users, "user", "list")
+	assert.Len(t, users, 2)
+
+	properties, err := ct.propertyService.GetAllListedProperties(context.Background(), "landlord")
+	require.NoError(t, err)
+	assert.Empty(t, properties)
+}
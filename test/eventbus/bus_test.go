@@ -0,0 +1,119 @@
+package eventbus_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/app/eventbus"
+	"rentease/internal/domain/events"
+)
+
+func TestBus_DeliversToSubscribersInOrder(t *testing.T) {
+	bus := eventbus.New()
+	var handled []string
+	eventbus.Subscribe(bus, func(ctx context.Context, e events.UserDeleted) error {
+		handled = append(handled, "first "+e.Username)
+		return nil
+	})
+	bus.SubscribeAll(func(ctx context.Context, e events.Event) error {
+		handled = append(handled, "all "+e.EventName())
+		return nil
+	})
+	eventbus.Subscribe(bus, func(ctx context.Context, e events.UserDeleted) error {
+		handled = append(handled, "second "+e.Username)
+		return nil
+	})
+	eventbus.Subscribe(bus, func(ctx context.Context, e events.UserSignedUp) error {
+		handled = append(handled, "signed up "+e.Username)
+		return nil
+	})
+
+	require.NoError(t, bus.Publish(context.Background(), events.UserDeleted{Username: "bob", By: "admin"}))
+	// Subscribers to every event come after the ones of its kind
+	assert.Equal(t, []string{"first bob", "second bob", "all user.deleted"}, handled)
+
+	// Events nobody subscribed to are fine
+	require.NoError(t, eventbus.New().Publish(context.Background(), events.UserDeleted{Username: "bob"}))
+}
+
+func TestBus_JoinsErrors(t *testing.T) {
+	bus := eventbus.New()
+	errFirst, errSecond := errors.New("first failed"), errors.New("second failed")
+	called := 0
+	eventbus.Subscribe(bus, func(ctx context.Context, e events.PropertyApproved) error {
+		called++
+		return errFirst
+	})
+	eventbus.Subscribe(bus, func(ctx context.Context, e events.PropertyApproved) error {
+		called++
+		return nil
+	})
+	bus.SubscribeAll(func(ctx context.Context, e events.Event) error {
+		called++
+		return errSecond
+	})
+
+	err := bus.Publish(context.Background(), events.PropertyApproved{PropertyID: primitive.NewObjectID()})
+	// A failing subscriber does not keep the event from the others
+	assert.Equal(t, 3, called)
+	assert.ErrorIs(t, err, errFirst)
+	assert.ErrorIs(t, err, errSecond)
+	assert.ErrorContains(t, err, "property.approved: first failed")
+}
+
+func TestAuditLog_RecordsEvents(t *testing.T) {
+	var out bytes.Buffer
+	bus := eventbus.New()
+	bus.SubscribeAll(eventbus.NewAuditLog(&out).Record)
+
+	propertyID := primitive.NewObjectID()
+	require.NoError(t, bus.Publish(context.Background(), events.UserSignedUp{Username: "alice"}))
+	require.NoError(t, bus.Publish(context.Background(), events.PropertyRejected{PropertyID: propertyID, Title: "Family House", Landlord: "alice", By: "admin", Reason: "No photos"}))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 2)
+	var entry struct {
+		At    string                 `json:"at"`
+		Event string                 `json:"event"`
+		Data  map[string]interface{} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &entry))
+	assert.NotEmpty(t, entry.At)
+	assert.Equal(t, "property.rejected", entry.Event)
+	assert.Equal(t, map[string]interface{}{
+		"property_id": propertyID.Hex(),
+		"title":       "Family House",
+		"landlord":    "alice",
+		"by":          "admin",
+		"reason":      "No photos",
+	}, entry.Data)
+}
+
+func TestOpenAuditLog_Appends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	for _, username := range []string{"alice", "bob"} {
+		auditLog, err := eventbus.OpenAuditLog(path)
+		require.NoError(t, err)
+		require.NoError(t, auditLog.Record(context.Background(), events.UserDeleted{Username: username, By: "admin"}))
+		require.NoError(t, auditLog.Close())
+	}
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"data":{"username":"alice","by":"admin"}`)
+	assert.Contains(t, lines[1], `"data":{"username":"bob","by":"admin"}`)
+
+	_, err = eventbus.OpenAuditLog(filepath.Join(t.TempDir(), "missing", "audit.log"))
+	assert.ErrorContains(t, err, "failed to open audit log")
+}
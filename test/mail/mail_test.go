@@ -0,0 +1,208 @@
+package mail_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/app/repositories"
+	"rentease/internal/domain/entities"
+	"rentease/internal/mail"
+)
+
+// flakySender fails the first failures sends and records the ones after.
+type flakySender struct {
+	mu       sync.Mutex
+	failures int
+	attempts int
+	sent     []mail.Message
+}
+
+func (s *flakySender) Send(ctx context.Context, message mail.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attempts++
+	if s.attempts <= s.failures {
+		return errors.New("connection refused")
+	}
+	s.sent = append(s.sent, message)
+	return nil
+}
+
+func TestQueue_RetriesUntilSent(t *testing.T) {
+	sender := &flakySender{failures: 2}
+	queue := mail.NewQueue(sender, 10, 3, time.Millisecond)
+	require.NoError(t, queue.Enqueue(mail.Message{To: "tenant@example.com", Subject: "Hello"}))
+	require.NoError(t, queue.Close(context.Background()))
+
+	assert.Equal(t, 3, sender.attempts)
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, "Hello", sender.sent[0].Subject)
+	assert.ErrorIs(t, queue.Enqueue(mail.Message{}), mail.ErrQueueClosed)
+}
+
+func TestQueue_GivesUpAfterMaxAttempts(t *testing.T) {
+	sender := &flakySender{failures: 3}
+	queue := mail.NewQueue(sender, 10, 2, time.Millisecond)
+	require.NoError(t, queue.Enqueue(mail.Message{Subject: "First"}))
+	require.NoError(t, queue.Enqueue(mail.Message{Subject: "Second"}))
+	require.NoError(t, queue.Close(context.Background()))
+
+	// The first is tried twice and given up, the second fails once more before it goes
+	assert.Equal(t, 4, sender.attempts)
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, "Second", sender.sent[0].Subject)
+}
+
+func TestQueue_CloseGivesUpWhenContextIsDone(t *testing.T) {
+	sender := &flakySender{failures: 100}
+	queue := mail.NewQueue(sender, 1, 100, time.Hour)
+	require.NoError(t, queue.Enqueue(mail.Message{Subject: "First"}))
+	require.Eventually(t, func() bool {
+		sender.mu.Lock()
+		defer sender.mu.Unlock()
+		return sender.attempts == 1
+	}, time.Second, time.Millisecond)
+
+	// While the first waits to be tried again the queue fills up
+	require.NoError(t, queue.Enqueue(mail.Message{Subject: "Second"}))
+	assert.ErrorIs(t, queue.Enqueue(mail.Message{Subject: "Third"}), mail.ErrQueueFull)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, queue.Close(ctx), context.DeadlineExceeded)
+	assert.Empty(t, sender.sent)
+}
+
+// blockingSender never finishes a send before its context is done.
+type blockingSender struct {
+	started chan struct{}
+}
+
+func (s *blockingSender) Send(ctx context.Context, message mail.Message) error {
+	close(s.started)
+	<-ctx.Done()
+	time.Sleep(time.Hour) // a sender slow to notice does not hold up Close either
+	return ctx.Err()
+}
+
+func TestQueue_CloseReturnsWhileSending(t *testing.T) {
+	sender := &blockingSender{started: make(chan struct{})}
+	queue := mail.NewQueue(sender, 1, 1, time.Hour)
+	require.NoError(t, queue.Enqueue(mail.Message{Subject: "Stuck"}))
+	<-sender.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	assert.ErrorIs(t, queue.Close(ctx), context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestSMTPSender_GivesUpOnSilentServer(t *testing.T) {
+	// The server takes the connection but never greets
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+	addr := listener.Addr().(*net.TCPAddr)
+	message := mail.Message{From: "no-reply@rentease.local", To: "asha@example.com", Subject: "Hi"}
+
+	sender := mail.NewSMTPSender("127.0.0.1", addr.Port, "", "", 20*time.Millisecond)
+	start := time.Now()
+	assert.ErrorIs(t, sender.Send(context.Background(), message), context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+
+	// Cancelling the context stops the send too
+	sender = mail.NewSMTPSender("127.0.0.1", addr.Port, "", "", time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	start = time.Now()
+	assert.ErrorIs(t, sender.Send(ctx, message), context.Canceled)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestTemplates_ReplacedFromDirectory(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, mail.TemplateName(entities.EmailWelcome)), []byte("Subject: Hi {{.Name}}\n\nWelcome aboard, {{.Username}}.\n"), 0o600))
+	templates, err := mail.LoadTemplates(dir)
+	require.NoError(t, err)
+
+	sender := mail.NewMemorySender()
+	userRepo := repositories.NewInMemoryUserRepo()
+	propertyRepo := repositories.NewInMemoryPropertyRepo()
+	require.NoError(t, userRepo.SaveUser(context.Background(), entities.User{Username: "tenant1", Name: "Asha", Email: "asha@example.com"}))
+	require.NoError(t, userRepo.SaveUser(context.Background(), entities.User{Username: "tenant2"}))
+	property := entities.Property{ID: primitive.NewObjectID(), Title: "Family House", LandlordUsername: "landlord1"}
+	require.NoError(t, propertyRepo.SaveProperty(context.Background(), property))
+	notifier := mail.NewNotifier("RentEase <no-reply@example.com>", templates, mail.NewQueue(sender, 10, 1, time.Millisecond), userRepo, propertyRepo)
+
+	require.NoError(t, notifier.Notify(context.Background(), entities.EmailNotice{Kind: entities.EmailWelcome, Username: "tenant1"}))
+	// The other templates are still the built-in ones
+	require.NoError(t, notifier.Notify(context.Background(), entities.EmailNotice{
+		Kind:       entities.EmailRequestDecided,
+		Username:   "tenant1",
+		PropertyID: property.ID,
+		By:         "landlord1",
+		Status:     entities.RequestRejected,
+		Reason:     "Already let",
+	}))
+	// Users without an address and users that do not exist are not emailed
+	require.NoError(t, notifier.Notify(context.Background(), entities.EmailNotice{Kind: entities.EmailWelcome, Username: "tenant2"}))
+	require.NoError(t, notifier.Notify(context.Background(), entities.EmailNotice{Kind: entities.EmailWelcome, Username: "nobody"}))
+	require.NoError(t, notifier.Close(context.Background()))
+
+	messages := sender.Messages()
+	require.Len(t, messages, 2)
+	assert.Equal(t, mail.Message{From: "RentEase <no-reply@example.com>", To: `"Asha" <asha@example.com>`, Subject: "Hi Asha", Body: "Welcome aboard, tenant1.\n"}, messages[0])
+	assert.Equal(t, "Your request for Family House was rejected", messages[1].Subject)
+	assert.Contains(t, messages[1].Body, "The reason given was: Already let")
+}
+
+func TestTemplates_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, mail.TemplateName(entities.EmailListingApproved))
+	require.NoError(t, os.WriteFile(path, []byte("Subject: {{.Title\n\nBody"), 0o600))
+	_, err := mail.LoadTemplates(dir)
+	assert.ErrorContains(t, err, "failed to parse template listing_approved.tmpl")
+
+	// A template without a subject only fails when an email is written from it
+	require.NoError(t, os.WriteFile(path, []byte("Your listing was approved"), 0o600))
+	templates, err := mail.LoadTemplates(dir)
+	require.NoError(t, err)
+	userRepo := repositories.NewInMemoryUserRepo()
+	require.NoError(t, userRepo.SaveUser(context.Background(), entities.User{Username: "landlord1", Email: "landlord1@example.com"}))
+	notifier := mail.NewNotifier("RentEase <no-reply@example.com>", templates, mail.NewQueue(mail.NewMemorySender(), 10, 1, time.Millisecond), userRepo, repositories.NewInMemoryPropertyRepo())
+	defer notifier.Close(context.Background())
+	err = notifier.Notify(context.Background(), entities.EmailNotice{Kind: entities.EmailListingApproved, Username: "landlord1"})
+	assert.ErrorContains(t, err, "must start with a Subject: line")
+}
+
+func TestFileSender_AppendsMessages(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mail.txt")
+	sender := mail.NewFileSender(path)
+	require.NoError(t, sender.Send(context.Background(), mail.Message{From: "a@example.com", To: "b@example.com", Subject: "First", Body: "One\n"}))
+	require.NoError(t, sender.Send(context.Background(), mail.Message{From: "a@example.com", To: "b@example.com", Subject: "Second", Body: "Two\n"}))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "Subject: First\r\n")
+	assert.Contains(t, string(content), "Subject: Second\r\n")
+	assert.Contains(t, string(content), "Content-Type: text/plain; charset=utf-8\r\n\r\nTwo\r\n")
+}
@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: C:\Users\gmangat\RentEase\internal\domain\interfaces\property_interface.go
+// Source: internal/domain/interfaces/property_repository.go
 
 // Package mocks is a generated GoMock package.
 package mocks
@@ -37,31 +37,31 @@ func (m *MockPropertyRepo) EXPECT() *MockPropertyRepoMockRecorder {
 }
 
 // DeleteAllListedPropertiesOfaUser mocks base method.
-func (m *MockPropertyRepo) DeleteAllListedPropertiesOfaUser(username string) error {
+func (m *MockPropertyRepo) DeleteAllListedPropertiesOfaUser(ctx context.Context, username string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteAllListedPropertiesOfaUser", username)
+	ret := m.ctrl.Call(m, "DeleteAllListedPropertiesOfaUser", ctx, username)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteAllListedPropertiesOfaUser indicates an expected call of DeleteAllListedPropertiesOfaUser.
-func (mr *MockPropertyRepoMockRecorder) DeleteAllListedPropertiesOfaUser(username interface{}) *gomock.Call {
+func (mr *MockPropertyRepoMockRecorder) DeleteAllListedPropertiesOfaUser(ctx, username interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAllListedPropertiesOfaUser", reflect.TypeOf((*MockPropertyRepo)(nil).DeleteAllListedPropertiesOfaUser), username)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAllListedPropertiesOfaUser", reflect.TypeOf((*MockPropertyRepo)(nil).DeleteAllListedPropertiesOfaUser), ctx, username)
 }
 
 // DeleteListedProperty mocks base method.
-func (m *MockPropertyRepo) DeleteListedProperty(propertyID string) error {
+func (m *MockPropertyRepo) DeleteListedProperty(ctx context.Context, propertyID primitive.ObjectID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteListedProperty", propertyID)
+	ret := m.ctrl.Call(m, "DeleteListedProperty", ctx, propertyID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteListedProperty indicates an expected call of DeleteListedProperty.
-func (mr *MockPropertyRepoMockRecorder) DeleteListedProperty(propertyID interface{}) *gomock.Call {
+func (mr *MockPropertyRepoMockRecorder) DeleteListedProperty(ctx, propertyID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteListedProperty", reflect.TypeOf((*MockPropertyRepo)(nil).DeleteListedProperty), propertyID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteListedProperty", reflect.TypeOf((*MockPropertyRepo)(nil).DeleteListedProperty), ctx, propertyID)
 }
 
 // FindByID mocks base method.
@@ -80,73 +80,88 @@ func (mr *MockPropertyRepoMockRecorder) FindByID(ctx, id interface{}) *gomock.Ca
 }
 
 // FindPendingProperties mocks base method.
-func (m *MockPropertyRepo) FindPendingProperties() ([]entities.Property, error) {
+func (m *MockPropertyRepo) FindPendingProperties(ctx context.Context) ([]entities.Property, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindPendingProperties")
+	ret := m.ctrl.Call(m, "FindPendingProperties", ctx)
 	ret0, _ := ret[0].([]entities.Property)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindPendingProperties indicates an expected call of FindPendingProperties.
-func (mr *MockPropertyRepoMockRecorder) FindPendingProperties() *gomock.Call {
+func (mr *MockPropertyRepoMockRecorder) FindPendingProperties(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPendingProperties", reflect.TypeOf((*MockPropertyRepo)(nil).FindPendingProperties))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPendingProperties", reflect.TypeOf((*MockPropertyRepo)(nil).FindPendingProperties), ctx)
 }
 
 // GetAllListedProperties mocks base method.
-func (m *MockPropertyRepo) GetAllListedProperties(activerUseronly bool) ([]entities.Property, error) {
+func (m *MockPropertyRepo) GetAllListedProperties(ctx context.Context, landlordUsername string) ([]entities.Property, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllListedProperties", activerUseronly)
+	ret := m.ctrl.Call(m, "GetAllListedProperties", ctx, landlordUsername)
 	ret0, _ := ret[0].([]entities.Property)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllListedProperties indicates an expected call of GetAllListedProperties.
-func (mr *MockPropertyRepoMockRecorder) GetAllListedProperties(activerUseronly interface{}) *gomock.Call {
+func (mr *MockPropertyRepoMockRecorder) GetAllListedProperties(ctx, landlordUsername interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllListedProperties", reflect.TypeOf((*MockPropertyRepo)(nil).GetAllListedProperties), activerUseronly)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllListedProperties", reflect.TypeOf((*MockPropertyRepo)(nil).GetAllListedProperties), ctx, landlordUsername)
 }
 
 // SaveProperty mocks base method.
-func (m *MockPropertyRepo) SaveProperty(property entities.Property) error {
+func (m *MockPropertyRepo) SaveProperty(ctx context.Context, property entities.Property) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveProperty", property)
+	ret := m.ctrl.Call(m, "SaveProperty", ctx, property)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SaveProperty indicates an expected call of SaveProperty.
-func (mr *MockPropertyRepoMockRecorder) SaveProperty(property interface{}) *gomock.Call {
+func (mr *MockPropertyRepoMockRecorder) SaveProperty(ctx, property interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveProperty", reflect.TypeOf((*MockPropertyRepo)(nil).SaveProperty), property)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveProperty", reflect.TypeOf((*MockPropertyRepo)(nil).SaveProperty), ctx, property)
+}
+
+// SetRented mocks base method.
+func (m *MockPropertyRepo) SetRented(ctx context.Context, propertyID primitive.ObjectID, rented bool) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRented", ctx, propertyID, rented)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetRented indicates an expected call of SetRented.
+func (mr *MockPropertyRepoMockRecorder) SetRented(ctx, propertyID, rented interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRented", reflect.TypeOf((*MockPropertyRepo)(nil).SetRented), ctx, propertyID, rented)
 }
 
 // UpdateApprovalStatus mocks base method.
-func (m *MockPropertyRepo) UpdateApprovalStatus(propertyID primitive.ObjectID, approved bool, adminUsername string) error {
+func (m *MockPropertyRepo) UpdateApprovalStatus(ctx context.Context, propertyID primitive.ObjectID, approved bool, adminUsername string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateApprovalStatus", propertyID, approved, adminUsername)
+	ret := m.ctrl.Call(m, "UpdateApprovalStatus", ctx, propertyID, approved, adminUsername)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateApprovalStatus indicates an expected call of UpdateApprovalStatus.
-func (mr *MockPropertyRepoMockRecorder) UpdateApprovalStatus(propertyID, approved, adminUsername interface{}) *gomock.Call {
+func (mr *MockPropertyRepoMockRecorder) UpdateApprovalStatus(ctx, propertyID, approved, adminUsername interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApprovalStatus", reflect.TypeOf((*MockPropertyRepo)(nil).UpdateApprovalStatus), propertyID, approved, adminUsername)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApprovalStatus", reflect.TypeOf((*MockPropertyRepo)(nil).UpdateApprovalStatus), ctx, propertyID, approved, adminUsername)
 }
 
 // UpdateListedProperty mocks base method.
-func (m *MockPropertyRepo) UpdateListedProperty(property entities.Property) error {
+func (m *MockPropertyRepo) UpdateListedProperty(ctx context.Context, property entities.Property) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateListedProperty", property)
+	ret := m.ctrl.Call(m, "UpdateListedProperty", ctx, property)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateListedProperty indicates an expected call of UpdateListedProperty.
-func (mr *MockPropertyRepoMockRecorder) UpdateListedProperty(property interface{}) *gomock.Call {
+func (mr *MockPropertyRepoMockRecorder) UpdateListedProperty(ctx, property interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateListedProperty", reflect.TypeOf((*MockPropertyRepo)(nil).UpdateListedProperty), property)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateListedProperty", reflect.TypeOf((*MockPropertyRepo)(nil).UpdateListedProperty), ctx, property)
 }
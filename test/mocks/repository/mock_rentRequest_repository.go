@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: C:\Users\gmangat\RentEase\internal\domain\interfaces\rentRequest_repository.go
+// Source: internal/domain/interfaces/rentRequest_repository.go
 
 // Package mocks is a generated GoMock package.
 package mocks
@@ -10,6 +10,7 @@ import (
 	entities "rentease/internal/domain/entities"
 
 	gomock "github.com/golang/mock/gomock"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // MockRequestRepo is a mock of RequestRepo interface.
@@ -35,6 +36,21 @@ func (m *MockRequestRepo) EXPECT() *MockRequestRepoMockRecorder {
 	return m.recorder
 }
 
+// CreateRequest mocks base method.
+func (m *MockRequestRepo) CreateRequest(ctx context.Context, request entities.Request) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRequest", ctx, request)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRequest indicates an expected call of CreateRequest.
+func (mr *MockRequestRepoMockRecorder) CreateRequest(ctx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRequest", reflect.TypeOf((*MockRequestRepo)(nil).CreateRequest), ctx, request)
+}
+
 // FindByLandlordName mocks base method.
 func (m *MockRequestRepo) FindByLandlordName(ctx context.Context, landlordName string) ([]entities.Request, error) {
 	m.ctrl.T.Helper()
@@ -50,6 +66,36 @@ func (mr *MockRequestRepoMockRecorder) FindByLandlordName(ctx, landlordName inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByLandlordName", reflect.TypeOf((*MockRequestRepo)(nil).FindByLandlordName), ctx, landlordName)
 }
 
+// FindByPropertyID mocks base method.
+func (m *MockRequestRepo) FindByPropertyID(ctx context.Context, propertyID primitive.ObjectID) ([]entities.Request, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByPropertyID", ctx, propertyID)
+	ret0, _ := ret[0].([]entities.Request)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByPropertyID indicates an expected call of FindByPropertyID.
+func (mr *MockRequestRepoMockRecorder) FindByPropertyID(ctx, propertyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByPropertyID", reflect.TypeOf((*MockRequestRepo)(nil).FindByPropertyID), ctx, propertyID)
+}
+
+// FindByStatus mocks base method.
+func (m *MockRequestRepo) FindByStatus(ctx context.Context, status entities.RequestStatus) ([]entities.Request, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByStatus", ctx, status)
+	ret0, _ := ret[0].([]entities.Request)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByStatus indicates an expected call of FindByStatus.
+func (mr *MockRequestRepoMockRecorder) FindByStatus(ctx, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByStatus", reflect.TypeOf((*MockRequestRepo)(nil).FindByStatus), ctx, status)
+}
+
 // FindByTenantUsername mocks base method.
 func (m *MockRequestRepo) FindByTenantUsername(ctx context.Context, tenantUsername string) ([]entities.Request, error) {
 	m.ctrl.T.Helper()
@@ -65,30 +111,46 @@ func (mr *MockRequestRepoMockRecorder) FindByTenantUsername(ctx, tenantUsername
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByTenantUsername", reflect.TypeOf((*MockRequestRepo)(nil).FindByTenantUsername), ctx, tenantUsername)
 }
 
+// FindRequestByID mocks base method.
+func (m *MockRequestRepo) FindRequestByID(ctx context.Context, id primitive.ObjectID) (*entities.Request, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRequestByID", ctx, id)
+	ret0, _ := ret[0].(*entities.Request)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRequestByID indicates an expected call of FindRequestByID.
+func (mr *MockRequestRepoMockRecorder) FindRequestByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRequestByID", reflect.TypeOf((*MockRequestRepo)(nil).FindRequestByID), ctx, id)
+}
+
 // SaveRequest mocks base method.
-func (m *MockRequestRepo) SaveRequest(request entities.Request) error {
+func (m *MockRequestRepo) SaveRequest(ctx context.Context, request entities.Request) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveRequest", request)
+	ret := m.ctrl.Call(m, "SaveRequest", ctx, request)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SaveRequest indicates an expected call of SaveRequest.
-func (mr *MockRequestRepoMockRecorder) SaveRequest(request interface{}) *gomock.Call {
+func (mr *MockRequestRepoMockRecorder) SaveRequest(ctx, request interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRequest", reflect.TypeOf((*MockRequestRepo)(nil).SaveRequest), request)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRequest", reflect.TypeOf((*MockRequestRepo)(nil).SaveRequest), ctx, request)
 }
 
-// UpdateRequest mocks base method.
-func (m *MockRequestRepo) UpdateRequest(request entities.Request, status string) error {
+// UpdateRequestStatus mocks base method.
+func (m *MockRequestRepo) UpdateRequestStatus(ctx context.Context, id primitive.ObjectID, change entities.StatusChange) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateRequest", request, status)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "UpdateRequestStatus", ctx, id, change)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// UpdateRequest indicates an expected call of UpdateRequest.
-func (mr *MockRequestRepoMockRecorder) UpdateRequest(request, status interface{}) *gomock.Call {
+// UpdateRequestStatus indicates an expected call of UpdateRequestStatus.
+func (mr *MockRequestRepoMockRecorder) UpdateRequestStatus(ctx, id, change interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRequest", reflect.TypeOf((*MockRequestRepo)(nil).UpdateRequest), request, status)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRequestStatus", reflect.TypeOf((*MockRequestRepo)(nil).UpdateRequestStatus), ctx, id, change)
 }
@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: C:\Users\gmangat\RentEase\internal\domain\interfaces\user_interface.go
+// Source: internal/domain/interfaces/user_repository.go
 
 // Package mocks is a generated GoMock package.
 package mocks
@@ -51,32 +51,32 @@ func (mr *MockUserRepoMockRecorder) CheckPassword(ctx, username, password interf
 }
 
 // Delete mocks base method.
-func (m *MockUserRepo) Delete(username string) error {
+func (m *MockUserRepo) Delete(ctx context.Context, username string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", username)
+	ret := m.ctrl.Call(m, "Delete", ctx, username)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockUserRepoMockRecorder) Delete(username interface{}) *gomock.Call {
+func (mr *MockUserRepoMockRecorder) Delete(ctx, username interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUserRepo)(nil).Delete), username)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUserRepo)(nil).Delete), ctx, username)
 }
 
 // FindAll mocks base method.
-func (m *MockUserRepo) FindAll() ([]entities.User, error) {
+func (m *MockUserRepo) FindAll(ctx context.Context) ([]entities.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAll")
+	ret := m.ctrl.Call(m, "FindAll", ctx)
 	ret0, _ := ret[0].([]entities.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindAll indicates an expected call of FindAll.
-func (mr *MockUserRepoMockRecorder) FindAll() *gomock.Call {
+func (mr *MockUserRepoMockRecorder) FindAll(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockUserRepo)(nil).FindAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockUserRepo)(nil).FindAll), ctx)
 }
 
 // FindByUsername mocks base method.
@@ -95,29 +95,29 @@ func (mr *MockUserRepoMockRecorder) FindByUsername(ctx, username interface{}) *g
 }
 
 // SaveUser mocks base method.
-func (m *MockUserRepo) SaveUser(user entities.User) error {
+func (m *MockUserRepo) SaveUser(ctx context.Context, user entities.User) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveUser", user)
+	ret := m.ctrl.Call(m, "SaveUser", ctx, user)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SaveUser indicates an expected call of SaveUser.
-func (mr *MockUserRepoMockRecorder) SaveUser(user interface{}) *gomock.Call {
+func (mr *MockUserRepoMockRecorder) SaveUser(ctx, user interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUser", reflect.TypeOf((*MockUserRepo)(nil).SaveUser), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUser", reflect.TypeOf((*MockUserRepo)(nil).SaveUser), ctx, user)
 }
 
 // UpdateUser mocks base method.
-func (m *MockUserRepo) UpdateUser(user entities.User) error {
+func (m *MockUserRepo) UpdateUser(ctx context.Context, user entities.User) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateUser", user)
+	ret := m.ctrl.Call(m, "UpdateUser", ctx, user)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateUser indicates an expected call of UpdateUser.
-func (mr *MockUserRepoMockRecorder) UpdateUser(user interface{}) *gomock.Call {
+func (mr *MockUserRepoMockRecorder) UpdateUser(ctx, user interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUserRepo)(nil).UpdateUser), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUserRepo)(nil).UpdateUser), ctx, user)
 }
@@ -0,0 +1,34 @@
+package mock_service
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+)
+
+type MockDepositService struct {
+}
+
+func NewMockDepositService() *MockDepositService {
+	return &MockDepositService{}
+}
+
+func (ms *MockDepositService) Deposit(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID) (entities.Deposit, error) {
+	return entities.Deposit{LeaseID: leaseID, Status: entities.DepositAwaited}, nil
+}
+
+func (ms *MockDepositService) RecordDeposit(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID, receipt entities.Payment) (entities.Deposit, error) {
+	return entities.Deposit{LeaseID: leaseID, Received: receipt.Amount, Refund: receipt.Amount, Status: entities.DepositHeld}, nil
+}
+
+func (ms *MockDepositService) ProposeDeductions(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID, deductions []entities.DepositDeduction) (entities.Deposit, error) {
+	return entities.Deposit{LeaseID: leaseID, Deductions: deductions, Status: entities.DepositProposed}, nil
+}
+
+func (ms *MockDepositService) AcknowledgeDeductions(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID) (entities.Deposit, error) {
+	return entities.Deposit{LeaseID: leaseID, Status: entities.DepositClosed}, nil
+}
+
+func (ms *MockDepositService) DisputeDeductions(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID, reason string) (entities.Deposit, error) {
+	return entities.Deposit{LeaseID: leaseID, Dispute: &entities.DepositDispute{Reason: reason}, Status: entities.DepositDisputed}, nil
+}
@@ -0,0 +1,22 @@
+package mock_service
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+)
+
+type MockDocumentService struct {
+}
+
+func NewMockDocumentService() *MockDocumentService {
+	return &MockDocumentService{}
+}
+
+func (ms *MockDocumentService) RentReceipt(ctx context.Context, session *entities.Session, leaseID, paymentID primitive.ObjectID) (entities.Document, error) {
+	return entities.Document{}, nil
+}
+
+func (ms *MockDocumentService) LeaseAgreement(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID) (entities.Document, error) {
+	return entities.Document{}, nil
+}
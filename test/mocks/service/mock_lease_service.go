@@ -0,0 +1,47 @@
+package mock_service
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+	"time"
+)
+
+type MockLeaseService struct {
+}
+
+func NewMockLeaseService() *MockLeaseService {
+	return &MockLeaseService{}
+}
+
+func (ms *MockLeaseService) GetLeasesForTenant(ctx context.Context, session *entities.Session) ([]entities.Lease, error) {
+	return []entities.Lease{}, nil
+}
+
+func (ms *MockLeaseService) GetLeasesForLandlord(ctx context.Context, session *entities.Session) ([]entities.Lease, error) {
+	return []entities.Lease{}, nil
+}
+
+func (ms *MockLeaseService) GetLease(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID) (entities.Lease, error) {
+	return entities.Lease{}, nil
+}
+
+func (ms *MockLeaseService) GiveNotice(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID) (entities.Lease, error) {
+	return entities.Lease{}, nil
+}
+
+func (ms *MockLeaseService) OfferRenewal(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID, monthlyRent float64, months int) (entities.Lease, error) {
+	return entities.Lease{}, nil
+}
+
+func (ms *MockLeaseService) AnswerRenewal(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID, accept bool) (entities.Lease, error) {
+	return entities.Lease{}, nil
+}
+
+func (ms *MockLeaseService) SetRentRules(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID, rules entities.RentRules) (entities.Lease, error) {
+	return entities.Lease{Rules: &rules}, nil
+}
+
+func (ms *MockLeaseService) EndLeases(ctx context.Context, session *entities.Session, now time.Time) (int, error) {
+	return 0, nil
+}
@@ -0,0 +1,26 @@
+package mock_service
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+)
+
+type MockLedgerService struct {
+}
+
+func NewMockLedgerService() *MockLedgerService {
+	return &MockLedgerService{}
+}
+
+func (ms *MockLedgerService) Statement(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID) (entities.Statement, error) {
+	return entities.Statement{}, nil
+}
+
+func (ms *MockLedgerService) RecordPayment(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID, payment entities.Payment) (entities.Payment, error) {
+	return payment, nil
+}
+
+func (ms *MockLedgerService) Schedule(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID, rules *entities.RentRules, months int) (entities.Schedule, error) {
+	return entities.Schedule{LeaseID: leaseID}, nil
+}
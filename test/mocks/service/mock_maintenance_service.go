@@ -0,0 +1,38 @@
+package mock_service
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+)
+
+type MockMaintenanceService struct {
+}
+
+func NewMockMaintenanceService() *MockMaintenanceService {
+	return &MockMaintenanceService{}
+}
+
+func (ms *MockMaintenanceService) ReportIssue(ctx context.Context, session *entities.Session, leaseID primitive.ObjectID, category entities.MaintenanceCategory, priority entities.MaintenancePriority, description string) (entities.MaintenanceTicket, error) {
+	return entities.MaintenanceTicket{LeaseID: leaseID, Category: category, Priority: priority, Description: description, Status: entities.TicketOpen}, nil
+}
+
+func (ms *MockMaintenanceService) Ticket(ctx context.Context, session *entities.Session, ticketID primitive.ObjectID) (entities.MaintenanceTicket, error) {
+	return entities.MaintenanceTicket{ID: ticketID, Status: entities.TicketOpen}, nil
+}
+
+func (ms *MockMaintenanceService) TicketsForTenant(ctx context.Context, session *entities.Session) ([]entities.MaintenanceTicket, error) {
+	return []entities.MaintenanceTicket{}, nil
+}
+
+func (ms *MockMaintenanceService) MaintenanceQueue(ctx context.Context, session *entities.Session) ([]entities.MaintenanceTicket, error) {
+	return []entities.MaintenanceTicket{}, nil
+}
+
+func (ms *MockMaintenanceService) UpdateTicketStatus(ctx context.Context, session *entities.Session, ticketID primitive.ObjectID, to entities.TicketStatus, note string) (entities.MaintenanceTicket, error) {
+	return entities.MaintenanceTicket{ID: ticketID, Status: to}, nil
+}
+
+func (ms *MockMaintenanceService) AddComment(ctx context.Context, session *entities.Session, ticketID primitive.ObjectID, text string) (entities.MaintenanceTicket, error) {
+	return entities.MaintenanceTicket{ID: ticketID, Status: entities.TicketOpen, Comments: []entities.TicketComment{{Author: session.Username(), Text: text}}}, nil
+}
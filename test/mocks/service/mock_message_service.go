@@ -0,0 +1,39 @@
+package mock_service
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+	"time"
+)
+
+type MockMessageService struct {
+}
+
+func NewMockMessageService() *MockMessageService {
+	return &MockMessageService{}
+}
+
+func (ms *MockMessageService) ContactLandlord(ctx context.Context, session *entities.Session, propertyID primitive.ObjectID, text string) (entities.Thread, error) {
+	return entities.Thread{PropertyID: propertyID, TenantName: session.Username(), Messages: []entities.Message{{Sender: session.Username(), Text: text, SentAt: time.Now()}}}, nil
+}
+
+func (ms *MockMessageService) MessageAboutRequest(ctx context.Context, session *entities.Session, requestID primitive.ObjectID, text string) (entities.Thread, error) {
+	return entities.Thread{Messages: []entities.Message{{Sender: session.Username(), Text: text, SentAt: time.Now()}}}, nil
+}
+
+func (ms *MockMessageService) SendMessage(ctx context.Context, session *entities.Session, threadID primitive.ObjectID, text string) (entities.Thread, error) {
+	return entities.Thread{ID: threadID, Messages: []entities.Message{{Sender: session.Username(), Text: text, SentAt: time.Now()}}}, nil
+}
+
+func (ms *MockMessageService) ReadThread(ctx context.Context, session *entities.Session, threadID primitive.ObjectID) (entities.Thread, error) {
+	return entities.Thread{ID: threadID, Messages: []entities.Message{}}, nil
+}
+
+func (ms *MockMessageService) Threads(ctx context.Context, session *entities.Session) ([]entities.Thread, error) {
+	return []entities.Thread{}, nil
+}
+
+func (ms *MockMessageService) UnreadMessages(ctx context.Context, session *entities.Session) (entities.UnreadMessages, error) {
+	return entities.UnreadMessages{}, nil
+}
@@ -0,0 +1,38 @@
+package mock_service
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+)
+
+type MockNotificationService struct {
+}
+
+func NewMockNotificationService() *MockNotificationService {
+	return &MockNotificationService{}
+}
+
+func (ns *MockNotificationService) Notifications(ctx context.Context, session *entities.Session) ([]entities.Notification, error) {
+	return []entities.Notification{}, nil
+}
+
+func (ns *MockNotificationService) UnreadNotifications(ctx context.Context, session *entities.Session) (int, error) {
+	return 0, nil
+}
+
+func (ns *MockNotificationService) MarkNotificationRead(ctx context.Context, session *entities.Session, id primitive.ObjectID) error {
+	return nil
+}
+
+func (ns *MockNotificationService) MarkAllNotificationsRead(ctx context.Context, session *entities.Session) (int, error) {
+	return 0, nil
+}
+
+func (ns *MockNotificationService) DeleteNotification(ctx context.Context, session *entities.Session, id primitive.ObjectID) error {
+	return nil
+}
+
+func (ns *MockNotificationService) ClearReadNotifications(ctx context.Context, session *entities.Session) (int, error) {
+	return 0, nil
+}
@@ -1,6 +1,7 @@
 package mock_service
 
 import (
+	"context"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"rentease/internal/domain/entities"
 )
@@ -13,48 +14,53 @@ func NewMockPropertyService() *MockPropertyService {
 }
 
 // ListProperty mock implementation .
-func (ms *MockPropertyService) ListProperty(property entities.Property) error {
+func (ms *MockPropertyService) ListProperty(ctx context.Context, session *entities.Session, property entities.Property) error {
 	return nil
 }
 
 // GetAllListedProperties mock implementation .
-func (ms *MockPropertyService) GetAllListedProperties(activeUseronly bool) ([]entities.Property, error) {
+func (ms *MockPropertyService) GetAllListedProperties(ctx context.Context, landlordUsername string) ([]entities.Property, error) {
 	return []entities.Property{}, nil
 }
 
 // UpdateListedProperty mock implementation .
-func (ms *MockPropertyService) UpdateListedProperty(property entities.Property) error {
+func (ms *MockPropertyService) UpdateListedProperty(ctx context.Context, session *entities.Session, property entities.Property) error {
 
 	return nil
 }
 
 // DeleteListedProperty mock implementation
-func (ms *MockPropertyService) DeleteListedProperty(propertyID string) error {
+func (ms *MockPropertyService) DeleteListedProperty(ctx context.Context, session *entities.Session, propertyID primitive.ObjectID) error {
 	return nil
 }
 
 // SearchProperties function's Mock implementation
-func (ms *MockPropertyService) SearchProperties(area, city, state string, pincode, propertyType int) ([]entities.Property, error) {
+func (ms *MockPropertyService) SearchProperties(ctx context.Context, area, city, state string, pincode, propertyType int) ([]entities.Property, error) {
 
 	return []entities.Property{}, nil
 }
 
 // FindByID function's Mock implementation
-func (ms *MockPropertyService) FindByID(id primitive.ObjectID) (entities.Property, error) {
+func (ms *MockPropertyService) FindByID(ctx context.Context, id primitive.ObjectID) (entities.Property, error) {
 	return entities.Property{}, nil
 }
 
 // DeleteAllListedPropertiesOfaUser function's Mock implementation
-func (ms *MockPropertyService) DeleteAllListedPropertiesOfaUser(username string) error {
+func (ms *MockPropertyService) DeleteAllListedPropertiesOfaUser(ctx context.Context, session *entities.Session, username string) error {
 	return nil
 }
 
 // GetPendingProperties function's Mock implementation
-func (ms *MockPropertyService) GetPendingProperties() ([]entities.Property, error) {
+func (ms *MockPropertyService) GetPendingProperties(ctx context.Context, session *entities.Session) ([]entities.Property, error) {
 	return []entities.Property{}, nil
 }
 
 // ApproveProperty function's  Mock implementation
-func (ms *MockPropertyService) ApproveProperty(propertyID primitive.ObjectID, adminUsername string) error {
+func (ms *MockPropertyService) ApproveProperty(ctx context.Context, session *entities.Session, propertyID primitive.ObjectID) error {
+	return nil
+}
+
+// RejectProperty function's  Mock implementation
+func (ms *MockPropertyService) RejectProperty(ctx context.Context, session *entities.Session, propertyID primitive.ObjectID, reason string) error {
 	return nil
 }
@@ -1,8 +1,10 @@
 package mock_service
 
 import (
+	"context"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"rentease/internal/domain/entities"
+	"time"
 )
 
 type MockRentRequestService struct {
@@ -12,23 +14,31 @@ func NewMockRentRequestService() *MockRentRequestService {
 	return &MockRentRequestService{}
 }
 
-func (ms *MockRentRequestService) CreateRentRequest(tenantName string, propertyID primitive.ObjectID, landlordName string) error {
+func (ms *MockRentRequestService) CreateRentRequest(ctx context.Context, session *entities.Session, propertyID primitive.ObjectID) error {
 
 	return nil
 
 }
 
-func (ms *MockRentRequestService) GetRentRequestsInfoForLandlord(landlordName string) ([]entities.Request, error) {
+func (ms *MockRentRequestService) GetRentRequestsInfoForLandlord(ctx context.Context, session *entities.Session) ([]entities.Request, error) {
 
 	return []entities.Request{}, nil
 }
 
-func (ms *MockRentRequestService) UpdateRequestStatus(request entities.Request, status string) error {
+func (ms *MockRentRequestService) UpdateRequestStatus(ctx context.Context, session *entities.Session, requestID primitive.ObjectID, status entities.RequestStatus) error {
 
 	return nil
 
 }
 
-func (ms *MockUserService) GetRentRequestsInfoForTenant(tenantName string) ([]entities.Request, error) {
+func (ms *MockRentRequestService) WithdrawRequest(ctx context.Context, session *entities.Session, requestID primitive.ObjectID) error {
+	return nil
+}
+
+func (ms *MockRentRequestService) ExpireRequests(ctx context.Context, session *entities.Session, createdBefore time.Time) (int, error) {
+	return 0, nil
+}
+
+func (ms *MockUserService) GetRentRequestsInfoForTenant(ctx context.Context, session *entities.Session) ([]entities.Request, error) {
 	return []entities.Request{}, nil
 }
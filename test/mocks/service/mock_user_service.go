@@ -1,6 +1,7 @@
 package mock_service
 
 import (
+	"context"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"rentease/internal/domain/entities"
 )
@@ -12,35 +13,39 @@ func NewMockUserService() *MockUserService {
 	return &MockUserService{}
 }
 
-func (ms *MockUserService) SignUp(user entities.User) error {
+func (ms *MockUserService) SignUp(ctx context.Context, user entities.User) error {
 
 	return nil
 
 }
 
-func (ms *MockUserService) FindByUsername(username string) (entities.User, error) {
+func (ms *MockUserService) FindByUsername(ctx context.Context, username string) (entities.User, error) {
 
 	return entities.User{}, nil
 }
 
-func (ms *MockUserService) Login(username, password string) (bool, error) {
+func (ms *MockUserService) Login(ctx context.Context, username, password string) (*entities.Session, error) {
 
-	return true, nil
+	return &entities.Session{User: entities.User{Username: username}}, nil
 
 }
 
-func (ms *MockUserService) AddToWishlist(username string, propertyID primitive.ObjectID) error {
+func (ms *MockUserService) AddToWishlist(ctx context.Context, session *entities.Session, propertyID primitive.ObjectID) error {
 	return nil
 }
 
-func (ms *MockUserService) UpdateUser(user entities.User) error {
+func (ms *MockUserService) UpdateUser(ctx context.Context, session *entities.Session, user entities.User) error {
 	return nil
 }
 
-func (ms *MockUserService) GetAllUsers() ([]entities.User, error) {
+func (ms *MockUserService) GetAllUsers(ctx context.Context, session *entities.Session) ([]entities.User, error) {
 	return []entities.User{}, nil
 }
 
-func (ms *MockUserService) DeleteUser(username string) error {
+func (ms *MockUserService) DeleteUser(ctx context.Context, session *entities.Session, username string) error {
+	return nil
+}
+
+func (ms *MockUserService) SetRole(ctx context.Context, session *entities.Session, username, role string) error {
 	return nil
 }
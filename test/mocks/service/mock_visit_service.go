@@ -0,0 +1,47 @@
+package mock_service
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+	"time"
+)
+
+type MockVisitService struct {
+}
+
+func NewMockVisitService() *MockVisitService {
+	return &MockVisitService{}
+}
+
+func (vs *MockVisitService) PublishSlots(ctx context.Context, session *entities.Session, propertyID primitive.ObjectID, starts []time.Time, length time.Duration) ([]entities.VisitSlot, error) {
+	slots := make([]entities.VisitSlot, 0, len(starts))
+	for _, start := range starts {
+		slots = append(slots, entities.VisitSlot{PropertyID: propertyID, Start: start, End: start.Add(length), Status: entities.VisitOpen})
+	}
+	return slots, nil
+}
+
+func (vs *MockVisitService) VisitSlots(ctx context.Context, session *entities.Session, propertyID primitive.ObjectID) ([]entities.VisitSlot, error) {
+	return []entities.VisitSlot{}, nil
+}
+
+func (vs *MockVisitService) BookVisit(ctx context.Context, session *entities.Session, slotID primitive.ObjectID) (entities.VisitSlot, error) {
+	return entities.VisitSlot{ID: slotID, Status: entities.VisitBooked, TenantName: session.Username()}, nil
+}
+
+func (vs *MockVisitService) RescheduleVisit(ctx context.Context, session *entities.Session, slotID, toSlotID primitive.ObjectID) (entities.VisitSlot, error) {
+	return entities.VisitSlot{ID: toSlotID, Status: entities.VisitBooked}, nil
+}
+
+func (vs *MockVisitService) CancelVisit(ctx context.Context, session *entities.Session, slotID primitive.ObjectID) (entities.VisitSlot, error) {
+	return entities.VisitSlot{ID: slotID, Status: entities.VisitCancelled, CancelledBy: session.Username()}, nil
+}
+
+func (vs *MockVisitService) UpcomingVisitsForTenant(ctx context.Context, session *entities.Session) ([]entities.VisitSlot, error) {
+	return []entities.VisitSlot{}, nil
+}
+
+func (vs *MockVisitService) UpcomingVisitsForLandlord(ctx context.Context, session *entities.Session) ([]entities.VisitSlot, error) {
+	return []entities.VisitSlot{}, nil
+}
@@ -0,0 +1,189 @@
+package repository_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"rentease/config"
+	"rentease/internal/app/repositories"
+	"rentease/internal/domain/interfaces"
+)
+
+// mongoURIEnv names the environment variable holding the MongoDB URI used by the contract tests.
+// When it is not set the MongoDB backend is skipped.
+const mongoURIEnv = "RENTEASE_TEST_MONGO_URI"
+
+// backend creates fresh, empty repositories of one storage implementation.
+type backend struct {
+	name                string
+	newUserRepo         func(t *testing.T) interfaces.UserRepo
+	newPropertyRepo     func(t *testing.T) interfaces.PropertyRepo
+	newRequestRepo      func(t *testing.T) interfaces.RequestRepo
+	newRefreshTokenRepo func(t *testing.T) interfaces.RefreshTokenRepo
+	newLeaseRepo        func(t *testing.T) interfaces.LeaseRepo
+	newLedgerRepo       func(t *testing.T) interfaces.LedgerRepo
+	newDepositRepo      func(t *testing.T) interfaces.DepositRepo
+	newMaintenanceRepo  func(t *testing.T) interfaces.MaintenanceRepo
+	newVisitRepo        func(t *testing.T) interfaces.VisitRepo
+	newMessageRepo      func(t *testing.T) interfaces.MessageRepo
+	newNotificationRepo func(t *testing.T) interfaces.NotificationRepo
+}
+
+// backends lists every storage implementation the repository contract runs against.
+func backends() []backend {
+	return []backend{
+		{
+			name:            "memory",
+			newUserRepo:     func(t *testing.T) interfaces.UserRepo { return repositories.NewInMemoryUserRepo() },
+			newPropertyRepo: func(t *testing.T) interfaces.PropertyRepo { return repositories.NewInMemoryPropertyRepo() },
+			newRequestRepo:  func(t *testing.T) interfaces.RequestRepo { return repositories.NewInMemoryRequestRepo() },
+			newRefreshTokenRepo: func(t *testing.T) interfaces.RefreshTokenRepo {
+				return repositories.NewInMemoryRefreshTokenRepo()
+			},
+			newLeaseRepo:   func(t *testing.T) interfaces.LeaseRepo { return repositories.NewInMemoryLeaseRepo() },
+			newLedgerRepo:  func(t *testing.T) interfaces.LedgerRepo { return repositories.NewInMemoryLedgerRepo() },
+			newDepositRepo: func(t *testing.T) interfaces.DepositRepo { return repositories.NewInMemoryDepositRepo() },
+			newMaintenanceRepo: func(t *testing.T) interfaces.MaintenanceRepo {
+				return repositories.NewInMemoryMaintenanceRepo()
+			},
+			newVisitRepo: func(t *testing.T) interfaces.VisitRepo { return repositories.NewInMemoryVisitRepo() },
+			newMessageRepo: func(t *testing.T) interfaces.MessageRepo {
+				return repositories.NewInMemoryMessageRepo()
+			},
+			newNotificationRepo: func(t *testing.T) interfaces.NotificationRepo {
+				return repositories.NewInMemoryNotificationRepo()
+			},
+		},
+		{
+			name:            "bolt",
+			newUserRepo:     func(t *testing.T) interfaces.UserRepo { return repositories.NewBoltUserRepo(boltTestDB(t)) },
+			newPropertyRepo: func(t *testing.T) interfaces.PropertyRepo { return repositories.NewBoltPropertyRepo(boltTestDB(t)) },
+			newRequestRepo:  func(t *testing.T) interfaces.RequestRepo { return repositories.NewBoltRequestRepo(boltTestDB(t)) },
+			newRefreshTokenRepo: func(t *testing.T) interfaces.RefreshTokenRepo {
+				return repositories.NewBoltRefreshTokenRepo(boltTestDB(t))
+			},
+			newLeaseRepo:   func(t *testing.T) interfaces.LeaseRepo { return repositories.NewBoltLeaseRepo(boltTestDB(t)) },
+			newLedgerRepo:  func(t *testing.T) interfaces.LedgerRepo { return repositories.NewBoltLedgerRepo(boltTestDB(t)) },
+			newDepositRepo: func(t *testing.T) interfaces.DepositRepo { return repositories.NewBoltDepositRepo(boltTestDB(t)) },
+			newMaintenanceRepo: func(t *testing.T) interfaces.MaintenanceRepo {
+				return repositories.NewBoltMaintenanceRepo(boltTestDB(t))
+			},
+			newVisitRepo: func(t *testing.T) interfaces.VisitRepo { return repositories.NewBoltVisitRepo(boltTestDB(t)) },
+			newMessageRepo: func(t *testing.T) interfaces.MessageRepo {
+				return repositories.NewBoltMessageRepo(boltTestDB(t))
+			},
+			newNotificationRepo: func(t *testing.T) interfaces.NotificationRepo {
+				return repositories.NewBoltNotificationRepo(boltTestDB(t))
+			},
+		},
+		{
+			name: "mongo",
+			newUserRepo: func(t *testing.T) interfaces.UserRepo {
+				client, dbName := mongoTestDatabase(t)
+				return repositories.NewUserRepo(client, dbName, "users")
+			},
+			newPropertyRepo: func(t *testing.T) interfaces.PropertyRepo {
+				client, dbName := mongoTestDatabase(t)
+				return repositories.NewPropertyRepo(client, dbName, "properties")
+			},
+			newRequestRepo: func(t *testing.T) interfaces.RequestRepo {
+				client, dbName := mongoTestDatabase(t)
+				if err := repositories.EnsureRequestIndexes(context.Background(), client, dbName, "rentRequest"); err != nil {
+					t.Fatalf("failed to create request indexes: %v", err)
+				}
+				return repositories.NewRequestRepo(client, dbName, "rentRequest")
+			},
+			newRefreshTokenRepo: func(t *testing.T) interfaces.RefreshTokenRepo {
+				client, dbName := mongoTestDatabase(t)
+				return repositories.NewRefreshTokenRepo(client, dbName, "refreshTokens")
+			},
+			newLeaseRepo: func(t *testing.T) interfaces.LeaseRepo {
+				client, dbName := mongoTestDatabase(t)
+				return repositories.NewLeaseRepo(client, dbName, "leases")
+			},
+			newLedgerRepo: func(t *testing.T) interfaces.LedgerRepo {
+				client, dbName := mongoTestDatabase(t)
+				if err := repositories.EnsureLedgerIndexes(context.Background(), client, dbName, "rentDues"); err != nil {
+					t.Fatalf("failed to create ledger indexes: %v", err)
+				}
+				return repositories.NewLedgerRepo(client, dbName, "rentDues", "payments")
+			},
+			newDepositRepo: func(t *testing.T) interfaces.DepositRepo {
+				client, dbName := mongoTestDatabase(t)
+				if err := repositories.EnsureDepositIndexes(context.Background(), client, dbName, "deposits"); err != nil {
+					t.Fatalf("failed to create deposit indexes: %v", err)
+				}
+				return repositories.NewDepositRepo(client, dbName, "deposits")
+			},
+			newMaintenanceRepo: func(t *testing.T) interfaces.MaintenanceRepo {
+				client, dbName := mongoTestDatabase(t)
+				return repositories.NewMaintenanceRepo(client, dbName, "maintenance")
+			},
+			newVisitRepo: func(t *testing.T) interfaces.VisitRepo {
+				client, dbName := mongoTestDatabase(t)
+				return repositories.NewVisitRepo(client, dbName, "visits")
+			},
+			newMessageRepo: func(t *testing.T) interfaces.MessageRepo {
+				client, dbName := mongoTestDatabase(t)
+				if err := repositories.EnsureMessageIndexes(context.Background(), client, dbName, "messages"); err != nil {
+					t.Fatalf("failed to create message indexes: %v", err)
+				}
+				return repositories.NewMessageRepo(client, dbName, "messages")
+			},
+			newNotificationRepo: func(t *testing.T) interfaces.NotificationRepo {
+				client, dbName := mongoTestDatabase(t)
+				return repositories.NewNotificationRepo(client, dbName, "notifications")
+			},
+		},
+	}
+}
+
+// forEachBackend runs the test body once per storage backend.
+func forEachBackend(t *testing.T, run func(t *testing.T, b backend)) {
+	for _, b := range backends() {
+		b := b
+		t.Run(b.name, func(t *testing.T) {
+			run(t, b)
+		})
+	}
+}
+
+// boltTestDB opens a database file in a temporary directory that is removed when the test ends.
+func boltTestDB(t *testing.T) *bbolt.DB {
+	db, err := repositories.OpenBoltDB(filepath.Join(t.TempDir(), "rentease.db"))
+	if err != nil {
+		t.Fatalf("failed to open bolt database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// mongoTestDatabase connects to the test MongoDB server and returns the client and a
+// throwaway database name. The database is dropped and the client disconnected when the test ends.
+func mongoTestDatabase(t *testing.T) (*mongo.Client, string) {
+	uri := os.Getenv(mongoURIEnv)
+	if uri == "" {
+		t.Skipf("%s not set, skipping MongoDB backend", mongoURIEnv)
+	}
+
+	cfg := config.Default().Mongo
+	cfg.URI = uri
+	client, err := repositories.NewMongoClient(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("failed to connect to MongoDB: %v", err)
+	}
+
+	dbName := "rentease_test_" + primitive.NewObjectID().Hex()
+	t.Cleanup(func() {
+		if err := client.Database(dbName).Drop(context.Background()); err != nil {
+			t.Logf("failed to drop test database %s: %v", dbName, err)
+		}
+		_ = repositories.DisconnectMongoClient(client, cfg.OperationTimeout)
+	})
+	return client, dbName
+}
@@ -0,0 +1,60 @@
+package repository_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"rentease/internal/app/repositories"
+)
+
+func TestOpenBoltDB_PersistsAcrossRestarts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rentease.db")
+
+	// First start creates the schema
+	db, err := repositories.OpenBoltDB(path)
+	require.NoError(t, err)
+	property := newTestProperties("landlord1")[1]
+	require.NoError(t, repositories.NewBoltUserRepo(db).SaveUser(context.Background(), newTestUser(t, "alice", "Secret@123")))
+	require.NoError(t, repositories.NewBoltPropertyRepo(db).SaveProperty(context.Background(), property))
+	require.NoError(t, db.Close())
+
+	// Second start reuses the existing file
+	db, err = repositories.OpenBoltDB(path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	user, err := repositories.NewBoltUserRepo(db).FindByUsername(context.Background(), "alice")
+	require.NoError(t, err)
+	assert.NotNil(t, user)
+
+	found, err := repositories.NewBoltPropertyRepo(db).FindByID(context.Background(), property.ID)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, property, *found)
+}
+
+func TestBoltUserRepo_RejectsDuplicateUsername(t *testing.T) {
+	repo := repositories.NewBoltUserRepo(boltTestDB(t))
+	require.NoError(t, repo.SaveUser(context.Background(), newTestUser(t, "alice", "Secret@123")))
+	assert.Error(t, repo.SaveUser(context.Background(), newTestUser(t, "alice", "Other@123")))
+}
+
+func TestBoltRepos_HonourCancelledContext(t *testing.T) {
+	db := boltTestDB(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := repositories.NewBoltUserRepo(db).SaveUser(ctx, newTestUser(t, "alice", "Secret@123"))
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = repositories.NewBoltPropertyRepo(db).GetAllListedProperties(ctx, "")
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// Nothing was written by the cancelled call
+	user, err := repositories.NewBoltUserRepo(db).FindByUsername(context.Background(), "alice")
+	require.NoError(t, err)
+	assert.Nil(t, user)
+}
@@ -0,0 +1,108 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+)
+
+func newTestDeposit(leaseID primitive.ObjectID) entities.Deposit {
+	return entities.Deposit{
+		LeaseID:    leaseID,
+		Agreed:     30000,
+		Received:   30000,
+		ReceivedOn: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+		Mode:       entities.PaymentBankTransfer,
+		RecordedBy: "landlord",
+		RecordedAt: time.Now().UTC().Truncate(time.Millisecond),
+		Status:     entities.DepositHeld,
+	}
+}
+
+func TestDepositRepoContract_CreateAndFind(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newDepositRepo(t)
+		leaseID := primitive.NewObjectID()
+
+		created, err := repo.CreateDeposit(context.Background(), newTestDeposit(leaseID))
+		require.NoError(t, err)
+		assert.True(t, created)
+
+		// A lease has one deposit
+		created, err = repo.CreateDeposit(context.Background(), newTestDeposit(leaseID))
+		require.NoError(t, err)
+		assert.False(t, created)
+
+		found, err := repo.FindDepositByLease(context.Background(), leaseID)
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.False(t, found.ID.IsZero())
+		assert.Equal(t, leaseID, found.LeaseID)
+		assert.Equal(t, 30000.0, found.Received)
+		assert.Equal(t, entities.PaymentBankTransfer, found.Mode)
+		assert.Equal(t, entities.DepositHeld, found.Status)
+
+		missing, err := repo.FindDepositByLease(context.Background(), primitive.NewObjectID())
+		assert.NoError(t, err)
+		assert.Nil(t, missing)
+	})
+}
+
+func TestDepositRepoContract_UpdateDeposit(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newDepositRepo(t)
+		leaseID := primitive.NewObjectID()
+		_, err := repo.CreateDeposit(context.Background(), newTestDeposit(leaseID))
+		require.NoError(t, err)
+		stored, err := repo.FindDepositByLease(context.Background(), leaseID)
+		require.NoError(t, err)
+
+		proposed := *stored
+		proposedAt := time.Now().UTC().Truncate(time.Millisecond)
+		proposed.Deductions = []entities.DepositDeduction{
+			{Reason: "Broken window", Amount: 2500, Note: "Glass replaced"},
+			{Reason: "Cleaning", Amount: 1000},
+		}
+		proposed.Refund = 26500
+		proposed.ProposedAt = &proposedAt
+		proposed.Status = entities.DepositProposed
+
+		// Only a deposit still in the given status is replaced
+		updated, err := repo.UpdateDeposit(context.Background(), proposed, entities.DepositDisputed)
+		require.NoError(t, err)
+		assert.False(t, updated)
+		updated, err = repo.UpdateDeposit(context.Background(), proposed, entities.DepositHeld)
+		require.NoError(t, err)
+		assert.True(t, updated)
+		updated, err = repo.UpdateDeposit(context.Background(), proposed, entities.DepositHeld)
+		require.NoError(t, err)
+		assert.False(t, updated)
+
+		found, err := repo.FindDepositByLease(context.Background(), leaseID)
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, entities.DepositProposed, found.Status)
+		assert.Equal(t, proposed.Deductions, found.Deductions)
+		assert.Equal(t, 3500.0, found.Deducted())
+		assert.Equal(t, 26500.0, found.Refund)
+		require.NotNil(t, found.ProposedAt)
+		assert.True(t, proposedAt.Equal(*found.ProposedAt))
+
+		// Changing the deposit that was read does not change the stored one
+		found.Deductions[0].Amount = 0
+		again, err := repo.FindDepositByLease(context.Background(), leaseID)
+		require.NoError(t, err)
+		assert.Equal(t, 2500.0, again.Deductions[0].Amount)
+
+		unknown := newTestDeposit(primitive.NewObjectID())
+		unknown.ID = primitive.NewObjectID()
+		updated, err = repo.UpdateDeposit(context.Background(), unknown, entities.DepositHeld)
+		require.NoError(t, err)
+		assert.False(t, updated)
+	})
+}
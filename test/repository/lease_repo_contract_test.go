@@ -0,0 +1,179 @@
+package repository_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+)
+
+func newTestLease(tenant, landlord string) entities.Lease {
+	start := time.Now().UTC().Truncate(24 * time.Hour)
+	return entities.Lease{
+		RequestID:        primitive.NewObjectID(),
+		PropertyID:       primitive.NewObjectID(),
+		TenantName:       tenant,
+		LandlordName:     landlord,
+		StartDate:        start,
+		EndDate:          start.AddDate(0, 11, 0),
+		MonthlyRent:      1200,
+		Deposit:          2400,
+		NoticePeriodDays: 30,
+		Status:           entities.LeasePending,
+		// MongoDB stores times with millisecond precision
+		CreatedAt: time.Now().UTC().Truncate(time.Millisecond),
+	}
+}
+
+func TestLeaseRepoContract_SaveAndFind(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newLeaseRepo(t)
+		lease := newTestLease("tenant1", "landlord1")
+		lease.ID = primitive.NewObjectID()
+		require.NoError(t, repo.SaveLease(context.Background(), lease))
+		require.NoError(t, repo.SaveLease(context.Background(), newTestLease("tenant1", "landlord2")))
+		require.NoError(t, repo.SaveLease(context.Background(), newTestLease("tenant2", "landlord1")))
+
+		found, err := repo.FindLeaseByID(context.Background(), lease.ID)
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, lease.RequestID, found.RequestID)
+		assert.Equal(t, lease.MonthlyRent, found.MonthlyRent)
+		assert.True(t, lease.StartDate.Equal(found.StartDate))
+		assert.True(t, lease.EndDate.Equal(found.EndDate))
+
+		byRequest, err := repo.FindLeaseByRequestID(context.Background(), lease.RequestID)
+		require.NoError(t, err)
+		require.NotNil(t, byRequest)
+		assert.Equal(t, lease.ID, byRequest.ID)
+
+		byTenant, err := repo.FindLeasesByTenant(context.Background(), "tenant1")
+		require.NoError(t, err)
+		assert.Len(t, byTenant, 2)
+		for _, lease := range byTenant {
+			// Saved leases are assigned an ID by the repository
+			assert.False(t, lease.ID.IsZero())
+		}
+
+		byLandlord, err := repo.FindLeasesByLandlord(context.Background(), "landlord1")
+		require.NoError(t, err)
+		assert.Len(t, byLandlord, 2)
+
+		missing, err := repo.FindLeaseByID(context.Background(), primitive.NewObjectID())
+		assert.NoError(t, err)
+		assert.Nil(t, missing)
+		missing, err = repo.FindLeaseByRequestID(context.Background(), primitive.NewObjectID())
+		assert.NoError(t, err)
+		assert.Nil(t, missing)
+	})
+}
+
+func TestLeaseRepoContract_UpdateLeaseStatus(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newLeaseRepo(t)
+		lease := newTestLease("tenant1", "landlord1")
+		lease.ID = primitive.NewObjectID()
+		require.NoError(t, repo.SaveLease(context.Background(), lease))
+
+		// Of several concurrent changes from the same status only one goes through
+		const attempts = 8
+		results := make(chan bool, attempts)
+		var wg sync.WaitGroup
+		for i := 0; i < attempts; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				updated, err := repo.UpdateLeaseStatus(context.Background(), lease.ID, entities.LeasePending, entities.LeaseActive)
+				assert.NoError(t, err)
+				results <- updated
+			}()
+		}
+		wg.Wait()
+		close(results)
+		succeeded := 0
+		for updated := range results {
+			if updated {
+				succeeded++
+			}
+		}
+		assert.Equal(t, 1, succeeded)
+
+		found, err := repo.FindLeaseByID(context.Background(), lease.ID)
+		require.NoError(t, err)
+		assert.Equal(t, entities.LeaseActive, found.Status)
+
+		updated, err := repo.UpdateLeaseStatus(context.Background(), primitive.NewObjectID(), entities.LeasePending, entities.LeaseActive)
+		assert.NoError(t, err)
+		assert.False(t, updated)
+	})
+}
+
+func TestLeaseRepoContract_UpdateLease(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newLeaseRepo(t)
+		lease := newTestLease("tenant1", "landlord1")
+		lease.ID = primitive.NewObjectID()
+		lease.Status = entities.LeaseActive
+		require.NoError(t, repo.SaveLease(context.Background(), lease))
+		require.NoError(t, repo.SaveLease(context.Background(), newTestLease("tenant2", "landlord1")))
+
+		answeredAt := time.Now().UTC().Truncate(time.Millisecond)
+		changed := lease
+		changed.Status = entities.LeaseEnding
+		changed.EndDate = lease.StartDate.AddDate(0, 1, 0)
+		changed.Notice = &entities.Notice{GivenBy: "tenant1", GivenAt: answeredAt}
+		changed.Renewal = &entities.RenewalOffer{MonthlyRent: 1300, EndDate: lease.EndDate.AddDate(1, 0, 0), OfferedAt: answeredAt, Status: entities.RenewalLapsed, AnsweredAt: &answeredAt}
+		changed.Rules = &entities.RentRules{
+			Escalation: &entities.Escalation{Percent: 5, EveryMonths: 12, Since: lease.StartDate},
+			LateFee:    &entities.LateFee{GraceDays: 4, PerDay: 100, Max: 1000},
+		}
+		changed.PastRents = []entities.PastRent{{MonthlyRent: 1100, From: lease.StartDate, Until: lease.StartDate.AddDate(0, 6, 0)}}
+
+		// Only applied while the stored lease is still in the expected status
+		updated, err := repo.UpdateLease(context.Background(), changed, entities.LeasePending)
+		require.NoError(t, err)
+		assert.False(t, updated)
+		updated, err = repo.UpdateLease(context.Background(), changed, entities.LeaseActive)
+		require.NoError(t, err)
+		assert.True(t, updated)
+
+		found, err := repo.FindLeaseByID(context.Background(), lease.ID)
+		require.NoError(t, err)
+		assert.Equal(t, entities.LeaseEnding, found.Status)
+		assert.True(t, changed.EndDate.Equal(found.EndDate))
+		if assert.NotNil(t, found.Notice) {
+			assert.Equal(t, "tenant1", found.Notice.GivenBy)
+		}
+		if assert.NotNil(t, found.Renewal) && assert.NotNil(t, found.Renewal.AnsweredAt) {
+			assert.Equal(t, entities.RenewalLapsed, found.Renewal.Status)
+			assert.True(t, answeredAt.Equal(*found.Renewal.AnsweredAt))
+		}
+		if assert.NotNil(t, found.Rules) && assert.NotNil(t, found.Rules.Escalation) {
+			assert.True(t, lease.StartDate.Equal(found.Rules.Escalation.Since))
+			assert.Equal(t, *changed.Rules.LateFee, *found.Rules.LateFee)
+		}
+		if assert.Len(t, found.PastRents, 1) {
+			assert.Equal(t, 1100.0, found.PastRents[0].MonthlyRent)
+			assert.True(t, changed.PastRents[0].Until.Equal(found.PastRents[0].Until))
+		}
+
+		byStatus, err := repo.FindLeasesByStatus(context.Background(), entities.LeaseEnding)
+		require.NoError(t, err)
+		require.Len(t, byStatus, 1)
+		assert.Equal(t, lease.ID, byStatus[0].ID)
+		pending, err := repo.FindLeasesByStatus(context.Background(), entities.LeasePending)
+		require.NoError(t, err)
+		assert.Len(t, pending, 1)
+
+		missing := newTestLease("tenant3", "landlord1")
+		missing.ID = primitive.NewObjectID()
+		updated, err = repo.UpdateLease(context.Background(), missing, entities.LeasePending)
+		assert.NoError(t, err)
+		assert.False(t, updated)
+	})
+}
@@ -0,0 +1,127 @@
+package repository_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+)
+
+func newTestDue(leaseID primitive.ObjectID, period int) entities.RentDue {
+	start := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	return entities.RentDue{
+		LeaseID:   leaseID,
+		Period:    period,
+		DueDate:   start.AddDate(0, period-1, 0),
+		Amount:    1200,
+		CreatedAt: time.Now().UTC().Truncate(time.Millisecond),
+	}
+}
+
+func TestLedgerRepoContract_CreateDue(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newLedgerRepo(t)
+		leaseID := primitive.NewObjectID()
+		for _, period := range []int{3, 1, 2} {
+			created, err := repo.CreateDue(context.Background(), newTestDue(leaseID, period))
+			require.NoError(t, err)
+			assert.True(t, created)
+		}
+		created, err := repo.CreateDue(context.Background(), newTestDue(primitive.NewObjectID(), 1))
+		require.NoError(t, err)
+		assert.True(t, created)
+
+		// A lease has one due per period
+		created, err = repo.CreateDue(context.Background(), newTestDue(leaseID, 2))
+		require.NoError(t, err)
+		assert.False(t, created)
+
+		dues, err := repo.FindDuesByLease(context.Background(), leaseID)
+		require.NoError(t, err)
+		require.Len(t, dues, 3)
+		for i, due := range dues {
+			assert.Equal(t, i+1, due.Period)
+			assert.False(t, due.ID.IsZero())
+			assert.Equal(t, 1200.0, due.Amount)
+			assert.True(t, newTestDue(leaseID, i+1).DueDate.Equal(due.DueDate))
+		}
+
+		none, err := repo.FindDuesByLease(context.Background(), primitive.NewObjectID())
+		assert.NoError(t, err)
+		assert.Empty(t, none)
+	})
+}
+
+func TestLedgerRepoContract_CreateDueConcurrently(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newLedgerRepo(t)
+		leaseID := primitive.NewObjectID()
+
+		// Of several concurrent attempts to create the same due only one succeeds
+		const attempts = 8
+		results := make(chan bool, attempts)
+		var wg sync.WaitGroup
+		for i := 0; i < attempts; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				created, err := repo.CreateDue(context.Background(), newTestDue(leaseID, 1))
+				assert.NoError(t, err)
+				results <- created
+			}()
+		}
+		wg.Wait()
+		close(results)
+		succeeded := 0
+		for created := range results {
+			if created {
+				succeeded++
+			}
+		}
+		assert.Equal(t, 1, succeeded)
+
+		dues, err := repo.FindDuesByLease(context.Background(), leaseID)
+		require.NoError(t, err)
+		assert.Len(t, dues, 1)
+	})
+}
+
+func TestLedgerRepoContract_Payments(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newLedgerRepo(t)
+		leaseID := primitive.NewObjectID()
+		day := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+		for _, offset := range []int{10, 0, 5} {
+			require.NoError(t, repo.SavePayment(context.Background(), entities.Payment{
+				LeaseID:    leaseID,
+				Amount:     float64(100 + offset),
+				PaidOn:     day.AddDate(0, 0, offset),
+				Mode:       entities.PaymentUPI,
+				Reference:  "TXN",
+				RecordedBy: "landlord1",
+				RecordedAt: time.Now().UTC().Truncate(time.Millisecond),
+			}))
+		}
+		require.NoError(t, repo.SavePayment(context.Background(), entities.Payment{LeaseID: primitive.NewObjectID(), Amount: 50, PaidOn: day, Mode: entities.PaymentCash}))
+
+		payments, err := repo.FindPaymentsByLease(context.Background(), leaseID)
+		require.NoError(t, err)
+		require.Len(t, payments, 3)
+		for i, offset := range []int{0, 5, 10} {
+			assert.False(t, payments[i].ID.IsZero())
+			assert.Equal(t, float64(100+offset), payments[i].Amount)
+			assert.True(t, day.AddDate(0, 0, offset).Equal(payments[i].PaidOn))
+			assert.Equal(t, entities.PaymentUPI, payments[i].Mode)
+			assert.Equal(t, "TXN", payments[i].Reference)
+		}
+
+		none, err := repo.FindPaymentsByLease(context.Background(), primitive.NewObjectID())
+		assert.NoError(t, err)
+		assert.Empty(t, none)
+	})
+}
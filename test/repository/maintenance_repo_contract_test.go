@@ -0,0 +1,176 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+)
+
+func newTestTicket(tenant, landlord string, createdAt time.Time) entities.MaintenanceTicket {
+	return entities.MaintenanceTicket{
+		ID:           primitive.NewObjectID(),
+		PropertyID:   primitive.NewObjectID(),
+		LeaseID:      primitive.NewObjectID(),
+		TenantName:   tenant,
+		LandlordName: landlord,
+		Category:     entities.CategoryPlumbing,
+		Description:  "The kitchen tap is leaking",
+		Priority:     entities.PriorityMedium,
+		Status:       entities.TicketOpen,
+		CreatedAt:    createdAt,
+		UpdatedAt:    createdAt,
+		DueBy:        createdAt.Add(entities.PriorityMedium.ResolveWithin()),
+	}
+}
+
+func TestMaintenanceRepoContract_SaveAndFind(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newMaintenanceRepo(t)
+		now := time.Now().UTC().Truncate(time.Millisecond)
+
+		later := newTestTicket("tenant1", "landlord1", now)
+		earlier := newTestTicket("tenant1", "landlord2", now.Add(-time.Hour))
+		other := newTestTicket("tenant2", "landlord1", now.Add(-2*time.Hour))
+		for _, ticket := range []entities.MaintenanceTicket{later, earlier, other} {
+			require.NoError(t, repo.SaveTicket(context.Background(), ticket))
+		}
+
+		found, err := repo.FindTicketByID(context.Background(), later.ID)
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, later.LeaseID, found.LeaseID)
+		assert.Equal(t, entities.CategoryPlumbing, found.Category)
+		assert.Equal(t, entities.TicketOpen, found.Status)
+		assert.True(t, later.DueBy.Equal(found.DueBy))
+		assert.Nil(t, found.ResolvedAt)
+
+		missing, err := repo.FindTicketByID(context.Background(), primitive.NewObjectID())
+		assert.NoError(t, err)
+		assert.Nil(t, missing)
+
+		// Both lists are ordered by when the tickets were reported
+		byTenant, err := repo.FindTicketsByTenant(context.Background(), "tenant1")
+		require.NoError(t, err)
+		require.Len(t, byTenant, 2)
+		assert.Equal(t, earlier.ID, byTenant[0].ID)
+		assert.Equal(t, later.ID, byTenant[1].ID)
+
+		byLandlord, err := repo.FindTicketsByLandlord(context.Background(), "landlord1")
+		require.NoError(t, err)
+		require.Len(t, byLandlord, 2)
+		assert.Equal(t, other.ID, byLandlord[0].ID)
+		assert.Equal(t, later.ID, byLandlord[1].ID)
+	})
+}
+
+func TestMaintenanceRepoContract_UpdateTicketStatus(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newMaintenanceRepo(t)
+		now := time.Now().UTC().Truncate(time.Millisecond)
+		ticket := newTestTicket("tenant1", "landlord1", now)
+		require.NoError(t, repo.SaveTicket(context.Background(), ticket))
+
+		resolvedAt := now.Add(time.Hour)
+		note := entities.TicketComment{Author: "landlord1", Text: "Replaced the washer", PostedAt: resolvedAt}
+		updated, err := repo.UpdateTicketStatus(context.Background(), ticket.ID, entities.TicketOpen, entities.TicketResolved, resolvedAt, &note)
+		require.NoError(t, err)
+		assert.True(t, updated)
+
+		// The ticket is no longer open
+		updated, err = repo.UpdateTicketStatus(context.Background(), ticket.ID, entities.TicketOpen, entities.TicketAcknowledged, resolvedAt, nil)
+		require.NoError(t, err)
+		assert.False(t, updated)
+
+		updated, err = repo.UpdateTicketStatus(context.Background(), primitive.NewObjectID(), entities.TicketOpen, entities.TicketAcknowledged, resolvedAt, nil)
+		require.NoError(t, err)
+		assert.False(t, updated)
+
+		found, err := repo.FindTicketByID(context.Background(), ticket.ID)
+		require.NoError(t, err)
+		assert.Equal(t, entities.TicketResolved, found.Status)
+		require.NotNil(t, found.ResolvedAt)
+		assert.True(t, resolvedAt.Equal(*found.ResolvedAt))
+		assert.True(t, resolvedAt.Equal(found.UpdatedAt))
+		require.Len(t, found.Comments, 1)
+		assert.Equal(t, "Replaced the washer", found.Comments[0].Text)
+
+		// Reopening clears when it was resolved and makes the ticket due again
+		reopenedAt := resolvedAt.Add(time.Hour)
+		updated, err = repo.UpdateTicketStatus(context.Background(), ticket.ID, entities.TicketResolved, entities.TicketOpen, reopenedAt, nil)
+		require.NoError(t, err)
+		assert.True(t, updated)
+		found, err = repo.FindTicketByID(context.Background(), ticket.ID)
+		require.NoError(t, err)
+		assert.Equal(t, entities.TicketOpen, found.Status)
+		assert.Nil(t, found.ResolvedAt)
+		assert.True(t, reopenedAt.Add(entities.PriorityMedium.ResolveWithin()).Equal(found.DueBy))
+		assert.Len(t, found.Comments, 1)
+	})
+}
+
+func TestMaintenanceRepoContract_CloseKeepsResolvedAt(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newMaintenanceRepo(t)
+		now := time.Now().UTC().Truncate(time.Millisecond)
+		ticket := newTestTicket("tenant1", "landlord1", now)
+		require.NoError(t, repo.SaveTicket(context.Background(), ticket))
+
+		resolvedAt := now.Add(time.Hour)
+		updated, err := repo.UpdateTicketStatus(context.Background(), ticket.ID, entities.TicketOpen, entities.TicketResolved, resolvedAt, nil)
+		require.NoError(t, err)
+		require.True(t, updated)
+		// The tenant confirms the fix
+		closedAt := resolvedAt.Add(time.Hour)
+		updated, err = repo.UpdateTicketStatus(context.Background(), ticket.ID, entities.TicketResolved, entities.TicketClosed, closedAt, nil)
+		require.NoError(t, err)
+		require.True(t, updated)
+
+		found, err := repo.FindTicketByID(context.Background(), ticket.ID)
+		require.NoError(t, err)
+		assert.Equal(t, entities.TicketClosed, found.Status)
+		require.NotNil(t, found.ResolvedAt)
+		assert.True(t, resolvedAt.Equal(*found.ResolvedAt))
+		assert.True(t, closedAt.Equal(found.UpdatedAt))
+		assert.True(t, ticket.DueBy.Equal(found.DueBy))
+	})
+}
+
+func TestMaintenanceRepoContract_AddTicketComment(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newMaintenanceRepo(t)
+		now := time.Now().UTC().Truncate(time.Millisecond)
+		ticket := newTestTicket("tenant1", "landlord1", now)
+		require.NoError(t, repo.SaveTicket(context.Background(), ticket))
+
+		for i, author := range []string{"tenant1", "landlord1"} {
+			comment := entities.TicketComment{Author: author, Text: "Any news?", PostedAt: now.Add(time.Duration(i+1) * time.Minute)}
+			added, err := repo.AddTicketComment(context.Background(), ticket.ID, comment)
+			require.NoError(t, err)
+			assert.True(t, added)
+		}
+
+		found, err := repo.FindTicketByID(context.Background(), ticket.ID)
+		require.NoError(t, err)
+		require.Len(t, found.Comments, 2)
+		assert.Equal(t, "tenant1", found.Comments[0].Author)
+		assert.Equal(t, "landlord1", found.Comments[1].Author)
+		assert.True(t, now.Add(2*time.Minute).Equal(found.UpdatedAt))
+
+		// Closed tickets take no more comments
+		updated, err := repo.UpdateTicketStatus(context.Background(), ticket.ID, entities.TicketOpen, entities.TicketClosed, now, nil)
+		require.NoError(t, err)
+		require.True(t, updated)
+		added, err := repo.AddTicketComment(context.Background(), ticket.ID, entities.TicketComment{Author: "tenant1", Text: "Thanks", PostedAt: now})
+		require.NoError(t, err)
+		assert.False(t, added)
+
+		added, err = repo.AddTicketComment(context.Background(), primitive.NewObjectID(), entities.TicketComment{Author: "tenant1", Text: "Hello", PostedAt: now})
+		require.NoError(t, err)
+		assert.False(t, added)
+	})
+}
@@ -0,0 +1,130 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+)
+
+func newTestThread(propertyID primitive.ObjectID, tenant string, at time.Time) entities.Thread {
+	return entities.Thread{
+		ID:            primitive.NewObjectID(),
+		PropertyID:    propertyID,
+		TenantName:    tenant,
+		LandlordName:  "landlord1",
+		Messages:      []entities.Message{},
+		CreatedAt:     at,
+		LastMessageAt: at,
+	}
+}
+
+func TestMessageRepoContract_CreateAndFindThreads(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newMessageRepo(t)
+		now := time.Now().UTC().Truncate(time.Millisecond)
+		propertyID := primitive.NewObjectID()
+
+		older := newTestThread(propertyID, "tenant1", now.Add(-time.Hour))
+		newer := newTestThread(primitive.NewObjectID(), "tenant1", now)
+		other := newTestThread(propertyID, "tenant2", now)
+		for _, thread := range []entities.Thread{older, newer, other} {
+			created, err := repo.CreateThread(context.Background(), thread)
+			require.NoError(t, err)
+			require.True(t, created)
+		}
+
+		// A tenant has one thread per property
+		created, err := repo.CreateThread(context.Background(), newTestThread(propertyID, "tenant1", now))
+		require.NoError(t, err)
+		assert.False(t, created)
+
+		found, err := repo.FindThreadByID(context.Background(), older.ID)
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, "tenant1", found.TenantName)
+		assert.Empty(t, found.Messages)
+		missing, err := repo.FindThreadByID(context.Background(), primitive.NewObjectID())
+		assert.NoError(t, err)
+		assert.Nil(t, missing)
+
+		found, err = repo.FindThread(context.Background(), propertyID, "tenant2")
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, other.ID, found.ID)
+		missing, err = repo.FindThread(context.Background(), newer.PropertyID, "tenant2")
+		assert.NoError(t, err)
+		assert.Nil(t, missing)
+
+		// The latest conversation comes first
+		threads, err := repo.FindThreadsByUser(context.Background(), "tenant1")
+		require.NoError(t, err)
+		require.Len(t, threads, 2)
+		assert.Equal(t, newer.ID, threads[0].ID)
+		assert.Equal(t, older.ID, threads[1].ID)
+		threads, err = repo.FindThreadsByUser(context.Background(), "landlord1")
+		require.NoError(t, err)
+		assert.Len(t, threads, 3)
+	})
+}
+
+func TestMessageRepoContract_AddMessageAndMarkRead(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newMessageRepo(t)
+		now := time.Now().UTC().Truncate(time.Millisecond)
+		first := newTestThread(primitive.NewObjectID(), "tenant1", now.Add(-time.Hour))
+		second := newTestThread(primitive.NewObjectID(), "tenant1", now.Add(-30*time.Minute))
+		for _, thread := range []entities.Thread{first, second} {
+			_, err := repo.CreateThread(context.Background(), thread)
+			require.NoError(t, err)
+		}
+
+		sent := []entities.Message{
+			{Sender: "tenant1", Text: "Is the flat still available?", SentAt: now},
+			{Sender: "tenant1", Text: "I could visit on Saturday.", SentAt: now.Add(time.Minute)},
+			{Sender: "landlord1", Text: "Yes, Saturday works.", SentAt: now.Add(2 * time.Minute)},
+		}
+		for _, message := range sent {
+			added, err := repo.AddMessage(context.Background(), first.ID, message)
+			require.NoError(t, err)
+			require.True(t, added)
+		}
+		// Only the parties write in a thread
+		added, err := repo.AddMessage(context.Background(), first.ID, entities.Message{Sender: "tenant2", Text: "Hello", SentAt: now})
+		require.NoError(t, err)
+		assert.False(t, added)
+		added, err = repo.AddMessage(context.Background(), primitive.NewObjectID(), sent[0])
+		require.NoError(t, err)
+		assert.False(t, added)
+
+		found, err := repo.FindThreadByID(context.Background(), first.ID)
+		require.NoError(t, err)
+		require.Len(t, found.Messages, 3)
+		assert.Equal(t, "Is the flat still available?", found.Messages[0].Text)
+		assert.Equal(t, "landlord1", found.Messages[2].Sender)
+		assert.Equal(t, 1, found.TenantUnread)
+		assert.Equal(t, 2, found.LandlordUnread)
+		assert.True(t, sent[2].SentAt.Equal(found.LastMessageAt))
+
+		// The thread with the new messages moves to the top
+		threads, err := repo.FindThreadsByUser(context.Background(), "tenant1")
+		require.NoError(t, err)
+		require.Len(t, threads, 2)
+		assert.Equal(t, first.ID, threads[0].ID)
+
+		read, err := repo.MarkThreadRead(context.Background(), first.ID, "landlord1")
+		require.NoError(t, err)
+		assert.True(t, read)
+		read, err = repo.MarkThreadRead(context.Background(), first.ID, "tenant2")
+		require.NoError(t, err)
+		assert.False(t, read)
+		found, err = repo.FindThreadByID(context.Background(), first.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, found.TenantUnread)
+		assert.Equal(t, 0, found.LandlordUnread)
+	})
+}
@@ -0,0 +1,25 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"rentease/config"
+	"rentease/internal/app/repositories"
+)
+
+func TestNewMongoClient_FailsFastWhenServerIsUnreachable(t *testing.T) {
+	cfg := config.Default().Mongo
+	cfg.URI = "mongodb://127.0.0.1:1" // Nothing listens on port 1
+	cfg.ConnectTimeout = 200 * time.Millisecond
+	cfg.ConnectRetries = 1
+
+	start := time.Now()
+	client, err := repositories.NewMongoClient(context.Background(), cfg)
+
+	assert.Nil(t, client)
+	assert.ErrorContains(t, err, "health check failed after 2 attempt(s)")
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
@@ -0,0 +1,123 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+)
+
+func newTestNotification(username string, at time.Time) entities.Notification {
+	return entities.Notification{
+		ID:        primitive.NewObjectID(),
+		Username:  username,
+		Kind:      entities.NotificationRequestCreated,
+		Text:      "tenant1 asked to rent Family House",
+		CreatedAt: at,
+	}
+}
+
+func TestNotificationRepoContract_SaveAndFind(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newNotificationRepo(t)
+		now := time.Now().UTC().Truncate(time.Millisecond)
+
+		older := newTestNotification("landlord1", now.Add(-time.Hour))
+		older.PropertyID = primitive.NewObjectID()
+		newer := newTestNotification("landlord1", now)
+		other := newTestNotification("landlord2", now)
+		for _, notification := range []entities.Notification{older, newer, other} {
+			require.NoError(t, repo.SaveNotification(context.Background(), notification))
+		}
+
+		// Newest first, only those of the user
+		found, err := repo.FindNotificationsByUser(context.Background(), "landlord1")
+		require.NoError(t, err)
+		require.Len(t, found, 2)
+		assert.Equal(t, newer.ID, found[0].ID)
+		assert.Equal(t, older.ID, found[1].ID)
+		assert.Equal(t, older.PropertyID, found[1].PropertyID)
+		assert.True(t, older.CreatedAt.Equal(found[1].CreatedAt))
+		assert.False(t, found[1].Read)
+
+		none, err := repo.FindNotificationsByUser(context.Background(), "nobody")
+		require.NoError(t, err)
+		assert.Empty(t, none)
+	})
+}
+
+func TestNotificationRepoContract_MarkRead(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newNotificationRepo(t)
+		now := time.Now().UTC().Truncate(time.Millisecond)
+		first := newTestNotification("landlord1", now.Add(-time.Minute))
+		second := newTestNotification("landlord1", now)
+		other := newTestNotification("landlord2", now)
+		for _, notification := range []entities.Notification{first, second, other} {
+			require.NoError(t, repo.SaveNotification(context.Background(), notification))
+		}
+
+		// Only the owner marks a notification read
+		marked, err := repo.MarkNotificationRead(context.Background(), first.ID, "landlord2")
+		require.NoError(t, err)
+		assert.False(t, marked)
+		marked, err = repo.MarkNotificationRead(context.Background(), first.ID, "landlord1")
+		require.NoError(t, err)
+		assert.True(t, marked)
+		marked, err = repo.MarkNotificationRead(context.Background(), primitive.NewObjectID(), "landlord1")
+		require.NoError(t, err)
+		assert.False(t, marked)
+
+		count, err := repo.MarkAllNotificationsRead(context.Background(), "landlord1")
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		found, err := repo.FindNotificationsByUser(context.Background(), "landlord1")
+		require.NoError(t, err)
+		require.Len(t, found, 2)
+		assert.True(t, found[0].Read && found[1].Read)
+
+		found, err = repo.FindNotificationsByUser(context.Background(), "landlord2")
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.False(t, found[0].Read)
+	})
+}
+
+func TestNotificationRepoContract_Delete(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newNotificationRepo(t)
+		now := time.Now().UTC().Truncate(time.Millisecond)
+		read := newTestNotification("landlord1", now.Add(-time.Minute))
+		read.Read = true
+		unread := newTestNotification("landlord1", now)
+		gone := newTestNotification("landlord1", now.Add(-time.Hour))
+		otherRead := newTestNotification("landlord2", now)
+		otherRead.Read = true
+		for _, notification := range []entities.Notification{read, unread, gone, otherRead} {
+			require.NoError(t, repo.SaveNotification(context.Background(), notification))
+		}
+
+		deleted, err := repo.DeleteNotification(context.Background(), gone.ID, "landlord2")
+		require.NoError(t, err)
+		assert.False(t, deleted)
+		deleted, err = repo.DeleteNotification(context.Background(), gone.ID, "landlord1")
+		require.NoError(t, err)
+		assert.True(t, deleted)
+
+		// Clearing keeps the unread ones and those of other users
+		count, err := repo.DeleteReadNotifications(context.Background(), "landlord1")
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		found, err := repo.FindNotificationsByUser(context.Background(), "landlord1")
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, unread.ID, found[0].ID)
+		found, err = repo.FindNotificationsByUser(context.Background(), "landlord2")
+		require.NoError(t, err)
+		assert.Len(t, found, 1)
+	})
+}
@@ -0,0 +1,201 @@
+package repository_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+)
+
+func newTestProperties(landlord string) []entities.Property {
+	return []entities.Property{
+		{
+			ID:               primitive.NewObjectID(),
+			PropertyType:     1, // Commercial
+			Title:            "Commercial Space",
+			Address:          entities.Address{Area: "Downtown", City: "Metropolis", State: "NY", Pincode: 100001},
+			LandlordUsername: landlord,
+			RentAmount:       2000.00,
+			Details:          entities.CommercialDetails{FloorArea: "5000", SubType: "Warehouse"},
+		},
+		{
+			ID:               primitive.NewObjectID(),
+			PropertyType:     2, // House
+			Title:            "Family House",
+			Address:          entities.Address{Area: "Suburb", City: "Smalltown", State: "TX", Pincode: 750001},
+			LandlordUsername: landlord,
+			RentAmount:       1500.00,
+			Details:          entities.HouseDetails{NoOfRooms: 4, FurnishedCategory: "Semi Furnished", Amenities: []string{"garden", "garage"}},
+		},
+		{
+			ID:               primitive.NewObjectID(),
+			PropertyType:     3, // Flat
+			Title:            "Luxury Flat",
+			Address:          entities.Address{Area: "Uptown", City: "Metropolis", State: "NY", Pincode: 100002},
+			LandlordUsername: landlord,
+			RentAmount:       2500.00,
+			Details:          entities.FlatDetails{FurnishedCategory: "Fully Furnished", Amenities: []string{"gym", "pool"}, BHK: 3},
+		},
+	}
+}
+
+func TestPropertyRepoContract_SaveAndFindByID(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newPropertyRepo(t)
+
+		for _, property := range newTestProperties("landlord1") {
+			require.NoError(t, repo.SaveProperty(context.Background(), property))
+
+			// Details must come back with their concrete type, not as a generic document
+			found, err := repo.FindByID(context.Background(), property.ID)
+			require.NoError(t, err)
+			require.NotNil(t, found)
+			assert.Equal(t, property, *found)
+		}
+
+		missing, err := repo.FindByID(context.Background(), primitive.NewObjectID())
+		assert.NoError(t, err)
+		assert.Nil(t, missing)
+	})
+}
+
+func TestPropertyRepoContract_GetAllListedProperties(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newPropertyRepo(t)
+		own := newTestProperties("landlord1")
+		others := newTestProperties("landlord2")
+		others[0].IsRented = true
+		for _, property := range append(own, others...) {
+			require.NoError(t, repo.SaveProperty(context.Background(), property))
+		}
+
+		mine, err := repo.GetAllListedProperties(context.Background(), "landlord1")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, own, mine)
+
+		// A landlord sees their rented properties as well
+		theirs, err := repo.GetAllListedProperties(context.Background(), "landlord2")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, others, theirs)
+
+		available, err := repo.GetAllListedProperties(context.Background(), "")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, append(own, others[1:]...), available)
+	})
+}
+
+func TestPropertyRepoContract_UpdateListedProperty(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newPropertyRepo(t)
+		property := newTestProperties("landlord1")[2]
+		require.NoError(t, repo.SaveProperty(context.Background(), property))
+
+		property.Title = "Renovated Flat"
+		property.RentAmount = 3000.00
+		property.IsRented = true
+		property.Details = entities.FlatDetails{FurnishedCategory: "Unfurnished", Amenities: []string{"lift"}, BHK: 2}
+		require.NoError(t, repo.UpdateListedProperty(context.Background(), property))
+
+		found, err := repo.FindByID(context.Background(), property.ID)
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, property, *found)
+	})
+}
+
+func TestPropertyRepoContract_ApprovalFlow(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newPropertyRepo(t)
+		properties := newTestProperties("landlord1")
+		for _, property := range properties {
+			require.NoError(t, repo.SaveProperty(context.Background(), property))
+		}
+
+		require.NoError(t, repo.UpdateApprovalStatus(context.Background(), properties[0].ID, true, "admin"))
+
+		pending, err := repo.FindPendingProperties(context.Background())
+		require.NoError(t, err)
+		assert.ElementsMatch(t, properties[1:], pending)
+
+		found, err := repo.FindByID(context.Background(), properties[0].ID)
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.True(t, found.IsApprovedByAdmin)
+	})
+}
+
+func TestPropertyRepoContract_Delete(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newPropertyRepo(t)
+		own := newTestProperties("landlord1")
+		others := newTestProperties("landlord2")
+		for _, property := range append(own, others...) {
+			require.NoError(t, repo.SaveProperty(context.Background(), property))
+		}
+
+		// DeleteListedProperty removes a single property identified by its ID
+		require.NoError(t, repo.DeleteListedProperty(context.Background(), own[0].ID))
+		remaining, err := repo.GetAllListedProperties(context.Background(), "")
+		require.NoError(t, err)
+		assert.Len(t, remaining, len(own)+len(others)-1)
+
+		require.NoError(t, repo.DeleteAllListedPropertiesOfaUser(context.Background(), "landlord2"))
+		for _, property := range others {
+			found, err := repo.FindByID(context.Background(), property.ID)
+			assert.NoError(t, err)
+			assert.Nil(t, found)
+		}
+	})
+}
+
+func TestPropertyRepoContract_SetRented(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newPropertyRepo(t)
+		property := newTestProperties("landlord1")[0]
+		require.NoError(t, repo.SaveProperty(context.Background(), property))
+
+		// Of several concurrent attempts to rent out the property only one succeeds
+		const attempts = 8
+		results := make(chan bool, attempts)
+		var wg sync.WaitGroup
+		for i := 0; i < attempts; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				rented, err := repo.SetRented(context.Background(), property.ID, true)
+				assert.NoError(t, err)
+				results <- rented
+			}()
+		}
+		wg.Wait()
+		close(results)
+		succeeded := 0
+		for rented := range results {
+			if rented {
+				succeeded++
+			}
+		}
+		assert.Equal(t, 1, succeeded)
+
+		found, err := repo.FindByID(context.Background(), property.ID)
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.True(t, found.IsRented)
+		assert.Equal(t, property.Title, found.Title)
+
+		updated, err := repo.SetRented(context.Background(), property.ID, false)
+		require.NoError(t, err)
+		assert.True(t, updated)
+		updated, err = repo.SetRented(context.Background(), property.ID, false)
+		require.NoError(t, err)
+		assert.False(t, updated)
+
+		updated, err = repo.SetRented(context.Background(), primitive.NewObjectID(), true)
+		require.NoError(t, err)
+		assert.False(t, updated)
+	})
+}
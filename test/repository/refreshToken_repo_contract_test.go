@@ -0,0 +1,113 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"rentease/internal/domain/entities"
+)
+
+func newTestRefreshToken(id, username string) entities.RefreshToken {
+	// Stored times are compared after a round trip, which keeps millisecond precision in MongoDB
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	return entities.RefreshToken{
+		ID:        id,
+		Username:  username,
+		TokenHash: "hash-" + id,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(time.Hour),
+	}
+}
+
+func TestRefreshTokenRepoContract_SaveAndFind(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newRefreshTokenRepo(t)
+		token := newTestRefreshToken("session-1", "alice")
+		require.NoError(t, repo.SaveRefreshToken(context.Background(), token))
+
+		found, err := repo.FindRefreshToken(context.Background(), "session-1")
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, token, *found)
+
+		// Saving again replaces the stored token
+		token.TokenHash = "rotated"
+		require.NoError(t, repo.SaveRefreshToken(context.Background(), token))
+		found, err = repo.FindRefreshToken(context.Background(), "session-1")
+		require.NoError(t, err)
+		assert.Equal(t, "rotated", found.TokenHash)
+
+		missing, err := repo.FindRefreshToken(context.Background(), "unknown")
+		assert.NoError(t, err)
+		assert.Nil(t, missing)
+	})
+}
+
+func TestRefreshTokenRepoContract_Rotate(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newRefreshTokenRepo(t)
+		ctx := context.Background()
+		token := newTestRefreshToken("session-1", "alice")
+		require.NoError(t, repo.SaveRefreshToken(ctx, token))
+
+		first, second := token, token
+		first.TokenHash, second.TokenHash = "first", "second"
+		rotated, err := repo.RotateRefreshToken(ctx, first, token.TokenHash)
+		require.NoError(t, err)
+		assert.True(t, rotated)
+		// Of two refreshes with the same token only the first rotates it
+		rotated, err = repo.RotateRefreshToken(ctx, second, token.TokenHash)
+		require.NoError(t, err)
+		assert.False(t, rotated)
+		found, err := repo.FindRefreshToken(ctx, "session-1")
+		require.NoError(t, err)
+		assert.Equal(t, "first", found.TokenHash)
+
+		// Revoked and unknown tokens are not rotated
+		require.NoError(t, repo.RevokeRefreshToken(ctx, "session-1"))
+		rotated, err = repo.RotateRefreshToken(ctx, second, "first")
+		require.NoError(t, err)
+		assert.False(t, rotated)
+		unknown := newTestRefreshToken("unknown", "alice")
+		rotated, err = repo.RotateRefreshToken(ctx, unknown, unknown.TokenHash)
+		require.NoError(t, err)
+		assert.False(t, rotated)
+		missing, err := repo.FindRefreshToken(ctx, "unknown")
+		require.NoError(t, err)
+		assert.Nil(t, missing)
+	})
+}
+
+func TestRefreshTokenRepoContract_Revoke(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newRefreshTokenRepo(t)
+		ctx := context.Background()
+		for _, token := range []entities.RefreshToken{
+			newTestRefreshToken("alice-1", "alice"),
+			newTestRefreshToken("alice-2", "alice"),
+			newTestRefreshToken("alice-3", "alice"),
+			newTestRefreshToken("bob-1", "bob"),
+		} {
+			require.NoError(t, repo.SaveRefreshToken(ctx, token))
+		}
+
+		require.NoError(t, repo.RevokeRefreshToken(ctx, "alice-1"))
+		require.NoError(t, repo.RevokeRefreshToken(ctx, "unknown"))
+		revoked, err := repo.FindRefreshToken(ctx, "alice-1")
+		require.NoError(t, err)
+		assert.True(t, revoked.Revoked)
+		active, err := repo.FindRefreshToken(ctx, "alice-2")
+		require.NoError(t, err)
+		assert.False(t, active.Revoked)
+
+		require.NoError(t, repo.RevokeUserRefreshTokens(ctx, "alice"))
+		for id, expected := range map[string]bool{"alice-2": true, "alice-3": true, "bob-1": false} {
+			token, err := repo.FindRefreshToken(ctx, id)
+			require.NoError(t, err)
+			assert.Equal(t, expected, token.Revoked, id)
+		}
+	})
+}
@@ -0,0 +1,220 @@
+package repository_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+)
+
+func newTestRequest(tenant, landlord string) entities.Request {
+	return entities.Request{
+		TenantName:    tenant,
+		PropertyID:    primitive.NewObjectID(),
+		LandlordName:  landlord,
+		RequestStatus: entities.RequestPending,
+		// MongoDB stores times with millisecond precision
+		CreatedAt: time.Now().UTC().Truncate(time.Millisecond),
+	}
+}
+
+func TestRequestRepoContract_SaveAndFind(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newRequestRepo(t)
+		requests := []entities.Request{
+			newTestRequest("tenant1", "landlord1"),
+			newTestRequest("tenant1", "landlord2"),
+			newTestRequest("tenant2", "landlord1"),
+		}
+		for _, request := range requests {
+			require.NoError(t, repo.SaveRequest(context.Background(), request))
+		}
+
+		byTenant, err := repo.FindByTenantUsername(context.Background(), "tenant1")
+		require.NoError(t, err)
+		require.Len(t, byTenant, 2)
+		for _, request := range byTenant {
+			// Saved requests are assigned an ID by the repository
+			assert.False(t, request.ID.IsZero())
+			assert.Equal(t, "tenant1", request.TenantName)
+			assert.True(t, request.CreatedAt.Equal(requests[0].CreatedAt) || request.CreatedAt.Equal(requests[1].CreatedAt))
+		}
+
+		byLandlord, err := repo.FindByLandlordName(context.Background(), "landlord1")
+		require.NoError(t, err)
+		assert.Len(t, byLandlord, 2)
+
+		none, err := repo.FindByTenantUsername(context.Background(), "nobody")
+		assert.NoError(t, err)
+		assert.Empty(t, none)
+	})
+}
+
+func TestRequestRepoContract_CreateRequest(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newRequestRepo(t)
+		request := newTestRequest("tenant1", "landlord1")
+
+		// Of several requests of the tenant for the property created at the same time only one is saved
+		const attempts = 8
+		results := make(chan bool, attempts)
+		var wg sync.WaitGroup
+		for i := 0; i < attempts; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				created, err := repo.CreateRequest(context.Background(), request)
+				assert.NoError(t, err)
+				results <- created
+			}()
+		}
+		wg.Wait()
+		close(results)
+		succeeded := 0
+		for created := range results {
+			if created {
+				succeeded++
+			}
+		}
+		assert.Equal(t, 1, succeeded)
+
+		// Other tenants can still request the property
+		other := newTestRequest("tenant2", "landlord1")
+		other.PropertyID = request.PropertyID
+		created, err := repo.CreateRequest(context.Background(), other)
+		require.NoError(t, err)
+		assert.True(t, created)
+
+		requests, err := repo.FindByTenantUsername(context.Background(), "tenant1")
+		require.NoError(t, err)
+		require.Len(t, requests, 1)
+		assert.False(t, requests[0].ID.IsZero())
+
+		// An accepted request is still open
+		accept := entities.StatusChange{From: entities.RequestPending, To: entities.RequestAccepted, ChangedAt: time.Now(), ChangedBy: "landlord1"}
+		updated, err := repo.UpdateRequestStatus(context.Background(), requests[0].ID, accept)
+		require.NoError(t, err)
+		require.True(t, updated)
+		created, err = repo.CreateRequest(context.Background(), request)
+		require.NoError(t, err)
+		assert.False(t, created)
+
+		// Once the request is final the tenant may ask again
+		cancel := entities.StatusChange{From: entities.RequestAccepted, To: entities.RequestCancelled, ChangedAt: time.Now(), ChangedBy: "landlord1"}
+		updated, err = repo.UpdateRequestStatus(context.Background(), requests[0].ID, cancel)
+		require.NoError(t, err)
+		require.True(t, updated)
+		created, err = repo.CreateRequest(context.Background(), request)
+		require.NoError(t, err)
+		assert.True(t, created)
+
+		requests, err = repo.FindByTenantUsername(context.Background(), "tenant1")
+		require.NoError(t, err)
+		assert.Len(t, requests, 2)
+	})
+}
+
+func TestRequestRepoContract_FindByPropertyID(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newRequestRepo(t)
+		first := newTestRequest("tenant1", "landlord1")
+		second := newTestRequest("tenant2", "landlord1")
+		second.PropertyID = first.PropertyID
+		for _, request := range []entities.Request{first, second, newTestRequest("tenant1", "landlord1")} {
+			require.NoError(t, repo.SaveRequest(context.Background(), request))
+		}
+
+		requests, err := repo.FindByPropertyID(context.Background(), first.PropertyID)
+		require.NoError(t, err)
+		require.Len(t, requests, 2)
+		tenants := []string{requests[0].TenantName, requests[1].TenantName}
+		assert.ElementsMatch(t, []string{"tenant1", "tenant2"}, tenants)
+
+		none, err := repo.FindByPropertyID(context.Background(), primitive.NewObjectID())
+		assert.NoError(t, err)
+		assert.Empty(t, none)
+	})
+}
+
+func TestRequestRepoContract_FindRequestByID(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newRequestRepo(t)
+		request := newTestRequest("tenant1", "landlord1")
+		request.ID = primitive.NewObjectID()
+		require.NoError(t, repo.SaveRequest(context.Background(), request))
+
+		found, err := repo.FindRequestByID(context.Background(), request.ID)
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, "tenant1", found.TenantName)
+		assert.Equal(t, entities.RequestPending, found.RequestStatus)
+
+		missing, err := repo.FindRequestByID(context.Background(), primitive.NewObjectID())
+		assert.NoError(t, err)
+		assert.Nil(t, missing)
+	})
+}
+
+func TestRequestRepoContract_UpdateRequestStatus(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newRequestRepo(t)
+		created := newTestRequest("tenant1", "landlord1")
+		created.History = []entities.StatusChange{{To: entities.RequestPending, ChangedAt: created.CreatedAt, ChangedBy: "tenant1"}}
+		require.NoError(t, repo.SaveRequest(context.Background(), created))
+		// Saved without any history, as requests were before it was recorded
+		require.NoError(t, repo.SaveRequest(context.Background(), newTestRequest("tenant2", "landlord1")))
+
+		requests, err := repo.FindByTenantUsername(context.Background(), "tenant1")
+		require.NoError(t, err)
+		require.Len(t, requests, 1)
+		id := requests[0].ID
+
+		accept := entities.StatusChange{From: entities.RequestPending, To: entities.RequestAccepted, ChangedAt: time.Now().UTC().Truncate(time.Millisecond), ChangedBy: "landlord1", Reason: "first to ask"}
+		updated, err := repo.UpdateRequestStatus(context.Background(), id, accept)
+		require.NoError(t, err)
+		assert.True(t, updated)
+
+		// The request is no longer pending, so a second change from pending does nothing
+		reject := accept
+		reject.To = entities.RequestRejected
+		updated, err = repo.UpdateRequestStatus(context.Background(), id, reject)
+		require.NoError(t, err)
+		assert.False(t, updated)
+
+		updated, err = repo.UpdateRequestStatus(context.Background(), primitive.NewObjectID(), accept)
+		require.NoError(t, err)
+		assert.False(t, updated)
+
+		stored, err := repo.FindRequestByID(context.Background(), id)
+		require.NoError(t, err)
+		assert.Equal(t, entities.RequestAccepted, stored.RequestStatus)
+		require.Len(t, stored.History, 2)
+		assert.Equal(t, entities.RequestPending, stored.History[0].To)
+		assert.Equal(t, accept.To, stored.History[1].To)
+		assert.Equal(t, accept.ChangedBy, stored.History[1].ChangedBy)
+		assert.Equal(t, accept.Reason, stored.History[1].Reason)
+		assert.True(t, accept.ChangedAt.Equal(stored.History[1].ChangedAt))
+
+		other, err := repo.FindByTenantUsername(context.Background(), "tenant2")
+		require.NoError(t, err)
+		require.Len(t, other, 1)
+		updated, err = repo.UpdateRequestStatus(context.Background(), other[0].ID, reject)
+		require.NoError(t, err)
+		assert.True(t, updated)
+
+		rejected, err := repo.FindByStatus(context.Background(), entities.RequestRejected)
+		require.NoError(t, err)
+		require.Len(t, rejected, 1)
+		assert.Equal(t, "tenant2", rejected[0].TenantName)
+		assert.Len(t, rejected[0].History, 1)
+
+		pending, err := repo.FindByStatus(context.Background(), entities.RequestPending)
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+	})
+}
@@ -0,0 +1,118 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+	"rentease/pkg/utils"
+)
+
+func newTestUser(t *testing.T, username, password string) entities.User {
+	hash, err := utils.HashPassword(password)
+	require.NoError(t, err)
+	return entities.User{
+		Username:     username,
+		PasswordHash: hash,
+		Name:         "Test " + username,
+		Age:          30,
+		Email:        username + "@example.com",
+		PhoneNumber:  "9876543210",
+		Address:      "12 Test Street",
+		Role:         "User",
+		Wishlist:     []primitive.ObjectID{},
+	}
+}
+
+func TestUserRepoContract_SaveAndFind(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newUserRepo(t)
+		user := newTestUser(t, "alice", "Secret@123")
+
+		require.NoError(t, repo.SaveUser(context.Background(), user))
+
+		found, err := repo.FindByUsername(context.Background(), "alice")
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, user, *found)
+
+		missing, err := repo.FindByUsername(context.Background(), "nobody")
+		assert.NoError(t, err)
+		assert.Nil(t, missing)
+	})
+}
+
+func TestUserRepoContract_CheckPassword(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newUserRepo(t)
+		require.NoError(t, repo.SaveUser(context.Background(), newTestUser(t, "alice", "Secret@123")))
+
+		tests := []struct {
+			name     string
+			username string
+			password string
+			expected bool
+		}{
+			{name: "Correct password", username: "alice", password: "Secret@123", expected: true},
+			{name: "Wrong password", username: "alice", password: "wrong", expected: false},
+			{name: "Unknown user", username: "nobody", password: "Secret@123", expected: false},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				ok, err := repo.CheckPassword(context.Background(), tt.username, tt.password)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, ok)
+			})
+		}
+	})
+}
+
+func TestUserRepoContract_UpdateUser(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newUserRepo(t)
+		user := newTestUser(t, "alice", "Secret@123")
+		require.NoError(t, repo.SaveUser(context.Background(), user))
+
+		user.Name = "Alice Updated"
+		user.Wishlist = []primitive.ObjectID{primitive.NewObjectID()}
+		require.NoError(t, repo.UpdateUser(context.Background(), user))
+
+		found, err := repo.FindByUsername(context.Background(), "alice")
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, user, *found)
+
+		assert.Error(t, repo.UpdateUser(context.Background(), newTestUser(t, "nobody", "Secret@123")))
+	})
+}
+
+func TestUserRepoContract_FindAllAndDelete(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newUserRepo(t)
+		require.NoError(t, repo.SaveUser(context.Background(), newTestUser(t, "alice", "Secret@123")))
+		require.NoError(t, repo.SaveUser(context.Background(), newTestUser(t, "bob", "Secret@123")))
+
+		users, err := repo.FindAll(context.Background())
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"alice", "bob"}, usernames(users))
+
+		require.NoError(t, repo.Delete(context.Background(), "alice"))
+		assert.NoError(t, repo.Delete(context.Background(), "nobody"))
+
+		users, err = repo.FindAll(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"bob"}, usernames(users))
+	})
+}
+
+func usernames(users []entities.User) []string {
+	var names []string
+	for _, user := range users {
+		names = append(names, user.Username)
+	}
+	return names
+}
@@ -0,0 +1,158 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/domain/entities"
+)
+
+func newTestSlot(propertyID primitive.ObjectID, landlord string, start time.Time) entities.VisitSlot {
+	return entities.VisitSlot{
+		ID:           primitive.NewObjectID(),
+		PropertyID:   propertyID,
+		LandlordName: landlord,
+		Start:        start,
+		End:          start.Add(30 * time.Minute),
+		Status:       entities.VisitOpen,
+		CreatedAt:    start.Add(-24 * time.Hour),
+		UpdatedAt:    start.Add(-24 * time.Hour),
+	}
+}
+
+func TestVisitRepoContract_SaveAndFind(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newVisitRepo(t)
+		start := time.Now().UTC().Truncate(time.Millisecond).Add(24 * time.Hour)
+		propertyID := primitive.NewObjectID()
+
+		later := newTestSlot(propertyID, "landlord1", start.Add(time.Hour))
+		earlier := newTestSlot(propertyID, "landlord1", start)
+		other := newTestSlot(primitive.NewObjectID(), "landlord2", start)
+		for _, slot := range []entities.VisitSlot{later, earlier, other} {
+			require.NoError(t, repo.SaveSlot(context.Background(), slot))
+		}
+
+		found, err := repo.FindSlotByID(context.Background(), later.ID)
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, propertyID, found.PropertyID)
+		assert.Equal(t, entities.VisitOpen, found.Status)
+		assert.True(t, later.Start.Equal(found.Start))
+		assert.True(t, later.End.Equal(found.End))
+		assert.Empty(t, found.TenantName)
+		assert.Nil(t, found.BookedAt)
+
+		missing, err := repo.FindSlotByID(context.Background(), primitive.NewObjectID())
+		assert.NoError(t, err)
+		assert.Nil(t, missing)
+
+		// The lists are ordered by when the slots start
+		byProperty, err := repo.FindSlotsByProperty(context.Background(), propertyID)
+		require.NoError(t, err)
+		require.Len(t, byProperty, 2)
+		assert.Equal(t, earlier.ID, byProperty[0].ID)
+		assert.Equal(t, later.ID, byProperty[1].ID)
+
+		byLandlord, err := repo.FindSlotsByLandlord(context.Background(), "landlord2")
+		require.NoError(t, err)
+		require.Len(t, byLandlord, 1)
+		assert.Equal(t, other.ID, byLandlord[0].ID)
+
+		byTenant, err := repo.FindSlotsByTenant(context.Background(), "tenant1")
+		require.NoError(t, err)
+		assert.Empty(t, byTenant)
+	})
+}
+
+func TestVisitRepoContract_BookAndFreeSlot(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newVisitRepo(t)
+		start := time.Now().UTC().Truncate(time.Millisecond).Add(24 * time.Hour)
+		slot := newTestSlot(primitive.NewObjectID(), "landlord1", start)
+		require.NoError(t, repo.SaveSlot(context.Background(), slot))
+
+		bookedAt := start.Add(-time.Hour)
+		booked, err := repo.BookSlot(context.Background(), slot.ID, "tenant1", bookedAt)
+		require.NoError(t, err)
+		assert.True(t, booked)
+
+		// A booked slot cannot be booked again
+		booked, err = repo.BookSlot(context.Background(), slot.ID, "tenant2", bookedAt)
+		require.NoError(t, err)
+		assert.False(t, booked)
+
+		booked, err = repo.BookSlot(context.Background(), primitive.NewObjectID(), "tenant2", bookedAt)
+		require.NoError(t, err)
+		assert.False(t, booked)
+
+		found, err := repo.FindSlotByID(context.Background(), slot.ID)
+		require.NoError(t, err)
+		assert.Equal(t, entities.VisitBooked, found.Status)
+		assert.Equal(t, "tenant1", found.TenantName)
+		require.NotNil(t, found.BookedAt)
+		assert.True(t, bookedAt.Equal(*found.BookedAt))
+		byTenant, err := repo.FindSlotsByTenant(context.Background(), "tenant1")
+		require.NoError(t, err)
+		require.Len(t, byTenant, 1)
+
+		// Only the tenant who booked it frees it
+		freed, err := repo.FreeSlot(context.Background(), slot.ID, "tenant2", bookedAt)
+		require.NoError(t, err)
+		assert.False(t, freed)
+		freed, err = repo.FreeSlot(context.Background(), slot.ID, "tenant1", bookedAt.Add(time.Minute))
+		require.NoError(t, err)
+		assert.True(t, freed)
+
+		found, err = repo.FindSlotByID(context.Background(), slot.ID)
+		require.NoError(t, err)
+		assert.Equal(t, entities.VisitOpen, found.Status)
+		assert.Empty(t, found.TenantName)
+		assert.Nil(t, found.BookedAt)
+		assert.True(t, bookedAt.Add(time.Minute).Equal(found.UpdatedAt))
+		byTenant, err = repo.FindSlotsByTenant(context.Background(), "tenant1")
+		require.NoError(t, err)
+		assert.Empty(t, byTenant)
+
+		// Freeing a slot that is open again changes nothing
+		freed, err = repo.FreeSlot(context.Background(), slot.ID, "tenant1", bookedAt)
+		require.NoError(t, err)
+		assert.False(t, freed)
+	})
+}
+
+func TestVisitRepoContract_CancelSlot(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, b backend) {
+		repo := b.newVisitRepo(t)
+		start := time.Now().UTC().Truncate(time.Millisecond).Add(24 * time.Hour)
+		slot := newTestSlot(primitive.NewObjectID(), "landlord1", start)
+		require.NoError(t, repo.SaveSlot(context.Background(), slot))
+		booked, err := repo.BookSlot(context.Background(), slot.ID, "tenant1", start.Add(-time.Hour))
+		require.NoError(t, err)
+		require.True(t, booked)
+
+		// The slot is no longer open
+		cancelled, err := repo.CancelSlot(context.Background(), slot.ID, entities.VisitOpen, "landlord1", start)
+		require.NoError(t, err)
+		assert.False(t, cancelled)
+
+		cancelled, err = repo.CancelSlot(context.Background(), slot.ID, entities.VisitBooked, "landlord1", start)
+		require.NoError(t, err)
+		assert.True(t, cancelled)
+
+		found, err := repo.FindSlotByID(context.Background(), slot.ID)
+		require.NoError(t, err)
+		assert.Equal(t, entities.VisitCancelled, found.Status)
+		assert.Equal(t, "landlord1", found.CancelledBy)
+		// Who booked it is kept, so the tenant still sees the visit was called off
+		assert.Equal(t, "tenant1", found.TenantName)
+
+		booked, err = repo.BookSlot(context.Background(), slot.ID, "tenant2", start)
+		require.NoError(t, err)
+		assert.False(t, booked)
+	})
+}
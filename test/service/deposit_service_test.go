@@ -0,0 +1,286 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/app/services"
+	"rentease/internal/domain/entities"
+	mocks_interfaces "rentease/test/mocks/repository"
+)
+
+var (
+	mockDepositRepo *mocks_interfaces.MockDepositRepo
+	depositService  *services.DepositService
+)
+
+func setupDeposits(t *testing.T) func() {
+	ctrl := gomock.NewController(t)
+	mockDepositRepo = mocks_interfaces.NewMockDepositRepo(ctrl)
+	mockLeaseRepo = mocks_interfaces.NewMockLeaseRepo(ctrl)
+	depositService = services.NewDepositService(mockDepositRepo, mockLeaseRepo)
+	return func() {
+		ctrl.Finish()
+	}
+}
+
+// newHeldDeposit returns the deposit of the lease as received in full.
+func newHeldDeposit(lease *entities.Lease) *entities.Deposit {
+	return &entities.Deposit{
+		ID:         primitive.NewObjectID(),
+		LeaseID:    lease.ID,
+		Agreed:     lease.Deposit,
+		Received:   lease.Deposit,
+		ReceivedOn: lease.StartDate,
+		Mode:       entities.PaymentBankTransfer,
+		Status:     entities.DepositHeld,
+		Deductions: []entities.DepositDeduction{},
+		Refund:     lease.Deposit,
+	}
+}
+
+// expectUpdateDeposit expects the deposit to be replaced if it is still in from, and returns the stored deposit.
+func expectUpdateDeposit(from entities.DepositStatus, updated bool) *entities.Deposit {
+	stored := &entities.Deposit{}
+	mockDepositRepo.EXPECT().UpdateDeposit(gomock.Any(), gomock.Any(), from).
+		DoAndReturn(func(_ context.Context, deposit entities.Deposit, _ entities.DepositStatus) (bool, error) {
+			*stored = deposit
+			return updated, nil
+		})
+	return stored
+}
+
+func TestDepositService_Deposit(t *testing.T) {
+	cleanup := setupDeposits(t)
+	defer cleanup()
+
+	lease := newActiveLease()
+	tenant := newTestSession("tenant1", entities.RoleTenant)
+
+	// Nothing received yet: the deposit the lease sets is awaited
+	mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+	mockDepositRepo.EXPECT().FindDepositByLease(gomock.Any(), lease.ID).Return(nil, nil)
+	deposit, err := depositService.Deposit(context.Background(), tenant, lease.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.DepositAwaited, deposit.Status)
+	assert.Equal(t, 2000.0, deposit.Agreed)
+	assert.Empty(t, deposit.Deductions)
+
+	held := newHeldDeposit(lease)
+	mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+	mockDepositRepo.EXPECT().FindDepositByLease(gomock.Any(), lease.ID).Return(held, nil)
+	deposit, err = depositService.Deposit(context.Background(), newTestSession("admin", entities.RoleAdmin), lease.ID)
+	require.NoError(t, err)
+	assert.Equal(t, *held, deposit)
+
+	mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+	_, err = depositService.Deposit(context.Background(), newTestSession("tenant2", entities.RoleTenant), lease.ID)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+
+	mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(nil, nil)
+	_, err = depositService.Deposit(context.Background(), tenant, lease.ID)
+	assert.ErrorIs(t, err, services.ErrLeaseNotFound)
+}
+
+func TestDepositService_RecordDeposit(t *testing.T) {
+	cleanup := setupDeposits(t)
+	defer cleanup()
+
+	lease := newActiveLease()
+	landlord := newTestSession("landlord1", entities.RoleLandlord)
+	receipt := entities.Payment{Amount: 2000, PaidOn: lease.StartDate, Mode: entities.PaymentUPI, Reference: "UPI-42"}
+
+	mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+	var created entities.Deposit
+	mockDepositRepo.EXPECT().CreateDeposit(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, deposit entities.Deposit) (bool, error) {
+			created = deposit
+			return true, nil
+		})
+	deposit, err := depositService.RecordDeposit(context.Background(), landlord, lease.ID, receipt)
+	require.NoError(t, err)
+	assert.Equal(t, created, deposit)
+	assert.False(t, deposit.ID.IsZero())
+	assert.Equal(t, entities.DepositHeld, deposit.Status)
+	assert.Equal(t, 2000.0, deposit.Received)
+	assert.Equal(t, 2000.0, deposit.Refund)
+	assert.Equal(t, "landlord1", deposit.RecordedBy)
+	assert.Equal(t, "UPI-42", deposit.Reference)
+	assert.True(t, lease.StartDate.Equal(deposit.ReceivedOn))
+
+	// Recorded twice: the deposit is reported as it is stored
+	mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+	mockDepositRepo.EXPECT().CreateDeposit(gomock.Any(), gomock.Any()).Return(false, nil)
+	mockDepositRepo.EXPECT().FindDepositByLease(gomock.Any(), lease.ID).Return(&created, nil)
+	_, err = depositService.RecordDeposit(context.Background(), landlord, lease.ID, receipt)
+	var stateErr *services.DepositStateError
+	require.ErrorAs(t, err, &stateErr)
+	assert.Equal(t, entities.DepositHeld, stateErr.Status)
+	assert.ErrorIs(t, err, services.ErrInvalidTransition)
+
+	tests := []struct {
+		name    string
+		session *entities.Session
+		receipt entities.Payment
+		lease   *entities.Lease
+		wantErr error
+	}{
+		{"Nothing received", landlord, entities.Payment{Amount: 0, Mode: entities.PaymentCash}, nil, services.ErrInvalidPayment},
+		{"Unknown mode", landlord, entities.Payment{Amount: 100, Mode: "barter"}, nil, services.ErrInvalidPayment},
+		{"Tenant", newTestSession("tenant1", entities.RoleTenant), receipt, nil, services.ErrForbidden},
+		{"Another landlord", newTestSession("landlord2", entities.RoleLandlord), receipt, lease, services.ErrForbidden},
+		{"Unsigned lease", landlord, receipt, &entities.Lease{ID: lease.ID, LandlordName: "landlord1", Status: entities.LeasePending}, services.ErrInvalidTransition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.lease != nil {
+				mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(tt.lease, nil)
+			}
+			_, err := depositService.RecordDeposit(context.Background(), tt.session, lease.ID, tt.receipt)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestDepositService_ProposeDeductions(t *testing.T) {
+	cleanup := setupDeposits(t)
+	defer cleanup()
+
+	lease := newActiveLease()
+	lease.Status = entities.LeaseEnding
+	landlord := newTestSession("landlord1", entities.RoleLandlord)
+	deductions := []entities.DepositDeduction{
+		{Reason: " Repainting ", Amount: 450.255, Note: "Living room walls"},
+		{Reason: "Broken tap", Amount: 120},
+	}
+
+	mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+	mockDepositRepo.EXPECT().FindDepositByLease(gomock.Any(), lease.ID).Return(newHeldDeposit(lease), nil)
+	stored := expectUpdateDeposit(entities.DepositHeld, true)
+	deposit, err := depositService.ProposeDeductions(context.Background(), landlord, lease.ID, deductions)
+	require.NoError(t, err)
+	assert.Equal(t, *stored, deposit)
+	assert.Equal(t, entities.DepositProposed, deposit.Status)
+	require.Len(t, deposit.Deductions, 2)
+	assert.Equal(t, "Repainting", deposit.Deductions[0].Reason)
+	assert.Equal(t, 450.26, deposit.Deductions[0].Amount)
+	assert.Equal(t, 570.26, deposit.Deducted())
+	assert.Equal(t, 1429.74, deposit.Refund)
+	assert.NotNil(t, deposit.ProposedAt)
+
+	// Disputed deductions are revised; no deductions refund the whole deposit
+	disputed := newHeldDeposit(lease)
+	disputed.Status = entities.DepositDisputed
+	disputed.Deductions = deductions
+	mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+	mockDepositRepo.EXPECT().FindDepositByLease(gomock.Any(), lease.ID).Return(disputed, nil)
+	expectUpdateDeposit(entities.DepositDisputed, true)
+	deposit, err = depositService.ProposeDeductions(context.Background(), landlord, lease.ID, nil)
+	require.NoError(t, err)
+	assert.Empty(t, deposit.Deductions)
+	assert.Equal(t, 2000.0, deposit.Refund)
+
+	// The tenant acknowledged meanwhile
+	closed := newHeldDeposit(lease)
+	closed.Status = entities.DepositClosed
+	mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+	mockDepositRepo.EXPECT().FindDepositByLease(gomock.Any(), lease.ID).Return(newHeldDeposit(lease), nil)
+	expectUpdateDeposit(entities.DepositHeld, false)
+	mockDepositRepo.EXPECT().FindDepositByLease(gomock.Any(), lease.ID).Return(closed, nil)
+	_, err = depositService.ProposeDeductions(context.Background(), landlord, lease.ID, deductions)
+	var stateErr *services.DepositStateError
+	require.ErrorAs(t, err, &stateErr)
+	assert.Equal(t, entities.DepositClosed, stateErr.Status)
+
+	active := newActiveLease()
+	active.ID = lease.ID
+	tests := []struct {
+		name       string
+		session    *entities.Session
+		lease      *entities.Lease
+		deposit    *entities.Deposit
+		deductions []entities.DepositDeduction
+		wantErr    error
+	}{
+		{"Tenant", newTestSession("tenant1", entities.RoleTenant), nil, nil, deductions, services.ErrForbidden},
+		{"Another landlord", newTestSession("landlord2", entities.RoleLandlord), lease, nil, deductions, services.ErrForbidden},
+		{"Lease still running", landlord, active, nil, deductions, services.ErrInvalidTransition},
+		{"Deposit not received", landlord, lease, &entities.Deposit{}, deductions, services.ErrInvalidTransition},
+		{"Deposit closed", landlord, lease, closed, deductions, services.ErrInvalidTransition},
+		{"No reason", landlord, lease, newHeldDeposit(lease), []entities.DepositDeduction{{Reason: " ", Amount: 10}}, services.ErrInvalidDeposit},
+		{"Negative amount", landlord, lease, newHeldDeposit(lease), []entities.DepositDeduction{{Reason: "Cleaning", Amount: -10}}, services.ErrInvalidDeposit},
+		{"More than the deposit", landlord, lease, newHeldDeposit(lease), []entities.DepositDeduction{{Reason: "Cleaning", Amount: 1500}, {Reason: "Repairs", Amount: 500.01}}, services.ErrInvalidDeposit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.lease != nil {
+				mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(tt.lease, nil)
+			}
+			if tt.deposit != nil {
+				stored := tt.deposit
+				if stored.ID.IsZero() {
+					stored = nil // Not received yet
+				}
+				mockDepositRepo.EXPECT().FindDepositByLease(gomock.Any(), lease.ID).Return(stored, nil)
+			}
+			_, err := depositService.ProposeDeductions(context.Background(), tt.session, lease.ID, tt.deductions)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestDepositService_Settle(t *testing.T) {
+	cleanup := setupDeposits(t)
+	defer cleanup()
+
+	lease := newActiveLease()
+	lease.Status = entities.LeaseEnded
+	tenant := newTestSession("tenant1", entities.RoleTenant)
+	proposed := func() *entities.Deposit {
+		deposit := newHeldDeposit(lease)
+		deposit.Deductions = []entities.DepositDeduction{{Reason: "Cleaning", Amount: 300}}
+		deposit.Refund = 1700
+		deposit.Status = entities.DepositProposed
+		return deposit
+	}
+
+	mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+	mockDepositRepo.EXPECT().FindDepositByLease(gomock.Any(), lease.ID).Return(proposed(), nil)
+	expectUpdateDeposit(entities.DepositProposed, true)
+	deposit, err := depositService.DisputeDeductions(context.Background(), tenant, lease.ID, " The flat was cleaned ")
+	require.NoError(t, err)
+	assert.Equal(t, entities.DepositDisputed, deposit.Status)
+	require.NotNil(t, deposit.Dispute)
+	assert.Equal(t, "The flat was cleaned", deposit.Dispute.Reason)
+	assert.WithinDuration(t, time.Now(), deposit.Dispute.RaisedAt, time.Minute)
+
+	mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+	mockDepositRepo.EXPECT().FindDepositByLease(gomock.Any(), lease.ID).Return(proposed(), nil)
+	stored := expectUpdateDeposit(entities.DepositProposed, true)
+	deposit, err = depositService.AcknowledgeDeductions(context.Background(), tenant, lease.ID)
+	require.NoError(t, err)
+	assert.Equal(t, *stored, deposit)
+	assert.Equal(t, entities.DepositClosed, deposit.Status)
+	assert.NotNil(t, deposit.ClosedAt)
+	assert.Equal(t, 1700.0, deposit.Refund)
+
+	_, err = depositService.DisputeDeductions(context.Background(), tenant, lease.ID, "  ")
+	assert.ErrorIs(t, err, services.ErrInvalidDeposit)
+
+	mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+	_, err = depositService.AcknowledgeDeductions(context.Background(), newTestSession("landlord1", entities.RoleLandlord), lease.ID)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+
+	// Deductions not itemised yet
+	mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+	mockDepositRepo.EXPECT().FindDepositByLease(gomock.Any(), lease.ID).Return(newHeldDeposit(lease), nil)
+	_, err = depositService.AcknowledgeDeductions(context.Background(), tenant, lease.ID)
+	var stateErr *services.DepositStateError
+	require.ErrorAs(t, err, &stateErr)
+	assert.Equal(t, entities.DepositHeld, stateErr.Status)
+}
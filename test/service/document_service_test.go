@@ -0,0 +1,221 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/app/services"
+	"rentease/internal/documents"
+	"rentease/internal/domain/entities"
+	mocks_interfaces "rentease/test/mocks/repository"
+)
+
+var documentService *services.DocumentService
+
+func setupDocuments(t *testing.T, templateDir string) func() {
+	ctrl := gomock.NewController(t)
+	mockLeaseRepo = mocks_interfaces.NewMockLeaseRepo(ctrl)
+	mockLedgerRepo = mocks_interfaces.NewMockLedgerRepo(ctrl)
+	mockPropertyRepo = mocks_interfaces.NewMockPropertyRepo(ctrl)
+	mockUserRepo = mocks_interfaces.NewMockUserRepo(ctrl)
+	renderer, err := documents.NewRenderer(templateDir)
+	require.NoError(t, err)
+	documentService = services.NewDocumentService(mockLeaseRepo, mockLedgerRepo, mockPropertyRepo, mockUserRepo, renderer)
+	return func() {
+		ctrl.Finish()
+	}
+}
+
+func newTestPayment(lease *entities.Lease, amount float64) entities.Payment {
+	return entities.Payment{
+		ID:         primitive.NewObjectID(),
+		LeaseID:    lease.ID,
+		Amount:     amount,
+		PaidOn:     time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC),
+		Mode:       entities.PaymentUPI,
+		Reference:  "UPI-4411",
+		RecordedBy: lease.LandlordName,
+		RecordedAt: time.Now(),
+	}
+}
+
+// expectParties expects the property and both parties of the lease to be looked up.
+func expectParties(lease *entities.Lease) {
+	mockPropertyRepo.EXPECT().FindByID(gomock.Any(), lease.PropertyID).Return(&entities.Property{
+		ID:      lease.PropertyID,
+		Title:   "Family House",
+		Address: entities.Address{Area: "Baner", City: "Pune", State: "Maharashtra", Pincode: 411045},
+	}, nil)
+	mockUserRepo.EXPECT().FindByUsername(gomock.Any(), lease.LandlordName).
+		Return(&entities.User{Username: lease.LandlordName, Name: "Asha Landlord", PhoneNumber: "9876543210"}, nil)
+	// The tenant deleted their account since
+	mockUserRepo.EXPECT().FindByUsername(gomock.Any(), lease.TenantName).Return(nil, nil)
+}
+
+func TestDocumentService_RentReceipt(t *testing.T) {
+	cleanup := setupDocuments(t, "")
+	defer cleanup()
+
+	lease := newActiveLease()
+	payment := newTestPayment(lease, 500.5)
+	mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+	mockLedgerRepo.EXPECT().FindPaymentsByLease(gomock.Any(), lease.ID).
+		Return([]entities.Payment{newTestPayment(lease, 1000), payment}, nil)
+	expectParties(lease)
+
+	document, err := documentService.RentReceipt(context.Background(), newTestSession("tenant1", entities.RoleTenant), lease.ID, payment.ID)
+	require.NoError(t, err)
+	number := services.ReceiptNumber(payment)
+	assert.Equal(t, "receipt-"+number+".pdf", document.FileName)
+	assert.Equal(t, "application/pdf", document.ContentType)
+	content := string(document.Content)
+	assert.Contains(t, content, "(Rent Receipt) Tj")
+	assert.Contains(t, content, "(Receipt No. "+number+") Tj")
+	assert.Contains(t, content, "Rs. 500.50 \\(Rupees Five Hundred and Fifty Paise Only\\)")
+	assert.Contains(t, content, "(Family House) Tj")
+	assert.Contains(t, content, "(Asha Landlord \\(landlord1\\)) Tj")
+	assert.Contains(t, content, "(tenant1) Tj")
+}
+
+func TestDocumentService_RentReceiptErrors(t *testing.T) {
+	t.Run("Not a party to the lease", func(t *testing.T) {
+		cleanup := setupDocuments(t, "")
+		defer cleanup()
+
+		lease := newActiveLease()
+		mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+
+		_, err := documentService.RentReceipt(context.Background(), newTestSession("tenant2", entities.RoleTenant), lease.ID, primitive.NewObjectID())
+		assert.ErrorIs(t, err, services.ErrForbidden)
+	})
+
+	t.Run("Unknown payment", func(t *testing.T) {
+		cleanup := setupDocuments(t, "")
+		defer cleanup()
+
+		lease := newActiveLease()
+		mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+		mockLedgerRepo.EXPECT().FindPaymentsByLease(gomock.Any(), lease.ID).Return([]entities.Payment{newTestPayment(lease, 1000)}, nil)
+
+		_, err := documentService.RentReceipt(context.Background(), newTestSession("landlord1", entities.RoleUser), lease.ID, primitive.NewObjectID())
+		assert.ErrorIs(t, err, services.ErrPaymentNotFound)
+	})
+
+	t.Run("Unknown lease", func(t *testing.T) {
+		cleanup := setupDocuments(t, "")
+		defer cleanup()
+
+		id := primitive.NewObjectID()
+		mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), id).Return(nil, nil)
+
+		_, err := documentService.RentReceipt(context.Background(), newTestSession("tenant1", entities.RoleTenant), id, primitive.NewObjectID())
+		assert.ErrorIs(t, err, services.ErrLeaseNotFound)
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		cleanup := setupDocuments(t, "")
+		defer cleanup()
+
+		lease := newActiveLease()
+		mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+		mockLedgerRepo.EXPECT().FindPaymentsByLease(gomock.Any(), lease.ID).Return(nil, errors.New("db error"))
+
+		_, err := documentService.RentReceipt(context.Background(), newTestSession("tenant1", entities.RoleTenant), lease.ID, primitive.NewObjectID())
+		assert.EqualError(t, err, "db error")
+	})
+}
+
+func TestDocumentService_LeaseAgreement(t *testing.T) {
+	t.Run("Active lease", func(t *testing.T) {
+		cleanup := setupDocuments(t, "")
+		defer cleanup()
+
+		lease := newActiveLease()
+		mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+		expectParties(lease)
+
+		document, err := documentService.LeaseAgreement(context.Background(), newTestSession("landlord1", entities.RoleUser), lease.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "lease-"+lease.ID.Hex()+".pdf", document.FileName)
+		content := string(document.Content)
+		assert.Contains(t, content, "(Lease Agreement) Tj")
+		assert.Contains(t, content, "Rs. 1,000.00 \\(Rupees One Thousand Only\\)")
+		assert.Contains(t, content, "(Baner, Pune, Maharashtra - 411045) Tj")
+	})
+
+	t.Run("Cancelled lease", func(t *testing.T) {
+		cleanup := setupDocuments(t, "")
+		defer cleanup()
+
+		lease := newActiveLease()
+		lease.Status = entities.LeaseCancelled
+		mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+
+		_, err := documentService.LeaseAgreement(context.Background(), newTestSession("tenant1", entities.RoleTenant), lease.ID)
+		assert.ErrorIs(t, err, services.ErrInvalidTransition)
+	})
+
+	t.Run("Not a party to the lease", func(t *testing.T) {
+		cleanup := setupDocuments(t, "")
+		defer cleanup()
+
+		lease := newActiveLease()
+		mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+
+		_, err := documentService.LeaseAgreement(context.Background(), newTestSession("moderator", entities.RoleModerator), lease.ID)
+		assert.ErrorIs(t, err, services.ErrForbidden)
+	})
+}
+
+func TestDocumentService_CustomTemplates(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, documents.ReceiptTemplate),
+		[]byte("# Acme Rentals\n{{.Number}} for INR {{money .Payment.Amount}}\n"), 0o644))
+	cleanup := setupDocuments(t, dir)
+	defer cleanup()
+
+	lease := newActiveLease()
+	payment := newTestPayment(lease, 1500)
+	mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+	mockLedgerRepo.EXPECT().FindPaymentsByLease(gomock.Any(), lease.ID).Return([]entities.Payment{payment}, nil)
+	expectParties(lease)
+
+	document, err := documentService.RentReceipt(context.Background(), newTestSession("tenant1", entities.RoleTenant), lease.ID, payment.ID)
+	require.NoError(t, err)
+	assert.Contains(t, string(document.Content), "(Acme Rentals) Tj")
+	assert.Contains(t, string(document.Content), "("+services.ReceiptNumber(payment)+" for INR 1,500.00) Tj")
+	assert.NotContains(t, string(document.Content), "(Rent Receipt) Tj")
+}
+
+func TestDocuments_Templates(t *testing.T) {
+	t.Run("Export", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "templates")
+		files, err := documents.ExportTemplates(dir)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{filepath.Join(dir, documents.ReceiptTemplate), filepath.Join(dir, documents.LeaseTemplate)}, files)
+
+		// Exported templates load as they are
+		_, err = documents.NewRenderer(dir)
+		assert.NoError(t, err)
+
+		// and are not overwritten once edited
+		_, err = documents.ExportTemplates(dir)
+		assert.ErrorIs(t, err, os.ErrExist)
+	})
+
+	t.Run("Invalid template", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, documents.LeaseTemplate), []byte("{{.Lease"), 0o644))
+
+		_, err := documents.NewRenderer(dir)
+		assert.ErrorContains(t, err, documents.LeaseTemplate)
+	})
+}
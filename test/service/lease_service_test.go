@@ -0,0 +1,411 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/app/services"
+	"rentease/internal/domain/entities"
+	mocks_interfaces "rentease/test/mocks/repository"
+)
+
+var leaseService *services.LeaseService
+
+func setupLeases(t *testing.T) func() {
+	ctrl := gomock.NewController(t)
+	mockLeaseRepo = mocks_interfaces.NewMockLeaseRepo(ctrl)
+	mockPropertyRepo = mocks_interfaces.NewMockPropertyRepo(ctrl)
+	leaseService = services.NewLeaseService(mockLeaseRepo, mockPropertyRepo, true)
+	return func() {
+		ctrl.Finish()
+	}
+}
+
+func TestLeaseService_GetLeases(t *testing.T) {
+	cleanup := setupLeases(t)
+	defer cleanup()
+
+	leases := []entities.Lease{{ID: primitive.NewObjectID(), TenantName: "tenant1", LandlordName: "landlord1"}}
+
+	mockLeaseRepo.EXPECT().FindLeasesByTenant(gomock.Any(), "tenant1").Return(leases, nil)
+	result, err := leaseService.GetLeasesForTenant(context.Background(), newTestSession("tenant1", entities.RoleTenant))
+	assert.NoError(t, err)
+	assert.Equal(t, leases, result)
+
+	mockLeaseRepo.EXPECT().FindLeasesByLandlord(gomock.Any(), "landlord1").Return(leases, nil)
+	result, err = leaseService.GetLeasesForLandlord(context.Background(), newTestSession("landlord1", entities.RoleLandlord))
+	assert.NoError(t, err)
+	assert.Equal(t, leases, result)
+
+	_, err = leaseService.GetLeasesForLandlord(context.Background(), newTestSession("tenant1", entities.RoleTenant))
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	_, err = leaseService.GetLeasesForTenant(context.Background(), newTestSession("landlord1", entities.RoleLandlord))
+	assert.ErrorIs(t, err, services.ErrForbidden)
+}
+
+func TestLeaseService_GetLease(t *testing.T) {
+	cleanup := setupLeases(t)
+	defer cleanup()
+
+	lease := &entities.Lease{ID: primitive.NewObjectID(), TenantName: "tenant1", LandlordName: "landlord1", Status: entities.LeasePending}
+
+	tests := []struct {
+		name    string
+		session *entities.Session
+		allowed bool
+	}{
+		{name: "Tenant of the lease", session: newTestSession("tenant1", entities.RoleTenant), allowed: true},
+		{name: "Landlord of the lease", session: newTestSession("landlord1", entities.RoleLandlord), allowed: true},
+		{name: "Moderator", session: newTestSession("moderator", entities.RoleModerator), allowed: true},
+		{name: "Another tenant", session: newTestSession("tenant2", entities.RoleTenant)},
+		{name: "Another landlord", session: newTestSession("landlord2", entities.RoleLandlord)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+
+			result, err := leaseService.GetLease(context.Background(), tt.session, lease.ID)
+			if tt.allowed {
+				assert.NoError(t, err)
+				assert.Equal(t, *lease, result)
+			} else {
+				assert.ErrorIs(t, err, services.ErrForbidden)
+			}
+		})
+	}
+
+	t.Run("Unknown lease", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), id).Return(nil, nil)
+
+		_, err := leaseService.GetLease(context.Background(), newTestSession("tenant1", entities.RoleTenant), id)
+		assert.ErrorIs(t, err, services.ErrLeaseNotFound)
+	})
+}
+
+// newActiveLease returns an active lease of tenant1 for a property of landlord1 that started today.
+func newActiveLease() *entities.Lease {
+	start := time.Now().UTC().Truncate(24 * time.Hour)
+	return &entities.Lease{
+		ID:               primitive.NewObjectID(),
+		RequestID:        primitive.NewObjectID(),
+		PropertyID:       primitive.NewObjectID(),
+		TenantName:       "tenant1",
+		LandlordName:     "landlord1",
+		StartDate:        start,
+		EndDate:          start.AddDate(0, services.DefaultLeaseMonths, 0),
+		MonthlyRent:      1000,
+		Deposit:          2000,
+		NoticePeriodDays: services.DefaultNoticePeriodDays,
+		Status:           entities.LeaseActive,
+	}
+}
+
+// expectUpdateLease expects the lease to be replaced if it is still in from, and returns the stored lease.
+func expectUpdateLease(id primitive.ObjectID, from entities.LeaseStatus, updated bool) *entities.Lease {
+	stored := &entities.Lease{}
+	mockLeaseRepo.EXPECT().UpdateLease(gomock.Any(), gomock.Any(), from).
+		DoAndReturn(func(_ context.Context, lease entities.Lease, _ entities.LeaseStatus) (bool, error) {
+			if lease.ID != id {
+				return false, nil
+			}
+			*stored = lease
+			return updated, nil
+		})
+	return stored
+}
+
+func TestLeaseService_GiveNotice(t *testing.T) {
+	cleanup := setupLeases(t)
+	defer cleanup()
+
+	t.Run("Tenant gives notice", func(t *testing.T) {
+		lease := newActiveLease()
+		lease.Renewal = &entities.RenewalOffer{MonthlyRent: 1100, EndDate: lease.EndDate.AddDate(1, 0, 0), Status: entities.RenewalOffered}
+		mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+		stored := expectUpdateLease(lease.ID, entities.LeaseActive, true)
+
+		result, err := leaseService.GiveNotice(context.Background(), newTestSession("tenant1", entities.RoleTenant), lease.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, *stored, result)
+		assert.Equal(t, entities.LeaseEnding, stored.Status)
+		assert.Equal(t, lease.StartDate.AddDate(0, 0, services.DefaultNoticePeriodDays), stored.EndDate)
+		if assert.NotNil(t, stored.Notice) {
+			assert.Equal(t, "tenant1", stored.Notice.GivenBy)
+		}
+		// The open renewal offer lapses
+		assert.Equal(t, entities.RenewalLapsed, stored.Renewal.Status)
+	})
+
+	t.Run("Notice near the end date", func(t *testing.T) {
+		lease := newActiveLease()
+		lease.EndDate = lease.StartDate.AddDate(0, 0, 10)
+		end := lease.EndDate
+		mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+		stored := expectUpdateLease(lease.ID, entities.LeaseActive, true)
+
+		_, err := leaseService.GiveNotice(context.Background(), newTestSession("landlord1", entities.RoleLandlord), lease.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, end, stored.EndDate)
+	})
+
+	t.Run("Someone else", func(t *testing.T) {
+		lease := newActiveLease()
+		mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+
+		_, err := leaseService.GiveNotice(context.Background(), newTestSession("tenant2", entities.RoleUser), lease.ID)
+		assert.ErrorIs(t, err, services.ErrForbidden)
+	})
+
+	t.Run("Lease not signed yet", func(t *testing.T) {
+		lease := newActiveLease()
+		lease.Status = entities.LeasePending
+		mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+
+		_, err := leaseService.GiveNotice(context.Background(), newTestSession("tenant1", entities.RoleTenant), lease.ID)
+		assert.ErrorIs(t, err, services.ErrInvalidTransition)
+	})
+
+	t.Run("Lease changed meanwhile", func(t *testing.T) {
+		lease := newActiveLease()
+		mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+		expectUpdateLease(lease.ID, entities.LeaseActive, false)
+		ended := *newActiveLease()
+		ended.Status = entities.LeaseEnded
+		mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(&ended, nil)
+
+		_, err := leaseService.GiveNotice(context.Background(), newTestSession("tenant1", entities.RoleTenant), lease.ID)
+		var stateErr *services.LeaseStateError
+		if assert.ErrorAs(t, err, &stateErr) {
+			assert.Equal(t, entities.LeaseEnded, stateErr.Status)
+		}
+	})
+}
+
+func TestLeaseService_Renewal(t *testing.T) {
+	cleanup := setupLeases(t)
+	defer cleanup()
+
+	landlord := newTestSession("landlord1", entities.RoleLandlord)
+	tenant := newTestSession("tenant1", entities.RoleTenant)
+
+	t.Run("Landlord offers a renewal", func(t *testing.T) {
+		lease := newActiveLease()
+		end := lease.EndDate
+		mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+		stored := expectUpdateLease(lease.ID, entities.LeaseActive, true)
+
+		_, err := leaseService.OfferRenewal(context.Background(), landlord, lease.ID, 1100, 12)
+		assert.NoError(t, err)
+		if assert.NotNil(t, stored.Renewal) {
+			assert.Equal(t, entities.RenewalOffered, stored.Renewal.Status)
+			assert.Equal(t, 1100.0, stored.Renewal.MonthlyRent)
+			assert.Equal(t, end.AddDate(0, 12, 0), stored.Renewal.EndDate)
+		}
+		// The lease itself is unchanged until the tenant accepts
+		assert.Equal(t, 1000.0, stored.MonthlyRent)
+		assert.Equal(t, end, stored.EndDate)
+	})
+
+	t.Run("Invalid terms", func(t *testing.T) {
+		_, err := leaseService.OfferRenewal(context.Background(), landlord, primitive.NewObjectID(), 0, 12)
+		assert.ErrorIs(t, err, services.ErrInvalidLeaseTerms)
+		_, err = leaseService.OfferRenewal(context.Background(), landlord, primitive.NewObjectID(), 1000, 0)
+		assert.ErrorIs(t, err, services.ErrInvalidLeaseTerms)
+	})
+
+	t.Run("Only the landlord offers renewals", func(t *testing.T) {
+		lease := newActiveLease()
+		mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+
+		_, err := leaseService.OfferRenewal(context.Background(), newTestSession("tenant1", entities.RoleUser), lease.ID, 1100, 12)
+		assert.ErrorIs(t, err, services.ErrForbidden)
+	})
+
+	t.Run("Lease under notice", func(t *testing.T) {
+		lease := newActiveLease()
+		lease.Status = entities.LeaseEnding
+		mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+
+		_, err := leaseService.OfferRenewal(context.Background(), landlord, lease.ID, 1100, 12)
+		assert.ErrorIs(t, err, services.ErrInvalidTransition)
+	})
+
+	for _, accept := range []bool{true, false} {
+		name := "Tenant declines"
+		if accept {
+			name = "Tenant accepts"
+		}
+		t.Run(name, func(t *testing.T) {
+			lease := newActiveLease()
+			end := lease.EndDate
+			lease.Renewal = &entities.RenewalOffer{MonthlyRent: 1100, EndDate: end.AddDate(1, 0, 0), Status: entities.RenewalOffered}
+			escalation := entities.Escalation{Percent: 5, EveryMonths: 12, Since: lease.StartDate}
+			lease.Rules = &entities.RentRules{Escalation: &escalation}
+			mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+			stored := expectUpdateLease(lease.ID, entities.LeaseActive, true)
+
+			_, err := leaseService.AnswerRenewal(context.Background(), tenant, lease.ID, accept)
+			assert.NoError(t, err)
+			assert.NotNil(t, stored.Renewal.AnsweredAt)
+			if accept {
+				assert.Equal(t, entities.RenewalAccepted, stored.Renewal.Status)
+				// The renewed rent, and the escalation of it, start when the current term ends
+				assert.Equal(t, end, stored.Rules.Escalation.Since)
+				assert.Equal(t, lease.StartDate, escalation.Since, "the rules of the lease read are not changed")
+				assert.Equal(t, 1100.0, stored.MonthlyRent)
+				assert.Equal(t, []entities.PastRent{{MonthlyRent: 1000, From: lease.StartDate, Until: end}}, stored.PastRents)
+				assert.Equal(t, end.AddDate(1, 0, 0), stored.EndDate)
+			} else {
+				assert.Equal(t, entities.RenewalDeclined, stored.Renewal.Status)
+				assert.Equal(t, 1000.0, stored.MonthlyRent)
+				assert.Equal(t, end, stored.EndDate)
+			}
+		})
+	}
+
+	t.Run("No open offer", func(t *testing.T) {
+		lease := newActiveLease()
+		lease.Renewal = &entities.RenewalOffer{MonthlyRent: 1100, Status: entities.RenewalDeclined}
+		mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+
+		_, err := leaseService.AnswerRenewal(context.Background(), tenant, lease.ID, true)
+		assert.ErrorIs(t, err, services.ErrNoRenewalOffer)
+	})
+
+	t.Run("Only the tenant answers", func(t *testing.T) {
+		lease := newActiveLease()
+		mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+
+		_, err := leaseService.AnswerRenewal(context.Background(), newTestSession("landlord1", entities.RoleUser), lease.ID, true)
+		assert.ErrorIs(t, err, services.ErrForbidden)
+	})
+}
+
+func TestLeaseService_SetRentRules(t *testing.T) {
+	cleanup := setupLeases(t)
+	defer cleanup()
+
+	landlord := newTestSession("landlord1", entities.RoleLandlord)
+	rules := entities.RentRules{
+		Escalation: &entities.Escalation{Percent: 5, EveryMonths: 12},
+		LateFee:    &entities.LateFee{GraceDays: 4, PerDay: 100},
+	}
+
+	t.Run("Landlord sets the rules", func(t *testing.T) {
+		lease := newActiveLease()
+		mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+		stored := expectUpdateLease(lease.ID, entities.LeaseActive, true)
+
+		_, err := leaseService.SetRentRules(context.Background(), landlord, lease.ID, rules)
+		assert.NoError(t, err)
+		if assert.NotNil(t, stored.Rules) {
+			assert.Equal(t, 5.0, stored.Rules.Escalation.Percent)
+			// The rent is raised counting from the start of the lease
+			assert.Equal(t, lease.StartDate, stored.Rules.Escalation.Since)
+			assert.Equal(t, *rules.LateFee, *stored.Rules.LateFee)
+		}
+		assert.True(t, rules.Escalation.Since.IsZero(), "the rules given are not changed")
+	})
+
+	t.Run("After a renewal", func(t *testing.T) {
+		lease := newActiveLease()
+		renewed := lease.StartDate.AddDate(1, 0, 0)
+		lease.PastRents = []entities.PastRent{{MonthlyRent: 1000, From: lease.StartDate, Until: renewed}}
+		lease.MonthlyRent = 1100
+		mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+		stored := expectUpdateLease(lease.ID, entities.LeaseActive, true)
+
+		_, err := leaseService.SetRentRules(context.Background(), landlord, lease.ID, entities.RentRules{Escalation: rules.Escalation})
+		assert.NoError(t, err)
+		if assert.NotNil(t, stored.Rules) {
+			assert.Equal(t, renewed, stored.Rules.Escalation.Since)
+			assert.Nil(t, stored.Rules.LateFee)
+		}
+	})
+
+	t.Run("No rules remove them", func(t *testing.T) {
+		lease := newActiveLease()
+		lease.Rules = &rules
+		mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+		stored := expectUpdateLease(lease.ID, entities.LeaseActive, true)
+
+		_, err := leaseService.SetRentRules(context.Background(), landlord, lease.ID, entities.RentRules{})
+		assert.NoError(t, err)
+		assert.Nil(t, stored.Rules)
+	})
+
+	invalid := []entities.RentRules{
+		{Escalation: &entities.Escalation{EveryMonths: 12}},
+		{Escalation: &entities.Escalation{Percent: -5, Amount: 100, EveryMonths: 12}},
+		{Escalation: &entities.Escalation{Percent: 5}},
+		{LateFee: &entities.LateFee{GraceDays: 5}},
+		{LateFee: &entities.LateFee{GraceDays: -1, PerDay: 100}},
+		{LateFee: &entities.LateFee{Flat: 500, Max: -1}},
+	}
+	for _, rules := range invalid {
+		_, err := leaseService.SetRentRules(context.Background(), landlord, primitive.NewObjectID(), rules)
+		assert.ErrorIs(t, err, services.ErrInvalidRentRules)
+	}
+
+	t.Run("Only the landlord sets the rules", func(t *testing.T) {
+		lease := newActiveLease()
+		_, err := leaseService.SetRentRules(context.Background(), newTestSession("tenant1", entities.RoleTenant), lease.ID, rules)
+		assert.ErrorIs(t, err, services.ErrForbidden)
+
+		mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+		_, err = leaseService.SetRentRules(context.Background(), newTestSession("landlord2", entities.RoleLandlord), lease.ID, rules)
+		assert.ErrorIs(t, err, services.ErrForbidden)
+	})
+
+	t.Run("Lease over", func(t *testing.T) {
+		lease := newActiveLease()
+		lease.Status = entities.LeaseEnded
+		mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+
+		_, err := leaseService.SetRentRules(context.Background(), landlord, lease.ID, rules)
+		assert.ErrorIs(t, err, services.ErrInvalidTransition)
+	})
+}
+
+func TestLeaseService_EndLeases(t *testing.T) {
+	cleanup := setupLeases(t)
+	defer cleanup()
+
+	now := time.Now()
+	due := newActiveLease()
+	due.EndDate = now.Add(-time.Hour)
+	running := newActiveLease()
+	underNotice := newActiveLease()
+	underNotice.Status = entities.LeaseEnding
+	underNotice.EndDate = now.Add(-time.Hour)
+	changedMeanwhile := newActiveLease()
+	changedMeanwhile.Status = entities.LeaseEnding
+	changedMeanwhile.EndDate = now.Add(-time.Hour)
+
+	mockLeaseRepo.EXPECT().FindLeasesByStatus(gomock.Any(), entities.LeaseActive).Return([]entities.Lease{*due, *running}, nil)
+	mockLeaseRepo.EXPECT().FindLeasesByStatus(gomock.Any(), entities.LeaseEnding).Return([]entities.Lease{*underNotice, *changedMeanwhile}, nil)
+	mockLeaseRepo.EXPECT().UpdateLease(gomock.Any(), gomock.Any(), gomock.Any()).Times(3).
+		DoAndReturn(func(_ context.Context, lease entities.Lease, from entities.LeaseStatus) (bool, error) {
+			assert.NotEqual(t, running.ID, lease.ID)
+			assert.Equal(t, entities.LeaseEnded, lease.Status)
+			return lease.ID != changedMeanwhile.ID, nil
+		})
+	for _, lease := range []*entities.Lease{due, underNotice} {
+		mockPropertyRepo.EXPECT().SetRented(gomock.Any(), lease.PropertyID, false).Return(true, nil)
+		// The property waits for approval before it is shown again
+		mockPropertyRepo.EXPECT().UpdateApprovalStatus(gomock.Any(), lease.PropertyID, false, "moderator").Return(nil)
+	}
+
+	ended, err := leaseService.EndLeases(context.Background(), newTestSession("moderator", entities.RoleModerator), now)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, ended)
+
+	_, err = leaseService.EndLeases(context.Background(), newTestSession("landlord1", entities.RoleLandlord), now)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+}
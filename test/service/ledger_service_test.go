@@ -0,0 +1,371 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/app/services"
+	"rentease/internal/domain/entities"
+	mocks_interfaces "rentease/test/mocks/repository"
+)
+
+var (
+	mockLedgerRepo *mocks_interfaces.MockLedgerRepo
+	ledgerService  *services.LedgerService
+)
+
+func setupLedger(t *testing.T) func() {
+	ctrl := gomock.NewController(t)
+	mockLedgerRepo = mocks_interfaces.NewMockLedgerRepo(ctrl)
+	mockLeaseRepo = mocks_interfaces.NewMockLeaseRepo(ctrl)
+	ledgerService = services.NewLedgerService(mockLedgerRepo, mockLeaseRepo)
+	return func() {
+		ctrl.Finish()
+	}
+}
+
+func newTestDue(lease *entities.Lease, period int, amount float64) entities.RentDue {
+	return entities.RentDue{
+		ID:      primitive.NewObjectID(),
+		LeaseID: lease.ID,
+		Period:  period,
+		DueDate: lease.StartDate.AddDate(0, period-1, 0),
+		Amount:  amount,
+	}
+}
+
+// expectCreateDue expects the due of the lease for the period to be created and returns it as stored.
+func expectCreateDue(t *testing.T, lease *entities.Lease, period int, amount float64) entities.RentDue {
+	due := newTestDue(lease, period, amount)
+	mockLedgerRepo.EXPECT().CreateDue(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, created entities.RentDue) (bool, error) {
+			assert.Equal(t, lease.ID, created.LeaseID)
+			assert.Equal(t, period, created.Period)
+			assert.True(t, due.DueDate.Equal(created.DueDate), "due date of period %d", period)
+			assert.Equal(t, amount, created.Amount)
+			return true, nil
+		})
+	return due
+}
+
+func TestLedgerService_Statement(t *testing.T) {
+	cleanup := setupLedger(t)
+	defer cleanup()
+
+	now := time.Now()
+	lease := newActiveLease()
+	lease.StartDate = lease.StartDate.AddDate(0, -3, -10)
+	lease.EndDate = lease.StartDate.AddDate(0, services.DefaultLeaseMonths, 0)
+	stored := []entities.RentDue{newTestDue(lease, 1, 1000), newTestDue(lease, 2, 1000)}
+
+	mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+	mockLedgerRepo.EXPECT().FindDuesByLease(gomock.Any(), lease.ID).Return(stored, nil)
+	// The dues of the months that started since are generated
+	dues := append(stored, expectCreateDue(t, lease, 3, 1000), expectCreateDue(t, lease, 4, 1000))
+	mockLedgerRepo.EXPECT().FindDuesByLease(gomock.Any(), lease.ID).Return(dues, nil)
+	mockLedgerRepo.EXPECT().FindPaymentsByLease(gomock.Any(), lease.ID).Return([]entities.Payment{
+		{LeaseID: lease.ID, Amount: 1000, PaidOn: dues[0].DueDate, Mode: entities.PaymentCash},
+		{LeaseID: lease.ID, Amount: 1500, PaidOn: dues[1].DueDate.AddDate(0, 0, 5), Mode: entities.PaymentUPI},
+	}, nil)
+
+	statement, err := ledgerService.Statement(context.Background(), newTestSession("tenant1", entities.RoleTenant), lease.ID)
+	require.NoError(t, err)
+	require.Len(t, statement.Lines, 4)
+	assert.Equal(t, 4000.0, statement.TotalDue)
+	assert.Equal(t, 2500.0, statement.TotalPaid)
+	assert.Equal(t, 1500.0, statement.Outstanding)
+
+	// Payments settle the oldest dues first
+	assert.Equal(t, 1000.0, statement.Lines[0].Paid)
+	assert.Equal(t, 0, statement.Lines[0].LateDays)
+	assert.Equal(t, 1000.0, statement.Lines[1].Paid)
+	assert.Equal(t, 5, statement.Lines[1].LateDays)
+	require.NotNil(t, statement.Lines[1].PaidOn)
+	assert.Equal(t, 500.0, statement.Lines[2].Paid)
+	assert.Equal(t, 500.0, statement.Lines[2].Balance)
+	assert.Nil(t, statement.Lines[2].PaidOn)
+	assert.Equal(t, entities.DaysBetween(dues[2].DueDate, now), statement.Lines[2].LateDays)
+	assert.Equal(t, 0.0, statement.Lines[3].Paid)
+	assert.Equal(t, entities.DaysBetween(dues[3].DueDate, now), statement.Lines[3].LateDays)
+}
+
+func TestLedgerService_StatementOfEndedLease(t *testing.T) {
+	cleanup := setupLedger(t)
+	defer cleanup()
+
+	// Notice cut the lease short halfway through February
+	lease := newActiveLease()
+	lease.Status = entities.LeaseEnded
+	lease.StartDate = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	lease.EndDate = time.Date(2025, time.February, 16, 0, 0, 0, 0, time.UTC)
+
+	mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+	mockLedgerRepo.EXPECT().FindDuesByLease(gomock.Any(), lease.ID).Return(nil, nil)
+	// The last month is charged for the 15 of its 28 days that were leased
+	dues := []entities.RentDue{expectCreateDue(t, lease, 1, 1000), expectCreateDue(t, lease, 2, 535.71)}
+	mockLedgerRepo.EXPECT().FindDuesByLease(gomock.Any(), lease.ID).Return(dues, nil)
+	mockLedgerRepo.EXPECT().FindPaymentsByLease(gomock.Any(), lease.ID).Return([]entities.Payment{
+		{LeaseID: lease.ID, Amount: 2000, PaidOn: lease.StartDate, Mode: entities.PaymentBankTransfer},
+	}, nil)
+
+	statement, err := ledgerService.Statement(context.Background(), newTestSession("landlord1", entities.RoleLandlord), lease.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1535.71, statement.TotalDue)
+	// Overpaying shows as a negative balance
+	assert.Equal(t, -464.29, statement.Outstanding)
+	for _, line := range statement.Lines {
+		assert.Equal(t, 0.0, line.Balance)
+		assert.Equal(t, 0, line.LateDays)
+	}
+}
+
+func TestLedgerService_StatementAccess(t *testing.T) {
+	cleanup := setupLedger(t)
+	defer cleanup()
+
+	// Pending leases owe no rent yet
+	pending := newActiveLease()
+	pending.Status = entities.LeasePending
+	mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), pending.ID).Return(pending, nil).Times(3)
+	mockLedgerRepo.EXPECT().FindDuesByLease(gomock.Any(), pending.ID).Return(nil, nil).Times(2)
+	mockLedgerRepo.EXPECT().FindPaymentsByLease(gomock.Any(), pending.ID).Return(nil, nil).Times(2)
+
+	statement, err := ledgerService.Statement(context.Background(), newTestSession("tenant1", entities.RoleTenant), pending.ID)
+	require.NoError(t, err)
+	assert.Empty(t, statement.Lines)
+	assert.Equal(t, 0.0, statement.Outstanding)
+
+	// Moderators can see every statement, other users none
+	_, err = ledgerService.Statement(context.Background(), newTestSession("moderator", entities.RoleModerator), pending.ID)
+	assert.NoError(t, err)
+	_, err = ledgerService.Statement(context.Background(), newTestSession("someone", entities.RoleUser), pending.ID)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+
+	missing := primitive.NewObjectID()
+	mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), missing).Return(nil, nil)
+	_, err = ledgerService.Statement(context.Background(), newTestSession("tenant1", entities.RoleTenant), missing)
+	assert.ErrorIs(t, err, services.ErrLeaseNotFound)
+}
+
+func TestLedgerService_RecordPayment(t *testing.T) {
+	cleanup := setupLedger(t)
+	defer cleanup()
+
+	lease := newActiveLease()
+	landlord := newTestSession("landlord1", entities.RoleLandlord)
+	paidOn := lease.StartDate
+
+	mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+	mockLedgerRepo.EXPECT().FindDuesByLease(gomock.Any(), lease.ID).Return(nil, nil)
+	expectCreateDue(t, lease, 1, 1000)
+	var saved entities.Payment
+	mockLedgerRepo.EXPECT().SavePayment(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, payment entities.Payment) error {
+			saved = payment
+			return nil
+		})
+
+	payment, err := ledgerService.RecordPayment(context.Background(), landlord, lease.ID,
+		entities.Payment{Amount: 1000, PaidOn: paidOn, Mode: entities.PaymentCheque, Reference: "000123"})
+	require.NoError(t, err)
+	assert.Equal(t, saved, payment)
+	assert.False(t, payment.ID.IsZero())
+	assert.Equal(t, lease.ID, payment.LeaseID)
+	assert.Equal(t, "landlord1", payment.RecordedBy)
+	assert.Equal(t, "000123", payment.Reference)
+	assert.True(t, paidOn.Equal(payment.PaidOn))
+
+	tests := []struct {
+		name    string
+		session *entities.Session
+		payment entities.Payment
+		lease   *entities.Lease
+		wantErr error
+	}{
+		{"Nothing paid", landlord, entities.Payment{Amount: 0, Mode: entities.PaymentCash}, nil, services.ErrInvalidPayment},
+		{"Unknown mode", landlord, entities.Payment{Amount: 100, Mode: "barter"}, nil, services.ErrInvalidPayment},
+		{"Paid in the future", landlord, entities.Payment{Amount: 100, Mode: entities.PaymentCash, PaidOn: time.Now().AddDate(0, 0, 2)}, nil, services.ErrInvalidPayment},
+		{"Tenant", newTestSession("tenant1", entities.RoleTenant), entities.Payment{Amount: 100, Mode: entities.PaymentCash}, nil, services.ErrForbidden},
+		{"Another landlord", newTestSession("landlord2", entities.RoleLandlord), entities.Payment{Amount: 100, Mode: entities.PaymentCash}, lease, services.ErrForbidden},
+		{"Unsigned lease", landlord, entities.Payment{Amount: 100, Mode: entities.PaymentCash}, &entities.Lease{ID: lease.ID, LandlordName: "landlord1", Status: entities.LeaseCancelled}, services.ErrInvalidTransition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.lease != nil {
+				mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(tt.lease, nil)
+			}
+			_, err := ledgerService.RecordPayment(context.Background(), tt.session, lease.ID, tt.payment)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestLedgerService_StatementUnderRentRules(t *testing.T) {
+	cleanup := setupLedger(t)
+	defer cleanup()
+
+	lease := newActiveLease()
+	lease.Status = entities.LeaseEnded
+	lease.StartDate = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	lease.EndDate = time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC)
+	lease.Rules = &entities.RentRules{
+		Escalation: &entities.Escalation{Percent: 5, EveryMonths: 2, Since: lease.StartDate},
+		LateFee:    &entities.LateFee{GraceDays: 4, PerDay: 100, Max: 1000},
+	}
+
+	mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+	mockLedgerRepo.EXPECT().FindDuesByLease(gomock.Any(), lease.ID).Return(nil, nil)
+	// The rent is raised 5% from the third month
+	dues := []entities.RentDue{
+		expectCreateDue(t, lease, 1, 1000),
+		expectCreateDue(t, lease, 2, 1000),
+		expectCreateDue(t, lease, 3, 1050),
+		expectCreateDue(t, lease, 4, 1050),
+	}
+	mockLedgerRepo.EXPECT().FindDuesByLease(gomock.Any(), lease.ID).Return(dues, nil)
+	mockLedgerRepo.EXPECT().FindPaymentsByLease(gomock.Any(), lease.ID).Return([]entities.Payment{
+		{LeaseID: lease.ID, Amount: 1000, PaidOn: dues[0].DueDate, Mode: entities.PaymentUPI},
+		{LeaseID: lease.ID, Amount: 1000, PaidOn: dues[1].DueDate.AddDate(0, 0, 11), Mode: entities.PaymentUPI},
+		{LeaseID: lease.ID, Amount: 2100, PaidOn: dues[2].DueDate.AddDate(0, 0, 19), Mode: entities.PaymentUPI},
+	}, nil)
+
+	statement, err := ledgerService.Statement(context.Background(), newTestSession("tenant1", entities.RoleTenant), lease.ID)
+	require.NoError(t, err)
+	require.Len(t, statement.Lines, 4)
+	assert.Equal(t, 4100.0, statement.TotalDue)
+	assert.Equal(t, 0.0, statement.Lines[0].LateFee)
+	// 11 days late is 7 days after the grace days
+	assert.Equal(t, 700.0, statement.Lines[1].LateFee)
+	// 19 days late is capped
+	assert.Equal(t, 1000.0, statement.Lines[2].LateFee)
+	// Paid in advance by the rest of the last payment
+	assert.Equal(t, 0.0, statement.Lines[3].LateFee)
+	assert.Equal(t, 1700.0, statement.LateFees)
+	assert.Equal(t, 1700.0, statement.Outstanding)
+}
+
+func TestLedgerService_StatementAfterRenewal(t *testing.T) {
+	cleanup := setupLedger(t)
+	defer cleanup()
+
+	// Renewed in February at a higher rent from May, when the first term ends
+	lease := newActiveLease()
+	lease.Status = entities.LeaseEnded
+	lease.StartDate = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	renewed := time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC)
+	lease.EndDate = time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC)
+	lease.MonthlyRent = 1200
+	lease.PastRents = []entities.PastRent{{MonthlyRent: 1000, From: lease.StartDate, Until: renewed}}
+	answered := time.Date(2025, time.February, 10, 0, 0, 0, 0, time.UTC)
+	lease.Renewal = &entities.RenewalOffer{MonthlyRent: 1200, EndDate: lease.EndDate, Status: entities.RenewalAccepted, AnsweredAt: &answered}
+	lease.Rules = &entities.RentRules{Escalation: &entities.Escalation{Percent: 5, EveryMonths: 2, Since: renewed}}
+
+	mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+	mockLedgerRepo.EXPECT().FindDuesByLease(gomock.Any(), lease.ID).Return(nil, nil)
+	// The first term is charged at its own rent, raised from its start; the renewed rent is raised from May
+	dues := []entities.RentDue{
+		expectCreateDue(t, lease, 1, 1000),
+		expectCreateDue(t, lease, 2, 1000),
+		expectCreateDue(t, lease, 3, 1050),
+		expectCreateDue(t, lease, 4, 1050),
+		expectCreateDue(t, lease, 5, 1200),
+		expectCreateDue(t, lease, 6, 1200),
+		expectCreateDue(t, lease, 7, 1260),
+		expectCreateDue(t, lease, 8, 1260),
+	}
+	mockLedgerRepo.EXPECT().FindDuesByLease(gomock.Any(), lease.ID).Return(dues, nil)
+	mockLedgerRepo.EXPECT().FindPaymentsByLease(gomock.Any(), lease.ID).Return(nil, nil)
+
+	statement, err := ledgerService.Statement(context.Background(), newTestSession("tenant1", entities.RoleTenant), lease.ID)
+	require.NoError(t, err)
+	require.Len(t, statement.Lines, 8)
+	assert.Equal(t, 9020.0, statement.TotalDue)
+}
+
+func TestLedgerService_Schedule(t *testing.T) {
+	cleanup := setupLedger(t)
+	defer cleanup()
+
+	lease := newActiveLease()
+	tenant := newTestSession("tenant1", entities.RoleTenant)
+	rules := &entities.RentRules{
+		Escalation: &entities.Escalation{Percent: 10, Amount: 5, EveryMonths: 1},
+		LateFee:    &entities.LateFee{GraceDays: 5, Flat: 50, PerDay: 10},
+	}
+
+	// A dry run under other rules; the first month is already due
+	mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+	schedule, err := ledgerService.Schedule(context.Background(), newTestSession("landlord1", entities.RoleLandlord), lease.ID, rules, 3)
+	require.NoError(t, err)
+	assert.Nil(t, lease.Rules, "the lease is not changed")
+	assert.True(t, lease.StartDate.Equal(schedule.Rules.Escalation.Since))
+	require.Len(t, schedule.Dues, 3)
+	for i, want := range []struct {
+		amount, increase float64
+	}{{1105, 105}, {1220.5, 115.5}, {1347.55, 127.05}} {
+		due := schedule.Dues[i]
+		assert.Equal(t, i+2, due.Period)
+		assert.True(t, lease.StartDate.AddDate(0, i+1, 0).Equal(due.DueDate))
+		assert.Equal(t, want.amount, due.Amount, "month %d", due.Period)
+		assert.Equal(t, want.increase, due.Increase, "month %d", due.Period)
+		require.NotNil(t, due.LateFrom)
+		assert.True(t, due.DueDate.AddDate(0, 0, 6).Equal(*due.LateFrom))
+		assert.Equal(t, 60.0, due.LateFee)
+	}
+
+	// Under the rules of the lease, with none set the rent stays as it is
+	mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+	schedule, err = ledgerService.Schedule(context.Background(), tenant, lease.ID, nil, services.DefaultScheduleMonths)
+	require.NoError(t, err)
+	require.Len(t, schedule.Dues, services.DefaultLeaseMonths-1)
+	for _, due := range schedule.Dues {
+		assert.Equal(t, 1000.0, due.Amount)
+		assert.Equal(t, 0.0, due.Increase)
+		assert.Nil(t, due.LateFrom)
+	}
+
+	// No rent is due under a pending lease yet, so its schedule starts with the first month
+	pending := newActiveLease()
+	pending.Status = entities.LeasePending
+	mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), pending.ID).Return(pending, nil)
+	schedule, err = ledgerService.Schedule(context.Background(), tenant, pending.ID, nil, 1)
+	require.NoError(t, err)
+	require.Len(t, schedule.Dues, 1)
+	assert.Equal(t, 1, schedule.Dues[0].Period)
+
+	// A renewal accepted months before the term ends leaves the rest of the term at the current rent
+	renewed := newActiveLease()
+	renewed.PastRents = []entities.PastRent{{MonthlyRent: 1000, From: renewed.StartDate, Until: renewed.EndDate}}
+	renewed.MonthlyRent = 1100
+	renewed.EndDate = renewed.EndDate.AddDate(1, 0, 0)
+	mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), renewed.ID).Return(renewed, nil)
+	schedule, err = ledgerService.Schedule(context.Background(), tenant, renewed.ID, nil, services.DefaultLeaseMonths)
+	require.NoError(t, err)
+	require.Len(t, schedule.Dues, services.DefaultLeaseMonths)
+	for _, due := range schedule.Dues[:services.DefaultLeaseMonths-1] {
+		assert.Equal(t, 1000.0, due.Amount, "month %d", due.Period)
+	}
+	assert.Equal(t, 1100.0, schedule.Dues[services.DefaultLeaseMonths-1].Amount)
+	assert.Equal(t, 100.0, schedule.Dues[services.DefaultLeaseMonths-1].Increase)
+
+	ended := newActiveLease()
+	ended.Status = entities.LeaseEnded
+	mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), ended.ID).Return(ended, nil)
+	schedule, err = ledgerService.Schedule(context.Background(), tenant, ended.ID, nil, 12)
+	require.NoError(t, err)
+	assert.Empty(t, schedule.Dues)
+
+	_, err = ledgerService.Schedule(context.Background(), tenant, lease.ID, nil, 0)
+	assert.ErrorIs(t, err, services.ErrInvalidRentRules)
+	mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+	_, err = ledgerService.Schedule(context.Background(), tenant, lease.ID, &entities.RentRules{LateFee: &entities.LateFee{GraceDays: 5}}, 12)
+	assert.ErrorIs(t, err, services.ErrInvalidRentRules)
+	mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+	_, err = ledgerService.Schedule(context.Background(), newTestSession("tenant2", entities.RoleTenant), lease.ID, nil, 12)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+}
@@ -0,0 +1,264 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/app/services"
+	"rentease/internal/domain/entities"
+	mocks_interfaces "rentease/test/mocks/repository"
+)
+
+var (
+	mockMaintenanceRepo *mocks_interfaces.MockMaintenanceRepo
+	maintenanceService  *services.MaintenanceService
+)
+
+func setupMaintenance(t *testing.T) func() {
+	ctrl := gomock.NewController(t)
+	mockMaintenanceRepo = mocks_interfaces.NewMockMaintenanceRepo(ctrl)
+	mockLeaseRepo = mocks_interfaces.NewMockLeaseRepo(ctrl)
+	maintenanceService = services.NewMaintenanceService(mockMaintenanceRepo, mockLeaseRepo)
+	return func() {
+		ctrl.Finish()
+	}
+}
+
+// newTicket returns a ticket of tenant1 and landlord1 in the status, reported age ago with the priority.
+func newTicket(status entities.TicketStatus, priority entities.MaintenancePriority, age time.Duration) *entities.MaintenanceTicket {
+	reported := time.Now().Add(-age)
+	return &entities.MaintenanceTicket{
+		ID:           primitive.NewObjectID(),
+		LeaseID:      primitive.NewObjectID(),
+		TenantName:   "tenant1",
+		LandlordName: "landlord1",
+		Category:     entities.CategoryPlumbing,
+		Description:  "The kitchen tap is leaking",
+		Priority:     priority,
+		Status:       status,
+		Comments:     []entities.TicketComment{},
+		CreatedAt:    reported,
+		UpdatedAt:    reported,
+		DueBy:        reported.Add(priority.ResolveWithin()),
+	}
+}
+
+func TestMaintenanceService_ReportIssue(t *testing.T) {
+	cleanup := setupMaintenance(t)
+	defer cleanup()
+
+	lease := newActiveLease()
+	tenant := newTestSession("tenant1", entities.RoleTenant)
+
+	mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(lease, nil)
+	var saved entities.MaintenanceTicket
+	mockMaintenanceRepo.EXPECT().SaveTicket(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, ticket entities.MaintenanceTicket) error {
+			saved = ticket
+			return nil
+		})
+	ticket, err := maintenanceService.ReportIssue(context.Background(), tenant, lease.ID, entities.CategoryPlumbing, "", "  The kitchen tap is leaking ")
+	require.NoError(t, err)
+	assert.Equal(t, saved, ticket)
+	assert.False(t, ticket.ID.IsZero())
+	assert.Equal(t, lease.PropertyID, ticket.PropertyID)
+	assert.Equal(t, "landlord1", ticket.LandlordName)
+	assert.Equal(t, "The kitchen tap is leaking", ticket.Description)
+	assert.Equal(t, entities.PriorityMedium, ticket.Priority)
+	assert.Equal(t, entities.TicketOpen, ticket.Status)
+	assert.Equal(t, 7*24*time.Hour, ticket.DueBy.Sub(ticket.CreatedAt))
+
+	ended := newActiveLease()
+	ended.Status = entities.LeaseEnded
+	tests := []struct {
+		name        string
+		session     *entities.Session
+		category    entities.MaintenanceCategory
+		priority    entities.MaintenancePriority
+		description string
+		lease       *entities.Lease
+		wantErr     error
+	}{
+		{"Unknown category", tenant, "roof", entities.PriorityHigh, "Leaks", nil, services.ErrInvalidTicket},
+		{"Unknown priority", tenant, entities.CategoryOther, "asap", "Leaks", nil, services.ErrInvalidTicket},
+		{"No description", tenant, entities.CategoryOther, entities.PriorityLow, "  ", nil, services.ErrInvalidTicket},
+		{"Landlord", newTestSession("landlord1", entities.RoleLandlord), entities.CategoryOther, entities.PriorityLow, "Leaks", nil, services.ErrForbidden},
+		{"Another tenant", newTestSession("tenant2", entities.RoleTenant), entities.CategoryOther, entities.PriorityLow, "Leaks", lease, services.ErrForbidden},
+		{"Ended lease", tenant, entities.CategoryOther, entities.PriorityLow, "Leaks", ended, services.ErrInvalidTransition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.lease != nil {
+				mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(tt.lease, nil)
+			}
+			_, err := maintenanceService.ReportIssue(context.Background(), tt.session, lease.ID, tt.category, tt.priority, tt.description)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+
+	mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(nil, nil)
+	_, err = maintenanceService.ReportIssue(context.Background(), tenant, lease.ID, entities.CategoryOther, entities.PriorityLow, "Leaks")
+	assert.ErrorIs(t, err, services.ErrLeaseNotFound)
+}
+
+func TestMaintenanceService_Ticket(t *testing.T) {
+	cleanup := setupMaintenance(t)
+	defer cleanup()
+
+	overdue := newTicket(entities.TicketAcknowledged, entities.PriorityUrgent, 2*24*time.Hour)
+	mockMaintenanceRepo.EXPECT().FindTicketByID(gomock.Any(), overdue.ID).Return(overdue, nil)
+	ticket, err := maintenanceService.Ticket(context.Background(), newTestSession("tenant1", entities.RoleTenant), overdue.ID)
+	require.NoError(t, err)
+	assert.True(t, ticket.Overdue)
+
+	// Resolved tickets are no longer overdue
+	resolved := newTicket(entities.TicketResolved, entities.PriorityUrgent, 2*24*time.Hour)
+	mockMaintenanceRepo.EXPECT().FindTicketByID(gomock.Any(), resolved.ID).Return(resolved, nil)
+	ticket, err = maintenanceService.Ticket(context.Background(), newTestSession("admin", entities.RoleAdmin), resolved.ID)
+	require.NoError(t, err)
+	assert.False(t, ticket.Overdue)
+
+	mockMaintenanceRepo.EXPECT().FindTicketByID(gomock.Any(), overdue.ID).Return(overdue, nil)
+	_, err = maintenanceService.Ticket(context.Background(), newTestSession("tenant2", entities.RoleTenant), overdue.ID)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+
+	mockMaintenanceRepo.EXPECT().FindTicketByID(gomock.Any(), overdue.ID).Return(nil, nil)
+	_, err = maintenanceService.Ticket(context.Background(), newTestSession("tenant1", entities.RoleTenant), overdue.ID)
+	assert.ErrorIs(t, err, services.ErrTicketNotFound)
+}
+
+func TestMaintenanceService_MaintenanceQueue(t *testing.T) {
+	cleanup := setupMaintenance(t)
+	defer cleanup()
+
+	low := newTicket(entities.TicketOpen, entities.PriorityLow, time.Hour)
+	high := newTicket(entities.TicketInProgress, entities.PriorityHigh, 2*time.Hour)
+	overdue := newTicket(entities.TicketOpen, entities.PriorityMedium, 8*24*time.Hour)
+	resolved := newTicket(entities.TicketResolved, entities.PriorityUrgent, 3*time.Hour)
+	closed := newTicket(entities.TicketClosed, entities.PriorityUrgent, 30*24*time.Hour)
+	mockMaintenanceRepo.EXPECT().FindTicketsByLandlord(gomock.Any(), "landlord1").
+		Return([]entities.MaintenanceTicket{*closed, *overdue, *high, *resolved, *low}, nil)
+
+	queue, err := maintenanceService.MaintenanceQueue(context.Background(), newTestSession("landlord1", entities.RoleLandlord))
+	require.NoError(t, err)
+	require.Len(t, queue, 4)
+	assert.Equal(t, overdue.ID, queue[0].ID)
+	assert.True(t, queue[0].Overdue)
+	assert.Equal(t, high.ID, queue[1].ID)
+	assert.Equal(t, low.ID, queue[2].ID)
+	assert.Equal(t, resolved.ID, queue[3].ID)
+	assert.False(t, queue[3].Overdue)
+
+	_, err = maintenanceService.MaintenanceQueue(context.Background(), newTestSession("tenant1", entities.RoleTenant))
+	assert.ErrorIs(t, err, services.ErrForbidden)
+}
+
+func TestMaintenanceService_UpdateTicketStatus(t *testing.T) {
+	cleanup := setupMaintenance(t)
+	defer cleanup()
+
+	landlord := newTestSession("landlord1", entities.RoleLandlord)
+	tenant := newTestSession("tenant1", entities.RoleTenant)
+
+	// The landlord resolves an open ticket straight away, with a note
+	open := newTicket(entities.TicketOpen, entities.PriorityHigh, time.Hour)
+	resolved := *open
+	resolved.Status = entities.TicketResolved
+	mockMaintenanceRepo.EXPECT().FindTicketByID(gomock.Any(), open.ID).Return(open, nil)
+	mockMaintenanceRepo.EXPECT().UpdateTicketStatus(gomock.Any(), open.ID, entities.TicketOpen, entities.TicketResolved, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ primitive.ObjectID, _, _ entities.TicketStatus, _ time.Time, note *entities.TicketComment) (bool, error) {
+			require.NotNil(t, note)
+			assert.Equal(t, "landlord1", note.Author)
+			assert.Equal(t, "Replaced the washer", note.Text)
+			return true, nil
+		})
+	mockMaintenanceRepo.EXPECT().FindTicketByID(gomock.Any(), open.ID).Return(&resolved, nil)
+	ticket, err := maintenanceService.UpdateTicketStatus(context.Background(), landlord, open.ID, entities.TicketResolved, " Replaced the washer ")
+	require.NoError(t, err)
+	assert.Equal(t, entities.TicketResolved, ticket.Status)
+
+	// The tenant closes it, without a note
+	mockMaintenanceRepo.EXPECT().FindTicketByID(gomock.Any(), open.ID).Return(&resolved, nil)
+	mockMaintenanceRepo.EXPECT().UpdateTicketStatus(gomock.Any(), open.ID, entities.TicketResolved, entities.TicketClosed, gomock.Any(), nil).Return(true, nil)
+	mockMaintenanceRepo.EXPECT().FindTicketByID(gomock.Any(), open.ID).Return(&entities.MaintenanceTicket{ID: open.ID, Status: entities.TicketClosed}, nil)
+	ticket, err = maintenanceService.UpdateTicketStatus(context.Background(), tenant, open.ID, entities.TicketClosed, "")
+	require.NoError(t, err)
+	assert.Equal(t, entities.TicketClosed, ticket.Status)
+
+	// The tenant reopened it before the landlord acknowledged it
+	mockMaintenanceRepo.EXPECT().FindTicketByID(gomock.Any(), open.ID).Return(open, nil)
+	mockMaintenanceRepo.EXPECT().UpdateTicketStatus(gomock.Any(), open.ID, entities.TicketOpen, entities.TicketAcknowledged, gomock.Any(), nil).Return(false, nil)
+	mockMaintenanceRepo.EXPECT().FindTicketByID(gomock.Any(), open.ID).Return(&resolved, nil)
+	_, err = maintenanceService.UpdateTicketStatus(context.Background(), landlord, open.ID, entities.TicketAcknowledged, "")
+	var stateErr *services.TicketStateError
+	require.ErrorAs(t, err, &stateErr)
+	assert.Equal(t, entities.TicketResolved, stateErr.Status)
+
+	tests := []struct {
+		name    string
+		session *entities.Session
+		ticket  *entities.MaintenanceTicket
+		to      entities.TicketStatus
+		wantErr error
+	}{
+		{"Unknown status", landlord, nil, "fixed", services.ErrInvalidTicket},
+		{"Landlord closes", landlord, &resolved, entities.TicketClosed, services.ErrForbidden},
+		{"Tenant resolves", tenant, open, entities.TicketResolved, services.ErrForbidden},
+		{"Outsider", newTestSession("landlord2", entities.RoleLandlord), open, entities.TicketAcknowledged, services.ErrForbidden},
+		{"Landlord moves back", landlord, newTicket(entities.TicketInProgress, entities.PriorityLow, time.Hour), entities.TicketAcknowledged, services.ErrInvalidTransition},
+		{"Tenant closes unresolved", tenant, open, entities.TicketClosed, services.ErrInvalidTransition},
+		{"Tenant reopens open", tenant, open, entities.TicketOpen, services.ErrInvalidTransition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.ticket != nil {
+				mockMaintenanceRepo.EXPECT().FindTicketByID(gomock.Any(), open.ID).Return(tt.ticket, nil)
+			}
+			_, err := maintenanceService.UpdateTicketStatus(context.Background(), tt.session, open.ID, tt.to, "")
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestMaintenanceService_AddComment(t *testing.T) {
+	cleanup := setupMaintenance(t)
+	defer cleanup()
+
+	open := newTicket(entities.TicketOpen, entities.PriorityLow, time.Hour)
+	mockMaintenanceRepo.EXPECT().FindTicketByID(gomock.Any(), open.ID).Return(open, nil)
+	mockMaintenanceRepo.EXPECT().AddTicketComment(gomock.Any(), open.ID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ primitive.ObjectID, comment entities.TicketComment) (bool, error) {
+			assert.Equal(t, "landlord1", comment.Author)
+			assert.Equal(t, "The plumber comes on Monday", comment.Text)
+			return true, nil
+		})
+	mockMaintenanceRepo.EXPECT().FindTicketByID(gomock.Any(), open.ID).Return(open, nil)
+	_, err := maintenanceService.AddComment(context.Background(), newTestSession("landlord1", entities.RoleLandlord), open.ID, "The plumber comes on Monday")
+	require.NoError(t, err)
+
+	// Closed meanwhile
+	closed := newTicket(entities.TicketClosed, entities.PriorityLow, time.Hour)
+	mockMaintenanceRepo.EXPECT().FindTicketByID(gomock.Any(), open.ID).Return(open, nil)
+	mockMaintenanceRepo.EXPECT().AddTicketComment(gomock.Any(), open.ID, gomock.Any()).Return(false, nil)
+	mockMaintenanceRepo.EXPECT().FindTicketByID(gomock.Any(), open.ID).Return(closed, nil)
+	_, err = maintenanceService.AddComment(context.Background(), newTestSession("tenant1", entities.RoleTenant), open.ID, "Any news?")
+	var stateErr *services.TicketStateError
+	require.ErrorAs(t, err, &stateErr)
+	assert.Equal(t, entities.TicketClosed, stateErr.Status)
+
+	mockMaintenanceRepo.EXPECT().FindTicketByID(gomock.Any(), open.ID).Return(closed, nil)
+	_, err = maintenanceService.AddComment(context.Background(), newTestSession("tenant1", entities.RoleTenant), open.ID, "Thanks")
+	assert.ErrorIs(t, err, services.ErrInvalidTransition)
+
+	mockMaintenanceRepo.EXPECT().FindTicketByID(gomock.Any(), open.ID).Return(open, nil)
+	_, err = maintenanceService.AddComment(context.Background(), newTestSession("tenant2", entities.RoleTenant), open.ID, "Hello")
+	assert.ErrorIs(t, err, services.ErrForbidden)
+
+	_, err = maintenanceService.AddComment(context.Background(), newTestSession("tenant1", entities.RoleTenant), open.ID, " ")
+	assert.ErrorIs(t, err, services.ErrInvalidTicket)
+}
@@ -0,0 +1,191 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/app/services"
+	"rentease/internal/domain/entities"
+	mocks_interfaces "rentease/test/mocks/repository"
+)
+
+var (
+	mockMessageRepo *mocks_interfaces.MockMessageRepo
+	messageService  *services.MessageService
+)
+
+func setupMessages(t *testing.T) func() {
+	ctrl := gomock.NewController(t)
+	mockMessageRepo = mocks_interfaces.NewMockMessageRepo(ctrl)
+	mockPropertyRepo = mocks_interfaces.NewMockPropertyRepo(ctrl)
+	mockRentRequestRepo = mocks_interfaces.NewMockRequestRepo(ctrl)
+	messageService = services.NewMessageService(mockMessageRepo, mockPropertyRepo, mockRentRequestRepo)
+	return func() {
+		ctrl.Finish()
+	}
+}
+
+// newThread returns the thread of tenant1 with landlord1 about the property, without messages.
+func newThread(propertyID primitive.ObjectID) *entities.Thread {
+	return &entities.Thread{
+		ID:           primitive.NewObjectID(),
+		PropertyID:   propertyID,
+		TenantName:   "tenant1",
+		LandlordName: "landlord1",
+		Messages:     []entities.Message{},
+	}
+}
+
+func TestMessageService_ContactLandlord(t *testing.T) {
+	cleanup := setupMessages(t)
+	defer cleanup()
+
+	property := newVisitProperty()
+	tenant := newTestSession("tenant1", entities.RoleTenant)
+	thread := newThread(property.ID)
+
+	// The first message starts the thread
+	mockPropertyRepo.EXPECT().FindByID(gomock.Any(), property.ID).Return(property, nil)
+	gomock.InOrder(
+		mockMessageRepo.EXPECT().FindThread(gomock.Any(), property.ID, "tenant1").Return(nil, nil),
+		mockMessageRepo.EXPECT().CreateThread(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, created entities.Thread) (bool, error) {
+				assert.Equal(t, "tenant1", created.TenantName)
+				assert.Equal(t, "landlord1", created.LandlordName)
+				thread.ID = created.ID
+				return true, nil
+			}),
+		mockMessageRepo.EXPECT().FindThread(gomock.Any(), property.ID, "tenant1").Return(thread, nil),
+	)
+	mockMessageRepo.EXPECT().AddMessage(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, id primitive.ObjectID, message entities.Message) (bool, error) {
+			assert.Equal(t, thread.ID, id)
+			assert.Equal(t, "tenant1", message.Sender)
+			assert.Equal(t, "Is it still available?", message.Text)
+			return true, nil
+		})
+	mockMessageRepo.EXPECT().FindThreadByID(gomock.Any(), gomock.Any()).Return(thread, nil)
+	got, err := messageService.ContactLandlord(context.Background(), tenant, property.ID, "  Is it still available? ")
+	require.NoError(t, err)
+	assert.Equal(t, thread.ID, got.ID)
+
+	tests := []struct {
+		name     string
+		session  *entities.Session
+		property *entities.Property
+		text     string
+		expected error
+	}{
+		{name: "Not logged in", text: "Hello", expected: services.ErrNotLoggedIn},
+		{name: "Landlord role", session: newTestSession("landlord2", entities.RoleLandlord), text: "Hello", expected: services.ErrForbidden},
+		{name: "Empty", session: tenant, text: "   ", expected: services.ErrInvalidMessage},
+		{name: "Too long", session: tenant, text: strings.Repeat("a", services.MaxMessageLength+1), expected: services.ErrInvalidMessage},
+		{name: "Property not found", session: tenant, text: "Hello", expected: services.ErrPropertyNotFound},
+		{name: "Own property", session: newTestSession("landlord1", entities.RoleUser), property: property, text: "Hello", expected: services.ErrForbidden},
+		{name: "Not approved", session: tenant, property: &entities.Property{ID: property.ID, LandlordUsername: "landlord1"}, text: "Hello", expected: services.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.expected == services.ErrPropertyNotFound || tt.property != nil {
+				mockPropertyRepo.EXPECT().FindByID(gomock.Any(), property.ID).Return(tt.property, nil)
+			}
+			_, err := messageService.ContactLandlord(context.Background(), tt.session, property.ID, tt.text)
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
+
+func TestMessageService_MessageAboutRequest(t *testing.T) {
+	cleanup := setupMessages(t)
+	defer cleanup()
+
+	request := &entities.Request{ID: primitive.NewObjectID(), PropertyID: primitive.NewObjectID(), TenantName: "tenant1", LandlordName: "landlord1"}
+	thread := newThread(request.PropertyID)
+
+	// The landlord answers in the thread the tenant already has about the property
+	mockRentRequestRepo.EXPECT().FindRequestByID(gomock.Any(), request.ID).Return(request, nil)
+	mockMessageRepo.EXPECT().FindThread(gomock.Any(), request.PropertyID, "tenant1").Return(thread, nil)
+	mockMessageRepo.EXPECT().AddMessage(gomock.Any(), thread.ID, gomock.Any()).Return(true, nil)
+	answered := *thread
+	answered.LandlordUnread = 1
+	answered.TenantUnread = 1
+	mockMessageRepo.EXPECT().FindThreadByID(gomock.Any(), thread.ID).Return(&answered, nil)
+	got, err := messageService.MessageAboutRequest(context.Background(), newTestSession("landlord1", entities.RoleLandlord), request.ID, "When can you move in?")
+	require.NoError(t, err)
+	// Answering does not mark the tenant's message read, which the landlord has not opened
+	assert.Equal(t, 1, got.LandlordUnread)
+
+	mockRentRequestRepo.EXPECT().FindRequestByID(gomock.Any(), request.ID).Return(request, nil)
+	_, err = messageService.MessageAboutRequest(context.Background(), newTestSession("tenant2", entities.RoleTenant), request.ID, "Hello")
+	assert.ErrorIs(t, err, services.ErrForbidden)
+
+	missing := primitive.NewObjectID()
+	mockRentRequestRepo.EXPECT().FindRequestByID(gomock.Any(), missing).Return(nil, nil)
+	_, err = messageService.MessageAboutRequest(context.Background(), newTestSession("tenant1", entities.RoleTenant), missing, "Hello")
+	assert.ErrorIs(t, err, services.ErrRequestNotFound)
+}
+
+func TestMessageService_SendAndReadThread(t *testing.T) {
+	cleanup := setupMessages(t)
+	defer cleanup()
+
+	thread := newThread(primitive.NewObjectID())
+	thread.Messages = []entities.Message{{Sender: "tenant1", Text: "Hello", SentAt: time.Now()}}
+	thread.LandlordUnread = 1
+	landlord := newTestSession("landlord1", entities.RoleLandlord)
+
+	// Reading gives the thread with its unread count and marks it read
+	mockMessageRepo.EXPECT().FindThreadByID(gomock.Any(), thread.ID).Return(thread, nil)
+	mockMessageRepo.EXPECT().MarkThreadRead(gomock.Any(), thread.ID, "landlord1").Return(true, nil)
+	read, err := messageService.ReadThread(context.Background(), landlord, thread.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, read.UnreadFor("landlord1"))
+
+	// Nothing is marked when there is nothing new
+	read.LandlordUnread = 0
+	mockMessageRepo.EXPECT().FindThreadByID(gomock.Any(), thread.ID).Return(&read, nil)
+	_, err = messageService.ReadThread(context.Background(), landlord, thread.ID)
+	require.NoError(t, err)
+
+	mockMessageRepo.EXPECT().FindThreadByID(gomock.Any(), thread.ID).Return(thread, nil)
+	_, err = messageService.ReadThread(context.Background(), newTestSession("tenant2", entities.RoleTenant), thread.ID)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+
+	mockMessageRepo.EXPECT().FindThreadByID(gomock.Any(), thread.ID).Return(thread, nil)
+	mockMessageRepo.EXPECT().AddMessage(gomock.Any(), thread.ID, gomock.Any()).Return(true, nil)
+	mockMessageRepo.EXPECT().FindThreadByID(gomock.Any(), thread.ID).Return(thread, nil)
+	_, err = messageService.SendMessage(context.Background(), landlord, thread.ID, "Hi there")
+	require.NoError(t, err)
+
+	missing := primitive.NewObjectID()
+	mockMessageRepo.EXPECT().FindThreadByID(gomock.Any(), missing).Return(nil, nil)
+	_, err = messageService.SendMessage(context.Background(), landlord, missing, "Hi there")
+	assert.ErrorIs(t, err, services.ErrThreadNotFound)
+}
+
+func TestMessageService_UnreadMessages(t *testing.T) {
+	cleanup := setupMessages(t)
+	defer cleanup()
+
+	asTenant := newThread(primitive.NewObjectID())
+	asTenant.TenantUnread = 2
+	asTenant.LandlordUnread = 5
+	asLandlord := newThread(primitive.NewObjectID())
+	asLandlord.TenantName = "tenant2"
+	asLandlord.LandlordName = "tenant1"
+	asLandlord.LandlordUnread = 3
+
+	mockMessageRepo.EXPECT().FindThreadsByUser(gomock.Any(), "tenant1").Return([]entities.Thread{*asTenant, *asLandlord}, nil)
+	unread, err := messageService.UnreadMessages(context.Background(), newTestSession("tenant1", entities.RoleUser))
+	require.NoError(t, err)
+	assert.Equal(t, entities.UnreadMessages{AsTenant: 2, AsLandlord: 3}, unread)
+
+	_, err = messageService.UnreadMessages(context.Background(), nil)
+	assert.ErrorIs(t, err, services.ErrNotLoggedIn)
+}
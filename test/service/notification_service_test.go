@@ -0,0 +1,218 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/app/eventbus"
+	"rentease/internal/app/services"
+	"rentease/internal/domain/entities"
+	mocks_interfaces "rentease/test/mocks/repository"
+)
+
+var (
+	mockNotificationRepo *mocks_interfaces.MockNotificationRepo
+	mockEmailNotifier    *mocks_interfaces.MockEmailNotifier
+	notificationService  *services.NotificationService
+	// eventBus delivers the events of the services to the notification and email subscribers
+	eventBus *eventbus.Bus
+)
+
+func setupNotifications(t *testing.T) func() {
+	ctrl := gomock.NewController(t)
+	mockNotificationRepo = mocks_interfaces.NewMockNotificationRepo(ctrl)
+	mockEmailNotifier = mocks_interfaces.NewMockEmailNotifier(ctrl)
+	mockPropertyRepo = mocks_interfaces.NewMockPropertyRepo(ctrl)
+	mockRentRequestRepo = mocks_interfaces.NewMockRequestRepo(ctrl)
+	mockUserRepo = mocks_interfaces.NewMockUserRepo(ctrl)
+	mockRefreshTokenRepo = mocks_interfaces.NewMockRefreshTokenRepo(ctrl)
+	notificationService = services.NewNotificationService(mockNotificationRepo)
+	eventBus = eventbus.New()
+	services.SubscribeNotifications(eventBus, mockNotificationRepo)
+	services.SubscribeEmails(eventBus, mockEmailNotifier)
+	return func() {
+		ctrl.Finish()
+	}
+}
+
+// ignoreEvents returns a publisher that accepts any event, for tests about other things than what the
+// services publish.
+func ignoreEvents(ctrl *gomock.Controller) *mocks_interfaces.MockEventPublisher {
+	publisher := mocks_interfaces.NewMockEventPublisher(ctrl)
+	publisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	return publisher
+}
+
+// expectEmail expects one email to be sent and returns where its notice will be stored.
+func expectEmail() *entities.EmailNotice {
+	sent := &entities.EmailNotice{}
+	mockEmailNotifier.EXPECT().Notify(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, notice entities.EmailNotice) error {
+			*sent = notice
+			return nil
+		})
+	return sent
+}
+
+// expectNotification expects one notification to be recorded and returns where it will be stored.
+func expectNotification() *entities.Notification {
+	saved := &entities.Notification{}
+	mockNotificationRepo.EXPECT().SaveNotification(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, notification entities.Notification) error {
+			*saved = notification
+			return nil
+		})
+	return saved
+}
+
+func TestNotificationService_Inbox(t *testing.T) {
+	cleanup := setupNotifications(t)
+	defer cleanup()
+
+	user := newTestSession("tenant1", entities.RoleUser)
+	notifications := []entities.Notification{
+		{ID: primitive.NewObjectID(), Username: "tenant1", Kind: entities.NotificationRequestAccepted},
+		{ID: primitive.NewObjectID(), Username: "tenant1", Kind: entities.NotificationAccountChanged, Read: true},
+		{ID: primitive.NewObjectID(), Username: "tenant1", Kind: entities.NotificationRequestRejected},
+	}
+	mockNotificationRepo.EXPECT().FindNotificationsByUser(gomock.Any(), "tenant1").Return(notifications, nil)
+	unread, err := notificationService.UnreadNotifications(context.Background(), user)
+	require.NoError(t, err)
+	assert.Equal(t, 2, unread)
+
+	// Users only change their own notifications
+	mockNotificationRepo.EXPECT().MarkNotificationRead(gomock.Any(), notifications[0].ID, "tenant1").Return(true, nil)
+	assert.NoError(t, notificationService.MarkNotificationRead(context.Background(), user, notifications[0].ID))
+	mockNotificationRepo.EXPECT().MarkNotificationRead(gomock.Any(), notifications[0].ID, "tenant2").Return(false, nil)
+	err = notificationService.MarkNotificationRead(context.Background(), newTestSession("tenant2", entities.RoleUser), notifications[0].ID)
+	assert.ErrorIs(t, err, services.ErrNotificationNotFound)
+
+	mockNotificationRepo.EXPECT().DeleteNotification(gomock.Any(), notifications[1].ID, "tenant1").Return(true, nil)
+	assert.NoError(t, notificationService.DeleteNotification(context.Background(), user, notifications[1].ID))
+	mockNotificationRepo.EXPECT().DeleteNotification(gomock.Any(), notifications[1].ID, "tenant1").Return(false, nil)
+	assert.ErrorIs(t, notificationService.DeleteNotification(context.Background(), user, notifications[1].ID), services.ErrNotificationNotFound)
+
+	mockNotificationRepo.EXPECT().MarkAllNotificationsRead(gomock.Any(), "tenant1").Return(1, nil)
+	marked, err := notificationService.MarkAllNotificationsRead(context.Background(), user)
+	require.NoError(t, err)
+	assert.Equal(t, 1, marked)
+	mockNotificationRepo.EXPECT().DeleteReadNotifications(gomock.Any(), "tenant1").Return(2, nil)
+	cleared, err := notificationService.ClearReadNotifications(context.Background(), user)
+	require.NoError(t, err)
+	assert.Equal(t, 2, cleared)
+
+	_, err = notificationService.Notifications(context.Background(), nil)
+	assert.ErrorIs(t, err, services.ErrNotLoggedIn)
+}
+
+func TestRequestService_NotifiesLandlordAndTenant(t *testing.T) {
+	cleanup := setupNotifications(t)
+	defer cleanup()
+	requestService := services.NewRequestService(mockRentRequestRepo, mockPropertyRepo, mocks_interfaces.NewMockLeaseRepo(gomock.NewController(t)), eventBus)
+
+	// A new request is announced to the landlord
+	property := &entities.Property{ID: primitive.NewObjectID(), Title: "Family House", LandlordUsername: "landlord1", IsApprovedByAdmin: true}
+	mockPropertyRepo.EXPECT().FindByID(gomock.Any(), property.ID).Return(property, nil)
+	mockRentRequestRepo.EXPECT().CreateRequest(gomock.Any(), gomock.Any()).Return(true, nil)
+	created := expectNotification()
+	createdEmail := expectEmail()
+	require.NoError(t, requestService.CreateRentRequest(context.Background(), newTestSession("tenant1", entities.RoleTenant), property.ID))
+	assert.Equal(t, entities.EmailNotice{Kind: entities.EmailRequestCreated, Username: "landlord1", PropertyID: property.ID, By: "tenant1"}, *createdEmail)
+	assert.Equal(t, "landlord1", created.Username)
+	assert.Equal(t, entities.NotificationRequestCreated, created.Kind)
+	assert.Equal(t, property.ID, created.PropertyID)
+	assert.Equal(t, "tenant1 asked to rent Family House", created.Text)
+	assert.False(t, created.Read)
+
+	// A rejected request is announced to the tenant
+	request := newStoredRequest(entities.RequestPending)
+	mockRentRequestRepo.EXPECT().FindRequestByID(gomock.Any(), request.ID).Return(request, nil)
+	mockRentRequestRepo.EXPECT().UpdateRequestStatus(gomock.Any(), request.ID, gomock.Any()).Return(true, nil)
+	rejected := expectNotification()
+	decidedEmail := expectEmail()
+	require.NoError(t, requestService.UpdateRequestStatus(context.Background(), newTestSession("landlord1", entities.RoleLandlord), request.ID, entities.RequestRejected))
+	assert.Equal(t, "tenant1", rejected.Username)
+	assert.Equal(t, entities.NotificationRequestRejected, rejected.Kind)
+	assert.Equal(t, request.PropertyID, rejected.PropertyID)
+	assert.Equal(t, entities.EmailNotice{Kind: entities.EmailRequestDecided, Username: "tenant1", PropertyID: request.PropertyID, By: "landlord1", Status: entities.RequestRejected}, *decidedEmail)
+
+	// Nobody is notified of a change that did not happen
+	request = newStoredRequest(entities.RequestPending)
+	mockRentRequestRepo.EXPECT().FindRequestByID(gomock.Any(), request.ID).Return(request, nil).Times(2)
+	mockRentRequestRepo.EXPECT().UpdateRequestStatus(gomock.Any(), request.ID, gomock.Any()).Return(false, nil)
+	err := requestService.UpdateRequestStatus(context.Background(), newTestSession("landlord1", entities.RoleLandlord), request.ID, entities.RequestRejected)
+	assert.ErrorIs(t, err, services.ErrInvalidTransition)
+}
+
+func TestPropertyService_ApproveAndRejectNotifyLandlord(t *testing.T) {
+	cleanup := setupNotifications(t)
+	defer cleanup()
+	propertyService := services.NewPropertyService(mockPropertyRepo, eventBus)
+	admin := newTestSession("admin1", entities.RoleAdmin)
+
+	pending := &entities.Property{ID: primitive.NewObjectID(), Title: "Family House", LandlordUsername: "landlord1"}
+	mockPropertyRepo.EXPECT().FindByID(gomock.Any(), pending.ID).Return(pending, nil)
+	mockPropertyRepo.EXPECT().UpdateApprovalStatus(gomock.Any(), pending.ID, true, "admin1").Return(nil)
+	approved := expectNotification()
+	approvedEmail := expectEmail()
+	require.NoError(t, propertyService.ApproveProperty(context.Background(), admin, pending.ID))
+	assert.Equal(t, entities.EmailNotice{Kind: entities.EmailListingApproved, Username: "landlord1", PropertyID: pending.ID, By: "admin1"}, *approvedEmail)
+	assert.Equal(t, entities.NotificationPropertyApproved, approved.Kind)
+	assert.Equal(t, "admin1 approved your listing Family House", approved.Text)
+
+	mockPropertyRepo.EXPECT().FindByID(gomock.Any(), pending.ID).Return(pending, nil)
+	mockPropertyRepo.EXPECT().DeleteListedProperty(gomock.Any(), pending.ID).Return(nil)
+	rejected := expectNotification()
+	require.NoError(t, propertyService.RejectProperty(context.Background(), admin, pending.ID, " No photos "))
+	assert.Equal(t, "landlord1", rejected.Username)
+	assert.Equal(t, "admin1 rejected your listing Family House: No photos", rejected.Text)
+
+	// An approved listing is not rejected afterwards
+	live := &entities.Property{ID: primitive.NewObjectID(), LandlordUsername: "landlord1", IsApprovedByAdmin: true}
+	mockPropertyRepo.EXPECT().FindByID(gomock.Any(), live.ID).Return(live, nil)
+	assert.ErrorIs(t, propertyService.RejectProperty(context.Background(), admin, live.ID, ""), services.ErrPropertyApproved)
+
+	missing := primitive.NewObjectID()
+	mockPropertyRepo.EXPECT().FindByID(gomock.Any(), missing).Return(nil, nil)
+	assert.ErrorIs(t, propertyService.RejectProperty(context.Background(), admin, missing, ""), services.ErrPropertyNotFound)
+	assert.ErrorIs(t, propertyService.RejectProperty(context.Background(), newTestSession("landlord1", entities.RoleUser), pending.ID, ""), services.ErrForbidden)
+}
+
+func TestUserService_NotifiesAccountChanges(t *testing.T) {
+	cleanup := setupNotifications(t)
+	defer cleanup()
+	userService := services.NewUserService(mockUserRepo, mockPropertyRepo, mockRefreshTokenRepo, eventBus)
+
+	mockUserRepo.EXPECT().FindByUsername(gomock.Any(), "testuser").Return(&entities.User{Username: "testuser", Role: entities.RoleUser}, nil)
+	mockUserRepo.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).Return(nil)
+	changed := expectNotification()
+	require.NoError(t, userService.SetRole(context.Background(), newTestSession("admin", entities.RoleAdmin), "testuser", entities.RoleModerator))
+	assert.Equal(t, "testuser", changed.Username)
+	assert.Equal(t, "admin changed your role to Moderator", changed.Text)
+
+	mockUserRepo.EXPECT().FindByUsername(gomock.Any(), "testuser").Return(&entities.User{Username: "testuser", Role: entities.RoleUser}, nil)
+	mockUserRepo.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).Return(nil)
+	updated := expectNotification()
+	require.NoError(t, userService.UpdateUser(context.Background(), newTestSession("testuser", entities.RoleUser), entities.User{Username: "testuser", Email: "new@example.com"}))
+	assert.Equal(t, "Your account details were changed", updated.Text)
+}
+
+func TestUserService_SignUpEmailsWelcome(t *testing.T) {
+	cleanup := setupNotifications(t)
+	defer cleanup()
+	userService := services.NewUserService(mockUserRepo, mockPropertyRepo, mockRefreshTokenRepo, eventBus)
+
+	mockUserRepo.EXPECT().FindByUsername(gomock.Any(), "newuser").Return(nil, nil)
+	mockUserRepo.EXPECT().SaveUser(gomock.Any(), gomock.Any()).Return(nil)
+	welcome := expectEmail()
+	require.NoError(t, userService.SignUp(context.Background(), entities.User{Username: "newuser", Email: "new@example.com"}))
+	assert.Equal(t, entities.EmailNotice{Kind: entities.EmailWelcome, Username: "newuser"}, *welcome)
+
+	// Nobody is welcomed twice
+	mockUserRepo.EXPECT().FindByUsername(gomock.Any(), "newuser").Return(&entities.User{Username: "newuser"}, nil)
+	assert.ErrorIs(t, userService.SignUp(context.Background(), entities.User{Username: "newuser"}), services.ErrUsernameTaken)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -26,7 +27,7 @@ func setup2(t *testing.T) func() {
 	mockPropertyRepo = mocks_interfaces.NewMockPropertyRepo(ctrl)
 
 	// Initialize the PropertyService with the mock repository
-	propertyService = services.NewPropertyService(mockPropertyRepo)
+	propertyService = services.NewPropertyService(mockPropertyRepo, ignoreEvents(ctrl))
 
 	// Return a cleanup function to be called at the end of the test
 	return func() {
@@ -112,10 +113,11 @@ func TestPropertyService_ListProperty(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Set up the expected behavior of the mock repository
-			mockPropertyRepo.EXPECT().SaveProperty(tt.property).Return(tt.mockError).Times(1)
+			mockPropertyRepo.EXPECT().SaveProperty(gomock.Any(), tt.property).Return(tt.mockError).Times(1)
 
 			// Call the ListProperty method and capture the result
-			err := propertyService.ListProperty(tt.property)
+			session := newTestSession(tt.property.LandlordUsername, entities.RoleUser)
+			err := propertyService.ListProperty(context.Background(), session, tt.property)
 
 			// Validate the result
 			if tt.expectedError {
@@ -127,6 +129,28 @@ func TestPropertyService_ListProperty(t *testing.T) {
 	}
 }
 
+func TestPropertyService_ListProperty_Authorization(t *testing.T) {
+	cleanup := setup2(t)
+	defer cleanup()
+
+	// The landlord is always the user of the session
+	property := entities.Property{ID: primitive.NewObjectID(), Title: "Family House", LandlordUsername: "someoneElse"}
+	expected := property
+	expected.LandlordUsername = "landlord1"
+	mockPropertyRepo.EXPECT().SaveProperty(gomock.Any(), expected).Return(nil)
+	assert.NoError(t, propertyService.ListProperty(context.Background(), newTestSession("landlord1", entities.RoleLandlord), property))
+
+	// Tenants cannot list properties
+	err := propertyService.ListProperty(context.Background(), newTestSession("tenant1", entities.RoleTenant), property)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+	var forbidden *services.ForbiddenError
+	assert.ErrorAs(t, err, &forbidden)
+	assert.Equal(t, "tenant1", forbidden.Username)
+
+	err = propertyService.ListProperty(context.Background(), nil, property)
+	assert.ErrorIs(t, err, services.ErrNotLoggedIn)
+}
+
 func TestPropertyService_GetAllListedProperties(t *testing.T) {
 	cleanup := setup2(t)
 	defer cleanup()
@@ -136,15 +160,15 @@ func TestPropertyService_GetAllListedProperties(t *testing.T) {
 
 	tests := []struct {
 		name             string
-		activeUserOnly   bool
+		landlordUsername string
 		mockProperties   []entities.Property
 		mockError        error
 		expectedError    bool
 		expectedResponse []entities.Property
 	}{
 		{
-			name:           "Successful retrieval of all listed properties",
-			activeUserOnly: false,
+			name:             "Successful retrieval of all listed properties",
+			landlordUsername: "",
 			mockProperties: []entities.Property{
 				{
 					ID:               predefinedID1,
@@ -186,7 +210,7 @@ func TestPropertyService_GetAllListedProperties(t *testing.T) {
 		},
 		{
 			name:             "Error retrieving properties",
-			activeUserOnly:   true,
+			landlordUsername: "landlord1",
 			mockProperties:   nil,
 			mockError:        errors.New("error fetching properties"),
 			expectedError:    true,
@@ -196,9 +220,9 @@ func TestPropertyService_GetAllListedProperties(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockPropertyRepo.EXPECT().GetAllListedProperties(tt.activeUserOnly).Return(tt.mockProperties, tt.mockError).Times(1)
+			mockPropertyRepo.EXPECT().GetAllListedProperties(gomock.Any(), tt.landlordUsername).Return(tt.mockProperties, tt.mockError).Times(1)
 
-			properties, err := propertyService.GetAllListedProperties(tt.activeUserOnly)
+			properties, err := propertyService.GetAllListedProperties(context.Background(), tt.landlordUsername)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -344,13 +368,18 @@ func TestPropertyService_UpdateListedProperty(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			// The stored property belongs to the landlord of the session
+			stored := tt.property
+			mockPropertyRepo.EXPECT().FindByID(gomock.Any(), tt.property.ID).Return(&stored, nil)
+
 			// Expect the UpdateListedProperty method to be called with the modified property
 			mockPropertyRepo.EXPECT().
-				UpdateListedProperty(tt.property).
+				UpdateListedProperty(gomock.Any(), tt.property).
 				Return(tt.mockError).
 				Times(tt.expectedCalls)
 
-			err := propertyService.UpdateListedProperty(tt.property)
+			session := newTestSession(tt.property.LandlordUsername, entities.RoleUser)
+			err := propertyService.UpdateListedProperty(context.Background(), session, tt.property)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -361,58 +390,104 @@ func TestPropertyService_UpdateListedProperty(t *testing.T) {
 	}
 }
 
+func TestPropertyService_UpdateListedProperty_Authorization(t *testing.T) {
+	cleanup := setup2(t)
+	defer cleanup()
+
+	stored := entities.Property{ID: primitive.NewObjectID(), Title: "Family House", LandlordUsername: "landlord1"}
+
+	t.Run("Other landlord", func(t *testing.T) {
+		mockPropertyRepo.EXPECT().FindByID(gomock.Any(), stored.ID).Return(&stored, nil)
+		err := propertyService.UpdateListedProperty(context.Background(), newTestSession("landlord2", entities.RoleUser), stored)
+		assert.ErrorIs(t, err, services.ErrForbidden)
+	})
+
+	t.Run("Landlord cannot be changed", func(t *testing.T) {
+		update := stored
+		update.LandlordUsername = "landlord2"
+		mockPropertyRepo.EXPECT().FindByID(gomock.Any(), stored.ID).Return(&stored, nil)
+		mockPropertyRepo.EXPECT().UpdateListedProperty(gomock.Any(), stored).Return(nil)
+		err := propertyService.UpdateListedProperty(context.Background(), newTestSession("landlord1", entities.RoleUser), update)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Unknown property", func(t *testing.T) {
+		mockPropertyRepo.EXPECT().FindByID(gomock.Any(), stored.ID).Return(nil, nil)
+		err := propertyService.UpdateListedProperty(context.Background(), newTestSession("landlord1", entities.RoleUser), stored)
+		assert.ErrorIs(t, err, services.ErrPropertyNotFound)
+	})
+}
+
 func TestPropertyService_DeleteListedProperty(t *testing.T) {
 	cleanup := setup2(t) // Assuming setup2 initializes the mock and service
 	defer cleanup()
 
-	predefinedID := "somePropertyID"
+	property := entities.Property{ID: primitive.NewObjectID(), Title: "Family House", LandlordUsername: "landlord1"}
 
 	tests := []struct {
 		name          string
-		propertyID    string
+		session       *entities.Session
+		mockProperty  *entities.Property
 		mockError     error
-		expectedError bool
+		expectedError error
 		expectedCalls int
 	}{
 		{
-			name:          "Successful deletion",
-			propertyID:    predefinedID,
-			mockError:     nil,
-			expectedError: false,
+			name:          "Landlord deletes own property",
+			session:       newTestSession("landlord1", entities.RoleUser),
+			mockProperty:  &property,
 			expectedCalls: 1,
 		},
 		{
 			name:          "Error during deletion",
-			propertyID:    predefinedID,
+			session:       newTestSession("landlord1", entities.RoleUser),
+			mockProperty:  &property,
 			mockError:     errors.New("delete error"),
-			expectedError: true,
+			expectedError: errors.New("delete error"),
 			expectedCalls: 1,
 		},
 		{
-			name:          "Empty property ID",
-			propertyID:    "",
-			mockError:     nil,
-			expectedError: false,
+			name:          "Moderator deletes any property",
+			session:       newTestSession("moderator1", entities.RoleModerator),
+			mockProperty:  &property,
 			expectedCalls: 1,
 		},
+		{
+			name:          "Other landlord is forbidden",
+			session:       newTestSession("landlord2", entities.RoleUser),
+			mockProperty:  &property,
+			expectedError: services.ErrForbidden,
+		},
+		{
+			name:          "Tenant is forbidden",
+			session:       newTestSession("landlord1", entities.RoleTenant),
+			mockProperty:  &property,
+			expectedError: services.ErrForbidden,
+		},
+		{
+			name:          "Unknown property",
+			session:       newTestSession("landlord1", entities.RoleUser),
+			expectedError: services.ErrPropertyNotFound,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Set up the expected call only if the property ID is not empty
-			if tt.expectedCalls > 0 {
-				mockPropertyRepo.EXPECT().
-					DeleteListedProperty(tt.propertyID).
-					Return(tt.mockError).
-					Times(tt.expectedCalls)
-			}
+			mockPropertyRepo.EXPECT().FindByID(gomock.Any(), property.ID).Return(tt.mockProperty, nil)
+			mockPropertyRepo.EXPECT().
+				DeleteListedProperty(gomock.Any(), property.ID).
+				Return(tt.mockError).
+				Times(tt.expectedCalls)
 
-			err := propertyService.DeleteListedProperty(tt.propertyID)
+			err := propertyService.DeleteListedProperty(context.Background(), tt.session, property.ID)
 
-			if tt.expectedError {
-				assert.Error(t, err)
-			} else {
+			switch {
+			case tt.expectedError == nil:
 				assert.NoError(t, err)
+			case tt.mockError != nil:
+				assert.EqualError(t, err, tt.expectedError.Error())
+			default:
+				assert.ErrorIs(t, err, tt.expectedError)
 			}
 		})
 	}
@@ -523,11 +598,11 @@ func TestPropertyService_SearchProperties(t *testing.T) {
 
 			// Set up the mock to return the predefined properties or error
 			mockPropertyRepo.EXPECT().
-				GetAllListedProperties(false).
+				GetAllListedProperties(gomock.Any(), "").
 				Return(tt.mockProperties, nil).
 				Times(1)
 
-			result, err := propertyService.SearchProperties(tt.area, tt.city, tt.state, tt.pincode, tt.propertyType)
+			result, err := propertyService.SearchProperties(context.Background(), tt.area, tt.city, tt.state, tt.pincode, tt.propertyType)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -594,11 +669,11 @@ func TestPropertyService_FindByID(t *testing.T) {
 
 			// Set up the mock to return the predefined property or error
 			mockPropertyRepo.EXPECT().
-				FindByID(context.TODO(), tt.propertyID). // Match any context
+				FindByID(gomock.Any(), tt.propertyID). // Match any context
 				Return(tt.mockProperty, tt.mockError).
 				Times(1)
 
-			result, err := propertyService.FindByID(tt.propertyID)
+			result, err := propertyService.FindByID(context.Background(), tt.propertyID)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -640,11 +715,11 @@ func TestPropertyService_DeleteAllListedPropertiesOfaUser(t *testing.T) {
 
 			// Set up the mock to return the predefined error
 			mockPropertyRepo.EXPECT().
-				DeleteAllListedPropertiesOfaUser(tt.username).
+				DeleteAllListedPropertiesOfaUser(gomock.Any(), tt.username).
 				Return(tt.mockError).
 				Times(1)
 
-			err := propertyService.DeleteAllListedPropertiesOfaUser(tt.username)
+			err := propertyService.DeleteAllListedPropertiesOfaUser(context.Background(), newTestSession("adminUser", entities.RoleAdmin), tt.username)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -708,11 +783,11 @@ func TestPropertyService_GetPendingProperties(t *testing.T) {
 
 			// Set up the mock to return the predefined properties or error
 			mockPropertyRepo.EXPECT().
-				FindPendingProperties().
+				FindPendingProperties(gomock.Any()).
 				Return(tt.mockProperties, tt.mockError).
 				Times(1)
 
-			result, err := propertyService.GetPendingProperties()
+			result, err := propertyService.GetPendingProperties(context.Background(), newTestSession("moderatorUser", entities.RoleModerator))
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -764,13 +839,15 @@ func TestPropertyService_ApproveProperty(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Set up the mock to expect the correct call
+			// Set up the mock to expect the correct calls
+			mockPropertyRepo.EXPECT().FindByID(gomock.Any(), tt.propertyID).
+				Return(&entities.Property{ID: tt.propertyID, LandlordUsername: "landlord1"}, nil)
 			mockPropertyRepo.EXPECT().
-				UpdateApprovalStatus(tt.propertyID, true, tt.adminUsername).
+				UpdateApprovalStatus(gomock.Any(), tt.propertyID, true, tt.adminUsername).
 				Return(tt.mockError).
 				Times(1)
 
-			err := propertyService.ApproveProperty(tt.propertyID, tt.adminUsername)
+			err := propertyService.ApproveProperty(context.Background(), newTestSession(tt.adminUsername, "Admin"), tt.propertyID)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -780,3 +857,37 @@ func TestPropertyService_ApproveProperty(t *testing.T) {
 		})
 	}
 }
+
+func TestPropertyService_ApproveProperty_RequiresValidSession(t *testing.T) {
+	cleanup := setup2(t)
+	defer cleanup()
+
+	expired := newTestSession("adminUser", "Admin")
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+
+	// The repository must not be called without a valid session
+	err := propertyService.ApproveProperty(context.Background(), nil, primitive.NewObjectID())
+	assert.ErrorIs(t, err, services.ErrNotLoggedIn)
+
+	err = propertyService.ApproveProperty(context.Background(), expired, primitive.NewObjectID())
+	assert.ErrorIs(t, err, services.ErrSessionExpired)
+}
+
+func TestPropertyService_AdminActionsRequirePermission(t *testing.T) {
+	cleanup := setup2(t)
+	defer cleanup()
+
+	// The repository must not be called for users without the permission
+	landlord := newTestSession("landlord1", entities.RoleUser)
+	moderator := newTestSession("moderator1", entities.RoleModerator)
+
+	err := propertyService.ApproveProperty(context.Background(), landlord, primitive.NewObjectID())
+	assert.ErrorIs(t, err, services.ErrForbidden)
+
+	_, err = propertyService.GetPendingProperties(context.Background(), landlord)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+
+	// Only admins may delete all properties of a user
+	err = propertyService.DeleteAllListedPropertiesOfaUser(context.Background(), moderator, "landlord1")
+	assert.ErrorIs(t, err, services.ErrForbidden)
+}
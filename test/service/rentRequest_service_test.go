@@ -1,6 +1,7 @@
 package service_test
 
 import (
+	"context"
 	"errors"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -14,6 +15,7 @@ import (
 
 var (
 	mockRentRequestRepo *mocks_interfaces.MockRequestRepo
+	mockLeaseRepo       *mocks_interfaces.MockLeaseRepo
 	rentRequestService  *services.RequestService
 )
 
@@ -23,9 +25,11 @@ func setup3(t *testing.T) func() {
 
 	// Create a mock PropertyRepo
 	mockRentRequestRepo = mocks_interfaces.NewMockRequestRepo(ctrl)
+	mockPropertyRepo = mocks_interfaces.NewMockPropertyRepo(ctrl)
+	mockLeaseRepo = mocks_interfaces.NewMockLeaseRepo(ctrl)
 
-	// Initialize the RentRequestService with the mock repository
-	rentRequestService = services.NewRequestService(mockRentRequestRepo)
+	// Initialize the RentRequestService with the mock repositories
+	rentRequestService = services.NewRequestService(mockRentRequestRepo, mockPropertyRepo, mockLeaseRepo, ignoreEvents(ctrl))
 
 	// Return a cleanup function to be called at the end of the test
 	return func() {
@@ -44,14 +48,14 @@ func TestRequestService_GetRentRequestsInfoForLandlord(t *testing.T) {
 			PropertyID:    primitive.NewObjectID(),
 			TenantName:    "tenant1",
 			LandlordName:  landlordName,
-			RequestStatus: "pending",
+			RequestStatus: entities.RequestPending,
 			CreatedAt:     now,
 		},
 		{
 			PropertyID:    primitive.NewObjectID(),
 			TenantName:    "tenant2",
 			LandlordName:  landlordName,
-			RequestStatus: "pending",
+			RequestStatus: entities.RequestPending,
 			CreatedAt:     now,
 		},
 	}
@@ -88,7 +92,7 @@ func TestRequestService_GetRentRequestsInfoForLandlord(t *testing.T) {
 				Times(1)
 
 			// Call the method under test
-			result, err := rentRequestService.GetRentRequestsInfoForLandlord(landlordName)
+			result, err := rentRequestService.GetRentRequestsInfoForLandlord(context.Background(), newTestSession(landlordName, entities.RoleLandlord))
 
 			// Assertions
 			if tt.expectedError {
@@ -109,45 +113,106 @@ func TestRequestService_GetRentRequestsInfoForLandlord(t *testing.T) {
 	}
 }
 
-func TestRequestService_UpdateRequestStatus(t *testing.T) {
-	cleanup := setup3(t)
-	defer cleanup()
-
-	request := entities.Request{
+// newStoredRequest returns a request of tenant1 for a property of landlord1 in the given status.
+func newStoredRequest(status entities.RequestStatus) *entities.Request {
+	return &entities.Request{
+		ID:            primitive.NewObjectID(),
 		PropertyID:    primitive.NewObjectID(),
 		TenantName:    "tenant1",
 		LandlordName:  "landlord1",
-		RequestStatus: "pending",
+		RequestStatus: status,
 		CreatedAt:     time.Now(),
 	}
-	status := "approved"
+}
+
+// expectTransition expects the request to be moved from its status to the given one by the user.
+func expectTransition(t *testing.T, request *entities.Request, to entities.RequestStatus, by string, updated bool, err error) {
+	mockRentRequestRepo.EXPECT().
+		UpdateRequestStatus(gomock.Any(), request.ID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ primitive.ObjectID, change entities.StatusChange) (bool, error) {
+			assert.Equal(t, request.RequestStatus, change.From)
+			assert.Equal(t, to, change.To)
+			assert.Equal(t, by, change.ChangedBy)
+			assert.WithinDuration(t, time.Now(), change.ChangedAt, time.Minute)
+			return updated, err
+		})
+}
+
+// expectOpenLease expects the property of the request to be rented out and a pending lease to be drawn up for it.
+// The returned lease is filled in once it is saved.
+func expectOpenLease(t *testing.T, request *entities.Request) *entities.Lease {
+	property := &entities.Property{ID: request.PropertyID, RentAmount: 1000}
+	saved := &entities.Lease{}
+	mockPropertyRepo.EXPECT().FindByID(gomock.Any(), request.PropertyID).Return(property, nil)
+	mockPropertyRepo.EXPECT().SetRented(gomock.Any(), request.PropertyID, true).Return(true, nil)
+	mockLeaseRepo.EXPECT().SaveLease(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, lease entities.Lease) error {
+			assert.Equal(t, request.ID, lease.RequestID)
+			assert.Equal(t, request.TenantName, lease.TenantName)
+			assert.Equal(t, entities.LeasePending, lease.Status)
+			assert.Equal(t, 1000.0, lease.MonthlyRent)
+			assert.Equal(t, 2000.0, lease.Deposit)
+			assert.Equal(t, lease.StartDate.AddDate(0, services.DefaultLeaseMonths, 0), lease.EndDate)
+			*saved = lease
+			return nil
+		})
+	return saved
+}
+
+// expectLeaseStatus expects the lease of the request to be found in from and moved to the given status.
+func expectLeaseStatus(request *entities.Request, from, to entities.LeaseStatus) {
+	lease := &entities.Lease{ID: primitive.NewObjectID(), RequestID: request.ID, PropertyID: request.PropertyID, Status: from}
+	mockLeaseRepo.EXPECT().FindLeaseByRequestID(gomock.Any(), request.ID).Return(lease, nil)
+	mockLeaseRepo.EXPECT().UpdateLeaseStatus(gomock.Any(), lease.ID, from, to).Return(true, nil)
+}
+
+func TestRequestService_UpdateRequestStatus(t *testing.T) {
+	cleanup := setup3(t)
+	defer cleanup()
+
+	session := newTestSession("landlord1", entities.RoleLandlord)
 
 	tests := []struct {
 		name          string
+		from          entities.RequestStatus
+		to            entities.RequestStatus
 		mockError     error
 		expectedError bool
 	}{
-		{
-			name:          "Successful update",
-			mockError:     nil,
-			expectedError: false,
-		},
-		{
-			name:          "Error during update",
-			mockError:     errors.New("update error"),
-			expectedError: true,
-		},
+		{name: "Accept a pending request", from: entities.RequestPending, to: entities.RequestAccepted},
+		{name: "Reject a pending request", from: entities.RequestPending, to: entities.RequestRejected},
+		{name: "Sign the lease of an accepted request", from: entities.RequestAccepted, to: entities.RequestLeaseSigned},
+		{name: "Cancel an accepted request", from: entities.RequestAccepted, to: entities.RequestCancelled},
+		{name: "Error during update", from: entities.RequestPending, to: entities.RequestAccepted, mockError: errors.New("update error"), expectedError: true},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Mock the UpdateRequest call with the expected arguments
-			mockRentRequestRepo.EXPECT().
-				UpdateRequest(request, status).
-				Return(tt.mockError).
-				Times(1)
+			request := newStoredRequest(tt.from)
+			mockRentRequestRepo.EXPECT().FindRequestByID(gomock.Any(), request.ID).Return(request, nil)
+			switch tt.to {
+			case entities.RequestAccepted:
+				lease := expectOpenLease(t, request)
+				if tt.mockError == nil {
+					mockRentRequestRepo.EXPECT().FindByPropertyID(gomock.Any(), request.PropertyID).Return([]entities.Request{*request}, nil)
+				} else {
+					// The lease is called off and the property put back on the market
+					mockLeaseRepo.EXPECT().UpdateLeaseStatus(gomock.Any(), gomock.Any(), entities.LeasePending, entities.LeaseCancelled).
+						DoAndReturn(func(_ context.Context, id primitive.ObjectID, _, _ entities.LeaseStatus) (bool, error) {
+							assert.Equal(t, lease.ID, id)
+							return true, nil
+						})
+					mockPropertyRepo.EXPECT().SetRented(gomock.Any(), request.PropertyID, false).Return(true, nil)
+				}
+			case entities.RequestLeaseSigned:
+				expectLeaseStatus(request, entities.LeasePending, entities.LeaseActive)
+			case entities.RequestCancelled:
+				expectLeaseStatus(request, entities.LeasePending, entities.LeaseCancelled)
+				mockPropertyRepo.EXPECT().SetRented(gomock.Any(), request.PropertyID, false).Return(true, nil)
+			}
+			expectTransition(t, request, tt.to, "landlord1", tt.mockError == nil, tt.mockError)
 
-			err := rentRequestService.UpdateRequestStatus(request, status)
+			err := rentRequestService.UpdateRequestStatus(context.Background(), session, request.ID, tt.to)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -156,6 +221,222 @@ func TestRequestService_UpdateRequestStatus(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("Illegal transitions", func(t *testing.T) {
+		for _, tt := range []struct {
+			from entities.RequestStatus
+			to   entities.RequestStatus
+		}{
+			{entities.RequestPending, entities.RequestLeaseSigned},
+			{entities.RequestPending, entities.RequestPending},
+			{entities.RequestAccepted, entities.RequestRejected},
+			{entities.RequestRejected, entities.RequestAccepted},
+			{entities.RequestLeaseSigned, entities.RequestCancelled},
+			{entities.RequestPending, "approved"},
+		} {
+			request := newStoredRequest(tt.from)
+			mockRentRequestRepo.EXPECT().FindRequestByID(gomock.Any(), request.ID).Return(request, nil)
+
+			err := rentRequestService.UpdateRequestStatus(context.Background(), session, request.ID, tt.to)
+			assert.ErrorIs(t, err, services.ErrInvalidTransition, "%s to %s", tt.from, tt.to)
+			var transitionErr *services.TransitionError
+			if assert.ErrorAs(t, err, &transitionErr) {
+				assert.Equal(t, tt.from, transitionErr.From)
+			}
+		}
+	})
+
+	t.Run("Request changed meanwhile", func(t *testing.T) {
+		request := newStoredRequest(entities.RequestPending)
+		mockRentRequestRepo.EXPECT().FindRequestByID(gomock.Any(), request.ID).Return(request, nil)
+		expectOpenLease(t, request)
+		expectTransition(t, request, entities.RequestAccepted, "landlord1", false, nil)
+		mockRentRequestRepo.EXPECT().FindRequestByID(gomock.Any(), request.ID).Return(newStoredRequest(entities.RequestWithdrawn), nil)
+		mockLeaseRepo.EXPECT().UpdateLeaseStatus(gomock.Any(), gomock.Any(), entities.LeasePending, entities.LeaseCancelled).Return(true, nil)
+		mockPropertyRepo.EXPECT().SetRented(gomock.Any(), request.PropertyID, false).Return(true, nil)
+
+		err := rentRequestService.UpdateRequestStatus(context.Background(), session, request.ID, entities.RequestAccepted)
+		assert.ErrorIs(t, err, services.ErrInvalidTransition)
+		assert.Contains(t, err.Error(), "withdrawn")
+	})
+
+	t.Run("Accepting rejects the competing requests", func(t *testing.T) {
+		request := newStoredRequest(entities.RequestPending)
+		competing := newStoredRequest(entities.RequestPending)
+		withdrawn := newStoredRequest(entities.RequestWithdrawn)
+		withdrawnMeanwhile := newStoredRequest(entities.RequestPending)
+		for _, other := range []*entities.Request{competing, withdrawn, withdrawnMeanwhile} {
+			other.PropertyID = request.PropertyID
+			other.TenantName = "tenant2"
+		}
+
+		mockRentRequestRepo.EXPECT().FindRequestByID(gomock.Any(), request.ID).Return(request, nil)
+		expectOpenLease(t, request)
+		expectTransition(t, request, entities.RequestAccepted, "landlord1", true, nil)
+		mockRentRequestRepo.EXPECT().FindByPropertyID(gomock.Any(), request.PropertyID).
+			Return([]entities.Request{*request, *competing, *withdrawn, *withdrawnMeanwhile}, nil)
+		mockRentRequestRepo.EXPECT().
+			UpdateRequestStatus(gomock.Any(), competing.ID, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ primitive.ObjectID, change entities.StatusChange) (bool, error) {
+				assert.Equal(t, entities.RequestPending, change.From)
+				assert.Equal(t, entities.RequestRejected, change.To)
+				assert.Equal(t, services.ReasonPropertyRented, change.Reason)
+				return true, nil
+			})
+		expectTransition(t, withdrawnMeanwhile, entities.RequestRejected, "landlord1", false, nil)
+		current := *withdrawnMeanwhile
+		current.RequestStatus = entities.RequestWithdrawn
+		mockRentRequestRepo.EXPECT().FindRequestByID(gomock.Any(), withdrawnMeanwhile.ID).Return(&current, nil)
+
+		assert.NoError(t, rentRequestService.UpdateRequestStatus(context.Background(), session, request.ID, entities.RequestAccepted))
+	})
+
+	t.Run("Property already rented", func(t *testing.T) {
+		request := newStoredRequest(entities.RequestPending)
+		mockRentRequestRepo.EXPECT().FindRequestByID(gomock.Any(), request.ID).Return(request, nil)
+		mockPropertyRepo.EXPECT().FindByID(gomock.Any(), request.PropertyID).Return(&entities.Property{ID: request.PropertyID, IsRented: true}, nil)
+		mockPropertyRepo.EXPECT().SetRented(gomock.Any(), request.PropertyID, true).Return(false, nil)
+
+		err := rentRequestService.UpdateRequestStatus(context.Background(), session, request.ID, entities.RequestAccepted)
+		assert.ErrorIs(t, err, services.ErrPropertyRented)
+	})
+
+	t.Run("Property deleted", func(t *testing.T) {
+		request := newStoredRequest(entities.RequestPending)
+		mockRentRequestRepo.EXPECT().FindRequestByID(gomock.Any(), request.ID).Return(request, nil)
+		mockPropertyRepo.EXPECT().FindByID(gomock.Any(), request.PropertyID).Return(nil, nil)
+
+		err := rentRequestService.UpdateRequestStatus(context.Background(), session, request.ID, entities.RequestAccepted)
+		assert.ErrorIs(t, err, services.ErrPropertyNotFound)
+	})
+
+	t.Run("Lease changed meanwhile", func(t *testing.T) {
+		request := newStoredRequest(entities.RequestAccepted)
+		mockRentRequestRepo.EXPECT().FindRequestByID(gomock.Any(), request.ID).Return(request, nil)
+		lease := &entities.Lease{ID: primitive.NewObjectID(), RequestID: request.ID, PropertyID: request.PropertyID, Status: entities.LeasePending}
+		mockLeaseRepo.EXPECT().FindLeaseByRequestID(gomock.Any(), request.ID).Return(lease, nil)
+		mockLeaseRepo.EXPECT().UpdateLeaseStatus(gomock.Any(), lease.ID, entities.LeasePending, entities.LeaseActive).Return(false, nil)
+		mockLeaseRepo.EXPECT().FindLeaseByID(gomock.Any(), lease.ID).Return(&entities.Lease{ID: lease.ID, Status: entities.LeaseCancelled}, nil)
+
+		// The request is left as it was
+		err := rentRequestService.UpdateRequestStatus(context.Background(), session, request.ID, entities.RequestLeaseSigned)
+		assert.ErrorIs(t, err, services.ErrInvalidTransition)
+		var stateErr *services.LeaseStateError
+		if assert.ErrorAs(t, err, &stateErr) {
+			assert.Equal(t, entities.LeaseCancelled, stateErr.Status)
+		}
+	})
+
+	t.Run("Request changed meanwhile moves the lease back", func(t *testing.T) {
+		request := newStoredRequest(entities.RequestAccepted)
+		mockRentRequestRepo.EXPECT().FindRequestByID(gomock.Any(), request.ID).Return(request, nil)
+		expectLeaseStatus(request, entities.LeasePending, entities.LeaseCancelled)
+		mockPropertyRepo.EXPECT().SetRented(gomock.Any(), request.PropertyID, false).Return(true, nil)
+		expectTransition(t, request, entities.RequestCancelled, "landlord1", false, nil)
+		mockRentRequestRepo.EXPECT().FindRequestByID(gomock.Any(), request.ID).Return(newStoredRequest(entities.RequestLeaseSigned), nil)
+		// The property is rented out again for the lease put back
+		mockPropertyRepo.EXPECT().SetRented(gomock.Any(), request.PropertyID, true).Return(true, nil)
+		mockLeaseRepo.EXPECT().UpdateLeaseStatus(gomock.Any(), gomock.Any(), entities.LeaseCancelled, entities.LeasePending).Return(true, nil)
+
+		err := rentRequestService.UpdateRequestStatus(context.Background(), session, request.ID, entities.RequestCancelled)
+		assert.ErrorIs(t, err, services.ErrInvalidTransition)
+	})
+
+	t.Run("Cancelling a request accepted without a lease", func(t *testing.T) {
+		request := newStoredRequest(entities.RequestAccepted)
+		mockRentRequestRepo.EXPECT().FindRequestByID(gomock.Any(), request.ID).Return(request, nil)
+		expectTransition(t, request, entities.RequestCancelled, "landlord1", true, nil)
+		mockLeaseRepo.EXPECT().FindLeaseByRequestID(gomock.Any(), request.ID).Return(nil, nil)
+		mockPropertyRepo.EXPECT().SetRented(gomock.Any(), request.PropertyID, false).Return(true, nil)
+
+		assert.NoError(t, rentRequestService.UpdateRequestStatus(context.Background(), session, request.ID, entities.RequestCancelled))
+	})
+
+	t.Run("Landlords cannot withdraw or expire requests", func(t *testing.T) {
+		for _, status := range []entities.RequestStatus{entities.RequestWithdrawn, entities.RequestExpired} {
+			request := newStoredRequest(entities.RequestPending)
+			mockRentRequestRepo.EXPECT().FindRequestByID(gomock.Any(), request.ID).Return(request, nil)
+
+			err := rentRequestService.UpdateRequestStatus(context.Background(), session, request.ID, status)
+			assert.ErrorIs(t, err, services.ErrForbidden)
+		}
+	})
+
+	t.Run("Request of another landlord", func(t *testing.T) {
+		request := newStoredRequest(entities.RequestPending)
+		mockRentRequestRepo.EXPECT().FindRequestByID(gomock.Any(), request.ID).Return(request, nil)
+
+		err := rentRequestService.UpdateRequestStatus(context.Background(), newTestSession("landlord2", entities.RoleLandlord), request.ID, entities.RequestAccepted)
+		assert.ErrorIs(t, err, services.ErrForbidden)
+	})
+
+	t.Run("Unknown request", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockRentRequestRepo.EXPECT().FindRequestByID(gomock.Any(), id).Return(nil, nil)
+
+		err := rentRequestService.UpdateRequestStatus(context.Background(), session, id, entities.RequestAccepted)
+		assert.ErrorIs(t, err, services.ErrRequestNotFound)
+	})
+
+	t.Run("Tenants cannot answer requests", func(t *testing.T) {
+		err := rentRequestService.UpdateRequestStatus(context.Background(), newTestSession("landlord1", entities.RoleTenant), primitive.NewObjectID(), entities.RequestAccepted)
+		assert.ErrorIs(t, err, services.ErrForbidden)
+	})
+}
+
+func TestRequestService_WithdrawRequest(t *testing.T) {
+	cleanup := setup3(t)
+	defer cleanup()
+
+	session := newTestSession("tenant1", entities.RoleTenant)
+
+	t.Run("Pending request", func(t *testing.T) {
+		request := newStoredRequest(entities.RequestPending)
+		mockRentRequestRepo.EXPECT().FindRequestByID(gomock.Any(), request.ID).Return(request, nil)
+		expectTransition(t, request, entities.RequestWithdrawn, "tenant1", true, nil)
+
+		assert.NoError(t, rentRequestService.WithdrawRequest(context.Background(), session, request.ID))
+	})
+
+	t.Run("Answered request", func(t *testing.T) {
+		request := newStoredRequest(entities.RequestAccepted)
+		mockRentRequestRepo.EXPECT().FindRequestByID(gomock.Any(), request.ID).Return(request, nil)
+
+		err := rentRequestService.WithdrawRequest(context.Background(), session, request.ID)
+		assert.ErrorIs(t, err, services.ErrInvalidTransition)
+	})
+
+	t.Run("Request of another tenant", func(t *testing.T) {
+		request := newStoredRequest(entities.RequestPending)
+		mockRentRequestRepo.EXPECT().FindRequestByID(gomock.Any(), request.ID).Return(request, nil)
+
+		err := rentRequestService.WithdrawRequest(context.Background(), newTestSession("tenant2", entities.RoleTenant), request.ID)
+		assert.ErrorIs(t, err, services.ErrForbidden)
+	})
+}
+
+func TestRequestService_ExpireRequests(t *testing.T) {
+	cleanup := setup3(t)
+	defer cleanup()
+
+	old := newStoredRequest(entities.RequestPending)
+	old.CreatedAt = time.Now().Add(-60 * 24 * time.Hour)
+	answered := newStoredRequest(entities.RequestPending)
+	answered.CreatedAt = old.CreatedAt
+	recent := newStoredRequest(entities.RequestPending)
+
+	mockRentRequestRepo.EXPECT().FindByStatus(gomock.Any(), entities.RequestPending).Return([]entities.Request{*old, *answered, *recent}, nil)
+	expectTransition(t, old, entities.RequestExpired, "moderator", true, nil)
+	// Answered by the landlord after it was read, so it is skipped
+	expectTransition(t, answered, entities.RequestExpired, "moderator", false, nil)
+	mockRentRequestRepo.EXPECT().FindRequestByID(gomock.Any(), answered.ID).Return(newStoredRequest(entities.RequestAccepted), nil)
+
+	expired, err := rentRequestService.ExpireRequests(context.Background(), newTestSession("moderator", entities.RoleModerator), time.Now().Add(-30*24*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, expired)
+
+	_, err = rentRequestService.ExpireRequests(context.Background(), newTestSession("landlord1", entities.RoleUser), time.Now())
+	assert.ErrorIs(t, err, services.ErrForbidden)
 }
 
 func TestRequestService_GetRentRequestsInfoForTenant(t *testing.T) {
@@ -168,14 +449,14 @@ func TestRequestService_GetRentRequestsInfoForTenant(t *testing.T) {
 			PropertyID:    primitive.NewObjectID(),
 			TenantName:    tenantName,
 			LandlordName:  "landlord1",
-			RequestStatus: "pending",
+			RequestStatus: entities.RequestPending,
 			CreatedAt:     time.Now(),
 		},
 		{
 			PropertyID:    primitive.NewObjectID(),
 			TenantName:    tenantName,
 			LandlordName:  "landlord2",
-			RequestStatus: "approved",
+			RequestStatus: entities.RequestAccepted,
 			CreatedAt:     time.Now(),
 		},
 	}
@@ -210,7 +491,7 @@ func TestRequestService_GetRentRequestsInfoForTenant(t *testing.T) {
 				Return(tt.mockReturn, tt.mockError).
 				Times(1)
 
-			result, err := rentRequestService.GetRentRequestsInfoForTenant(tenantName)
+			result, err := rentRequestService.GetRentRequestsInfoForTenant(context.Background(), newTestSession(tenantName, entities.RoleTenant))
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -223,53 +504,115 @@ func TestRequestService_GetRentRequestsInfoForTenant(t *testing.T) {
 	}
 }
 
+func TestRequestService_CreateRentRequest_RequiresTenantPermission(t *testing.T) {
+	cleanup := setup3(t)
+	defer cleanup()
+
+	err := rentRequestService.CreateRentRequest(context.Background(), newTestSession("landlord1", entities.RoleLandlord), primitive.NewObjectID())
+	assert.ErrorIs(t, err, services.ErrForbidden)
+
+	_, err = rentRequestService.GetRentRequestsInfoForTenant(context.Background(), newTestSession("landlord1", entities.RoleLandlord))
+	assert.ErrorIs(t, err, services.ErrForbidden)
+}
+
 func TestRequestService_CreateRentRequest(t *testing.T) {
 	cleanup := setup3(t)
 	defer cleanup()
 
 	tenantName := "tenant1"
-	propertyID := primitive.NewObjectID()
-	landlordName := "landlord1"
+	property := &entities.Property{ID: primitive.NewObjectID(), LandlordUsername: "landlord1", IsApprovedByAdmin: true}
 
 	tests := []struct {
 		name          string
+		created       bool
 		mockError     error
-		expectedError bool
+		expectedError error
 	}{
 		{
-			name:          "Successful creation",
-			mockError:     nil,
-			expectedError: false,
+			name:    "Successful creation",
+			created: true,
+		},
+		{
+			name:          "Open request exists",
+			created:       false,
+			expectedError: services.ErrDuplicateRequest,
 		},
 		{
 			name:          "Error during creation",
 			mockError:     errors.New("save error"),
-			expectedError: true,
+			expectedError: errors.New("save error"),
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			expectedRequest := entities.Request{
-				PropertyID:    propertyID,
-				TenantName:    tenantName,
-				LandlordName:  landlordName,
-				RequestStatus: "pending",
-				CreatedAt:     time.Now(), // We can't precisely match time, but we'll compare the other fields.
-			}
-
+			mockPropertyRepo.EXPECT().FindByID(gomock.Any(), property.ID).Return(property, nil)
 			mockRentRequestRepo.EXPECT().
-				SaveRequest(gomock.AssignableToTypeOf(expectedRequest)).
-				Return(tt.mockError).
-				Times(1)
+				CreateRequest(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, request entities.Request) (bool, error) {
+					// The tenant is taken from the session and the landlord from the property
+					assert.Equal(t, property.ID, request.PropertyID)
+					assert.Equal(t, tenantName, request.TenantName)
+					assert.Equal(t, "landlord1", request.LandlordName)
+					assert.Equal(t, entities.RequestPending, request.RequestStatus)
+					if assert.Len(t, request.History, 1) {
+						assert.Equal(t, entities.RequestPending, request.History[0].To)
+						assert.Equal(t, tenantName, request.History[0].ChangedBy)
+					}
+					return tt.created, tt.mockError
+				})
 
-			err := rentRequestService.CreateRentRequest(tenantName, propertyID, landlordName)
+			err := rentRequestService.CreateRentRequest(context.Background(), newTestSession(tenantName, entities.RoleTenant), property.ID)
 
-			if tt.expectedError {
-				assert.Error(t, err)
-			} else {
+			switch {
+			case tt.expectedError == nil:
 				assert.NoError(t, err)
+			case tt.mockError != nil:
+				assert.EqualError(t, err, tt.expectedError.Error())
+			default:
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.ErrorIs(t, err, services.ErrRequestNotAllowed)
+			}
+		})
+	}
+}
+
+func TestRequestService_CreateRentRequest_Rules(t *testing.T) {
+	cleanup := setup3(t)
+	defer cleanup()
+
+	session := newTestSession("tenant1", entities.RoleUser)
+
+	tests := []struct {
+		name     string
+		property entities.Property
+		expected error
+	}{
+		{name: "Own property", property: entities.Property{LandlordUsername: "tenant1", IsApprovedByAdmin: true}, expected: services.ErrOwnProperty},
+		{name: "Unapproved property", property: entities.Property{LandlordUsername: "landlord1"}, expected: services.ErrPropertyNotApproved},
+		{name: "Rented property", property: entities.Property{LandlordUsername: "landlord1", IsApprovedByAdmin: true, IsRented: true}, expected: services.ErrPropertyRented},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property := tt.property
+			property.ID = primitive.NewObjectID()
+			mockPropertyRepo.EXPECT().FindByID(gomock.Any(), property.ID).Return(&property, nil)
+
+			err := rentRequestService.CreateRentRequest(context.Background(), session, property.ID)
+			assert.ErrorIs(t, err, tt.expected)
+			var notAllowed *services.RequestNotAllowedError
+			if assert.ErrorAs(t, err, &notAllowed) {
+				assert.Equal(t, property.ID, notAllowed.PropertyID)
 			}
 		})
 	}
+
+	t.Run("Unknown property", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		mockPropertyRepo.EXPECT().FindByID(gomock.Any(), id).Return(nil, nil)
+
+		err := rentRequestService.CreateRentRequest(context.Background(), session, id)
+		assert.ErrorIs(t, err, services.ErrPropertyNotFound)
+	})
 }
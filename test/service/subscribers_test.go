@@ -0,0 +1,79 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"rentease/internal/app/services"
+	"rentease/internal/domain/entities"
+	"rentease/internal/domain/events"
+	mocks_interfaces "rentease/test/mocks/repository"
+)
+
+func TestServices_PublishEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	publisher := mocks_interfaces.NewMockEventPublisher(ctrl)
+	userRepo := mocks_interfaces.NewMockUserRepo(ctrl)
+	propertyRepo := mocks_interfaces.NewMockPropertyRepo(ctrl)
+	refreshTokenRepo := mocks_interfaces.NewMockRefreshTokenRepo(ctrl)
+	userService := services.NewUserService(userRepo, propertyRepo, refreshTokenRepo, publisher)
+	admin := newTestSession("admin", entities.RoleAdmin)
+
+	userRepo.EXPECT().FindByUsername(gomock.Any(), "testuser").Return(&entities.User{Username: "testuser", Role: entities.RoleUser}, nil)
+	refreshTokenRepo.EXPECT().RevokeUserRefreshTokens(gomock.Any(), "testuser").Return(nil)
+	propertyRepo.EXPECT().DeleteAllListedPropertiesOfaUser(gomock.Any(), "testuser").Return(nil)
+	gomock.InOrder(
+		userRepo.EXPECT().Delete(gomock.Any(), "testuser").Return(nil),
+		publisher.EXPECT().Publish(gomock.Any(), events.UserDeleted{Username: "testuser", By: "admin"}).Return(nil),
+	)
+	require.NoError(t, userService.DeleteUser(context.Background(), admin, "testuser"))
+
+	// A user who could not be deleted is not announced as deleted
+	userRepo.EXPECT().FindByUsername(gomock.Any(), "testuser").Return(&entities.User{Username: "testuser", Role: entities.RoleUser}, nil)
+	refreshTokenRepo.EXPECT().RevokeUserRefreshTokens(gomock.Any(), "testuser").Return(nil)
+	propertyRepo.EXPECT().DeleteAllListedPropertiesOfaUser(gomock.Any(), "testuser").Return(nil)
+	userRepo.EXPECT().Delete(gomock.Any(), "testuser").Return(errors.New("repository error"))
+	assert.ErrorContains(t, userService.DeleteUser(context.Background(), admin, "testuser"), "repository error")
+
+	// Nothing is published when the change fails
+	userRepo.EXPECT().FindByUsername(gomock.Any(), "testuser").Return(&entities.User{Username: "testuser", Role: entities.RoleUser}, nil)
+	userRepo.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).Return(errors.New("repository error"))
+	assert.Error(t, userService.SetRole(context.Background(), admin, "testuser", entities.RoleTenant))
+
+	// A failing subscriber fails the call, though the change has been made
+	userRepo.EXPECT().FindByUsername(gomock.Any(), "testuser").Return(&entities.User{Username: "testuser", Role: entities.RoleUser}, nil)
+	userRepo.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).Return(nil)
+	publisher.EXPECT().Publish(gomock.Any(), events.UserRoleChanged{Username: "testuser", Role: entities.RoleTenant, By: "admin"}).Return(errors.New("subscriber error"))
+	assert.ErrorContains(t, userService.SetRole(context.Background(), admin, "testuser", entities.RoleTenant), "subscriber error")
+
+	requestRepo := mocks_interfaces.NewMockRequestRepo(ctrl)
+	requestService := services.NewRequestService(requestRepo, propertyRepo, mocks_interfaces.NewMockLeaseRepo(ctrl), publisher)
+	request := newStoredRequest(entities.RequestPending)
+	requestRepo.EXPECT().FindRequestByID(gomock.Any(), request.ID).Return(request, nil)
+	requestRepo.EXPECT().UpdateRequestStatus(gomock.Any(), request.ID, gomock.Any()).Return(true, nil)
+	publisher.EXPECT().Publish(gomock.Any(), events.RentRequestRejected{
+		RequestID:  request.ID,
+		PropertyID: request.PropertyID,
+		Tenant:     request.TenantName,
+		Landlord:   request.LandlordName,
+		By:         "landlord1",
+	}).Return(nil)
+	require.NoError(t, requestService.UpdateRequestStatus(context.Background(), newTestSession("landlord1", entities.RoleLandlord), request.ID, entities.RequestRejected))
+}
+
+func TestSubscribeEmails_FailuresAreNotReturned(t *testing.T) {
+	cleanup := setupNotifications(t)
+	defer cleanup()
+
+	mockEmailNotifier.EXPECT().Notify(gomock.Any(), entities.EmailNotice{Kind: entities.EmailWelcome, Username: "newuser"}).Return(errors.New("queue full"))
+	assert.NoError(t, eventBus.Publish(context.Background(), events.UserSignedUp{Username: "newuser"}))
+
+	// Notifications are best effort too
+	mockNotificationRepo.EXPECT().SaveNotification(gomock.Any(), gomock.Any()).Return(errors.New("repository error"))
+	assert.NoError(t, eventBus.Publish(context.Background(), events.UserRoleChanged{Username: "testuser", Role: entities.RoleTenant, By: "admin"}))
+}
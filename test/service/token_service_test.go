@@ -0,0 +1,214 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"rentease/internal/app/services"
+	"rentease/internal/domain/entities"
+	mocks_interfaces "rentease/test/mocks/repository"
+)
+
+var testTokenSecret = []byte("test-secret-of-at-least-32-bytes")
+
+func newTokenService(t *testing.T, accessTTL time.Duration) (*services.TokenService, *mocks_interfaces.MockUserRepo, *mocks_interfaces.MockRefreshTokenRepo) {
+	ctrl := gomock.NewController(t)
+	userRepo := mocks_interfaces.NewMockUserRepo(ctrl)
+	tokenRepo := mocks_interfaces.NewMockRefreshTokenRepo(ctrl)
+	return services.NewTokenService(userRepo, tokenRepo, testTokenSecret, accessTTL, time.Hour), userRepo, tokenRepo
+}
+
+// issueTokens issues tokens for alice and returns them with the refresh token record that was saved.
+func issueTokens(t *testing.T, ts *services.TokenService, tokenRepo *mocks_interfaces.MockRefreshTokenRepo) (entities.TokenPair, *entities.RefreshToken) {
+	var saved entities.RefreshToken
+	tokenRepo.EXPECT().SaveRefreshToken(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, token entities.RefreshToken) error {
+			saved = token
+			return nil
+		})
+
+	tokens, err := ts.Issue(context.Background(), newTestSession("alice", "User"))
+	require.NoError(t, err)
+	return tokens, &saved
+}
+
+func TestTokenService_Issue(t *testing.T) {
+	ts, _, tokenRepo := newTokenService(t, 15*time.Minute)
+	tokens, saved := issueTokens(t, ts, tokenRepo)
+
+	assert.Equal(t, "alice", saved.Username)
+	assert.False(t, saved.Revoked)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), saved.ExpiresAt, time.Minute)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), tokens.AccessExpiresAt, time.Minute)
+	assert.Equal(t, saved.ExpiresAt, tokens.RefreshExpiresAt)
+	assert.Len(t, strings.Split(tokens.AccessToken, "."), 3)
+
+	// Only a hash of the refresh secret is stored
+	assert.True(t, strings.HasPrefix(tokens.RefreshToken, saved.ID+"."))
+	assert.NotContains(t, tokens.RefreshToken, saved.TokenHash)
+
+	t.Run("Expired session", func(t *testing.T) {
+		session := newTestSession("alice", "User")
+		session.ExpiresAt = time.Now().Add(-time.Minute)
+		_, err := ts.Issue(context.Background(), session)
+		assert.ErrorIs(t, err, services.ErrSessionExpired)
+	})
+}
+
+func TestTokenService_Authenticate(t *testing.T) {
+	ts, userRepo, tokenRepo := newTokenService(t, 15*time.Minute)
+	tokens, saved := issueTokens(t, ts, tokenRepo)
+
+	t.Run("Valid token resolves the current user and role", func(t *testing.T) {
+		tokenRepo.EXPECT().FindRefreshToken(gomock.Any(), saved.ID).Return(saved, nil)
+		userRepo.EXPECT().FindByUsername(gomock.Any(), "alice").Return(&entities.User{Username: "alice", Role: "Admin"}, nil)
+
+		session, err := ts.Authenticate(context.Background(), tokens.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, saved.ID, session.ID)
+		assert.Equal(t, "alice", session.Username())
+		assert.Equal(t, "Admin", session.Role)
+		assert.Equal(t, tokens.AccessExpiresAt, session.ExpiresAt)
+	})
+
+	t.Run("Revoked login", func(t *testing.T) {
+		revoked := *saved
+		revoked.Revoked = true
+		tokenRepo.EXPECT().FindRefreshToken(gomock.Any(), saved.ID).Return(&revoked, nil)
+
+		_, err := ts.Authenticate(context.Background(), tokens.AccessToken)
+		assert.ErrorIs(t, err, services.ErrTokenRevoked)
+	})
+
+	t.Run("Deleted user", func(t *testing.T) {
+		tokenRepo.EXPECT().FindRefreshToken(gomock.Any(), saved.ID).Return(saved, nil)
+		userRepo.EXPECT().FindByUsername(gomock.Any(), "alice").Return(nil, nil)
+
+		_, err := ts.Authenticate(context.Background(), tokens.AccessToken)
+		assert.ErrorIs(t, err, services.ErrInvalidToken)
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		tokenRepo.EXPECT().FindRefreshToken(gomock.Any(), saved.ID).Return(nil, errors.New("database error"))
+
+		_, err := ts.Authenticate(context.Background(), tokens.AccessToken)
+		assert.EqualError(t, err, "database error")
+	})
+
+	t.Run("Malformed or forged tokens", func(t *testing.T) {
+		parts := strings.Split(tokens.AccessToken, ".")
+		other := services.NewTokenService(userRepo, tokenRepo, []byte("another-secret-of-at-least-32-bytes"), time.Minute, time.Hour)
+		forged, _ := issueTokens(t, other, tokenRepo)
+
+		for name, token := range map[string]string{
+			"Empty":            "",
+			"Not a JWT":        "not-a-token",
+			"Algorithm none":   "eyJhbGciOiJub25lIiwidHlwIjoiSldUIn0." + parts[1] + ".",
+			"Changed payload":  parts[0] + "." + parts[1] + "e30." + parts[2],
+			"Wrong secret":     forged.AccessToken,
+			"Broken signature": parts[0] + "." + parts[1] + ".!!!",
+		} {
+			_, err := ts.Authenticate(context.Background(), token)
+			assert.ErrorIs(t, err, services.ErrInvalidToken, name)
+		}
+	})
+}
+
+func TestTokenService_AuthenticateExpired(t *testing.T) {
+	ts, _, tokenRepo := newTokenService(t, time.Nanosecond)
+	tokens, _ := issueTokens(t, ts, tokenRepo)
+
+	_, err := ts.Authenticate(context.Background(), tokens.AccessToken)
+	assert.ErrorIs(t, err, services.ErrTokenExpired)
+}
+
+func TestTokenService_Refresh(t *testing.T) {
+	t.Run("Rotates the refresh secret", func(t *testing.T) {
+		ts, userRepo, tokenRepo := newTokenService(t, 15*time.Minute)
+		tokens, saved := issueTokens(t, ts, tokenRepo)
+		stored := *saved
+		tokenRepo.EXPECT().FindRefreshToken(gomock.Any(), saved.ID).Return(&stored, nil)
+		userRepo.EXPECT().FindByUsername(gomock.Any(), "alice").Return(&entities.User{Username: "alice", Role: "User"}, nil)
+		var rotated entities.RefreshToken
+		tokenRepo.EXPECT().RotateRefreshToken(gomock.Any(), gomock.Any(), saved.TokenHash).DoAndReturn(
+			func(ctx context.Context, token entities.RefreshToken, previousHash string) (bool, error) {
+				rotated = token
+				return true, nil
+			})
+
+		refreshed, err := ts.Refresh(context.Background(), tokens.RefreshToken)
+		require.NoError(t, err)
+		assert.Equal(t, saved.ID, rotated.ID)
+		assert.Equal(t, saved.IssuedAt, rotated.IssuedAt)
+		assert.NotEqual(t, saved.TokenHash, rotated.TokenHash)
+		assert.NotEqual(t, tokens.RefreshToken, refreshed.RefreshToken)
+		assert.True(t, strings.HasPrefix(refreshed.RefreshToken, saved.ID+"."))
+	})
+
+	t.Run("Concurrent refresh with the same token", func(t *testing.T) {
+		ts, userRepo, tokenRepo := newTokenService(t, 15*time.Minute)
+		tokens, saved := issueTokens(t, ts, tokenRepo)
+		stored := *saved
+		tokenRepo.EXPECT().FindRefreshToken(gomock.Any(), saved.ID).Return(&stored, nil)
+		userRepo.EXPECT().FindByUsername(gomock.Any(), "alice").Return(&entities.User{Username: "alice", Role: "User"}, nil)
+		// The other refresh rotated the token between the check and the update
+		tokenRepo.EXPECT().RotateRefreshToken(gomock.Any(), gomock.Any(), saved.TokenHash).Return(false, nil)
+
+		_, err := ts.Refresh(context.Background(), tokens.RefreshToken)
+		assert.ErrorIs(t, err, services.ErrInvalidToken)
+	})
+
+	t.Run("Reused refresh token revokes the login", func(t *testing.T) {
+		ts, _, tokenRepo := newTokenService(t, 15*time.Minute)
+		tokens, saved := issueTokens(t, ts, tokenRepo)
+		rotated := *saved
+		rotated.TokenHash = "hash-of-a-newer-secret"
+		tokenRepo.EXPECT().FindRefreshToken(gomock.Any(), saved.ID).Return(&rotated, nil)
+		tokenRepo.EXPECT().RevokeRefreshToken(gomock.Any(), saved.ID).Return(nil)
+
+		_, err := ts.Refresh(context.Background(), tokens.RefreshToken)
+		assert.ErrorIs(t, err, services.ErrTokenRevoked)
+	})
+
+	t.Run("Expired refresh token", func(t *testing.T) {
+		ts, _, tokenRepo := newTokenService(t, 15*time.Minute)
+		tokens, saved := issueTokens(t, ts, tokenRepo)
+		expired := *saved
+		expired.ExpiresAt = time.Now().Add(-time.Minute)
+		tokenRepo.EXPECT().FindRefreshToken(gomock.Any(), saved.ID).Return(&expired, nil)
+
+		_, err := ts.Refresh(context.Background(), tokens.RefreshToken)
+		assert.ErrorIs(t, err, services.ErrTokenExpired)
+	})
+
+	t.Run("Unknown refresh token", func(t *testing.T) {
+		ts, _, tokenRepo := newTokenService(t, 15*time.Minute)
+		tokenRepo.EXPECT().FindRefreshToken(gomock.Any(), "unknown").Return(nil, nil)
+
+		_, err := ts.Refresh(context.Background(), "unknown.secret")
+		assert.ErrorIs(t, err, services.ErrInvalidToken)
+		_, err = ts.Refresh(context.Background(), "no-separator")
+		assert.ErrorIs(t, err, services.ErrInvalidToken)
+	})
+}
+
+func TestTokenService_Revoke(t *testing.T) {
+	ts, _, tokenRepo := newTokenService(t, 15*time.Minute)
+
+	session := newTestSession("alice", "User")
+	session.ID = "session-1"
+	tokenRepo.EXPECT().RevokeRefreshToken(gomock.Any(), "session-1").Return(nil)
+	assert.NoError(t, ts.Revoke(context.Background(), session))
+
+	// Terminal sessions have no tokens to revoke
+	assert.ErrorIs(t, ts.Revoke(context.Background(), newTestSession("alice", "User")), services.ErrNotLoggedIn)
+
+	tokenRepo.EXPECT().RevokeUserRefreshTokens(gomock.Any(), "alice").Return(nil)
+	assert.NoError(t, ts.RevokeAll(context.Background(), "alice"))
+}
@@ -1,6 +1,7 @@
 package service_test
 
 import (
+	"context"
 	"errors"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -8,26 +9,29 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 	"rentease/internal/app/services"
 	"rentease/internal/domain/entities"
-	"rentease/pkg/utils"
 	mocks_interfaces "rentease/test/mocks/repository"
 	"testing"
+	"time"
 )
 
 var (
-	ctrl         *gomock.Controller
-	mockUserRepo *mocks_interfaces.MockUserRepo
-	userService  *services.UserService
+	ctrl                 *gomock.Controller
+	mockUserRepo         *mocks_interfaces.MockUserRepo
+	mockRefreshTokenRepo *mocks_interfaces.MockRefreshTokenRepo
+	userService          *services.UserService
 )
 
 func setup(t *testing.T) func() {
 	// Set up the gomock controller
 	ctrl = gomock.NewController(t)
 
-	// Create a mock UserRepository
+	// Create the mock repositories
 	mockUserRepo = mocks_interfaces.NewMockUserRepo(ctrl)
+	mockPropertyRepo = mocks_interfaces.NewMockPropertyRepo(ctrl)
+	mockRefreshTokenRepo = mocks_interfaces.NewMockRefreshTokenRepo(ctrl)
 
-	// Initialize the UserService with the mock repository
-	userService = services.NewUserService(mockUserRepo)
+	// Initialize the UserService with the mock repositories
+	userService = services.NewUserService(mockUserRepo, mockPropertyRepo, mockRefreshTokenRepo, ignoreEvents(ctrl))
 
 	// Return a cleanup function to be called at the end of the test
 	return func() {
@@ -35,6 +39,17 @@ func setup(t *testing.T) func() {
 	}
 }
 
+// newTestSession returns a session for the user that is valid for an hour.
+func newTestSession(username, role string) *entities.Session {
+	now := time.Now()
+	return &entities.Session{
+		User:      entities.User{Username: username, Role: role},
+		Role:      role,
+		LoginTime: now,
+		ExpiresAt: now.Add(time.Hour),
+	}
+}
+
 func TestUserService_SignUp(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -92,10 +107,10 @@ func TestUserService_SignUp(t *testing.T) {
 
 			// If no existing user is found, mock SaveUser behavior and check if correct user data is passed
 			if tt.mockFindError == mongo.ErrNoDocuments {
-				mockUserRepo.EXPECT().SaveUser(tt.newUser).Return(tt.mockSaveError).Times(1)
+				mockUserRepo.EXPECT().SaveUser(gomock.Any(), tt.newUser).Return(tt.mockSaveError).Times(1)
 			}
 
-			err := userService.SignUp(tt.newUser)
+			err := userService.SignUp(context.Background(), tt.newUser)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -106,6 +121,21 @@ func TestUserService_SignUp(t *testing.T) {
 	}
 }
 
+func TestUserService_SignUp_UsernameTaken(t *testing.T) {
+	teardown := setup(t)
+	defer teardown()
+
+	// The taken username is turned down before anything is saved
+	mockUserRepo.EXPECT().FindByUsername(gomock.Any(), "testuser").Return(&entities.User{Username: "testuser"}, nil)
+	err := userService.SignUp(context.Background(), entities.User{Username: "testuser", Email: "other@example.com"})
+	assert.ErrorIs(t, err, services.ErrUsernameTaken)
+
+	// A failed lookup is not taken for a free username
+	mockUserRepo.EXPECT().FindByUsername(gomock.Any(), "testuser").Return(nil, errors.New("repository error"))
+	err = userService.SignUp(context.Background(), entities.User{Username: "testuser"})
+	assert.EqualError(t, err, "repository error")
+}
+
 func TestUserService_FindByUsername(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -147,7 +177,7 @@ func TestUserService_FindByUsername(t *testing.T) {
 
 			mockUserRepo.EXPECT().FindByUsername(gomock.Any(), tt.username).Return(tt.mockUser, tt.mockRepoError).Times(1)
 
-			user, err := userService.FindByUsername(tt.username)
+			user, err := userService.FindByUsername(context.Background(), tt.username)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -171,8 +201,8 @@ func TestUserService_Login(t *testing.T) {
 		password      string
 		mockExist     bool
 		mockRepoError error
-		expectedError bool
-		expectedExist bool
+		mockUser      *entities.User
+		expectedError error
 	}{
 		{
 			name:          "Successful Login",
@@ -180,8 +210,8 @@ func TestUserService_Login(t *testing.T) {
 			password:      "correctpassword",
 			mockExist:     true,
 			mockRepoError: nil,
-			expectedError: false,
-			expectedExist: true,
+			mockUser:      &entities.User{Username: "testuser", Role: "tenant"},
+			expectedError: nil,
 		},
 		{
 			name:          "Wrong Password",
@@ -189,8 +219,7 @@ func TestUserService_Login(t *testing.T) {
 			password:      "wrongpassword",
 			mockExist:     false,
 			mockRepoError: nil,
-			expectedError: false,
-			expectedExist: false,
+			expectedError: services.ErrInvalidCredentials,
 		},
 		{
 			name:          "Repository Error",
@@ -198,8 +227,7 @@ func TestUserService_Login(t *testing.T) {
 			password:      "anyPassword",
 			mockExist:     false,
 			mockRepoError: errors.New("repository error"),
-			expectedError: true,
-			expectedExist: false,
+			expectedError: errors.New("repository error"),
 		},
 	}
 
@@ -209,17 +237,25 @@ func TestUserService_Login(t *testing.T) {
 			defer teardown()
 
 			mockUserRepo.EXPECT().CheckPassword(gomock.Any(), tt.username, tt.password).Return(tt.mockExist, tt.mockRepoError).Times(1)
+			if tt.mockUser != nil {
+				mockUserRepo.EXPECT().FindByUsername(gomock.Any(), tt.username).Return(tt.mockUser, nil).Times(1)
+			}
 
-			success, err := userService.Login(tt.username, tt.password)
+			before := time.Now()
+			session, err := userService.Login(context.Background(), tt.username, tt.password)
 
-			if tt.expectedError {
-				assert.Error(t, err)
-				assert.False(t, success)
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+				assert.Nil(t, session)
 			} else {
 				assert.NoError(t, err)
-				assert.Equal(t, tt.expectedExist, success)
-				if success {
-					assert.Equal(t, tt.username, utils.ActiveUser)
+				if assert.NotNil(t, session) {
+					assert.Equal(t, *tt.mockUser, session.User)
+					assert.Equal(t, tt.username, session.Username())
+					assert.Equal(t, tt.mockUser.Role, session.Role)
+					assert.False(t, session.LoginTime.Before(before))
+					assert.Equal(t, session.LoginTime.Add(services.SessionDuration), session.ExpiresAt)
+					assert.False(t, session.IsExpired(time.Now()))
 				}
 			}
 		})
@@ -288,12 +324,12 @@ func TestUserService_AddToWishlist(t *testing.T) {
 
 			// Setup the expectations for UpdateUser if needed
 			if tt.mockUser != nil && tt.mockRepoError == nil && !tt.expectedError {
-				mockUserRepo.EXPECT().UpdateUser(gomock.Any()).Return(nil).Times(1)
+				mockUserRepo.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).Return(nil).Times(1)
 			} else if tt.mockRepoError == nil && tt.expectedError {
-				mockUserRepo.EXPECT().UpdateUser(gomock.Any()).Return(errors.New("update user error")).Times(1)
+				mockUserRepo.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).Return(errors.New("update user error")).Times(1)
 			}
 
-			err := userService.AddToWishlist(tt.username, tt.propertyID)
+			err := userService.AddToWishlist(context.Background(), newTestSession(tt.username, entities.RoleTenant), tt.propertyID)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -337,10 +373,11 @@ func TestUserService_UpdateUser(t *testing.T) {
 			defer teardown()
 
 			// Set up the mock expectation
-			mockUserRepo.EXPECT().UpdateUser(tt.user).Return(tt.mockRepoError).Times(1)
+			mockUserRepo.EXPECT().FindByUsername(gomock.Any(), tt.user.Username).Return(&tt.user, nil)
+			mockUserRepo.EXPECT().UpdateUser(gomock.Any(), tt.user).Return(tt.mockRepoError).Times(1)
 
 			// Call the UpdateUser method
-			err := userService.UpdateUser(tt.user)
+			err := userService.UpdateUser(context.Background(), newTestSession(tt.user.Username, entities.RoleUser), tt.user)
 
 			// Assert the results
 			if tt.expectedError {
@@ -352,6 +389,32 @@ func TestUserService_UpdateUser(t *testing.T) {
 	}
 }
 
+func TestUserService_UpdateUser_Authorization(t *testing.T) {
+	teardown := setup(t)
+	defer teardown()
+
+	stored := entities.User{Username: "testuser", Name: "Test", Role: entities.RoleUser}
+
+	// Users cannot update others
+	err := userService.UpdateUser(context.Background(), newTestSession("other", entities.RoleUser), stored)
+	assert.ErrorIs(t, err, services.ErrForbidden)
+
+	// Nor promote themselves
+	promoted := stored
+	promoted.Name = "Renamed"
+	promoted.Role = entities.RoleAdmin
+	expected := promoted
+	expected.Role = entities.RoleUser
+	mockUserRepo.EXPECT().FindByUsername(gomock.Any(), "testuser").Return(&stored, nil)
+	mockUserRepo.EXPECT().UpdateUser(gomock.Any(), expected).Return(nil)
+	assert.NoError(t, userService.UpdateUser(context.Background(), newTestSession("testuser", entities.RoleUser), promoted))
+
+	// Admins can update anyone
+	mockUserRepo.EXPECT().FindByUsername(gomock.Any(), "testuser").Return(&stored, nil)
+	mockUserRepo.EXPECT().UpdateUser(gomock.Any(), stored).Return(nil)
+	assert.NoError(t, userService.UpdateUser(context.Background(), newTestSession("admin", entities.RoleAdmin), stored))
+}
+
 func TestUserService_GetAllUsers(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -388,10 +451,10 @@ func TestUserService_GetAllUsers(t *testing.T) {
 			defer teardown()
 
 			// Set up the mock expectation
-			mockUserRepo.EXPECT().FindAll().Return(tt.mockUsers, tt.mockRepoError).Times(1)
+			mockUserRepo.EXPECT().FindAll(gomock.Any()).Return(tt.mockUsers, tt.mockRepoError).Times(1)
 
 			// Call the GetAllUsers method
-			users, err := userService.GetAllUsers()
+			users, err := userService.GetAllUsers(context.Background(), newTestSession("admin", entities.RoleAdmin))
 
 			// Assert the results
 			if tt.expectedError {
@@ -409,20 +472,33 @@ func TestUserService_DeleteUser(t *testing.T) {
 	tests := []struct {
 		name          string
 		username      string
+		revokeError   error
+		propertyError error
 		mockRepoError error
-		expectedError bool
+		expectedError string
 	}{
 		{
-			name:          "Successful Deletion",
-			username:      "testuser",
-			mockRepoError: nil,
-			expectedError: false,
+			name:     "Successful Deletion",
+			username: "testuser",
 		},
 		{
 			name:          "Repository Error",
 			username:      "testuser",
 			mockRepoError: errors.New("repository error"),
-			expectedError: true,
+			expectedError: "repository error",
+		},
+		{
+			// What belongs to the user goes first, so a failure leaves them to be deleted again
+			name:          "Properties Not Deleted",
+			username:      "testuser",
+			propertyError: errors.New("repository error"),
+			expectedError: "failed to delete the properties of testuser: repository error",
+		},
+		{
+			name:          "Logins Not Revoked",
+			username:      "testuser",
+			revokeError:   errors.New("repository error"),
+			expectedError: "failed to revoke the logins of testuser: repository error",
 		},
 	}
 
@@ -431,18 +507,61 @@ func TestUserService_DeleteUser(t *testing.T) {
 			teardown := setup(t)
 			defer teardown()
 
-			// Set up the mock expectation
-			mockUserRepo.EXPECT().Delete(tt.username).Return(tt.mockRepoError).Times(1)
+			// Set up the mock expectations
+			mockUserRepo.EXPECT().FindByUsername(gomock.Any(), tt.username).Return(&entities.User{Username: tt.username, Role: entities.RoleUser}, nil)
+			mockRefreshTokenRepo.EXPECT().RevokeUserRefreshTokens(gomock.Any(), tt.username).Return(tt.revokeError)
+			if tt.revokeError == nil {
+				mockPropertyRepo.EXPECT().DeleteAllListedPropertiesOfaUser(gomock.Any(), tt.username).Return(tt.propertyError)
+			}
+			if tt.revokeError == nil && tt.propertyError == nil {
+				mockUserRepo.EXPECT().Delete(gomock.Any(), tt.username).Return(tt.mockRepoError).Times(1)
+			}
 
 			// Call the DeleteUser method
-			err := userService.DeleteUser(tt.username)
+			err := userService.DeleteUser(context.Background(), newTestSession("admin", entities.RoleAdmin), tt.username)
 
 			// Assert the results
-			if tt.expectedError {
-				assert.Error(t, err)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
 			} else {
 				assert.NoError(t, err)
 			}
 		})
 	}
 }
+
+func TestUserService_DeleteUser_Authorization(t *testing.T) {
+	teardown := setup(t)
+	defer teardown()
+	admin := newTestSession("admin", entities.RoleAdmin)
+
+	// Only admins can delete users
+	for _, role := range []string{entities.RoleUser, entities.RoleTenant, entities.RoleLandlord, entities.RoleModerator} {
+		err := userService.DeleteUser(context.Background(), newTestSession("caller", role), "testuser")
+		assert.ErrorIs(t, err, services.ErrForbidden, role)
+	}
+
+	// Admins cannot be deleted
+	mockUserRepo.EXPECT().FindByUsername(gomock.Any(), "admin2").Return(&entities.User{Username: "admin2", Role: entities.RoleAdmin}, nil)
+	assert.ErrorIs(t, userService.DeleteUser(context.Background(), admin, "admin2"), services.ErrForbidden)
+
+	mockUserRepo.EXPECT().FindByUsername(gomock.Any(), "nobody").Return(nil, nil)
+	assert.ErrorIs(t, userService.DeleteUser(context.Background(), admin, "nobody"), services.ErrUserNotFound)
+}
+
+func TestUserService_SetRole(t *testing.T) {
+	teardown := setup(t)
+	defer teardown()
+	admin := newTestSession("admin", entities.RoleAdmin)
+
+	mockUserRepo.EXPECT().FindByUsername(gomock.Any(), "testuser").Return(&entities.User{Username: "testuser", Role: entities.RoleUser}, nil)
+	mockUserRepo.EXPECT().UpdateUser(gomock.Any(), entities.User{Username: "testuser", Role: entities.RoleModerator}).Return(nil)
+	assert.NoError(t, userService.SetRole(context.Background(), admin, "testuser", entities.RoleModerator))
+
+	assert.ErrorIs(t, userService.SetRole(context.Background(), admin, "testuser", "Superuser"), services.ErrInvalidRole)
+	assert.ErrorIs(t, userService.SetRole(context.Background(), admin, "admin", entities.RoleUser), services.ErrForbidden)
+	assert.ErrorIs(t, userService.SetRole(context.Background(), newTestSession("moderator", entities.RoleModerator), "testuser", entities.RoleAdmin), services.ErrForbidden)
+
+	mockUserRepo.EXPECT().FindByUsername(gomock.Any(), "nobody").Return(nil, nil)
+	assert.ErrorIs(t, userService.SetRole(context.Background(), admin, "nobody", entities.RoleTenant), services.ErrUserNotFound)
+}
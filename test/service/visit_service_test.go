@@ -0,0 +1,378 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"rentease/internal/app/services"
+	"rentease/internal/domain/entities"
+	mocks_interfaces "rentease/test/mocks/repository"
+)
+
+var (
+	mockVisitRepo *mocks_interfaces.MockVisitRepo
+	visitService  *services.VisitService
+)
+
+func setupVisits(t *testing.T) func() {
+	ctrl := gomock.NewController(t)
+	mockVisitRepo = mocks_interfaces.NewMockVisitRepo(ctrl)
+	mockPropertyRepo = mocks_interfaces.NewMockPropertyRepo(ctrl)
+	visitService = services.NewVisitService(mockVisitRepo, mockPropertyRepo)
+	return func() {
+		ctrl.Finish()
+	}
+}
+
+// newVisitProperty returns an approved property of landlord1 that is not rented.
+func newVisitProperty() *entities.Property {
+	return &entities.Property{ID: primitive.NewObjectID(), Title: "Family House", LandlordUsername: "landlord1", IsApprovedByAdmin: true}
+}
+
+// newSlot returns a slot of the property starting in, booked by tenant unless it is empty.
+func newSlot(property *entities.Property, in time.Duration, tenant string) *entities.VisitSlot {
+	start := time.Now().Add(in).Truncate(time.Minute)
+	slot := &entities.VisitSlot{
+		ID:           primitive.NewObjectID(),
+		PropertyID:   property.ID,
+		LandlordName: property.LandlordUsername,
+		Start:        start,
+		End:          start.Add(30 * time.Minute),
+		Status:       entities.VisitOpen,
+	}
+	if tenant != "" {
+		slot.Status = entities.VisitBooked
+		slot.TenantName = tenant
+	}
+	return slot
+}
+
+func TestVisitService_PublishSlots(t *testing.T) {
+	cleanup := setupVisits(t)
+	defer cleanup()
+
+	property := newVisitProperty()
+	landlord := newTestSession("landlord1", entities.RoleLandlord)
+	tomorrow := time.Now().Add(24 * time.Hour).Truncate(time.Minute)
+
+	mockPropertyRepo.EXPECT().FindByID(gomock.Any(), property.ID).Return(property, nil)
+	mockVisitRepo.EXPECT().FindSlotsByLandlord(gomock.Any(), "landlord1").Return(nil, nil)
+	var saved []entities.VisitSlot
+	mockVisitRepo.EXPECT().SaveSlot(gomock.Any(), gomock.Any()).Times(2).
+		DoAndReturn(func(_ context.Context, slot entities.VisitSlot) error {
+			saved = append(saved, slot)
+			return nil
+		})
+	slots, err := visitService.PublishSlots(context.Background(), landlord, property.ID, []time.Time{tomorrow, tomorrow.Add(time.Hour)}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, saved, slots)
+	require.Len(t, slots, 2)
+	assert.Equal(t, entities.VisitOpen, slots[0].Status)
+	assert.Equal(t, services.DefaultVisitLength, slots[0].End.Sub(slots[0].Start))
+	assert.Equal(t, "landlord1", slots[1].LandlordName)
+
+	// A slot the landlord offers for another property at the same time overlaps
+	elsewhere := newSlot(newVisitProperty(), 24*time.Hour, "")
+	cancelled := newSlot(property, 48*time.Hour, "")
+	cancelled.Status = entities.VisitCancelled
+	rented := newVisitProperty()
+	rented.IsRented = true
+	tests := []struct {
+		name     string
+		session  *entities.Session
+		property *entities.Property
+		offered  []entities.VisitSlot
+		starts   []time.Time
+		length   time.Duration
+		expected error
+	}{
+		{name: "Tenant", session: newTestSession("tenant1", entities.RoleTenant), starts: []time.Time{tomorrow}, expected: services.ErrForbidden},
+		{name: "Too long", session: landlord, starts: []time.Time{tomorrow}, length: 5 * time.Hour, expected: services.ErrInvalidVisit},
+		{name: "No start", session: landlord, expected: services.ErrInvalidVisit},
+		{name: "Not the owner", session: newTestSession("landlord2", entities.RoleLandlord), property: property, starts: []time.Time{tomorrow}, expected: services.ErrForbidden},
+		{name: "Rented", session: landlord, property: rented, starts: []time.Time{tomorrow}, expected: services.ErrPropertyRented},
+		{name: "In the past", session: landlord, property: property, starts: []time.Time{time.Now().Add(-time.Hour)}, expected: services.ErrInvalidVisit},
+		{name: "Overlapping own slot", session: landlord, property: property, offered: []entities.VisitSlot{*elsewhere}, starts: []time.Time{elsewhere.Start.Add(10 * time.Minute)}, expected: services.ErrSlotOverlap},
+		{name: "Overlapping each other", session: landlord, property: property, starts: []time.Time{tomorrow, tomorrow.Add(15 * time.Minute)}, expected: services.ErrInvalidVisit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.property != nil {
+				mockPropertyRepo.EXPECT().FindByID(gomock.Any(), tt.property.ID).Return(tt.property, nil)
+				if tt.session == landlord && !tt.property.IsRented {
+					mockVisitRepo.EXPECT().FindSlotsByLandlord(gomock.Any(), "landlord1").Return(tt.offered, nil)
+				}
+			}
+			propertyID := primitive.NewObjectID()
+			if tt.property != nil {
+				propertyID = tt.property.ID
+			}
+			_, err := visitService.PublishSlots(context.Background(), tt.session, propertyID, tt.starts, tt.length)
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+
+	// A cancelled slot no longer holds its time
+	mockPropertyRepo.EXPECT().FindByID(gomock.Any(), property.ID).Return(property, nil)
+	mockVisitRepo.EXPECT().FindSlotsByLandlord(gomock.Any(), "landlord1").Return([]entities.VisitSlot{*cancelled}, nil)
+	mockVisitRepo.EXPECT().SaveSlot(gomock.Any(), gomock.Any()).Return(nil)
+	_, err = visitService.PublishSlots(context.Background(), landlord, property.ID, []time.Time{cancelled.Start}, 0)
+	assert.NoError(t, err)
+}
+
+func TestVisitService_VisitSlots(t *testing.T) {
+	cleanup := setupVisits(t)
+	defer cleanup()
+
+	property := newVisitProperty()
+	open := newSlot(property, 24*time.Hour, "")
+	booked := newSlot(property, 25*time.Hour, "tenant2")
+	started := newSlot(property, -10*time.Minute, "")
+	all := []entities.VisitSlot{*started, *open, *booked}
+
+	mockPropertyRepo.EXPECT().FindByID(gomock.Any(), property.ID).Return(property, nil).Times(2)
+	mockVisitRepo.EXPECT().FindSlotsByProperty(gomock.Any(), property.ID).Return(all, nil).Times(2)
+
+	slots, err := visitService.VisitSlots(context.Background(), newTestSession("landlord1", entities.RoleLandlord), property.ID)
+	require.NoError(t, err)
+	assert.Len(t, slots, 3)
+
+	// Others only see the slots they can still book
+	slots, err = visitService.VisitSlots(context.Background(), newTestSession("tenant1", entities.RoleTenant), property.ID)
+	require.NoError(t, err)
+	require.Len(t, slots, 1)
+	assert.Equal(t, open.ID, slots[0].ID)
+
+	_, err = visitService.VisitSlots(context.Background(), nil, property.ID)
+	assert.ErrorIs(t, err, services.ErrNotLoggedIn)
+}
+
+func TestVisitService_BookVisit(t *testing.T) {
+	cleanup := setupVisits(t)
+	defer cleanup()
+
+	property := newVisitProperty()
+	slot := newSlot(property, 24*time.Hour, "")
+	tenant := newTestSession("tenant1", entities.RoleTenant)
+
+	mockVisitRepo.EXPECT().FindSlotByID(gomock.Any(), slot.ID).Return(slot, nil)
+	mockPropertyRepo.EXPECT().FindByID(gomock.Any(), property.ID).Return(property, nil)
+	mockVisitRepo.EXPECT().FindSlotsByTenant(gomock.Any(), "tenant1").Return(nil, nil)
+	mockVisitRepo.EXPECT().BookSlot(gomock.Any(), slot.ID, "tenant1", gomock.Any()).Return(true, nil)
+	booked := *newSlot(property, 24*time.Hour, "tenant1")
+	booked.ID = slot.ID
+	mockVisitRepo.EXPECT().FindSlotsByTenant(gomock.Any(), "tenant1").Return([]entities.VisitSlot{booked}, nil)
+	mockVisitRepo.EXPECT().FindSlotByID(gomock.Any(), slot.ID).Return(&booked, nil)
+	result, err := visitService.BookVisit(context.Background(), tenant, slot.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.VisitBooked, result.Status)
+	assert.Equal(t, "tenant1", result.TenantName)
+
+	// Another booking of the tenant at the same time got in between the checks and the booking
+	clashing := newSlot(newVisitProperty(), 24*time.Hour+10*time.Minute, "tenant1")
+	mockVisitRepo.EXPECT().FindSlotByID(gomock.Any(), slot.ID).Return(slot, nil)
+	mockPropertyRepo.EXPECT().FindByID(gomock.Any(), property.ID).Return(property, nil)
+	mockVisitRepo.EXPECT().FindSlotsByTenant(gomock.Any(), "tenant1").Return(nil, nil)
+	mockVisitRepo.EXPECT().BookSlot(gomock.Any(), slot.ID, "tenant1", gomock.Any()).Return(true, nil)
+	mockVisitRepo.EXPECT().FindSlotsByTenant(gomock.Any(), "tenant1").Return([]entities.VisitSlot{booked, *clashing}, nil)
+	mockVisitRepo.EXPECT().FreeSlot(gomock.Any(), slot.ID, "tenant1", gomock.Any()).Return(true, nil)
+	_, err = visitService.BookVisit(context.Background(), tenant, slot.ID)
+	assert.ErrorIs(t, err, services.ErrVisitClash)
+
+	// Another tenant got the slot between the checks and the booking
+	mockVisitRepo.EXPECT().FindSlotByID(gomock.Any(), slot.ID).Return(slot, nil)
+	mockPropertyRepo.EXPECT().FindByID(gomock.Any(), property.ID).Return(property, nil)
+	mockVisitRepo.EXPECT().FindSlotsByTenant(gomock.Any(), "tenant1").Return(nil, nil)
+	mockVisitRepo.EXPECT().BookSlot(gomock.Any(), slot.ID, "tenant1", gomock.Any()).Return(false, nil)
+	_, err = visitService.BookVisit(context.Background(), tenant, slot.ID)
+	assert.ErrorIs(t, err, services.ErrSlotTaken)
+	assert.ErrorIs(t, err, services.ErrVisitNotAllowed)
+
+	unapproved := newVisitProperty()
+	unapproved.IsApprovedByAdmin = false
+	elsewhere := newSlot(newVisitProperty(), 24*time.Hour+10*time.Minute, "tenant1")
+	sameProperty := newSlot(property, 48*time.Hour, "tenant1")
+	tests := []struct {
+		name     string
+		session  *entities.Session
+		slot     *entities.VisitSlot
+		property *entities.Property
+		visits   []entities.VisitSlot
+		expected error
+	}{
+		{name: "Landlord role", session: newTestSession("landlord2", entities.RoleLandlord), expected: services.ErrForbidden},
+		{name: "Missing slot", session: tenant, expected: services.ErrSlotNotFound},
+		{name: "Own property", session: newTestSession("landlord1", entities.RoleUser), slot: slot, property: property, expected: services.ErrOwnProperty},
+		{name: "Not approved", session: tenant, slot: newSlot(unapproved, 24*time.Hour, ""), property: unapproved, expected: services.ErrPropertyNotApproved},
+		{name: "Taken", session: tenant, slot: newSlot(property, 24*time.Hour, "tenant2"), property: property, expected: services.ErrSlotTaken},
+		{name: "Started", session: tenant, slot: newSlot(property, -time.Minute, ""), property: property, expected: services.ErrSlotPassed},
+		{name: "Already visiting the property", session: tenant, slot: slot, property: property, visits: []entities.VisitSlot{*sameProperty}, expected: services.ErrAlreadyVisiting},
+		{name: "Visit at the same time", session: tenant, slot: slot, property: property, visits: []entities.VisitSlot{*elsewhere}, expected: services.ErrVisitClash},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if errors.Is(tt.expected, services.ErrSlotNotFound) {
+				mockVisitRepo.EXPECT().FindSlotByID(gomock.Any(), gomock.Any()).Return(nil, nil)
+			}
+			id := primitive.NewObjectID()
+			if tt.slot != nil {
+				id = tt.slot.ID
+				mockVisitRepo.EXPECT().FindSlotByID(gomock.Any(), id).Return(tt.slot, nil)
+				mockPropertyRepo.EXPECT().FindByID(gomock.Any(), tt.property.ID).Return(tt.property, nil)
+				if tt.visits != nil {
+					mockVisitRepo.EXPECT().FindSlotsByTenant(gomock.Any(), "tenant1").Return(tt.visits, nil)
+				}
+			}
+			_, err := visitService.BookVisit(context.Background(), tt.session, id)
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
+
+func TestVisitService_RescheduleVisit(t *testing.T) {
+	cleanup := setupVisits(t)
+	defer cleanup()
+
+	property := newVisitProperty()
+	from := newSlot(property, 24*time.Hour, "tenant1")
+	to := newSlot(property, 48*time.Hour, "")
+	moved := *to
+	moved.Status = entities.VisitBooked
+	moved.TenantName = "tenant1"
+
+	// The tenant frees the slot they leave
+	mockVisitRepo.EXPECT().FindSlotByID(gomock.Any(), from.ID).Return(from, nil)
+	mockVisitRepo.EXPECT().FindSlotByID(gomock.Any(), to.ID).Return(to, nil)
+	mockPropertyRepo.EXPECT().FindByID(gomock.Any(), property.ID).Return(property, nil)
+	mockVisitRepo.EXPECT().FindSlotsByTenant(gomock.Any(), "tenant1").Return([]entities.VisitSlot{*from}, nil)
+	mockVisitRepo.EXPECT().BookSlot(gomock.Any(), to.ID, "tenant1", gomock.Any()).Return(true, nil)
+	mockVisitRepo.EXPECT().FindSlotsByTenant(gomock.Any(), "tenant1").Return([]entities.VisitSlot{*from, moved}, nil)
+	mockVisitRepo.EXPECT().FreeSlot(gomock.Any(), from.ID, "tenant1", gomock.Any()).Return(true, nil)
+	mockVisitRepo.EXPECT().FindSlotByID(gomock.Any(), to.ID).Return(&moved, nil)
+	result, err := visitService.RescheduleVisit(context.Background(), newTestSession("tenant1", entities.RoleTenant), from.ID, to.ID)
+	require.NoError(t, err)
+	assert.Equal(t, to.ID, result.ID)
+	assert.Equal(t, entities.VisitBooked, result.Status)
+
+	// The landlord cancels the slot they leave, and gives the new slot back when the visit was called off meanwhile
+	calledOff := *from
+	calledOff.Status = entities.VisitOpen
+	calledOff.TenantName = ""
+	mockVisitRepo.EXPECT().FindSlotByID(gomock.Any(), from.ID).Return(from, nil)
+	mockVisitRepo.EXPECT().FindSlotByID(gomock.Any(), to.ID).Return(to, nil)
+	mockPropertyRepo.EXPECT().FindByID(gomock.Any(), property.ID).Return(property, nil)
+	mockVisitRepo.EXPECT().FindSlotsByTenant(gomock.Any(), "tenant1").Return([]entities.VisitSlot{*from}, nil)
+	mockVisitRepo.EXPECT().BookSlot(gomock.Any(), to.ID, "tenant1", gomock.Any()).Return(true, nil)
+	mockVisitRepo.EXPECT().FindSlotsByTenant(gomock.Any(), "tenant1").Return([]entities.VisitSlot{*from, moved}, nil)
+	mockVisitRepo.EXPECT().CancelSlot(gomock.Any(), from.ID, entities.VisitBooked, "landlord1", gomock.Any()).Return(false, nil)
+	mockVisitRepo.EXPECT().FindSlotByID(gomock.Any(), from.ID).Return(&calledOff, nil)
+	mockVisitRepo.EXPECT().FreeSlot(gomock.Any(), to.ID, "tenant1", gomock.Any()).Return(true, nil)
+	_, err = visitService.RescheduleVisit(context.Background(), newTestSession("landlord1", entities.RoleLandlord), from.ID, to.ID)
+	var stateErr *services.VisitStateError
+	require.ErrorAs(t, err, &stateErr)
+	assert.Equal(t, entities.VisitOpen, stateErr.Status)
+	assert.ErrorIs(t, err, services.ErrInvalidTransition)
+
+	otherProperty := newSlot(newVisitProperty(), 48*time.Hour, "")
+	notBooked := newSlot(property, 24*time.Hour, "")
+	tests := []struct {
+		name     string
+		session  *entities.Session
+		from     *entities.VisitSlot
+		to       *entities.VisitSlot
+		expected error
+	}{
+		{name: "Other property", session: newTestSession("tenant1", entities.RoleTenant), from: from, to: otherProperty, expected: services.ErrInvalidVisit},
+		{name: "Not booked", session: newTestSession("tenant1", entities.RoleTenant), from: notBooked, to: to, expected: services.ErrInvalidTransition},
+		{name: "Stranger", session: newTestSession("tenant2", entities.RoleTenant), from: from, to: to, expected: services.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockVisitRepo.EXPECT().FindSlotByID(gomock.Any(), tt.from.ID).Return(tt.from, nil)
+			mockVisitRepo.EXPECT().FindSlotByID(gomock.Any(), tt.to.ID).Return(tt.to, nil)
+			_, err := visitService.RescheduleVisit(context.Background(), tt.session, tt.from.ID, tt.to.ID)
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
+
+func TestVisitService_CancelVisit(t *testing.T) {
+	cleanup := setupVisits(t)
+	defer cleanup()
+
+	property := newVisitProperty()
+	booked := newSlot(property, 24*time.Hour, "tenant1")
+	freed := *booked
+	freed.Status = entities.VisitOpen
+	freed.TenantName = ""
+	cancelled := *booked
+	cancelled.Status = entities.VisitCancelled
+	cancelled.CancelledBy = "landlord1"
+
+	// The tenant's visit is called off and the slot is open again
+	mockVisitRepo.EXPECT().FindSlotByID(gomock.Any(), booked.ID).Return(booked, nil)
+	mockVisitRepo.EXPECT().FreeSlot(gomock.Any(), booked.ID, "tenant1", gomock.Any()).Return(true, nil)
+	mockVisitRepo.EXPECT().FindSlotByID(gomock.Any(), booked.ID).Return(&freed, nil)
+	result, err := visitService.CancelVisit(context.Background(), newTestSession("tenant1", entities.RoleTenant), booked.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.VisitOpen, result.Status)
+
+	// The landlord withdraws the slot
+	mockVisitRepo.EXPECT().FindSlotByID(gomock.Any(), booked.ID).Return(booked, nil)
+	mockVisitRepo.EXPECT().CancelSlot(gomock.Any(), booked.ID, entities.VisitBooked, "landlord1", gomock.Any()).Return(true, nil)
+	mockVisitRepo.EXPECT().FindSlotByID(gomock.Any(), booked.ID).Return(&cancelled, nil)
+	result, err = visitService.CancelVisit(context.Background(), newTestSession("landlord1", entities.RoleLandlord), booked.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.VisitCancelled, result.Status)
+	assert.Equal(t, "tenant1", result.TenantName)
+
+	tests := []struct {
+		name     string
+		session  *entities.Session
+		slot     *entities.VisitSlot
+		expected error
+	}{
+		{name: "Already cancelled", session: newTestSession("landlord1", entities.RoleLandlord), slot: &cancelled, expected: services.ErrInvalidTransition},
+		{name: "Stranger", session: newTestSession("tenant2", entities.RoleTenant), slot: booked, expected: services.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockVisitRepo.EXPECT().FindSlotByID(gomock.Any(), tt.slot.ID).Return(tt.slot, nil)
+			_, err := visitService.CancelVisit(context.Background(), tt.session, tt.slot.ID)
+			assert.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
+
+func TestVisitService_UpcomingVisits(t *testing.T) {
+	cleanup := setupVisits(t)
+	defer cleanup()
+
+	property := newVisitProperty()
+	upcoming := newSlot(property, 24*time.Hour, "tenant1")
+	past := newSlot(property, -2*time.Hour, "tenant1")
+	open := newSlot(property, 2*time.Hour, "")
+	cancelled := newSlot(property, 3*time.Hour, "tenant1")
+	cancelled.Status = entities.VisitCancelled
+
+	mockVisitRepo.EXPECT().FindSlotsByTenant(gomock.Any(), "tenant1").Return([]entities.VisitSlot{*past, *cancelled, *upcoming}, nil)
+	visits, err := visitService.UpcomingVisitsForTenant(context.Background(), newTestSession("tenant1", entities.RoleTenant))
+	require.NoError(t, err)
+	require.Len(t, visits, 1)
+	assert.Equal(t, upcoming.ID, visits[0].ID)
+
+	mockVisitRepo.EXPECT().FindSlotsByLandlord(gomock.Any(), "landlord1").Return([]entities.VisitSlot{*past, *open, *upcoming}, nil)
+	visits, err = visitService.UpcomingVisitsForLandlord(context.Background(), newTestSession("landlord1", entities.RoleLandlord))
+	require.NoError(t, err)
+	require.Len(t, visits, 1)
+	assert.Equal(t, upcoming.ID, visits[0].ID)
+
+	_, err = visitService.UpcomingVisitsForLandlord(context.Background(), newTestSession("tenant1", entities.RoleTenant))
+	assert.ErrorIs(t, err, services.ErrForbidden)
+}